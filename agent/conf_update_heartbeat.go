@@ -0,0 +1,16 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"context"
+
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+)
+
+func (s *Server) ConfUpdateHeartbeat(ctx context.Context, req *idl.ConfUpdateHeartbeatRequest) (*idl.ConfUpdateHeartbeatReply, error) {
+	progress, active := hub.ConfUpdateProgress()
+	return &idl.ConfUpdateHeartbeatReply{Progress: progress, ActiveUpdates: active}, nil
+}
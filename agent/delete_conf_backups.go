@@ -0,0 +1,30 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+)
+
+func (s *Server) DeleteConfBackups(ctx context.Context, req *idl.DeleteConfBackupsRequest) (*idl.DeleteConfBackupsReply, error) {
+	log.Printf("deleting the backups of %d conf files", len(req.GetPaths()))
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		return &idl.DeleteConfBackupsReply{}, err
+	}
+
+	deleted, err := hub.RemoveConfBackups(req.GetPaths())
+	if err != nil {
+		return &idl.DeleteConfBackupsReply{}, fmt.Errorf("on host %q: %w", hostname, err)
+	}
+
+	return &idl.DeleteConfBackupsReply{DeletedPaths: deleted}, nil
+}
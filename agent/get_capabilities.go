@@ -0,0 +1,15 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"context"
+
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+)
+
+func (s *Server) GetCapabilities(ctx context.Context, req *idl.GetCapabilitiesRequest) (*idl.GetCapabilitiesReply, error) {
+	return &idl.GetCapabilitiesReply{Features: hub.AgentConfFeatures}, nil
+}
@@ -0,0 +1,30 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+)
+
+func (s *Server) InventorySegments(ctx context.Context, req *idl.InventorySegmentsRequest) (*idl.InventorySegmentsReply, error) {
+	log.Printf("inventorying segments in %d directories", len(req.GetParentDirs()))
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		return &idl.InventorySegmentsReply{}, err
+	}
+
+	dataDirs, err := hub.InventorySegments(req.GetParentDirs(), req.GetRecoveryConfFile())
+	if err != nil {
+		return &idl.InventorySegmentsReply{}, fmt.Errorf("on host %q: %w", hostname, err)
+	}
+
+	return &idl.InventorySegmentsReply{DataDirectories: dataDirs}, nil
+}
@@ -0,0 +1,30 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+)
+
+func (s *Server) ListConfBackups(ctx context.Context, req *idl.ListConfBackupsRequest) (*idl.ListConfBackupsReply, error) {
+	log.Printf("listing conf backups in %d directories", len(req.GetDirs()))
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		return &idl.ListConfBackupsReply{}, err
+	}
+
+	backups, err := hub.ListConfBackups(req.GetDirs())
+	if err != nil {
+		return &idl.ListConfBackupsReply{}, fmt.Errorf("on host %q: %w", hostname, err)
+	}
+
+	return &idl.ListConfBackupsReply{Backups: backups}, nil
+}
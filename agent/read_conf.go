@@ -0,0 +1,24 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"context"
+	"log"
+	"os"
+
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+)
+
+func (s *Server) ReadConfiguration(ctx context.Context, req *idl.ReadConfigurationRequest) (*idl.ReadConfigurationReply, error) {
+	log.Printf("reading configuration of %d files", len(req.GetFiles()))
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		return &idl.ReadConfigurationReply{}, err
+	}
+
+	return hub.HandleReadConfiguration(hostname, req)
+}
@@ -0,0 +1,27 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+)
+
+func (s *Server) ReadConfJournal(ctx context.Context, req *idl.ReadConfJournalRequest) (*idl.ReadConfJournalReply, error) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return &idl.ReadConfJournalReply{}, err
+	}
+
+	records, err := hub.ReadConfJournalLines()
+	if err != nil {
+		return &idl.ReadConfJournalReply{}, fmt.Errorf("on host %q: %w", hostname, err)
+	}
+
+	return &idl.ReadConfJournalReply{Records: records}, nil
+}
@@ -0,0 +1,30 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+)
+
+func (s *Server) RestoreConfBackups(ctx context.Context, req *idl.RestoreConfBackupsRequest) (*idl.RestoreConfBackupsReply, error) {
+	log.Printf("restoring %d conf files from their backups", len(req.GetPaths()))
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		return &idl.RestoreConfBackupsReply{}, err
+	}
+
+	restored, err := hub.RestoreConfBackups(req.GetPaths(), req.GetTempDir())
+	if err != nil {
+		return &idl.RestoreConfBackupsReply{}, fmt.Errorf("on host %q: %w", hostname, err)
+	}
+
+	return &idl.RestoreConfBackupsReply{RestoredPaths: restored}, nil
+}
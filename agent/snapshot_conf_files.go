@@ -0,0 +1,30 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+)
+
+func (s *Server) SnapshotConfFiles(ctx context.Context, req *idl.SnapshotConfFilesRequest) (*idl.SnapshotConfFilesReply, error) {
+	log.Printf("snapshotting %d conf files", len(req.GetPaths()))
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		return &idl.SnapshotConfFilesReply{}, err
+	}
+
+	files, err := hub.SnapshotConfFiles(req.GetPaths())
+	if err != nil {
+		return &idl.SnapshotConfFilesReply{}, fmt.Errorf("on host %q: %w", hostname, err)
+	}
+
+	return &idl.SnapshotConfFilesReply{Files: files}, nil
+}
@@ -5,7 +5,6 @@ package agent
 
 import (
 	"context"
-	"fmt"
 	"log"
 	"os"
 
@@ -21,10 +20,5 @@ func (s *Server) UpdateConfiguration(ctx context.Context, req *idl.UpdateConfigu
 		return &idl.UpdateConfigurationReply{}, err
 	}
 
-	err = hub.UpdateConfigurationFile(req.GetOptions())
-	if err != nil {
-		return &idl.UpdateConfigurationReply{}, fmt.Errorf("on host %q: %w", hostname, err)
-	}
-
-	return &idl.UpdateConfigurationReply{}, nil
+	return hub.HandleUpdateConfiguration(ctx, hostname, req)
 }
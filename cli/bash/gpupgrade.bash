@@ -591,6 +591,18 @@ _gpupgrade_finalize()
     flags+=("-?")
     local_nonpersistent_flags+=("--?")
     local_nonpersistent_flags+=("-?")
+    flags+=("--conf-dry-run")
+    local_nonpersistent_flags+=("--conf-dry-run")
+    flags+=("--conf-hosts=")
+    two_word_flags+=("--conf-hosts")
+    local_nonpersistent_flags+=("--conf-hosts")
+    local_nonpersistent_flags+=("--conf-hosts=")
+    flags+=("--conf-resume-token=")
+    two_word_flags+=("--conf-resume-token")
+    local_nonpersistent_flags+=("--conf-resume-token")
+    local_nonpersistent_flags+=("--conf-resume-token=")
+    flags+=("--reconcile-late-mirrors")
+    local_nonpersistent_flags+=("--reconcile-late-mirrors")
     flags+=("--verbose")
     flags+=("-v")
     local_nonpersistent_flags+=("--verbose")
@@ -739,6 +751,10 @@ _gpupgrade_initialize()
     two_word_flags+=("--hub-port")
     local_nonpersistent_flags+=("--hub-port")
     local_nonpersistent_flags+=("--hub-port=")
+    flags+=("--jump-host=")
+    two_word_flags+=("--jump-host")
+    local_nonpersistent_flags+=("--jump-host")
+    local_nonpersistent_flags+=("--jump-host=")
     flags+=("--mode=")
     two_word_flags+=("--mode")
     local_nonpersistent_flags+=("--mode")
@@ -870,6 +886,80 @@ _gpupgrade_revert()
     noun_aliases=()
 }
 
+_gpupgrade_selftest_agents()
+{
+    last_command="gpupgrade_selftest_agents"
+
+    command_aliases=()
+
+    commands=()
+
+    flags=()
+    two_word_flags=()
+    local_nonpersistent_flags=()
+    flags_with_completion=()
+    flags_completion=()
+
+
+    must_have_one_flag=()
+    must_have_one_noun=()
+    noun_aliases=()
+}
+
+_gpupgrade_selftest_conf()
+{
+    last_command="gpupgrade_selftest_conf"
+
+    command_aliases=()
+
+    commands=()
+
+    flags=()
+    two_word_flags=()
+    local_nonpersistent_flags=()
+    flags_with_completion=()
+    flags_completion=()
+
+    flags+=("--output-dir=")
+    two_word_flags+=("--output-dir")
+    local_nonpersistent_flags+=("--output-dir")
+    local_nonpersistent_flags+=("--output-dir=")
+    flags+=("--replay=")
+    two_word_flags+=("--replay")
+    local_nonpersistent_flags+=("--replay")
+    local_nonpersistent_flags+=("--replay=")
+    flags+=("--simulate=")
+    two_word_flags+=("--simulate")
+    local_nonpersistent_flags+=("--simulate")
+    local_nonpersistent_flags+=("--simulate=")
+
+    must_have_one_flag=()
+    must_have_one_noun=()
+    noun_aliases=()
+}
+
+_gpupgrade_selftest()
+{
+    last_command="gpupgrade_selftest"
+
+    command_aliases=()
+
+    commands=()
+    commands+=("agents")
+    commands+=("conf")
+
+    flags=()
+    two_word_flags=()
+    local_nonpersistent_flags=()
+    flags_with_completion=()
+    flags_completion=()
+
+
+    must_have_one_flag=()
+    must_have_one_noun=()
+    noun_aliases=()
+}
+
 _gpupgrade_version()
 {
     last_command="gpupgrade_version"
@@ -911,6 +1001,7 @@ _gpupgrade_root_command()
     commands+=("kill-services")
     commands+=("restart-services")
     commands+=("revert")
+    commands+=("selftest")
     commands+=("version")
 
     flags=()
@@ -83,8 +83,8 @@ func Execute(client idl.CliToHubClient, request *idl.ExecuteRequest, verbose boo
 	return executeResponse, nil
 }
 
-func Finalize(client idl.CliToHubClient, verbose bool) (*idl.FinalizeResponse, error) {
-	stream, err := client.Finalize(context.Background(), &idl.FinalizeRequest{})
+func Finalize(client idl.CliToHubClient, request *idl.FinalizeRequest, verbose bool) (*idl.FinalizeResponse, error) {
+	stream, err := client.Finalize(context.Background(), request)
 	if err != nil {
 		return &idl.FinalizeResponse{}, err
 	}
@@ -167,6 +167,18 @@ func UILoop(stream receiver, verbose bool) (*idl.Response, error) {
 				fmt.Println()
 			}
 
+		case *idl.Message_Event:
+			log.Print(FormatEvent(x.Event))
+			if verbose {
+				fmt.Println(FormatEvent(x.Event))
+				continue
+			}
+
+			// Show the progress of the current step in its status line.
+			if x.Event.Type == idl.SubstepEvent_host_completed && x.Event.Substep == lastStep {
+				fmt.Print("\r" + FormatProgress(x.Event))
+			}
+
 		case *idl.Message_Response:
 			response = x.Response
 
@@ -212,6 +224,37 @@ func FormatStatus(status *idl.SubstepStatus) string {
 	return Format(line.OutputText, status.Status)
 }
 
+// FormatEvent describes a substep event for the log and verbose output.
+func FormatEvent(event *idl.SubstepEvent) string {
+	outcome := "completed"
+	if event.Failed {
+		outcome = "failed"
+	}
+
+	switch event.Type {
+	case idl.SubstepEvent_started:
+		return fmt.Sprintf("%s started on %d hosts", event.Substep, event.TotalHosts)
+	case idl.SubstepEvent_host_completed:
+		return fmt.Sprintf("%s %s %s on %s (%d/%d hosts)", event.Substep, event.Phase, outcome, event.Hostname, event.CompletedHosts, event.TotalHosts)
+	case idl.SubstepEvent_finished:
+		return fmt.Sprintf("%s %s", event.Substep, outcome)
+	}
+
+	return fmt.Sprintf("%s %s", event.Substep, event.Type)
+}
+
+// FormatProgress is the running status line of a substep including the hosts
+// completed in its current phase.
+func FormatProgress(event *idl.SubstepEvent) string {
+	line, ok := substeps.SubstepDescriptions[event.Substep]
+	if !ok {
+		panic(fmt.Sprintf("unexpected step %#v", event.Substep))
+	}
+
+	description := fmt.Sprintf("%s (%d/%d hosts)", line.OutputText, event.CompletedHosts, event.TotalHosts)
+	return Format(description, idl.Status_running)
+}
+
 // Format is also exported for ease of testing (see FormatStatus). Use NewSubstep
 // instead.
 func Format(description string, status idl.Status) string {
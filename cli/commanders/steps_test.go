@@ -227,6 +227,55 @@ func TestUILoop(t *testing.T) {
 		}
 	})
 
+	t.Run("shows substep progress in the status line in non-verbose mode", func(t *testing.T) {
+		msgs := msgStream{
+			{Contents: &idl.Message_Status{Status: &idl.SubstepStatus{
+				Step:   idl.Substep_update_target_conf_files,
+				Status: idl.Status_running,
+			}}},
+			{Contents: &idl.Message_Event{Event: &idl.SubstepEvent{
+				Substep:    idl.Substep_update_target_conf_files,
+				Type:       idl.SubstepEvent_started,
+				TotalHosts: 2,
+			}}},
+			{Contents: &idl.Message_Event{Event: &idl.SubstepEvent{
+				Substep:        idl.Substep_update_target_conf_files,
+				Type:           idl.SubstepEvent_host_completed,
+				Phase:          "segment-postgresql-conf",
+				Hostname:       "sdw1",
+				CompletedHosts: 1,
+				TotalHosts:     2,
+			}}},
+			{Contents: &idl.Message_Status{Status: &idl.SubstepStatus{
+				Step:   idl.Substep_update_target_conf_files,
+				Status: idl.Status_complete,
+			}}},
+		}
+
+		expected := commanders.FormatStatus(msgs[0].GetStatus()) + "\r"
+		expected += commanders.FormatProgress(msgs[2].GetEvent()) + "\r"
+		expected += commanders.FormatStatus(msgs[3].GetStatus()) + "\n"
+
+		d := BufferStandardDescriptors(t)
+		defer d.Close()
+
+		_, err := commanders.UILoop(&msgs, false)
+		if err != nil {
+			t.Errorf("UILoop() returned %#v", err)
+		}
+
+		actualOut, actualErr := d.Collect()
+
+		if len(actualErr) != 0 {
+			t.Errorf("unexpected stderr %#v", string(actualErr))
+		}
+
+		actual := string(actualOut)
+		if actual != expected {
+			t.Errorf("output %#v want %#v", actual, expected)
+		}
+	})
+
 	t.Run("processes responses successfully", func(t *testing.T) {
 		source := MustCreateCluster(t, greenplum.SegConfigs{
 			{ContentID: -1, DbID: 1, Hostname: "mdw", DataDir: "/data/qddir/seg-1", Role: greenplum.PrimaryRole, Port: 15432},
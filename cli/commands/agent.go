@@ -4,9 +4,12 @@
 package commands
 
 import (
+	"path/filepath"
+
 	"github.com/spf13/cobra"
 
 	"github.com/greenplum-db/gpupgrade/agent"
+	"github.com/greenplum-db/gpupgrade/hub"
 	"github.com/greenplum-db/gpupgrade/upgrade"
 	"github.com/greenplum-db/gpupgrade/utils"
 	"github.com/greenplum-db/gpupgrade/utils/daemon"
@@ -28,6 +31,7 @@ func Agent() *cobra.Command {
 			logger.Initialize("agent")
 			defer logger.WritePanics()
 
+			hub.SetConfJournalPath(filepath.Join(stateDir, hub.ConfJournalFileName))
 			agentServer := agent.New()
 
 			// blocking call
@@ -82,6 +82,7 @@ func BuildRootCommand() *cobra.Command {
 	root.AddCommand(execute())
 	root.AddCommand(finalize())
 	root.AddCommand(revert())
+	root.AddCommand(selfTest())
 	root.AddCommand(restartServices)
 	root.AddCommand(killServices)
 	root.AddCommand(Agent())
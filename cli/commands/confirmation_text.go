@@ -24,6 +24,7 @@ dynamic_library_path: %s
 temp_port_range:      %s
 hub_port:             %d
 agent_port:           %d
+jump_host:            %s
 
 You will still have the opportunity to revert the cluster to its original state 
 after this step.
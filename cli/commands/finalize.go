@@ -24,6 +24,11 @@ import (
 func finalize() *cobra.Command {
 	var verbose bool
 	var nonInteractive bool
+	var confResumeToken string
+	var confHosts []string
+	var confDryRun bool
+	var confDumpRequests bool
+	var reconcileLateMirrors bool
 
 	cmd := &cobra.Command{
 		Use:   "finalize",
@@ -58,7 +63,8 @@ func finalize() *cobra.Command {
 					return err
 				}
 
-				response, err = commanders.Finalize(client, verbose)
+				request := &idl.FinalizeRequest{ConfResumeToken: confResumeToken, Verbose: verbose, ConfHosts: confHosts, ConfDryRun: confDryRun, ConfDumpRequests: confDumpRequests, ReconcileLateMirrors: reconcileLateMirrors}
+				response, err = commanders.Finalize(client, request, verbose)
 				if err != nil {
 					return err
 				}
@@ -134,5 +140,11 @@ If you postpone creating statistics then after the upgrade run "vacuumdb --all -
 	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "print the output stream from all substeps")
 	cmd.Flags().BoolVar(&nonInteractive, "non-interactive", false, "do not prompt for confirmation to proceed")
 	cmd.Flags().MarkHidden("non-interactive") //nolint
+	cmd.Flags().StringVar(&confResumeToken, "conf-resume-token", "", "resume updating the target conf files from the token printed by an interrupted finalize")
+	cmd.Flags().StringSliceVar(&confHosts, "conf-hosts", nil, "only update the target conf files of these hosts, such as to retry the hosts that failed")
+	cmd.Flags().BoolVar(&confDryRun, "conf-dry-run", false, "print the changes to the target conf files without making them, stopping finalize before the cluster is started. The substeps before them, which upgrade the mirrors and standby and update the catalog and data directories, still run and cannot be undone")
+	cmd.Flags().BoolVar(&confDumpRequests, "conf-dump-requests", false, "write the requests updating the target conf files to the state directory without sending them, stopping finalize before the cluster is started")
+	cmd.Flags().MarkHidden("conf-dump-requests") //nolint
+	cmd.Flags().BoolVar(&reconcileLateMirrors, "reconcile-late-mirrors", false, "also update the target conf files of the mirrors added after initialize, found next to the recorded segments")
 	return addHelpToCommand(cmd, FinalizeHelp)
 }
@@ -79,6 +79,7 @@ func init() {
 		idl.Substep_wait_for_cluster_to_be_ready_after_updating_catalog,
 		idl.Substep_archive_log_directories,
 		idl.Substep_delete_backupdir,
+		idl.Substep_delete_conf_backups,
 		idl.Substep_delete_segment_statedirs,
 		idl.Substep_stop_hub_and_agents,
 		idl.Substep_execute_finalize_data_migration_scripts,
@@ -90,6 +91,7 @@ func init() {
 		idl.Substep_ensure_gpupgrade_agents_are_running,
 		idl.Substep_check_active_connections_on_target_cluster,
 		idl.Substep_shutdown_target_cluster,
+		idl.Substep_restore_conf_files,
 		idl.Substep_delete_target_cluster_datadirs,
 		idl.Substep_delete_tablespaces,
 		idl.Substep_restore_pgcontrol,
@@ -177,8 +179,22 @@ Usage: gpupgrade finalize
 
 Optional Flags:
 
-  -h, --help      displays help output for finalize
-  -v, --verbose   outputs detailed logs for finalize
+  -h, --help                displays help output for finalize
+  -v, --verbose             outputs detailed logs for finalize
+      --conf-resume-token   resumes updating the target conf files from the 
+                            token printed by an interrupted finalize
+      --conf-hosts          only updates the target conf files of these 
+                            hosts, such as to retry the hosts that failed
+      --conf-dry-run        prints the changes to the target conf files 
+                            without making them, stopping finalize before 
+                            the cluster is started. The substeps before 
+                            them, which upgrade the mirrors and standby 
+                            and update the catalog and data directories, 
+                            still run and cannot be undone
+      --reconcile-late-mirrors
+                            also updates the target conf files of the mirrors 
+                            added after initialize, found next to the 
+                            recorded segments
 
 NOTE: After running finalize, you must execute data migration scripts. 
 Refer to documentation for instructions.
@@ -5,8 +5,12 @@ package commands
 
 import (
 	"fmt"
+	"net/url"
+	"path/filepath"
+	"time"
 
 	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
 
 	"github.com/greenplum-db/gpupgrade/config"
 	"github.com/greenplum-db/gpupgrade/hub"
@@ -52,6 +56,10 @@ func Hub() *cobra.Command {
 				conf.HubPort = hubPort
 			}
 
+			if err := configureHub(conf); err != nil {
+				return err
+			}
+
 			hubServer := hub.New(conf)
 			return hubServer.Start(conf.HubPort, shouldDaemonize)
 		},
@@ -63,3 +71,173 @@ func Hub() *cobra.Command {
 
 	return cmd
 }
+
+// configureHub sets the options of the hub stored in the configuration.
+func configureHub(conf *config.Config) error {
+	hub.SetConfJournalPath(filepath.Join(utils.GetStateDir(), hub.ConfJournalFileName))
+	if conf.ConfEditConcurrency > 0 {
+		hub.SetConfEditConcurrency(conf.ConfEditConcurrency)
+	}
+	hub.SetGpperfmonLogLocation(conf.GpperfmonLogLocation)
+
+	if conf.JumpHost != "" {
+		hub.SetAgentContextDialer(hub.JumpHostDialer(conf.JumpHost))
+	}
+
+	hub.SetConfRPCConcurrency(conf.ConfRPCConcurrency)
+	if conf.ConfHostTimeout != "" {
+		timeout, err := time.ParseDuration(conf.ConfHostTimeout)
+		if err != nil {
+			return xerrors.Errorf("invalid conf host timeout: %w", err)
+		}
+
+		if timeout < 0 {
+			return xerrors.Errorf("invalid conf host timeout %s: it must not be negative", timeout)
+		}
+		hub.SetConfHostTimeout(timeout)
+	}
+
+	if conf.ConfFailureThreshold != "" {
+		threshold, err := hub.ParseFailureThreshold(conf.ConfFailureThreshold)
+		if err != nil {
+			return err
+		}
+		hub.SetConfFailureThreshold(threshold)
+	}
+
+	if conf.CustomConfEditsFile != "" {
+		edits, err := hub.LoadCustomConfEdits(conf.CustomConfEditsFile)
+		if err != nil {
+			return err
+		}
+		hub.SetCustomConfEdits(edits)
+	}
+
+	hub.SetConfExclusions(hub.ConfExclusions{Contents: conf.ConfExcludeContents, Hosts: conf.ConfExcludeHosts})
+
+	if conf.TargetPortMapFile != "" {
+		ports, err := hub.LoadTargetPortMap(conf.TargetPortMapFile)
+		if err != nil {
+			return err
+		}
+		hub.SetTargetPortMap(ports)
+	}
+
+	if conf.ConfOrder != "" {
+		order := hub.ConfOrder(conf.ConfOrder)
+		if err := order.Validate(); err != nil {
+			return err
+		}
+		hub.SetConfOrder(order)
+	}
+
+	if conf.ConfUnreachablePolicy != "" {
+		policy, err := hub.ParseUnreachablePolicy(conf.ConfUnreachablePolicy)
+		if err != nil {
+			return err
+		}
+		hub.SetConfUnreachablePolicy(policy)
+	}
+
+	if conf.ConfRetryAttempts != 0 || conf.ConfRetryBackoff != "" || conf.ConfRetryMaxBackoff != "" {
+		policy := hub.DefaultRetryPolicy()
+		if conf.ConfRetryAttempts != 0 {
+			if conf.ConfRetryAttempts < 1 {
+				return xerrors.Errorf("invalid conf retry attempts %d: it must be at least 1", conf.ConfRetryAttempts)
+			}
+			policy.Attempts = conf.ConfRetryAttempts
+		}
+
+		if conf.ConfRetryBackoff != "" {
+			backoff, err := time.ParseDuration(conf.ConfRetryBackoff)
+			if err != nil {
+				return xerrors.Errorf("invalid conf retry backoff: %w", err)
+			}
+			policy.InitialBackoff = backoff
+		}
+
+		if conf.ConfRetryMaxBackoff != "" {
+			backoff, err := time.ParseDuration(conf.ConfRetryMaxBackoff)
+			if err != nil {
+				return xerrors.Errorf("invalid conf retry max backoff: %w", err)
+			}
+			policy.MaxBackoff = backoff
+		}
+
+		if policy.InitialBackoff <= 0 || policy.MaxBackoff < policy.InitialBackoff {
+			return xerrors.Errorf("invalid conf retry backoff %s: it must be positive and at most the max backoff %s", policy.InitialBackoff, policy.MaxBackoff)
+		}
+		hub.SetRPCRetryPolicy(policy)
+	}
+
+	if conf.ConfHeartbeatTimeout != "" {
+		timeout, err := time.ParseDuration(conf.ConfHeartbeatTimeout)
+		if err != nil {
+			return xerrors.Errorf("invalid conf heartbeat timeout: %w", err)
+		}
+
+		if timeout < hub.MinConfHeartbeatTimeout {
+			return xerrors.Errorf("invalid conf heartbeat timeout %s: it must be at least %s", timeout, hub.MinConfHeartbeatTimeout)
+		}
+		hub.SetConfHeartbeatTimeout(timeout)
+	}
+
+	hub.SetCoreConfMode(conf.CoreConfMode)
+	hub.SetEnsureTrailingNewline(!conf.PreserveTrailingNewline)
+	hub.SetConfBatchSegments(conf.ConfBatchSegments)
+	hub.SetVerifyAfterWrite(conf.ConfVerifyAfterWrite)
+	hub.SetStrictPathReferences(conf.StrictPathReferences)
+	hub.SetSnapshotConfBundle(conf.SnapshotConfBundle)
+	hub.SetRecordConfSupportBundle(conf.RecordConfSupportBundle)
+
+	if conf.ConfBackupDir != "" && !filepath.IsAbs(conf.ConfBackupDir) {
+		return xerrors.Errorf("invalid conf backup directory %q: it must be an absolute path", conf.ConfBackupDir)
+	}
+	hub.SetConfBackupDir(conf.ConfBackupDir)
+
+	if conf.ConfAuditFile != "" && conf.ConfAuditWebhook != "" {
+		return xerrors.New("invalid conf audit sink: set only one of the conf audit file and webhook")
+	}
+
+	if conf.ConfAuditWebhook != "" {
+		webhook, err := url.Parse(conf.ConfAuditWebhook)
+		if err != nil {
+			return xerrors.Errorf("invalid conf audit webhook: %w", err)
+		}
+
+		if webhook.Scheme != "http" && webhook.Scheme != "https" {
+			return xerrors.Errorf("invalid conf audit webhook %q: it must be an http or https URL", conf.ConfAuditWebhook)
+		}
+		hub.SetConfAuditSink(hub.WebhookAuditSink{URL: conf.ConfAuditWebhook})
+	} else {
+		hub.SetConfAuditSink(hub.FileAuditSink{Path: conf.ConfAuditFile})
+	}
+
+	if conf.ConfAuditFailurePolicy != "" {
+		policy := hub.AuditFailurePolicy(conf.ConfAuditFailurePolicy)
+		if err := policy.Validate(); err != nil {
+			return err
+		}
+		hub.SetAuditFailurePolicy(policy)
+	}
+
+	if conf.ConfTempDir != "" && !filepath.IsAbs(conf.ConfTempDir) {
+		return xerrors.Errorf("invalid conf temp directory %q: it must be an absolute path", conf.ConfTempDir)
+	}
+	hub.SetConfTempDir(conf.ConfTempDir)
+
+	if conf.ConfPlanReportFile != "" && !filepath.IsAbs(conf.ConfPlanReportFile) {
+		return xerrors.Errorf("invalid conf plan report file %q: it must be an absolute path", conf.ConfPlanReportFile)
+	}
+	hub.SetConfPlanReportFile(conf.ConfPlanReportFile)
+
+	if conf.ConfBackupPolicy != "" {
+		policy := hub.BackupPolicy(conf.ConfBackupPolicy)
+		if err := policy.Validate(); err != nil {
+			return err
+		}
+		hub.SetBackupPolicy(policy)
+	}
+
+	return nil
+}
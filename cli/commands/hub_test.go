@@ -0,0 +1,200 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/greenplum-db/gpupgrade/config"
+	"github.com/greenplum-db/gpupgrade/hub"
+)
+
+func TestConfigureHub(t *testing.T) {
+	defer hub.ResetVerifyAfterWrite()
+	defer hub.ResetStrictPathReferences()
+	defer hub.ResetSnapshotConfBundle()
+	defer hub.ResetRecordConfSupportBundle()
+	defer hub.ResetConfBackupDir()
+	defer hub.ResetBackupPolicy()
+	defer hub.ResetConfTempDir()
+	defer hub.ResetConfPlanReportFile()
+	defer hub.ResetConfAuditSink()
+	defer hub.ResetAuditFailurePolicy()
+	defer hub.ResetCoreConfMode()
+	defer hub.ResetEnsureTrailingNewline()
+	defer hub.ResetConfBatchSegments()
+	defer hub.ResetConfHeartbeatTimeout()
+	defer hub.ResetRPCRetryPolicy()
+	defer hub.ResetConfOrder()
+	defer hub.ResetConfUnreachablePolicy()
+	defer hub.ResetTargetPortMap()
+	defer hub.ResetConfExclusions()
+	defer hub.ResetCustomConfEdits()
+	defer hub.ResetConfFailureThreshold()
+	defer hub.ResetConfRPCConcurrency()
+	defer hub.ResetConfHostTimeout()
+	defer hub.ResetAgentContextDialer()
+
+	t.Run("accepts the defaults", func(t *testing.T) {
+		err := configureHub(&config.Config{})
+		if err != nil {
+			t.Errorf("unexpected error %+v", err)
+		}
+	})
+
+	t.Run("accepts valid options", func(t *testing.T) {
+		err := configureHub(&config.Config{
+			JumpHost:                "gpadmin@bastion",
+			ConfRPCConcurrency:      8,
+			ConfHostTimeout:         "90s",
+			ConfFailureThreshold:    "5%",
+			ConfExcludeContents:     []int{2},
+			ConfExcludeHosts:        []string{"sdw3"},
+			ConfOrder:               string(hub.ConfOrderSegmentsFirst),
+			ConfUnreachablePolicy:   "skip",
+			ConfHeartbeatTimeout:    "2m",
+			ConfRetryAttempts:       6,
+			ConfRetryBackoff:        "1s",
+			ConfRetryMaxBackoff:     "10s",
+			CoreConfMode:            true,
+			PreserveTrailingNewline: true,
+			ConfBatchSegments:       true,
+			ConfVerifyAfterWrite:    true,
+			StrictPathReferences:    true,
+			SnapshotConfBundle:      true,
+			RecordConfSupportBundle: true,
+			ConfBackupDir:           "/central/backups",
+			ConfBackupPolicy:        string(hub.BackupPolicyFail),
+			ConfTempDir:             "/data/tmp",
+			ConfPlanReportFile:      "/data/conf-plan.csv",
+			ConfAuditWebhook:        "https://audit.example.com/events",
+			ConfAuditFailurePolicy:  string(hub.AuditFailureFatal),
+		})
+		if err != nil {
+			t.Errorf("unexpected error %+v", err)
+		}
+	})
+
+	errorCases := []struct {
+		name     string
+		conf     *config.Config
+		expected string
+	}{
+		{
+			name:     "an invalid conf host timeout",
+			conf:     &config.Config{ConfHostTimeout: "ninety seconds"},
+			expected: "invalid conf host timeout",
+		},
+		{
+			name:     "a negative conf host timeout",
+			conf:     &config.Config{ConfHostTimeout: "-1m"},
+			expected: "it must not be negative",
+		},
+		{
+			name:     "too few conf retry attempts",
+			conf:     &config.Config{ConfRetryAttempts: -1},
+			expected: "invalid conf retry attempts",
+		},
+		{
+			name:     "an invalid conf retry backoff",
+			conf:     &config.Config{ConfRetryBackoff: "a second"},
+			expected: "invalid conf retry backoff",
+		},
+		{
+			name:     "a conf retry backoff longer than the max",
+			conf:     &config.Config{ConfRetryBackoff: "10s", ConfRetryMaxBackoff: "5s"},
+			expected: "it must be positive and at most the max backoff",
+		},
+		{
+			name:     "a relative conf backup directory",
+			conf:     &config.Config{ConfBackupDir: "backups"},
+			expected: "invalid conf backup directory",
+		},
+		{
+			name:     "both a conf audit file and webhook",
+			conf:     &config.Config{ConfAuditFile: "/var/log/conf-audit.log", ConfAuditWebhook: "https://audit.example.com/events"},
+			expected: "set only one of the conf audit file and webhook",
+		},
+		{
+			name:     "a conf audit webhook that is not an http URL",
+			conf:     &config.Config{ConfAuditWebhook: "audit.example.com/events"},
+			expected: "it must be an http or https URL",
+		},
+		{
+			name:     "an unknown conf audit failure policy",
+			conf:     &config.Config{ConfAuditFailurePolicy: "ignore"},
+			expected: "unknown audit failure policy",
+		},
+		{
+			name:     "a relative conf temp directory",
+			conf:     &config.Config{ConfTempDir: "tmp"},
+			expected: "invalid conf temp directory",
+		},
+		{
+			name:     "a relative conf plan report file",
+			conf:     &config.Config{ConfPlanReportFile: "conf-plan.csv"},
+			expected: "invalid conf plan report file",
+		},
+		{
+			name:     "an unknown conf backup policy",
+			conf:     &config.Config{ConfBackupPolicy: "keep"},
+			expected: "unknown backup policy",
+		},
+		{
+			name:     "an invalid conf failure threshold",
+			conf:     &config.Config{ConfFailureThreshold: "150%"},
+			expected: "invalid failure threshold",
+		},
+		{
+			name:     "a missing custom conf edits file",
+			conf:     &config.Config{CustomConfEditsFile: "/does/not/exist.json"},
+			expected: "read custom conf edits",
+		},
+		{
+			name:     "a missing target port map file",
+			conf:     &config.Config{TargetPortMapFile: "/does/not/exist.json"},
+			expected: "read target port map",
+		},
+		{
+			name:     "an unknown conf order",
+			conf:     &config.Config{ConfOrder: "standby-first"},
+			expected: "unknown conf update order",
+		},
+		{
+			name:     "an unknown conf unreachable policy",
+			conf:     &config.Config{ConfUnreachablePolicy: "ignore"},
+			expected: "unknown unreachable policy",
+		},
+		{
+			name:     "an invalid conf heartbeat timeout",
+			conf:     &config.Config{ConfHeartbeatTimeout: "2"},
+			expected: "invalid conf heartbeat timeout",
+		},
+		{
+			name:     "a negative conf heartbeat timeout",
+			conf:     &config.Config{ConfHeartbeatTimeout: "-2m"},
+			expected: "invalid conf heartbeat timeout -2m0s: it must be at least 1s",
+		},
+		{
+			name:     "a zero conf heartbeat timeout",
+			conf:     &config.Config{ConfHeartbeatTimeout: "0s"},
+			expected: "invalid conf heartbeat timeout 0s: it must be at least 1s",
+		},
+		{
+			name:     "a conf heartbeat timeout too short to poll",
+			conf:     &config.Config{ConfHeartbeatTimeout: "3ns"},
+			expected: "invalid conf heartbeat timeout 3ns: it must be at least 1s",
+		},
+	}
+
+	for _, c := range errorCases {
+		t.Run("errors on "+c.name, func(t *testing.T) {
+			err := configureHub(c.conf)
+			if err == nil || !strings.Contains(err.Error(), c.expected) {
+				t.Errorf("expected error %v to contain %q", err, c.expected)
+			}
+		})
+	}
+}
@@ -49,6 +49,7 @@ func initialize() *cobra.Command {
 	var useHbaHostnames bool
 	var dynamicLibraryPath string
 	var dataMigrationSeedDir string
+	var jumpHost string
 
 	subInit := &cobra.Command{
 		Use:   "initialize",
@@ -168,7 +169,7 @@ func initialize() *cobra.Command {
 			confirmationText := fmt.Sprintf(initializeConfirmationText,
 				cases.Title(language.English).String(idl.Step_initialize.String()),
 				initializeSubsteps, logdir, configPath,
-				sourcePort, sourceGPHome, targetGPHome, mode, diskFreeRatio, pgUpgradeJobs, useHbaHostnames, dynamicLibraryPath, ports, hubPort, agentPort)
+				sourcePort, sourceGPHome, targetGPHome, mode, diskFreeRatio, pgUpgradeJobs, useHbaHostnames, dynamicLibraryPath, ports, hubPort, agentPort, jumpHost)
 
 			st, err := clistep.Begin(idl.Step_initialize, verbose, nonInteractive, confirmationText)
 			if err != nil {
@@ -199,7 +200,7 @@ func initialize() *cobra.Command {
 					filepath.Clean(sourceGPHome),
 					filepath.Clean(targetGPHome),
 					mode, useHbaHostnames, parsedPorts, pgUpgradeJobs,
-					parentBackupDirs,
+					parentBackupDirs, jumpHost,
 				)
 				if err != nil {
 					return err
@@ -314,6 +315,7 @@ func initialize() *cobra.Command {
 		"To specify a single directory across all hosts set /dir."+
 		"To specify different directories for each host use the form \"host1:/dir1,host2:/dir2,host3:/dir3\" where the first host must be the coordinator.")
 	subInit.Flags().Float64Var(&diskFreeRatio, "disk-free-ratio", 0.60, "percentage of disk space that must be available (from 0.0 - 1.0)")
+	subInit.Flags().StringVar(&jumpHost, "jump-host", "", "SSH destination such as user@bastion:port through which to reach the segment hosts when they are not directly reachable")
 	subInit.Flags().BoolVar(&useHbaHostnames, "use-hba-hostnames", false, "use hostnames in pg_hba.conf")
 	subInit.Flags().StringVar(&dynamicLibraryPath, "dynamic-library-path", upgrade.DefaultDynamicLibraryPath, "sets the dynamic_library_path GUC to correctly find extensions installed outside their default location. Defaults to '$dynamic_library_path'.")
 	subInit.Flags().StringVar(&ports, "temp-port-range", "50432-65535", "set of ports to use when initializing the target cluster")
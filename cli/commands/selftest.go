@@ -0,0 +1,157 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/config"
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+)
+
+func selfTest() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "selftest",
+		Short: "subcommands to validate the gpupgrade installation",
+		Long:  "subcommands to validate the gpupgrade installation",
+	}
+
+	cmd.AddCommand(selfTestConf())
+	cmd.AddCommand(selfTestAgents())
+
+	return cmd
+}
+
+func selfTestConf() *cobra.Command {
+	var replay string
+	var simulate string
+	var outputDir string
+
+	cmd := &cobra.Command{
+		Use:   "conf",
+		Short: "validate the conf file rewrite engine on this host",
+		Long:  "validate the conf file rewrite engine against generated temporary conf files without requiring a cluster",
+		Args:  cobra.MaximumNArgs(0), // no positional args allowed
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if replay != "" {
+				dir, err := confOutputDir(outputDir)
+				if err != nil {
+					return err
+				}
+
+				return hub.ReplayConfSupportBundle(replay, dir, os.Stdout)
+			}
+
+			if simulate != "" {
+				dir, err := confOutputDir(outputDir)
+				if err != nil {
+					return err
+				}
+
+				return simulateConfFiles(simulate, dir)
+			}
+
+			results, err := hub.SelfTestConf(os.TempDir())
+			if err != nil {
+				return err
+			}
+
+			failed := false
+			for _, result := range results {
+				fmt.Println(result)
+				if result.Err != nil {
+					failed = true
+				}
+			}
+
+			if failed {
+				return errors.New("conf self test failed")
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&replay, "replay", "", "replay the conf update recorded in this support bundle against the conf files it captured")
+	cmd.Flags().StringVar(&simulate, "simulate", "", "simulate the conf update of the initialized upgrade against the conf files captured in this fixture directory")
+	cmd.Flags().StringVar(&outputDir, "output-dir", "", "the directory the conf files are written to, defaulting to a new temporary directory")
+	cmd.MarkFlagsMutuallyExclusive("replay", "simulate")
+
+	return cmd
+}
+
+// simulateConfFiles applies the conf edits finalize plans for the initialized
+// upgrade, with the settings of its hub configuration, to a copy of the
+// fixture in outputDir.
+func simulateConfFiles(fixtureDir string, outputDir string) error {
+	conf, err := config.Read()
+	if err != nil {
+		return xerrors.Errorf("read the upgrade to simulate: %w", err)
+	}
+
+	if conf.Intermediate == nil || conf.Target == nil {
+		return xerrors.New("the target cluster has not been initialized. Run gpupgrade initialize.")
+	}
+
+	if err := configureHub(conf); err != nil {
+		return err
+	}
+
+	if err := hub.SimulateConfFiles(fixtureDir, outputDir, conf.Target.Version, conf.Intermediate, conf.Target); err != nil {
+		return err
+	}
+
+	fmt.Printf("simulated the conf edits to %s\n", outputDir)
+	return nil
+}
+
+// confOutputDir returns the directory the conf files of a replay are written
+// to, creating a temporary one when none is given.
+func confOutputDir(outputDir string) (string, error) {
+	if outputDir != "" {
+		return outputDir, nil
+	}
+
+	return os.MkdirTemp("", "gpupgrade-conf-")
+}
+
+func selfTestAgents() *cobra.Command {
+	return &cobra.Command{
+		Use:   "agents",
+		Short: "validate every agent supports the conf update features the hub will use",
+		Long:  "validate every agent supports the conf update features the hub will use during finalize, listing the unsupported features of each host with an older agent",
+		Args:  cobra.MaximumNArgs(0), // no positional args allowed
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := connectToHub()
+			if err != nil {
+				return err
+			}
+
+			reply, err := client.CheckAgentCapabilities(context.Background(), &idl.CheckAgentCapabilitiesRequest{})
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("planned conf update features: %s\n", strings.Join(reply.GetFeatures(), ", "))
+			if len(reply.GetHosts()) == 0 {
+				fmt.Println("every agent supports the planned features")
+				return nil
+			}
+
+			for _, host := range reply.GetHosts() {
+				fmt.Printf("the agent on host %s does not support: %s\n", host.GetHostname(), strings.Join(host.GetUnsupported(), ", "))
+			}
+
+			return errors.New("agents do not support the planned conf update features. Upgrade gpupgrade on the listed hosts.")
+		},
+	}
+}
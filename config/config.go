@@ -21,6 +21,15 @@ import (
 
 const ConfigFileName = "config.json"
 
+// StateVersion stamps the layout of the persisted configuration. Increment it
+// whenever a change to Config or the clusters it contains means that state
+// written by an older gpupgrade can no longer be safely used by this one.
+const StateVersion = 1
+
+// unstampedStateVersion is the state version of configurations written before
+// the stamp was introduced, which have the layout of the first version.
+const unstampedStateVersion = 1
+
 type Config struct {
 	// We do not combine the state directory and backup directory for
 	// several reasons:
@@ -55,6 +64,154 @@ type Config struct {
 	UseHbaHostnames bool
 	UpgradeID       string
 	PgUpgradeJobs   uint
+
+	// JumpHost is the SSH destination, such as gpadmin@bastion, through which
+	// the hub reaches the agents when the segment hosts are not directly
+	// reachable. It is empty when the hub connects directly.
+	JumpHost string
+
+	// ConfEditConcurrency caps the conf files edited at once across every
+	// host when updating them. Zero uses the default of the hub.
+	ConfEditConcurrency int
+
+	// GpperfmonLogLocation is the absolute directory the gpperfmon of the
+	// target cluster logs to, such as one on a separate volume. It is empty
+	// to log under the target coordinator data directory.
+	GpperfmonLogLocation string
+
+	// ConfRPCConcurrency caps the hosts sent a conf read or write at once. Zero
+	// sends every host its request at once.
+	ConfRPCConcurrency int
+
+	// ConfHostTimeout bounds each conf read or write request to a host, such
+	// as "90s". Each retry of a request gets its own deadline. It is "0s" to
+	// not time out, and when empty defaults to 5m.
+	ConfHostTimeout string
+
+	// ConfFailureThreshold is the hosts whose conf update may fail without
+	// failing finalize, as a count such as "3" or a percentage of the hosts
+	// such as "5%". It is empty to fail on any host.
+	ConfFailureThreshold string
+
+	// CustomConfEditsFile is the JSON file of the operator provided edits made
+	// to the conf files of every segment after the built-in ones. It is empty
+	// to make only the built-in edits.
+	CustomConfEditsFile string
+
+	// ConfExcludeContents and ConfExcludeHosts are the segments left out of
+	// the conf update, by content or by host, such as a segment maintained
+	// independently during a phased rollout.
+	ConfExcludeContents []int
+	ConfExcludeHosts    []string
+
+	// TargetPortMapFile is the JSON file of the target ports of each content,
+	// supplied by an external planning system, that override the ports of the
+	// target cluster when rewriting the conf files. It is empty to use the
+	// ports of the cluster.
+	TargetPortMapFile string
+
+	// ConfOrder is whether the coordinator and standby conf files are updated
+	// before the segments, "coordinator-first", or after them,
+	// "segments-first". It is empty to update the coordinator first.
+	ConfOrder string
+
+	// ConfUnreachablePolicy is whether the conf update fails on a segment host
+	// whose agent cannot be reached, "fail", or skips it to be updated later,
+	// "skip". It is empty to fail.
+	ConfUnreachablePolicy string
+
+	// ConfRetryAttempts is how many times a conf request is sent to an agent
+	// that is briefly unavailable, such as while it restarts, before its host
+	// fails. Zero uses the default of 4.
+	ConfRetryAttempts int
+
+	// ConfRetryBackoff is how long the hub waits before the first retry of a
+	// conf request, such as "500ms". The wait doubles after each attempt up
+	// to ConfRetryMaxBackoff, such as "4s". Empty uses the defaults.
+	ConfRetryBackoff    string
+	ConfRetryMaxBackoff string
+
+	// ConfHeartbeatTimeout is how long the hub waits for the conf update of an
+	// agent to make progress before reporting its host as hung, such as "2m".
+	// It must be at least a second, and is empty to not poll the agents for a
+	// heartbeat.
+	ConfHeartbeatTimeout string
+
+	// CoreConfMode has the conf update make only the port rewrites of
+	// postgresql.conf and primary_conninfo, skipping every other edit.
+	CoreConfMode bool
+
+	// ConfBatchSegments has the conf update send the postgresql.conf and
+	// primary_conninfo edits of the segments in one request to each host.
+	ConfBatchSegments bool
+
+	// PreserveTrailingNewline has rewritten conf files keep the trailing
+	// newlines of their original rather than end with exactly one newline.
+	PreserveTrailingNewline bool
+
+	// ConfVerifyAfterWrite has each host read back the GUCs it edited in the
+	// same request that writes them, rolling back and failing the conf update
+	// of a file whose value is not the expected one.
+	ConfVerifyAfterWrite bool
+
+	// StrictPathReferences fails finalize when an edited conf file still
+	// references an intermediate data directory rather than only warning.
+	StrictPathReferences bool
+
+	// SnapshotConfBundle has the conf update capture every conf file it edits
+	// before and after the update into a bundle in the state directory, such
+	// as to attach to a support ticket.
+	SnapshotConfBundle bool
+
+	// ConfAuditFile is the file each completed conf edit is appended to as a
+	// line of JSON. When empty and ConfAuditWebhook is not set the edits are
+	// appended to conf-audit.log in the state directory.
+	ConfAuditFile string
+
+	// ConfAuditWebhook is an http or https URL each completed conf edit is
+	// posted to as JSON, such as of a central audit system, instead of
+	// appending it to a file.
+	ConfAuditWebhook string
+
+	// ConfAuditFailurePolicy is what to do when a conf edit cannot be
+	// recorded: "fatal" fails the host of the edit, and "warn", the default,
+	// logs a warning and continues.
+	ConfAuditFailurePolicy string
+
+	// RecordConfSupportBundle has the conf update write a support bundle to
+	// the state directory when it finishes, whether or not it succeeded. The
+	// bundle is replayed outside of the cluster with "gpupgrade selftest conf
+	// --replay".
+	RecordConfSupportBundle bool
+
+	// ConfBackupDir is an absolute directory, such as a shared mount, that
+	// each host also backs up its conf files to under its hostname before
+	// editing them. The local backups next to the files are always written
+	// since revert restores from them. When empty files are only backed up
+	// locally.
+	ConfBackupDir string
+
+	// ConfBackupPolicy is how an existing backup of a conf file, such as from
+	// a prior run, is handled: "fail", "skip-backup", "version" or
+	// "overwrite". When empty the new backup is versioned.
+	ConfBackupPolicy string
+
+	// ConfTempDir is an absolute directory each host writes the temporary
+	// file of an atomic conf rewrite to before renaming it over the conf
+	// file, such as to keep the data directories clean. It is only used for
+	// the files on the same filesystem. When empty the temporary file is
+	// written next to the conf file.
+	ConfTempDir string
+
+	// ConfPlanReportFile is the absolute path the conf update writes its plan
+	// of edits to as CSV before making them, including on a dry run, for
+	// review in a spreadsheet. It is empty to not write the plan.
+	ConfPlanReportFile string
+
+	// StateVersion is the StateVersion of the gpupgrade that created the
+	// configuration. Configurations written before the stamp was introduced
+	// are read as the first version.
+	StateVersion int
 }
 
 func (conf *Config) Write() error {
@@ -78,14 +235,33 @@ func Read() (*Config, error) {
 		return nil, xerrors.Errorf("unmarshal configuration file: %w", err)
 	}
 
+	// upgrades initialized before the stamp can still be finalized
+	if conf.StateVersion == 0 {
+		conf.StateVersion = unstampedStateVersion
+	}
+
 	return conf, nil
 }
 
+// CheckStateVersion errors when the configuration was written by a gpupgrade
+// whose persisted state is incompatible with the running binary.
+func CheckStateVersion(stateVersion int) error {
+	if stateVersion == StateVersion {
+		return nil
+	}
+
+	err := xerrors.Errorf("configuration %q has state version %d but this gpupgrade requires state version %d", GetConfigFile(), stateVersion, StateVersion)
+	nextAction := `The persisted state was written by a different version of gpupgrade.
+Re-run with the gpupgrade binary that started this upgrade, or run "gpupgrade revert"
+with that binary and start over.`
+	return utils.NewNextActionErr(err, nextAction)
+}
+
 func GetConfigFile() string {
 	return filepath.Join(utils.GetStateDir(), ConfigFileName)
 }
 
-func Create(db *sql.DB, hubPort int, agentPort int, sourceGPHome string, targetGPHome string, mode idl.Mode, useHbaHostnames bool, ports []int, pgUpgradeJobs uint, parentBackupDirs string) (Config, error) {
+func Create(db *sql.DB, hubPort int, agentPort int, sourceGPHome string, targetGPHome string, mode idl.Mode, useHbaHostnames bool, ports []int, pgUpgradeJobs uint, parentBackupDirs string, jumpHost string) (Config, error) {
 	source, err := greenplum.ClusterFromDB(db, sourceGPHome, idl.ClusterDestination_source)
 	if err != nil {
 		return Config{}, xerrors.Errorf("retrieve source configuration: %w", err)
@@ -103,12 +279,14 @@ func Create(db *sql.DB, hubPort int, agentPort int, sourceGPHome string, targetG
 	}
 
 	config := Config{}
+	config.StateVersion = StateVersion
 	config.HubPort = hubPort
 	config.AgentPort = agentPort
 	config.Mode = mode
 	config.UseHbaHostnames = useHbaHostnames
 	config.UpgradeID = upgrade.NewID()
 	config.PgUpgradeJobs = pgUpgradeJobs
+	config.JumpHost = jumpHost
 	config.BackupDirs, err = backupdir.ParseParentBackupDirs(parentBackupDirs, source)
 	if err != nil {
 		return Config{}, err
@@ -4,6 +4,7 @@
 package config_test
 
 import (
+	"errors"
 	"os"
 	"reflect"
 	"testing"
@@ -17,6 +18,7 @@ import (
 	"github.com/greenplum-db/gpupgrade/idl"
 	"github.com/greenplum-db/gpupgrade/testutils"
 	"github.com/greenplum-db/gpupgrade/testutils/exectest"
+	"github.com/greenplum-db/gpupgrade/utils"
 )
 
 func TestConfig(t *testing.T) {
@@ -29,6 +31,7 @@ func TestConfig(t *testing.T) {
 		AgentPort:    54321,
 		Mode:         idl.Mode_copy,
 		UpgradeID:    "ABC123",
+		StateVersion: config.StateVersion,
 	}
 
 	t.Run("save configuration contents to disk and load it back", func(t *testing.T) {
@@ -51,6 +54,29 @@ func TestConfig(t *testing.T) {
 			t.Errorf("wrote config %#v but wanted %#v", actual, conf)
 		}
 	})
+
+	t.Run("reads a configuration written without a state version as the first version", func(t *testing.T) {
+		stateDir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, stateDir)
+
+		resetEnv := testutils.SetEnv(t, "GPUPGRADE_HOME", stateDir)
+		defer resetEnv()
+
+		testutils.MustWriteToFile(t, config.GetConfigFile(), `{"HubPort": 12345, "AgentPort": 54321, "UpgradeID": "ABC123"}`)
+
+		actual, err := config.Read()
+		if err != nil {
+			t.Fatalf("loading config: %+v", err)
+		}
+
+		if actual.StateVersion != 1 {
+			t.Errorf("got state version %d want 1", actual.StateVersion)
+		}
+
+		if err := config.CheckStateVersion(actual.StateVersion); err != nil {
+			t.Errorf("unexpected error %#v", err)
+		}
+	})
 }
 
 func TestCreate(t *testing.T) {
@@ -79,6 +105,7 @@ func TestCreate(t *testing.T) {
 	const mode = idl.Mode_link
 	const useHbaHostnames = false
 	const parentBackupDirs = ""
+	const jumpHost = "gpadmin@bastion"
 	const pgUpgradeJobs = 1
 	ports, err := commands.ParsePorts("50432-65535")
 	if err != nil {
@@ -100,7 +127,7 @@ func TestCreate(t *testing.T) {
 			expectPgStatReplicationToReturn(mock)
 			expectPgTablespace(mock)
 
-			conf, err := config.Create(db, hubPort, agentPort, source.GPHome, targetGPHome, mode, useHbaHostnames, ports, pgUpgradeJobs, parentBackupDirs)
+			conf, err := config.Create(db, hubPort, agentPort, source.GPHome, targetGPHome, mode, useHbaHostnames, ports, pgUpgradeJobs, parentBackupDirs, jumpHost)
 			if err != nil {
 				t.Fatalf("unexpected error %#v", err)
 			}
@@ -126,7 +153,7 @@ func TestCreate(t *testing.T) {
 			expectPgStatReplicationToReturn(mock)
 			expectPgTablespace(mock)
 
-			conf, err := config.Create(db, hubPort, agentPort, source.GPHome, targetGPHome, mode, useHbaHostnames, ports, pgUpgradeJobs, parentBackupDirs)
+			conf, err := config.Create(db, hubPort, agentPort, source.GPHome, targetGPHome, mode, useHbaHostnames, ports, pgUpgradeJobs, parentBackupDirs, jumpHost)
 			if err != nil {
 				t.Fatalf("unexpected error %#v", err)
 			}
@@ -151,7 +178,7 @@ func TestCreate(t *testing.T) {
 			expectPgStatReplicationToReturn(mock)
 			expectPgTablespace(mock)
 
-			conf, err := config.Create(db, hubPort, agentPort, source.GPHome, targetGPHome, mode, useHbaHostnames, ports, pgUpgradeJobs, parentBackupDirs)
+			conf, err := config.Create(db, hubPort, agentPort, source.GPHome, targetGPHome, mode, useHbaHostnames, ports, pgUpgradeJobs, parentBackupDirs, jumpHost)
 			if err != nil {
 				t.Fatalf("unexpected error %#v", err)
 			}
@@ -168,7 +195,7 @@ func TestCreate(t *testing.T) {
 		expectPgStatReplicationToReturn(mock)
 		expectPgTablespace(mock)
 
-		conf, err := config.Create(db, hubPort, agentPort, source.GPHome, targetGPHome, mode, useHbaHostnames, ports, pgUpgradeJobs, parentBackupDirs)
+		conf, err := config.Create(db, hubPort, agentPort, source.GPHome, targetGPHome, mode, useHbaHostnames, ports, pgUpgradeJobs, parentBackupDirs, jumpHost)
 		if err != nil {
 			t.Fatalf("unexpected error %#v", err)
 		}
@@ -214,9 +241,36 @@ func TestCreate(t *testing.T) {
 			t.Errorf("got %d want %d", conf.PgUpgradeJobs, pgUpgradeJobs)
 		}
 
+		if conf.JumpHost != jumpHost {
+			t.Errorf("got %s want %s", conf.JumpHost, jumpHost)
+		}
+
 		if conf.UpgradeID == "" {
 			t.Errorf("expected non-empty UpgradeID")
 		}
+
+		if conf.StateVersion != config.StateVersion {
+			t.Errorf("got state version %d want %d", conf.StateVersion, config.StateVersion)
+		}
+	})
+}
+
+func TestCheckStateVersion(t *testing.T) {
+	t.Run("succeeds when the state version matches", func(t *testing.T) {
+		err := config.CheckStateVersion(config.StateVersion)
+		if err != nil {
+			t.Errorf("unexpected error %#v", err)
+		}
+	})
+
+	t.Run("errors with guidance when the state version does not match", func(t *testing.T) {
+		for _, stateVersion := range []int{0, config.StateVersion + 1} {
+			err := config.CheckStateVersion(stateVersion)
+			var nextActionErr utils.NextActionErr
+			if !errors.As(err, &nextActionErr) {
+				t.Errorf("got error %#v want type %T", err, nextActionErr)
+			}
+		}
 	})
 }
 
@@ -265,23 +265,33 @@ func (c *Cluster) Hosts() []string {
 }
 
 // SelectSegments returns a list of all segments that match the given selector
-// function.
+// function. Use ForEachSegment for very large clusters to avoid holding the
+// matches in memory at once.
 func (c Cluster) SelectSegments(selector func(*SegConfig) bool) SegConfigs {
 	var matches SegConfigs
 
+	c.ForEachSegment(selector, func(seg *SegConfig) bool {
+		matches = append(matches, *seg)
+		return true
+	})
+
+	return matches
+}
+
+// ForEachSegment calls visit with each segment that matches the given selector
+// function, primaries first. It stops early when visit returns false.
+func (c Cluster) ForEachSegment(selector func(*SegConfig) bool, visit func(*SegConfig) bool) {
 	for _, seg := range c.Primaries {
-		if selector(&seg) {
-			matches = append(matches, seg)
+		if selector(&seg) && !visit(&seg) {
+			return
 		}
 	}
 
 	for _, seg := range c.Mirrors {
-		if selector(&seg) {
-			matches = append(matches, seg)
+		if selector(&seg) && !visit(&seg) {
+			return
 		}
 	}
-
-	return matches
 }
 
 func (c *Cluster) Start(stream step.OutStreams) error {
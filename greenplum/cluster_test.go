@@ -300,6 +300,49 @@ func TestSelectSegments(t *testing.T) {
 
 }
 
+func TestForEachSegment(t *testing.T) {
+	cluster := greenplum.MustCreateCluster(t, greenplum.SegConfigs{
+		{ContentID: 1, Role: greenplum.PrimaryRole},
+		{ContentID: 2, Role: greenplum.PrimaryRole},
+		{ContentID: 3, Role: greenplum.PrimaryRole},
+		{ContentID: 3, Role: greenplum.MirrorRole},
+	})
+
+	t.Run("visits the selected segments", func(t *testing.T) {
+		var actual greenplum.SegConfigs
+		cluster.ForEachSegment(func(seg *greenplum.SegConfig) bool {
+			return seg.ContentID > 1
+		}, func(seg *greenplum.SegConfig) bool {
+			actual = append(actual, *seg)
+			return true
+		})
+		sort.Sort(actual)
+
+		expected := greenplum.SegConfigs{
+			{ContentID: 2, Role: greenplum.PrimaryRole},
+			{ContentID: 3, Role: greenplum.PrimaryRole},
+			{ContentID: 3, Role: greenplum.MirrorRole},
+		}
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("got %+v want %+v", actual, expected)
+		}
+	})
+
+	t.Run("stops when visit returns false", func(t *testing.T) {
+		visited := 0
+		cluster.ForEachSegment(func(seg *greenplum.SegConfig) bool {
+			return true
+		}, func(seg *greenplum.SegConfig) bool {
+			visited++
+			return visited < 2
+		})
+
+		if visited != 2 {
+			t.Errorf("visited %d segments want 2", visited)
+		}
+	})
+}
+
 func TestHasAllMirrorsAndStandby(t *testing.T) {
 	t.Run("returns true on full cluster", func(t *testing.T) {
 		segs := greenplum.SegConfigs{
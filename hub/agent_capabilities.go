@@ -0,0 +1,178 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/blang/semver/v4"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/idl"
+)
+
+// The conf update features an agent may not support when it is older than
+// the hub.
+const (
+	ConfFeatureExpectedMatches         = "expected-matches"
+	ConfFeaturePreserveTrailingNewline = "preserve-trailing-newline"
+	ConfFeatureMatchCurrentValue       = "match-current-value"
+	ConfFeatureVerifyAfterWrite        = "verify-after-write"
+	ConfFeatureRollBackUnverified      = "roll-back-unverified"
+	ConfFeatureSearchConfiguration     = "search-configuration"
+	ConfFeatureInventorySegments       = "inventory-segments"
+	ConfFeatureListConfBackups         = "list-conf-backups"
+	ConfFeatureSnapshotConfFiles       = "snapshot-conf-files"
+	ConfFeatureConfUpdateHeartbeat     = "conf-update-heartbeat"
+	ConfFeatureConfFileChecksums       = "conf-file-checksums"
+	ConfFeatureDuplicateMatches        = "duplicate-matches"
+)
+
+// AgentConfFeatures are the conf update features supported by this version
+// of the agent.
+var AgentConfFeatures = []string{
+	ConfFeatureExpectedMatches,
+	ConfFeaturePreserveTrailingNewline,
+	ConfFeatureMatchCurrentValue,
+	ConfFeatureVerifyAfterWrite,
+	ConfFeatureRollBackUnverified,
+	ConfFeatureSearchConfiguration,
+	ConfFeatureInventorySegments,
+	ConfFeatureListConfBackups,
+	ConfFeatureSnapshotConfFiles,
+	ConfFeatureConfUpdateHeartbeat,
+	ConfFeatureConfFileChecksums,
+	ConfFeatureDuplicateMatches,
+}
+
+// PlannedConfFeatures returns the sorted conf update features finalize uses
+// with the current settings.
+func PlannedConfFeatures(version semver.Version, intermediate *greenplum.Cluster, target *greenplum.Cluster) ([]string, error) {
+	plan, err := PlanConfFiles(version, intermediate, target)
+	if err != nil {
+		return nil, err
+	}
+
+	// finalize always inventories the segments for late mirrors and searches
+	// for references to the intermediate data directories
+	features := map[string]bool{
+		ConfFeatureInventorySegments:   true,
+		ConfFeatureSearchConfiguration: true,
+	}
+
+	if verifyAfterWrite {
+		features[ConfFeatureVerifyAfterWrite] = true
+		features[ConfFeatureRollBackUnverified] = true
+	}
+
+	if snapshotConfBundle {
+		features[ConfFeatureSnapshotConfFiles] = true
+	}
+
+	if confHeartbeatTimeout > 0 {
+		features[ConfFeatureConfUpdateHeartbeat] = true
+	}
+
+	for _, opt := range plan.Options() {
+		if opt.GetExpectedMatches() > 0 {
+			features[ConfFeatureExpectedMatches] = true
+		}
+
+		if opt.GetPreserveTrailingNewline() {
+			features[ConfFeaturePreserveTrailingNewline] = true
+		}
+
+		if opt.GetMatchCurrentValue() != "" {
+			features[ConfFeatureMatchCurrentValue] = true
+		}
+
+		if opt.GetDuplicateMatches() != idl.UpdateFileConfOptions_rewriteAllMatches {
+			features[ConfFeatureDuplicateMatches] = true
+		}
+	}
+
+	var sorted []string
+	for feature := range features {
+		sorted = append(sorted, feature)
+	}
+	sort.Strings(sorted)
+
+	return sorted, nil
+}
+
+// CheckAgentCapabilities returns each host whose agent does not support all
+// of the features, along with the unsupported features, sorted by host. An
+// agent predating capability reporting supports none of them.
+func CheckAgentCapabilities(agentConns []*idl.Connection, features []string) ([]*idl.CheckAgentCapabilitiesReply_Host, error) {
+	var mutex sync.Mutex
+	var hosts []*idl.CheckAgentCapabilitiesReply_Host
+
+	request := func(ctx context.Context, conn *idl.Connection) error {
+		reply, err := conn.AgentClient.GetCapabilities(ctx, &idl.GetCapabilitiesRequest{})
+		if status.Code(err) == codes.Unimplemented {
+			reply, err = &idl.GetCapabilitiesReply{}, nil
+		}
+
+		if err != nil {
+			return xerrors.Errorf("get capabilities of the agent on host %s: %w", conn.Hostname, err)
+		}
+
+		supported := make(map[string]bool)
+		for _, feature := range reply.GetFeatures() {
+			supported[feature] = true
+		}
+
+		var unsupported []string
+		for _, feature := range features {
+			if !supported[feature] {
+				unsupported = append(unsupported, feature)
+			}
+		}
+
+		if len(unsupported) == 0 {
+			return nil
+		}
+
+		mutex.Lock()
+		hosts = append(hosts, &idl.CheckAgentCapabilitiesReply_Host{Hostname: conn.Hostname, Unsupported: unsupported})
+		mutex.Unlock()
+
+		return nil
+	}
+
+	if err := ExecuteRPCContext(context.Background(), agentConns, request); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(hosts, func(i, j int) bool { return hosts[i].GetHostname() < hosts[j].GetHostname() })
+	return hosts, nil
+}
+
+func (s *Server) CheckAgentCapabilities(ctx context.Context, req *idl.CheckAgentCapabilitiesRequest) (*idl.CheckAgentCapabilitiesReply, error) {
+	if s.Intermediate == nil || s.Target == nil {
+		return nil, xerrors.New("the target cluster has not been initialized. Run gpupgrade initialize.")
+	}
+
+	features, err := PlannedConfFeatures(s.Target.Version, s.Intermediate, s.Target)
+	if err != nil {
+		return nil, err
+	}
+
+	agentConns, err := s.AgentConns()
+	if err != nil {
+		return nil, err
+	}
+
+	hosts, err := CheckAgentCapabilities(agentConns, features)
+	if err != nil {
+		return nil, err
+	}
+
+	return &idl.CheckAgentCapabilitiesReply{Features: features, Hosts: hosts}, nil
+}
@@ -0,0 +1,119 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/blang/semver/v4"
+	"github.com/golang/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/idl/mock_idl"
+)
+
+func TestAgentCapabilities(t *testing.T) {
+	intermediate := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: "/data/qddir/seg.HqtFHX54y0o.-1", Port: 50432, Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg.HqtFHX54y0o.1", Port: 50434, Role: greenplum.PrimaryRole},
+	})
+
+	target := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: "/data/qddir/seg-1", Port: 15432, Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg1", Port: 25433, Role: greenplum.PrimaryRole},
+	})
+
+	version := semver.MustParse("7.0.0")
+
+	t.Run("plans the features used with the current settings", func(t *testing.T) {
+		features, err := hub.PlannedConfFeatures(version, intermediate, target)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		expected := []string{hub.ConfFeatureDuplicateMatches, hub.ConfFeatureInventorySegments, hub.ConfFeatureSearchConfiguration}
+		if !reflect.DeepEqual(features, expected) {
+			t.Errorf("got %q want %q", features, expected)
+		}
+
+		hub.SetVerifyAfterWrite(true)
+		defer hub.ResetVerifyAfterWrite()
+
+		hub.SetEnsureTrailingNewline(false)
+		defer hub.ResetEnsureTrailingNewline()
+
+		features, err = hub.PlannedConfFeatures(version, intermediate, target)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		expected = []string{
+			hub.ConfFeatureDuplicateMatches,
+			hub.ConfFeatureInventorySegments,
+			hub.ConfFeaturePreserveTrailingNewline,
+			hub.ConfFeatureRollBackUnverified,
+			hub.ConfFeatureSearchConfiguration,
+			hub.ConfFeatureVerifyAfterWrite,
+		}
+		if !reflect.DeepEqual(features, expected) {
+			t.Errorf("got %q want %q", features, expected)
+		}
+	})
+
+	features := []string{hub.ConfFeatureInventorySegments, hub.ConfFeatureVerifyAfterWrite}
+
+	t.Run("reports the unsupported features of each host", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		current := mock_idl.NewMockAgentClient(ctrl)
+		current.EXPECT().GetCapabilities(gomock.Any(), gomock.Any()).Return(&idl.GetCapabilitiesReply{Features: hub.AgentConfFeatures}, nil)
+
+		older := mock_idl.NewMockAgentClient(ctrl)
+		older.EXPECT().GetCapabilities(gomock.Any(), gomock.Any()).Return(&idl.GetCapabilitiesReply{Features: []string{hub.ConfFeatureInventorySegments}}, nil)
+
+		// an agent predating capability reporting
+		oldest := mock_idl.NewMockAgentClient(ctrl)
+		oldest.EXPECT().GetCapabilities(gomock.Any(), gomock.Any()).Return(nil, status.Error(codes.Unimplemented, "unknown method GetCapabilities"))
+
+		agentConns := []*idl.Connection{
+			{AgentClient: oldest, Hostname: "sdw3"},
+			{AgentClient: current, Hostname: "sdw1"},
+			{AgentClient: older, Hostname: "sdw2"},
+		}
+
+		hosts, err := hub.CheckAgentCapabilities(agentConns, features)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		expected := []*idl.CheckAgentCapabilitiesReply_Host{
+			{Hostname: "sdw2", Unsupported: []string{hub.ConfFeatureVerifyAfterWrite}},
+			{Hostname: "sdw3", Unsupported: features},
+		}
+		if !reflect.DeepEqual(hosts, expected) {
+			t.Errorf("got %v want %v", hosts, expected)
+		}
+	})
+
+	t.Run("errors when an agent cannot be queried", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		expected := errors.New("connection refused")
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw1.EXPECT().GetCapabilities(gomock.Any(), gomock.Any()).Return(nil, expected)
+
+		_, err := hub.CheckAgentCapabilities([]*idl.Connection{{AgentClient: sdw1, Hostname: "sdw1"}}, features)
+		if !errors.Is(err, expected) {
+			t.Errorf("got error %#v want %#v", err, expected)
+		}
+	})
+}
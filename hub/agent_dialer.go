@@ -0,0 +1,24 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"context"
+	"net"
+)
+
+// agentContextDialer reaches the address of an agent for the gRPC
+// connections of the hub, such as to tunnel through the jump host when the
+// segment hosts are not directly reachable. When nil the hub connects
+// directly. The requests sent over the connections, such as those of a conf
+// update, are unchanged. The hub sets it from its configuration.
+var agentContextDialer func(ctx context.Context, address string) (net.Conn, error)
+
+func SetAgentContextDialer(dialer func(ctx context.Context, address string) (net.Conn, error)) {
+	agentContextDialer = dialer
+}
+
+func ResetAgentContextDialer() {
+	agentContextDialer = nil
+}
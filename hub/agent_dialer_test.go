@@ -0,0 +1,93 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/greenplum-db/gpupgrade/agent"
+	"github.com/greenplum-db/gpupgrade/config"
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/testutils"
+	"github.com/greenplum-db/gpupgrade/testutils/testlog"
+)
+
+func TestAgentContextDialer(t *testing.T) {
+	testlog.SetupTestLogger()
+
+	// the agent is reachable only through the in-process listener
+	listener := bufconn.Listen(1024 * 1024)
+	agentServer := grpc.NewServer()
+	defer agentServer.Stop()
+
+	idl.RegisterAgentServer(agentServer, &agent.Server{})
+	go func() {
+		_ = agentServer.Serve(listener)
+	}()
+
+	var mutex sync.Mutex
+	var addresses []string
+	hub.SetAgentContextDialer(func(ctx context.Context, address string) (net.Conn, error) {
+		mutex.Lock()
+		addresses = append(addresses, address)
+		mutex.Unlock()
+
+		return listener.DialContext(ctx)
+	})
+	defer hub.ResetAgentContextDialer()
+
+	dataDir := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, dataDir)
+
+	path := filepath.Join(dataDir, "postgresql.conf")
+	testutils.MustWriteToFile(t, path, "port=50434\n")
+
+	source := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "cdw", DataDir: "/data/qddir/seg-1", Port: 15432, Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: dataDir, Port: 25432, Role: greenplum.PrimaryRole},
+	})
+	intermediate := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "cdw", DataDir: "/data/qddir/seg-1", Port: 50432, Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: dataDir, Port: 50434, Role: greenplum.PrimaryRole},
+	})
+
+	hubServer := hub.New(&config.Config{Source: source, Target: source, Intermediate: intermediate, AgentPort: 6416})
+
+	agentConns, err := hubServer.AgentConns()
+	if err != nil {
+		t.Fatalf("unexpected error %+v", err)
+	}
+	defer func() {
+		for _, conn := range agentConns {
+			_ = conn.Conn.Close()
+			conn.CancelContext()
+		}
+	}()
+
+	err = hub.UpdatePostgresqlConfOnSegments(context.Background(), agentConns, intermediate, source)
+	if err != nil {
+		t.Fatalf("unexpected error %+v", err)
+	}
+
+	contents := testutils.MustReadFile(t, path)
+	if contents != "port=25432\n" {
+		t.Errorf("got %q want %q", contents, "port=25432\n")
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	if !reflect.DeepEqual(addresses, []string{"sdw1:6416"}) {
+		t.Errorf("dialed %q want %q", addresses, []string{"sdw1:6416"})
+	}
+}
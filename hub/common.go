@@ -11,3 +11,6 @@ import "os/exec"
 
 // Allow exec.Command to be mocked out by exectest.NewCommand.
 var ExecCommand = exec.Command
+
+// Allow exec.CommandContext to be mocked out by exectest.NewCommandContext.
+var ExecCommandContext = exec.CommandContext
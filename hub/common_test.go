@@ -76,6 +76,14 @@ func ResetExecCommand() {
 	ExecCommand = nil
 }
 
+func SetExecCommandContext(cmdFunc exectest.CommandContext) {
+	ExecCommandContext = cmdFunc
+}
+
+func ResetExecCommandContext() {
+	ExecCommandContext = nil
+}
+
 func SetCheckDiskUsage(usageFunc disk.CheckUsageType) {
 	checkDiskUsage = usageFunc
 }
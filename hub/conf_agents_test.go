@@ -0,0 +1,113 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/blang/semver/v4"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/testutils/mock_agent"
+)
+
+func TestConfUpdateThroughAgents(t *testing.T) {
+	intermediate := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: "/data/qddir/seg.HqtFHX54y0o.-1", Port: 50432, Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: -1, Hostname: "standby", DataDir: "/data/standby.HqtFHX54y0o", Port: 50433, Role: greenplum.MirrorRole},
+		{DbID: 3, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg.HqtFHX54y0o.1", Port: 50434, Role: greenplum.PrimaryRole},
+		{DbID: 4, ContentID: 0, Hostname: "sdw2", DataDir: "/data/dbfast_mirror1/seg.HqtFHX54y0o.1", Port: 50435, Role: greenplum.MirrorRole},
+		{DbID: 5, ContentID: 1, Hostname: "sdw2", DataDir: "/data/dbfast2/seg.HqtFHX54y0o.2", Port: 50436, Role: greenplum.PrimaryRole},
+		{DbID: 6, ContentID: 1, Hostname: "sdw1", DataDir: "/data/dbfast_mirror2/seg.HqtFHX54y0o.2", Port: 50437, Role: greenplum.MirrorRole},
+	})
+	target := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: "/data/qddir/seg-1", Port: 15432, Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: -1, Hostname: "standby", DataDir: "/data/standby", Port: 16432, Role: greenplum.MirrorRole},
+		{DbID: 3, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg1", Port: 25433, Role: greenplum.PrimaryRole},
+		{DbID: 4, ContentID: 0, Hostname: "sdw2", DataDir: "/data/dbfast_mirror1/seg1", Port: 25434, Role: greenplum.MirrorRole},
+		{DbID: 5, ContentID: 1, Hostname: "sdw2", DataDir: "/data/dbfast2/seg2", Port: 25435, Role: greenplum.PrimaryRole},
+		{DbID: 6, ContentID: 1, Hostname: "sdw1", DataDir: "/data/dbfast_mirror2/seg2", Port: 25436, Role: greenplum.MirrorRole},
+	})
+
+	version := semver.MustParse("7.0.0")
+
+	// checkRequest errors unless the agent on the host received exactly one
+	// request with the options.
+	checkRequest := func(t *testing.T, agents *mock_agent.ConfAgents, hostname string, expected []*idl.UpdateFileConfOptions) {
+		t.Helper()
+
+		requests := agents.Requests(hostname)
+		if len(requests) != 1 {
+			t.Fatalf("got %d requests to host %s want 1", len(requests), hostname)
+		}
+
+		actual := requests[0].GetOptions()
+		if len(actual) != len(expected) {
+			t.Fatalf("got options %v for host %s want %v", actual, hostname, expected)
+		}
+
+		for i := range expected {
+			if !proto.Equal(actual[i], expected[i]) {
+				t.Errorf("got option %v for host %s want %v", actual[i], hostname, expected[i])
+			}
+		}
+	}
+
+	t.Run("sends the segment hosts their port rewrites", func(t *testing.T) {
+		agents := mock_agent.NewConfAgents("standby", "sdw1", "sdw2")
+		defer agents.Stop()
+
+		err := hub.UpdatePostgresqlConfOnSegments(context.Background(), agents.Conns(), intermediate, target)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		// the standby is updated in its own phase
+		if !reflect.DeepEqual(agents.Hosts(), []string{"sdw1", "sdw2"}) {
+			t.Errorf("got requests to hosts %q want %q", agents.Hosts(), []string{"sdw1", "sdw2"})
+		}
+
+		for _, hostname := range []string{"sdw1", "sdw2"} {
+			checkRequest(t, agents, hostname, hub.BuildPostgresqlConfOptions(hostname, intermediate, target))
+		}
+	})
+
+	t.Run("sends only the standby host the standby edits", func(t *testing.T) {
+		agents := mock_agent.NewConfAgents("standby", "sdw1", "sdw2")
+		defer agents.Stop()
+
+		err := hub.UpdateStandbyConfFiles(context.Background(), agents.Conns(), version, intermediate, target)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		if !reflect.DeepEqual(agents.Hosts(), []string{"standby"}) {
+			t.Errorf("got requests to hosts %q want %q", agents.Hosts(), []string{"standby"})
+		}
+
+		checkRequest(t, agents, "standby", hub.BuildStandbyConfOptions("standby", version, intermediate, target))
+	})
+
+	t.Run("returns the error of a failing host", func(t *testing.T) {
+		agents := mock_agent.NewConfAgents("sdw1", "sdw2")
+		defer agents.Stop()
+
+		agents.SetError("sdw2", status.Error(codes.Internal, "permission denied"))
+
+		err := hub.UpdatePostgresqlConfOnSegments(context.Background(), agents.Conns(), intermediate, target)
+		if status.Code(err) != codes.Internal || !strings.Contains(err.Error(), "permission denied") {
+			t.Errorf("got error %v want the error of sdw2", err)
+		}
+
+		checkRequest(t, agents, "sdw1", hub.BuildPostgresqlConfOptions("sdw1", intermediate, target))
+	})
+}
@@ -0,0 +1,202 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/utils"
+	"github.com/greenplum-db/gpupgrade/utils/errorlist"
+)
+
+// ConfAuditFileName is the file in the state directory FileAuditSink appends
+// to by default.
+const ConfAuditFileName = "conf-audit.log"
+
+// ConfAuditEvent is a completed conf edit.
+type ConfAuditEvent struct {
+	Hostname string    `json:"hostname"`
+	Path     string    `json:"path"`
+	GUC      string    `json:"guc"`
+	OldValue string    `json:"oldValue"`
+	NewValue string    `json:"newValue"`
+	Reason   string    `json:"reason"`
+	RunID    string    `json:"runID"`
+	Time     time.Time `json:"time"`
+}
+
+// ConfAuditSink records the conf edits made by UpdateConfFiles, such as in a
+// central audit system, as they complete.
+type ConfAuditSink interface {
+	Record(event ConfAuditEvent) error
+}
+
+// FileAuditSink appends each event as a line of JSON to Path, or to
+// ConfAuditFileName in the state directory when Path is empty.
+type FileAuditSink struct {
+	Path string
+}
+
+// auditFileMutex serializes appends since the hosts complete concurrently.
+var auditFileMutex sync.Mutex
+
+func (f FileAuditSink) Record(event ConfAuditEvent) (err error) {
+	path := f.Path
+	if path == "" {
+		path = filepath.Join(utils.GetStateDir(), ConfAuditFileName)
+	}
+
+	line, err := json.Marshal(event)
+	if err != nil {
+		return xerrors.Errorf("marshal audit event: %w", err)
+	}
+
+	auditFileMutex.Lock()
+	defer auditFileMutex.Unlock()
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cErr := file.Close(); cErr != nil {
+			err = errorlist.Append(err, cErr)
+		}
+	}()
+
+	_, err = file.Write(append(line, '\n'))
+	return err
+}
+
+// WebhookAuditSink posts each event as JSON to URL. Client defaults to
+// http.DefaultClient.
+type WebhookAuditSink struct {
+	URL    string
+	Client *http.Client
+}
+
+func (w WebhookAuditSink) Record(event ConfAuditEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return xerrors.Errorf("marshal audit event: %w", err)
+	}
+
+	client := w.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Post(w.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return xerrors.Errorf("post audit event to %s: %w", w.URL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return xerrors.Errorf("post audit event to %s: %s", w.URL, resp.Status)
+	}
+
+	return nil
+}
+
+var confAuditSink ConfAuditSink = FileAuditSink{}
+
+func SetConfAuditSink(sink ConfAuditSink) {
+	confAuditSink = sink
+}
+
+func ResetConfAuditSink() {
+	confAuditSink = FileAuditSink{}
+}
+
+// AuditFailurePolicy is what to do when an edit cannot be recorded in the
+// audit sink.
+type AuditFailurePolicy string
+
+const (
+	// AuditFailureFatal fails the host whose edit was not recorded.
+	AuditFailureFatal AuditFailurePolicy = "fatal"
+	// AuditFailureWarn logs a warning and continues.
+	AuditFailureWarn AuditFailurePolicy = "warn"
+)
+
+func (p AuditFailurePolicy) Validate() error {
+	switch p {
+	case AuditFailureFatal, AuditFailureWarn:
+		return nil
+	default:
+		return xerrors.Errorf("unknown audit failure policy %q, expected %q or %q", p, AuditFailureFatal, AuditFailureWarn)
+	}
+}
+
+var auditFailurePolicy = AuditFailureWarn
+
+func SetAuditFailurePolicy(policy AuditFailurePolicy) {
+	auditFailurePolicy = policy
+}
+
+func ResetAuditFailurePolicy() {
+	auditFailurePolicy = AuditFailureWarn
+}
+
+// audit records each edit of the host whose file changed, leaving out the
+// edits that were skipped and the files that were rolled back.
+func (c *confChanges) audit(hostname string, edits ConfPlan, changedPaths []string, skipped []string, rolledBack []string) error {
+	if c == nil {
+		return nil
+	}
+
+	completed := make(map[string]bool)
+	for _, path := range changedPaths {
+		completed[path] = true
+	}
+
+	for _, path := range rolledBack {
+		delete(completed, path)
+	}
+
+	skips := make(map[string]bool)
+	for _, skip := range skipped {
+		skips[skip] = true
+	}
+
+	var err error
+	for _, edit := range edits {
+		opt := edit.Option
+		if !completed[opt.GetPath()] || skips[skippedEditMessage(opt.GetPath(), opt)] {
+			continue
+		}
+
+		event := ConfAuditEvent{
+			Hostname: hostname,
+			Path:     opt.GetPath(),
+			GUC:      opt.GetGuc(),
+			OldValue: edit.OldValue,
+			NewValue: edit.NewValue,
+			Reason:   opt.GetReason(),
+			RunID:    c.runID,
+			Time:     utils.System.Now(),
+		}
+
+		if rErr := confAuditSink.Record(event); rErr != nil {
+			err = errorlist.Append(err, xerrors.Errorf("record the edit of %s in %s on host %s in the audit sink: %w", opt.GetGuc(), opt.GetPath(), hostname, rErr))
+		}
+	}
+
+	if err != nil && auditFailurePolicy != AuditFailureFatal {
+		log.Printf("Warning: %v", err)
+		return nil
+	}
+
+	return err
+}
@@ -0,0 +1,185 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blang/semver/v4"
+
+	"github.com/greenplum-db/gpupgrade/config"
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/step"
+	"github.com/greenplum-db/gpupgrade/testutils"
+	"github.com/greenplum-db/gpupgrade/utils"
+)
+
+type failingAuditSink struct{}
+
+func (failingAuditSink) Record(hub.ConfAuditEvent) error {
+	return errors.New("audit service unavailable")
+}
+
+func TestConfAudit(t *testing.T) {
+	now := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	utils.System.Now = func() time.Time {
+		return now
+	}
+	defer utils.ResetSystemFunctions()
+
+	stateDir := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, stateDir)
+
+	resetEnv := testutils.SetEnv(t, "GPUPGRADE_HOME", stateDir)
+	defer resetEnv()
+
+	coordinatorDir := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, coordinatorDir)
+
+	coordinatorConf := filepath.Join(coordinatorDir, "postgresql.conf")
+
+	intermediate := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: coordinatorDir, Port: 50432, Role: greenplum.PrimaryRole},
+	})
+	target := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: coordinatorDir, Port: 15432, Role: greenplum.PrimaryRole},
+	})
+
+	version := semver.MustParse("7.0.0")
+
+	t.Run("appends each completed edit to the audit file by default", func(t *testing.T) {
+		testutils.MustWriteToFile(t, coordinatorConf, "port=50432\n")
+
+		err := hub.UpdateConfFiles(context.Background(), nil, nil, step.DevNullStream, config.StateVersion, hub.ConfUpdateOptions{}, version, intermediate, target)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		lines := strings.Split(strings.TrimSpace(testutils.MustReadFile(t, filepath.Join(stateDir, hub.ConfAuditFileName))), "\n")
+		if len(lines) != 1 {
+			t.Fatalf("got %d audit records want 1: %q", len(lines), lines)
+		}
+
+		var event hub.ConfAuditEvent
+		if err := json.Unmarshal([]byte(lines[0]), &event); err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		if event.RunID == "" {
+			t.Errorf("expected the audit record to have a run ID")
+		}
+		event.RunID = ""
+
+		expected := hub.ConfAuditEvent{
+			Hostname: "coordinator",
+			Path:     coordinatorConf,
+			GUC:      "port",
+			OldValue: "50432",
+			NewValue: "15432",
+			Reason:   hub.ReasonPortRewrite,
+			Time:     now,
+		}
+		if !reflect.DeepEqual(event, expected) {
+			t.Errorf("got %+v want %+v", event, expected)
+		}
+	})
+
+	t.Run("posts each completed edit to a webhook", func(t *testing.T) {
+		var events []hub.ConfAuditEvent
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				t.Errorf("unexpected error %+v", err)
+			}
+
+			var event hub.ConfAuditEvent
+			if err := json.Unmarshal(body, &event); err != nil {
+				t.Errorf("unexpected error %+v", err)
+			}
+			events = append(events, event)
+		}))
+		defer server.Close()
+
+		hub.SetConfAuditSink(hub.WebhookAuditSink{URL: server.URL})
+		defer hub.ResetConfAuditSink()
+
+		testutils.MustWriteToFile(t, coordinatorConf, "port=50432\n")
+
+		err := hub.UpdateConfFiles(context.Background(), nil, nil, step.DevNullStream, config.StateVersion, hub.ConfUpdateOptions{}, version, intermediate, target)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		if len(events) != 1 || events[0].Path != coordinatorConf || events[0].NewValue != "15432" {
+			t.Errorf("got events %+v want the port edit of %s", events, coordinatorConf)
+		}
+	})
+
+	t.Run("errors when the webhook rejects an event", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		err := hub.WebhookAuditSink{URL: server.URL}.Record(hub.ConfAuditEvent{})
+		expected := "post audit event to " + server.URL + ": 503 Service Unavailable"
+		if err == nil || err.Error() != expected {
+			t.Errorf("got error %v want %q", err, expected)
+		}
+	})
+
+	t.Run("does not record edits that leave the file unchanged", func(t *testing.T) {
+		sink := hub.FileAuditSink{Path: filepath.Join(stateDir, "unchanged.log")}
+		hub.SetConfAuditSink(sink)
+		defer hub.ResetConfAuditSink()
+
+		testutils.MustWriteToFile(t, coordinatorConf, "port=15432\n")
+
+		err := hub.UpdateConfFiles(context.Background(), nil, nil, step.DevNullStream, config.StateVersion, hub.ConfUpdateOptions{}, version, intermediate, target)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		testutils.PathMustNotExist(t, sink.Path)
+	})
+
+	t.Run("warns by default when an edit cannot be recorded", func(t *testing.T) {
+		hub.SetConfAuditSink(failingAuditSink{})
+		defer hub.ResetConfAuditSink()
+
+		testutils.MustWriteToFile(t, coordinatorConf, "port=50432\n")
+
+		err := hub.UpdateConfFiles(context.Background(), nil, nil, step.DevNullStream, config.StateVersion, hub.ConfUpdateOptions{}, version, intermediate, target)
+		if err != nil {
+			t.Errorf("unexpected error %+v", err)
+		}
+	})
+
+	t.Run("fails when an edit cannot be recorded and failures are fatal", func(t *testing.T) {
+		hub.SetConfAuditSink(failingAuditSink{})
+		defer hub.ResetConfAuditSink()
+
+		hub.SetAuditFailurePolicy(hub.AuditFailureFatal)
+		defer hub.ResetAuditFailurePolicy()
+
+		testutils.MustWriteToFile(t, coordinatorConf, "port=50432\n")
+
+		err := hub.UpdateConfFiles(context.Background(), nil, nil, step.DevNullStream, config.StateVersion, hub.ConfUpdateOptions{}, version, intermediate, target)
+		expected := "record the edit of port in " + coordinatorConf + " on host coordinator in the audit sink: audit service unavailable"
+		if err == nil || err.Error() != expected {
+			t.Errorf("got error %v want %q", err, expected)
+		}
+	})
+}
@@ -0,0 +1,282 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/xerrors"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
+)
+
+// BackupSuffix is appended to the path of a conf file to name its local
+// pre-edit backup unless its options name another suffix. Only backups with
+// this suffix are listed, restored on revert and deleted on finalize.
+const BackupSuffix = ".bak"
+
+// BackupSink stores the original contents of a conf file before it is edited,
+// naming the backup with suffix and handling an existing backup by policy. A
+// conf file is only edited once its backup has been successfully written so
+// that every edit is recoverable.
+type BackupSink interface {
+	Backup(path string, suffix string, contents []byte, perm os.FileMode, policy BackupPolicy) error
+}
+
+// LocalBackupSink writes the backup next to the conf file.
+type LocalBackupSink struct{}
+
+func (LocalBackupSink) Backup(path string, suffix string, contents []byte, perm os.FileMode, policy BackupPolicy) error {
+	return writeBackup(path+suffix, contents, perm, policy)
+}
+
+// DirectoryBackupSink writes the backup to a central directory, such as a
+// shared mount, under the hostname and the absolute path of the conf file.
+// For example, /central/sdw1/data/primary/gpseg0/postgresql.conf.bak.
+type DirectoryBackupSink struct {
+	Dir string
+}
+
+func (d DirectoryBackupSink) Backup(path string, suffix string, contents []byte, perm os.FileMode, policy BackupPolicy) error {
+	hostname, err := utils.System.Hostname()
+	if err != nil {
+		return err
+	}
+
+	// drop any volume name such as C: so the path nests under the hostname
+	backup := filepath.Join(d.Dir, hostname, strings.TrimPrefix(path, filepath.VolumeName(path))+suffix)
+	if err := utils.System.MkdirAll(filepath.Dir(backup), 0700); err != nil {
+		return err
+	}
+
+	return writeBackup(backup, contents, perm, policy)
+}
+
+// MultiBackupSink writes the backup to each sink in order, such as both a
+// local and a central backup.
+type MultiBackupSink []BackupSink
+
+func (m MultiBackupSink) Backup(path string, suffix string, contents []byte, perm os.FileMode, policy BackupPolicy) error {
+	for _, sink := range m {
+		if err := sink.Backup(path, suffix, contents, perm, policy); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+var backupSink BackupSink = LocalBackupSink{}
+
+// confBackupDir is a central directory, such as a shared mount, that conf
+// files are also backed up to in addition to their local backup. When empty
+// conf files are only backed up next to themselves.
+var confBackupDir = ""
+
+func SetConfBackupDir(dir string) {
+	confBackupDir = dir
+}
+
+func ResetConfBackupDir() {
+	confBackupDir = ""
+}
+
+// backupOption returns the option sent to the agents, naming the central
+// backup directory and the backup policy when they are configured.
+func backupOption(opt *idl.UpdateFileConfOptions) *idl.UpdateFileConfOptions {
+	if confBackupDir == "" && backupPolicy == BackupPolicyVersion {
+		return opt
+	}
+
+	backedUp := proto.Clone(opt).(*idl.UpdateFileConfOptions)
+	backedUp.BackupDir = confBackupDir
+	if backupPolicy != BackupPolicyVersion {
+		backedUp.BackupPolicy = string(backupPolicy)
+	}
+	return backedUp
+}
+
+// confBackupSink returns the sink backing up the file edited by opts, which
+// is also backed up to the central directory any of them name.
+func confBackupSink(opts []*idl.UpdateFileConfOptions) BackupSink {
+	for _, opt := range opts {
+		if opt.GetBackupDir() != "" {
+			return MultiBackupSink{backupSink, DirectoryBackupSink{Dir: opt.GetBackupDir()}}
+		}
+	}
+
+	return backupSink
+}
+
+// confBackupPolicy returns the policy for an existing backup of the file
+// edited by opts, which is the first one they name or else the configured
+// policy.
+func confBackupPolicy(opts []*idl.UpdateFileConfOptions) BackupPolicy {
+	for _, opt := range opts {
+		if opt.GetBackupPolicy() != "" {
+			return BackupPolicy(opt.GetBackupPolicy())
+		}
+	}
+
+	return backupPolicy
+}
+
+func SetBackupSink(sink BackupSink) {
+	backupSink = sink
+}
+
+func ResetBackupSink() {
+	backupSink = LocalBackupSink{}
+}
+
+// BackupPolicy is what to do when a backup already exists, such as from a
+// prior run, so that the original contents of a conf file are never lost by
+// overwriting its backup.
+type BackupPolicy string
+
+const (
+	// BackupPolicyFail refuses to edit the conf file.
+	BackupPolicyFail BackupPolicy = "fail"
+	// BackupPolicySkip keeps the existing backup and edits the conf file
+	// without writing a new one.
+	BackupPolicySkip BackupPolicy = "skip-backup"
+	// BackupPolicyVersion keeps the existing backup and writes the new one to
+	// the next free numbered path, such as postgresql.conf.bak.1.
+	BackupPolicyVersion BackupPolicy = "version"
+	// BackupPolicyOverwrite replaces the existing backup, losing its contents.
+	// It is only used when explicitly set.
+	BackupPolicyOverwrite BackupPolicy = "overwrite"
+)
+
+var ErrBackupExists = errors.New("backup already exists")
+
+func (p BackupPolicy) Validate() error {
+	switch p {
+	case BackupPolicyFail, BackupPolicySkip, BackupPolicyVersion, BackupPolicyOverwrite:
+		return nil
+	default:
+		return xerrors.Errorf("unknown backup policy %q, expected %q, %q, %q or %q", p, BackupPolicyFail, BackupPolicySkip, BackupPolicyVersion, BackupPolicyOverwrite)
+	}
+}
+
+var backupPolicy = BackupPolicyVersion
+
+func SetBackupPolicy(policy BackupPolicy) {
+	backupPolicy = policy
+}
+
+func ResetBackupPolicy() {
+	backupPolicy = BackupPolicyVersion
+}
+
+func writeBackup(backup string, contents []byte, perm os.FileMode, policy BackupPolicy) error {
+	exists, err := pathExists(backup)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		switch policy {
+		case BackupPolicyFail:
+			return xerrors.Errorf("%s: %w", backup, ErrBackupExists)
+		case BackupPolicySkip:
+			return nil
+		case BackupPolicyVersion:
+			backup, err = nextBackupVersion(backup)
+			if err != nil {
+				return err
+			}
+		case BackupPolicyOverwrite:
+		default:
+			return xerrors.Errorf("unknown backup policy %q", policy)
+		}
+	}
+
+	return utils.System.WriteFile(backup, contents, perm)
+}
+
+func nextBackupVersion(backup string) (string, error) {
+	for i := 1; ; i++ {
+		versioned := fmt.Sprintf("%s.%d", backup, i)
+		exists, err := pathExists(versioned)
+		if err != nil {
+			return "", err
+		}
+
+		if !exists {
+			return versioned, nil
+		}
+	}
+}
+
+func pathExists(path string) (bool, error) {
+	_, err := utils.System.Stat(path)
+	if err == nil {
+		return true, nil
+	}
+
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+
+	return false, err
+}
+
+func backupConfFile(path string, suffix string, sink BackupSink, policy BackupPolicy) error {
+	// the backup of a symlinked file is next to the file, where revert
+	// looks for it
+	resolved, err := resolveConfPath(path)
+	if err != nil {
+		return xerrors.Errorf("backup %s: %w", filepath.Base(path), err)
+	}
+	path = resolved
+
+	info, err := utils.System.Stat(path)
+	if err != nil {
+		return xerrors.Errorf("backup %s: %w", filepath.Base(path), err)
+	}
+
+	contents, err := utils.System.ReadFile(path)
+	if err != nil {
+		return xerrors.Errorf("backup %s: %w", filepath.Base(path), err)
+	}
+
+	if err := sink.Backup(path, suffix, contents, info.Mode().Perm(), policy); err != nil {
+		return xerrors.Errorf("backup %s: %w", filepath.Base(path), err)
+	}
+
+	return nil
+}
+
+// confBackupSuffix returns the suffix naming the backup of the file edited by
+// opts, which must agree on it.
+func confBackupSuffix(path string, opts []*idl.UpdateFileConfOptions) (string, error) {
+	suffix := ""
+	for _, opt := range opts {
+		if opt.GetBackupSuffix() == "" {
+			continue
+		}
+
+		if strings.ContainsRune(opt.GetBackupSuffix(), filepath.Separator) || strings.ContainsRune(opt.GetBackupSuffix(), '/') {
+			return "", xerrors.Errorf("backup %s%s: invalid backup suffix %q", filepath.Base(path), reasonSuffix(opt), opt.GetBackupSuffix())
+		}
+
+		if suffix != "" && suffix != opt.GetBackupSuffix() {
+			return "", xerrors.Errorf("backup %s: conflicting backup suffixes %q and %q", filepath.Base(path), suffix, opt.GetBackupSuffix())
+		}
+		suffix = opt.GetBackupSuffix()
+	}
+
+	if suffix == "" {
+		return BackupSuffix, nil
+	}
+
+	return suffix, nil
+}
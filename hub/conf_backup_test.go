@@ -0,0 +1,403 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/testutils"
+	"github.com/greenplum-db/gpupgrade/utils"
+)
+
+type failingBackupSink struct {
+	err error
+}
+
+func (f failingBackupSink) Backup(path string, suffix string, contents []byte, perm os.FileMode, policy hub.BackupPolicy) error {
+	return f.err
+}
+
+func TestBackupSink(t *testing.T) {
+	original := "port=5000\n"
+	edited := "port=6000\n"
+
+	opt := func(path string) []*idl.UpdateFileConfOptions {
+		return []*idl.UpdateFileConfOptions{{
+			Path:        path,
+			Pattern:     `(^port=)5000`,
+			Replacement: `\16000`,
+		}}
+	}
+
+	t.Run("writes a local backup by default", func(t *testing.T) {
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		path := filepath.Join(dir, "postgresql.conf")
+		testutils.MustWriteToFile(t, path, original)
+
+		err := hub.UpdateConfigurationFile(context.Background(), opt(path))
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		contents := testutils.MustReadFile(t, path)
+		if contents != edited {
+			t.Errorf("got %q want %q", contents, edited)
+		}
+
+		backup := testutils.MustReadFile(t, path+hub.BackupSuffix)
+		if backup != original {
+			t.Errorf("got backup %q want %q", backup, original)
+		}
+	})
+
+	t.Run("names the backup with the suffix of the options", func(t *testing.T) {
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		path := filepath.Join(dir, "postgresql.conf")
+		testutils.MustWriteToFile(t, path, original)
+
+		opts := opt(path)
+		opts[0].BackupSuffix = ".gpupgrade"
+
+		err := hub.UpdateConfigurationFile(context.Background(), opts)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		backup := testutils.MustReadFile(t, path+".gpupgrade")
+		if backup != original {
+			t.Errorf("got backup %q want %q", backup, original)
+		}
+
+		testutils.PathMustNotExist(t, path+hub.BackupSuffix)
+	})
+
+	t.Run("errors when the options of a file name different suffixes", func(t *testing.T) {
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		path := filepath.Join(dir, "postgresql.conf")
+		testutils.MustWriteToFile(t, path, original)
+
+		opts := []*idl.UpdateFileConfOptions{
+			{Path: path, Pattern: `(^port=)5000`, Replacement: `\16000`, BackupSuffix: ".orig"},
+			{Path: path, Pattern: `(^port=)6000`, Replacement: `\17000`, BackupSuffix: ".gpupgrade"},
+		}
+
+		err := hub.UpdateConfigurationFile(context.Background(), opts)
+		expected := `backup postgresql.conf: conflicting backup suffixes ".orig" and ".gpupgrade"`
+		if err == nil || err.Error() != expected {
+			t.Errorf("got error %v want %q", err, expected)
+		}
+
+		contents := testutils.MustReadFile(t, path)
+		if contents != original {
+			t.Errorf("got %q want %q", contents, original)
+		}
+	})
+
+	t.Run("writes the backup to a central directory instead of locally", func(t *testing.T) {
+		utils.System.Hostname = func() (string, error) {
+			return "sdw1", nil
+		}
+		defer utils.ResetSystemFunctions()
+
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		centralDir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, centralDir)
+
+		hub.SetBackupSink(hub.DirectoryBackupSink{Dir: centralDir})
+		defer hub.ResetBackupSink()
+
+		path := filepath.Join(dir, "postgresql.conf")
+		testutils.MustWriteToFile(t, path, original)
+
+		err := hub.UpdateConfigurationFile(context.Background(), opt(path))
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		contents := testutils.MustReadFile(t, path)
+		if contents != edited {
+			t.Errorf("got %q want %q", contents, edited)
+		}
+
+		backup := testutils.MustReadFile(t, filepath.Join(centralDir, "sdw1", path+hub.BackupSuffix))
+		if backup != original {
+			t.Errorf("got backup %q want %q", backup, original)
+		}
+
+		testutils.PathMustNotExist(t, path+hub.BackupSuffix)
+	})
+
+	t.Run("writes the backup to every sink", func(t *testing.T) {
+		utils.System.Hostname = func() (string, error) {
+			return "sdw1", nil
+		}
+		defer utils.ResetSystemFunctions()
+
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		centralDir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, centralDir)
+
+		hub.SetBackupSink(hub.MultiBackupSink{hub.LocalBackupSink{}, hub.DirectoryBackupSink{Dir: centralDir}})
+		defer hub.ResetBackupSink()
+
+		path := filepath.Join(dir, "postgresql.conf")
+		testutils.MustWriteToFile(t, path, original)
+
+		err := hub.UpdateConfigurationFile(context.Background(), opt(path))
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		for _, backupPath := range []string{path + hub.BackupSuffix, filepath.Join(centralDir, "sdw1", path+hub.BackupSuffix)} {
+			backup := testutils.MustReadFile(t, backupPath)
+			if backup != original {
+				t.Errorf("got backup %q want %q", backup, original)
+			}
+		}
+	})
+
+	t.Run("also writes the backup to the central directory of the options", func(t *testing.T) {
+		utils.System.Hostname = func() (string, error) {
+			return "sdw1", nil
+		}
+		defer utils.ResetSystemFunctions()
+
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		centralDir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, centralDir)
+
+		hub.SetConfBackupDir(centralDir)
+		defer hub.ResetConfBackupDir()
+
+		path := filepath.Join(dir, "postgresql.conf")
+		testutils.MustWriteToFile(t, path, original)
+
+		// the agents only know of the directory from the options
+		opts := hub.ConfPlan{{Hostname: "sdw1", Option: opt(path)[0]}}.Options()
+		hub.ResetConfBackupDir()
+
+		err := hub.UpdateConfigurationFile(context.Background(), opts)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		for _, backupPath := range []string{path + hub.BackupSuffix, filepath.Join(centralDir, "sdw1", path+hub.BackupSuffix)} {
+			backup := testutils.MustReadFile(t, backupPath)
+			if backup != original {
+				t.Errorf("got backup %q want %q", backup, original)
+			}
+		}
+	})
+
+	t.Run("does not edit the file when the backup fails", func(t *testing.T) {
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		expected := errors.New("permission denied")
+		hub.SetBackupSink(failingBackupSink{err: expected})
+		defer hub.ResetBackupSink()
+
+		path := filepath.Join(dir, "postgresql.conf")
+		testutils.MustWriteToFile(t, path, original)
+
+		err := hub.UpdateConfigurationFile(context.Background(), opt(path))
+		if !errors.Is(err, expected) {
+			t.Errorf("got error %#v want %#v", err, expected)
+		}
+
+		contents := testutils.MustReadFile(t, path)
+		if contents != original {
+			t.Errorf("got %q want %q", contents, original)
+		}
+	})
+}
+
+func TestBackupPolicy(t *testing.T) {
+	prior := "port=4000\n"
+	original := "port=5000\n"
+	edited := "port=6000\n"
+
+	opt := func(path string) []*idl.UpdateFileConfOptions {
+		return []*idl.UpdateFileConfOptions{{
+			Path:        path,
+			Pattern:     `(^port=)5000`,
+			Replacement: `\16000`,
+		}}
+	}
+
+	setup := func(t *testing.T) (string, func()) {
+		dir := testutils.GetTempDir(t, "")
+
+		path := filepath.Join(dir, "postgresql.conf")
+		testutils.MustWriteToFile(t, path, original)
+		testutils.MustWriteToFile(t, path+hub.BackupSuffix, prior)
+
+		return path, func() { testutils.MustRemoveAll(t, dir) }
+	}
+
+	t.Run("versions the backup by default", func(t *testing.T) {
+		path, cleanup := setup(t)
+		defer cleanup()
+
+		testutils.MustWriteToFile(t, path+hub.BackupSuffix+".1", prior)
+
+		err := hub.UpdateConfigurationFile(context.Background(), opt(path))
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		contents := testutils.MustReadFile(t, path)
+		if contents != edited {
+			t.Errorf("got %q want %q", contents, edited)
+		}
+
+		for backupPath, expected := range map[string]string{
+			path + hub.BackupSuffix:        prior,
+			path + hub.BackupSuffix + ".1": prior,
+			path + hub.BackupSuffix + ".2": original,
+		} {
+			backup := testutils.MustReadFile(t, backupPath)
+			if backup != expected {
+				t.Errorf("got backup %q want %q", backup, expected)
+			}
+		}
+	})
+
+	t.Run("keeps the existing backup when skipping", func(t *testing.T) {
+		hub.SetBackupPolicy(hub.BackupPolicySkip)
+		defer hub.ResetBackupPolicy()
+
+		path, cleanup := setup(t)
+		defer cleanup()
+
+		err := hub.UpdateConfigurationFile(context.Background(), opt(path))
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		contents := testutils.MustReadFile(t, path)
+		if contents != edited {
+			t.Errorf("got %q want %q", contents, edited)
+		}
+
+		backup := testutils.MustReadFile(t, path+hub.BackupSuffix)
+		if backup != prior {
+			t.Errorf("got backup %q want %q", backup, prior)
+		}
+
+		testutils.PathMustNotExist(t, path+hub.BackupSuffix+".1")
+	})
+
+	t.Run("overwrites the existing backup only when told to", func(t *testing.T) {
+		hub.SetBackupPolicy(hub.BackupPolicyOverwrite)
+		defer hub.ResetBackupPolicy()
+
+		path, cleanup := setup(t)
+		defer cleanup()
+
+		err := hub.UpdateConfigurationFile(context.Background(), opt(path))
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		backup := testutils.MustReadFile(t, path+hub.BackupSuffix)
+		if backup != original {
+			t.Errorf("got backup %q want %q", backup, original)
+		}
+
+		testutils.PathMustNotExist(t, path+hub.BackupSuffix+".1")
+	})
+
+	t.Run("uses the policy of the options", func(t *testing.T) {
+		hub.SetBackupPolicy(hub.BackupPolicyFail)
+		defer hub.ResetBackupPolicy()
+
+		path, cleanup := setup(t)
+		defer cleanup()
+
+		// the agents only know of the policy from the options
+		opts := hub.ConfPlan{{Hostname: "sdw1", Option: opt(path)[0]}}.Options()
+		hub.ResetBackupPolicy()
+
+		err := hub.UpdateConfigurationFile(context.Background(), opts)
+		if !errors.Is(err, hub.ErrBackupExists) {
+			t.Errorf("got error %#v want %#v", err, hub.ErrBackupExists)
+		}
+
+		contents := testutils.MustReadFile(t, path)
+		if contents != original {
+			t.Errorf("got %q want %q", contents, original)
+		}
+	})
+
+	t.Run("keeps the backup of a file already at its target when rerun", func(t *testing.T) {
+		hub.SetBackupPolicy(hub.BackupPolicyFail)
+		defer hub.ResetBackupPolicy()
+
+		path, cleanup := setup(t)
+		defer cleanup()
+
+		testutils.MustWriteToFile(t, path, edited)
+
+		opts := opt(path)
+		opts[0].Guc = "port"
+		opts[0].ExpectedValue = "6000"
+
+		err := hub.UpdateConfigurationFile(context.Background(), opts)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		contents := testutils.MustReadFile(t, path)
+		if contents != edited {
+			t.Errorf("got %q want %q", contents, edited)
+		}
+
+		backup := testutils.MustReadFile(t, path+hub.BackupSuffix)
+		if backup != prior {
+			t.Errorf("got backup %q want %q", backup, prior)
+		}
+	})
+
+	t.Run("does not edit the file when failing", func(t *testing.T) {
+		hub.SetBackupPolicy(hub.BackupPolicyFail)
+		defer hub.ResetBackupPolicy()
+
+		path, cleanup := setup(t)
+		defer cleanup()
+
+		err := hub.UpdateConfigurationFile(context.Background(), opt(path))
+		if !errors.Is(err, hub.ErrBackupExists) {
+			t.Errorf("got error %#v want %#v", err, hub.ErrBackupExists)
+		}
+
+		contents := testutils.MustReadFile(t, path)
+		if contents != original {
+			t.Errorf("got %q want %q", contents, original)
+		}
+
+		backup := testutils.MustReadFile(t, path+hub.BackupSuffix)
+		if backup != prior {
+			t.Errorf("got backup %q want %q", backup, prior)
+		}
+	})
+}
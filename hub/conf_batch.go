@@ -0,0 +1,82 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"context"
+
+	"github.com/blang/semver/v4"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/idl"
+)
+
+// ConfBatch accumulates the conf edits of several updates so that each host
+// is sent all of its edits in one UpdateConfiguration request, rather than a
+// request for each update. This saves round trips on large clusters and has
+// the agent edit the files of its host as a whole. The edits of a host are
+// sent in the order their updates were added.
+type ConfBatch struct {
+	sources []func(hostname string) (ConfPlan, error)
+}
+
+func NewConfBatch() *ConfBatch {
+	return &ConfBatch{}
+}
+
+// AddPostgresqlConf adds the edits of UpdatePostgresqlConfOnSegments.
+func (b *ConfBatch) AddPostgresqlConf(intermediate *greenplum.Cluster, target *greenplum.Cluster) {
+	b.add(func(hostname string) (ConfPlan, error) {
+		return postgresqlConfEdits(hostname, intermediate, target), nil
+	})
+}
+
+// AddRecoveryConf adds the edits of UpdateRecoveryConfOnSegments.
+func (b *ConfBatch) AddRecoveryConf(version semver.Version, intermediate *greenplum.Cluster, target *greenplum.Cluster) {
+	b.add(func(hostname string) (ConfPlan, error) {
+		return recoveryConfEdits(hostname, version, intermediate, target)
+	})
+}
+
+func (b *ConfBatch) add(hostEdits func(hostname string) (ConfPlan, error)) {
+	b.sources = append(b.sources, hostEdits)
+}
+
+// hostEdits returns the edits of every update added for the host.
+func (b *ConfBatch) hostEdits(hostname string) (ConfPlan, error) {
+	var edits ConfPlan
+	for _, source := range b.sources {
+		plan, err := source(hostname)
+		if err != nil {
+			return nil, err
+		}
+
+		edits = append(edits, plan...)
+	}
+
+	return edits, nil
+}
+
+// Send sends each host the edits of its conf files in a single request.
+// Hosts without edits are not sent a request.
+func (b *ConfBatch) Send(ctx context.Context, agentConns []*idl.Connection) error {
+	return b.send(ctx, agentConns, nil)
+}
+
+func (b *ConfBatch) send(ctx context.Context, agentConns []*idl.Connection, changes *confChanges) error {
+	return updateConfOnHosts(ctx, agentConns, changes, b.hostEdits)
+}
+
+// confBatchSegments has UpdateConfFiles send the postgresql.conf and
+// primary_conninfo edits of the segments in one request to each host, in the
+// phase of the postgresql.conf edits. The hub sets it from its configuration.
+var confBatchSegments = false
+
+func SetConfBatchSegments(batch bool) {
+	confBatchSegments = batch
+}
+
+func ResetConfBatchSegments() {
+	confBatchSegments = false
+}
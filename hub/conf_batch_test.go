@@ -0,0 +1,129 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/blang/semver/v4"
+
+	"github.com/greenplum-db/gpupgrade/config"
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/step"
+	"github.com/greenplum-db/gpupgrade/testutils"
+	"github.com/greenplum-db/gpupgrade/testutils/mock_agent"
+)
+
+func TestConfBatch(t *testing.T) {
+	stateDir := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, stateDir)
+
+	resetEnv := testutils.SetEnv(t, "GPUPGRADE_HOME", stateDir)
+	defer resetEnv()
+
+	coordinatorDir := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, coordinatorDir)
+
+	intermediate := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: coordinatorDir, Port: 50432, Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg.HqtFHX54y0o.1", Port: 50434, Role: greenplum.PrimaryRole},
+		{DbID: 3, ContentID: 0, Hostname: "sdw2", DataDir: "/data/dbfast_mirror1/seg.HqtFHX54y0o.1", Port: 50435, Role: greenplum.MirrorRole},
+		{DbID: 4, ContentID: 1, Hostname: "sdw2", DataDir: "/data/dbfast2/seg.HqtFHX54y0o.2", Port: 50436, Role: greenplum.PrimaryRole},
+		{DbID: 5, ContentID: 1, Hostname: "sdw1", DataDir: "/data/dbfast_mirror2/seg.HqtFHX54y0o.2", Port: 50437, Role: greenplum.MirrorRole},
+	})
+	target := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: coordinatorDir, Port: 15432, Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg.HqtFHX54y0o.1", Port: 25433, Role: greenplum.PrimaryRole},
+		{DbID: 3, ContentID: 0, Hostname: "sdw2", DataDir: "/data/dbfast_mirror1/seg.HqtFHX54y0o.1", Port: 25434, Role: greenplum.MirrorRole},
+		{DbID: 4, ContentID: 1, Hostname: "sdw2", DataDir: "/data/dbfast2/seg.HqtFHX54y0o.2", Port: 25435, Role: greenplum.PrimaryRole},
+		{DbID: 5, ContentID: 1, Hostname: "sdw1", DataDir: "/data/dbfast_mirror2/seg.HqtFHX54y0o.2", Port: 25436, Role: greenplum.MirrorRole},
+	})
+
+	version := semver.MustParse("7.0.0")
+	hosts := []string{"sdw1", "sdw2"}
+
+	// reasons returns the reasons of the options of each request received by
+	// the agent on the host.
+	reasons := func(agents *mock_agent.ConfAgents, hostname string) [][]string {
+		var requests [][]string
+		for _, req := range agents.Requests(hostname) {
+			var reasons []string
+			for _, opt := range req.GetOptions() {
+				reasons = append(reasons, opt.GetReason())
+			}
+			requests = append(requests, reasons)
+		}
+
+		return requests
+	}
+
+	t.Run("sends each host its edits of every update in one request", func(t *testing.T) {
+		agents := mock_agent.NewConfAgents(hosts...)
+		defer agents.Stop()
+
+		batch := hub.NewConfBatch()
+		batch.AddPostgresqlConf(intermediate, target)
+		batch.AddRecoveryConf(version, intermediate, target)
+
+		err := batch.Send(context.Background(), agents.Conns())
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		expected := [][]string{{hub.ReasonPortRewrite, hub.ReasonPortRewrite, hub.ReasonConninfoRewrite}}
+		for _, hostname := range hosts {
+			if actual := reasons(agents, hostname); !reflect.DeepEqual(actual, expected) {
+				t.Errorf("got requests with reasons %q for host %s want %q", actual, hostname, expected)
+			}
+		}
+	})
+
+	t.Run("sends hosts without edits no request", func(t *testing.T) {
+		agents := mock_agent.NewConfAgents(hosts...)
+		defer agents.Stop()
+
+		err := hub.NewConfBatch().Send(context.Background(), agents.Conns())
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		if len(agents.Hosts()) != 0 {
+			t.Errorf("got requests to hosts %q want none", agents.Hosts())
+		}
+	})
+
+	for _, batch := range []bool{false, true} {
+		expected := [][]string{{hub.ReasonPortRewrite, hub.ReasonPortRewrite}, {hub.ReasonConninfoRewrite}}
+		name := "sends the segment phases in separate requests by default"
+		if batch {
+			expected = [][]string{{hub.ReasonPortRewrite, hub.ReasonPortRewrite, hub.ReasonConninfoRewrite}}
+			name = "sends the segment phases in one request when batched"
+		}
+
+		t.Run(name, func(t *testing.T) {
+			testutils.MustWriteToFile(t, filepath.Join(coordinatorDir, "postgresql.conf"), "port=50432\n")
+
+			hub.SetConfBatchSegments(batch)
+			defer hub.ResetConfBatchSegments()
+
+			agents := mock_agent.NewConfAgents(hosts...)
+			defer agents.Stop()
+
+			err := hub.UpdateConfFiles(context.Background(), agents.Conns(), nil, step.DevNullStream, config.StateVersion, hub.ConfUpdateOptions{}, version, intermediate, target)
+			if err != nil {
+				t.Fatalf("unexpected error %+v", err)
+			}
+
+			for _, hostname := range hosts {
+				if actual := reasons(agents, hostname); !reflect.DeepEqual(actual, expected) {
+					t.Errorf("got requests with reasons %q for host %s want %q", actual, hostname, expected)
+				}
+			}
+		})
+	}
+}
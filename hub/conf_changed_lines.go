@@ -0,0 +1,96 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/greenplum-db/gpupgrade/idl"
+)
+
+// changedConfLines returns the lines an option changed in the contents of the
+// file at path. A removed line is paired with the line added in its place,
+// and a change to only the line ending is not reported.
+func changedConfLines(option int32, path string, before []byte, after []byte) []*idl.ChangedLine {
+	var lines []*idl.ChangedLine
+	var removed, added []string
+
+	flush := func() {
+		for i := 0; i < max(len(removed), len(added)); i++ {
+			line := &idl.ChangedLine{Option: option, Path: path}
+			if i < len(removed) {
+				line.OldLine = removed[i]
+			}
+			if i < len(added) {
+				line.NewLine = added[i]
+			}
+
+			if i >= len(removed) || i >= len(added) || line.OldLine != line.NewLine {
+				lines = append(lines, line)
+			}
+		}
+
+		removed, added = nil, nil
+	}
+
+	for _, op := range diffConfLines(splitConfLines(before), splitConfLines(after)) {
+		line := strings.TrimRight(op.line, "\r\n")
+		switch op.kind {
+		case '-':
+			removed = append(removed, line)
+		case '+':
+			added = append(added, line)
+		default:
+			flush()
+		}
+	}
+	flush()
+
+	return lines
+}
+
+func (c *confChanges) addLines(hostname string, lines []*idl.ChangedLine) {
+	if len(lines) == 0 {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.lines == nil {
+		c.lines = make(map[string][]*idl.ChangedLine)
+	}
+	c.lines[hostname] = append(c.lines[hostname], lines...)
+}
+
+// writeChangedLines writes every line changed by the conf update sorted by
+// host and path, recording what was changed even when the update failed.
+func (c *confChanges) writeChangedLines(w io.Writer) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	var hosts []string
+	count := 0
+	for host, lines := range c.lines {
+		hosts = append(hosts, host)
+		count += len(lines)
+	}
+	sort.Strings(hosts)
+
+	if count == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "changed %d conf lines:\n", count)
+	for _, host := range hosts {
+		lines := c.lines[host]
+		sort.SliceStable(lines, func(i, j int) bool { return lines[i].GetPath() < lines[j].GetPath() })
+
+		for _, line := range lines {
+			fmt.Fprintf(w, "  %s:%s: %q -> %q\n", host, line.GetPath(), line.GetOldLine(), line.GetNewLine())
+		}
+	}
+}
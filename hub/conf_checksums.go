@@ -0,0 +1,133 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"regexp"
+	"sort"
+
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
+	"github.com/greenplum-db/gpupgrade/utils/errorlist"
+)
+
+// readBackConfFile reads the file back after the update and returns its
+// digest and the number of options whose edit it has. The read back file
+// having the contents written has every edit. Otherwise, such as when a
+// network filesystem returns stale contents, only the options that would
+// not change it again are counted.
+func readBackConfFile(path string, written []byte, opts []*idl.UpdateFileConfOptions) (*idl.ConfFileChecksum, error) {
+	contents, err := utils.System.ReadFile(path)
+	if err != nil {
+		return nil, xerrors.Errorf("read back %s: %w", path, err)
+	}
+
+	sum := sha256.Sum256(contents)
+	checksum := &idl.ConfFileChecksum{Path: path, Sha256: hex.EncodeToString(sum[:])}
+
+	if bytes.Equal(contents, written) {
+		checksum.MatchedOptions = int32(len(opts))
+		return checksum, nil
+	}
+
+	for _, opt := range opts {
+		if confEditInEffect(path, contents, opt) {
+			checksum.MatchedOptions++
+		}
+	}
+
+	return checksum, nil
+}
+
+// confEditInEffect is whether making the edit of the option again would leave
+// contents unchanged.
+func confEditInEffect(path string, contents []byte, opt *idl.UpdateFileConfOptions) bool {
+	met, err := checkCurrentValue(path, contents, opt)
+	if err != nil {
+		return false
+	}
+
+	if !met {
+		return true
+	}
+
+	pattern, err := regexp.Compile(opt.GetPattern())
+	if err != nil {
+		return false
+	}
+	pattern.Longest()
+
+	if lastMatchOnly(opt) {
+		if done, err := hasExpectedValue(contents, opt); err == nil && done {
+			return true
+		}
+	}
+
+	rewritten, matches := rewriteConfLines(contents, pattern, goReplacement(opt.GetReplacement()), lastMatchOnly(opt))
+	if !bytes.Equal(rewritten, contents) {
+		return false
+	}
+
+	if matches == 0 && opt.GetAppendIfMissing() != "" {
+		_, appended, err := appendMissingSetting(contents, opt)
+		return err == nil && !appended
+	}
+
+	return true
+}
+
+// checkConfChecksums errors for each file of opts whose checksum on the host
+// does not have the edits of all of its options in effect. An agent older
+// than the checksums returns none, and its files are not checked.
+func checkConfChecksums(hostname string, opts []*idl.UpdateFileConfOptions, checksums []*idl.ConfFileChecksum) error {
+	if len(checksums) == 0 {
+		log.Printf("not checking the conf files read back on host %s as its agent returned no checksums", hostname)
+		return nil
+	}
+
+	sent := make(map[string]int)
+	optional := make(map[string][]*idl.UpdateFileConfOptions)
+	for _, opt := range opts {
+		sent[opt.GetPath()]++
+		optional[opt.GetPath()] = append(optional[opt.GetPath()], opt)
+	}
+
+	received := make(map[string]*idl.ConfFileChecksum)
+	for _, checksum := range checksums {
+		received[checksum.GetPath()] = checksum
+	}
+
+	var paths []string
+	for path := range sent {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var err error
+	for _, path := range paths {
+		checksum, ok := received[path]
+		if !ok && absentConfFileOptional(optional[path]) {
+			// an optional file that is absent is not read back
+			continue
+		}
+
+		if !ok {
+			err = errorlist.Append(err, xerrors.Errorf("%s on host %s was not read back after the update", path, hostname))
+			continue
+		}
+
+		if int(checksum.GetMatchedOptions()) != sent[path] {
+			err = errorlist.Append(err, xerrors.Errorf("%s on host %s has %d of its %d edits in effect after the update (sha256 %s)",
+				path, hostname, checksum.GetMatchedOptions(), sent[path], checksum.GetSha256()))
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,48 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"sort"
+
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/utils/errorlist"
+)
+
+// CheckIntermediateContentIDs errors when the intermediate cluster is missing
+// a content ID of the target, since the conf edits of a target segment
+// rewrite the port of the intermediate primary of its content, and those of
+// the standby the port of the intermediate standby. Without this check
+// clusters out of sync would rewrite a port that was never set. Every missing
+// content ID is returned naming the target segment using it.
+func CheckIntermediateContentIDs(intermediate *greenplum.Cluster, target *greenplum.Cluster) error {
+	segments := target.SelectSegments(func(*greenplum.SegConfig) bool { return true })
+	sort.Slice(segments, func(i, j int) bool { return segments[i].DbID < segments[j].DbID })
+
+	var err error
+	for _, seg := range segments {
+		if seg.IsStandby() {
+			if !intermediate.HasStandby() {
+				err = errorlist.Append(err, xerrors.Errorf("the intermediate cluster has no standby for the target standby (dbid %d) on host %s", seg.DbID, seg.Hostname))
+			}
+			continue
+		}
+
+		role := "primary"
+		switch {
+		case seg.IsCoordinator():
+			role = "coordinator"
+		case seg.IsMirror():
+			role = "mirror"
+		}
+
+		if _, ok := intermediate.Primaries[seg.ContentID]; !ok {
+			err = errorlist.Append(err, xerrors.Errorf("the intermediate cluster has no primary with content %d for the target %s (dbid %d) on host %s", seg.ContentID, role, seg.DbID, seg.Hostname))
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/blang/semver/v4"
+
+	"github.com/greenplum-db/gpupgrade/config"
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/step"
+	"github.com/greenplum-db/gpupgrade/utils/errorlist"
+)
+
+func TestCheckIntermediateContentIDs(t *testing.T) {
+	target := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: "/data/qddir/seg-1", Port: 15432, Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: -1, Hostname: "standby", DataDir: "/data/standby", Port: 16432, Role: greenplum.MirrorRole},
+		{DbID: 3, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg1", Port: 25433, Role: greenplum.PrimaryRole},
+		{DbID: 4, ContentID: 0, Hostname: "sdw2", DataDir: "/data/dbfast_mirror1/seg1", Port: 25434, Role: greenplum.MirrorRole},
+		{DbID: 5, ContentID: 1, Hostname: "sdw2", DataDir: "/data/dbfast2/seg2", Port: 25435, Role: greenplum.PrimaryRole},
+		{DbID: 6, ContentID: 1, Hostname: "sdw1", DataDir: "/data/dbfast_mirror2/seg2", Port: 25436, Role: greenplum.MirrorRole},
+	})
+
+	t.Run("succeeds when the intermediate cluster has every content of the target", func(t *testing.T) {
+		intermediate := hub.MustCreateCluster(t, greenplum.SegConfigs{
+			{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: "/data/qddir/seg.HqtFHX54y0o.-1", Port: 50432, Role: greenplum.PrimaryRole},
+			{DbID: 2, ContentID: -1, Hostname: "standby", DataDir: "/data/standby.HqtFHX54y0o", Port: 50433, Role: greenplum.MirrorRole},
+			{DbID: 3, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg.HqtFHX54y0o.1", Port: 50434, Role: greenplum.PrimaryRole},
+			{DbID: 5, ContentID: 1, Hostname: "sdw2", DataDir: "/data/dbfast2/seg.HqtFHX54y0o.2", Port: 50436, Role: greenplum.PrimaryRole},
+		})
+
+		if err := hub.CheckIntermediateContentIDs(intermediate, target); err != nil {
+			t.Errorf("unexpected error %+v", err)
+		}
+	})
+
+	intermediate := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: "/data/qddir/seg.HqtFHX54y0o.-1", Port: 50432, Role: greenplum.PrimaryRole},
+		{DbID: 3, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg.HqtFHX54y0o.1", Port: 50434, Role: greenplum.PrimaryRole},
+		{DbID: 4, ContentID: 0, Hostname: "sdw2", DataDir: "/data/dbfast_mirror1/seg.HqtFHX54y0o.1", Port: 50435, Role: greenplum.MirrorRole},
+	})
+
+	t.Run("errors with each target segment whose content is missing", func(t *testing.T) {
+		err := hub.CheckIntermediateContentIDs(intermediate, target)
+
+		var errs errorlist.Errors
+		if !errors.As(err, &errs) {
+			t.Fatalf("got error %#v want an errorlist", err)
+		}
+
+		expected := []string{
+			"the intermediate cluster has no standby for the target standby (dbid 2) on host standby",
+			"the intermediate cluster has no primary with content 1 for the target primary (dbid 5) on host sdw2",
+			"the intermediate cluster has no primary with content 1 for the target mirror (dbid 6) on host sdw1",
+		}
+		if len(errs) != len(expected) {
+			t.Fatalf("got %d errors want %d: %v", len(errs), len(expected), errs)
+		}
+
+		for i, err := range errs {
+			if err.Error() != expected[i] {
+				t.Errorf("got error %q want %q", err.Error(), expected[i])
+			}
+		}
+	})
+
+	t.Run("UpdateConfFiles errors before editing any host", func(t *testing.T) {
+		err := hub.UpdateConfFiles(context.Background(), nil, nil, step.DevNullStream, config.StateVersion, hub.ConfUpdateOptions{}, semver.MustParse("7.0.0"), intermediate, target)
+
+		var errs errorlist.Errors
+		if !errors.As(err, &errs) || len(errs) != 3 {
+			t.Errorf("got error %v want the 3 missing contents", err)
+		}
+	})
+}
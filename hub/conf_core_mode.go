@@ -0,0 +1,52 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+// coreConfMode has UpdateConfFiles make only the port rewrites gpupgrade has
+// always made, for the most cautious upgrades. These are:
+//
+//   - the port in the postgresql.conf of the coordinator, standby, primaries,
+//     and mirrors
+//   - the port of the primary_conninfo in the recovery.conf or
+//     postgresql.auto.conf of the standby and mirrors
+//
+// Everything else is skipped regardless of its configuration: the
+// gpperfmon.conf log_location, the coordinator role GUC renames, the
+// pg_hba.conf addresses of moved hosts, operator provided edits, and the
+// target port map. Files keep their trailing
+// newlines as they are. Exclusions still apply since they only narrow the
+// update. The gp_dbid rewrite of the mirrors' internal.auto.conf is made when
+// the mirrors are upgraded rather than by UpdateConfFiles and is unaffected.
+var coreConfMode = false
+
+func SetCoreConfMode(core bool) {
+	coreConfMode = core
+}
+
+func ResetCoreConfMode() {
+	coreConfMode = false
+}
+
+// coreConfReasons are the reasons of the edits made in core mode.
+var coreConfReasons = map[string]bool{
+	ReasonPortRewrite:     true,
+	ReasonConninfoRewrite: true,
+}
+
+// coreEdits returns only the core edits in core mode, and all of them
+// otherwise.
+func coreEdits(edits ConfPlan) ConfPlan {
+	if !coreConfMode {
+		return edits
+	}
+
+	var core ConfPlan
+	for _, edit := range edits {
+		if coreConfReasons[edit.Option.GetReason()] {
+			core = append(core, edit)
+		}
+	}
+
+	return core
+}
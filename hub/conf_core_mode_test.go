@@ -0,0 +1,114 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/blang/semver/v4"
+
+	"github.com/greenplum-db/gpupgrade/config"
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/step"
+	"github.com/greenplum-db/gpupgrade/testutils"
+)
+
+func TestCoreConfMode(t *testing.T) {
+	hub.SetCoreConfMode(true)
+	defer hub.ResetCoreConfMode()
+
+	hub.SetCustomConfEdits([]hub.CustomConfEdit{{File: "postgresql.conf", Pattern: `^shared_buffers = .*$`, Replacement: "shared_buffers = 1GB"}})
+	defer hub.ResetCustomConfEdits()
+
+	hub.SetTargetPortMap(hub.TargetPortMap{0: {Primary: 30000, Mirror: 30001}})
+	defer hub.ResetTargetPortMap()
+
+	t.Run("plans only the port rewrites", func(t *testing.T) {
+		intermediate := hub.MustCreateCluster(t, greenplum.SegConfigs{
+			{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: "/data/qddir/seg.HqtFHX54y0o.-1", Port: 50432, Role: greenplum.PrimaryRole},
+			{DbID: 2, ContentID: -1, Hostname: "standby", DataDir: "/data/standby.HqtFHX54y0o", Port: 50433, Role: greenplum.MirrorRole},
+			{DbID: 3, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg.HqtFHX54y0o.1", Port: 50434, Role: greenplum.PrimaryRole},
+			{DbID: 4, ContentID: 0, Hostname: "sdw2", DataDir: "/data/dbfast_mirror1/seg.HqtFHX54y0o.1", Port: 50435, Role: greenplum.MirrorRole},
+		})
+
+		target := hub.MustCreateCluster(t, greenplum.SegConfigs{
+			{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: "/data/qddir/seg-1", Port: 15432, Role: greenplum.PrimaryRole},
+			{DbID: 2, ContentID: -1, Hostname: "standby", DataDir: "/data/standby", Port: 16432, Role: greenplum.MirrorRole},
+			{DbID: 3, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg1", Port: 25433, Role: greenplum.PrimaryRole},
+			{DbID: 4, ContentID: 0, Hostname: "sdw2", DataDir: "/data/dbfast_mirror1/seg1", Port: 25434, Role: greenplum.MirrorRole},
+		})
+
+		plan, err := hub.PlanConfFiles(semver.MustParse("6.25.0"), intermediate, target)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		var edits [][]string
+		for _, edit := range plan {
+			edits = append(edits, []string{edit.Hostname, edit.Option.GetPath(), edit.NewValue, edit.Option.GetReason()})
+		}
+
+		for _, opt := range plan.Options() {
+			if !opt.GetPreserveTrailingNewline() {
+				t.Errorf("expected the edit of %s to preserve its trailing newline", opt.GetPath())
+			}
+		}
+
+		expected := [][]string{
+			{"coordinator", "/data/qddir/seg-1/postgresql.conf", "15432", hub.ReasonPortRewrite},
+			{"standby", "/data/standby/postgresql.conf", "16432", hub.ReasonPortRewrite},
+			{"standby", "/data/standby/recovery.conf", "port=15432", hub.ReasonConninfoRewrite},
+			{"sdw1", "/data/dbfast1/seg1/postgresql.conf", "25433", hub.ReasonPortRewrite},
+			{"sdw2", "/data/dbfast_mirror1/seg1/postgresql.conf", "25434", hub.ReasonPortRewrite},
+			{"sdw2", "/data/dbfast_mirror1/seg1/recovery.conf", "port=25433", hub.ReasonConninfoRewrite},
+		}
+		if !reflect.DeepEqual(edits, expected) {
+			t.Errorf("got %q want %q", edits, expected)
+		}
+	})
+
+	t.Run("skips the gpperfmon and coordinator role edits of the coordinator", func(t *testing.T) {
+		stateDir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, stateDir)
+
+		resetEnv := testutils.SetEnv(t, "GPUPGRADE_HOME", stateDir)
+		defer resetEnv()
+
+		coordinatorDir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, coordinatorDir)
+
+		postgresqlConf := filepath.Join(coordinatorDir, "postgresql.conf")
+		testutils.MustWriteToFile(t, postgresqlConf, "port=50432\ngp_session_role=dispatch")
+
+		gpperfmonConf := filepath.Join(coordinatorDir, "gpperfmon", "conf", "gpperfmon.conf")
+		testutils.MustCreateDir(t, filepath.Dir(gpperfmonConf))
+		testutils.MustWriteToFile(t, gpperfmonConf, "log_location = /old/gpperfmon/logs\n")
+
+		intermediate := hub.MustCreateCluster(t, greenplum.SegConfigs{
+			{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: coordinatorDir, Port: 50432, Role: greenplum.PrimaryRole},
+		})
+		target := hub.MustCreateCluster(t, greenplum.SegConfigs{
+			{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: coordinatorDir, Port: 15432, Role: greenplum.PrimaryRole},
+		})
+
+		err := hub.UpdateConfFiles(context.Background(), nil, nil, step.DevNullStream, config.StateVersion, hub.ConfUpdateOptions{}, semver.MustParse("6.25.0"), intermediate, target)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		for path, expected := range map[string]string{
+			postgresqlConf: "port=15432\ngp_session_role=dispatch",
+			gpperfmonConf:  "log_location = /old/gpperfmon/logs\n",
+		} {
+			contents := testutils.MustReadFile(t, path)
+			if contents != expected {
+				t.Errorf("got %q want %q", contents, expected)
+			}
+		}
+	})
+}
@@ -0,0 +1,245 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/blang/semver/v4"
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils/errorlist"
+)
+
+// confDiffContext is the number of unchanged lines shown around each change.
+const confDiffContext = 3
+
+// previewConfFiles computes the edits of the coordinator locally and those of
+// every other host through its agent, and writes the diffs to w sorted by
+// host. The edits of a host are sent in a single request in the order the
+// phases make them, so that a file edited by several phases is previewed
+// with all of its edits. Hosts failing to compute their edits are reported
+// together after the diffs of the rest. The coordinator is previewed unless
+// the update is limited to other hosts.
+func previewConfFiles(ctx context.Context, agentConns []*idl.Connection, w io.Writer, confHosts []string, version semver.Version, intermediate *greenplum.Cluster, target *greenplum.Cluster) error {
+	var mutex sync.Mutex
+	hostDiffs := make(map[string][]*idl.ConfFileDiff)
+
+	var previewErr error
+	if confHostIncluded(confHosts, target.CoordinatorHostname()) {
+		coordinator, err := coordinatorConfEdits(version, intermediate, target)
+		if err != nil {
+			return err
+		}
+
+		coordinator = append(coordinator, customConfEditsOnHost(target.CoordinatorHostname(), target, func(seg *greenplum.SegConfig) bool {
+			return seg.IsCoordinator()
+		})...)
+
+		var diffs []*idl.ConfFileDiff
+		diffs, _, previewErr = PreviewConfigurationFile(ctx, expectedValueOptions(coordinator, coordinator.Options()))
+		if previewErr != nil {
+			previewErr = xerrors.Errorf("preview conf files on host %s: %w", target.CoordinatorHostname(), previewErr)
+		}
+		hostDiffs[target.CoordinatorHostname()] = diffs
+	}
+
+	requests, err := AgentConfRequests(version, intermediate, target)
+	if err != nil {
+		return err
+	}
+
+	request := func(ctx context.Context, conn *idl.Connection) error {
+		req := &idl.UpdateConfigurationRequest{DryRun: true}
+		// operator provided edits are always made last
+		for _, phase := range []string{ConfPhaseStandby, ConfPhaseSegmentPostgresqlConf, ConfPhaseSegmentRecoveryConf, ConfPhaseSegmentPgHbaConf, ConfPhaseCustom} {
+			req.Options = append(req.Options, requests[conn.Hostname][phase].GetOptions()...)
+		}
+
+		if len(req.Options) == 0 {
+			return nil
+		}
+
+		// a preview writes no files so it is always safe to retry
+		var reply *idl.UpdateConfigurationReply
+		err := retryRPC(ctx, conn.Hostname, func() error {
+			return withConfRPCTimeout(ctx, conn.Hostname, "preview conf files", func(ctx context.Context) error {
+				var err error
+				reply, err = conn.AgentClient.UpdateConfiguration(ctx, req)
+				return err
+			})
+		})
+		if errors.Is(err, ErrConfRPCTimedOut) {
+			return err
+		}
+		if err != nil {
+			return xerrors.Errorf("preview conf files on host %s: %w", conn.Hostname, err)
+		}
+
+		mutex.Lock()
+		defer mutex.Unlock()
+		hostDiffs[conn.Hostname] = append(hostDiffs[conn.Hostname], reply.GetDiffs()...)
+		return nil
+	}
+
+	rpcErr := executeConfRPC(ctx, agentConns, request, nil, FailOnUnreachable).Err()
+
+	var hosts []string
+	files := 0
+	for host, diffs := range hostDiffs {
+		if len(diffs) > 0 {
+			hosts = append(hosts, host)
+			files += len(diffs)
+		}
+	}
+	sort.Strings(hosts)
+
+	for _, host := range hosts {
+		for _, diff := range hostDiffs[host] {
+			fmt.Fprintf(w, "--- %s:%s\n+++ %s:%s\n%s", host, diff.GetPath(), host, diff.GetPath(), diff.GetDiff())
+		}
+	}
+
+	fmt.Fprintf(w, "dry run: %d conf files would change on %d hosts\n", files, len(hosts))
+
+	return errorlist.Append(previewErr, rpcErr)
+}
+
+// unifiedConfDiff returns the hunks of a unified diff between the contents of
+// a conf file before and after its edits.
+func unifiedConfDiff(before []byte, after []byte) string {
+	ops := diffConfLines(splitConfLines(before), splitConfLines(after))
+
+	// the line numbers preceding each op
+	oldLines := make([]int, len(ops)+1)
+	newLines := make([]int, len(ops)+1)
+	for i, op := range ops {
+		oldLines[i+1], newLines[i+1] = oldLines[i], newLines[i]
+		if op.kind != '+' {
+			oldLines[i+1]++
+		}
+		if op.kind != '-' {
+			newLines[i+1]++
+		}
+	}
+
+	var diff strings.Builder
+	for i := 0; i < len(ops); i++ {
+		if ops[i].kind == ' ' {
+			continue
+		}
+
+		// extend the hunk over changes separated by at most twice the context
+		last := i
+		for j := i + 1; j < len(ops) && j <= last+2*confDiffContext; j++ {
+			if ops[j].kind != ' ' {
+				last = j
+			}
+		}
+
+		start := max(i-confDiffContext, 0)
+		end := min(last+1+confDiffContext, len(ops))
+
+		fmt.Fprintf(&diff, "@@ -%s +%s @@\n",
+			hunkRange(oldLines[start], oldLines[end]-oldLines[start]),
+			hunkRange(newLines[start], newLines[end]-newLines[start]))
+		for _, op := range ops[start:end] {
+			line := strings.TrimSuffix(op.line, "\n")
+			fmt.Fprintf(&diff, "%c%s\n", op.kind, strings.TrimSuffix(line, "\r"))
+			if !strings.HasSuffix(op.line, "\n") {
+				diff.WriteString("\\ No newline at end of file\n")
+			}
+		}
+
+		i = end - 1
+	}
+
+	return diff.String()
+}
+
+// hunkRange formats the start and length of a hunk, where an empty hunk
+// starts at the line preceding it.
+func hunkRange(preceding int, length int) string {
+	if length == 0 {
+		return fmt.Sprintf("%d,0", preceding)
+	}
+
+	return fmt.Sprintf("%d,%d", preceding+1, length)
+}
+
+type confDiffOp struct {
+	// kind is ' ' for an unchanged line, '-' for a removed one and '+' for
+	// an added one.
+	kind byte
+	line string
+}
+
+// splitConfLines splits contents into lines keeping their line endings, so
+// that a change to only the line ending or final newline is still a change.
+func splitConfLines(contents []byte) []string {
+	if len(contents) == 0 {
+		return nil
+	}
+
+	lines := strings.SplitAfter(string(contents), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
+
+// diffConfLines returns the ops turning a into b based on their longest
+// common subsequence. Conf files are small enough for the quadratic table.
+func diffConfLines(a []string, b []string) []confDiffOp {
+	common := make([][]int, len(a)+1)
+	for i := range common {
+		common[i] = make([]int, len(b)+1)
+	}
+
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				common[i][j] = common[i+1][j+1] + 1
+			} else {
+				common[i][j] = max(common[i+1][j], common[i][j+1])
+			}
+		}
+	}
+
+	var ops []confDiffOp
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, confDiffOp{' ', a[i]})
+			i++
+			j++
+		case common[i+1][j] >= common[i][j+1]:
+			ops = append(ops, confDiffOp{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, confDiffOp{'+', b[j]})
+			j++
+		}
+	}
+
+	for ; i < len(a); i++ {
+		ops = append(ops, confDiffOp{'-', a[i]})
+	}
+
+	for ; j < len(b); j++ {
+		ops = append(ops, confDiffOp{'+', b[j]})
+	}
+
+	return ops
+}
@@ -0,0 +1,161 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/blang/semver/v4"
+	"github.com/golang/mock/gomock"
+
+	"github.com/greenplum-db/gpupgrade/config"
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/idl/mock_idl"
+	"github.com/greenplum-db/gpupgrade/step"
+	"github.com/greenplum-db/gpupgrade/testutils"
+)
+
+func TestPreviewConfigurationFile(t *testing.T) {
+	t.Run("returns the diff of each file without writing or backing it up", func(t *testing.T) {
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		path := filepath.Join(dir, "postgresql.conf")
+		original := "listen_addresses='*'\nport=5000\nmax_connections=100\n"
+		testutils.MustWriteToFile(t, path, original)
+
+		diffs, _, err := hub.PreviewConfigurationFile(context.Background(), []*idl.UpdateFileConfOptions{{Path: path, Pattern: `^(port=)5000$`, Replacement: `\16000`}})
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		expected := "@@ -1,3 +1,3 @@\n listen_addresses='*'\n-port=5000\n+port=6000\n max_connections=100\n"
+		if len(diffs) != 1 || diffs[0].GetPath() != path || diffs[0].GetDiff() != expected {
+			t.Errorf("got diffs %v want a diff of %s of %q", diffs, path, expected)
+		}
+
+		contents := testutils.MustReadFile(t, path)
+		if contents != original {
+			t.Errorf("got %q want the file unchanged", contents)
+		}
+
+		testutils.PathMustNotExist(t, path+hub.BackupSuffix)
+	})
+
+	t.Run("shows a missing final newline being added", func(t *testing.T) {
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		path := filepath.Join(dir, "postgresql.conf")
+		testutils.MustWriteToFile(t, path, "port=5000")
+
+		diffs, _, err := hub.PreviewConfigurationFile(context.Background(), []*idl.UpdateFileConfOptions{{Path: path, Pattern: `^(port=)5000$`, Replacement: `\16000`}})
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		expected := "@@ -1,1 +1,1 @@\n-port=5000\n\\ No newline at end of file\n+port=6000\n"
+		if len(diffs) != 1 || diffs[0].GetDiff() != expected {
+			t.Errorf("got diffs %v want %q", diffs, expected)
+		}
+	})
+
+	t.Run("returns no diff for a file already at the target", func(t *testing.T) {
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		path := filepath.Join(dir, "postgresql.conf")
+		testutils.MustWriteToFile(t, path, "port=6000\n")
+
+		diffs, _, err := hub.PreviewConfigurationFile(context.Background(), []*idl.UpdateFileConfOptions{{Path: path, Pattern: `^(port=)5000$`, Replacement: `\16000`, Guc: "port", ExpectedValue: "6000"}})
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		if len(diffs) != 0 {
+			t.Errorf("got diffs %v want none", diffs)
+		}
+	})
+
+	t.Run("errors when a pattern matches no lines", func(t *testing.T) {
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		path := filepath.Join(dir, "recovery.conf")
+		testutils.MustWriteToFile(t, path, "primary_conninfo = 'host=sdw1 port=7000'\n")
+
+		_, _, err := hub.PreviewConfigurationFile(context.Background(), []*idl.UpdateFileConfOptions{{Path: path, Pattern: `(primary_conninfo .* port=)5000$`, Replacement: `\16000`}})
+		if err == nil || !strings.Contains(err.Error(), "matched no lines") {
+			t.Errorf("expected error %v to contain %q", err, "matched no lines")
+		}
+	})
+}
+
+func TestUpdateConfFilesDryRun(t *testing.T) {
+	stateDir := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, stateDir)
+
+	resetEnv := testutils.SetEnv(t, "GPUPGRADE_HOME", stateDir)
+	defer resetEnv()
+
+	coordinatorDir := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, coordinatorDir)
+
+	path := filepath.Join(coordinatorDir, "postgresql.conf")
+	testutils.MustWriteToFile(t, path, "port=50432\n")
+
+	intermediate := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: coordinatorDir, Port: 50432, Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg.HqtFHX54y0o.1", Port: 50434, Role: greenplum.PrimaryRole},
+	})
+	target := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: coordinatorDir, Port: 15432, Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg1", Port: 25433, Role: greenplum.PrimaryRole},
+	})
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	segmentDiff := "@@ -1,1 +1,1 @@\n-port=50434\n+port=25433\n"
+	sdw1 := mock_idl.NewMockAgentClient(ctrl)
+	sdw1.EXPECT().UpdateConfiguration(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, req *idl.UpdateConfigurationRequest, _ ...interface{}) (*idl.UpdateConfigurationReply, error) {
+			if !req.GetDryRun() {
+				t.Errorf("expected a dry run request got %v", req)
+			}
+
+			if len(req.GetOptions()) != 1 || req.GetOptions()[0].GetPath() != "/data/dbfast1/seg1/postgresql.conf" {
+				t.Errorf("got options %v want the port edit of the segment", req.GetOptions())
+			}
+
+			return &idl.UpdateConfigurationReply{Diffs: []*idl.ConfFileDiff{{Path: "/data/dbfast1/seg1/postgresql.conf", Diff: segmentDiff}}}, nil
+		}).Times(1)
+
+	agentConns := []*idl.Connection{{AgentClient: sdw1, Hostname: "sdw1"}}
+
+	streams := &step.BufferedStreams{}
+	err := hub.UpdateConfFiles(context.Background(), agentConns, nil, streams, config.StateVersion, hub.ConfUpdateOptions{DryRun: true}, semver.MustParse("7.0.0"), intermediate, target)
+	if err != nil {
+		t.Fatalf("unexpected error %+v", err)
+	}
+
+	expected := "--- coordinator:" + path + "\n+++ coordinator:" + path + "\n@@ -1,1 +1,1 @@\n-port=50432\n+port=15432\n" +
+		"--- sdw1:/data/dbfast1/seg1/postgresql.conf\n+++ sdw1:/data/dbfast1/seg1/postgresql.conf\n" + segmentDiff +
+		"dry run: 2 conf files would change on 2 hosts\n"
+	if !strings.HasSuffix(streams.StdoutBuf.String(), expected) {
+		t.Errorf("got stdout %q want it to end with %q", streams.StdoutBuf.String(), expected)
+	}
+
+	contents := testutils.MustReadFile(t, path)
+	if contents != "port=50432\n" {
+		t.Errorf("got %q want the coordinator conf unchanged", contents)
+	}
+
+	testutils.PathMustNotExist(t, path+hub.BackupSuffix)
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/greenplum-db/gpupgrade/idl"
+)
+
+// checkDuplicateMatches returns a warning when the pattern of an option
+// matches more than one line of contents, such as a port set twice in a
+// postgresql.conf, or errors when the option does not allow it. Options
+// rewriting every matching line, as sed does, are not checked.
+func checkDuplicateMatches(path string, contents []byte, opt *idl.UpdateFileConfOptions) (string, error) {
+	mode := opt.GetDuplicateMatches()
+	if mode == idl.UpdateFileConfOptions_rewriteAllMatches {
+		return "", nil
+	}
+
+	pattern, err := regexp.Compile(opt.GetPattern())
+	if err != nil {
+		return "", confCompileError(path, opt, err)
+	}
+
+	matches := 0
+	for _, line := range confLines(string(contents)) {
+		if pattern.MatchString(line) {
+			matches++
+		}
+	}
+
+	if matches <= 1 {
+		return "", nil
+	}
+
+	switch mode {
+	case idl.UpdateFileConfOptions_failDuplicateMatches:
+		return "", confEditError(path, opt, fmt.Sprintf("matched %d lines but expected at most one", matches), matchingConfSnippet(contents, pattern))
+	case idl.UpdateFileConfOptions_rewriteLastMatch:
+		return fmt.Sprintf("%s%s: pattern %q matched %d lines and only the last, which is in effect, is rewritten", path, reasonSuffix(opt), opt.GetPattern(), matches), nil
+	default:
+		return fmt.Sprintf("%s%s: pattern %q matched %d lines and all of them are rewritten", path, reasonSuffix(opt), opt.GetPattern(), matches), nil
+	}
+}
+
+// lastMatchOnly is whether only the last line matching the pattern of the
+// option is rewritten, as the last setting of a GUC is the one in effect.
+func lastMatchOnly(opt *idl.UpdateFileConfOptions) bool {
+	return opt.GetDuplicateMatches() == idl.UpdateFileConfOptions_rewriteLastMatch
+}
@@ -0,0 +1,142 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/testutils"
+)
+
+func TestDuplicateMatches(t *testing.T) {
+	dir := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, dir)
+
+	path := filepath.Join(dir, "postgresql.conf")
+	original := "#port=50432\nport=50432\nmax_connections=100\nport=50432\n"
+
+	option := func(mode idl.UpdateFileConfOptions_DuplicateMatches) *idl.UpdateFileConfOptions {
+		return &idl.UpdateFileConfOptions{
+			Path:             path,
+			Pattern:          `(^[ \t]*port[ \t]*=[ \t]*)50432([^0-9]|$)`,
+			Replacement:      `\115432\2`,
+			Reason:           hub.ReasonPortRewrite,
+			Guc:              "port",
+			ExpectedValue:    "15432",
+			DuplicateMatches: mode,
+		}
+	}
+
+	warning := func(rewritten string) []string {
+		return []string{fmt.Sprintf(`%s for port-rewrite: pattern "(^[ \\t]*port[ \\t]*=[ \\t]*)50432([^0-9]|$)" matched 2 lines and %s`, path, rewritten)}
+	}
+
+	cases := []struct {
+		name     string
+		mode     idl.UpdateFileConfOptions_DuplicateMatches
+		expected string
+		warnings []string
+	}{
+		{
+			name:     "rewrites every matching line by default",
+			mode:     idl.UpdateFileConfOptions_rewriteAllMatches,
+			expected: "#port=50432\nport=15432\nmax_connections=100\nport=15432\n",
+		},
+		{
+			name:     "warns when rewriting every matching line",
+			mode:     idl.UpdateFileConfOptions_warnDuplicateMatches,
+			expected: "#port=50432\nport=15432\nmax_connections=100\nport=15432\n",
+			warnings: warning("all of them are rewritten"),
+		},
+		{
+			name:     "rewrites only the last matching line",
+			mode:     idl.UpdateFileConfOptions_rewriteLastMatch,
+			expected: "#port=50432\nport=50432\nmax_connections=100\nport=15432\n",
+			warnings: warning("only the last, which is in effect, is rewritten"),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			testutils.MustWriteToFile(t, path, original)
+
+			reply, err := hub.UpdateConfigurationFileReply(context.Background(), []*idl.UpdateFileConfOptions{option(c.mode)})
+			if err != nil {
+				t.Fatalf("unexpected error %+v", err)
+			}
+
+			contents := testutils.MustReadFile(t, path)
+			if contents != c.expected {
+				t.Errorf("got %q want %q", contents, c.expected)
+			}
+
+			if !reflect.DeepEqual(reply.GetWarnings(), c.warnings) {
+				t.Errorf("got warnings %q want %q", reply.GetWarnings(), c.warnings)
+			}
+		})
+	}
+
+	t.Run("leaves the earlier matching lines alone when rerun", func(t *testing.T) {
+		edited := "#port=50432\nport=50432\nmax_connections=100\nport=15432\n"
+		testutils.MustWriteToFile(t, path, edited)
+
+		reply, err := hub.UpdateConfigurationFileReply(context.Background(), []*idl.UpdateFileConfOptions{option(idl.UpdateFileConfOptions_rewriteLastMatch)})
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		contents := testutils.MustReadFile(t, path)
+		if contents != edited {
+			t.Errorf("got %q want %q", contents, edited)
+		}
+
+		if len(reply.GetChangedPaths()) != 0 {
+			t.Errorf("got changed paths %q want none", reply.GetChangedPaths())
+		}
+
+		if len(reply.GetChecksums()) != 1 || reply.GetChecksums()[0].GetMatchedOptions() != 1 {
+			t.Errorf("got checksums %v want the edit in effect", reply.GetChecksums())
+		}
+	})
+
+	t.Run("errors on more than one matching line when not allowed", func(t *testing.T) {
+		testutils.MustWriteToFile(t, path, original)
+
+		_, err := hub.UpdateConfigurationFileReply(context.Background(), []*idl.UpdateFileConfOptions{option(idl.UpdateFileConfOptions_failDuplicateMatches)})
+		expected := "matched 2 lines but expected at most one"
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("got error %v want it to contain %q", err, expected)
+		}
+
+		contents := testutils.MustReadFile(t, path)
+		if contents != original {
+			t.Errorf("got %q want the file unchanged", contents)
+		}
+	})
+
+	t.Run("does not warn about a single matching line", func(t *testing.T) {
+		testutils.MustWriteToFile(t, path, "port=50432\n")
+
+		reply, err := hub.UpdateConfigurationFileReply(context.Background(), []*idl.UpdateFileConfOptions{option(idl.UpdateFileConfOptions_failDuplicateMatches)})
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		if len(reply.GetWarnings()) != 0 {
+			t.Errorf("got warnings %q want none", reply.GetWarnings())
+		}
+
+		contents := testutils.MustReadFile(t, path)
+		if contents != "port=15432\n" {
+			t.Errorf("got %q want %q", contents, "port=15432\n")
+		}
+	})
+}
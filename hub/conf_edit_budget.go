@@ -0,0 +1,89 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"context"
+	"sync"
+
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/idl"
+)
+
+// defaultConfEditConcurrency is conservative so that hosts with many
+// segments sharing their disks are not flooded with writes.
+const defaultConfEditConcurrency = 16
+
+// confEditConcurrency caps the conf files edited at once across every host of
+// a conf update, including the coordinator. The hub sets it from its
+// configuration.
+var confEditConcurrency = defaultConfEditConcurrency
+
+func SetConfEditConcurrency(concurrency int) {
+	confEditConcurrency = concurrency
+}
+
+func ResetConfEditConcurrency() {
+	confEditConcurrency = defaultConfEditConcurrency
+}
+
+// confEditBudget is the number of conf files that may be edited at once
+// across the hosts of a conf update. Each host holds a share of it while its
+// edits are in flight, and its agent edits at most that many files at once.
+type confEditBudget struct {
+	tokens chan struct{}
+	// acquireMutex lets a single host take its tokens at a time so that hosts
+	// each holding part of their share cannot wait on one another.
+	acquireMutex sync.Mutex
+}
+
+func newConfEditBudget(concurrency int) *confEditBudget {
+	return &confEditBudget{tokens: make(chan struct{}, max(concurrency, 1))}
+}
+
+// share returns the number of files a host with the given files may edit at
+// once when hosts are sent their edits at once, which splits the budget
+// evenly between them.
+func (b *confEditBudget) share(files int, hosts int) int {
+	if confRPCConcurrency > 0 {
+		hosts = min(hosts, confRPCConcurrency)
+	}
+
+	return min(max(cap(b.tokens)/max(hosts, 1), 1), files)
+}
+
+// acquire waits for n of the budget until ctx is canceled, returning the
+// function to release them.
+func (b *confEditBudget) acquire(ctx context.Context, n int) (func(), error) {
+	b.acquireMutex.Lock()
+	defer b.acquireMutex.Unlock()
+
+	release := func(n int) {
+		for i := 0; i < n; i++ {
+			<-b.tokens
+		}
+	}
+
+	for i := 0; i < n; i++ {
+		select {
+		case b.tokens <- struct{}{}:
+		case <-ctx.Done():
+			release(i)
+			return nil, xerrors.Errorf("wait for the conf edit budget: %w", ctx.Err())
+		}
+	}
+
+	return func() { release(n) }, nil
+}
+
+// confRequestFiles returns the number of files edited by req.
+func confRequestFiles(req *idl.UpdateConfigurationRequest) int {
+	paths := make(map[string]bool)
+	for _, opt := range req.GetOptions() {
+		paths[opt.GetPath()] = true
+	}
+
+	return len(paths)
+}
@@ -0,0 +1,100 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/idl/mock_idl"
+)
+
+func TestConfEditBudget(t *testing.T) {
+	hosts := []string{"sdw1", "sdw2", "sdw3", "sdw4"}
+
+	intermediateSegs := greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: "/data/qddir/seg-1", Port: 50432, Role: greenplum.PrimaryRole},
+	}
+	targetSegs := greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: "/data/qddir/seg-1", Port: 15432, Role: greenplum.PrimaryRole},
+	}
+
+	// each host has two primaries
+	for i := 0; i < 2*len(hosts); i++ {
+		hostname := hosts[i/2]
+		dataDir := fmt.Sprintf("/data/dbfast%d/seg%d", i/2+1, i)
+		intermediateSegs = append(intermediateSegs, greenplum.SegConfig{DbID: i + 2, ContentID: i, Hostname: hostname, DataDir: dataDir, Port: 50434 + i, Role: greenplum.PrimaryRole})
+		targetSegs = append(targetSegs, greenplum.SegConfig{DbID: i + 2, ContentID: i, Hostname: hostname, DataDir: dataDir, Port: 25434 + i, Role: greenplum.PrimaryRole})
+	}
+
+	intermediate := hub.MustCreateCluster(t, intermediateSegs)
+	target := hub.MustCreateCluster(t, targetSegs)
+
+	cases := []struct {
+		name        string
+		concurrency int
+		maxShare    int32
+	}{
+		{name: "splits the budget between the hosts", concurrency: 8, maxShare: 2},
+		{name: "holds back hosts once the budget is spent", concurrency: 3, maxShare: 1},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			hub.SetConfEditConcurrency(c.concurrency)
+			defer hub.ResetConfEditConcurrency()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			var mutex sync.Mutex
+			var inFlight, maxInFlight int32
+			update := func(ctx context.Context, req *idl.UpdateConfigurationRequest, opts ...interface{}) (*idl.UpdateConfigurationReply, error) {
+				share := req.GetFileConcurrency()
+				if share < 1 || share > c.maxShare {
+					t.Errorf("got a share of %d files want between 1 and %d", share, c.maxShare)
+				}
+
+				mutex.Lock()
+				inFlight += share
+				if inFlight > maxInFlight {
+					maxInFlight = inFlight
+				}
+				mutex.Unlock()
+
+				time.Sleep(10 * time.Millisecond)
+
+				mutex.Lock()
+				inFlight -= share
+				mutex.Unlock()
+
+				return &idl.UpdateConfigurationReply{}, nil
+			}
+
+			var agentConns []*idl.Connection
+			for _, hostname := range hosts {
+				client := mock_idl.NewMockAgentClient(ctrl)
+				client.EXPECT().UpdateConfiguration(gomock.Any(), gomock.Any()).DoAndReturn(update)
+				agentConns = append(agentConns, &idl.Connection{AgentClient: client, Hostname: hostname})
+			}
+
+			err := hub.UpdatePostgresqlConfOnSegments(context.Background(), agentConns, intermediate, target)
+			if err != nil {
+				t.Fatalf("unexpected error %+v", err)
+			}
+
+			if maxInFlight > int32(c.concurrency) {
+				t.Errorf("got %d files edited at once want at most %d", maxInFlight, c.concurrency)
+			}
+		})
+	}
+}
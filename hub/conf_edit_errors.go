@@ -0,0 +1,102 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/idl"
+)
+
+// The lines of a conf file quoted in an edit error are bounded so that a
+// large file is never dumped into the logs.
+const (
+	maxConfSnippetLines      = 5
+	maxConfSnippetLineLength = 120
+)
+
+// confEditError describes an option that failed to edit the file at path as
+// "update <path> for <reason>: pattern <pattern> <problem>; replacement
+// <replacement>", followed by the quoted lines of the file involved, if any,
+// so that every edit failure names what was attempted where.
+func confEditError(path string, opt *idl.UpdateFileConfOptions, problem string, snippet string) error {
+	msg := fmt.Sprintf("update %s%s: pattern %q %s; replacement %q", path, reasonSuffix(opt), opt.GetPattern(), problem, opt.GetReplacement())
+	if snippet != "" {
+		msg += "; " + snippet
+	}
+
+	return xerrors.New(msg)
+}
+
+// confCompileError is the error of an option whose pattern does not compile,
+// which names the position of the error within it.
+func confCompileError(path string, opt *idl.UpdateFileConfOptions, err error) error {
+	return xerrors.Errorf("update %s%s: compile pattern %q with replacement %q: %w", path, reasonSuffix(opt), opt.GetPattern(), opt.GetReplacement(), err)
+}
+
+// matchingConfSnippet quotes the numbered lines of contents the pattern
+// matches, such as `lines 2: "port=5000", 7: "port=5000"`.
+func matchingConfSnippet(contents []byte, pattern *regexp.Regexp) string {
+	return confSnippet(contents, pattern.MatchString)
+}
+
+// gucConfSnippet quotes the numbered lines of contents setting the GUC of an
+// option, so that an option whose pattern matched no lines shows what the
+// GUC is set to instead. Options without a GUC have no snippet.
+func gucConfSnippet(contents []byte, opt *idl.UpdateFileConfOptions) string {
+	guc := strings.ToLower(opt.GetGuc())
+	if guc == "" {
+		return ""
+	}
+
+	snippet := confSnippet(contents, func(line string) bool {
+		match := confLinePattern.FindStringSubmatch(line)
+		return match != nil && strings.ToLower(match[1]) == guc
+	})
+	if snippet == "" {
+		return opt.GetGuc() + " is not set"
+	}
+
+	return opt.GetGuc() + " is set at " + snippet
+}
+
+func confSnippet(contents []byte, match func(line string) bool) string {
+	var quoted []string
+	more := 0
+	for i, line := range confLines(string(contents)) {
+		if !match(line) {
+			continue
+		}
+
+		if len(quoted) == maxConfSnippetLines {
+			more++
+			continue
+		}
+
+		if len(line) > maxConfSnippetLineLength {
+			line = line[:maxConfSnippetLineLength] + "..."
+		}
+		quoted = append(quoted, fmt.Sprintf("%d: %q", i+1, line))
+	}
+
+	if len(quoted) == 0 {
+		return ""
+	}
+
+	snippet := "line "
+	if len(quoted) > 1 {
+		snippet = "lines "
+	}
+	snippet += strings.Join(quoted, ", ")
+
+	if more > 0 {
+		snippet += fmt.Sprintf(" and %d more", more)
+	}
+
+	return snippet
+}
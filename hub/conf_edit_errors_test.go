@@ -0,0 +1,65 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/testutils"
+)
+
+func TestConfEditErrors(t *testing.T) {
+	dir := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, dir)
+
+	path := filepath.Join(dir, "postgresql.conf")
+
+	t.Run("quotes a bounded number of the matching lines", func(t *testing.T) {
+		long := "my_extension.path = '" + strings.Repeat("a", 200) + "'"
+		testutils.MustWriteToFile(t, path, "max_connections=100\n"+strings.Repeat(long+"\n", 7))
+
+		err := hub.UpdateConfigurationFile(context.Background(), []*idl.UpdateFileConfOptions{
+			{Path: path, Pattern: `^(my_extension.path = ).*$`, Replacement: `\1'/usr/local/lib'`, ExpectedMatches: 1},
+		})
+
+		quoted := fmt.Sprintf("%q", long[:120]+"...")
+		expected := fmt.Sprintf(`update %s: pattern "^(my_extension.path = ).*$" matched 7 lines but expected 1; replacement "\\1'/usr/local/lib'"; lines 2: %s, 3: %s, 4: %s, 5: %s, 6: %s and 2 more`,
+			path, quoted, quoted, quoted, quoted, quoted)
+		if err == nil || err.Error() != expected {
+			t.Errorf("got error %v want %q", err, expected)
+		}
+	})
+
+	t.Run("notes a GUC that is not set", func(t *testing.T) {
+		testutils.MustWriteToFile(t, path, "max_connections=100\n")
+
+		err := hub.UpdateConfigurationFile(context.Background(), []*idl.UpdateFileConfOptions{
+			{Path: path, Pattern: `^(port=)5000$`, Replacement: `\16000`, Reason: hub.ReasonPortRewrite, Guc: "port"},
+		})
+
+		expected := `matched no lines; replacement "\\16000"; port is not set`
+		if err == nil || !strings.HasSuffix(err.Error(), expected) {
+			t.Errorf("got error %v want it to end with %q", err, expected)
+		}
+	})
+
+	t.Run("names the position of a pattern that does not compile", func(t *testing.T) {
+		testutils.MustWriteToFile(t, path, "port=5000\n")
+
+		err := hub.UpdateConfigurationFile(context.Background(), []*idl.UpdateFileConfOptions{
+			{Path: path, Pattern: `^(port=5000$`, Replacement: `\16000`},
+		})
+
+		expected := fmt.Sprintf("update %s: compile pattern %q with replacement %q: error parsing regexp: missing closing ): `^(port=5000$`", path, `^(port=5000$`, `\16000`)
+		if err == nil || err.Error() != expected {
+			t.Errorf("got error %v want %q", err, expected)
+		}
+	})
+}
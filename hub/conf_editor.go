@@ -0,0 +1,202 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
+	"github.com/greenplum-db/gpupgrade/utils/errorlist"
+)
+
+// ConfEditor edits a conf file with its options, applied in order, and
+// returns the reply of that file alone. The lines changed are numbered by the
+// index of their option among opts. A dry run previews the edits without
+// backing up or writing the file. The reply is returned along with any error
+// so that the edits skipped before a failure are still reported. A missing
+// file fails with ErrConfFileAbsent unless its options are all marked
+// skipIfAbsent.
+type ConfEditor interface {
+	Edit(ctx context.Context, path string, opts []*idl.UpdateFileConfOptions, dryRun bool) (*idl.UpdateConfigurationReply, error)
+}
+
+// FileConfEditor edits the conf file on disk, backing it up and writing it
+// once all of its options have been applied to its contents in memory. When
+// any option fails the file is neither backed up nor written.
+type FileConfEditor struct{}
+
+func (FileConfEditor) Edit(ctx context.Context, path string, opts []*idl.UpdateFileConfOptions, dryRun bool) (*idl.UpdateConfigurationReply, error) {
+	reply := &idl.UpdateConfigurationReply{}
+
+	// the file is checked first so that an absent one is reported as such
+	// rather than as failing the later checks
+	info, err := utils.System.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		if !absentConfFileOptional(opts) {
+			return reply, &ConfFileAbsentError{Path: path, Reason: opts[0].GetReason(), Err: err}
+		}
+
+		for _, opt := range opts {
+			reply.Skipped = append(reply.Skipped, skippedAbsentFileMessage(path, opt))
+		}
+		return reply, nil
+	}
+
+	if err != nil {
+		return reply, xerrors.Errorf("update %s%s: %w", filepath.Base(path), reasonSuffix(opts[0]), err)
+	}
+
+	for _, opt := range opts {
+		if err := validateSingleLineGUC(path, opt.GetGuc()); err != nil {
+			return reply, err
+		}
+	}
+
+	suffix, err := confBackupSuffix(path, opts)
+	if err != nil {
+		return reply, err
+	}
+
+	before, err := utils.System.ReadFile(path)
+	if err != nil {
+		return reply, xerrors.Errorf("update %s%s: %w", filepath.Base(path), reasonSuffix(opts[0]), err)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return reply, xerrors.Errorf("update %s%s: canceled before editing: %w", path, reasonSuffix(opts[0]), err)
+	}
+
+	var editErr error
+	var changedLines []*idl.ChangedLine
+	contents := before
+	for i, opt := range opts {
+		if err := ctx.Err(); err != nil {
+			return reply, errorlist.Append(editErr, xerrors.Errorf("update %s%s: canceled while editing: %w", path, reasonSuffix(opt), err))
+		}
+
+		met, err := checkCurrentValue(path, contents, opt)
+		if err != nil {
+			editErr = errorlist.Append(editErr, err)
+			continue
+		}
+
+		if !met {
+			reply.Skipped = append(reply.Skipped, skippedEditMessage(path, opt))
+			continue
+		}
+
+		// An option whose GUC already has its expected value, such as when
+		// rerunning an interrupted update, is satisfied rather than applied
+		// again, since its pattern matching the prior value no longer matches.
+		done, err := hasExpectedValue(contents, opt)
+		if err != nil {
+			editErr = errorlist.Append(editErr, xerrors.Errorf("update %s%s: %w", filepath.Base(path), reasonSuffix(opt), err))
+			continue
+		}
+
+		if done {
+			reply.Satisfied = append(reply.Satisfied, satisfiedEditMessage(path, opt))
+			recordConfProgress()
+			continue
+		}
+
+		if err := checkExpectedMatches(path, contents, opt); err != nil {
+			editErr = errorlist.Append(editErr, err)
+			continue
+		}
+
+		warning, err := checkDuplicateMatches(path, contents, opt)
+		if err != nil {
+			editErr = errorlist.Append(editErr, err)
+			continue
+		}
+
+		if warning != "" {
+			reply.Warnings = append(reply.Warnings, warning)
+		}
+
+		rewritten, err := rewriteConfContents(path, contents, opt)
+		if err != nil {
+			editErr = errorlist.Append(editErr, err)
+		} else {
+			changedLines = append(changedLines, changedConfLines(int32(i), path, contents, rewritten)...)
+			contents = rewritten
+		}
+		recordConfProgress()
+	}
+
+	// Only rewritten files have their trailing newlines fixed, so that a file
+	// without edits to make is left as it is.
+	after := contents
+	if !bytes.Equal(before, contents) {
+		after = fixTrailingNewline(before, contents, opts)
+	}
+
+	if dryRun {
+		if !bytes.Equal(before, after) {
+			reply.Diffs = append(reply.Diffs, &idl.ConfFileDiff{Path: path, Diff: unifiedConfDiff(before, after)})
+		}
+		return reply, editErr
+	}
+
+	// A file with a failed option is left untouched rather than written with
+	// the edits of its other options, so that it is never half edited.
+	if editErr != nil {
+		return reply, editErr
+	}
+
+	// A file already at its target, such as when rerunning an interrupted
+	// update, is not backed up again so that its backup keeps the original
+	// contents.
+	if !bytes.Equal(before, after) {
+		if err := journalConfEdit(path, before, opts, changedLines); err != nil {
+			return reply, err
+		}
+
+		if err := backupConfFile(path, suffix, confBackupSink(opts), confBackupPolicy(opts)); err != nil {
+			return reply, err
+		}
+
+		if err := writeConfFileAtomically(path, after, info, confOptionsTempDir(opts)); err != nil {
+			return reply, xerrors.Errorf("update %s%s: %w", filepath.Base(path), reasonSuffix(opts[0]), err)
+		}
+
+		reply.ChangedPaths = append(reply.ChangedPaths, path)
+		reply.ChangedLines = changedLines
+
+		resolved, err := resolveConfPath(path)
+		if err != nil {
+			return reply, err
+		}
+
+		if resolved != path {
+			reply.ResolvedPaths = map[string]string{path: resolved}
+		}
+	}
+
+	checksum, err := readBackConfFile(path, after, opts)
+	if err != nil {
+		return reply, err
+	}
+	reply.Checksums = append(reply.Checksums, checksum)
+
+	return reply, nil
+}
+
+var confEditor ConfEditor = FileConfEditor{}
+
+func SetConfEditor(editor ConfEditor) {
+	confEditor = editor
+}
+
+func ResetConfEditor() {
+	confEditor = FileConfEditor{}
+}
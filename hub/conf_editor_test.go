@@ -0,0 +1,197 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils/errorlist"
+)
+
+// fakeConfEditor returns the reply and error of each path without touching
+// the filesystem, recording the options it was given.
+type fakeConfEditor struct {
+	mutex      sync.Mutex
+	edited     map[string][]*idl.UpdateFileConfOptions
+	editing    int
+	maxEditing int
+	delay      time.Duration
+
+	replies map[string]*idl.UpdateConfigurationReply
+	errs    map[string]error
+}
+
+func (f *fakeConfEditor) Edit(ctx context.Context, path string, opts []*idl.UpdateFileConfOptions, dryRun bool) (*idl.UpdateConfigurationReply, error) {
+	f.mutex.Lock()
+	if f.edited == nil {
+		f.edited = make(map[string][]*idl.UpdateFileConfOptions)
+	}
+	f.edited[path] = opts
+	f.editing++
+	f.maxEditing = max(f.maxEditing, f.editing)
+	f.mutex.Unlock()
+
+	time.Sleep(f.delay)
+
+	f.mutex.Lock()
+	f.editing--
+	f.mutex.Unlock()
+
+	return f.replies[path], f.errs[path]
+}
+
+func TestConfEditor(t *testing.T) {
+	opts := []*idl.UpdateFileConfOptions{
+		{Path: "/data/dbfast1/seg1/postgresql.conf", Pattern: "a"},
+		{Path: "/data/dbfast2/seg2/postgresql.conf", Pattern: "b"},
+		{Path: "/data/dbfast1/seg1/postgresql.conf", Pattern: "c"},
+	}
+
+	cases := []struct {
+		name     string
+		editor   *fakeConfEditor
+		expected *idl.UpdateConfigurationReply
+		errs     []string
+	}{
+		{
+			name: "merges the sorted replies of each file",
+			editor: &fakeConfEditor{replies: map[string]*idl.UpdateConfigurationReply{
+				"/data/dbfast2/seg2/postgresql.conf": {
+					ChangedPaths: []string{"/data/dbfast2/seg2/postgresql.conf"},
+					Skipped:      []string{"skipped b"},
+					ChangedLines: []*idl.ChangedLine{{Option: 0, Path: "/data/dbfast2/seg2/postgresql.conf"}},
+				},
+				"/data/dbfast1/seg1/postgresql.conf": {
+					ChangedPaths: []string{"/data/dbfast1/seg1/postgresql.conf"},
+					Skipped:      []string{"skipped a"},
+					ChangedLines: []*idl.ChangedLine{{Option: 1, Path: "/data/dbfast1/seg1/postgresql.conf"}},
+				},
+			}},
+			expected: &idl.UpdateConfigurationReply{
+				ChangedPaths: []string{"/data/dbfast1/seg1/postgresql.conf", "/data/dbfast2/seg2/postgresql.conf"},
+				Skipped:      []string{"skipped a", "skipped b"},
+				// the lines are numbered by the options of the request
+				ChangedLines: []*idl.ChangedLine{
+					{Option: 1, Path: "/data/dbfast2/seg2/postgresql.conf"},
+					{Option: 2, Path: "/data/dbfast1/seg1/postgresql.conf"},
+				},
+			},
+		},
+		{
+			name: "returns the errors of every file along with the replies of the rest",
+			editor: &fakeConfEditor{
+				replies: map[string]*idl.UpdateConfigurationReply{
+					"/data/dbfast2/seg2/postgresql.conf": {ChangedPaths: []string{"/data/dbfast2/seg2/postgresql.conf"}},
+				},
+				errs: map[string]error{
+					"/data/dbfast1/seg1/postgresql.conf": errorlist.Append(errors.New("permission denied"), errors.New("no match")),
+				},
+			},
+			expected: &idl.UpdateConfigurationReply{
+				ChangedPaths: []string{"/data/dbfast2/seg2/postgresql.conf"},
+			},
+			errs: []string{"permission denied", "no match"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			hub.SetConfEditor(c.editor)
+			defer hub.ResetConfEditor()
+
+			reply, err := hub.UpdateConfigurationFileReply(context.Background(), opts)
+			if len(c.errs) == 0 && err != nil {
+				t.Fatalf("unexpected error %+v", err)
+			}
+
+			if len(c.errs) > 0 {
+				var errs errorlist.Errors
+				if !errors.As(err, &errs) {
+					t.Fatalf("error %#v does not contain type %T", err, errs)
+				}
+
+				var actual []string
+				for _, err := range errs {
+					actual = append(actual, err.Error())
+				}
+
+				if !reflect.DeepEqual(actual, c.errs) {
+					t.Errorf("got errors %q want %q", actual, c.errs)
+				}
+			}
+
+			if !reflect.DeepEqual(reply.GetChangedPaths(), c.expected.GetChangedPaths()) {
+				t.Errorf("got changed paths %v want %v", reply.GetChangedPaths(), c.expected.GetChangedPaths())
+			}
+
+			if !reflect.DeepEqual(reply.GetSkipped(), c.expected.GetSkipped()) {
+				t.Errorf("got skipped %v want %v", reply.GetSkipped(), c.expected.GetSkipped())
+			}
+
+			if len(reply.GetChangedLines()) != len(c.expected.GetChangedLines()) {
+				t.Fatalf("got changed lines %v want %v", reply.GetChangedLines(), c.expected.GetChangedLines())
+			}
+
+			for i, line := range reply.GetChangedLines() {
+				expected := c.expected.GetChangedLines()[i]
+				if line.GetOption() != expected.GetOption() || line.GetPath() != expected.GetPath() {
+					t.Errorf("got changed line %v want %v", line, expected)
+				}
+			}
+		})
+	}
+
+	t.Run("gives each file its options in order", func(t *testing.T) {
+		editor := &fakeConfEditor{}
+		hub.SetConfEditor(editor)
+		defer hub.ResetConfEditor()
+
+		_, err := hub.UpdateConfigurationFileReply(context.Background(), opts)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		expected := map[string][]*idl.UpdateFileConfOptions{
+			"/data/dbfast1/seg1/postgresql.conf": {opts[0], opts[2]},
+			"/data/dbfast2/seg2/postgresql.conf": {opts[1]},
+		}
+		if !reflect.DeepEqual(editor.edited, expected) {
+			t.Errorf("got edited %v want %v", editor.edited, expected)
+		}
+	})
+
+	t.Run("edits at most the configured number of files at once", func(t *testing.T) {
+		hub.SetConfFileConcurrency(2)
+		defer hub.ResetConfFileConcurrency()
+
+		editor := &fakeConfEditor{delay: 10 * time.Millisecond}
+		hub.SetConfEditor(editor)
+		defer hub.ResetConfEditor()
+
+		var many []*idl.UpdateFileConfOptions
+		for _, path := range []string{"/a/postgresql.conf", "/b/postgresql.conf", "/c/postgresql.conf", "/d/postgresql.conf", "/e/postgresql.conf"} {
+			many = append(many, &idl.UpdateFileConfOptions{Path: path})
+		}
+
+		_, err := hub.UpdateConfigurationFileReply(context.Background(), many)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		if len(editor.edited) != len(many) {
+			t.Errorf("got %d files edited want %d", len(editor.edited), len(many))
+		}
+
+		if editor.maxEditing > 2 {
+			t.Errorf("got %d files edited at once want at most 2", editor.maxEditing)
+		}
+	})
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"sync"
+
+	"github.com/greenplum-db/gpupgrade/idl"
+)
+
+// Conf update phases as reported in substep events.
+const (
+	ConfPhaseCoordinator           = "coordinator"
+	ConfPhaseStandby               = "standby"
+	ConfPhaseSegmentPostgresqlConf = "segment-postgresql-conf"
+	ConfPhaseSegmentRecoveryConf   = "segment-recovery-conf"
+	ConfPhaseSegmentPgHbaConf      = "segment-pg-hba-conf"
+	ConfPhaseCustom                = "custom"
+)
+
+// confEvents sends the substep events of a conf update so that its progress
+// is reported alongside the substep status. A nil confEvents or sender sends
+// nothing. Events are sent from the goroutine of each host, so the sender
+// must serialize its sends as that of a step does.
+type confEvents struct {
+	sender idl.MessageSender
+
+	mutex     sync.Mutex
+	phase     string
+	completed int32
+	total     int32
+}
+
+func (e *confEvents) started(totalHosts int) {
+	e.send(&idl.SubstepEvent{Type: idl.SubstepEvent_started, TotalHosts: int32(totalHosts)})
+}
+
+func (e *confEvents) startPhase(phase string, totalHosts int) {
+	if e == nil {
+		return
+	}
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.phase = phase
+	e.completed = 0
+	e.total = int32(totalHosts)
+}
+
+func (e *confEvents) hostCompleted(hostname string, failed bool) {
+	if e == nil {
+		return
+	}
+
+	e.mutex.Lock()
+	e.completed++
+	event := &idl.SubstepEvent{
+		Type:           idl.SubstepEvent_host_completed,
+		Phase:          e.phase,
+		Hostname:       hostname,
+		Failed:         failed,
+		CompletedHosts: e.completed,
+		TotalHosts:     e.total,
+	}
+	e.mutex.Unlock()
+
+	e.send(event)
+}
+
+func (e *confEvents) finished(failed bool) {
+	e.send(&idl.SubstepEvent{Type: idl.SubstepEvent_finished, Failed: failed})
+}
+
+func (e *confEvents) send(event *idl.SubstepEvent) {
+	if e == nil || e.sender == nil {
+		return
+	}
+
+	event.Substep = idl.Substep_update_target_conf_files
+
+	// As with substep statuses the stream is not guaranteed to remain
+	// connected, so errors are explicitly ignored.
+	_ = e.sender.Send(&idl.Message{Contents: &idl.Message_Event{Event: event}})
+}
@@ -0,0 +1,110 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/utils/errorlist"
+)
+
+// ConfExclusions are the segments left out of the conf update, such as a
+// segment being maintained independently during a phased rollout. A content
+// excludes both its primary and mirror, and a host excludes every segment on
+// it. The coordinator and standby are always updated.
+type ConfExclusions struct {
+	Contents []int    `json:",omitempty"`
+	Hosts    []string `json:",omitempty"`
+}
+
+var confExclusions ConfExclusions
+
+func SetConfExclusions(exclusions ConfExclusions) {
+	confExclusions = exclusions
+}
+
+func ResetConfExclusions() {
+	confExclusions = ConfExclusions{}
+}
+
+// Validate errors when an exclusion does not reference a segment of the
+// target cluster, or would exclude the coordinator or standby.
+func (e ConfExclusions) Validate(target *greenplum.Cluster) error {
+	var err error
+
+	for _, content := range e.Contents {
+		if content == -1 {
+			err = errorlist.Append(err, xerrors.New("cannot exclude content -1 since the coordinator and standby are always updated"))
+			continue
+		}
+
+		if _, ok := target.Primaries[content]; !ok {
+			err = errorlist.Append(err, xerrors.Errorf("excluded content %d is not a segment of the cluster", content))
+		}
+	}
+
+	hosts := make(map[string]bool)
+	for _, host := range AgentHosts(target) {
+		hosts[host] = true
+	}
+
+	for _, host := range e.Hosts {
+		if host == target.CoordinatorHostname() || (target.HasStandby() && host == target.StandbyHostname()) {
+			err = errorlist.Append(err, xerrors.Errorf("cannot exclude host %s since the coordinator and standby are always updated", host))
+			continue
+		}
+
+		if !hosts[host] {
+			err = errorlist.Append(err, xerrors.Errorf("excluded host %s has no segments of the cluster", host))
+		}
+	}
+
+	return err
+}
+
+// excludes returns why a segment is excluded, or false when it is not.
+func (e ConfExclusions) excludes(seg *greenplum.SegConfig) (string, bool) {
+	if seg.ContentID == -1 {
+		return "", false
+	}
+
+	for _, content := range e.Contents {
+		if seg.ContentID == content {
+			return fmt.Sprintf("content %d is excluded", content), true
+		}
+	}
+
+	for _, host := range e.Hosts {
+		if seg.IsOnHost(host) {
+			return fmt.Sprintf("host %s is excluded", host), true
+		}
+	}
+
+	return "", false
+}
+
+// confIncluded returns whether the conf files of a segment are updated.
+func confIncluded(seg *greenplum.SegConfig) bool {
+	_, excluded := confExclusions.excludes(seg)
+	return !excluded
+}
+
+// reportConfExclusions writes each excluded segment of the target cluster and
+// why it is excluded.
+func reportConfExclusions(w io.Writer, target *greenplum.Cluster) {
+	segments := target.SelectSegments(func(seg *greenplum.SegConfig) bool {
+		return !confIncluded(seg)
+	})
+	sort.Slice(segments, func(i, j int) bool { return segments[i].DbID < segments[j].DbID })
+
+	for _, seg := range segments {
+		reason, _ := confExclusions.excludes(&seg)
+		fmt.Fprintf(w, "skipping the conf update of %s %s with content %d on host %s since %s\n", segmentRole(&seg), seg.DataDir, seg.ContentID, seg.Hostname, reason)
+	}
+}
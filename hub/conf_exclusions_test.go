@@ -0,0 +1,124 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"context"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/blang/semver/v4"
+
+	"github.com/greenplum-db/gpupgrade/config"
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/step"
+	"github.com/greenplum-db/gpupgrade/testutils"
+)
+
+func TestConfExclusions(t *testing.T) {
+	intermediate := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: "/data/qddir/seg.HqtFHX54y0o.-1", Port: 50432, Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: -1, Hostname: "standby", DataDir: "/data/standby.HqtFHX54y0o", Port: 50433, Role: greenplum.MirrorRole},
+		{DbID: 3, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg.HqtFHX54y0o.1", Port: 50434, Role: greenplum.PrimaryRole},
+		{DbID: 4, ContentID: 0, Hostname: "sdw2", DataDir: "/data/dbfast_mirror1/seg.HqtFHX54y0o.1", Port: 50435, Role: greenplum.MirrorRole},
+		{DbID: 5, ContentID: 1, Hostname: "sdw2", DataDir: "/data/dbfast2/seg.HqtFHX54y0o.2", Port: 50436, Role: greenplum.PrimaryRole},
+		{DbID: 6, ContentID: 1, Hostname: "sdw1", DataDir: "/data/dbfast_mirror2/seg.HqtFHX54y0o.2", Port: 50437, Role: greenplum.MirrorRole},
+	})
+
+	target := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: "/data/qddir/seg-1", Port: 15432, Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: -1, Hostname: "standby", DataDir: "/data/standby", Port: 16432, Role: greenplum.MirrorRole},
+		{DbID: 3, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg1", Port: 25433, Role: greenplum.PrimaryRole},
+		{DbID: 4, ContentID: 0, Hostname: "sdw2", DataDir: "/data/dbfast_mirror1/seg1", Port: 25434, Role: greenplum.MirrorRole},
+		{DbID: 5, ContentID: 1, Hostname: "sdw2", DataDir: "/data/dbfast2/seg2", Port: 25435, Role: greenplum.PrimaryRole},
+		{DbID: 6, ContentID: 1, Hostname: "sdw1", DataDir: "/data/dbfast_mirror2/seg2", Port: 25436, Role: greenplum.MirrorRole},
+	})
+
+	version := semver.MustParse("7.0.0")
+
+	// plannedDataDirs returns the data directories of the planned agent edits
+	plannedDataDirs := func(t *testing.T) []string {
+		plan, err := hub.PlanConfFiles(version, intermediate, target)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		seen := make(map[string]bool)
+		var dirs []string
+		for _, edit := range plan {
+			dir := filepath.Dir(edit.Option.GetPath())
+			if edit.Hostname != "coordinator" && edit.Hostname != "standby" && !seen[dir] {
+				seen[dir] = true
+				dirs = append(dirs, dir)
+			}
+		}
+		sort.Strings(dirs)
+
+		return dirs
+	}
+
+	t.Run("skips the primary and mirror of an excluded content", func(t *testing.T) {
+		hub.SetConfExclusions(hub.ConfExclusions{Contents: []int{1}})
+		defer hub.ResetConfExclusions()
+
+		dirs := plannedDataDirs(t)
+		expected := []string{"/data/dbfast1/seg1", "/data/dbfast_mirror1/seg1"}
+		if strings.Join(dirs, ",") != strings.Join(expected, ",") {
+			t.Errorf("got planned data directories %q want %q", dirs, expected)
+		}
+	})
+
+	t.Run("skips the segments of an excluded host", func(t *testing.T) {
+		hub.SetConfExclusions(hub.ConfExclusions{Hosts: []string{"sdw2"}})
+		defer hub.ResetConfExclusions()
+
+		dirs := plannedDataDirs(t)
+		expected := []string{"/data/dbfast1/seg1", "/data/dbfast_mirror2/seg2"}
+		if strings.Join(dirs, ",") != strings.Join(expected, ",") {
+			t.Errorf("got planned data directories %q want %q", dirs, expected)
+		}
+	})
+
+	t.Run("reports the skipped segments", func(t *testing.T) {
+		stateDir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, stateDir)
+		testutils.SetEnv(t, "GPUPGRADE_HOME", stateDir)
+
+		hub.SetConfExclusions(hub.ConfExclusions{Contents: []int{1}})
+		defer hub.ResetConfExclusions()
+
+		// dump the requests rather than send them
+		streams := &step.BufferedStreams{}
+		err := hub.UpdateConfFiles(context.Background(), nil, nil, streams, config.StateVersion, hub.ConfUpdateOptions{DumpRequests: true}, version, intermediate, target)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		expected := "skipping the conf update of primary /data/dbfast2/seg2 with content 1 on host sdw2 since content 1 is excluded\n" +
+			"skipping the conf update of mirror /data/dbfast_mirror2/seg2 with content 1 on host sdw1 since content 1 is excluded\n"
+		if !strings.HasPrefix(streams.StdoutBuf.String(), expected) {
+			t.Errorf("got stdout %q want it to start with %q", streams.StdoutBuf.String(), expected)
+		}
+	})
+
+	t.Run("errors when the exclusions do not reference segments", func(t *testing.T) {
+		hub.SetConfExclusions(hub.ConfExclusions{Contents: []int{-1, 7}, Hosts: []string{"standby", "sdw9"}})
+		defer hub.ResetConfExclusions()
+
+		err := hub.UpdateConfFiles(context.Background(), nil, nil, step.DevNullStream, config.StateVersion, hub.ConfUpdateOptions{}, version, intermediate, target)
+		for _, expected := range []string{
+			"cannot exclude content -1 since the coordinator and standby are always updated",
+			"excluded content 7 is not a segment of the cluster",
+			"cannot exclude host standby since the coordinator and standby are always updated",
+			"excluded host sdw9 has no segments of the cluster",
+		} {
+			if err == nil || !strings.Contains(err.Error(), expected) {
+				t.Errorf("got error %v want it to contain %q", err, expected)
+			}
+		}
+	})
+}
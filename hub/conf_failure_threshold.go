@@ -0,0 +1,66 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"strconv"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+// FailureThreshold is the number of hosts that may fail a conf update before
+// the update fails. Percent takes precedence over Count when set. The zero
+// value tolerates no failures.
+type FailureThreshold struct {
+	Count   int
+	Percent float64
+}
+
+// ParseFailureThreshold parses an absolute count such as "3" or a percentage
+// of the hosts such as "5%".
+func ParseFailureThreshold(threshold string) (FailureThreshold, error) {
+	if percent, ok := strings.CutSuffix(threshold, "%"); ok {
+		value, err := strconv.ParseFloat(percent, 64)
+		if err != nil || value < 0 || value > 100 {
+			return FailureThreshold{}, xerrors.Errorf("invalid failure threshold %q: expected a percentage between 0%% and 100%%", threshold)
+		}
+
+		return FailureThreshold{Percent: value}, nil
+	}
+
+	value, err := strconv.Atoi(threshold)
+	if err != nil || value < 0 {
+		return FailureThreshold{}, xerrors.Errorf("invalid failure threshold %q: expected a non-negative count or a percentage", threshold)
+	}
+
+	return FailureThreshold{Count: value}, nil
+}
+
+// Exceeded returns whether failed hosts out of total exceed the threshold.
+func (t FailureThreshold) Exceeded(failed int, total int) bool {
+	if t.Percent > 0 {
+		return float64(failed)*100 > t.Percent*float64(total)
+	}
+
+	return failed > t.Count
+}
+
+func (t FailureThreshold) String() string {
+	if t.Percent > 0 {
+		return strconv.FormatFloat(t.Percent, 'f', -1, 64) + "%"
+	}
+
+	return strconv.Itoa(t.Count)
+}
+
+var confFailureThreshold FailureThreshold
+
+func SetConfFailureThreshold(threshold FailureThreshold) {
+	confFailureThreshold = threshold
+}
+
+func ResetConfFailureThreshold() {
+	confFailureThreshold = FailureThreshold{}
+}
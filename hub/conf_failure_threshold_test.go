@@ -0,0 +1,69 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"testing"
+
+	"github.com/greenplum-db/gpupgrade/hub"
+)
+
+func TestParseFailureThreshold(t *testing.T) {
+	t.Run("parses counts and percentages", func(t *testing.T) {
+		cases := []struct {
+			threshold string
+			expected  hub.FailureThreshold
+		}{
+			{"0", hub.FailureThreshold{}},
+			{"3", hub.FailureThreshold{Count: 3}},
+			{"5%", hub.FailureThreshold{Percent: 5}},
+			{"0.5%", hub.FailureThreshold{Percent: 0.5}},
+		}
+
+		for _, c := range cases {
+			threshold, err := hub.ParseFailureThreshold(c.threshold)
+			if err != nil {
+				t.Errorf("unexpected error %+v", err)
+			}
+
+			if threshold != c.expected {
+				t.Errorf("got %+v want %+v", threshold, c.expected)
+			}
+		}
+	})
+
+	t.Run("errors on invalid thresholds", func(t *testing.T) {
+		for _, threshold := range []string{"", "-1", "abc", "101%", "-5%", "%"} {
+			_, err := hub.ParseFailureThreshold(threshold)
+			if err == nil {
+				t.Errorf("expected an error parsing %q", threshold)
+			}
+		}
+	})
+}
+
+func TestFailureThresholdExceeded(t *testing.T) {
+	cases := []struct {
+		name      string
+		threshold hub.FailureThreshold
+		failed    int
+		expected  bool
+	}{
+		{name: "zero tolerance without failures", failed: 0, expected: false},
+		{name: "zero tolerance with a failure", failed: 1, expected: true},
+		{name: "within a count", threshold: hub.FailureThreshold{Count: 2}, failed: 2, expected: false},
+		{name: "exceeding a count", threshold: hub.FailureThreshold{Count: 2}, failed: 3, expected: true},
+		{name: "within a percentage", threshold: hub.FailureThreshold{Percent: 1}, failed: 10, expected: false},
+		{name: "exceeding a percentage", threshold: hub.FailureThreshold{Percent: 1}, failed: 11, expected: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			exceeded := c.threshold.Exceeded(c.failed, 1000)
+			if exceeded != c.expected {
+				t.Errorf("got %t want %t", exceeded, c.expected)
+			}
+		})
+	}
+}
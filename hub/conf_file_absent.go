@@ -0,0 +1,72 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
+)
+
+var ErrConfFileAbsent = errors.New("conf file absent")
+
+// ConfFileAbsentError is the backing error type for ErrConfFileAbsent,
+// naming the file missing from the data directory of its segment, such as a
+// recovery.conf when the file is named differently in the version upgraded.
+type ConfFileAbsentError struct {
+	Path   string
+	Reason string
+	Err    error
+}
+
+func (c *ConfFileAbsentError) Error() string {
+	reason := ""
+	if c.Reason != "" {
+		reason = " for " + c.Reason
+	}
+
+	return fmt.Sprintf("update %s%s: the file is absent from data directory %s", filepath.Base(c.Path), reason, filepath.Dir(c.Path))
+}
+
+func (c *ConfFileAbsentError) Is(err error) bool {
+	return err == ErrConfFileAbsent
+}
+
+func (c *ConfFileAbsentError) Unwrap() error {
+	return c.Err
+}
+
+// absentConfFileOptional is whether every option of the absent file is
+// marked skipIfAbsent, in which case its edits are skipped. Otherwise the
+// file is required and its absence is an error.
+func absentConfFileOptional(opts []*idl.UpdateFileConfOptions) bool {
+	for _, opt := range opts {
+		if !opt.GetSkipIfAbsent() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// skippedAbsentFileMessage describes an edit skipped as its optional file is
+// absent.
+func skippedAbsentFileMessage(path string, opt *idl.UpdateFileConfOptions) string {
+	return fmt.Sprintf("%s%s: the optional file is absent", path, reasonSuffix(opt))
+}
+
+// optionalConfFileAbsent is whether the option edits an optional file that
+// is absent, which is neither read nor verified.
+func optionalConfFileAbsent(opt *idl.UpdateFileConfOptions) bool {
+	if !opt.GetSkipIfAbsent() {
+		return false
+	}
+
+	_, err := utils.System.Stat(opt.GetPath())
+	return errors.Is(err, os.ErrNotExist)
+}
@@ -0,0 +1,65 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+)
+
+// gpperfmonLogLocation is the directory gpperfmon.conf of the target
+// coordinator logs to, such as one on a separate volume that operators keep
+// the gpperfmon logs on. It is empty to log under the target coordinator data
+// directory. The hub sets it from its configuration.
+var gpperfmonLogLocation = ""
+
+func SetGpperfmonLogLocation(path string) {
+	gpperfmonLogLocation = path
+}
+
+func ResetGpperfmonLogLocation() {
+	gpperfmonLogLocation = ""
+}
+
+// gpperfmonLogDir returns the log_location of the gpperfmon.conf of the
+// coordinator with the data directory. A configured location must be a clean
+// absolute path since gpperfmon resolves a relative one against its working
+// directory rather than the data directory.
+func gpperfmonLogDir(dataDir string) (string, error) {
+	if gpperfmonLogLocation == "" {
+		return filepath.Join(dataDir, "gpperfmon", "logs"), nil
+	}
+
+	if !filepath.IsAbs(gpperfmonLogLocation) || filepath.Clean(gpperfmonLogLocation) != gpperfmonLogLocation {
+		return "", xerrors.Errorf("gpperfmon log location %q is not a clean absolute path", gpperfmonLogLocation)
+	}
+
+	return gpperfmonLogLocation, nil
+}
+
+// warnMissingGpperfmonLogLocation warns when the configured gpperfmon log
+// location does not exist, as gpperfmon does not create it. The hub runs on
+// the coordinator host, so both it and gpperfmon.conf are checked locally,
+// and nothing is reported when gpperfmon is not installed.
+func warnMissingGpperfmonLogLocation(w io.Writer, target *greenplum.Cluster) {
+	if gpperfmonLogLocation == "" {
+		return
+	}
+
+	conf := filepath.Join(target.CoordinatorDataDir(), "gpperfmon", "conf", "gpperfmon.conf")
+	if _, err := os.Stat(conf); err != nil {
+		return
+	}
+
+	if _, err := os.Stat(gpperfmonLogLocation); errors.Is(err, os.ErrNotExist) {
+		fmt.Fprintf(w, "warning: gpperfmon log location %s does not exist on host %s. Create it before starting gpperfmon.\n", gpperfmonLogLocation, target.CoordinatorHostname())
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/blang/semver/v4"
+
+	"github.com/greenplum-db/gpupgrade/config"
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/step"
+	"github.com/greenplum-db/gpupgrade/testutils"
+)
+
+func TestGpperfmonLogLocation(t *testing.T) {
+	stateDir := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, stateDir)
+
+	resetEnv := testutils.SetEnv(t, "GPUPGRADE_HOME", stateDir)
+	defer resetEnv()
+
+	const original = "log_location = /data/qddir/seg.HqtFHX54y0o.-1/gpperfmon/logs\n"
+
+	// setup returns the coordinator data directory of the clusters and the
+	// path of its gpperfmon.conf, which is only written when installed.
+	setup := func(t *testing.T, installed bool) (string, string, *greenplum.Cluster, *greenplum.Cluster) {
+		coordinatorDir := testutils.GetTempDir(t, "")
+		testutils.MustWriteToFile(t, filepath.Join(coordinatorDir, "postgresql.conf"), "port=50432\n")
+
+		gpperfmonConf := filepath.Join(coordinatorDir, "gpperfmon", "conf", "gpperfmon.conf")
+		if installed {
+			testutils.MustCreateDir(t, filepath.Dir(gpperfmonConf))
+			testutils.MustWriteToFile(t, gpperfmonConf, original)
+		}
+
+		intermediate := hub.MustCreateCluster(t, greenplum.SegConfigs{
+			{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: coordinatorDir, Port: 50432, Role: greenplum.PrimaryRole},
+		})
+		target := hub.MustCreateCluster(t, greenplum.SegConfigs{
+			{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: coordinatorDir, Port: 15432, Role: greenplum.PrimaryRole},
+		})
+
+		return coordinatorDir, gpperfmonConf, intermediate, target
+	}
+
+	t.Run("points the log location at the configured directory", func(t *testing.T) {
+		logDir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, logDir)
+
+		hub.SetGpperfmonLogLocation(logDir)
+		defer hub.ResetGpperfmonLogLocation()
+
+		coordinatorDir, gpperfmonConf, intermediate, target := setup(t, true)
+		defer testutils.MustRemoveAll(t, coordinatorDir)
+
+		streams := &step.BufferedStreams{}
+		err := hub.UpdateConfFiles(context.Background(), nil, nil, streams, config.StateVersion, hub.ConfUpdateOptions{}, semver.MustParse("6.25.0"), intermediate, target)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		expected := "log_location = " + logDir + "\n"
+		contents := testutils.MustReadFile(t, gpperfmonConf)
+		if contents != expected {
+			t.Errorf("got %q want %q", contents, expected)
+		}
+
+		if strings.Contains(streams.StdoutBuf.String(), "warning: gpperfmon") {
+			t.Errorf("got output %q want no gpperfmon warning", streams.StdoutBuf.String())
+		}
+	})
+
+	t.Run("warns when the configured directory does not exist", func(t *testing.T) {
+		logDir := filepath.Join(stateDir, "gpperfmon-logs")
+
+		hub.SetGpperfmonLogLocation(logDir)
+		defer hub.ResetGpperfmonLogLocation()
+
+		coordinatorDir, gpperfmonConf, intermediate, target := setup(t, true)
+		defer testutils.MustRemoveAll(t, coordinatorDir)
+
+		streams := &step.BufferedStreams{}
+		err := hub.UpdateConfFiles(context.Background(), nil, nil, streams, config.StateVersion, hub.ConfUpdateOptions{}, semver.MustParse("6.25.0"), intermediate, target)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		expected := "warning: gpperfmon log location " + logDir + " does not exist on host coordinator"
+		if !strings.Contains(streams.StdoutBuf.String(), expected) {
+			t.Errorf("got output %q want it to contain %q", streams.StdoutBuf.String(), expected)
+		}
+
+		contents := testutils.MustReadFile(t, gpperfmonConf)
+		if contents != "log_location = "+logDir+"\n" {
+			t.Errorf("got %q want the log location updated", contents)
+		}
+	})
+
+	t.Run("does not warn when gpperfmon is not installed", func(t *testing.T) {
+		hub.SetGpperfmonLogLocation(filepath.Join(stateDir, "gpperfmon-logs"))
+		defer hub.ResetGpperfmonLogLocation()
+
+		coordinatorDir, _, intermediate, target := setup(t, false)
+		defer testutils.MustRemoveAll(t, coordinatorDir)
+
+		streams := &step.BufferedStreams{}
+		err := hub.UpdateConfFiles(context.Background(), nil, nil, streams, config.StateVersion, hub.ConfUpdateOptions{}, semver.MustParse("7.0.0"), intermediate, target)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		testutils.PathMustNotExist(t, filepath.Join(coordinatorDir, "gpperfmon"))
+
+		if strings.Contains(streams.StdoutBuf.String(), "warning: gpperfmon") {
+			t.Errorf("got output %q want no gpperfmon warning", streams.StdoutBuf.String())
+		}
+	})
+
+	for _, location := range []string{"gpperfmon/logs", "/data/../gpperfmon/logs"} {
+		t.Run("errors on the configured directory "+location, func(t *testing.T) {
+			hub.SetGpperfmonLogLocation(location)
+			defer hub.ResetGpperfmonLogLocation()
+
+			coordinatorDir, gpperfmonConf, intermediate, target := setup(t, true)
+			defer testutils.MustRemoveAll(t, coordinatorDir)
+
+			err := hub.UpdateConfFiles(context.Background(), nil, nil, step.DevNullStream, config.StateVersion, hub.ConfUpdateOptions{}, semver.MustParse("6.25.0"), intermediate, target)
+			expected := `gpperfmon log location "` + location + `" is not a clean absolute path`
+			if err == nil || !strings.Contains(err.Error(), expected) {
+				t.Errorf("got error %v want it to contain %q", err, expected)
+			}
+
+			contents := testutils.MustReadFile(t, gpperfmonConf)
+			if contents != original {
+				t.Errorf("got %q want the file unchanged", contents)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/greenplum-db/gpupgrade/idl"
+)
+
+// confHeartbeatTimeout is how long the hub waits for the conf update of an
+// agent to make progress before reporting the host as hung and cancelling
+// its request. This tells a slow host that is still working apart from one
+// whose storage hangs mid-rewrite, which otherwise holds the request open
+// until the conf host timeout with no signal. Zero disables the heartbeat.
+var confHeartbeatTimeout time.Duration = 0
+
+// MinConfHeartbeatTimeout is the shortest heartbeat timeout the hub may be
+// configured with, as the agents are polled four times within it.
+const MinConfHeartbeatTimeout = time.Second
+
+func SetConfHeartbeatTimeout(timeout time.Duration) {
+	confHeartbeatTimeout = timeout
+}
+
+func ResetConfHeartbeatTimeout() {
+	confHeartbeatTimeout = 0
+}
+
+var (
+	ErrHostHung     = errors.New("hung (no heartbeat)")
+	ErrHostTimedOut = errors.New("timed out during active work")
+)
+
+// confProgress counts the conf edits made by this process, and
+// confActiveUpdates the updates in flight, for ConfUpdateHeartbeat.
+var (
+	confProgress      int64
+	confActiveUpdates int32
+)
+
+// ConfUpdateProgress returns the conf edits made so far and the number of
+// updates in progress.
+func ConfUpdateProgress() (int64, int32) {
+	return atomic.LoadInt64(&confProgress), atomic.LoadInt32(&confActiveUpdates)
+}
+
+func recordConfProgress() {
+	atomic.AddInt64(&confProgress, 1)
+}
+
+func beginConfUpdate() func() {
+	atomic.AddInt32(&confActiveUpdates, 1)
+	return func() { atomic.AddInt32(&confActiveUpdates, -1) }
+}
+
+// updateConfigurationWithHeartbeat sends the request to the agent while
+// polling its heartbeat. A host whose progress does not advance within the
+// heartbeat timeout is reported with ErrHostHung, and one that reaches the
+// conf host timeout while making progress with ErrHostTimedOut. Agents
+// predating the heartbeat are waited on as before.
+func updateConfigurationWithHeartbeat(ctx context.Context, conn *idl.Connection, req *idl.UpdateConfigurationRequest) (*idl.UpdateConfigurationReply, error) {
+	if confHeartbeatTimeout <= 0 {
+		return conn.AgentClient.UpdateConfiguration(ctx, req)
+	}
+
+	updateCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		reply *idl.UpdateConfigurationReply
+		err   error
+	}
+
+	done := make(chan result, 1)
+	go func() {
+		reply, err := conn.AgentClient.UpdateConfiguration(updateCtx, req)
+		done <- result{reply, err}
+	}()
+
+	interval := confHeartbeatTimeout / 4
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	heartbeat := true
+	lastBeat := time.Now()
+	lastProgress := int64(-1)
+
+	for {
+		select {
+		case r := <-done:
+			if r.err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return nil, xerrors.Errorf("host %s %w: %v", conn.Hostname, ErrHostTimedOut, r.err)
+			}
+
+			return r.reply, r.err
+
+		case <-ticker.C:
+			if !heartbeat {
+				continue
+			}
+
+			pollCtx, pollCancel := context.WithTimeout(updateCtx, interval)
+			reply, err := conn.AgentClient.ConfUpdateHeartbeat(pollCtx, &idl.ConfUpdateHeartbeatRequest{})
+			pollCancel()
+
+			if status.Code(err) == codes.Unimplemented {
+				heartbeat = false
+				continue
+			}
+
+			if err == nil && reply.GetProgress() != lastProgress {
+				lastProgress = reply.GetProgress()
+				lastBeat = time.Now()
+			}
+
+			if time.Since(lastBeat) > confHeartbeatTimeout {
+				return nil, xerrors.Errorf("host %s %w for %s", conn.Hostname, ErrHostHung, confHeartbeatTimeout)
+			}
+		}
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/idl/mock_idl"
+	"github.com/greenplum-db/gpupgrade/testutils"
+)
+
+func TestConfUpdateHeartbeat(t *testing.T) {
+	intermediate := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: "/data/qddir/seg.HqtFHX54y0o.-1", Port: 50432, Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg.HqtFHX54y0o.1", Port: 50434, Role: greenplum.PrimaryRole},
+	})
+
+	target := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: "/data/qddir/seg-1", Port: 15432, Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg1", Port: 25433, Role: greenplum.PrimaryRole},
+	})
+
+	hub.SetConfHeartbeatTimeout(40 * time.Millisecond)
+	defer hub.ResetConfHeartbeatTimeout()
+
+	// blockUntilCancelled is an update whose storage hangs.
+	blockUntilCancelled := func(ctx context.Context, _ *idl.UpdateConfigurationRequest, _ ...grpc.CallOption) (*idl.UpdateConfigurationReply, error) {
+		<-ctx.Done()
+		return nil, status.Error(codes.Canceled, ctx.Err().Error())
+	}
+
+	// slowUpdate is an update that takes longer than the heartbeat timeout.
+	slowUpdate := func(context.Context, *idl.UpdateConfigurationRequest, ...grpc.CallOption) (*idl.UpdateConfigurationReply, error) {
+		time.Sleep(100 * time.Millisecond)
+		return &idl.UpdateConfigurationReply{}, nil
+	}
+
+	progressing := func() func(context.Context, *idl.ConfUpdateHeartbeatRequest, ...grpc.CallOption) (*idl.ConfUpdateHeartbeatReply, error) {
+		var progress int64
+		return func(context.Context, *idl.ConfUpdateHeartbeatRequest, ...grpc.CallOption) (*idl.ConfUpdateHeartbeatReply, error) {
+			return &idl.ConfUpdateHeartbeatReply{Progress: atomic.AddInt64(&progress, 1), ActiveUpdates: 1}, nil
+		}
+	}
+
+	cases := []struct {
+		name      string
+		update    func(context.Context, *idl.UpdateConfigurationRequest, ...grpc.CallOption) (*idl.UpdateConfigurationReply, error)
+		heartbeat func(context.Context, *idl.ConfUpdateHeartbeatRequest, ...grpc.CallOption) (*idl.ConfUpdateHeartbeatReply, error)
+		timeout   time.Duration
+		expected  error
+	}{
+		{
+			name:   "reports a host without progress as hung",
+			update: blockUntilCancelled,
+			heartbeat: func(context.Context, *idl.ConfUpdateHeartbeatRequest, ...grpc.CallOption) (*idl.ConfUpdateHeartbeatReply, error) {
+				return &idl.ConfUpdateHeartbeatReply{Progress: 3, ActiveUpdates: 1}, nil
+			},
+			expected: hub.ErrHostHung,
+		},
+		{
+			name:      "reports a progressing host reaching the per host timeout as timed out",
+			update:    blockUntilCancelled,
+			heartbeat: progressing(),
+			timeout:   100 * time.Millisecond,
+			expected:  hub.ErrHostTimedOut,
+		},
+		{
+			name:      "waits for a slow host that is making progress",
+			update:    slowUpdate,
+			heartbeat: progressing(),
+		},
+		{
+			name:   "waits for an agent without a heartbeat",
+			update: slowUpdate,
+			heartbeat: func(context.Context, *idl.ConfUpdateHeartbeatRequest, ...grpc.CallOption) (*idl.ConfUpdateHeartbeatReply, error) {
+				return nil, status.Error(codes.Unimplemented, "unknown method ConfUpdateHeartbeat")
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			hub.SetConfHostTimeout(c.timeout)
+			defer hub.ResetConfHostTimeout()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			sdw1 := mock_idl.NewMockAgentClient(ctrl)
+			sdw1.EXPECT().UpdateConfiguration(gomock.Any(), gomock.Any()).DoAndReturn(c.update)
+			sdw1.EXPECT().ConfUpdateHeartbeat(gomock.Any(), gomock.Any()).DoAndReturn(c.heartbeat).AnyTimes()
+
+			err := hub.UpdatePostgresqlConfOnSegments(context.Background(), []*idl.Connection{{AgentClient: sdw1, Hostname: "sdw1"}}, intermediate, target)
+			if c.expected == nil {
+				if err != nil {
+					t.Errorf("unexpected error %+v", err)
+				}
+				return
+			}
+
+			if !errors.Is(err, c.expected) {
+				t.Errorf("got error %#v want %#v", err, c.expected)
+			}
+		})
+	}
+
+	t.Run("the agent reports progress for each edit", func(t *testing.T) {
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		path := filepath.Join(dir, "postgresql.conf")
+		testutils.MustWriteToFile(t, path, "port=5000\n")
+
+		before, _ := hub.ConfUpdateProgress()
+
+		err := hub.UpdateConfigurationFile(context.Background(), []*idl.UpdateFileConfOptions{
+			{Path: path, Pattern: `(^port=)5000`, Replacement: `\16000`, Guc: "port"},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		after, active := hub.ConfUpdateProgress()
+		if after != before+1 {
+			t.Errorf("got progress %d want %d", after, before+1)
+		}
+
+		if active != 0 {
+			t.Errorf("got %d active updates want 0", active)
+		}
+	})
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils/errorlist"
+)
+
+// validateConfHosts errors for each host of the conf update that is neither
+// the coordinator host nor has segments of the target cluster.
+func validateConfHosts(target *greenplum.Cluster, hosts []string) error {
+	known := map[string]bool{target.CoordinatorHostname(): true}
+	for _, host := range AgentHosts(target) {
+		known[host] = true
+	}
+
+	var err error
+	for _, host := range hosts {
+		if !known[host] {
+			err = errorlist.Append(err, xerrors.Errorf("conf update host %s is not a host of the cluster", host))
+		}
+	}
+
+	return err
+}
+
+// confHostIncluded is whether the conf files of the host are updated when
+// the update is limited to hosts.
+func confHostIncluded(hosts []string, hostname string) bool {
+	if len(hosts) == 0 {
+		return true
+	}
+
+	for _, host := range hosts {
+		if host == hostname {
+			return true
+		}
+	}
+
+	return false
+}
+
+// confAgentConns returns the connections to the agents of the hosts whose
+// conf files are updated.
+func confAgentConns(agentConns []*idl.Connection, hosts []string) []*idl.Connection {
+	if len(hosts) == 0 {
+		return agentConns
+	}
+
+	var conns []*idl.Connection
+	for _, conn := range agentConns {
+		if confHostIncluded(hosts, conn.Hostname) {
+			conns = append(conns, conn)
+		}
+	}
+
+	return conns
+}
@@ -0,0 +1,120 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/blang/semver/v4"
+	"github.com/golang/mock/gomock"
+
+	"github.com/greenplum-db/gpupgrade/config"
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/idl/mock_idl"
+	"github.com/greenplum-db/gpupgrade/step"
+	"github.com/greenplum-db/gpupgrade/testutils"
+)
+
+func TestConfHosts(t *testing.T) {
+	stateDir := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, stateDir)
+
+	resetEnv := testutils.SetEnv(t, "GPUPGRADE_HOME", stateDir)
+	defer resetEnv()
+
+	coordinatorDir := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, coordinatorDir)
+
+	coordinatorConf := filepath.Join(coordinatorDir, "postgresql.conf")
+
+	intermediate := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: coordinatorDir, Port: 50432, Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg.HqtFHX54y0o.1", Port: 50434, Role: greenplum.PrimaryRole},
+		{DbID: 3, ContentID: 1, Hostname: "sdw2", DataDir: "/data/dbfast2/seg.HqtFHX54y0o.2", Port: 50435, Role: greenplum.PrimaryRole},
+	})
+	target := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: coordinatorDir, Port: 15432, Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg1", Port: 25433, Role: greenplum.PrimaryRole},
+		{DbID: 3, ContentID: 1, Hostname: "sdw2", DataDir: "/data/dbfast2/seg2", Port: 25434, Role: greenplum.PrimaryRole},
+	})
+
+	version := semver.MustParse("7.0.0")
+
+	t.Run("only updates the conf files of the named hosts", func(t *testing.T) {
+		testutils.MustWriteToFile(t, coordinatorConf, "port=50432\n")
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// sdw1 is not sent any requests
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+
+		sdw2 := mock_idl.NewMockAgentClient(ctrl)
+		sdw2.EXPECT().UpdateConfiguration(gomock.Any(), equivalentConfRequest(&idl.UpdateConfigurationRequest{
+			Options: []*idl.UpdateFileConfOptions{{
+				Path:             "/data/dbfast2/seg2/postgresql.conf",
+				DataDir:          "/data/dbfast2/seg2",
+				Pattern:          `(^[ \t]*port[ \t]*=[ \t]*)50435([^0-9]|$)`,
+				Replacement:      `\125434\2`,
+				Reason:           hub.ReasonPortRewrite,
+				Guc:              "port",
+				DuplicateMatches: idl.UpdateFileConfOptions_rewriteLastMatch,
+				ExpectedValue:    "25434",
+			}},
+		})).Return(&idl.UpdateConfigurationReply{ChangedPaths: []string{"/data/dbfast2/seg2/postgresql.conf"}}, nil)
+
+		agentConns := []*idl.Connection{
+			{AgentClient: sdw1, Hostname: "sdw1"},
+			{AgentClient: sdw2, Hostname: "sdw2"},
+		}
+
+		err := hub.UpdateConfFiles(context.Background(), agentConns, nil, step.DevNullStream, config.StateVersion, hub.ConfUpdateOptions{Hosts: []string{"sdw2"}}, version, intermediate, target)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		contents := testutils.MustReadFile(t, coordinatorConf)
+		if contents != "port=50432\n" {
+			t.Errorf("got %q want the coordinator conf unchanged", contents)
+		}
+	})
+
+	t.Run("updates the coordinator when its host is named", func(t *testing.T) {
+		testutils.MustWriteToFile(t, coordinatorConf, "port=50432\n")
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// neither segment host is sent any requests
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw2 := mock_idl.NewMockAgentClient(ctrl)
+
+		agentConns := []*idl.Connection{
+			{AgentClient: sdw1, Hostname: "sdw1"},
+			{AgentClient: sdw2, Hostname: "sdw2"},
+		}
+
+		err := hub.UpdateConfFiles(context.Background(), agentConns, nil, step.DevNullStream, config.StateVersion, hub.ConfUpdateOptions{Hosts: []string{"coordinator"}}, version, intermediate, target)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		contents := testutils.MustReadFile(t, coordinatorConf)
+		if contents != "port=15432\n" {
+			t.Errorf("got %q want %q", contents, "port=15432\n")
+		}
+	})
+
+	t.Run("errors on hosts that are not in the cluster", func(t *testing.T) {
+		err := hub.UpdateConfFiles(context.Background(), nil, nil, step.DevNullStream, config.StateVersion, hub.ConfUpdateOptions{Hosts: []string{"sdw2", "sdw9"}}, version, intermediate, target)
+		expected := "conf update host sdw9 is not a host of the cluster"
+		if err == nil || err.Error() != expected {
+			t.Errorf("got error %v want %q", err, expected)
+		}
+	})
+}
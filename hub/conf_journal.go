@@ -0,0 +1,328 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
+	"github.com/greenplum-db/gpupgrade/utils/errorlist"
+)
+
+// ConfJournalFileName is the journal of conf edits in the state directory of
+// the hub and of each agent.
+const ConfJournalFileName = "conf-journal.jsonl"
+
+// ConfJournalReportFileName is the report of the journals of every host
+// written to the state directory of the hub after a conf update.
+const ConfJournalReportFileName = "conf-journal-report.json"
+
+// ConfJournalEdit is an option applied to a conf file.
+type ConfJournalEdit struct {
+	Pattern     string `json:"pattern"`
+	Replacement string `json:"replacement"`
+	Reason      string `json:"reason"`
+	// Matches is the number of lines the option changed.
+	Matches int `json:"matches"`
+}
+
+// ConfJournalRecord is the edit of a conf file, appended to the journal
+// before the file is written so that it can be restored to Original even
+// after a crash mid-edit.
+type ConfJournalRecord struct {
+	Hostname string `json:"hostname"`
+	Path     string `json:"path"`
+	// Resolved is the real file of a Path that is a symlink.
+	Resolved string            `json:"resolved,omitempty"`
+	Time     time.Time         `json:"time"`
+	Edits    []ConfJournalEdit `json:"edits"`
+	// Original is the contents of the file before the edit.
+	Original []byte `json:"original"`
+}
+
+// confJournalPath is the journal appended to by each conf file edit. The hub
+// and agents set it to ConfJournalFileName in their state directory when
+// they start. Empty keeps no journal.
+var confJournalPath = ""
+
+func SetConfJournalPath(path string) {
+	confJournalPath = path
+}
+
+func ResetConfJournalPath() {
+	confJournalPath = ""
+}
+
+// confJournalMutex serializes appends since the files are edited
+// concurrently.
+var confJournalMutex sync.Mutex
+
+// journalConfEdit records the edit of the file at path by opts, where before
+// is its contents and lines are the lines changed by each option.
+func journalConfEdit(path string, before []byte, opts []*idl.UpdateFileConfOptions, lines []*idl.ChangedLine) error {
+	if confJournalPath == "" {
+		return nil
+	}
+
+	hostname, err := utils.System.Hostname()
+	if err != nil {
+		return xerrors.Errorf("journal the edit of %s: %w", path, err)
+	}
+
+	matches := make(map[int32]int)
+	for _, line := range lines {
+		matches[line.GetOption()]++
+	}
+
+	resolved, err := resolveConfPath(path)
+	if err != nil {
+		return xerrors.Errorf("journal the edit of %s: %w", path, err)
+	}
+
+	record := ConfJournalRecord{Hostname: hostname, Path: path, Time: utils.System.Now(), Original: before}
+	if resolved != path {
+		record.Resolved = resolved
+	}
+	for i, opt := range opts {
+		record.Edits = append(record.Edits, ConfJournalEdit{
+			Pattern:     opt.GetPattern(),
+			Replacement: opt.GetReplacement(),
+			Reason:      opt.GetReason(),
+			Matches:     matches[int32(i)],
+		})
+	}
+
+	if err := appendConfJournal(confJournalPath, record); err != nil {
+		return xerrors.Errorf("journal the edit of %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// appendConfJournal appends the record as a line of JSON and syncs the
+// journal, along with its directory when the journal is created, so that the
+// record is durable before the file it describes is written.
+func appendConfJournal(journal string, record ConfJournalRecord) (err error) {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return xerrors.Errorf("marshal journal record: %w", err)
+	}
+
+	confJournalMutex.Lock()
+	defer confJournalMutex.Unlock()
+
+	created, err := pathExists(journal)
+	if err != nil {
+		return err
+	}
+	created = !created
+
+	file, err := os.OpenFile(journal, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cErr := file.Close(); cErr != nil {
+			err = errorlist.Append(err, cErr)
+		}
+	}()
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return err
+	}
+
+	if err := file.Sync(); err != nil {
+		return err
+	}
+
+	if created {
+		return syncDir(filepath.Dir(journal))
+	}
+
+	return nil
+}
+
+func syncDir(dir string) (err error) {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cErr := d.Close(); cErr != nil {
+			err = errorlist.Append(err, cErr)
+		}
+	}()
+
+	return d.Sync()
+}
+
+// ReadConfJournalLines returns the lines of the journal of this host. A
+// missing journal has none.
+func ReadConfJournalLines() ([]string, error) {
+	if confJournalPath == "" {
+		return nil, nil
+	}
+
+	contents, err := utils.System.ReadFile(confJournalPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, xerrors.Errorf("read conf journal: %w", err)
+	}
+
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(contents))
+	scanner.Buffer(nil, len(contents)+1)
+	for scanner.Scan() {
+		if scanner.Text() != "" {
+			lines = append(lines, scanner.Text())
+		}
+	}
+
+	return lines, scanner.Err()
+}
+
+// ParseConfJournal parses the lines of a journal. A final line that does not
+// parse was cut short by a crash while appending it, and is left out since
+// the file it describes was not yet written.
+func ParseConfJournal(lines []string) ([]ConfJournalRecord, error) {
+	var records []ConfJournalRecord
+	for i, line := range lines {
+		var record ConfJournalRecord
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			if i == len(lines)-1 {
+				log.Printf("Warning: ignoring the incomplete last record of the conf journal: %v", err)
+				break
+			}
+
+			return nil, xerrors.Errorf("parse record %d of the conf journal: %w", i+1, err)
+		}
+
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// journaledOriginal returns the contents of the file at path before its
+// first journaled edit, which are those before the upgrade.
+func journaledOriginal(path string) ([]byte, bool, error) {
+	lines, err := ReadConfJournalLines()
+	if err != nil {
+		return nil, false, err
+	}
+
+	records, err := ParseConfJournal(lines)
+	if err != nil {
+		return nil, false, err
+	}
+
+	for _, record := range records {
+		if record.Path == path {
+			return record.Original, true, nil
+		}
+	}
+
+	return nil, false, nil
+}
+
+// ConfJournalReport is the journal of every host.
+type ConfJournalReport struct {
+	Records []ConfJournalRecord `json:"records"`
+	// Unavailable are the hosts whose journal could not be read.
+	Unavailable []string `json:"unavailable,omitempty"`
+}
+
+// collectConfJournals returns the journals of the coordinator and of every
+// agent, sorted by time. Hosts whose agent predates the journal, or whose
+// journal cannot be read, are listed as unavailable rather than failing the
+// report.
+func collectConfJournals(ctx context.Context, agentConns []*idl.Connection, target *greenplum.Cluster) ConfJournalReport {
+	var mutex sync.Mutex
+	var report ConfJournalReport
+
+	add := func(hostname string, lines []string, err error) {
+		var records []ConfJournalRecord
+		if err == nil {
+			records, err = ParseConfJournal(lines)
+		}
+
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		if err != nil {
+			log.Printf("Warning: not reporting the conf journal of host %s: %v", hostname, err)
+			report.Unavailable = append(report.Unavailable, hostname)
+			return
+		}
+
+		report.Records = append(report.Records, records...)
+	}
+
+	// the journal of the coordinator is that of the agent on its host when
+	// there is one
+	if _, ok := coordinatorConfConn(agentConns, target.CoordinatorHostname()).AgentClient.(localConfAgent); ok {
+		lines, err := ReadConfJournalLines()
+		add(target.CoordinatorHostname(), lines, err)
+	}
+
+	ExecuteRPCContext(ctx, agentConns, func(ctx context.Context, conn *idl.Connection) error {
+		reply, err := conn.AgentClient.ReadConfJournal(ctx, &idl.ReadConfJournalRequest{})
+		if status.Code(err) == codes.Unimplemented {
+			err = xerrors.New("the agent does not keep a conf journal")
+		}
+
+		add(conn.Hostname, reply.GetRecords(), err)
+		return nil
+	})
+
+	sort.SliceStable(report.Records, func(i, j int) bool {
+		return report.Records[i].Time.Before(report.Records[j].Time)
+	})
+	sort.Strings(report.Unavailable)
+
+	return report
+}
+
+// writeConfJournalReport writes the journals of every host to
+// ConfJournalReportFileName in the state directory when the journal is kept.
+func writeConfJournalReport(ctx context.Context, agentConns []*idl.Connection, target *greenplum.Cluster, out io.Writer) error {
+	if confJournalPath == "" {
+		return nil
+	}
+
+	report := collectConfJournals(ctx, agentConns, target)
+	contents, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return xerrors.Errorf("marshal conf journal report: %w", err)
+	}
+
+	path := filepath.Join(utils.GetStateDir(), ConfJournalReportFileName)
+	if err := utils.AtomicallyWrite(path, contents); err != nil {
+		return xerrors.Errorf("write conf journal report: %w", err)
+	}
+
+	fmt.Fprintf(out, "wrote the journal of %d conf file edits to %s\n", len(report.Records), path)
+	return nil
+}
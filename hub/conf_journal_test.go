@@ -0,0 +1,210 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blang/semver/v4"
+	"github.com/golang/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/greenplum-db/gpupgrade/config"
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/idl/mock_idl"
+	"github.com/greenplum-db/gpupgrade/step"
+	"github.com/greenplum-db/gpupgrade/testutils"
+	"github.com/greenplum-db/gpupgrade/utils"
+)
+
+func TestConfJournal(t *testing.T) {
+	dir := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, dir)
+
+	journal := filepath.Join(dir, hub.ConfJournalFileName)
+	hub.SetConfJournalPath(journal)
+	defer hub.ResetConfJournalPath()
+
+	path := filepath.Join(dir, "postgresql.conf")
+	opts := []*idl.UpdateFileConfOptions{
+		{Path: path, Pattern: `^(port=)5000$`, Replacement: `\16000`, Reason: hub.ReasonPortRewrite, AllowNoMatch: true},
+		{Path: path, Pattern: `^(archive_mode=)on$`, Replacement: `\1off`, AllowNoMatch: true},
+	}
+
+	readJournal := func(t *testing.T) []hub.ConfJournalRecord {
+		t.Helper()
+
+		lines, err := hub.ReadConfJournalLines()
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		records, err := hub.ParseConfJournal(lines)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		return records
+	}
+
+	t.Run("records the edits of each changed file", func(t *testing.T) {
+		defer testutils.MustRemoveAll(t, journal)
+		testutils.MustWriteToFile(t, path, "port=5000\n")
+
+		hostname, err := utils.System.Hostname()
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		_, err = hub.UpdateConfigurationFileReply(context.Background(), opts)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		records := readJournal(t)
+		if len(records) != 1 {
+			t.Fatalf("got %d records want 1", len(records))
+		}
+
+		record := records[0]
+		if record.Hostname != hostname || record.Path != path || string(record.Original) != "port=5000\n" || record.Time.IsZero() {
+			t.Errorf("got record %+v", record)
+		}
+
+		expected := []hub.ConfJournalEdit{
+			{Pattern: `^(port=)5000$`, Replacement: `\16000`, Reason: hub.ReasonPortRewrite, Matches: 1},
+			{Pattern: `^(archive_mode=)on$`, Replacement: `\1off`, Matches: 0},
+		}
+		if !reflect.DeepEqual(record.Edits, expected) {
+			t.Errorf("got edits %+v want %+v", record.Edits, expected)
+		}
+
+		// a file already at its target is not edited again
+		_, err = hub.UpdateConfigurationFileReply(context.Background(), opts)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		if records := readJournal(t); len(records) != 1 {
+			t.Errorf("got %d records want 1", len(records))
+		}
+	})
+
+	t.Run("ignores a last record cut short by a crash", func(t *testing.T) {
+		records, err := hub.ParseConfJournal([]string{`{"path":"/data/qddir/postgresql.conf"}`, `{"path":"/data/qd`})
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		if len(records) != 1 || records[0].Path != "/data/qddir/postgresql.conf" {
+			t.Errorf("got records %+v want only the complete one", records)
+		}
+	})
+
+	t.Run("errors on a malformed record before the last", func(t *testing.T) {
+		_, err := hub.ParseConfJournal([]string{`{"path":`, `{"path":"/data/qddir/postgresql.conf"}`})
+		expected := "parse record 1 of the conf journal"
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("got error %v want it to contain %q", err, expected)
+		}
+	})
+
+	t.Run("restores a file without a backup from the journal", func(t *testing.T) {
+		defer testutils.MustRemoveAll(t, journal)
+		testutils.MustWriteToFile(t, path, "port=5000\n")
+
+		_, err := hub.UpdateConfigurationFileReply(context.Background(), opts)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+		testutils.MustRemoveAll(t, path+hub.BackupSuffix)
+
+		restored, err := hub.RestoreConfBackups([]string{path}, "")
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		if !reflect.DeepEqual(restored, []string{path}) {
+			t.Errorf("got restored %q want %q", restored, []string{path})
+		}
+
+		contents := testutils.MustReadFile(t, path)
+		if contents != "port=5000\n" {
+			t.Errorf("got %q want %q", contents, "port=5000\n")
+		}
+	})
+}
+
+func TestConfJournalReport(t *testing.T) {
+	stateDir := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, stateDir)
+
+	resetEnv := testutils.SetEnv(t, "GPUPGRADE_HOME", stateDir)
+	defer resetEnv()
+
+	hub.SetConfJournalPath(filepath.Join(stateDir, hub.ConfJournalFileName))
+	defer hub.ResetConfJournalPath()
+
+	coordinatorDir := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, coordinatorDir)
+
+	path := filepath.Join(coordinatorDir, "postgresql.conf")
+	testutils.MustWriteToFile(t, path, "port=50432\n")
+
+	intermediate := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "cdw", DataDir: coordinatorDir, Port: 50432, Role: greenplum.PrimaryRole},
+	})
+	target := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "cdw", DataDir: coordinatorDir, Port: 15432, Role: greenplum.PrimaryRole},
+	})
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	sdw1Record := hub.ConfJournalRecord{Hostname: "sdw1", Path: "/data/dbfast1/seg1/postgresql.conf", Time: time.Now().Add(time.Hour).UTC()}
+	line, err := json.Marshal(sdw1Record)
+	if err != nil {
+		t.Fatalf("unexpected error %+v", err)
+	}
+
+	// neither host has segments so they are only asked for their journal
+	sdw1 := mock_idl.NewMockAgentClient(ctrl)
+	sdw1.EXPECT().ReadConfJournal(gomock.Any(), gomock.Any()).Return(&idl.ReadConfJournalReply{Records: []string{string(line)}}, nil)
+
+	sdw2 := mock_idl.NewMockAgentClient(ctrl)
+	sdw2.EXPECT().ReadConfJournal(gomock.Any(), gomock.Any()).Return(nil, status.Error(codes.Unimplemented, "unknown method ReadConfJournal"))
+
+	agentConns := []*idl.Connection{
+		{AgentClient: sdw1, Hostname: "sdw1"},
+		{AgentClient: sdw2, Hostname: "sdw2"},
+	}
+
+	err = hub.UpdateConfFiles(context.Background(), agentConns, nil, step.DevNullStream, config.StateVersion, hub.ConfUpdateOptions{}, semver.MustParse("7.0.0"), intermediate, target)
+	if err != nil {
+		t.Fatalf("unexpected error %+v", err)
+	}
+
+	var report hub.ConfJournalReport
+	contents := testutils.MustReadFile(t, filepath.Join(stateDir, hub.ConfJournalReportFileName))
+	if err := json.Unmarshal([]byte(contents), &report); err != nil {
+		t.Fatalf("unexpected error %+v", err)
+	}
+
+	if len(report.Records) != 2 || report.Records[0].Path != path || !reflect.DeepEqual(report.Records[1], sdw1Record) {
+		t.Errorf("got records %+v want the coordinator record followed by that of sdw1", report.Records)
+	}
+
+	if !reflect.DeepEqual(report.Unavailable, []string{"sdw2"}) {
+		t.Errorf("got unavailable hosts %q want %q", report.Unavailable, []string{"sdw2"})
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"bytes"
+	"strings"
+)
+
+// lineEnding returns the dominant line ending of conf file contents. Files
+// written on Windows end their lines with "\r\n", which is kept when
+// rewriting them rather than assuming "\n". A file mixing both uses the one
+// ending most of its lines, preferring "\n" on a tie.
+func lineEnding(contents []byte) []byte {
+	lines := bytes.Count(contents, []byte("\n"))
+	windows := bytes.Count(contents, []byte("\r\n"))
+	if windows > lines-windows {
+		return []byte("\r\n")
+	}
+
+	return []byte("\n")
+}
+
+// trimTrailingLineEndings returns the contents without their trailing line
+// endings, of either kind, along with the endings removed.
+func trimTrailingLineEndings(contents []byte) ([]byte, [][]byte) {
+	var endings [][]byte
+	for bytes.HasSuffix(contents, []byte("\n")) {
+		ending := []byte("\n")
+		if bytes.HasSuffix(contents, []byte("\r\n")) {
+			ending = []byte("\r\n")
+		}
+
+		contents = contents[:len(contents)-len(ending)]
+		endings = append([][]byte{ending}, endings...)
+	}
+
+	return contents, endings
+}
+
+// confLines splits conf file contents into lines without their line endings.
+func confLines(contents string) []string {
+	lines := strings.Split(strings.TrimSuffix(contents, "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSuffix(line, "\r")
+	}
+
+	return lines
+}
@@ -0,0 +1,164 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/testutils"
+)
+
+func TestConfLineEndings(t *testing.T) {
+	portOption := func(path string) *idl.UpdateFileConfOptions {
+		return &idl.UpdateFileConfOptions{Path: path, Pattern: `(^[ \t]*port[ \t]*=[ \t]*)5000([^0-9]|$)`, Replacement: `\16000\2`}
+	}
+
+	cases := []struct {
+		name     string
+		contents string
+		expected string
+	}{
+		{
+			name:     "keeps windows line endings",
+			contents: "listen_addresses='*'\r\nport=5000\r\n",
+			expected: "listen_addresses='*'\r\nport=6000\r\n",
+		},
+		{
+			name:     "ends a windows file with a single windows line ending",
+			contents: "listen_addresses='*'\r\nport=5000\r\n\r\n",
+			expected: "listen_addresses='*'\r\nport=6000\r\n",
+		},
+		{
+			name:     "adds a windows line ending to a windows file without one",
+			contents: "listen_addresses='*'\r\nport=5000",
+			expected: "listen_addresses='*'\r\nport=6000\r\n",
+		},
+		{
+			name:     "keeps unix line endings",
+			contents: "listen_addresses='*'\nport=5000\n",
+			expected: "listen_addresses='*'\nport=6000\n",
+		},
+		{
+			name:     "keeps the line ending of each line of a file mixing them",
+			contents: "listen_addresses='*'\nport=5000\r\nmax_connections=100\n",
+			expected: "listen_addresses='*'\nport=6000\r\nmax_connections=100\n",
+		},
+		{
+			name:     "keeps the last line ending of a file mixing them",
+			contents: "listen_addresses='*'\r\nmax_connections=100\r\nport=5000\n",
+			expected: "listen_addresses='*'\r\nmax_connections=100\r\nport=6000\n",
+		},
+		{
+			name:     "ends a file mixing them with its dominant line ending",
+			contents: "listen_addresses='*'\r\nmax_connections=100\nport=5000\r\n\n",
+			expected: "listen_addresses='*'\r\nmax_connections=100\nport=6000\r\n",
+		},
+		{
+			name:     "adds the dominant line ending to a file mixing them without one",
+			contents: "listen_addresses='*'\r\nmax_connections=100\r\nshared_buffers=128MB\nport=5000",
+			expected: "listen_addresses='*'\r\nmax_connections=100\r\nshared_buffers=128MB\nport=6000\r\n",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			dir := testutils.GetTempDir(t, "")
+			defer testutils.MustRemoveAll(t, dir)
+
+			path := filepath.Join(dir, "postgresql.conf")
+			testutils.MustWriteToFile(t, path, c.contents)
+
+			err := hub.UpdateConfigurationFile(context.Background(), []*idl.UpdateFileConfOptions{portOption(path)})
+			if err != nil {
+				t.Fatalf("unexpected error %+v", err)
+			}
+
+			contents := testutils.MustReadFile(t, path)
+			if contents != c.expected {
+				t.Errorf("got %q want %q", contents, c.expected)
+			}
+		})
+	}
+
+	t.Run("counts the expected matches of end anchored patterns in windows files", func(t *testing.T) {
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		path := filepath.Join(dir, "postgresql.conf")
+		testutils.MustWriteToFile(t, path, "port=5000\r\nmax_connections=5000\r\n")
+
+		err := hub.UpdateConfigurationFile(context.Background(), []*idl.UpdateFileConfOptions{{
+			Path:            path,
+			Pattern:         `^(max_connections=)[0-9]+$`,
+			Replacement:     `\1500`,
+			ExpectedMatches: 1,
+		}})
+		if err != nil {
+			t.Errorf("unexpected error %+v", err)
+		}
+
+		contents := testutils.MustReadFile(t, path)
+		expected := "port=5000\r\nmax_connections=500\r\n"
+		if contents != expected {
+			t.Errorf("got %q want %q", contents, expected)
+		}
+	})
+
+	t.Run("appends a missing setting with the dominant line ending", func(t *testing.T) {
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		path := filepath.Join(dir, "postgresql.conf")
+		testutils.MustWriteToFile(t, path, "port=5000\r\nmax_connections=100\r\nshared_buffers=128MB\n")
+
+		err := hub.UpdateConfigurationFile(context.Background(), []*idl.UpdateFileConfOptions{{
+			Path:            path,
+			Pattern:         `^(gp_contentid=).*$`,
+			Replacement:     `\10`,
+			Guc:             "gp_contentid",
+			AppendIfMissing: "gp_contentid=0",
+		}})
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		contents := testutils.MustReadFile(t, path)
+		expected := "port=5000\r\nmax_connections=100\r\nshared_buffers=128MB\ngp_contentid=0\r\n"
+		if contents != expected {
+			t.Errorf("got %q want %q", contents, expected)
+		}
+	})
+
+	t.Run("keeps the file mode", func(t *testing.T) {
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		path := filepath.Join(dir, "postgresql.conf")
+		testutils.MustWriteToFile(t, path, "port=5000")
+		if err := os.Chmod(path, 0640); err != nil {
+			t.Fatal(err)
+		}
+
+		err := hub.UpdateConfigurationFile(context.Background(), []*idl.UpdateFileConfOptions{portOption(path)})
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		for _, p := range []string{path, path + hub.BackupSuffix} {
+			info, err := os.Stat(p)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if info.Mode().Perm() != 0640 {
+				t.Errorf("%s has mode %v want %v", p, info.Mode().Perm(), os.FileMode(0640))
+			}
+		}
+	})
+}
@@ -0,0 +1,114 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/upgrade"
+	"github.com/greenplum-db/gpupgrade/utils"
+	"github.com/greenplum-db/gpupgrade/utils/errorlist"
+)
+
+const ConfLockFileName = "conf-update.lock"
+
+// ErrConfLockHeld is returned when another conf operation holds the lock.
+var ErrConfLockHeld = errors.New("another conf operation is in progress")
+
+type confLockHolder struct {
+	PID   int    `json:"pid"`
+	RunID string `json:"runID"`
+}
+
+// AcquireConfLock ensures only one conf-update operation runs at a time by
+// exclusively creating a lock file in the state directory. A lock left behind
+// by a process that no longer exists is taken over. The returned function
+// releases the lock.
+func AcquireConfLock() (release func() error, err error) {
+	path := filepath.Join(utils.GetStateDir(), ConfLockFileName)
+	holder := confLockHolder{PID: os.Getpid(), RunID: upgrade.NewID()}
+
+	contents, err := json.Marshal(holder)
+	if err != nil {
+		return nil, xerrors.Errorf("marshal conf lock: %w", err)
+	}
+
+	err = createConfLock(path, contents)
+	if errors.Is(err, fs.ErrExist) {
+		existing, rErr := readConfLock(path)
+		if rErr != nil {
+			return nil, rErr
+		}
+
+		if processExists(existing.PID) {
+			return nil, xerrors.Errorf("%w (pid %d, run %s). If no other gpupgrade is running remove %q and retry.",
+				ErrConfLockHeld, existing.PID, existing.RunID, path)
+		}
+
+		// the holder exited without releasing the lock
+		if rmErr := os.Remove(path); rmErr != nil {
+			return nil, xerrors.Errorf("remove stale conf lock: %w", rmErr)
+		}
+
+		err = createConfLock(path, contents)
+	}
+
+	if err != nil {
+		return nil, xerrors.Errorf("acquire conf lock: %w", err)
+	}
+
+	return func() error {
+		return os.Remove(path)
+	}, nil
+}
+
+func createConfLock(path string, contents []byte) (err error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cErr := file.Close(); cErr != nil {
+			err = errorlist.Append(err, cErr)
+		}
+	}()
+
+	_, err = file.Write(contents)
+	return err
+}
+
+func readConfLock(path string) (confLockHolder, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return confLockHolder{}, xerrors.Errorf("read conf lock: %w", err)
+	}
+
+	var holder confLockHolder
+	if err := json.Unmarshal(contents, &holder); err != nil {
+		return confLockHolder{}, xerrors.Errorf("unmarshal conf lock %q: %w", path, err)
+	}
+
+	return holder, nil
+}
+
+func processExists(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+
+	err = process.Signal(syscall.Signal(0))
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
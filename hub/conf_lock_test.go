@@ -0,0 +1,104 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/blang/semver/v4"
+
+	"github.com/greenplum-db/gpupgrade/config"
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/step"
+	"github.com/greenplum-db/gpupgrade/testutils"
+)
+
+func TestAcquireConfLock(t *testing.T) {
+	stateDir := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, stateDir)
+
+	resetEnv := testutils.SetEnv(t, "GPUPGRADE_HOME", stateDir)
+	defer resetEnv()
+
+	lockPath := filepath.Join(stateDir, hub.ConfLockFileName)
+
+	t.Run("allows only one holder at a time", func(t *testing.T) {
+		release, err := hub.AcquireConfLock()
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		_, err = hub.AcquireConfLock()
+		if !errors.Is(err, hub.ErrConfLockHeld) {
+			t.Errorf("got error %#v want %#v", err, hub.ErrConfLockHeld)
+		}
+
+		expected := fmt.Sprintf("pid %d, run ", os.Getpid())
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("expected error %v to contain %q", err, expected)
+		}
+
+		if err := release(); err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		testutils.PathMustNotExist(t, lockPath)
+
+		release, err = hub.AcquireConfLock()
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		if err := release(); err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+	})
+
+	t.Run("takes over a lock left by a process that exited", func(t *testing.T) {
+		cmd := exec.Command("true")
+		if err := cmd.Run(); err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		testutils.MustWriteToFile(t, lockPath, fmt.Sprintf(`{"pid": %d, "runID": "stale"}`, cmd.Process.Pid))
+
+		release, err := hub.AcquireConfLock()
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		if err := release(); err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+	})
+
+	t.Run("UpdateConfFiles errors when another conf operation is in progress", func(t *testing.T) {
+		release, err := hub.AcquireConfLock()
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+		defer func() {
+			if err := release(); err != nil {
+				t.Errorf("unexpected error %+v", err)
+			}
+		}()
+
+		cluster := hub.MustCreateCluster(t, greenplum.SegConfigs{
+			{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: "/data/qddir/seg-1", Port: 15432, Role: greenplum.PrimaryRole},
+		})
+
+		err = hub.UpdateConfFiles(context.Background(), nil, nil, step.DevNullStream, config.StateVersion, hub.ConfUpdateOptions{}, semver.MustParse("7.0.0"), cluster, cluster)
+		if !errors.Is(err, hub.ErrConfLockHeld) {
+			t.Errorf("got error %#v want %#v", err, hub.ErrConfLockHeld)
+		}
+	})
+}
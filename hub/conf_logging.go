@@ -0,0 +1,75 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"log"
+
+	"github.com/greenplum-db/gpupgrade/idl"
+)
+
+// confOptionChanged is whether the option at index i of the request changed
+// its file. Agents predating the changed lines only report the files changed,
+// in which case every option of a changed file is taken to have changed it.
+func confOptionChanged(i int, opt *idl.UpdateFileConfOptions, reply *idl.UpdateConfigurationReply) bool {
+	changedPath := false
+	for _, path := range reply.GetChangedPaths() {
+		changedPath = changedPath || path == opt.GetPath()
+	}
+
+	if !changedPath {
+		return false
+	}
+
+	if len(reply.GetChangedLines()) == 0 {
+		return true
+	}
+
+	for _, line := range reply.GetChangedLines() {
+		if int(line.GetOption()) == i {
+			return true
+		}
+	}
+
+	return false
+}
+
+// LogConfOptions logs at debug level the outcome of each option of a conf
+// update, as the agent does when the hub asks for debug logging.
+func LogConfOptions(opts []*idl.UpdateFileConfOptions, reply *idl.UpdateConfigurationReply) {
+	for i, opt := range opts {
+		log.Printf("Debug: conf edit path=%s reason=%s pattern=%q changed=%t",
+			opt.GetPath(), opt.GetReason(), opt.GetPattern(), confOptionChanged(i, opt, reply))
+	}
+}
+
+// logConfEdits logs at debug level the outcome of each edit of a host when
+// debug is set, with the content ID of the segment it was made for so that it
+// can be correlated with gp_segment_configuration, and then the counts of the
+// host.
+func logConfEdits(hostname string, edits ConfPlan, reply *idl.UpdateConfigurationReply, debug bool) {
+	if debug {
+		for i, edit := range edits {
+			opt := edit.Option
+			changed := confOptionChanged(i, opt, reply)
+
+			if edit.Role == "" {
+				log.Printf("Debug: conf edit host=%s path=%s reason=%s pattern=%q changed=%t",
+					hostname, opt.GetPath(), opt.GetReason(), opt.GetPattern(), changed)
+				continue
+			}
+
+			log.Printf("Debug: conf edit host=%s content=%d role=%s path=%s reason=%s pattern=%q changed=%t",
+				hostname, edit.ContentID, edit.Role, opt.GetPath(), opt.GetReason(), opt.GetPattern(), changed)
+		}
+	}
+
+	files := make(map[string]bool)
+	for _, edit := range edits {
+		files[edit.Option.GetPath()] = true
+	}
+
+	log.Printf("conf update host=%s edits=%d files=%d changed=%d skipped=%d",
+		hostname, len(edits), len(files), len(reply.GetChangedPaths()), len(reply.GetSkipped()))
+}
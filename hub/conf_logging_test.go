@@ -0,0 +1,120 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/blang/semver/v4"
+	"github.com/golang/mock/gomock"
+	"google.golang.org/grpc"
+
+	"github.com/greenplum-db/gpupgrade/config"
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/idl/mock_idl"
+	"github.com/greenplum-db/gpupgrade/step"
+	"github.com/greenplum-db/gpupgrade/testutils"
+	"github.com/greenplum-db/gpupgrade/testutils/testlog"
+)
+
+func TestConfDebugLogging(t *testing.T) {
+	stateDir := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, stateDir)
+
+	resetEnv := testutils.SetEnv(t, "GPUPGRADE_HOME", stateDir)
+	defer resetEnv()
+
+	coordinatorDir := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, coordinatorDir)
+
+	intermediate := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: coordinatorDir, Port: 50432, Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg.HqtFHX54y0o.1", Port: 50434, Role: greenplum.PrimaryRole},
+		{DbID: 3, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast_mirror1/seg.HqtFHX54y0o.1", Port: 50435, Role: greenplum.MirrorRole},
+	})
+	target := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: coordinatorDir, Port: 15432, Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg1", Port: 25433, Role: greenplum.PrimaryRole},
+		{DbID: 3, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast_mirror1/seg1", Port: 25434, Role: greenplum.MirrorRole},
+	})
+
+	pattern := `(^[ \t]*port[ \t]*=[ \t]*)%d([^0-9]|$)`
+
+	// the mirror is edited first, and only the primary is changed
+	reply := &idl.UpdateConfigurationReply{
+		ChangedPaths: []string{"/data/dbfast1/seg1/postgresql.conf"},
+		ChangedLines: []*idl.ChangedLine{{Option: 1, Path: "/data/dbfast1/seg1/postgresql.conf", OldLine: "port=50434", NewLine: "port=25433"}},
+	}
+
+	t.Run("logs each edit with its segment when enabled", func(t *testing.T) {
+		log := testlog.SetupTestLogger()
+
+		testutils.MustWriteToFile(t, filepath.Join(coordinatorDir, "postgresql.conf"), "port=50432\n")
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw1.EXPECT().UpdateConfiguration(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, req *idl.UpdateConfigurationRequest, opts ...grpc.CallOption) (*idl.UpdateConfigurationReply, error) {
+				if !req.GetDebugLogging() {
+					t.Errorf("expected the agent to be asked for debug logging")
+				}
+				return reply, nil
+			}).Times(2)
+
+		opts := hub.ConfUpdateOptions{DebugLogging: true}
+		err := hub.UpdateConfFiles(context.Background(), []*idl.Connection{{AgentClient: sdw1, Hostname: "sdw1"}}, nil, step.DevNullStream, config.StateVersion, opts, semver.MustParse("7.0.0"), intermediate, target)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		testlog.VerifyLogContains(t, log, fmt.Sprintf("Debug: conf edit host=sdw1 content=0 role=mirror path=/data/dbfast_mirror1/seg1/postgresql.conf reason=port-rewrite pattern=%q changed=false", fmt.Sprintf(pattern, 50434)))
+		testlog.VerifyLogContains(t, log, fmt.Sprintf("Debug: conf edit host=sdw1 content=0 role=primary path=/data/dbfast1/seg1/postgresql.conf reason=port-rewrite pattern=%q changed=true", fmt.Sprintf(pattern, 50434)))
+		testlog.VerifyLogContains(t, log, "conf update host=sdw1 edits=2 files=2 changed=1 skipped=0")
+	})
+
+	t.Run("logs only the summary of each host by default", func(t *testing.T) {
+		log := testlog.SetupTestLogger()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw1.EXPECT().UpdateConfiguration(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, req *idl.UpdateConfigurationRequest, opts ...grpc.CallOption) (*idl.UpdateConfigurationReply, error) {
+				if req.GetDebugLogging() {
+					t.Errorf("expected the agent not to be asked for debug logging")
+				}
+				return reply, nil
+			})
+
+		err := hub.UpdatePostgresqlConfOnSegments(context.Background(), []*idl.Connection{{AgentClient: sdw1, Hostname: "sdw1"}}, intermediate, target)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		testlog.VerifyLogDoesNotContain(t, log, "Debug:")
+		testlog.VerifyLogContains(t, log, "conf update host=sdw1 edits=2 files=2 changed=1 skipped=0")
+	})
+
+	t.Run("takes every option of a changed file as changed without changed lines", func(t *testing.T) {
+		log := testlog.SetupTestLogger()
+
+		opts := []*idl.UpdateFileConfOptions{
+			{Path: "/data/dbfast1/seg1/postgresql.conf", Pattern: "a", Reason: hub.ReasonPortRewrite},
+			{Path: "/data/dbfast1/seg1/postgresql.auto.conf", Pattern: "b", Reason: hub.ReasonConninfoRewrite},
+		}
+
+		hub.LogConfOptions(opts, &idl.UpdateConfigurationReply{ChangedPaths: []string{"/data/dbfast1/seg1/postgresql.conf"}})
+
+		testlog.VerifyLogContains(t, log, `Debug: conf edit path=/data/dbfast1/seg1/postgresql.conf reason=port-rewrite pattern="a" changed=true`)
+		testlog.VerifyLogContains(t, log, `Debug: conf edit path=/data/dbfast1/seg1/postgresql.auto.conf reason=primary-conninfo-rewrite pattern="b" changed=false`)
+	})
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"golang.org/x/xerrors"
+)
+
+// ConfOrder is whether UpdateConfFiles rewrites the conf files of the
+// coordinator and standby before or after those of the segments, so that the
+// order can match how the cluster is started and recovered. Operator provided
+// edits are always made last.
+//
+// Until the update completes the rewritten instances expect the target ports
+// while the rest still have the intermediate ones. With coordinator-first an
+// interrupted update leaves a coordinator pointing at segments whose conf
+// files have not been rewritten. With segments-first the segments are ready
+// before the coordinator, which suits starting the segments first, but an
+// interrupted update leaves a coordinator that can no longer reach them.
+type ConfOrder string
+
+const (
+	ConfOrderCoordinatorFirst ConfOrder = "coordinator-first"
+	ConfOrderSegmentsFirst    ConfOrder = "segments-first"
+)
+
+var confOrder = ConfOrderCoordinatorFirst
+
+func SetConfOrder(order ConfOrder) {
+	confOrder = order
+}
+
+func ResetConfOrder() {
+	confOrder = ConfOrderCoordinatorFirst
+}
+
+func (o ConfOrder) Validate() error {
+	switch o {
+	case ConfOrderCoordinatorFirst, ConfOrderSegmentsFirst:
+		return nil
+	default:
+		return xerrors.Errorf("unknown conf update order %q, expected %q or %q", o, ConfOrderCoordinatorFirst, ConfOrderSegmentsFirst)
+	}
+}
+
+// phases returns the phases of UpdateConfFiles in the order they are run.
+func (o ConfOrder) phases() []int {
+	if o == ConfOrderSegmentsFirst {
+		return []int{confPhaseSegmentPostgresqlConf, confPhaseSegmentRecoveryConf, confPhaseSegmentPgHbaConf, confPhaseCoordinator, confPhaseStandby, confPhaseCustom}
+	}
+
+	return []int{confPhaseCoordinator, confPhaseStandby, confPhaseSegmentPostgresqlConf, confPhaseSegmentRecoveryConf, confPhaseSegmentPgHbaConf, confPhaseCustom}
+}
+
+// remaining returns the phases still to run after the completed phase.
+func (o ConfOrder) remaining(completed int) []int {
+	phases := o.phases()
+	for i, phase := range phases {
+		if phase == completed {
+			return phases[i+1:]
+		}
+	}
+
+	return phases
+}
+
+// digestConfOrder returns the conf order to digest, or an empty string for
+// the default order so that the digest of a default update is unchanged.
+func digestConfOrder() ConfOrder {
+	if confOrder == ConfOrderCoordinatorFirst {
+		return ""
+	}
+
+	return confOrder
+}
@@ -0,0 +1,133 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/blang/semver/v4"
+	"github.com/golang/mock/gomock"
+
+	"github.com/greenplum-db/gpupgrade/config"
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/idl/mock_idl"
+	"github.com/greenplum-db/gpupgrade/step"
+	"github.com/greenplum-db/gpupgrade/testutils"
+)
+
+func TestConfOrder(t *testing.T) {
+	stateDir := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, stateDir)
+
+	resetEnv := testutils.SetEnv(t, "GPUPGRADE_HOME", stateDir)
+	defer resetEnv()
+
+	coordinatorDir := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, coordinatorDir)
+
+	coordinatorConf := filepath.Join(coordinatorDir, "postgresql.conf")
+
+	intermediate := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: coordinatorDir, Port: 50432, Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: -1, Hostname: "standby", DataDir: "/data/standby.HqtFHX54y0o", Port: 50433, Role: greenplum.MirrorRole},
+		{DbID: 3, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg.HqtFHX54y0o.1", Port: 50434, Role: greenplum.PrimaryRole},
+		{DbID: 4, ContentID: 0, Hostname: "sdw2", DataDir: "/data/dbfast_mirror1/seg.HqtFHX54y0o.1", Port: 50435, Role: greenplum.MirrorRole},
+	})
+
+	target := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: coordinatorDir, Port: 15432, Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: -1, Hostname: "standby", DataDir: "/data/standby", Port: 16432, Role: greenplum.MirrorRole},
+		{DbID: 3, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg1", Port: 25433, Role: greenplum.PrimaryRole},
+		{DbID: 4, ContentID: 0, Hostname: "sdw2", DataDir: "/data/dbfast_mirror1/seg1", Port: 25434, Role: greenplum.MirrorRole},
+	})
+
+	version := semver.MustParse("7.0.0")
+
+	t.Run("rejects an unknown order", func(t *testing.T) {
+		hub.SetConfOrder("random")
+		defer hub.ResetConfOrder()
+
+		testutils.MustWriteToFile(t, coordinatorConf, "port=50432\n")
+
+		err := hub.UpdateConfFiles(context.Background(), nil, nil, step.DevNullStream, config.StateVersion, hub.ConfUpdateOptions{}, version, intermediate, target)
+		expected := `unknown conf update order "random", expected "coordinator-first" or "segments-first"`
+		if err == nil || err.Error() != expected {
+			t.Errorf("got error %v want %q", err, expected)
+		}
+
+		contents := testutils.MustReadFile(t, coordinatorConf)
+		if contents != "port=50432\n" {
+			t.Errorf("expected %q to be unchanged, got %q", coordinatorConf, contents)
+		}
+	})
+
+	t.Run("updates the segments before the coordinator and resumes in that order", func(t *testing.T) {
+		hub.SetConfOrder(hub.ConfOrderSegmentsFirst)
+		defer hub.ResetConfOrder()
+
+		testutils.MustWriteToFile(t, coordinatorConf, "port=50432\n")
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// the recovery conf of the mirror on sdw2 fails after the
+		// postgresql.conf of both segments was updated
+		expected := errors.New("connection reset")
+		standby := mock_idl.NewMockAgentClient(ctrl)
+
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw1.EXPECT().UpdateConfiguration(gomock.Any(), gomock.Any()).Return(&idl.UpdateConfigurationReply{}, nil).Times(1)
+
+		sdw2 := mock_idl.NewMockAgentClient(ctrl)
+		sdw2.EXPECT().UpdateConfiguration(gomock.Any(), gomock.Any()).Return(&idl.UpdateConfigurationReply{}, nil).Times(1)
+		sdw2.EXPECT().UpdateConfiguration(gomock.Any(), gomock.Any()).Return(nil, expected).Times(1)
+
+		agentConns := []*idl.Connection{
+			{AgentClient: standby, Hostname: "standby"},
+			{AgentClient: sdw1, Hostname: "sdw1"},
+			{AgentClient: sdw2, Hostname: "sdw2"},
+		}
+
+		streams := &step.BufferedStreams{}
+		err := hub.UpdateConfFiles(context.Background(), agentConns, nil, streams, config.StateVersion, hub.ConfUpdateOptions{}, version, intermediate, target)
+		if !errors.Is(err, expected) {
+			t.Fatalf("got error %#v want %#v", err, expected)
+		}
+
+		contents := testutils.MustReadFile(t, coordinatorConf)
+		if contents != "port=50432\n" {
+			t.Errorf("expected the coordinator conf to not be updated before the segments, got %q", contents)
+		}
+
+		output := streams.StdoutBuf.String()
+		token := strings.TrimSpace(strings.TrimPrefix(output[strings.LastIndex(output, "conf update resume token: "):], "conf update resume token: "))
+
+		// resuming in the default order refuses the token
+		hub.ResetConfOrder()
+		err = hub.UpdateConfFiles(context.Background(), agentConns, nil, step.DevNullStream, config.StateVersion, hub.ConfUpdateOptions{ResumeToken: token}, version, intermediate, target)
+		if err == nil || !strings.Contains(err.Error(), "was created for a different cluster configuration") {
+			t.Errorf("got error %v want a different cluster configuration error", err)
+		}
+
+		hub.SetConfOrder(hub.ConfOrderSegmentsFirst)
+		sdw2.EXPECT().UpdateConfiguration(gomock.Any(), gomock.Any()).Return(&idl.UpdateConfigurationReply{}, nil).Times(1)
+		standby.EXPECT().UpdateConfiguration(gomock.Any(), gomock.Any()).Return(&idl.UpdateConfigurationReply{}, nil).Times(1)
+
+		err = hub.UpdateConfFiles(context.Background(), agentConns, nil, step.DevNullStream, config.StateVersion, hub.ConfUpdateOptions{ResumeToken: token}, version, intermediate, target)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		contents = testutils.MustReadFile(t, coordinatorConf)
+		if contents != "port=15432\n" {
+			t.Errorf("expected the coordinator conf to be updated after the segments, got %q", contents)
+		}
+	})
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils/errorlist"
+)
+
+// checkConfPathsWithinDataDirs errors for each option whose path is not
+// within its data directory, such as one built from a data directory of a
+// corrupted gp_segment_configuration containing "..", or a crafted request.
+// Options not naming their data directory are not checked.
+func checkConfPathsWithinDataDirs(opts []*idl.UpdateFileConfOptions) error {
+	var err error
+	for _, opt := range opts {
+		err = errorlist.Append(err, checkConfPathWithinDataDir(opt))
+	}
+
+	return err
+}
+
+func checkConfPathWithinDataDir(opt *idl.UpdateFileConfOptions) error {
+	dataDir := opt.GetDataDir()
+	if dataDir == "" {
+		return nil
+	}
+
+	// the data directory is not cleaned itself, since one such as
+	// /data/../etc would otherwise contain any path under /etc
+	if !filepath.IsAbs(dataDir) || filepath.Clean(dataDir) != dataDir {
+		return xerrors.Errorf("update %s%s: data directory %q is not a clean absolute path", opt.GetPath(), reasonSuffix(opt), dataDir)
+	}
+
+	path := filepath.Clean(opt.GetPath())
+	if !strings.HasPrefix(path, dataDir+string(filepath.Separator)) {
+		return xerrors.Errorf("update %s%s: the path resolves to %s outside of data directory %s", opt.GetPath(), reasonSuffix(opt), path, dataDir)
+	}
+
+	return nil
+}
+
+// resolveConfPath returns the real file of a conf file, following symlinks
+// such as one to a central config location, so that the file is written and
+// backed up where it is rather than replacing the link. An absent path is
+// returned as is.
+func resolveConfPath(path string) (string, error) {
+	resolved, err := filepath.EvalSymlinks(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return path, nil
+	}
+
+	if err != nil {
+		return "", xerrors.Errorf("resolve %s: %w", path, err)
+	}
+
+	return resolved, nil
+}
@@ -0,0 +1,99 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/testutils"
+)
+
+func TestConfPathsWithinDataDirs(t *testing.T) {
+	dir := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, dir)
+
+	dataDir := filepath.Join(dir, "seg1")
+	testutils.MustCreateDir(t, dataDir)
+
+	path := filepath.Join(dataDir, "postgresql.conf")
+	outside := filepath.Join(dir, "postgresql.conf")
+
+	option := func(path string, dataDir string) *idl.UpdateFileConfOptions {
+		return &idl.UpdateFileConfOptions{
+			Path:        path,
+			Pattern:     `^(port=)5000$`,
+			Replacement: `\16000`,
+			Reason:      hub.ReasonPortRewrite,
+			DataDir:     dataDir,
+		}
+	}
+
+	t.Run("edits a path within its data directory", func(t *testing.T) {
+		testutils.MustWriteToFile(t, path, "port=5000\n")
+
+		err := hub.UpdateConfigurationFile(context.Background(), []*idl.UpdateFileConfOptions{option(path, dataDir)})
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		contents := testutils.MustReadFile(t, path)
+		if contents != "port=6000\n" {
+			t.Errorf("got %q want %q", contents, "port=6000\n")
+		}
+	})
+
+	errCases := []struct {
+		name     string
+		opt      *idl.UpdateFileConfOptions
+		expected string
+	}{
+		{
+			name:     "rejects a path escaping its data directory",
+			opt:      option(filepath.Join(dataDir, "..", "postgresql.conf"), dataDir),
+			expected: "the path resolves to " + outside + " outside of data directory " + dataDir,
+		},
+		{
+			name:     "rejects the data directory itself",
+			opt:      option(dataDir, dataDir),
+			expected: "outside of data directory " + dataDir,
+		},
+		{
+			name:     "rejects a data directory that is not clean",
+			opt:      option(outside, dataDir+"/.."),
+			expected: "is not a clean absolute path",
+		},
+		{
+			name:     "rejects a relative data directory",
+			opt:      option("seg1/postgresql.conf", "seg1"),
+			expected: `data directory "seg1" is not a clean absolute path`,
+		},
+	}
+
+	for _, c := range errCases {
+		t.Run(c.name, func(t *testing.T) {
+			testutils.MustWriteToFile(t, path, "port=5000\n")
+			testutils.MustWriteToFile(t, outside, "port=5000\n")
+
+			// a valid option is not applied when any option is rejected
+			opts := []*idl.UpdateFileConfOptions{option(path, dataDir), c.opt}
+
+			err := hub.UpdateConfigurationFile(context.Background(), opts)
+			if err == nil || !strings.Contains(err.Error(), c.expected) {
+				t.Errorf("got error %v want it to contain %q", err, c.expected)
+			}
+
+			for _, file := range []string{path, outside} {
+				contents := testutils.MustReadFile(t, file)
+				if contents != "port=5000\n" {
+					t.Errorf("got %s %q want it unchanged", file, contents)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"encoding/csv"
+	"io"
+	"sort"
+
+	"github.com/blang/semver/v4"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/idl"
+)
+
+// ConfEdit is a planned edit of a GUC in a conf file on a host, along with
+// the option that performs it.
+type ConfEdit struct {
+	Hostname string
+	OldValue string
+	NewValue string
+	Option   *idl.UpdateFileConfOptions
+	// Role and ContentID identify the segment an edit is made for, such as a
+	// port rewrite. Role is empty for edits not made for a single segment.
+	Role      string
+	ContentID int
+}
+
+type ConfPlan []ConfEdit
+
+func (p ConfPlan) Options() []*idl.UpdateFileConfOptions {
+	var opts []*idl.UpdateFileConfOptions
+	for _, edit := range p {
+		opts = append(opts, agentOption(edit.Option))
+	}
+
+	return opts
+}
+
+// agentOption returns the option as sent to the agents, carrying the settings
+// of the hub configuration they apply to the file it edits.
+func agentOption(opt *idl.UpdateFileConfOptions) *idl.UpdateFileConfOptions {
+	return tempDirOption(backupOption(trailingNewlineOption(opt)))
+}
+
+// PlanConfFiles returns the edits UpdateConfFiles makes across the cluster,
+// in the order they are made and sorted by host, without making them.
+func PlanConfFiles(version semver.Version, intermediate *greenplum.Cluster, target *greenplum.Cluster) (ConfPlan, error) {
+	target, err := remapTargetPorts(target)
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := coordinatorConfEdits(version, intermediate, target)
+	if err != nil {
+		return nil, err
+	}
+
+	hosts := AgentHosts(target)
+	sort.Strings(hosts)
+	for _, host := range hosts {
+		plan = append(plan, standbyConfEdits(host, version, intermediate, target)...)
+	}
+
+	for _, host := range hosts {
+		plan = append(plan, postgresqlConfEdits(host, intermediate, target)...)
+	}
+
+	for _, host := range hosts {
+		edits, err := recoveryConfEdits(host, version, intermediate, target)
+		if err != nil {
+			return nil, err
+		}
+
+		plan = append(plan, edits...)
+	}
+
+	moved, err := movedHosts(intermediate, target)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, host := range hosts {
+		plan = append(plan, pgHbaConfEdits(host, moved, target)...)
+	}
+
+	plan = append(plan, customConfEditsOnHost(target.CoordinatorHostname(), target, func(seg *greenplum.SegConfig) bool {
+		return seg.IsCoordinator()
+	})...)
+
+	for _, host := range hosts {
+		plan = append(plan, customConfEditsOnHost(host, target, func(seg *greenplum.SegConfig) bool {
+			return !seg.IsCoordinator()
+		})...)
+	}
+
+	return plan, nil
+}
+
+// BuildPostgresqlConfOptions returns the options UpdatePostgresqlConfOnSegments
+// sends the agent on a host to rewrite the ports of its segments, without
+// connecting to it or reading any file.
+func BuildPostgresqlConfOptions(hostname string, intermediate *greenplum.Cluster, target *greenplum.Cluster) []*idl.UpdateFileConfOptions {
+	return confRequestOptions(postgresqlConfEdits(hostname, intermediate, target))
+}
+
+// BuildRecoveryConfOptions returns the options UpdateRecoveryConfOnSegments
+// sends the agent on a host to rewrite the primary_conninfo of its mirrors.
+func BuildRecoveryConfOptions(hostname string, version semver.Version, intermediate *greenplum.Cluster, target *greenplum.Cluster) ([]*idl.UpdateFileConfOptions, error) {
+	edits, err := recoveryConfEdits(hostname, version, intermediate, target)
+	if err != nil {
+		return nil, err
+	}
+
+	return confRequestOptions(edits), nil
+}
+
+// BuildStandbyConfOptions returns the options UpdateStandbyConfFiles sends the
+// agent on a host when it is that of the standby.
+func BuildStandbyConfOptions(hostname string, version semver.Version, intermediate *greenplum.Cluster, target *greenplum.Cluster) []*idl.UpdateFileConfOptions {
+	return confRequestOptions(standbyConfEdits(hostname, version, intermediate, target))
+}
+
+// WriteCSV writes the plan for review in a spreadsheet.
+func (p ConfPlan) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+
+	err := writer.Write([]string{"host", "path", "guc", "old-value", "new-value", "reason"})
+	if err != nil {
+		return err
+	}
+
+	for _, edit := range p {
+		err := writer.Write([]string{
+			edit.Hostname,
+			edit.Option.GetPath(),
+			edit.Option.GetGuc(),
+			edit.OldValue,
+			edit.NewValue,
+			edit.Option.GetReason(),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
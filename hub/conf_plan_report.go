@@ -0,0 +1,48 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/utils"
+)
+
+// confPlanReportFile is the absolute path the conf update writes its plan to
+// as CSV before sending any edits, so that operators can review the planned
+// edits in a spreadsheet. It is empty to not write the plan. The hub sets it
+// from its configuration.
+var confPlanReportFile = ""
+
+func SetConfPlanReportFile(path string) {
+	confPlanReportFile = path
+}
+
+func ResetConfPlanReportFile() {
+	confPlanReportFile = ""
+}
+
+// writeConfPlanReport writes the plan to the conf plan report file when one is
+// configured, replacing that of a prior run.
+func writeConfPlanReport(w io.Writer, plan ConfPlan) error {
+	if confPlanReportFile == "" {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	if err := plan.WriteCSV(&buf); err != nil {
+		return err
+	}
+
+	if err := utils.AtomicallyWrite(confPlanReportFile, buf.Bytes()); err != nil {
+		return xerrors.Errorf("write conf plan report: %w", err)
+	}
+
+	fmt.Fprintf(w, "wrote the conf plan to %s\n", confPlanReportFile)
+	return nil
+}
@@ -0,0 +1,193 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/blang/semver/v4"
+	"github.com/golang/mock/gomock"
+
+	"github.com/greenplum-db/gpupgrade/config"
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/idl/mock_idl"
+	"github.com/greenplum-db/gpupgrade/step"
+	"github.com/greenplum-db/gpupgrade/testutils"
+)
+
+func TestConfPlanWriteCSV(t *testing.T) {
+	intermediate := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: "/data/qddir/seg.HqtFHX54y0o.-1", Port: 50432, Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: -1, Hostname: "standby", DataDir: "/data/standby.HqtFHX54y0o", Port: 50433, Role: greenplum.MirrorRole},
+		{DbID: 3, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg.HqtFHX54y0o.1", Port: 50434, Role: greenplum.PrimaryRole},
+		{DbID: 4, ContentID: 0, Hostname: "sdw2", DataDir: "/data/dbfast_mirror1/seg.HqtFHX54y0o.1", Port: 50435, Role: greenplum.MirrorRole},
+	})
+
+	target := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: "/data/qddir/seg-1", Port: 15432, Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: -1, Hostname: "standby", DataDir: "/data/standby", Port: 16432, Role: greenplum.MirrorRole},
+		{DbID: 3, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg1", Port: 25433, Role: greenplum.PrimaryRole},
+		{DbID: 4, ContentID: 0, Hostname: "sdw2", DataDir: "/data/dbfast_mirror1/seg1", Port: 25434, Role: greenplum.MirrorRole},
+	})
+
+	plan, err := hub.PlanConfFiles(semver.MustParse("6.0.0"), intermediate, target)
+	if err != nil {
+		t.Fatalf("unexpected error %+v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := plan.WriteCSV(&buf); err != nil {
+		t.Fatalf("unexpected error %+v", err)
+	}
+
+	expected := `host,path,guc,old-value,new-value,reason
+coordinator,/data/qddir/seg-1/gpperfmon/conf/gpperfmon.conf,log_location,,/data/qddir/seg-1/gpperfmon/logs,gpperfmon-log-location
+coordinator,/data/qddir/seg-1/postgresql.conf,port,50432,15432,port-rewrite
+standby,/data/standby/postgresql.conf,port,50433,16432,port-rewrite
+standby,/data/standby/recovery.conf,primary_conninfo,port=50432,port=15432,primary-conninfo-rewrite
+sdw1,/data/dbfast1/seg1/postgresql.conf,port,50434,25433,port-rewrite
+sdw2,/data/dbfast_mirror1/seg1/postgresql.conf,port,50434,25434,port-rewrite
+sdw2,/data/dbfast_mirror1/seg1/recovery.conf,primary_conninfo,port=50434,port=25433,primary-conninfo-rewrite
+`
+	if buf.String() != expected {
+		t.Errorf("got\n%s\nwant\n%s", buf.String(), expected)
+	}
+}
+
+func TestConfPlanReport(t *testing.T) {
+	stateDir := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, stateDir)
+	testutils.SetEnv(t, "GPUPGRADE_HOME", stateDir)
+
+	intermediate := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: "/data/qddir/seg.HqtFHX54y0o.-1", Port: 50432, Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg.HqtFHX54y0o.1", Port: 50434, Role: greenplum.PrimaryRole},
+	})
+
+	target := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: "/data/qddir/seg-1", Port: 15432, Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg1", Port: 25433, Role: greenplum.PrimaryRole},
+	})
+
+	version := semver.MustParse("7.0.0")
+
+	report := filepath.Join(stateDir, "conf-plan.csv")
+	hub.SetConfPlanReportFile(report)
+	defer hub.ResetConfPlanReportFile()
+
+	streams := &step.BufferedStreams{}
+	err := hub.UpdateConfFiles(context.Background(), nil, nil, streams, config.StateVersion, hub.ConfUpdateOptions{DumpRequests: true}, version, intermediate, target)
+	if err != nil {
+		t.Fatalf("unexpected error %+v", err)
+	}
+
+	plan, err := hub.PlanConfFiles(version, intermediate, target)
+	if err != nil {
+		t.Fatalf("unexpected error %+v", err)
+	}
+
+	var expected bytes.Buffer
+	if err := plan.WriteCSV(&expected); err != nil {
+		t.Fatalf("unexpected error %+v", err)
+	}
+
+	actual := testutils.MustReadFile(t, report)
+	if actual != expected.String() {
+		t.Errorf("got report\n%s\nwant\n%s", actual, expected.String())
+	}
+
+	if !strings.Contains(streams.StdoutBuf.String(), "wrote the conf plan to "+report+"\n") {
+		t.Errorf("got stdout %q want the conf plan report reported", streams.StdoutBuf.String())
+	}
+}
+
+func TestBuildConfOptions(t *testing.T) {
+	intermediate := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: "/data/qddir/seg.HqtFHX54y0o.-1", Port: 50432, Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: -1, Hostname: "standby", DataDir: "/data/standby.HqtFHX54y0o", Port: 50433, Role: greenplum.MirrorRole},
+		{DbID: 3, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg.HqtFHX54y0o.1", Port: 50434, Role: greenplum.PrimaryRole},
+		{DbID: 4, ContentID: 0, Hostname: "sdw2", DataDir: "/data/dbfast_mirror1/seg.HqtFHX54y0o.1", Port: 50435, Role: greenplum.MirrorRole},
+	})
+
+	target := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: "/data/qddir/seg-1", Port: 15432, Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: -1, Hostname: "standby", DataDir: "/data/standby", Port: 16432, Role: greenplum.MirrorRole},
+		{DbID: 3, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg1", Port: 25433, Role: greenplum.PrimaryRole},
+		{DbID: 4, ContentID: 0, Hostname: "sdw2", DataDir: "/data/dbfast_mirror1/seg1", Port: 25434, Role: greenplum.MirrorRole},
+	})
+
+	version := semver.MustParse("6.0.0")
+
+	t.Run("builds the options of each host", func(t *testing.T) {
+		opts := hub.BuildPostgresqlConfOptions("sdw2", intermediate, target)
+		if len(opts) != 1 || opts[0].GetPath() != "/data/dbfast_mirror1/seg1/postgresql.conf" || opts[0].GetExpectedValue() != "25434" {
+			t.Errorf("got options %v want the port rewrite of the mirror", opts)
+		}
+
+		opts, err := hub.BuildRecoveryConfOptions("sdw2", version, intermediate, target)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		if len(opts) != 1 || opts[0].GetPath() != "/data/dbfast_mirror1/seg1/recovery.conf" || opts[0].GetExpectedValue() != "port=25433" {
+			t.Errorf("got options %v want the conninfo rewrite of the mirror", opts)
+		}
+
+		opts = hub.BuildStandbyConfOptions("standby", version, intermediate, target)
+		if len(opts) != 2 {
+			t.Errorf("got options %v want the port and conninfo rewrites of the standby", opts)
+		}
+
+		for _, opts := range [][]*idl.UpdateFileConfOptions{
+			hub.BuildPostgresqlConfOptions("standby", intermediate, target),
+			hub.BuildStandbyConfOptions("sdw1", version, intermediate, target),
+		} {
+			if len(opts) != 0 {
+				t.Errorf("got options %v want none", opts)
+			}
+		}
+	})
+
+	t.Run("builds the options sent to the agents", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var agentConns []*idl.Connection
+		for _, hostname := range []string{"sdw1", "sdw2"} {
+			client := mock_idl.NewMockAgentClient(ctrl)
+			client.EXPECT().UpdateConfiguration(gomock.Any(), equivalentConfRequest(&idl.UpdateConfigurationRequest{
+				Options: hub.BuildPostgresqlConfOptions(hostname, intermediate, target),
+			})).Return(&idl.UpdateConfigurationReply{}, nil)
+			agentConns = append(agentConns, &idl.Connection{AgentClient: client, Hostname: hostname})
+		}
+
+		err := hub.UpdatePostgresqlConfOnSegments(context.Background(), agentConns, intermediate, target)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+	})
+
+	t.Run("errors when a mirror has no target primary", func(t *testing.T) {
+		mirrorOnly := hub.MustCreateCluster(t, greenplum.SegConfigs{
+			{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: "/data/qddir/seg-1", Port: 15432, Role: greenplum.PrimaryRole},
+			{DbID: 4, ContentID: 0, Hostname: "sdw2", DataDir: "/data/dbfast_mirror1/seg1", Port: 25434, Role: greenplum.MirrorRole},
+		})
+
+		opts, err := hub.BuildRecoveryConfOptions("sdw2", version, intermediate, mirrorOnly)
+		expected := "mirror with content 0 on host sdw2 has no target primary"
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("got error %v want it to contain %q", err, expected)
+		}
+
+		if opts != nil {
+			t.Errorf("got options %v want none", opts)
+		}
+	})
+}
@@ -0,0 +1,100 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"path/filepath"
+	"sort"
+
+	"github.com/blang/semver/v4"
+	"golang.org/x/xerrors"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
+)
+
+// ConfRequestsDir is the directory in the state directory the conf update
+// requests are dumped to.
+const ConfRequestsDir = "conf-requests"
+
+// AgentConfRequests returns the UpdateConfiguration requests the conf update
+// sends to the agents keyed by host and then by phase. Hosts and phases
+// without edits are omitted. The request of the coordinator, whose host need
+// not run an agent, is not included.
+func AgentConfRequests(version semver.Version, intermediate *greenplum.Cluster, target *greenplum.Cluster) (map[string]map[string]*idl.UpdateConfigurationRequest, error) {
+	moved, err := movedHosts(intermediate, target)
+	if err != nil {
+		return nil, err
+	}
+
+	requests := make(map[string]map[string]*idl.UpdateConfigurationRequest)
+
+	for _, host := range AgentHosts(target) {
+		recoveryEdits, err := recoveryConfEdits(host, version, intermediate, target)
+		if err != nil {
+			return nil, err
+		}
+
+		phases := map[string]ConfPlan{
+			ConfPhaseStandby:               standbyConfEdits(host, version, intermediate, target),
+			ConfPhaseSegmentPostgresqlConf: postgresqlConfEdits(host, intermediate, target),
+			ConfPhaseSegmentRecoveryConf:   recoveryEdits,
+			ConfPhaseSegmentPgHbaConf:      pgHbaConfEdits(host, moved, target),
+			ConfPhaseCustom: customConfEditsOnHost(host, target, func(seg *greenplum.SegConfig) bool {
+				return !seg.IsCoordinator()
+			}),
+		}
+
+		for phase, edits := range phases {
+			req := newConfRequest(edits)
+			if req == nil {
+				continue
+			}
+
+			if requests[host] == nil {
+				requests[host] = make(map[string]*idl.UpdateConfigurationRequest)
+			}
+			requests[host][phase] = req
+		}
+	}
+
+	return requests, nil
+}
+
+// DumpConfRequests writes each request of AgentConfRequests serialized as
+// JSON to <dir>/<phase>/<hostname>.json, without sending them.
+func DumpConfRequests(dir string, version semver.Version, intermediate *greenplum.Cluster, target *greenplum.Cluster) error {
+	requests, err := AgentConfRequests(version, intermediate, target)
+	if err != nil {
+		return err
+	}
+
+	var hosts []string
+	for host := range requests {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+
+	for _, host := range hosts {
+		for phase, req := range requests[host] {
+			contents, err := protojson.MarshalOptions{Multiline: true}.Marshal(req)
+			if err != nil {
+				return xerrors.Errorf("marshal %s request for host %s: %w", phase, host, err)
+			}
+
+			path := filepath.Join(dir, phase, host+".json")
+			if err := utils.System.MkdirAll(filepath.Dir(path), 0700); err != nil {
+				return xerrors.Errorf("dump %s request for host %s: %w", phase, host, err)
+			}
+
+			if err := utils.System.WriteFile(path, append(contents, '\n'), 0600); err != nil {
+				return xerrors.Errorf("dump %s request for host %s: %w", phase, host, err)
+			}
+		}
+	}
+
+	return nil
+}
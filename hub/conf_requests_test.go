@@ -0,0 +1,123 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/blang/semver/v4"
+	"github.com/golang/mock/gomock"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/greenplum-db/gpupgrade/config"
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/idl/mock_idl"
+	"github.com/greenplum-db/gpupgrade/step"
+	"github.com/greenplum-db/gpupgrade/testutils"
+)
+
+func TestConfRequests(t *testing.T) {
+	intermediate := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: "/data/qddir/seg.HqtFHX54y0o.-1", Port: 50432, Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg.HqtFHX54y0o.1", Port: 50434, Role: greenplum.PrimaryRole},
+		{DbID: 3, ContentID: 0, Hostname: "sdw2", DataDir: "/data/dbfast_mirror1/seg.HqtFHX54y0o.1", Port: 50435, Role: greenplum.MirrorRole},
+	})
+
+	target := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: "/data/qddir/seg-1", Port: 15432, Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg1", Port: 25433, Role: greenplum.PrimaryRole},
+		{DbID: 3, ContentID: 0, Hostname: "sdw2", DataDir: "/data/dbfast_mirror1/seg1", Port: 25434, Role: greenplum.MirrorRole},
+	})
+
+	version := semver.MustParse("7.0.0")
+
+	t.Run("returns the requests of each host and phase", func(t *testing.T) {
+		requests, err := hub.AgentConfRequests(version, intermediate, target)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		var phases []string
+		for phase := range requests["sdw2"] {
+			phases = append(phases, phase)
+		}
+
+		sort.Strings(phases)
+
+		expected := []string{hub.ConfPhaseSegmentPostgresqlConf, hub.ConfPhaseSegmentRecoveryConf}
+		if len(requests) != 2 || !reflect.DeepEqual(phases, expected) {
+			t.Errorf("got phases %q on sdw2 want %q", phases, expected)
+		}
+
+		opts := requests["sdw2"][hub.ConfPhaseSegmentRecoveryConf].GetOptions()
+		if len(opts) != 1 || opts[0].GetPath() != "/data/dbfast_mirror1/seg1/postgresql.auto.conf" {
+			t.Errorf("got options %v want the primary_conninfo edit of the mirror", opts)
+		}
+	})
+
+	t.Run("dumps the requests as json", func(t *testing.T) {
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		err := hub.DumpConfRequests(dir, version, intermediate, target)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		requests, err := hub.AgentConfRequests(version, intermediate, target)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		for host, phases := range requests {
+			for phase, expected := range phases {
+				contents := testutils.MustReadFile(t, filepath.Join(dir, phase, host+".json"))
+
+				req := &idl.UpdateConfigurationRequest{}
+				if err := protojson.Unmarshal([]byte(contents), req); err != nil {
+					t.Fatalf("unmarshal %s request of %s: %v", phase, host, err)
+				}
+
+				if !proto.Equal(req, expected) {
+					t.Errorf("got %v want %v", req, expected)
+				}
+			}
+		}
+	})
+
+	t.Run("dumps the requests without sending them", func(t *testing.T) {
+		stateDir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, stateDir)
+		testutils.SetEnv(t, "GPUPGRADE_HOME", stateDir)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// no requests are expected
+		agentConns := []*idl.Connection{
+			{AgentClient: mock_idl.NewMockAgentClient(ctrl), Hostname: "sdw1"},
+			{AgentClient: mock_idl.NewMockAgentClient(ctrl), Hostname: "sdw2"},
+		}
+
+		streams := &step.BufferedStreams{}
+		err := hub.UpdateConfFiles(context.Background(), agentConns, nil, streams, config.StateVersion, hub.ConfUpdateOptions{DumpRequests: true}, version, intermediate, target)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		testutils.PathMustExist(t, filepath.Join(stateDir, hub.ConfRequestsDir, hub.ConfPhaseSegmentPostgresqlConf, "sdw1.json"))
+
+		expected := "wrote the conf update requests to " + filepath.Join(stateDir, hub.ConfRequestsDir) + " without sending them\n"
+		if streams.StdoutBuf.String() != expected {
+			t.Errorf("got stdout %q want %q", streams.StdoutBuf.String(), expected)
+		}
+	})
+}
@@ -0,0 +1,110 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/blang/semver/v4"
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+)
+
+// The phases of UpdateConfFiles. A resume token records the last completed
+// phase, so phases are added last to keep the meaning of existing tokens
+// rather than in the order they are run.
+const (
+	confPhaseNone = iota
+	confPhaseCoordinator
+	confPhaseStandby
+	confPhaseSegmentPostgresqlConf
+	confPhaseSegmentRecoveryConf
+	confPhaseCustom
+	confPhaseSegmentPgHbaConf
+)
+
+// confResumeToken is a self-contained checkpoint of UpdateConfFiles that an
+// external orchestrator can pass back to resume an interrupted update without
+// relying on the state directory. The digest ties the token to the clusters
+// it was created for so that it cannot be replayed against different ones.
+type confResumeToken struct {
+	Digest    string `json:"digest"`
+	Completed int    `json:"completed"`
+}
+
+func (t confResumeToken) String() string {
+	contents, _ := json.Marshal(t) // marshalling a struct of basic types cannot fail
+	return base64.RawURLEncoding.EncodeToString(contents)
+}
+
+// parseConfResumeToken returns the phase that was last completed. An empty
+// token starts from the beginning.
+func parseConfResumeToken(token string, digest string) (int, error) {
+	if token == "" {
+		return confPhaseNone, nil
+	}
+
+	contents, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return confPhaseNone, xerrors.Errorf("decode conf resume token %q: %w", token, err)
+	}
+
+	var parsed confResumeToken
+	if err := json.Unmarshal(contents, &parsed); err != nil {
+		return confPhaseNone, xerrors.Errorf("unmarshal conf resume token %q: %w", token, err)
+	}
+
+	if parsed.Digest != digest {
+		return confPhaseNone, xerrors.Errorf("conf resume token %q was created for a different cluster configuration", token)
+	}
+
+	if parsed.Completed < confPhaseNone || parsed.Completed > confPhaseSegmentPgHbaConf {
+		return confPhaseNone, xerrors.Errorf("conf resume token %q has unknown phase %d", token, parsed.Completed)
+	}
+
+	return parsed.Completed, nil
+}
+
+// confResumeDigest identifies the configuration of a conf update. It does not
+// cover the hosts the update is limited to, so that an update can be resumed
+// on only the hosts that failed.
+func confResumeDigest(version semver.Version, intermediate *greenplum.Cluster, target *greenplum.Cluster) (string, error) {
+	contents, err := json.Marshal(struct {
+		Version      string
+		Intermediate *greenplum.Cluster
+		Target       *greenplum.Cluster
+		Custom       []CustomConfEdit `json:",omitempty"`
+		Exclusions   *ConfExclusions  `json:",omitempty"`
+		Order        ConfOrder        `json:",omitempty"`
+		Core         bool             `json:",omitempty"`
+	}{version.String(), intermediate, target, customConfEdits, digestExclusions(), digestConfOrder(), coreConfMode})
+	if err != nil {
+		return "", xerrors.Errorf("marshal conf resume digest: %w", err)
+	}
+
+	sum := sha256.Sum256(contents)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+const confResumeTokenPrefix = "conf update resume token: "
+
+func writeConfResumeToken(w io.Writer, digest string, completed int) {
+	fmt.Fprintf(w, "%s%s\n", confResumeTokenPrefix, confResumeToken{Digest: digest, Completed: completed})
+}
+
+// digestExclusions returns the conf exclusions to digest, or nil when there
+// are none so that the digest of an unfiltered update is unchanged.
+func digestExclusions() *ConfExclusions {
+	if len(confExclusions.Contents) == 0 && len(confExclusions.Hosts) == 0 {
+		return nil
+	}
+
+	return &confExclusions
+}
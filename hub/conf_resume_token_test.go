@@ -0,0 +1,229 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/blang/semver/v4"
+	"github.com/golang/mock/gomock"
+
+	"github.com/greenplum-db/gpupgrade/config"
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/idl/mock_idl"
+	"github.com/greenplum-db/gpupgrade/step"
+	"github.com/greenplum-db/gpupgrade/testutils"
+	"github.com/greenplum-db/gpupgrade/utils"
+)
+
+func TestUpdateConfFilesResumeToken(t *testing.T) {
+	stateDir := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, stateDir)
+
+	resetEnv := testutils.SetEnv(t, "GPUPGRADE_HOME", stateDir)
+	defer resetEnv()
+
+	coordinatorDir := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, coordinatorDir)
+
+	coordinatorConf := filepath.Join(coordinatorDir, "postgresql.conf")
+
+	intermediate := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: coordinatorDir, Port: 50432, Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: -1, Hostname: "standby", DataDir: "/data/standby.HqtFHX54y0o", Port: 50433, Role: greenplum.MirrorRole},
+		{DbID: 3, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg.HqtFHX54y0o.1", Port: 50434, Role: greenplum.PrimaryRole},
+		{DbID: 4, ContentID: 0, Hostname: "sdw2", DataDir: "/data/dbfast_mirror1/seg.HqtFHX54y0o.1", Port: 50435, Role: greenplum.MirrorRole},
+	})
+
+	target := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: coordinatorDir, Port: 15432, Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: -1, Hostname: "standby", DataDir: "/data/standby", Port: 16432, Role: greenplum.MirrorRole},
+		{DbID: 3, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg1", Port: 25433, Role: greenplum.PrimaryRole},
+		{DbID: 4, ContentID: 0, Hostname: "sdw2", DataDir: "/data/dbfast_mirror1/seg1", Port: 25434, Role: greenplum.MirrorRole},
+	})
+
+	version := semver.MustParse("7.0.0")
+
+	lastToken := func(t *testing.T, streams *step.BufferedStreams) string {
+		t.Helper()
+
+		var token string
+		for _, line := range strings.Split(streams.StdoutBuf.String(), "\n") {
+			if strings.HasPrefix(line, "conf update resume token: ") {
+				token = strings.TrimPrefix(line, "conf update resume token: ")
+			}
+		}
+
+		if token == "" {
+			t.Fatalf("expected a resume token in %q", streams.StdoutBuf.String())
+		}
+
+		return token
+	}
+
+	interrupt := func(t *testing.T) string {
+		t.Helper()
+
+		testutils.MustWriteToFile(t, coordinatorConf, "port=50432\n")
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		expected := errors.New("connection reset")
+		standby := mock_idl.NewMockAgentClient(ctrl)
+		standby.EXPECT().UpdateConfiguration(gomock.Any(), gomock.Any()).Return(&idl.UpdateConfigurationReply{}, nil).Times(1)
+
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw1.EXPECT().UpdateConfiguration(gomock.Any(), gomock.Any()).Return(nil, expected).Times(1)
+
+		sdw2 := mock_idl.NewMockAgentClient(ctrl)
+		sdw2.EXPECT().UpdateConfiguration(gomock.Any(), gomock.Any()).Return(&idl.UpdateConfigurationReply{}, nil).Times(1)
+
+		agentConns := []*idl.Connection{
+			{AgentClient: standby, Hostname: "standby"},
+			{AgentClient: sdw1, Hostname: "sdw1"},
+			{AgentClient: sdw2, Hostname: "sdw2"},
+		}
+
+		streams := &step.BufferedStreams{}
+		err := hub.UpdateConfFiles(context.Background(), agentConns, nil, streams, config.StateVersion, hub.ConfUpdateOptions{}, version, intermediate, target)
+		if !errors.Is(err, expected) {
+			t.Fatalf("got error %#v want %#v", err, expected)
+		}
+
+		return lastToken(t, streams)
+	}
+
+	t.Run("retries only the hosts failed within the failure threshold", func(t *testing.T) {
+		testutils.MustWriteToFile(t, coordinatorConf, "port=50432\n")
+
+		hub.SetConfFailureThreshold(hub.FailureThreshold{Count: 1})
+		defer hub.ResetConfFailureThreshold()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		standby := mock_idl.NewMockAgentClient(ctrl)
+		standby.EXPECT().UpdateConfiguration(gomock.Any(), gomock.Any()).Return(&idl.UpdateConfigurationReply{}, nil).Times(1)
+
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw1.EXPECT().UpdateConfiguration(gomock.Any(), gomock.Any()).Return(nil, errors.New("permission denied")).Times(1)
+
+		sdw2 := mock_idl.NewMockAgentClient(ctrl)
+		sdw2.EXPECT().UpdateConfiguration(gomock.Any(), gomock.Any()).Return(&idl.UpdateConfigurationReply{}, nil).Times(2)
+
+		agentConns := []*idl.Connection{
+			{AgentClient: standby, Hostname: "standby"},
+			{AgentClient: sdw1, Hostname: "sdw1"},
+			{AgentClient: sdw2, Hostname: "sdw2"},
+		}
+
+		streams := &step.BufferedStreams{}
+		err := hub.UpdateConfFiles(context.Background(), agentConns, nil, streams, config.StateVersion, hub.ConfUpdateOptions{}, version, intermediate, target)
+
+		var nextActionErr utils.NextActionErr
+		if !errors.As(err, &nextActionErr) {
+			t.Fatalf("got error %#v want a next action", err)
+		}
+
+		// the token is not advanced past the phase the host failed in
+		token := lastToken(t, streams)
+		retry := fmt.Sprintf(`"gpupgrade finalize --conf-hosts sdw1 --conf-resume-token %s"`, token)
+		if !strings.Contains(nextActionErr.NextAction, retry) {
+			t.Errorf("expected next action %q to contain %q", nextActionErr.NextAction, retry)
+		}
+
+		// retrying updates only the failed host from that phase on
+		ctrl = gomock.NewController(t)
+		defer ctrl.Finish()
+
+		standby = mock_idl.NewMockAgentClient(ctrl)
+
+		sdw1 = mock_idl.NewMockAgentClient(ctrl)
+		sdw1.EXPECT().UpdateConfiguration(gomock.Any(), gomock.Any()).Return(&idl.UpdateConfigurationReply{}, nil).Times(1)
+
+		sdw2 = mock_idl.NewMockAgentClient(ctrl)
+
+		agentConns = []*idl.Connection{
+			{AgentClient: standby, Hostname: "standby"},
+			{AgentClient: sdw1, Hostname: "sdw1"},
+			{AgentClient: sdw2, Hostname: "sdw2"},
+		}
+
+		opts := hub.ConfUpdateOptions{ResumeToken: token, Hosts: []string{"sdw1"}}
+		err = hub.UpdateConfFiles(context.Background(), agentConns, nil, step.DevNullStream, config.StateVersion, opts, version, intermediate, target)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+	})
+
+	t.Run("resumes after the last completed phase", func(t *testing.T) {
+		token := interrupt(t)
+
+		// Reset the coordinator conf to show that the completed phase is not
+		// run again.
+		testutils.MustWriteToFile(t, coordinatorConf, "port=50432\n")
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		standby := mock_idl.NewMockAgentClient(ctrl)
+
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw1.EXPECT().UpdateConfiguration(gomock.Any(), gomock.Any()).Return(&idl.UpdateConfigurationReply{}, nil).Times(1)
+
+		sdw2 := mock_idl.NewMockAgentClient(ctrl)
+		sdw2.EXPECT().UpdateConfiguration(gomock.Any(), gomock.Any()).Return(&idl.UpdateConfigurationReply{}, nil).Times(2)
+
+		agentConns := []*idl.Connection{
+			{AgentClient: standby, Hostname: "standby"},
+			{AgentClient: sdw1, Hostname: "sdw1"},
+			{AgentClient: sdw2, Hostname: "sdw2"},
+		}
+
+		streams := &step.BufferedStreams{}
+		err := hub.UpdateConfFiles(context.Background(), agentConns, nil, streams, config.StateVersion, hub.ConfUpdateOptions{ResumeToken: token}, version, intermediate, target)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		contents := testutils.MustReadFile(t, coordinatorConf)
+		if contents != "port=50432\n" {
+			t.Errorf("expected coordinator conf to not be updated again, got %q", contents)
+		}
+
+		if lastToken(t, streams) == token {
+			t.Errorf("expected a new resume token after resuming")
+		}
+	})
+
+	t.Run("rejects a token created for a different cluster", func(t *testing.T) {
+		token := interrupt(t)
+
+		other := hub.MustCreateCluster(t, greenplum.SegConfigs{
+			{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: coordinatorDir, Port: 15433, Role: greenplum.PrimaryRole},
+		})
+
+		err := hub.UpdateConfFiles(context.Background(), nil, nil, step.DevNullStream, config.StateVersion, hub.ConfUpdateOptions{ResumeToken: token}, version, intermediate, other)
+		expected := "was created for a different cluster configuration"
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("expected error %v to contain %q", err, expected)
+		}
+	})
+
+	t.Run("rejects a malformed token", func(t *testing.T) {
+		err := hub.UpdateConfFiles(context.Background(), nil, nil, step.DevNullStream, config.StateVersion, hub.ConfUpdateOptions{ResumeToken: "not a token"}, version, intermediate, target)
+		expected := "decode conf resume token"
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("expected error %v to contain %q", err, expected)
+		}
+	})
+}
@@ -0,0 +1,277 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"syscall"
+
+	"github.com/google/renameio"
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils/errorlist"
+)
+
+// rewriteConfContents applies the pattern and replacement of an option to
+// each line of the contents of the file at path the way
+// `sed -E s@pattern@replacement@` would, replacing the leftmost longest match
+// of each line. Lines are matched without their line ending so that a $
+// anchor also matches before the "\r" of a windows line ending. The contents
+// are rewritten in memory so that the edits of a file can be previewed, or
+// written all at once.
+//
+// A pattern matching no lines is an error, since a port line that is not
+// matched would otherwise leave the wrong port in place, unless the option
+// allows it or its GUC already has the expected value such as when rerun.
+// An option with a line to append when missing instead appends it when its
+// GUC is not set. An option rewriting only the last match leaves the earlier
+// matching lines alone.
+func rewriteConfContents(path string, contents []byte, opt *idl.UpdateFileConfOptions) ([]byte, error) {
+	pattern, err := regexp.Compile(opt.GetPattern())
+	if err != nil {
+		return nil, confCompileError(path, opt, err)
+	}
+	pattern.Longest()
+
+	// Once the setting in effect has its expected value the earlier settings
+	// left in place are not rewritten when rerun.
+	if lastMatchOnly(opt) {
+		done, err := hasExpectedValue(contents, opt)
+		if err != nil {
+			return nil, xerrors.Errorf("update %s%s: %w", filepath.Base(path), reasonSuffix(opt), err)
+		}
+
+		if done {
+			return contents, nil
+		}
+	}
+
+	rewritten, matches := rewriteConfLines(contents, pattern, goReplacement(opt.GetReplacement()), lastMatchOnly(opt))
+	if matches == 0 && opt.GetAppendIfMissing() != "" {
+		appended, ok, err := appendMissingSetting(contents, opt)
+		if err != nil {
+			return nil, xerrors.Errorf("update %s%s: %w", filepath.Base(path), reasonSuffix(opt), err)
+		}
+
+		if ok {
+			return appended, nil
+		}
+	}
+
+	if matches == 0 && !opt.GetAllowNoMatch() {
+		done, err := hasExpectedValue(contents, opt)
+		if err != nil {
+			return nil, xerrors.Errorf("update %s%s: %w", filepath.Base(path), reasonSuffix(opt), err)
+		}
+
+		if !done && opt.GetExpectedValue() != "" {
+			return nil, confEditError(path, opt, fmt.Sprintf("matched no lines and %s is not already %q", opt.GetGuc(), opt.GetExpectedValue()), gucConfSnippet(contents, opt))
+		}
+
+		if !done {
+			return nil, confEditError(path, opt, "matched no lines", gucConfSnippet(contents, opt))
+		}
+	}
+
+	return rewritten, nil
+}
+
+// appendMissingSetting appends the line of the option to contents when its
+// GUC is not set, and reports whether it did. The GUC is checked rather than
+// the line so that rerunning never sets it twice.
+func appendMissingSetting(contents []byte, opt *idl.UpdateFileConfOptions) ([]byte, bool, error) {
+	line := opt.GetAppendIfMissing()
+	if opt.GetGuc() == "" {
+		return nil, false, xerrors.New("appending a missing line requires the GUC to check")
+	}
+
+	if strings.ContainsAny(line, "\r\n") {
+		return nil, false, xerrors.Errorf("line %q to append is not a single line", line)
+	}
+
+	guc := strings.ToLower(opt.GetGuc())
+	appended, err := parseConfSettings(strings.NewReader(line))
+	if err != nil {
+		return nil, false, err
+	}
+
+	if _, ok := appended[guc]; !ok {
+		return nil, false, xerrors.Errorf("line %q to append does not set %s", line, opt.GetGuc())
+	}
+
+	settings, err := parseConfSettings(bytes.NewReader(contents))
+	if err != nil {
+		return nil, false, err
+	}
+
+	if _, ok := settings[guc]; ok {
+		return nil, false, nil
+	}
+
+	// the line uses the dominant line ending of the file
+	ending := lineEnding(contents)
+
+	var result []byte
+	result = append(result, contents...)
+	if len(result) > 0 && !bytes.HasSuffix(result, []byte("\n")) {
+		result = append(result, ending...)
+	}
+	result = append(result, line...)
+	result = append(result, ending...)
+
+	return result, true, nil
+}
+
+// rewriteConfLines replaces the first match of pattern on each line with the
+// expanded Go template and returns the number of lines matched. When lastOnly
+// is set only the last line matched is rewritten.
+func rewriteConfLines(contents []byte, pattern *regexp.Regexp, template string, lastOnly bool) ([]byte, int) {
+	matches := 0
+	lines := bytes.Split(contents, []byte("\n"))
+	for n := range lines {
+		i := n
+		if lastOnly {
+			i = len(lines) - 1 - n
+		}
+
+		line := lines[i]
+		body := bytes.TrimSuffix(line, []byte("\r"))
+		match := pattern.FindSubmatchIndex(body)
+		if match == nil {
+			continue
+		}
+		matches++
+
+		if lastOnly && matches > 1 {
+			continue
+		}
+
+		var rewritten []byte
+		rewritten = append(rewritten, body[:match[0]]...)
+		rewritten = pattern.Expand(rewritten, []byte(template), body, match)
+		rewritten = append(rewritten, body[match[1]:]...)
+		rewritten = append(rewritten, line[len(body):]...)
+		lines[i] = rewritten
+	}
+
+	return bytes.Join(lines, []byte("\n")), matches
+}
+
+// hasExpectedValue returns whether the GUC of an option already has its
+// expected value in contents.
+func hasExpectedValue(contents []byte, opt *idl.UpdateFileConfOptions) (bool, error) {
+	if opt.GetGuc() == "" || opt.GetExpectedValue() == "" {
+		return false, nil
+	}
+
+	settings, err := parseConfSettings(bytes.NewReader(contents))
+	if err != nil {
+		return false, err
+	}
+
+	value, found := settings[strings.ToLower(opt.GetGuc())]
+	actual, found := writtenValue(&idl.ReadConfigurationReply_Value{Name: opt.GetGuc(), Value: value, Found: found})
+	return found && actual == opt.GetExpectedValue(), nil
+}
+
+// goReplacement translates a sed replacement into a template for
+// regexp.Expand. The backreferences \1 through \9 and & referring to the
+// whole match become ${1} through ${9} and ${0}, any other escaped character
+// is taken literally, and a literal $ is escaped as $$.
+func goReplacement(replacement string) string {
+	var template strings.Builder
+	for i := 0; i < len(replacement); i++ {
+		c := replacement[i]
+		switch {
+		case c == '\\' && i+1 < len(replacement):
+			i++
+			next := replacement[i]
+			if next >= '0' && next <= '9' {
+				template.WriteString("${" + string(next) + "}")
+			} else if next == '$' {
+				template.WriteString("$$")
+			} else {
+				template.WriteByte(next)
+			}
+		case c == '&':
+			template.WriteString("${0}")
+		case c == '$':
+			template.WriteString("$$")
+		default:
+			template.WriteByte(c)
+		}
+	}
+
+	return template.String()
+}
+
+// writeConfFileAtomically replaces the file at path with contents by renaming
+// a temporary file written in the ConfTempDir of tempDir over it, so that a conf file is
+// never left partially written even when the process is killed. The
+// temporary file is synced before the rename and is given the mode, owner and
+// group of the file it replaces, described by original. A symlinked file is
+// replaced where it is, leaving the link in place.
+func writeConfFileAtomically(path string, contents []byte, original os.FileInfo, tempDir string) (err error) {
+	path, err = resolveConfPath(path)
+	if err != nil {
+		return err
+	}
+
+	file, err := renameio.TempFile(ConfTempDir(path, tempDir), path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cErr := file.Cleanup(); cErr != nil {
+			err = errorlist.Append(err, cErr)
+		}
+	}()
+
+	if err := file.Chmod(original.Mode().Perm()); err != nil {
+		return err
+	}
+
+	if err := preserveOwner(file.File, original); err != nil {
+		return err
+	}
+
+	if _, err := file.Write(contents); err != nil {
+		return err
+	}
+
+	return file.CloseAtomicallyReplace()
+}
+
+// preserveOwner gives file the owner and group of original, since postgres
+// refuses to start when the ownership of its conf files changes. They are only
+// changed when they differ so that a process rewriting its own files needs no
+// permission to chown.
+func preserveOwner(file *os.File, original os.FileInfo) error {
+	want, ok := original.Sys().(*syscall.Stat_t)
+	if !ok {
+		return nil
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	have, ok := info.Sys().(*syscall.Stat_t)
+	if ok && have.Uid == want.Uid && have.Gid == want.Gid {
+		return nil
+	}
+
+	if err := file.Chown(int(want.Uid), int(want.Gid)); err != nil {
+		return xerrors.Errorf("preserve the owner %d and group %d: %w", want.Uid, want.Gid, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,464 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/testutils"
+	"github.com/greenplum-db/gpupgrade/utils/errorlist"
+)
+
+func TestRewriteConfFile(t *testing.T) {
+	t.Run("rewrites lines like sed", func(t *testing.T) {
+		cases := []struct {
+			name        string
+			contents    string
+			pattern     string
+			replacement string
+			expected    string
+		}{
+			{
+				name:        "translates backreferences",
+				contents:    "port=5000\n",
+				pattern:     `^(port=)5000()$`,
+				replacement: `\16000\2 # was 5000`,
+				expected:    "port=6000 # was 5000\n",
+			},
+			{
+				name:        "replaces & with the whole match",
+				contents:    "max_connections=100\n",
+				pattern:     `^max_connections=.*$`,
+				replacement: `#&`,
+				expected:    "#max_connections=100\n",
+			},
+			{
+				name:        "takes escaped characters literally",
+				contents:    "log_location=a\n",
+				pattern:     `^log_location=.*$`,
+				replacement: `log_location='C:\\data\&more'`,
+				expected:    "log_location='C:\\data&more'\n",
+			},
+			{
+				name:        "takes the former sed delimiter literally",
+				contents:    "my_extension.user=a@sdw1\n",
+				pattern:     `^(my_extension.user=)a@(.*)$`,
+				replacement: `\1gpadmin@\2`,
+				expected:    "my_extension.user=gpadmin@sdw1\n",
+			},
+			{
+				name:        "writes a literal $",
+				contents:    "dynamic_library_path=a\n",
+				pattern:     `^dynamic_library_path=.*$`,
+				replacement: `dynamic_library_path='$libdir'`,
+				expected:    "dynamic_library_path='$libdir'\n",
+			},
+			{
+				name:        "replaces only the first match of each line",
+				contents:    "a a\na\n",
+				pattern:     `a`,
+				replacement: `b`,
+				expected:    "b a\nb\n",
+			},
+			{
+				name:        "replaces the longest match",
+				contents:    "port=5000\n",
+				pattern:     `port|port=5000`,
+				replacement: `port=6000`,
+				expected:    "port=6000\n",
+			},
+			{
+				name:        "anchors to the end of windows lines",
+				contents:    "port=5000\r\nmax_connections=100\r\n",
+				pattern:     `^(port=)5000$`,
+				replacement: `\16000`,
+				expected:    "port=6000\r\nmax_connections=100\r\n",
+			},
+		}
+
+		for _, c := range cases {
+			t.Run(c.name, func(t *testing.T) {
+				dir := testutils.GetTempDir(t, "")
+				defer testutils.MustRemoveAll(t, dir)
+
+				path := filepath.Join(dir, "postgresql.conf")
+				testutils.MustWriteToFile(t, path, c.contents)
+
+				err := hub.UpdateConfigurationFile(context.Background(), []*idl.UpdateFileConfOptions{{
+					Path:                    path,
+					Pattern:                 c.pattern,
+					Replacement:             c.replacement,
+					PreserveTrailingNewline: true,
+				}})
+				if err != nil {
+					t.Fatalf("unexpected error %+v", err)
+				}
+
+				contents := testutils.MustReadFile(t, path)
+				if contents != c.expected {
+					t.Errorf("got %q want %q", contents, c.expected)
+				}
+			})
+		}
+	})
+
+	t.Run("writes the rewritten file through the conf temp dir", func(t *testing.T) {
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		tempDir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, tempDir)
+
+		hub.SetConfTempDir(tempDir)
+		defer hub.ResetConfTempDir()
+
+		path := filepath.Join(dir, "postgresql.conf")
+		testutils.MustWriteToFile(t, path, "port=5000\n")
+
+		err := hub.UpdateConfigurationFile(context.Background(), []*idl.UpdateFileConfOptions{{Path: path, Pattern: `^(port=)5000$`, Replacement: `\16000`}})
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		contents := testutils.MustReadFile(t, path)
+		if contents != "port=6000\n" {
+			t.Errorf("got %q want %q", contents, "port=6000\n")
+		}
+
+		entries, err := os.ReadDir(tempDir)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(entries) != 0 {
+			t.Errorf("expected the temp dir to be left empty got %d entries", len(entries))
+		}
+	})
+
+	t.Run("preserves the mode, owner and group of the file", func(t *testing.T) {
+		if os.Geteuid() != 0 {
+			t.Skip("changing the owner of a file requires root")
+		}
+
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		path := filepath.Join(dir, "postgresql.conf")
+		testutils.MustWriteToFile(t, path, "port=5000\n")
+
+		if err := os.Chmod(path, 0640); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.Chown(path, 1, 2); err != nil {
+			t.Fatal(err)
+		}
+
+		err := hub.UpdateConfigurationFile(context.Background(), []*idl.UpdateFileConfOptions{{Path: path, Pattern: `^(port=)5000$`, Replacement: `\16000`}})
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if info.Mode().Perm() != 0640 {
+			t.Errorf("got mode %v want %v", info.Mode().Perm(), os.FileMode(0640))
+		}
+
+		stat := info.Sys().(*syscall.Stat_t)
+		if stat.Uid != 1 || stat.Gid != 2 {
+			t.Errorf("got owner %d and group %d want 1 and 2", stat.Uid, stat.Gid)
+		}
+
+		contents := testutils.MustReadFile(t, path)
+		if contents != "port=6000\n" {
+			t.Errorf("got %q want %q", contents, "port=6000\n")
+		}
+
+		backup := testutils.MustReadFile(t, path+hub.BackupSuffix)
+		if backup != "port=5000\n" {
+			t.Errorf("got backup %q want %q", backup, "port=5000\n")
+		}
+	})
+
+	t.Run("errors when a pattern matches no lines", func(t *testing.T) {
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		path := filepath.Join(dir, "postgresql.conf")
+		testutils.MustWriteToFile(t, path, "port =  5000\n#port=5000\n")
+
+		err := hub.UpdateConfigurationFile(context.Background(), []*idl.UpdateFileConfOptions{{Path: path, Pattern: `^(port=)5000$`, Replacement: `\16000`, Reason: hub.ReasonPortRewrite}})
+		expected := fmt.Sprintf(`update %s for port-rewrite: pattern %q matched no lines; replacement %q`, path, `^(port=)5000$`, `\16000`)
+		if err == nil || err.Error() != expected {
+			t.Errorf("got error %v want %q", err, expected)
+		}
+
+		contents := testutils.MustReadFile(t, path)
+		if contents != "port =  5000\n#port=5000\n" {
+			t.Errorf("got %q want the file unchanged", contents)
+		}
+	})
+
+	t.Run("leaves a file with a failed option untouched", func(t *testing.T) {
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		path := filepath.Join(dir, "postgresql.conf")
+		testutils.MustWriteToFile(t, path, "port=5000\nmax_connections=100\n")
+
+		err := hub.UpdateConfigurationFile(context.Background(), []*idl.UpdateFileConfOptions{
+			{Path: path, Pattern: `^(port=)5000$`, Replacement: `\16000`},
+			{Path: path, Pattern: `^(max_connections=)200$`, Replacement: `\1300`},
+		})
+		if err == nil || !strings.Contains(err.Error(), "matched no lines") {
+			t.Errorf("expected error %v to contain %q", err, "matched no lines")
+		}
+
+		contents := testutils.MustReadFile(t, path)
+		if contents != "port=5000\nmax_connections=100\n" {
+			t.Errorf("got %q want the file unchanged", contents)
+		}
+
+		testutils.PathMustNotExist(t, path+hub.BackupSuffix)
+	})
+
+	t.Run("returns an error for each file matching no lines", func(t *testing.T) {
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		var opts []*idl.UpdateFileConfOptions
+		for _, name := range []string{"postgresql.conf", "postgresql.auto.conf"} {
+			path := filepath.Join(dir, name)
+			testutils.MustWriteToFile(t, path, "port=7000\n")
+			opts = append(opts, &idl.UpdateFileConfOptions{Path: path, Pattern: `^(port=)5000$`, Replacement: `\16000`})
+		}
+
+		err := hub.UpdateConfigurationFile(context.Background(), opts)
+		var errs errorlist.Errors
+		if !errors.As(err, &errs) {
+			t.Fatalf("error %#v does not contain type %T", err, errs)
+		}
+
+		if len(errs) != len(opts) {
+			t.Errorf("got error count %d, want %d", len(errs), len(opts))
+		}
+	})
+
+	t.Run("allows a pattern matching no lines", func(t *testing.T) {
+		cases := []struct {
+			name     string
+			contents string
+			opt      *idl.UpdateFileConfOptions
+		}{
+			{
+				name:     "when the option allows it",
+				contents: "log_location = gpperfmon/logs\n",
+				opt:      &idl.UpdateFileConfOptions{Pattern: `^log_location = /old$`, Replacement: `log_location = /new`, AllowNoMatch: true},
+			},
+			{
+				name:     "when the GUC already has the expected value",
+				contents: "port = 6000\n",
+				opt:      &idl.UpdateFileConfOptions{Pattern: `^(port = )5000$`, Replacement: `\16000`, Guc: "port", ExpectedValue: "6000"},
+			},
+			{
+				name:     "when primary_conninfo already has the expected port",
+				contents: "primary_conninfo = 'user=gpadmin host=sdw1 port=6000'\n",
+				opt:      &idl.UpdateFileConfOptions{Pattern: `(primary_conninfo .* port=)5000$`, Replacement: `\16000`, Guc: "primary_conninfo", ExpectedValue: "port=6000"},
+			},
+		}
+
+		for _, c := range cases {
+			t.Run(c.name, func(t *testing.T) {
+				dir := testutils.GetTempDir(t, "")
+				defer testutils.MustRemoveAll(t, dir)
+
+				path := filepath.Join(dir, "postgresql.conf")
+				testutils.MustWriteToFile(t, path, c.contents)
+				c.opt.Path = path
+
+				err := hub.UpdateConfigurationFile(context.Background(), []*idl.UpdateFileConfOptions{c.opt})
+				if err != nil {
+					t.Fatalf("unexpected error %+v", err)
+				}
+
+				contents := testutils.MustReadFile(t, path)
+				if contents != c.contents {
+					t.Errorf("got %q want %q", contents, c.contents)
+				}
+			})
+		}
+	})
+
+	t.Run("skips an option whose GUC already has its expected value", func(t *testing.T) {
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		path := filepath.Join(dir, "postgresql.conf")
+		testutils.MustWriteToFile(t, path, "port=6000\nport=6000\n")
+		testutils.MustWriteToFile(t, path+hub.BackupSuffix, "port=5000\n")
+
+		// the backup of the first run is neither overwritten nor refused
+		hub.SetBackupPolicy(hub.BackupPolicyFail)
+		defer hub.ResetBackupPolicy()
+
+		opt := &idl.UpdateFileConfOptions{
+			Path:             path,
+			Pattern:          `^(port=)5000$`,
+			Replacement:      `\16000`,
+			Reason:           hub.ReasonPortRewrite,
+			Guc:              "port",
+			ExpectedValue:    "6000",
+			ExpectedMatches:  1,
+			DuplicateMatches: idl.UpdateFileConfOptions_failDuplicateMatches,
+		}
+
+		reply, err := hub.UpdateConfigurationFileReply(context.Background(), []*idl.UpdateFileConfOptions{opt})
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		expected := []string{fmt.Sprintf(`%s for port-rewrite: port is already "6000"`, path)}
+		if !reflect.DeepEqual(reply.GetSatisfied(), expected) {
+			t.Errorf("got satisfied %q want %q", reply.GetSatisfied(), expected)
+		}
+
+		if len(reply.GetChangedPaths()) != 0 || len(reply.GetSkipped()) != 0 {
+			t.Errorf("got changed paths %q and skipped %q want none", reply.GetChangedPaths(), reply.GetSkipped())
+		}
+
+		contents := testutils.MustReadFile(t, path)
+		if contents != "port=6000\nport=6000\n" {
+			t.Errorf("got %q want the file unchanged", contents)
+		}
+
+		backup := testutils.MustReadFile(t, path+hub.BackupSuffix)
+		if backup != "port=5000\n" {
+			t.Errorf("got backup %q want %q", backup, "port=5000\n")
+		}
+	})
+
+	t.Run("errors when the GUC has neither its prior nor its expected value", func(t *testing.T) {
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		path := filepath.Join(dir, "postgresql.conf")
+		testutils.MustWriteToFile(t, path, "port=7000\n")
+
+		opt := &idl.UpdateFileConfOptions{Path: path, Pattern: `^(port=)5000$`, Replacement: `\16000`, Reason: hub.ReasonPortRewrite, Guc: "port", ExpectedValue: "6000"}
+		_, err := hub.UpdateConfigurationFileReply(context.Background(), []*idl.UpdateFileConfOptions{opt})
+		expected := fmt.Sprintf(`update %s for port-rewrite: pattern %q matched no lines and port is not already "6000"; replacement %q; port is set at line 1: "port=7000"`, path, `^(port=)5000$`, `\16000`)
+		if err == nil || err.Error() != expected {
+			t.Errorf("got error %v want %q", err, expected)
+		}
+	})
+
+	t.Run("appends the line of a missing GUC", func(t *testing.T) {
+		opt := func(path string) *idl.UpdateFileConfOptions {
+			return &idl.UpdateFileConfOptions{Path: path, Pattern: `^(gp_dbid=)2$`, Replacement: `\13`, Guc: "gp_dbid", ExpectedValue: "3", AppendIfMissing: "gp_dbid=3"}
+		}
+
+		cases := []struct {
+			name     string
+			contents string
+			expected string
+		}{
+			{name: "when the GUC is not set", contents: "port=6000\n", expected: "port=6000\ngp_dbid=3\n"},
+			{name: "when the file has no final newline", contents: "port=6000", expected: "port=6000\ngp_dbid=3\n"},
+			{name: "when the GUC is only commented out", contents: "#gp_dbid=2\n", expected: "#gp_dbid=2\ngp_dbid=3\n"},
+			{name: "not when the pattern matches", contents: "gp_dbid=2\n", expected: "gp_dbid=3\n"},
+		}
+
+		for _, c := range cases {
+			t.Run(c.name, func(t *testing.T) {
+				dir := testutils.GetTempDir(t, "")
+				defer testutils.MustRemoveAll(t, dir)
+
+				path := filepath.Join(dir, "internal.auto.conf")
+				testutils.MustWriteToFile(t, path, c.contents)
+
+				// rerunning leaves the line appended once
+				for i := 0; i < 2; i++ {
+					err := hub.UpdateConfigurationFile(context.Background(), []*idl.UpdateFileConfOptions{opt(path)})
+					if err != nil {
+						t.Fatalf("unexpected error %+v", err)
+					}
+				}
+
+				contents := testutils.MustReadFile(t, path)
+				if contents != c.expected {
+					t.Errorf("got %q want %q", contents, c.expected)
+				}
+			})
+		}
+	})
+
+	t.Run("does not append the line when the GUC is set to another value", func(t *testing.T) {
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		path := filepath.Join(dir, "internal.auto.conf")
+		testutils.MustWriteToFile(t, path, "gp_dbid=7\n")
+
+		err := hub.UpdateConfigurationFile(context.Background(), []*idl.UpdateFileConfOptions{{Path: path, Pattern: `^(gp_dbid=)2$`, Replacement: `\13`, Guc: "gp_dbid", AppendIfMissing: "gp_dbid=3"}})
+		if err == nil || !strings.Contains(err.Error(), "matched no lines") {
+			t.Errorf("expected error %v to contain %q", err, "matched no lines")
+		}
+
+		contents := testutils.MustReadFile(t, path)
+		if contents != "gp_dbid=7\n" {
+			t.Errorf("got %q want the file unchanged", contents)
+		}
+	})
+
+	t.Run("errors on a line to append that does not set the GUC", func(t *testing.T) {
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		path := filepath.Join(dir, "internal.auto.conf")
+		testutils.MustWriteToFile(t, path, "port=6000\n")
+
+		for _, opt := range []*idl.UpdateFileConfOptions{
+			{Path: path, Pattern: `^(gp_dbid=)2$`, Replacement: `\13`, AppendIfMissing: "gp_dbid=3"},
+			{Path: path, Pattern: `^(gp_dbid=)2$`, Replacement: `\13`, Guc: "gp_dbid", AppendIfMissing: "port=7000"},
+			{Path: path, Pattern: `^(gp_dbid=)2$`, Replacement: `\13`, Guc: "gp_dbid", AppendIfMissing: "gp_dbid=3\nport=7000"},
+		} {
+			err := hub.UpdateConfigurationFile(context.Background(), []*idl.UpdateFileConfOptions{opt})
+			if err == nil {
+				t.Errorf("expected an error appending %q", opt.GetAppendIfMissing())
+			}
+		}
+
+		contents := testutils.MustReadFile(t, path)
+		if contents != "port=6000\n" {
+			t.Errorf("got %q want the file unchanged", contents)
+		}
+	})
+
+	t.Run("errors when the GUC does not have the expected value", func(t *testing.T) {
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		path := filepath.Join(dir, "postgresql.conf")
+		testutils.MustWriteToFile(t, path, "port = 7000\n")
+
+		err := hub.UpdateConfigurationFile(context.Background(), []*idl.UpdateFileConfOptions{{Path: path, Pattern: `^(port = )5000$`, Replacement: `\16000`, Guc: "port", ExpectedValue: "6000"}})
+		if err == nil || !strings.Contains(err.Error(), "matched no lines") {
+			t.Errorf("expected error %v to contain %q", err, "matched no lines")
+		}
+	})
+}
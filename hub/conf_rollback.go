@@ -0,0 +1,114 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"os"
+	"sort"
+
+	"golang.org/x/xerrors"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
+	"github.com/greenplum-db/gpupgrade/utils/errorlist"
+)
+
+// expectedValueOptions returns the options of the edits with their new value
+// as the expected value so the agent can verify them after writing, and can
+// tell a file already updated by an earlier run from a pattern that does not
+// match. The options of conditional edits are unchanged since they may be
+// skipped.
+func expectedValueOptions(edits ConfPlan, opts []*idl.UpdateFileConfOptions) []*idl.UpdateFileConfOptions {
+	var expected []*idl.UpdateFileConfOptions
+	for i, opt := range opts {
+		if opt.GetGuc() == "" || opt.GetMatchCurrentValue() != "" {
+			expected = append(expected, opt)
+			continue
+		}
+
+		opt = proto.Clone(opt).(*idl.UpdateFileConfOptions)
+		opt.ExpectedValue = edits[i].NewValue
+		expected = append(expected, opt)
+	}
+
+	return expected
+}
+
+// ConfOriginal is the contents of a conf file before it is edited, which is
+// also the contents of its backup.
+type ConfOriginal struct {
+	Contents []byte
+	Mode     os.FileMode
+}
+
+// ReadConfOriginals reads each file edited by opts before editing so that it
+// can be rolled back when verifying the edit fails.
+func ReadConfOriginals(opts []*idl.UpdateFileConfOptions) (map[string]ConfOriginal, error) {
+	originals := make(map[string]ConfOriginal)
+	for _, opt := range opts {
+		if _, ok := originals[opt.GetPath()]; ok || optionalConfFileAbsent(opt) {
+			continue
+		}
+
+		info, err := utils.System.Stat(opt.GetPath())
+		if err != nil {
+			return nil, xerrors.Errorf("read original %s: %w", opt.GetPath(), err)
+		}
+
+		contents, err := utils.System.ReadFile(opt.GetPath())
+		if err != nil {
+			return nil, xerrors.Errorf("read original %s: %w", opt.GetPath(), err)
+		}
+
+		originals[opt.GetPath()] = ConfOriginal{Contents: contents, Mode: info.Mode().Perm()}
+	}
+
+	return originals, nil
+}
+
+// RollBackUnverifiedFiles restores each file with an option whose GUC was not
+// read back with its expected value to its original contents, so a failed
+// edit never leaves a bad conf file in place. It returns the sorted paths of
+// the restored files.
+func RollBackUnverifiedFiles(opts []*idl.UpdateFileConfOptions, values []*idl.ReadConfigurationReply_Value, originals map[string]ConfOriginal) ([]string, error) {
+	type key struct{ path, name string }
+	actuals := make(map[key]*idl.ReadConfigurationReply_Value)
+	for _, value := range values {
+		actuals[key{value.GetPath(), value.GetName()}] = value
+	}
+
+	unverified := make(map[string]bool)
+	for _, opt := range opts {
+		// an optional file that is absent is neither read back nor restored
+		if opt.GetExpectedValue() == "" || optionalConfFileAbsent(opt) {
+			continue
+		}
+
+		actual, found := writtenValue(actuals[key{opt.GetPath(), opt.GetGuc()}])
+		if !found || actual != opt.GetExpectedValue() {
+			unverified[opt.GetPath()] = true
+		}
+	}
+
+	var rolledBack []string
+	var err error
+	for path := range unverified {
+		original, ok := originals[path]
+		if !ok {
+			err = errorlist.Append(err, xerrors.Errorf("roll back %s: the original contents were not read", path))
+			continue
+		}
+
+		if wErr := utils.System.WriteFile(path, original.Contents, original.Mode); wErr != nil {
+			err = errorlist.Append(err, xerrors.Errorf("roll back %s: %w", path, wErr))
+			continue
+		}
+
+		rolledBack = append(rolledBack, path)
+	}
+
+	sort.Strings(rolledBack)
+	return rolledBack, err
+}
@@ -0,0 +1,108 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"google.golang.org/grpc"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/idl/mock_idl"
+	"github.com/greenplum-db/gpupgrade/testutils"
+)
+
+func TestRollBackUnverifiedFiles(t *testing.T) {
+	t.Run("restores the files that fail verification", func(t *testing.T) {
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		verified := filepath.Join(dir, "seg1", "postgresql.conf")
+		unverified := filepath.Join(dir, "seg2", "postgresql.conf")
+		testutils.MustCreateDir(t, filepath.Dir(verified))
+		testutils.MustCreateDir(t, filepath.Dir(unverified))
+		testutils.MustWriteToFile(t, verified, "port=5000\n")
+		testutils.MustWriteToFile(t, unverified, "port=5000\n")
+
+		opts := []*idl.UpdateFileConfOptions{
+			{Path: verified, Pattern: `(^port=)5000`, Replacement: `\16000`, Guc: "port", ExpectedValue: "6000"},
+			// the edit does not write the expected value forcing verification to fail
+			{Path: unverified, Pattern: `(^port=)5000`, Replacement: `\16000`, Guc: "port", ExpectedValue: "7000"},
+		}
+
+		originals, err := hub.ReadConfOriginals(opts)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		if err := hub.UpdateConfigurationFile(context.Background(), opts); err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		values, err := hub.ReadEditedGUCs(opts)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		rolledBack, err := hub.RollBackUnverifiedFiles(opts, values, originals)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		if !reflect.DeepEqual(rolledBack, []string{unverified}) {
+			t.Errorf("got rolled back %q want %q", rolledBack, []string{unverified})
+		}
+
+		for path, expected := range map[string]string{verified: "port=6000\n", unverified: "port=5000\n"} {
+			contents := testutils.MustReadFile(t, path)
+			if contents != expected {
+				t.Errorf("%s got %q want %q", path, contents, expected)
+			}
+		}
+	})
+
+	t.Run("reports the files rolled back by agents", func(t *testing.T) {
+		hub.SetVerifyAfterWrite(true)
+		defer hub.ResetVerifyAfterWrite()
+
+		intermediate := hub.MustCreateCluster(t, greenplum.SegConfigs{
+			{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: "/data/qddir/seg.HqtFHX54y0o.-1", Port: 50432, Role: greenplum.PrimaryRole},
+			{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg.HqtFHX54y0o.1", Port: 50434, Role: greenplum.PrimaryRole},
+		})
+		target := hub.MustCreateCluster(t, greenplum.SegConfigs{
+			{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: "/data/qddir/seg-1", Port: 15432, Role: greenplum.PrimaryRole},
+			{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg1", Port: 25433, Role: greenplum.PrimaryRole},
+		})
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		path := "/data/dbfast1/seg1/postgresql.conf"
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw1.EXPECT().UpdateConfiguration(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, req *idl.UpdateConfigurationRequest, _ ...grpc.CallOption) (*idl.UpdateConfigurationReply, error) {
+				if req.GetOptions()[0].GetExpectedValue() != "25433" {
+					t.Errorf("got expected value %q want %q", req.GetOptions()[0].GetExpectedValue(), "25433")
+				}
+
+				return &idl.UpdateConfigurationReply{
+					Values:          []*idl.ReadConfigurationReply_Value{{Path: path, Name: "port", Value: "50434", Found: true}},
+					RolledBackPaths: []string{path},
+				}, nil
+			})
+
+		err := hub.UpdatePostgresqlConfOnSegments(context.Background(), []*idl.Connection{{AgentClient: sdw1, Hostname: "sdw1"}}, intermediate, target)
+		expected := "rolled back /data/dbfast1/seg1/postgresql.conf on host sdw1 to its contents before the update"
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("got error %v want it to contain %q", err, expected)
+		}
+	})
+}
@@ -0,0 +1,49 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"context"
+	"time"
+
+	"github.com/greenplum-db/gpupgrade/idl"
+)
+
+// confRPCConcurrency caps the hosts sent a conf read or write at once. Zero
+// sends every host its request at once. The hub sets it from its
+// configuration.
+var confRPCConcurrency = 0
+
+func SetConfRPCConcurrency(concurrency int) {
+	confRPCConcurrency = concurrency
+}
+
+func ResetConfRPCConcurrency() {
+	confRPCConcurrency = 0
+}
+
+const DefaultConfHostTimeout = 5 * time.Minute
+
+// confHostTimeout bounds each conf read or write request to a host, so that a
+// host whose storage hangs mid-edit such as on a stuck NFS mount fails the
+// update instead of holding it open indefinitely. Each retry of a request is
+// given its own deadline. Zero does not time out. The hub sets it from its
+// configuration.
+var confHostTimeout = DefaultConfHostTimeout
+
+func SetConfHostTimeout(timeout time.Duration) {
+	confHostTimeout = timeout
+}
+
+func ResetConfHostTimeout() {
+	confHostTimeout = DefaultConfHostTimeout
+}
+
+// executeConfRPC sends the conf read, write or verify request of each host
+// bounded by the conf request cap, which does not apply to the other requests
+// to the agents. Each request bounds itself by the conf host timeout with
+// withConfRPCTimeout so that its retries get their own deadlines.
+func executeConfRPC(ctx context.Context, agentConns []*idl.Connection, executeRequest func(ctx context.Context, conn *idl.Connection) error, completed func(hostname string, err error), policy UnreachablePolicy) RPCResult {
+	return ExecuteRPCBounded(ctx, agentConns, executeRequest, completed, policy, confRPCConcurrency, 0)
+}
@@ -0,0 +1,54 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrConfRPCTimedOut = errors.New("conf request timed out")
+
+// ConfRPCTimeoutError is the backing error type for ErrConfRPCTimedOut,
+// naming the host and the operation that did not complete in time.
+type ConfRPCTimeoutError struct {
+	Hostname  string
+	Operation string
+	Timeout   time.Duration
+	Err       error
+}
+
+func (c *ConfRPCTimeoutError) Error() string {
+	return fmt.Sprintf("%s on host %s timed out after %s: %v", c.Operation, c.Hostname, c.Timeout, c.Err)
+}
+
+func (c *ConfRPCTimeoutError) Is(err error) bool {
+	return err == ErrConfRPCTimedOut
+}
+
+func (c *ConfRPCTimeoutError) Unwrap() error {
+	return c.Err
+}
+
+// withConfRPCTimeout calls request with ctx bounded by the conf host timeout.
+// Only the timeout is reported as such. A request failing as ctx itself was
+// canceled or reached its deadline is returned as is.
+func withConfRPCTimeout(ctx context.Context, hostname string, operation string, request func(ctx context.Context) error) error {
+	if confHostTimeout <= 0 {
+		return request(ctx)
+	}
+
+	timeout := confHostTimeout
+	requestCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err := request(requestCtx)
+	if err != nil && ctx.Err() == nil && errors.Is(requestCtx.Err(), context.DeadlineExceeded) {
+		return &ConfRPCTimeoutError{Hostname: hostname, Operation: operation, Timeout: timeout, Err: err}
+	}
+
+	return err
+}
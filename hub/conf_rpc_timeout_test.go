@@ -0,0 +1,115 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"google.golang.org/grpc"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/idl/mock_idl"
+)
+
+func TestConfRPCTimeout(t *testing.T) {
+	intermediate := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: "/data/qddir/seg.HqtFHX54y0o.-1", Port: 50432, Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg.HqtFHX54y0o.1", Port: 50434, Role: greenplum.PrimaryRole},
+	})
+	target := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: "/data/qddir/seg-1", Port: 15432, Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg1", Port: 25433, Role: greenplum.PrimaryRole},
+	})
+
+	// hangs until the request is canceled, as an agent blocked on its storage
+	hang := func(ctx context.Context, req *idl.UpdateConfigurationRequest, opts ...grpc.CallOption) (*idl.UpdateConfigurationReply, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	t.Run("names the host and operation that timed out", func(t *testing.T) {
+		hub.SetConfHostTimeout(10 * time.Millisecond)
+		defer hub.ResetConfHostTimeout()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw1.EXPECT().UpdateConfiguration(gomock.Any(), gomock.Any()).DoAndReturn(hang)
+
+		err := hub.UpdatePostgresqlConfOnSegments(context.Background(), []*idl.Connection{{AgentClient: sdw1, Hostname: "sdw1"}}, intermediate, target)
+		if !errors.Is(err, hub.ErrConfRPCTimedOut) {
+			t.Fatalf("got error %#v want %v", err, hub.ErrConfRPCTimedOut)
+		}
+
+		var timeoutErr *hub.ConfRPCTimeoutError
+		if !errors.As(err, &timeoutErr) {
+			t.Fatalf("got error %#v want type %T", err, timeoutErr)
+		}
+
+		if timeoutErr.Hostname != "sdw1" {
+			t.Errorf("got hostname %q want %q", timeoutErr.Hostname, "sdw1")
+		}
+
+		expected := "update conf files on host sdw1 timed out after 10ms"
+		if !strings.HasPrefix(err.Error(), expected) {
+			t.Errorf("got error %q want prefix %q", err.Error(), expected)
+		}
+	})
+
+	t.Run("does not report a canceled update as timed out", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw1.EXPECT().UpdateConfiguration(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, req *idl.UpdateConfigurationRequest, opts ...grpc.CallOption) (*idl.UpdateConfigurationReply, error) {
+				cancel()
+				return hang(ctx, req)
+			})
+
+		err := hub.UpdatePostgresqlConfOnSegments(ctx, []*idl.Connection{{AgentClient: sdw1, Hostname: "sdw1"}}, intermediate, target)
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("got error %#v want %v", err, context.Canceled)
+		}
+
+		if errors.Is(err, hub.ErrConfRPCTimedOut) {
+			t.Errorf("got error %v want it not to be a timeout", err)
+		}
+	})
+
+	t.Run("bounds each request by default", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw1.EXPECT().UpdateConfiguration(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, req *idl.UpdateConfigurationRequest, opts ...grpc.CallOption) (*idl.UpdateConfigurationReply, error) {
+				deadline, ok := ctx.Deadline()
+				if !ok {
+					t.Fatalf("expected the request to have a deadline")
+				}
+
+				if remaining := time.Until(deadline); remaining > hub.DefaultConfHostTimeout || remaining < hub.DefaultConfHostTimeout-time.Minute {
+					t.Errorf("got deadline in %s want %s", remaining, hub.DefaultConfHostTimeout)
+				}
+
+				return &idl.UpdateConfigurationReply{}, nil
+			})
+
+		err := hub.UpdatePostgresqlConfOnSegments(context.Background(), []*idl.Connection{{AgentClient: sdw1, Hostname: "sdw1"}}, intermediate, target)
+		if err != nil {
+			t.Errorf("unexpected error %+v", err)
+		}
+	})
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"log"
+
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+)
+
+// segmentRole is how the role of a segment is written in conf update
+// messages.
+func segmentRole(seg *greenplum.SegConfig) string {
+	if seg.IsMirror() {
+		return "mirror"
+	}
+
+	return "primary"
+}
+
+// forSegment attributes the edit to the segment it is made for so that its
+// result can be reported per role and content.
+func forSegment(edit ConfEdit, seg *greenplum.SegConfig) ConfEdit {
+	edit.Role = segmentRole(seg)
+	edit.ContentID = seg.ContentID
+	return edit
+}
+
+// checkSegmentConfPaths errors when edits of different segments on a host
+// target the same GUC of the same file. Each segment has its own data
+// directory, including a primary and mirror on the same host, so this only
+// happens when the recorded cluster is inconsistent and one segment's edit
+// would overwrite another's.
+func checkSegmentConfPaths(hostname string, edits ConfPlan) error {
+	type key struct{ path, guc string }
+	owners := make(map[key]ConfEdit)
+
+	for _, edit := range edits {
+		if edit.Role == "" {
+			continue
+		}
+
+		k := key{edit.Option.GetPath(), edit.Option.GetGuc()}
+		owner, ok := owners[k]
+		if !ok {
+			owners[k] = edit
+			continue
+		}
+
+		if owner.Role != edit.Role || owner.ContentID != edit.ContentID {
+			return xerrors.Errorf("%s with content %d and %s with content %d on host %s both edit %s in %s",
+				owner.Role, owner.ContentID, edit.Role, edit.ContentID, hostname, k.guc, k.path)
+		}
+	}
+
+	return nil
+}
+
+// reportSegmentConfResults logs the outcome of each segment edit of a host so
+// that the result for each role and content is unambiguous on hosts running
+// both primaries and mirrors.
+func reportSegmentConfResults(hostname string, edits ConfPlan, changedPaths []string) {
+	changed := make(map[string]bool)
+	for _, path := range changedPaths {
+		changed[path] = true
+	}
+
+	for _, edit := range edits {
+		if edit.Role == "" {
+			continue
+		}
+
+		if changed[edit.Option.GetPath()] {
+			log.Printf("%s with content %d on host %s: updated %s in %s from %s to %s",
+				edit.Role, edit.ContentID, hostname, edit.Option.GetGuc(), edit.Option.GetPath(), edit.OldValue, edit.NewValue)
+			continue
+		}
+
+		log.Printf("%s with content %d on host %s: left %s in %s unchanged",
+			edit.Role, edit.ContentID, hostname, edit.Option.GetGuc(), edit.Option.GetPath())
+	}
+}
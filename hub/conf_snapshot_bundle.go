@@ -0,0 +1,279 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/blang/semver/v4"
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
+	"github.com/greenplum-db/gpupgrade/utils/errorlist"
+)
+
+// snapshotConfBundle has UpdateConfFiles capture every conf file it is about
+// to edit across the cluster before and after the update into a single
+// bundle in the state directory. It is opt-in since the bundle holds a copy
+// of each conf file twice.
+var snapshotConfBundle = false
+
+func SetSnapshotConfBundle(snapshot bool) {
+	snapshotConfBundle = snapshot
+}
+
+func ResetSnapshotConfBundle() {
+	snapshotConfBundle = false
+}
+
+// confSnapshotManifest is the name of the bundle entry indexing the files.
+const confSnapshotManifest = "manifest.json"
+
+// ConfSnapshotFile is the manifest entry of a conf file in a snapshot bundle.
+type ConfSnapshotFile struct {
+	Hostname string
+	Path     string
+	Mode     os.FileMode
+	// Before and After are the names of the contents within the bundle, empty
+	// when the file did not exist or was not captured.
+	Before         string `json:",omitempty"`
+	BeforeChecksum string `json:",omitempty"`
+	After          string `json:",omitempty"`
+	AfterChecksum  string `json:",omitempty"`
+	Changed        bool
+}
+
+// ConfSnapshot is the captured contents of the conf files keyed by host and
+// then by path.
+type ConfSnapshot map[string]map[string]*idl.SnapshotConfFilesReply_File
+
+// ConfSnapshotBundlePath returns the bundle in dir named by when it was taken.
+func ConfSnapshotBundlePath(dir string) string {
+	return filepath.Join(dir, fmt.Sprintf("conf-snapshot-%s.tar.gz", utils.System.Now().Format("20060102T150405")))
+}
+
+// confSnapshotBundle is a snapshot bundle in progress. A nil
+// confSnapshotBundle is disabled.
+type confSnapshotBundle struct {
+	coordinatorHost string
+	paths           map[string][]string
+	before          ConfSnapshot
+}
+
+// beginConfSnapshotBundle captures the conf files the update is about to
+// edit when snapshotting is enabled. On a resumed update the files of the
+// completed phases are captured as they are at the time of the resume.
+func beginConfSnapshotBundle(ctx context.Context, agentConns []*idl.Connection, version semver.Version, intermediate *greenplum.Cluster, target *greenplum.Cluster) (*confSnapshotBundle, error) {
+	if !snapshotConfBundle {
+		return nil, nil
+	}
+
+	plan, err := PlanConfFiles(version, intermediate, target)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &confSnapshotBundle{coordinatorHost: target.CoordinatorHostname(), paths: snapshotPaths(plan)}
+	b.before, err = captureConfSnapshot(ctx, agentConns, b.coordinatorHost, b.paths, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// finish captures the conf files after the update and writes the bundle to
+// the state directory. The failed hosts are not captured again since they
+// are likely unreachable.
+func (b *confSnapshotBundle) finish(ctx context.Context, agentConns []*idl.Connection, failed []string, w io.Writer) error {
+	if b == nil {
+		return nil
+	}
+
+	skipped := make(map[string]bool)
+	for _, host := range failed {
+		skipped[host] = true
+	}
+
+	after, err := captureConfSnapshot(ctx, agentConns, b.coordinatorHost, b.paths, skipped)
+	if err != nil {
+		return err
+	}
+
+	bundle := ConfSnapshotBundlePath(utils.GetStateDir())
+	if err := WriteConfSnapshotBundle(bundle, b.paths, b.before, after); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "wrote the conf snapshot bundle to %s\n", bundle)
+	return nil
+}
+
+// SnapshotConfFiles returns the contents and mode of each file. A missing
+// file is reported as not found rather than as an error since, for example,
+// a conf file may be created by the update.
+func SnapshotConfFiles(paths []string) ([]*idl.SnapshotConfFilesReply_File, error) {
+	var files []*idl.SnapshotConfFilesReply_File
+	var err error
+
+	for _, path := range paths {
+		info, sErr := utils.System.Stat(path)
+		if errors.Is(sErr, os.ErrNotExist) {
+			files = append(files, &idl.SnapshotConfFilesReply_File{Path: path})
+			continue
+		}
+
+		if sErr != nil {
+			err = errorlist.Append(err, xerrors.Errorf("snapshot %s: %w", path, sErr))
+			continue
+		}
+
+		contents, rErr := utils.System.ReadFile(path)
+		if rErr != nil {
+			err = errorlist.Append(err, xerrors.Errorf("snapshot %s: %w", path, rErr))
+			continue
+		}
+
+		files = append(files, &idl.SnapshotConfFilesReply_File{
+			Path:     path,
+			Found:    true,
+			Contents: contents,
+			Mode:     uint32(info.Mode().Perm()),
+		})
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+// snapshotPaths returns the sorted unique paths of the plan keyed by host.
+func snapshotPaths(plan ConfPlan) map[string][]string {
+	seen := make(map[string]bool)
+	paths := make(map[string][]string)
+	for _, edit := range plan {
+		key := edit.Hostname + ":" + edit.Option.GetPath()
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		paths[edit.Hostname] = append(paths[edit.Hostname], edit.Option.GetPath())
+	}
+
+	for _, hostPaths := range paths {
+		sort.Strings(hostPaths)
+	}
+
+	return paths
+}
+
+// captureConfSnapshot captures the paths of each host other than skipped.
+// The coordinator host is read locally since the hub runs there, and every
+// other host through its agent.
+func captureConfSnapshot(ctx context.Context, agentConns []*idl.Connection, coordinatorHost string, paths map[string][]string, skipped map[string]bool) (ConfSnapshot, error) {
+	var mutex sync.Mutex
+	snapshot := make(ConfSnapshot)
+	add := func(host string, files []*idl.SnapshotConfFilesReply_File) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		snapshot[host] = make(map[string]*idl.SnapshotConfFilesReply_File)
+		for _, file := range files {
+			snapshot[host][file.GetPath()] = file
+		}
+	}
+
+	if !skipped[coordinatorHost] && len(paths[coordinatorHost]) > 0 {
+		files, err := SnapshotConfFiles(paths[coordinatorHost])
+		if err != nil {
+			return nil, xerrors.Errorf("snapshot conf files on host %s: %w", coordinatorHost, err)
+		}
+
+		add(coordinatorHost, files)
+	}
+
+	request := func(ctx context.Context, conn *idl.Connection) error {
+		if conn.Hostname == coordinatorHost || skipped[conn.Hostname] || len(paths[conn.Hostname]) == 0 {
+			return nil
+		}
+
+		reply, err := conn.AgentClient.SnapshotConfFiles(ctx, &idl.SnapshotConfFilesRequest{Paths: paths[conn.Hostname]})
+		if err != nil {
+			return xerrors.Errorf("snapshot conf files on host %s: %w", conn.Hostname, err)
+		}
+
+		add(conn.Hostname, reply.GetFiles())
+		return nil
+	}
+
+	if err := ExecuteRPCContext(ctx, agentConns, request); err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+// WriteConfSnapshotBundle writes the before and after contents of each path
+// to a compressed tarball with a manifest listing which files changed. The
+// contents are stored under before/<hostname>/<path> and
+// after/<hostname>/<path>.
+func WriteConfSnapshotBundle(bundle string, paths map[string][]string, before ConfSnapshot, after ConfSnapshot) error {
+	var hosts []string
+	for host := range paths {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+
+	var manifest []ConfSnapshotFile
+	var files []archiveEntry
+	for _, host := range hosts {
+		for _, path := range paths[host] {
+			entry := ConfSnapshotFile{Hostname: host, Path: path}
+			// bundle entries use forward slashes regardless of the OS
+			name := host + "/" + strings.TrimPrefix(filepath.ToSlash(path), "/")
+
+			if file := before[host][path]; file.GetFound() {
+				entry.Before = "before/" + name
+				entry.BeforeChecksum = checksum(file.GetContents())
+				entry.Mode = os.FileMode(file.GetMode())
+				files = append(files, archiveEntry{Name: entry.Before, Data: file.GetContents(), Mode: entry.Mode})
+			}
+
+			if file := after[host][path]; file.GetFound() {
+				entry.After = "after/" + name
+				entry.AfterChecksum = checksum(file.GetContents())
+				entry.Mode = os.FileMode(file.GetMode())
+				files = append(files, archiveEntry{Name: entry.After, Data: file.GetContents(), Mode: entry.Mode})
+			}
+
+			entry.Changed = entry.BeforeChecksum != entry.AfterChecksum
+			manifest = append(manifest, entry)
+		}
+	}
+
+	index, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return xerrors.Errorf("marshal %s manifest: %w", bundle, err)
+	}
+
+	if err := utils.System.MkdirAll(filepath.Dir(bundle), 0700); err != nil {
+		return err
+	}
+
+	return writeTarGz(bundle, append([]archiveEntry{{Name: confSnapshotManifest, Data: index, Mode: 0600}}, files...))
+}
@@ -0,0 +1,167 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/testutils"
+)
+
+func TestSnapshotConfFiles(t *testing.T) {
+	dir := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, dir)
+
+	postgresqlConf := filepath.Join(dir, "postgresql.conf")
+	testutils.MustWriteToFile(t, postgresqlConf, "port=5000\n")
+	if err := os.Chmod(postgresqlConf, 0640); err != nil {
+		t.Fatalf("unexpected error %+v", err)
+	}
+
+	missing := filepath.Join(dir, "recovery.conf")
+
+	files, err := hub.SnapshotConfFiles([]string{postgresqlConf, missing})
+	if err != nil {
+		t.Fatalf("unexpected error %+v", err)
+	}
+
+	expected := []*idl.SnapshotConfFilesReply_File{
+		{Path: postgresqlConf, Found: true, Contents: []byte("port=5000\n"), Mode: 0640},
+		{Path: missing},
+	}
+	if !reflect.DeepEqual(files, expected) {
+		t.Errorf("got %v want %v", files, expected)
+	}
+}
+
+func TestWriteConfSnapshotBundle(t *testing.T) {
+	dir := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, dir)
+
+	paths := map[string][]string{
+		"sdw1": {"/data/primary/gpseg0/postgresql.conf", "/data/primary/gpseg0/gpperfmon.conf"},
+		"cdw":  {"/data/qddir/seg-1/postgresql.conf"},
+	}
+
+	before := hub.ConfSnapshot{
+		"cdw": {
+			"/data/qddir/seg-1/postgresql.conf": {Path: "/data/qddir/seg-1/postgresql.conf", Found: true, Contents: []byte("port=5432\n"), Mode: 0600},
+		},
+		"sdw1": {
+			"/data/primary/gpseg0/postgresql.conf": {Path: "/data/primary/gpseg0/postgresql.conf", Found: true, Contents: []byte("port=5000\n"), Mode: 0600},
+			"/data/primary/gpseg0/gpperfmon.conf":  {Path: "/data/primary/gpseg0/gpperfmon.conf", Found: true, Contents: []byte("log_location = gpperfmon/logs\n"), Mode: 0600},
+		},
+	}
+
+	// sdw1 failed the update so was not captured afterwards
+	after := hub.ConfSnapshot{
+		"cdw": {
+			"/data/qddir/seg-1/postgresql.conf": {Path: "/data/qddir/seg-1/postgresql.conf", Found: true, Contents: []byte("port=6432\n"), Mode: 0600},
+		},
+	}
+
+	bundle := filepath.Join(dir, "bundle", "conf-snapshot.tar.gz")
+	err := hub.WriteConfSnapshotBundle(bundle, paths, before, after)
+	if err != nil {
+		t.Fatalf("unexpected error %+v", err)
+	}
+
+	entries := readTarGz(t, bundle)
+
+	var manifest []hub.ConfSnapshotFile
+	if err := json.Unmarshal(entries["manifest.json"], &manifest); err != nil {
+		t.Fatalf("unexpected error %+v", err)
+	}
+
+	expected := []hub.ConfSnapshotFile{
+		{
+			Hostname:       "cdw",
+			Path:           "/data/qddir/seg-1/postgresql.conf",
+			Mode:           0600,
+			Before:         "before/cdw/data/qddir/seg-1/postgresql.conf",
+			BeforeChecksum: "e6cf4d75ee104317cbb5fee26d50beb0c7575ff32f82a764fcb30ef2dac5873d",
+			After:          "after/cdw/data/qddir/seg-1/postgresql.conf",
+			AfterChecksum:  "b58d9eae235dcbacb4088b345d95c20429fbf153bf51cd0860895ec0717bb201",
+			Changed:        true,
+		},
+		{
+			Hostname:       "sdw1",
+			Path:           "/data/primary/gpseg0/postgresql.conf",
+			Mode:           0600,
+			Before:         "before/sdw1/data/primary/gpseg0/postgresql.conf",
+			BeforeChecksum: "401a3fab0abb88fc67ae8d34b58811bcea56ebaf003e425d5d513a364cbf813a",
+			Changed:        true,
+		},
+		{
+			Hostname:       "sdw1",
+			Path:           "/data/primary/gpseg0/gpperfmon.conf",
+			Mode:           0600,
+			Before:         "before/sdw1/data/primary/gpseg0/gpperfmon.conf",
+			BeforeChecksum: "8bc5ee9e8a967ab580f9c586c47ab191746fa283e85f82ac51677a9016995ded",
+			Changed:        true,
+		},
+	}
+	if !reflect.DeepEqual(manifest, expected) {
+		t.Errorf("got manifest %+v want %+v", manifest, expected)
+	}
+
+	for name, contents := range map[string]string{
+		"before/cdw/data/qddir/seg-1/postgresql.conf":     "port=5432\n",
+		"after/cdw/data/qddir/seg-1/postgresql.conf":      "port=6432\n",
+		"before/sdw1/data/primary/gpseg0/postgresql.conf": "port=5000\n",
+		"before/sdw1/data/primary/gpseg0/gpperfmon.conf":  "log_location = gpperfmon/logs\n",
+	} {
+		if string(entries[name]) != contents {
+			t.Errorf("got %s contents %q want %q", name, entries[name], contents)
+		}
+	}
+
+	if len(entries) != 5 {
+		t.Errorf("got %d entries want 5", len(entries))
+	}
+}
+
+func readTarGz(t *testing.T, path string) map[string][]byte {
+	t.Helper()
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("unexpected error %+v", err)
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatalf("unexpected error %+v", err)
+	}
+
+	entries := make(map[string][]byte)
+	reader := tar.NewReader(gz)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		entries[header.Name], err = io.ReadAll(reader)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+	}
+
+	return entries
+}
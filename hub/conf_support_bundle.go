@@ -0,0 +1,381 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/blang/semver/v4"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
+)
+
+// recordConfSupportBundle has UpdateConfFiles export a support bundle to the
+// state directory when it finishes, whether or not it succeeded, so that the
+// update can be replayed outside of the cluster when filing a support case.
+var recordConfSupportBundle = false
+
+func SetRecordConfSupportBundle(record bool) {
+	recordConfSupportBundle = record
+}
+
+func ResetRecordConfSupportBundle() {
+	recordConfSupportBundle = false
+}
+
+// confSupportRecordName is the bundle entry holding the ConfSupportRecord.
+// The conf files as they were before the update are stored beside it under
+// files/<hostname>/<path>, laid out like a SimulateConfFiles fixture.
+const confSupportRecordName = "support.json"
+
+// ConfSupportEdit is a planned edit as recorded in a support bundle.
+type ConfSupportEdit struct {
+	Hostname string
+	Path     string
+	GUC      string `json:",omitempty"`
+	OldValue string `json:",omitempty"`
+	NewValue string `json:",omitempty"`
+	Reason   string `json:",omitempty"`
+}
+
+// ConfHostResult is the outcome of a phase of the conf update on a host.
+type ConfHostResult struct {
+	Phase      string
+	Hostname   string
+	Changed    []string `json:",omitempty"`
+	Skipped    []string `json:",omitempty"`
+	RolledBack []string `json:",omitempty"`
+	Error      string   `json:",omitempty"`
+}
+
+// ConfSupportRecord is a self-contained record of a conf update. The target
+// cluster is recorded with any target port map already applied, along with
+// the other settings the plan depends on, so that replaying it reconstructs
+// the same plan.
+type ConfSupportRecord struct {
+	Version      string
+	Intermediate *greenplum.Cluster
+	Target       *greenplum.Cluster
+	Custom       []CustomConfEdit `json:",omitempty"`
+	Exclusions   ConfExclusions
+	Order        ConfOrder
+	Core         bool
+	Plan         []ConfSupportEdit
+	Results      []ConfHostResult
+	// AgentFeatures are the conf update features reported by the agent of
+	// each host, which identify what its version supports. An agent
+	// predating capability reporting has none.
+	AgentFeatures map[string][]string
+	Error         string `json:",omitempty"`
+}
+
+// ConfSupportBundlePath returns the bundle in dir named by when it was taken.
+func ConfSupportBundlePath(dir string) string {
+	return filepath.Join(dir, fmt.Sprintf("conf-support-%s.tar.gz", utils.System.Now().Format("20060102T150405")))
+}
+
+// confSupportBundle is a support bundle being recorded. A nil
+// confSupportBundle is disabled.
+type confSupportBundle struct {
+	mutex  sync.Mutex
+	record ConfSupportRecord
+	paths  map[string][]string
+	before ConfSnapshot
+	phase  string
+}
+
+// beginConfSupportBundle records the plan, the agent features and the conf
+// files about to be edited when recording is enabled.
+func beginConfSupportBundle(ctx context.Context, agentConns []*idl.Connection, version semver.Version, intermediate *greenplum.Cluster, target *greenplum.Cluster) (*confSupportBundle, error) {
+	if !recordConfSupportBundle {
+		return nil, nil
+	}
+
+	plan, err := PlanConfFiles(version, intermediate, target)
+	if err != nil {
+		return nil, err
+	}
+
+	features, err := agentConfFeatures(ctx, agentConns)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &confSupportBundle{
+		record: ConfSupportRecord{
+			Version:       version.String(),
+			Intermediate:  intermediate,
+			Target:        target,
+			Custom:        customConfEdits,
+			Exclusions:    confExclusions,
+			Order:         confOrder,
+			Core:          coreConfMode,
+			Plan:          supportEdits(plan),
+			AgentFeatures: features,
+		},
+		paths: snapshotPaths(plan),
+	}
+
+	b.before, err = captureConfSnapshot(ctx, agentConns, target.CoordinatorHostname(), b.paths, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+func (b *confSupportBundle) startPhase(name string) {
+	if b == nil {
+		return
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.phase = name
+}
+
+// hostCompleted records the outcome of the current phase on a host.
+func (b *confSupportBundle) hostCompleted(result ConfHostResult, err error) {
+	if b == nil {
+		return
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	result.Phase = b.phase
+	if err != nil {
+		result.Error = err.Error()
+	}
+	b.record.Results = append(b.record.Results, result)
+}
+
+// finish writes the bundle to the state directory along with the error the
+// update finished with.
+func (b *confSupportBundle) finish(updateErr error, w io.Writer) error {
+	if b == nil {
+		return nil
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if updateErr != nil {
+		b.record.Error = updateErr.Error()
+	}
+
+	bundle := ConfSupportBundlePath(utils.GetStateDir())
+	if err := WriteConfSupportBundle(bundle, b.record, b.paths, b.before); err != nil {
+		return xerrors.Errorf("write conf support bundle: %w", err)
+	}
+
+	fmt.Fprintf(w, "wrote the conf support bundle to %s\n", bundle)
+	return nil
+}
+
+// WriteConfSupportBundle writes the record and the captured conf files of
+// each path to a compressed tarball.
+func WriteConfSupportBundle(bundle string, record ConfSupportRecord, paths map[string][]string, files ConfSnapshot) error {
+	contents, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return xerrors.Errorf("marshal %s record: %w", bundle, err)
+	}
+
+	var hosts []string
+	for host := range paths {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+
+	entries := []archiveEntry{{Name: confSupportRecordName, Data: contents, Mode: 0600}}
+	for _, host := range hosts {
+		for _, path := range paths[host] {
+			file := files[host][path]
+			if !file.GetFound() {
+				continue
+			}
+
+			// bundle entries use forward slashes regardless of the OS
+			name := "files/" + host + "/" + strings.TrimPrefix(filepath.ToSlash(path), "/")
+			entries = append(entries, archiveEntry{Name: name, Data: file.GetContents(), Mode: os.FileMode(file.GetMode())})
+		}
+	}
+
+	if err := utils.System.MkdirAll(filepath.Dir(bundle), 0700); err != nil {
+		return err
+	}
+
+	return writeTarGz(bundle, entries)
+}
+
+// ReplayConfSupportBundle reproduces a recorded conf update in a sandbox. The
+// recorded conf files are written under outputDir in the layout of a
+// SimulateConfFiles fixture, the plan is reconstructed from the recorded
+// clusters and settings, and its edits are applied to those files. Any
+// differences between the reconstructed and recorded plans are written to w
+// since they point to a behavior change between the recording and replaying
+// versions of gpupgrade.
+func ReplayConfSupportBundle(bundle string, outputDir string, w io.Writer) error {
+	entries, err := readTarGz(bundle)
+	if err != nil {
+		return err
+	}
+
+	contents, ok := entries[confSupportRecordName]
+	if !ok {
+		return xerrors.Errorf("%s is missing from %s", confSupportRecordName, bundle)
+	}
+
+	var record ConfSupportRecord
+	if err := json.Unmarshal(contents, &record); err != nil {
+		return xerrors.Errorf("read %s record: %w", bundle, err)
+	}
+
+	version, err := semver.Parse(record.Version)
+	if err != nil {
+		return xerrors.Errorf("read %s record: %w", bundle, err)
+	}
+
+	for name, data := range entries {
+		relPath, ok := strings.CutPrefix(name, "files/")
+		if !ok {
+			continue
+		}
+
+		if !filepath.IsLocal(relPath) {
+			return xerrors.Errorf("%s has entry %q outside of the recorded files", bundle, name)
+		}
+
+		path := filepath.Join(outputDir, filepath.FromSlash(relPath))
+		if err := utils.System.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			return err
+		}
+
+		if err := utils.System.WriteFile(path, data, 0600); err != nil {
+			return err
+		}
+	}
+
+	defer replaySettings(record)()
+
+	plan, err := PlanConfFiles(version, record.Intermediate, record.Target)
+	if err != nil {
+		return err
+	}
+
+	for _, diff := range diffSupportEdits(record.Plan, supportEdits(plan)) {
+		fmt.Fprintln(w, diff)
+	}
+
+	if err := UpdateConfigurationFile(context.Background(), simulatedOptions(outputDir, plan)); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "replayed %d conf edits to %s\n", len(plan), outputDir)
+	return nil
+}
+
+// replaySettings applies the recorded settings the plan depends on and
+// returns a function restoring the current ones. The recorded target already
+// has the target port map applied so none is used.
+func replaySettings(record ConfSupportRecord) func() {
+	custom, exclusions, order, core, ports := customConfEdits, confExclusions, confOrder, coreConfMode, targetPortMap
+	customConfEdits, confExclusions, confOrder, coreConfMode, targetPortMap = record.Custom, record.Exclusions, record.Order, record.Core, nil
+
+	return func() {
+		customConfEdits, confExclusions, confOrder, coreConfMode, targetPortMap = custom, exclusions, order, core, ports
+	}
+}
+
+func supportEdits(plan ConfPlan) []ConfSupportEdit {
+	var edits []ConfSupportEdit
+	for _, edit := range plan {
+		edits = append(edits, ConfSupportEdit{
+			Hostname: edit.Hostname,
+			Path:     edit.Option.GetPath(),
+			GUC:      edit.Option.GetGuc(),
+			OldValue: edit.OldValue,
+			NewValue: edit.NewValue,
+			Reason:   edit.Option.GetReason(),
+		})
+	}
+
+	return edits
+}
+
+// diffSupportEdits describes the edits only in the recorded or only in the
+// replayed plan.
+func diffSupportEdits(recorded []ConfSupportEdit, replayed []ConfSupportEdit) []string {
+	counts := make(map[ConfSupportEdit]int)
+	for _, edit := range recorded {
+		counts[edit]++
+	}
+
+	for _, edit := range replayed {
+		counts[edit]--
+	}
+
+	describe := func(edit ConfSupportEdit) string {
+		return fmt.Sprintf("%s in %s on host %s from %q to %q", edit.GUC, edit.Path, edit.Hostname, edit.OldValue, edit.NewValue)
+	}
+
+	var diffs []string
+	for _, edit := range recorded {
+		if counts[edit] > 0 {
+			counts[edit]--
+			diffs = append(diffs, "recorded edit is not replayed: "+describe(edit))
+		}
+	}
+
+	for _, edit := range replayed {
+		if counts[edit] < 0 {
+			counts[edit]++
+			diffs = append(diffs, "replayed edit was not recorded: "+describe(edit))
+		}
+	}
+
+	return diffs
+}
+
+// agentConfFeatures returns the conf update features of the agent of each
+// host.
+func agentConfFeatures(ctx context.Context, agentConns []*idl.Connection) (map[string][]string, error) {
+	var mutex sync.Mutex
+	features := make(map[string][]string)
+
+	request := func(ctx context.Context, conn *idl.Connection) error {
+		reply, err := conn.AgentClient.GetCapabilities(ctx, &idl.GetCapabilitiesRequest{})
+		if status.Code(err) == codes.Unimplemented {
+			reply, err = &idl.GetCapabilitiesReply{}, nil
+		}
+
+		if err != nil {
+			return xerrors.Errorf("get capabilities of the agent on host %s: %w", conn.Hostname, err)
+		}
+
+		mutex.Lock()
+		defer mutex.Unlock()
+		features[conn.Hostname] = reply.GetFeatures()
+		return nil
+	}
+
+	if err := ExecuteRPCContext(ctx, agentConns, request); err != nil {
+		return nil, err
+	}
+
+	return features, nil
+}
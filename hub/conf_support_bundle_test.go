@@ -0,0 +1,298 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blang/semver/v4"
+
+	"github.com/greenplum-db/gpupgrade/config"
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/step"
+	"github.com/greenplum-db/gpupgrade/testutils"
+	"github.com/greenplum-db/gpupgrade/utils"
+)
+
+func TestConfSupportBundle(t *testing.T) {
+	intermediate := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: "/data/qddir/seg.HqtFHX54y0o.-1", Port: 50432, Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg.HqtFHX54y0o.1", Port: 50434, Role: greenplum.PrimaryRole},
+	})
+
+	target := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: "/data/qddir/seg-1", Port: 15432, Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg1", Port: 25433, Role: greenplum.PrimaryRole},
+	})
+
+	paths := map[string][]string{
+		"coordinator": {"/data/qddir/seg-1/postgresql.conf"},
+		"sdw1":        {"/data/dbfast1/seg1/postgresql.conf"},
+	}
+
+	files := hub.ConfSnapshot{
+		"coordinator": {
+			"/data/qddir/seg-1/postgresql.conf": {Path: "/data/qddir/seg-1/postgresql.conf", Found: true, Contents: []byte("port=50432\n"), Mode: 0600},
+		},
+		"sdw1": {
+			"/data/dbfast1/seg1/postgresql.conf": {Path: "/data/dbfast1/seg1/postgresql.conf", Found: true, Contents: []byte("port=50434\n"), Mode: 0600},
+		},
+	}
+
+	record := hub.ConfSupportRecord{
+		Version:      "7.0.0",
+		Intermediate: intermediate,
+		Target:       target,
+		Plan: []hub.ConfSupportEdit{
+			{Hostname: "coordinator", Path: "/data/qddir/seg-1/gpperfmon/conf/gpperfmon.conf", GUC: "log_location", NewValue: "/data/qddir/seg-1/gpperfmon/logs", Reason: hub.ReasonGpperfmonLogPath},
+			{Hostname: "coordinator", Path: "/data/qddir/seg-1/postgresql.conf", GUC: "port", OldValue: "50432", NewValue: "15432", Reason: hub.ReasonPortRewrite},
+			{Hostname: "sdw1", Path: "/data/dbfast1/seg1/postgresql.conf", GUC: "port", OldValue: "50434", NewValue: "25433", Reason: hub.ReasonPortRewrite},
+		},
+		Results: []hub.ConfHostResult{
+			{Phase: hub.ConfPhaseSegmentPostgresqlConf, Hostname: "sdw1", Error: "connection refused"},
+		},
+		AgentFeatures: map[string][]string{"sdw1": hub.AgentConfFeatures},
+		Error:         "connection refused",
+	}
+
+	t.Run("records the update and the conf files before it", func(t *testing.T) {
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		bundle := filepath.Join(dir, "conf-support.tar.gz")
+		err := hub.WriteConfSupportBundle(bundle, record, paths, files)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		entries := readTarGz(t, bundle)
+
+		var recorded hub.ConfSupportRecord
+		if err := json.Unmarshal(entries["support.json"], &recorded); err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		if recorded.Error != record.Error || len(recorded.Plan) != len(record.Plan) || len(recorded.Results) != 1 {
+			t.Errorf("got record %+v want %+v", recorded, record)
+		}
+
+		for name, contents := range map[string]string{
+			"files/coordinator/data/qddir/seg-1/postgresql.conf": "port=50432\n",
+			"files/sdw1/data/dbfast1/seg1/postgresql.conf":       "port=50434\n",
+		} {
+			if string(entries[name]) != contents {
+				t.Errorf("got %s contents %q want %q", name, entries[name], contents)
+			}
+		}
+	})
+
+	t.Run("replays the update against the recorded conf files", func(t *testing.T) {
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		bundle := filepath.Join(dir, "conf-support.tar.gz")
+		err := hub.WriteConfSupportBundle(bundle, record, paths, files)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		outputDir := filepath.Join(dir, "replay")
+		var out bytes.Buffer
+		err = hub.ReplayConfSupportBundle(bundle, outputDir, &out)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		for path, contents := range map[string]string{
+			"coordinator/data/qddir/seg-1/postgresql.conf": "port=15432\n",
+			"sdw1/data/dbfast1/seg1/postgresql.conf":       "port=25433\n",
+		} {
+			actual := testutils.MustReadFile(t, filepath.Join(outputDir, path))
+			if actual != contents {
+				t.Errorf("%s got %q, want %q", path, actual, contents)
+			}
+		}
+
+		// the edit of the absent gpperfmon.conf is replayed by skipping it
+		expected := "replayed 3 conf edits to " + outputDir + "\n"
+		if out.String() != expected {
+			t.Errorf("got output %q want %q", out.String(), expected)
+		}
+	})
+
+	t.Run("reports the differences from the recorded plan", func(t *testing.T) {
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		changed := record
+		changed.Plan = []hub.ConfSupportEdit{
+			record.Plan[0],
+			record.Plan[1],
+			{Hostname: "sdw1", Path: "/data/dbfast1/seg1/postgresql.conf", GUC: "port", OldValue: "50434", NewValue: "25434", Reason: hub.ReasonPortRewrite},
+		}
+
+		bundle := filepath.Join(dir, "conf-support.tar.gz")
+		err := hub.WriteConfSupportBundle(bundle, changed, paths, files)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		var out bytes.Buffer
+		err = hub.ReplayConfSupportBundle(bundle, filepath.Join(dir, "replay"), &out)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		for _, expected := range []string{
+			`recorded edit is not replayed: port in /data/dbfast1/seg1/postgresql.conf on host sdw1 from "50434" to "25434"`,
+			`replayed edit was not recorded: port in /data/dbfast1/seg1/postgresql.conf on host sdw1 from "50434" to "25433"`,
+		} {
+			if !strings.Contains(out.String(), expected) {
+				t.Errorf("expected output %q to contain %q", out.String(), expected)
+			}
+		}
+	})
+
+	t.Run("replays with the recorded settings", func(t *testing.T) {
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		excluded := record
+		excluded.Exclusions = hub.ConfExclusions{Contents: []int{0}}
+		excluded.Plan = record.Plan[:2]
+
+		bundle := filepath.Join(dir, "conf-support.tar.gz")
+		err := hub.WriteConfSupportBundle(bundle, excluded, paths, files)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		outputDir := filepath.Join(dir, "replay")
+		var out bytes.Buffer
+		err = hub.ReplayConfSupportBundle(bundle, outputDir, &out)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		actual := testutils.MustReadFile(t, filepath.Join(outputDir, "sdw1/data/dbfast1/seg1/postgresql.conf"))
+		if actual != "port=50434\n" {
+			t.Errorf("got %q want the excluded segment left unchanged", actual)
+		}
+
+		// the recorded settings do not leak into later updates
+		plan, err := hub.PlanConfFiles(semver.MustParse("7.0.0"), intermediate, target)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		if len(plan) != 3 {
+			t.Errorf("got %d planned edits want 3", len(plan))
+		}
+	})
+
+	t.Run("errors on entries outside of the recorded files", func(t *testing.T) {
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		escaping := hub.ConfSnapshot{
+			"..": {"/../etc/postgresql.conf": {Path: "/../etc/postgresql.conf", Found: true, Contents: []byte("port=1\n"), Mode: 0600}},
+		}
+
+		bundle := filepath.Join(dir, "conf-support.tar.gz")
+		err := hub.WriteConfSupportBundle(bundle, record, map[string][]string{"..": {"/../etc/postgresql.conf"}}, escaping)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		err = hub.ReplayConfSupportBundle(bundle, filepath.Join(dir, "replay"), &bytes.Buffer{})
+		if err == nil || !strings.Contains(err.Error(), "outside of the recorded files") {
+			t.Errorf("got error %v want an entry outside of the recorded files", err)
+		}
+	})
+}
+
+func TestUpdateConfFilesRecordsSupportBundle(t *testing.T) {
+	now := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	utils.System.Now = func() time.Time {
+		return now
+	}
+	defer utils.ResetSystemFunctions()
+
+	stateDir := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, stateDir)
+
+	resetEnv := testutils.SetEnv(t, "GPUPGRADE_HOME", stateDir)
+	defer resetEnv()
+
+	coordinatorDir := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, coordinatorDir)
+
+	coordinatorConf := filepath.Join(coordinatorDir, "postgresql.conf")
+	testutils.MustWriteToFile(t, coordinatorConf, "port=50432\n")
+
+	intermediate := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: coordinatorDir, Port: 50432, Role: greenplum.PrimaryRole},
+	})
+	target := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: coordinatorDir, Port: 15432, Role: greenplum.PrimaryRole},
+	})
+
+	hub.SetRecordConfSupportBundle(true)
+	defer hub.ResetRecordConfSupportBundle()
+
+	streams := &step.BufferedStreams{}
+	err := hub.UpdateConfFiles(context.Background(), nil, nil, streams, config.StateVersion, hub.ConfUpdateOptions{}, semver.MustParse("7.0.0"), intermediate, target)
+	if err != nil {
+		t.Fatalf("unexpected error %+v", err)
+	}
+
+	bundle := hub.ConfSupportBundlePath(stateDir)
+	if !strings.Contains(streams.StdoutBuf.String(), "wrote the conf support bundle to "+bundle) {
+		t.Errorf("expected stdout %q to report the bundle", streams.StdoutBuf.String())
+	}
+
+	entries := readTarGz(t, bundle)
+
+	var record hub.ConfSupportRecord
+	if err := json.Unmarshal(entries["support.json"], &record); err != nil {
+		t.Fatalf("unexpected error %+v", err)
+	}
+
+	expected := []hub.ConfHostResult{{
+		Phase:    hub.ConfPhaseCoordinator,
+		Hostname: "coordinator",
+		Changed:  []string{coordinatorConf},
+		Skipped:  []string{filepath.Join(coordinatorDir, "gpperfmon", "conf", "gpperfmon.conf") + " for " + hub.ReasonGpperfmonLogPath + ": the optional file is absent"},
+	}}
+	if !reflect.DeepEqual(record.Results, expected) {
+		t.Errorf("got results %+v want %+v", record.Results, expected)
+	}
+
+	name := "files/coordinator/" + strings.TrimPrefix(coordinatorConf, "/")
+	if string(entries[name]) != "port=50432\n" {
+		t.Errorf("got %s contents %q want the contents before the update", name, entries[name])
+	}
+
+	outputDir := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, outputDir)
+
+	err = hub.ReplayConfSupportBundle(bundle, outputDir, io.Discard)
+	if err != nil {
+		t.Fatalf("unexpected error %+v", err)
+	}
+
+	replayed := testutils.MustReadFile(t, filepath.Join(outputDir, "coordinator", coordinatorConf))
+	if replayed != testutils.MustReadFile(t, coordinatorConf) {
+		t.Errorf("got replayed contents %q want %q", replayed, testutils.MustReadFile(t, coordinatorConf))
+	}
+}
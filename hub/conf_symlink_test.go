@@ -0,0 +1,121 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/testutils"
+)
+
+func TestSymlinkedConfFiles(t *testing.T) {
+	dir := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, dir)
+
+	journal := filepath.Join(dir, hub.ConfJournalFileName)
+	hub.SetConfJournalPath(journal)
+	defer hub.ResetConfJournalPath()
+
+	// the conf file of the segment is a link to a central config location
+	dataDir := filepath.Join(dir, "seg0")
+	central := filepath.Join(dir, "central")
+	testutils.MustCreateDir(t, dataDir)
+	testutils.MustCreateDir(t, central)
+
+	path := filepath.Join(dataDir, "postgresql.conf")
+	real := filepath.Join(central, "seg0.conf")
+	testutils.MustWriteToFile(t, real, "port=5000\n")
+	if err := os.Symlink(real, path); err != nil {
+		t.Fatal(err)
+	}
+
+	reply, err := hub.UpdateConfigurationFileReply(context.Background(), []*idl.UpdateFileConfOptions{{
+		Path:        path,
+		DataDir:     dataDir,
+		Pattern:     `^(port=)5000$`,
+		Replacement: `\16000`,
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error %+v", err)
+	}
+
+	t.Run("edits the file the link points to and keeps the link", func(t *testing.T) {
+		target, err := os.Readlink(path)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		if target != real {
+			t.Errorf("got link to %q want %q", target, real)
+		}
+
+		contents := testutils.MustReadFile(t, real)
+		if contents != "port=6000\n" {
+			t.Errorf("got %q want %q", contents, "port=6000\n")
+		}
+	})
+
+	t.Run("backs up the file next to the file the link points to", func(t *testing.T) {
+		contents := testutils.MustReadFile(t, real+hub.BackupSuffix)
+		if contents != "port=5000\n" {
+			t.Errorf("got backup %q want %q", contents, "port=5000\n")
+		}
+
+		if _, err := os.Lstat(path + hub.BackupSuffix); !os.IsNotExist(err) {
+			t.Errorf("got %v want no backup next to the link", err)
+		}
+	})
+
+	t.Run("records the resolved path in the reply and the journal", func(t *testing.T) {
+		expected := map[string]string{path: real}
+		if !reflect.DeepEqual(reply.GetResolvedPaths(), expected) {
+			t.Errorf("got resolved paths %v want %v", reply.GetResolvedPaths(), expected)
+		}
+
+		lines, err := hub.ReadConfJournalLines()
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		records, err := hub.ParseConfJournal(lines)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		if len(records) != 1 || records[0].Path != path || records[0].Resolved != real {
+			t.Errorf("got records %+v want one of %s resolved to %s", records, path, real)
+		}
+	})
+
+	t.Run("reverts the file from the backup next to the file the link points to", func(t *testing.T) {
+		restored, err := hub.RestoreConfBackups([]string{path}, "")
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		if !reflect.DeepEqual(restored, []string{path}) {
+			t.Errorf("got restored %q want %q", restored, []string{path})
+		}
+
+		contents := testutils.MustReadFile(t, real)
+		if contents != "port=5000\n" {
+			t.Errorf("got %q want %q", contents, "port=5000\n")
+		}
+
+		info, err := os.Lstat(path)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		if info.Mode()&os.ModeSymlink == 0 {
+			t.Errorf("got mode %v want the link kept", info.Mode())
+		}
+	})
+}
@@ -0,0 +1,110 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"log"
+	"path/filepath"
+	"syscall"
+
+	"golang.org/x/xerrors"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
+)
+
+// confTempDir is where the temporary file of an atomic conf rewrite is
+// written before it is renamed over the conf file. When empty it is written
+// next to the conf file.
+var confTempDir = ""
+
+func SetConfTempDir(dir string) {
+	confTempDir = dir
+}
+
+func ResetConfTempDir() {
+	confTempDir = ""
+}
+
+// tempDirOption returns the option sent to the agents, naming the temp dir
+// when one is configured.
+func tempDirOption(opt *idl.UpdateFileConfOptions) *idl.UpdateFileConfOptions {
+	if confTempDir == "" {
+		return opt
+	}
+
+	withTempDir := proto.Clone(opt).(*idl.UpdateFileConfOptions)
+	withTempDir.TempDir = confTempDir
+	return withTempDir
+}
+
+// confOptionsTempDir returns the temp dir named by any of opts, which edit
+// the same file.
+func confOptionsTempDir(opts []*idl.UpdateFileConfOptions) string {
+	for _, opt := range opts {
+		if opt.GetTempDir() != "" {
+			return opt.GetTempDir()
+		}
+	}
+
+	return ""
+}
+
+// ConfTempDir returns the directory for the temporary file of an atomic
+// rewrite of path, which is tempDir such as one sent by the hub or else the
+// configured confTempDir. Since a rename is only atomic within a filesystem
+// the directory is only used when it is on the same filesystem as path;
+// otherwise it falls back to the directory of path with a warning.
+func ConfTempDir(path string, tempDir string) string {
+	if tempDir == "" {
+		tempDir = confTempDir
+	}
+
+	dir := filepath.Dir(path)
+	if tempDir == "" {
+		return dir
+	}
+
+	same, err := sameFilesystem(tempDir, dir)
+	if err != nil {
+		log.Printf("Warning: writing the temporary file for %s next to it instead of in %s: %v", path, tempDir, err)
+		return dir
+	}
+
+	if !same {
+		log.Printf("Warning: writing the temporary file for %s next to it since %s is on a different filesystem", path, tempDir)
+		return dir
+	}
+
+	return tempDir
+}
+
+func sameFilesystem(a string, b string) (bool, error) {
+	aDevice, err := device(a)
+	if err != nil {
+		return false, err
+	}
+
+	bDevice, err := device(b)
+	if err != nil {
+		return false, err
+	}
+
+	return aDevice == bDevice, nil
+}
+
+func device(path string) (uint64, error) {
+	info, err := utils.System.Stat(path)
+	if err != nil {
+		return 0, err
+	}
+
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, xerrors.Errorf("determine the filesystem of %s", path)
+	}
+
+	return uint64(stat.Dev), nil
+}
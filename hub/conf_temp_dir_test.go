@@ -0,0 +1,97 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/testutils"
+	"github.com/greenplum-db/gpupgrade/testutils/testlog"
+)
+
+func TestConfTempDir(t *testing.T) {
+	dataDir := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, dataDir)
+
+	path := filepath.Join(dataDir, "postgresql.conf")
+
+	t.Run("defaults to the directory of the conf file", func(t *testing.T) {
+		dir := hub.ConfTempDir(path, "")
+		if dir != dataDir {
+			t.Errorf("got %q want %q", dir, dataDir)
+		}
+	})
+
+	t.Run("uses the configured directory on the same filesystem", func(t *testing.T) {
+		tempDir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, tempDir)
+
+		hub.SetConfTempDir(tempDir)
+		defer hub.ResetConfTempDir()
+
+		dir := hub.ConfTempDir(path, "")
+		if dir != tempDir {
+			t.Errorf("got %q want %q", dir, tempDir)
+		}
+	})
+
+	t.Run("prefers the directory sent by the hub", func(t *testing.T) {
+		tempDir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, tempDir)
+
+		hub.SetConfTempDir(filepath.Join(dataDir, "missing"))
+		defer hub.ResetConfTempDir()
+
+		dir := hub.ConfTempDir(path, tempDir)
+		if dir != tempDir {
+			t.Errorf("got %q want %q", dir, tempDir)
+		}
+	})
+
+	t.Run("is sent to the agents with the options", func(t *testing.T) {
+		hub.SetConfTempDir("/data/tmp")
+		defer hub.ResetConfTempDir()
+
+		opt := &idl.UpdateFileConfOptions{Path: path}
+		opts := hub.ConfPlan{{Hostname: "sdw1", Option: opt}}.Options()
+
+		if opts[0].GetTempDir() != "/data/tmp" {
+			t.Errorf("got temp dir %q want %q", opts[0].GetTempDir(), "/data/tmp")
+		}
+
+		if opt.GetTempDir() != "" {
+			t.Errorf("expected the planned option to be unmodified")
+		}
+	})
+
+	t.Run("falls back to the directory of the conf file with a warning", func(t *testing.T) {
+		cases := []struct {
+			name     string
+			tempDir  string
+			expected string
+		}{
+			{name: "on a different filesystem", tempDir: "/proc", expected: "/proc is on a different filesystem"},
+			{name: "that does not exist", tempDir: filepath.Join(dataDir, "missing"), expected: "no such file or directory"},
+		}
+
+		for _, c := range cases {
+			t.Run(c.name, func(t *testing.T) {
+				log := testlog.SetupTestLogger()
+
+				hub.SetConfTempDir(c.tempDir)
+				defer hub.ResetConfTempDir()
+
+				dir := hub.ConfTempDir(path, "")
+				if dir != dataDir {
+					t.Errorf("got %q want %q", dir, dataDir)
+				}
+
+				testlog.VerifyLogContains(t, log, c.expected)
+			})
+		}
+	})
+}
@@ -0,0 +1,187 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/blang/semver/v4"
+	"google.golang.org/grpc"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/testutils"
+)
+
+// ConfTestCluster is a cluster with a coordinator, standby, primaries and
+// mirrors whose data directories are temporary directories seeded with the
+// conf files of its version, for tests making real conf edits. The
+// intermediate and target clusters share the data directories and differ in
+// their ports. The caller removes Dir.
+type ConfTestCluster struct {
+	Dir          string
+	Version      semver.Version
+	Intermediate *greenplum.Cluster
+	Target       *greenplum.Cluster
+	AgentConns   []*idl.Connection
+}
+
+// MustCreateConfTestCluster creates the data directories of a ConfTestCluster
+// and seeds their conf files with the ports of the intermediate cluster.
+func MustCreateConfTestCluster(t *testing.T, version semver.Version) *ConfTestCluster {
+	t.Helper()
+
+	dir := testutils.GetTempDir(t, "")
+	dataDir := func(name string) string {
+		path := filepath.Join(dir, name)
+		testutils.MustCreateDir(t, path)
+		return path
+	}
+
+	coordinator, standby := dataDir("qddir"), dataDir("standby")
+	primary0, primary1 := dataDir("dbfast1"), dataDir("dbfast2")
+	mirror0, mirror1 := dataDir("dbfast_mirror1"), dataDir("dbfast_mirror2")
+
+	c := &ConfTestCluster{
+		Dir:     dir,
+		Version: version,
+		Intermediate: MustCreateCluster(t, greenplum.SegConfigs{
+			{DbID: 1, ContentID: -1, Hostname: "cdw", DataDir: coordinator, Port: 50432, Role: greenplum.PrimaryRole},
+			{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: primary0, Port: 50434, Role: greenplum.PrimaryRole},
+			{DbID: 3, ContentID: 1, Hostname: "sdw2", DataDir: primary1, Port: 50435, Role: greenplum.PrimaryRole},
+			{DbID: 4, ContentID: 0, Hostname: "sdw2", DataDir: mirror0, Port: 50436, Role: greenplum.MirrorRole},
+			{DbID: 5, ContentID: 1, Hostname: "sdw1", DataDir: mirror1, Port: 50437, Role: greenplum.MirrorRole},
+			{DbID: 6, ContentID: -1, Hostname: "smdw", DataDir: standby, Port: 50433, Role: greenplum.MirrorRole},
+		}),
+		Target: MustCreateCluster(t, greenplum.SegConfigs{
+			{DbID: 1, ContentID: -1, Hostname: "cdw", DataDir: coordinator, Port: 15432, Role: greenplum.PrimaryRole},
+			{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: primary0, Port: 25433, Role: greenplum.PrimaryRole},
+			{DbID: 3, ContentID: 1, Hostname: "sdw2", DataDir: primary1, Port: 25434, Role: greenplum.PrimaryRole},
+			{DbID: 4, ContentID: 0, Hostname: "sdw2", DataDir: mirror0, Port: 25435, Role: greenplum.MirrorRole},
+			{DbID: 5, ContentID: 1, Hostname: "sdw1", DataDir: mirror1, Port: 25436, Role: greenplum.MirrorRole},
+			{DbID: 6, ContentID: -1, Hostname: "smdw", DataDir: standby, Port: 16432, Role: greenplum.MirrorRole},
+		}),
+	}
+
+	for _, host := range []string{"sdw1", "sdw2", "smdw"} {
+		c.AgentConns = append(c.AgentConns, &idl.Connection{AgentClient: LocalConfAgent{}, Hostname: host})
+	}
+
+	for _, seg := range c.Intermediate.SelectSegments(func(*greenplum.SegConfig) bool { return true }) {
+		for file, contents := range c.SeededConfFiles(seg.DbID) {
+			testutils.MustWriteToFile(t, filepath.Join(seg.DataDir, file), contents)
+		}
+	}
+
+	return c
+}
+
+// SeededConfFiles returns the conf files of the segment with the dbid as they
+// are seeded, by file name. As a mirror is copied from its primary, its
+// postgresql.conf has the port of the intermediate primary.
+func (c *ConfTestCluster) SeededConfFiles(dbid int) map[string]string {
+	seg := mustSelectSegment(c.Intermediate, dbid)
+
+	port := seg.Port
+	if seg.IsMirror() && !seg.IsStandby() {
+		port = c.Intermediate.Primaries[seg.ContentID].Port
+	}
+
+	return confTestFiles(c.Version, port, c.upstream(c.Intermediate, seg))
+}
+
+// UpdatedConfFiles returns the conf files of the segment with the dbid as
+// they are once updated for the target cluster, by file name.
+func (c *ConfTestCluster) UpdatedConfFiles(dbid int) map[string]string {
+	seg := mustSelectSegment(c.Target, dbid)
+	return confTestFiles(c.Version, seg.Port, c.upstream(c.Target, seg))
+}
+
+// MustReadConfFile returns the contents of a conf file in the data directory
+// of the segment with the dbid.
+func (c *ConfTestCluster) MustReadConfFile(t *testing.T, dbid int, file string) string {
+	t.Helper()
+	return testutils.MustReadFile(t, filepath.Join(mustSelectSegment(c.Target, dbid).DataDir, file))
+}
+
+// upstream returns the primary a mirror or standby replicates from, or nil
+// for a primary.
+func (c *ConfTestCluster) upstream(cluster *greenplum.Cluster, seg greenplum.SegConfig) *greenplum.SegConfig {
+	if !seg.IsMirror() && !seg.IsStandby() {
+		return nil
+	}
+
+	primary := cluster.Primaries[seg.ContentID]
+	return &primary
+}
+
+func mustSelectSegment(cluster *greenplum.Cluster, dbid int) greenplum.SegConfig {
+	segs := cluster.SelectSegments(func(seg *greenplum.SegConfig) bool { return seg.DbID == dbid })
+	if len(segs) != 1 {
+		panic(fmt.Sprintf("no segment with dbid %d", dbid))
+	}
+
+	return segs[0]
+}
+
+// confTestFiles returns the contents of the conf files of a data directory
+// whose postgresql.conf has the port, by file name. The conninfo of a mirror
+// or standby to its upstream primary is in recovery.conf for 6 and
+// postgresql.auto.conf otherwise.
+func confTestFiles(version semver.Version, port int, upstream *greenplum.SegConfig) map[string]string {
+	files := map[string]string{
+		"postgresql.conf": fmt.Sprintf(`# PostgreSQL configuration file
+listen_addresses='*'		# what IP address(es) to listen on;
+port=%d				# sets the database listener port for
+max_connections=750
+shared_buffers=125MB
+`, port),
+	}
+
+	if upstream == nil {
+		return files
+	}
+
+	if version.Major == 6 {
+		files["recovery.conf"] = fmt.Sprintf(`standby_mode = 'on'
+primary_conninfo = 'user=gpadmin host=%s port=%d sslmode=prefer sslcompression=1 krbsrvname=postgres application_name=gp_walreceiver'
+primary_slot_name = 'internal_wal_replication_slot'
+`, upstream.Hostname, upstream.Port)
+		return files
+	}
+
+	files["postgresql.auto.conf"] = fmt.Sprintf(`# Do not edit this file manually!
+# It will be overwritten by the ALTER SYSTEM command.
+primary_conninfo = 'user=gpadmin passfile=''/home/gpadmin/.pgpass'' host=%s port=%d sslmode=disable application_name=gp_walreceiver'
+primary_slot_name = 'internal_wal_replication_slot'
+`, upstream.Hostname, upstream.Port)
+	return files
+}
+
+// LocalConfAgent is the client of an agent making the conf edits of its
+// requests on the local filesystem. It supports no other requests.
+type LocalConfAgent struct {
+	idl.AgentClient
+}
+
+func (LocalConfAgent) UpdateConfiguration(ctx context.Context, req *idl.UpdateConfigurationRequest, opts ...grpc.CallOption) (*idl.UpdateConfigurationReply, error) {
+	if req.GetDryRun() {
+		diffs, skipped, err := PreviewConfigurationFile(ctx, req.GetOptions())
+		return &idl.UpdateConfigurationReply{Diffs: diffs, Skipped: skipped}, err
+	}
+
+	reply, err := UpdateConfigurationFileReply(ctx, req.GetOptions())
+	if err != nil {
+		return &idl.UpdateConfigurationReply{}, err
+	}
+
+	if req.GetVerifyAfterWrite() {
+		reply.Values, err = ReadEditedGUCs(req.GetOptions())
+	}
+
+	return reply, err
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"bytes"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/greenplum-db/gpupgrade/idl"
+)
+
+// ensureTrailingNewline has rewritten conf files end with exactly one
+// newline. Postgres tolerates a missing final newline but some tools reading
+// the conf files do not. When disabled a rewritten file keeps the trailing
+// newlines of the original.
+var ensureTrailingNewline = true
+
+func SetEnsureTrailingNewline(ensure bool) {
+	ensureTrailingNewline = ensure
+}
+
+func ResetEnsureTrailingNewline() {
+	ensureTrailingNewline = true
+}
+
+// preserveTrailingNewline is whether edited files keep their trailing
+// newlines as they are, which they always do in core mode.
+func preserveTrailingNewline() bool {
+	return !ensureTrailingNewline || coreConfMode
+}
+
+// trailingNewlineOption returns the option sent to the agents, marked to
+// preserve the original trailing newlines when they are not ensured.
+func trailingNewlineOption(opt *idl.UpdateFileConfOptions) *idl.UpdateFileConfOptions {
+	if !preserveTrailingNewline() {
+		return opt
+	}
+
+	preserved := proto.Clone(opt).(*idl.UpdateFileConfOptions)
+	preserved.PreserveTrailingNewline = true
+	return preserved
+}
+
+// fixTrailingNewline returns the edited contents of a file ending with exactly
+// one newline, or with the same number of trailing newlines as its original
+// contents when any of its options preserve them. The trailing newlines kept
+// keep their line endings, and those added use the dominant line ending of
+// the original. An empty file is left empty.
+func fixTrailingNewline(original []byte, contents []byte, opts []*idl.UpdateFileConfOptions) []byte {
+	body, endings := trimTrailingLineEndings(contents)
+	if len(body) == 0 {
+		return contents
+	}
+
+	newlines := 1
+	for _, opt := range opts {
+		if opt.GetPreserveTrailingNewline() {
+			_, originalEndings := trimTrailingLineEndings(original)
+			newlines = len(originalEndings)
+			break
+		}
+	}
+
+	for len(endings) < newlines {
+		endings = append(endings, lineEnding(original))
+	}
+
+	return append(body[:len(body):len(body)], bytes.Join(endings[:newlines], nil)...)
+}
@@ -0,0 +1,112 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/testutils"
+)
+
+func TestTrailingNewline(t *testing.T) {
+	cases := []struct {
+		name      string
+		contents  string
+		ensured   string
+		preserved string
+	}{
+		{
+			name:      "no trailing newline",
+			contents:  "listen_addresses='*'\nport=5000",
+			ensured:   "listen_addresses='*'\nport=6000\n",
+			preserved: "listen_addresses='*'\nport=6000",
+		},
+		{
+			name:      "one trailing newline",
+			contents:  "listen_addresses='*'\nport=5000\n",
+			ensured:   "listen_addresses='*'\nport=6000\n",
+			preserved: "listen_addresses='*'\nport=6000\n",
+		},
+		{
+			name:      "multiple trailing newlines",
+			contents:  "listen_addresses='*'\nport=5000\n\n\n",
+			ensured:   "listen_addresses='*'\nport=6000\n",
+			preserved: "listen_addresses='*'\nport=6000\n\n\n",
+		},
+		{
+			name:      "only newlines",
+			contents:  "\n\n",
+			ensured:   "\n\n",
+			preserved: "\n\n",
+		},
+	}
+
+	update := func(t *testing.T, contents string) string {
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		path := filepath.Join(dir, "postgresql.conf")
+		testutils.MustWriteToFile(t, path, contents)
+
+		plan := hub.ConfPlan{{
+			Hostname: "sdw1",
+			Option:   &idl.UpdateFileConfOptions{Path: path, Pattern: `(^[ \t]*port[ \t]*=[ \t]*)5000([^0-9]|$)`, Replacement: `\16000\2`, AllowNoMatch: true},
+		}}
+
+		err := hub.UpdateConfigurationFile(context.Background(), plan.Options())
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		return testutils.MustReadFile(t, path)
+	}
+
+	for _, c := range cases {
+		t.Run("ends a file with "+c.name+" with exactly one newline by default", func(t *testing.T) {
+			actual := update(t, c.contents)
+			if actual != c.ensured {
+				t.Errorf("got %q want %q", actual, c.ensured)
+			}
+		})
+
+		t.Run("preserves a file with "+c.name+" when not ensured", func(t *testing.T) {
+			hub.SetEnsureTrailingNewline(false)
+			defer hub.ResetEnsureTrailingNewline()
+
+			actual := update(t, c.contents)
+			if actual != c.preserved {
+				t.Errorf("got %q want %q", actual, c.preserved)
+			}
+		})
+	}
+
+	t.Run("leaves a file without edits to make as it is", func(t *testing.T) {
+		contents := "listen_addresses='*'\nport=6000\n\n\n"
+
+		actual := update(t, contents)
+		if actual != contents {
+			t.Errorf("got %q want %q", actual, contents)
+		}
+	})
+
+	t.Run("does not modify the planned options", func(t *testing.T) {
+		hub.SetEnsureTrailingNewline(false)
+		defer hub.ResetEnsureTrailingNewline()
+
+		opt := &idl.UpdateFileConfOptions{Path: "/data/dbfast1/seg1/postgresql.conf"}
+		opts := hub.ConfPlan{{Hostname: "sdw1", Option: opt}}.Options()
+
+		if !opts[0].GetPreserveTrailingNewline() {
+			t.Errorf("expected the option to preserve the trailing newline")
+		}
+
+		if opt.GetPreserveTrailingNewline() {
+			t.Errorf("expected the planned option to be unmodified")
+		}
+	})
+}
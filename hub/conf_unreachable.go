@@ -0,0 +1,34 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"golang.org/x/xerrors"
+)
+
+// confUnreachablePolicy is how the conf update treats an agent host that
+// cannot be reached. Skipping lets a partial upgrade go on while hosts
+// intentionally down are reported, so that they can be updated later by
+// retrying with only those conf hosts. The coordinator is always required.
+var confUnreachablePolicy = FailOnUnreachable
+
+func SetConfUnreachablePolicy(policy UnreachablePolicy) {
+	confUnreachablePolicy = policy
+}
+
+func ResetConfUnreachablePolicy() {
+	confUnreachablePolicy = FailOnUnreachable
+}
+
+// ParseUnreachablePolicy parses the unreachable policy "fail" or "skip".
+func ParseUnreachablePolicy(policy string) (UnreachablePolicy, error) {
+	switch policy {
+	case "fail":
+		return FailOnUnreachable, nil
+	case "skip":
+		return SkipUnreachable, nil
+	default:
+		return FailOnUnreachable, xerrors.Errorf("unknown unreachable policy %q, expected %q or %q", policy, "fail", "skip")
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blang/semver/v4"
+	"github.com/golang/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/greenplum-db/gpupgrade/config"
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/idl/mock_idl"
+	"github.com/greenplum-db/gpupgrade/step"
+	"github.com/greenplum-db/gpupgrade/testutils"
+)
+
+func TestConfUnreachablePolicy(t *testing.T) {
+	stateDir := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, stateDir)
+
+	resetEnv := testutils.SetEnv(t, "GPUPGRADE_HOME", stateDir)
+	defer resetEnv()
+
+	hub.SetRPCRetryPolicy(hub.RetryPolicy{Attempts: 1, Sleep: func(context.Context, time.Duration) error { return nil }})
+	defer hub.ResetRPCRetryPolicy()
+
+	coordinatorDir := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, coordinatorDir)
+
+	path := filepath.Join(coordinatorDir, "postgresql.conf")
+
+	intermediate := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: coordinatorDir, Port: 50432, Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg.HqtFHX54y0o.1", Port: 50434, Role: greenplum.PrimaryRole},
+		{DbID: 3, ContentID: 1, Hostname: "sdw2", DataDir: "/data/dbfast2/seg.HqtFHX54y0o.2", Port: 50435, Role: greenplum.PrimaryRole},
+	})
+	target := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: coordinatorDir, Port: 15432, Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg1", Port: 25433, Role: greenplum.PrimaryRole},
+		{DbID: 3, ContentID: 1, Hostname: "sdw2", DataDir: "/data/dbfast2/seg2", Port: 25434, Role: greenplum.PrimaryRole},
+	})
+
+	agentConns := func(ctrl *gomock.Controller, sdw2Err error) []*idl.Connection {
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw1.EXPECT().UpdateConfiguration(gomock.Any(), gomock.Any()).Return(&idl.UpdateConfigurationReply{}, nil).AnyTimes()
+
+		sdw2 := mock_idl.NewMockAgentClient(ctrl)
+		sdw2.EXPECT().UpdateConfiguration(gomock.Any(), gomock.Any()).Return(nil, sdw2Err).AnyTimes()
+
+		return []*idl.Connection{
+			{AgentClient: sdw1, Hostname: "sdw1"},
+			{AgentClient: sdw2, Hostname: "sdw2"},
+		}
+	}
+
+	unreachable := status.Error(codes.Unavailable, "connection refused")
+
+	t.Run("fails on an unreachable host by default", func(t *testing.T) {
+		testutils.MustWriteToFile(t, path, "port=50432\n")
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		err := hub.UpdateConfFiles(context.Background(), agentConns(ctrl, unreachable), nil, step.DevNullStream, config.StateVersion, hub.ConfUpdateOptions{}, semver.MustParse("7.0.0"), intermediate, target)
+		if err == nil || !strings.Contains(err.Error(), "connection refused") {
+			t.Errorf("got error %v want it to contain %q", err, "connection refused")
+		}
+	})
+
+	t.Run("skips and reports unreachable hosts when allowed", func(t *testing.T) {
+		testutils.MustWriteToFile(t, path, "port=50432\n")
+
+		hub.SetConfUnreachablePolicy(hub.SkipUnreachable)
+		defer hub.ResetConfUnreachablePolicy()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		streams := &step.BufferedStreams{}
+		err := hub.UpdateConfFiles(context.Background(), agentConns(ctrl, unreachable), nil, streams, config.StateVersion, hub.ConfUpdateOptions{}, semver.MustParse("7.0.0"), intermediate, target)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		expected := "conf files were not updated on 1 unreachable hosts: sdw2."
+		if !strings.Contains(streams.StdoutBuf.String(), expected) {
+			t.Errorf("got stdout %q want it to contain %q", streams.StdoutBuf.String(), expected)
+		}
+
+		if strings.Contains(streams.StdoutBuf.String(), hub.AlreadyAtTargetText) {
+			t.Errorf("got stdout %q want it not to claim the cluster was already updated", streams.StdoutBuf.String())
+		}
+
+		contents := testutils.MustReadFile(t, path)
+		if contents != "port=15432\n" {
+			t.Errorf("got %q want %q", contents, "port=15432\n")
+		}
+	})
+
+	t.Run("still fails on errors returned by a reachable host", func(t *testing.T) {
+		testutils.MustWriteToFile(t, path, "port=50432\n")
+
+		hub.SetConfUnreachablePolicy(hub.SkipUnreachable)
+		defer hub.ResetConfUnreachablePolicy()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		err := hub.UpdateConfFiles(context.Background(), agentConns(ctrl, status.Error(codes.Internal, "permission denied")), nil, step.DevNullStream, config.StateVersion, hub.ConfUpdateOptions{}, semver.MustParse("7.0.0"), intermediate, target)
+		if err == nil || !strings.Contains(err.Error(), "permission denied") {
+			t.Errorf("got error %v want it to contain %q", err, "permission denied")
+		}
+	})
+}
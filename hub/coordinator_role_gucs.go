@@ -0,0 +1,98 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/blang/semver/v4"
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/idl"
+)
+
+const ReasonCoordinatorRoleGUC = "coordinator-role-guc"
+
+// coordinatorRoleGUC is a GUC that only applies to the coordinator and whose
+// name depends on the version. Since the value is carried over unchanged, the
+// GUC is only rewritten when a setting under the other name is found.
+type coordinatorRoleGUC struct {
+	// name is the GUC for versions before since.
+	name string
+	// renamed is the GUC starting with version since.
+	renamed string
+	since   uint64
+}
+
+var coordinatorRoleGUCs = []coordinatorRoleGUC{
+	{name: "gp_session_role", renamed: "gp_role", since: 7},
+}
+
+// validCoordinatorRoleGUC errors when the GUC is not recognized by the target
+// version, so that an illegal setting is never written to postgresql.conf.
+func validCoordinatorRoleGUC(version semver.Version, name string) error {
+	for _, guc := range coordinatorRoleGUCs {
+		if name == guc.name && version.Major < guc.since {
+			return nil
+		}
+
+		if name == guc.renamed && version.Major >= guc.since {
+			return nil
+		}
+	}
+
+	return xerrors.Errorf("%s is not a coordinator GUC of Greenplum %s", name, version)
+}
+
+// coordinatorRoleEdits renames the coordinator role GUCs of the target
+// postgresql.conf to the names used by the target version.
+func coordinatorRoleEdits(hostname string, version semver.Version, dataDir string) (ConfPlan, error) {
+	path := filepath.Join(dataDir, "postgresql.conf")
+
+	settings, err := readConfSettings(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, xerrors.Errorf("read %s: %w", path, err)
+	}
+
+	var edits ConfPlan
+	for _, guc := range coordinatorRoleGUCs {
+		old, name := guc.renamed, guc.name
+		if version.Major >= guc.since {
+			old, name = guc.name, guc.renamed
+		}
+
+		value, ok := settings[old]
+		if !ok {
+			continue
+		}
+
+		if err := validCoordinatorRoleGUC(version, name); err != nil {
+			return nil, err
+		}
+
+		edits = append(edits, ConfEdit{
+			Hostname: hostname,
+			OldValue: old + "=" + value,
+			NewValue: name + "=" + value,
+			Option: &idl.UpdateFileConfOptions{
+				Path:        path,
+				Pattern:     fmt.Sprintf(`(^[ \t]*)%s([ \t]*=|[ \t])`, regexp.QuoteMeta(old)),
+				Replacement: fmt.Sprintf(`\1%s\2`, name),
+				Reason:      ReasonCoordinatorRoleGUC,
+				Guc:         old,
+				DataDir:     dataDir,
+			},
+		})
+	}
+
+	return edits, nil
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/blang/semver/v4"
+
+	"github.com/greenplum-db/gpupgrade/config"
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/step"
+	"github.com/greenplum-db/gpupgrade/testutils"
+)
+
+func TestCoordinatorRoleGUCs(t *testing.T) {
+	stateDir := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, stateDir)
+
+	resetEnv := testutils.SetEnv(t, "GPUPGRADE_HOME", stateDir)
+	defer resetEnv()
+
+	coordinatorDir := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, coordinatorDir)
+
+	path := filepath.Join(coordinatorDir, "postgresql.conf")
+
+	testutils.MustCreateDir(t, filepath.Join(coordinatorDir, "gpperfmon", "conf"))
+	testutils.MustWriteToFile(t, filepath.Join(coordinatorDir, "gpperfmon", "conf", "gpperfmon.conf"), "log_location = /data/gpperfmon/logs\n")
+
+	intermediate := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: coordinatorDir, Port: 50432, Role: greenplum.PrimaryRole},
+	})
+	target := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: coordinatorDir, Port: 15432, Role: greenplum.PrimaryRole},
+	})
+
+	cases := []struct {
+		name     string
+		version  string
+		contents string
+		expected string
+	}{
+		{
+			name:     "renames gp_session_role for a 7X target",
+			version:  "7.0.0",
+			contents: "port=50432\ngp_session_role = utility # for maintenance\n",
+			expected: "port=15432\ngp_role = utility # for maintenance\n",
+		},
+		{
+			name:     "keeps gp_session_role for a 6X target",
+			version:  "6.25.0",
+			contents: "port=50432\ngp_session_role = utility\n",
+			expected: "port=15432\ngp_session_role = utility\n",
+		},
+		{
+			name:     "does not add gp_role when it is not set",
+			version:  "7.0.0",
+			contents: "port=50432\n",
+			expected: "port=15432\n",
+		},
+		{
+			name:     "does not rename a commented out setting",
+			version:  "7.0.0",
+			contents: "port=50432\n#gp_session_role = utility\n",
+			expected: "port=15432\n#gp_session_role = utility\n",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			testutils.MustWriteToFile(t, path, c.contents)
+
+			err := hub.UpdateConfFiles(context.Background(), nil, nil, step.DevNullStream, config.StateVersion, hub.ConfUpdateOptions{}, semver.MustParse(c.version), intermediate, target)
+			if err != nil {
+				t.Fatalf("unexpected error %+v", err)
+			}
+
+			contents := testutils.MustReadFile(t, path)
+			if contents != c.expected {
+				t.Errorf("got %q want %q", contents, c.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,221 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"path/filepath"
+	"regexp"
+	"strconv"
+
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
+	"github.com/greenplum-db/gpupgrade/utils/errorlist"
+)
+
+const ReasonCustomEdit = "custom-edit"
+
+// managedGUCs are the GUCs rewritten by gpupgrade itself. Custom edits may
+// not touch them unless explicitly allowed.
+var managedGUCs = []string{"port", "gp_dbid", "primary_conninfo", "log_location", "gp_session_role", "gp_role"}
+
+// CustomConfEdit is an operator provided pattern and replacement applied to
+// a conf file of every segment, including the coordinator and standby. It is
+// an escape hatch for non-standard GUCs that gpupgrade does not rewrite, and
+// the operator owns its correctness.
+type CustomConfEdit struct {
+	// File is the conf file relative to each data directory, such as
+	// "postgresql.conf".
+	File        string `json:"file"`
+	Pattern     string `json:"pattern"`
+	Replacement string `json:"replacement"`
+	// ExpectedMatches when positive is the number of lines the pattern must
+	// match in each file.
+	ExpectedMatches int `json:"expectedMatches,omitempty"`
+	// AllowManagedGUCs permits a pattern matching a GUC rewritten by gpupgrade.
+	AllowManagedGUCs bool `json:"allowManagedGUCs,omitempty"`
+	// AllowNoMatch permits the pattern to match no lines of a file, such as
+	// when rerunning an edit that was already made.
+	AllowNoMatch bool `json:"allowNoMatch,omitempty"`
+	// LiteralReplacement writes the replacement as is rather than expanding
+	// \1-\9 and &, such as for a value containing a path.
+	LiteralReplacement bool `json:"literalReplacement,omitempty"`
+}
+
+var backreferencePattern = regexp.MustCompile(`\\([0-9])`)
+
+// Validate checks that the file stays within the data directory, the pattern
+// compiles, the replacement only refers to groups of the pattern, and that
+// the pattern leaves the GUCs managed by gpupgrade alone unless allowed. Any
+// character including @ may appear in the pattern and replacement since
+// edits are made in process rather than through sed.
+func (e CustomConfEdit) Validate() error {
+	if e.File == "" || !filepath.IsLocal(e.File) {
+		return xerrors.Errorf("custom edit file %q must be a path within the data directory", e.File)
+	}
+
+	pattern, err := regexp.Compile(e.Pattern)
+	if err != nil {
+		return xerrors.Errorf("custom edit of %s has invalid pattern %q: %w", e.File, e.Pattern, err)
+	}
+
+	err = e.checkBackreferences(pattern)
+	if err != nil {
+		return err
+	}
+
+	if e.ExpectedMatches < 0 {
+		return xerrors.Errorf("custom edit of %s has negative expected matches %d", e.File, e.ExpectedMatches)
+	}
+
+	if e.AllowManagedGUCs {
+		return nil
+	}
+
+	for _, guc := range managedGUCs {
+		for _, line := range []string{guc + " = 1", guc + "=1", fmt.Sprintf("%s = 'host=sdw1 port=1'", guc)} {
+			if pattern.MatchString(line) {
+				return xerrors.Errorf("custom edit of %s has pattern %q matching %s which is managed by gpupgrade. Set allowManagedGUCs to edit it anyway.", e.File, e.Pattern, guc)
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkBackreferences errors when the replacement refers to a group the
+// pattern does not have. A literal replacement has no backreferences.
+func (e CustomConfEdit) checkBackreferences(pattern *regexp.Regexp) error {
+	if e.LiteralReplacement {
+		return nil
+	}
+
+	for _, match := range backreferencePattern.FindAllStringSubmatch(e.Replacement, -1) {
+		group, _ := strconv.Atoi(match[1])
+		if group > pattern.NumSubexp() {
+			return xerrors.Errorf("custom edit of %s has replacement %q referring to group %d but pattern %q has %d groups",
+				e.File, e.Replacement, group, e.Pattern, pattern.NumSubexp())
+		}
+	}
+
+	return nil
+}
+
+// replacement returns the replacement as sent to the editor, escaping a
+// literal one so that its backslashes and & are written as is.
+func (e CustomConfEdit) replacement() string {
+	if e.LiteralReplacement {
+		return quoteReplacement(e.Replacement)
+	}
+
+	return e.Replacement
+}
+
+// LoadCustomConfEdits reads and validates a JSON array of custom edits,
+// returning every invalid edit.
+func LoadCustomConfEdits(path string) ([]CustomConfEdit, error) {
+	contents, err := utils.System.ReadFile(path)
+	if err != nil {
+		return nil, xerrors.Errorf("read custom conf edits: %w", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(contents))
+	decoder.DisallowUnknownFields()
+
+	var edits []CustomConfEdit
+	if err := decoder.Decode(&edits); err != nil {
+		return nil, xerrors.Errorf("parse custom conf edits %s: %w", path, err)
+	}
+
+	for _, edit := range edits {
+		err = errorlist.Append(err, edit.Validate())
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return edits, nil
+}
+
+var customConfEdits []CustomConfEdit
+
+// SetCustomConfEdits applies edits after the built-in ones in UpdateConfFiles.
+func SetCustomConfEdits(edits []CustomConfEdit) {
+	customConfEdits = edits
+}
+
+func ResetCustomConfEdits() {
+	customConfEdits = nil
+}
+
+// customConfEditsOnHost returns the custom edits of the selected segments of
+// the target cluster on a host.
+func customConfEditsOnHost(hostname string, target *greenplum.Cluster, selector func(seg *greenplum.SegConfig) bool) ConfPlan {
+	if coreConfMode {
+		return nil
+	}
+
+	var edits ConfPlan
+
+	target.ForEachSegment(func(seg *greenplum.SegConfig) bool {
+		return seg.IsOnHost(hostname) && selector(seg) && confIncluded(seg)
+	}, func(seg *greenplum.SegConfig) bool {
+		for _, custom := range customConfEdits {
+			edits = append(edits, ConfEdit{Hostname: hostname, Option: &idl.UpdateFileConfOptions{
+				Path:            filepath.Join(seg.DataDir, custom.File),
+				Pattern:         custom.Pattern,
+				Replacement:     custom.replacement(),
+				Reason:          ReasonCustomEdit,
+				ExpectedMatches: int32(custom.ExpectedMatches),
+				AllowNoMatch:    custom.AllowNoMatch,
+				DataDir:         seg.DataDir,
+			}})
+		}
+		return true
+	})
+
+	return edits
+}
+
+// updateCustomConfFiles applies the custom edits to the coordinator in the
+// same way as its built-in edits, and through the agents on every other
+// segment.
+func updateCustomConfFiles(ctx context.Context, agentConns []*idl.Connection, changes *confChanges, hosts []string, target *greenplum.Cluster) error {
+	if len(customConfEdits) == 0 {
+		return nil
+	}
+
+	log.Printf("Warning: applying %d operator provided conf edits. gpupgrade does not verify their effect and the operator owns their correctness.", len(customConfEdits))
+
+	// as with the built-in edits the coordinator is only updated when the
+	// update is not limited to other hosts
+	if confHostIncluded(hosts, target.CoordinatorHostname()) {
+		coordinator := customConfEditsOnHost(target.CoordinatorHostname(), target, func(seg *greenplum.SegConfig) bool {
+			return seg.IsCoordinator()
+		})
+
+		// the coordinator is never skipped as the cluster cannot start without it
+		conn := coordinatorConfConn(agentConns, target.CoordinatorHostname())
+		err := updateConfOnHostsPolicy(ctx, []*idl.Connection{conn}, changes, FailOnUnreachable, func(string) (ConfPlan, error) {
+			return coordinator, nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return updateConfOnHosts(ctx, agentConns, changes, func(hostname string) (ConfPlan, error) {
+		return customConfEditsOnHost(hostname, target, func(seg *greenplum.SegConfig) bool {
+			return !seg.IsCoordinator()
+		}), nil
+	})
+}
@@ -0,0 +1,318 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/blang/semver/v4"
+	"github.com/golang/mock/gomock"
+	"google.golang.org/grpc"
+
+	"github.com/greenplum-db/gpupgrade/config"
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/idl/mock_idl"
+	"github.com/greenplum-db/gpupgrade/step"
+	"github.com/greenplum-db/gpupgrade/testutils"
+)
+
+func TestLoadCustomConfEdits(t *testing.T) {
+	dir := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, dir)
+
+	path := filepath.Join(dir, "custom_edits.json")
+
+	t.Run("loads valid edits", func(t *testing.T) {
+		testutils.MustWriteToFile(t, path, `[
+  {"file": "postgresql.conf", "pattern": "^(my_extension.path = ).*$", "replacement": "\\1'/usr/local/target/lib'", "expectedMatches": 1},
+  {"file": "postgresql.conf", "pattern": "^port = 15432$", "replacement": "port = 15433", "allowManagedGUCs": true}
+]`)
+
+		edits, err := hub.LoadCustomConfEdits(path)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		expected := []hub.CustomConfEdit{
+			{File: "postgresql.conf", Pattern: "^(my_extension.path = ).*$", Replacement: `\1'/usr/local/target/lib'`, ExpectedMatches: 1},
+			{File: "postgresql.conf", Pattern: "^port = 15432$", Replacement: "port = 15433", AllowManagedGUCs: true},
+		}
+
+		if !reflect.DeepEqual(edits, expected) {
+			t.Errorf("got %+v want %+v", edits, expected)
+		}
+	})
+
+	t.Run("errors on unknown fields", func(t *testing.T) {
+		testutils.MustWriteToFile(t, path, `[{"file": "postgresql.conf", "pattern": "a", "replacement": "b", "matches": 1}]`)
+
+		_, err := hub.LoadCustomConfEdits(path)
+		if err == nil || !strings.Contains(err.Error(), "unknown field") {
+			t.Errorf("got error %v want an unknown field error", err)
+		}
+	})
+
+	t.Run("validates each edit", func(t *testing.T) {
+		cases := []struct {
+			name     string
+			edit     hub.CustomConfEdit
+			expected string
+		}{
+			{
+				name:     "file outside of the data directory",
+				edit:     hub.CustomConfEdit{File: "../postgresql.conf", Pattern: "^a$", Replacement: "b"},
+				expected: "must be a path within the data directory",
+			},
+			{
+				name:     "absolute file",
+				edit:     hub.CustomConfEdit{File: "/etc/passwd", Pattern: "^a$", Replacement: "b"},
+				expected: "must be a path within the data directory",
+			},
+			{
+				name:     "pattern that does not compile",
+				edit:     hub.CustomConfEdit{File: "postgresql.conf", Pattern: "^(a$", Replacement: "b"},
+				expected: "invalid pattern",
+			},
+			{
+				name:     "replacement referring to a missing group",
+				edit:     hub.CustomConfEdit{File: "postgresql.conf", Pattern: "^(a)$", Replacement: `\2`},
+				expected: "referring to group 2",
+			},
+			{
+				name:     "negative expected matches",
+				edit:     hub.CustomConfEdit{File: "postgresql.conf", Pattern: "^a$", Replacement: "b", ExpectedMatches: -1},
+				expected: "negative expected matches",
+			},
+			{
+				name:     "pattern matching a managed GUC",
+				edit:     hub.CustomConfEdit{File: "postgresql.conf", Pattern: "^port.*", Replacement: "b"},
+				expected: "matching port which is managed by gpupgrade",
+			},
+			{
+				name:     "pattern matching every line",
+				edit:     hub.CustomConfEdit{File: "postgresql.conf", Pattern: ".*", Replacement: "b"},
+				expected: "managed by gpupgrade",
+			},
+		}
+
+		for _, c := range cases {
+			t.Run(c.name, func(t *testing.T) {
+				err := c.edit.Validate()
+				if err == nil || !strings.Contains(err.Error(), c.expected) {
+					t.Errorf("got error %v want it to contain %q", err, c.expected)
+				}
+			})
+		}
+	})
+
+	t.Run("accepts special characters", func(t *testing.T) {
+		edits := []hub.CustomConfEdit{
+			{File: "postgresql.conf", Pattern: "^(my_extension.user = ).*@.*$", Replacement: `\1'gpadmin@sdw1'`},
+			{File: "postgresql.conf", Pattern: "^my_extension.path = .*$", Replacement: `my_extension.path = 'C:\\data\9&more'`, LiteralReplacement: true},
+		}
+
+		for _, edit := range edits {
+			if err := edit.Validate(); err != nil {
+				t.Errorf("unexpected error %+v", err)
+			}
+		}
+	})
+}
+
+func TestUpdateConfFilesWithCustomEdits(t *testing.T) {
+	stateDir := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, stateDir)
+
+	resetEnv := testutils.SetEnv(t, "GPUPGRADE_HOME", stateDir)
+	defer resetEnv()
+
+	coordinatorDir := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, coordinatorDir)
+
+	path := filepath.Join(coordinatorDir, "postgresql.conf")
+
+	intermediate := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: coordinatorDir, Port: 50432, Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg.HqtFHX54y0o.1", Port: 50434, Role: greenplum.PrimaryRole},
+	})
+	target := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: coordinatorDir, Port: 15432, Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg1", Port: 25433, Role: greenplum.PrimaryRole},
+	})
+
+	custom := hub.CustomConfEdit{File: "postgresql.conf", Pattern: "^(my_extension.path = ).*$", Replacement: `\1'/usr/local/target/lib'`, ExpectedMatches: 1}
+
+	t.Run("applies custom edits to the coordinator and the segments", func(t *testing.T) {
+		testutils.MustWriteToFile(t, path, "port=50432\nmy_extension.path = '/usr/local/source/lib'\n")
+
+		hub.SetCustomConfEdits([]hub.CustomConfEdit{custom})
+		defer hub.ResetCustomConfEdits()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var customOpts []*idl.UpdateFileConfOptions
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw1.EXPECT().UpdateConfiguration(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, req *idl.UpdateConfigurationRequest, _ ...grpc.CallOption) (*idl.UpdateConfigurationReply, error) {
+				for _, opt := range req.GetOptions() {
+					if opt.GetReason() == hub.ReasonCustomEdit {
+						customOpts = append(customOpts, opt)
+					}
+				}
+				return &idl.UpdateConfigurationReply{}, nil
+			}).Times(2)
+
+		agentConns := []*idl.Connection{{AgentClient: sdw1, Hostname: "sdw1"}}
+
+		err := hub.UpdateConfFiles(context.Background(), agentConns, nil, step.DevNullStream, config.StateVersion, hub.ConfUpdateOptions{}, semver.MustParse("7.0.0"), intermediate, target)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		expected := "port=15432\nmy_extension.path = '/usr/local/target/lib'\n"
+		contents := testutils.MustReadFile(t, path)
+		if contents != expected {
+			t.Errorf("got %q want %q", contents, expected)
+		}
+
+		expectedOpts := []*idl.UpdateFileConfOptions{{
+			Path:            "/data/dbfast1/seg1/postgresql.conf",
+			Pattern:         custom.Pattern,
+			Replacement:     custom.Replacement,
+			Reason:          hub.ReasonCustomEdit,
+			ExpectedMatches: 1,
+			DataDir:         "/data/dbfast1/seg1",
+		}}
+		if !reflect.DeepEqual(customOpts, expectedOpts) {
+			t.Errorf("got options %v want %v", customOpts, expectedOpts)
+		}
+	})
+
+	t.Run("sends the coordinator edits to its agent when it is connected", func(t *testing.T) {
+		contents := "port=50432\nmy_extension.path = '/usr/local/source/lib'\n"
+		testutils.MustWriteToFile(t, path, contents)
+
+		hub.SetCustomConfEdits([]hub.CustomConfEdit{custom})
+		defer hub.ResetCustomConfEdits()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var customPaths []string
+		coordinator := mock_idl.NewMockAgentClient(ctrl)
+		coordinator.EXPECT().UpdateConfiguration(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, req *idl.UpdateConfigurationRequest, _ ...grpc.CallOption) (*idl.UpdateConfigurationReply, error) {
+				for _, opt := range req.GetOptions() {
+					if opt.GetReason() == hub.ReasonCustomEdit {
+						customPaths = append(customPaths, opt.GetPath())
+					}
+				}
+				return &idl.UpdateConfigurationReply{}, nil
+			}).Times(2)
+
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw1.EXPECT().UpdateConfiguration(gomock.Any(), gomock.Any()).Return(&idl.UpdateConfigurationReply{}, nil).Times(2)
+
+		agentConns := []*idl.Connection{
+			{AgentClient: coordinator, Hostname: "coordinator"},
+			{AgentClient: sdw1, Hostname: "sdw1"},
+		}
+
+		err := hub.UpdateConfFiles(context.Background(), agentConns, nil, step.DevNullStream, config.StateVersion, hub.ConfUpdateOptions{}, semver.MustParse("7.0.0"), intermediate, target)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		if !reflect.DeepEqual(customPaths, []string{path}) {
+			t.Errorf("got custom edits of %v want %v", customPaths, []string{path})
+		}
+
+		actual := testutils.MustReadFile(t, path)
+		if actual != contents {
+			t.Errorf("got %q want the coordinator conf left to its agent", actual)
+		}
+	})
+
+	t.Run("leaves the coordinator untouched when the update is limited to other hosts", func(t *testing.T) {
+		contents := "port=50432\nmy_extension.path = '/usr/local/source/lib'\n"
+		testutils.MustWriteToFile(t, path, contents)
+
+		hub.SetCustomConfEdits([]hub.CustomConfEdit{custom})
+		defer hub.ResetCustomConfEdits()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw1.EXPECT().UpdateConfiguration(gomock.Any(), gomock.Any()).Return(&idl.UpdateConfigurationReply{}, nil).Times(2)
+
+		agentConns := []*idl.Connection{{AgentClient: sdw1, Hostname: "sdw1"}}
+
+		opts := hub.ConfUpdateOptions{Hosts: []string{"sdw1"}}
+		err := hub.UpdateConfFiles(context.Background(), agentConns, nil, step.DevNullStream, config.StateVersion, opts, semver.MustParse("7.0.0"), intermediate, target)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		actual := testutils.MustReadFile(t, path)
+		if actual != contents {
+			t.Errorf("got %q want the coordinator conf unchanged", actual)
+		}
+	})
+
+	t.Run("writes a literal replacement as is", func(t *testing.T) {
+		testutils.MustWriteToFile(t, path, "port=50432\nmy_extension.path = '/usr/local/source/lib'\n")
+
+		literal := hub.CustomConfEdit{File: "postgresql.conf", Pattern: "^my_extension.path = .*$", Replacement: `my_extension.path = '/data/a@b&c\1\d'`, LiteralReplacement: true}
+		hub.SetCustomConfEdits([]hub.CustomConfEdit{literal})
+		defer hub.ResetCustomConfEdits()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw1.EXPECT().UpdateConfiguration(gomock.Any(), gomock.Any()).Return(&idl.UpdateConfigurationReply{}, nil).AnyTimes()
+
+		agentConns := []*idl.Connection{{AgentClient: sdw1, Hostname: "sdw1"}}
+
+		err := hub.UpdateConfFiles(context.Background(), agentConns, nil, step.DevNullStream, config.StateVersion, hub.ConfUpdateOptions{}, semver.MustParse("7.0.0"), intermediate, target)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		expected := "port=15432\nmy_extension.path = '/data/a@b&c\\1\\d'\n"
+		contents := testutils.MustReadFile(t, path)
+		if contents != expected {
+			t.Errorf("got %q want %q", contents, expected)
+		}
+	})
+
+	t.Run("errors when the pattern does not match the expected number of lines", func(t *testing.T) {
+		testutils.MustWriteToFile(t, path, "port=50432\n")
+
+		hub.SetCustomConfEdits([]hub.CustomConfEdit{custom})
+		defer hub.ResetCustomConfEdits()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw1.EXPECT().UpdateConfiguration(gomock.Any(), gomock.Any()).Return(&idl.UpdateConfigurationReply{}, nil).AnyTimes()
+
+		agentConns := []*idl.Connection{{AgentClient: sdw1, Hostname: "sdw1"}}
+
+		err := hub.UpdateConfFiles(context.Background(), agentConns, nil, step.DevNullStream, config.StateVersion, hub.ConfUpdateOptions{}, semver.MustParse("7.0.0"), intermediate, target)
+		expected := "matched 0 lines but expected 1"
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("got error %v want it to contain %q", err, expected)
+		}
+	})
+}
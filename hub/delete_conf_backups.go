@@ -0,0 +1,155 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/blang/semver/v4"
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/step"
+	"github.com/greenplum-db/gpupgrade/utils"
+	"github.com/greenplum-db/gpupgrade/utils/errorlist"
+)
+
+var ErrConfBackupsNeeded = errors.New("the conf file backups are kept until finalize updates the target conf files so that revert can restore them")
+
+// DeleteConfBackups deletes the backups written next to every conf file the
+// conf update touches once finalize has updated the target conf files, after
+// which the upgrade can no longer be reverted. The backups of the coordinator
+// are deleted locally and those of every other host through its agent. A
+// file without backups is skipped, and the hosts failing to delete their
+// backups are returned together.
+func DeleteConfBackups(agentConns []*idl.Connection, version semver.Version, intermediate *greenplum.Cluster, target *greenplum.Cluster) error {
+	committed, err := step.HasCompleted(idl.Step_finalize, idl.Substep_update_target_conf_files)
+	if err != nil {
+		return err
+	}
+
+	if !committed {
+		return ErrConfBackupsNeeded
+	}
+
+	coordinator, err := coordinatorConfPaths(version, intermediate, target)
+	if err != nil {
+		return err
+	}
+
+	deleted, coordinatorErr := RemoveConfBackups(coordinator)
+	if coordinatorErr != nil {
+		coordinatorErr = xerrors.Errorf("delete conf backups on host %s: %w", target.CoordinatorHostname(), coordinatorErr)
+	}
+	logDeletedConfBackups(target.CoordinatorHostname(), deleted)
+
+	requests, err := AgentConfRequests(version, intermediate, target)
+	if err != nil {
+		return err
+	}
+
+	request := func(ctx context.Context, conn *idl.Connection) error {
+		opts := renamedInternalAutoConfOptions(conn.Hostname, intermediate, target)
+		for _, req := range requests[conn.Hostname] {
+			opts = append(opts, req.GetOptions()...)
+		}
+
+		paths := confPaths(opts)
+		if len(paths) == 0 {
+			return nil
+		}
+
+		reply, err := conn.AgentClient.DeleteConfBackups(ctx, &idl.DeleteConfBackupsRequest{Paths: paths})
+		if err != nil {
+			return xerrors.Errorf("delete conf backups on host %s: %w", conn.Hostname, err)
+		}
+
+		logDeletedConfBackups(conn.Hostname, reply.GetDeletedPaths())
+		return nil
+	}
+
+	return errorlist.Append(coordinatorErr, ExecuteRPCContext(context.Background(), agentConns, request))
+}
+
+// renamedInternalAutoConfOptions returns the internal.auto.conf edits of the
+// segments on the host with the data directories of the target, as finalize
+// renames the intermediate data directories before the backups are deleted.
+func renamedInternalAutoConfOptions(hostname string, intermediate *greenplum.Cluster, target *greenplum.Cluster) []*idl.UpdateFileConfOptions {
+	rename := func(opts []*idl.UpdateFileConfOptions, segments greenplum.ContentToSegConfig, renamedSegments greenplum.ContentToSegConfig) {
+		for _, opt := range opts {
+			for contentID, seg := range segments {
+				renamed, ok := renamedSegments[contentID]
+				if ok && opt.GetPath() == filepath.Join(seg.DataDir, "internal.auto.conf") {
+					opt.Path = filepath.Join(renamed.DataDir, "internal.auto.conf")
+				}
+			}
+		}
+	}
+
+	mirrors := internalAutoConfOptions(hostname, intermediate)
+	rename(mirrors, intermediate.Mirrors, target.Mirrors)
+
+	primaries := primaryInternalAutoConfOptions(hostname, intermediate, target)
+	rename(primaries, intermediate.Primaries, target.Primaries)
+
+	return append(mirrors, primaries...)
+}
+
+func logDeletedConfBackups(hostname string, paths []string) {
+	for _, path := range paths {
+		log.Printf("deleted conf backup %s on host %s", path, hostname)
+	}
+}
+
+// RemoveConfBackups deletes every backup of each conf file, the first backup
+// as well as the versioned backups taken after it, and returns the paths of
+// the backups deleted. Missing backups are skipped so that deleting again is
+// a no-op. Every file is attempted and the failures are returned together.
+func RemoveConfBackups(paths []string) ([]string, error) {
+	var deleted []string
+	var err error
+
+	for _, path := range paths {
+		backups, rErr := removeConfBackups(path)
+		deleted = append(deleted, backups...)
+		if rErr != nil {
+			err = errorlist.Append(err, xerrors.Errorf("delete backups of %s: %w", path, rErr))
+		}
+	}
+
+	return deleted, err
+}
+
+func removeConfBackups(path string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var deleted []string
+	for _, entry := range entries {
+		match := confBackupPattern.FindStringSubmatch(entry.Name())
+		if match == nil || match[1] != filepath.Base(path) {
+			continue
+		}
+
+		backup := filepath.Join(filepath.Dir(path), entry.Name())
+		if err := utils.System.Remove(backup); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return deleted, err
+		}
+
+		deleted = append(deleted, backup)
+	}
+
+	return deleted, nil
+}
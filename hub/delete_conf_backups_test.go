@@ -0,0 +1,162 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/blang/semver/v4"
+	"github.com/golang/mock/gomock"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/idl/mock_idl"
+	"github.com/greenplum-db/gpupgrade/step"
+	"github.com/greenplum-db/gpupgrade/testutils"
+)
+
+func TestRemoveConfBackups(t *testing.T) {
+	t.Run("deletes every backup of each file and skips those without one", func(t *testing.T) {
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		postgresqlConf := filepath.Join(dir, "postgresql.conf")
+		testutils.MustWriteToFile(t, postgresqlConf, "port=6000\n")
+		testutils.MustWriteToFile(t, postgresqlConf+hub.BackupSuffix, "port=5000\n")
+		testutils.MustWriteToFile(t, postgresqlConf+hub.BackupSuffix+".1", "port=5500\n")
+
+		// the backups of another file with the same prefix are kept
+		autoConf := filepath.Join(dir, "postgresql.conf.auto")
+		testutils.MustWriteToFile(t, autoConf+hub.BackupSuffix, "port=5000\n")
+
+		recoveryConf := filepath.Join(dir, "recovery.conf")
+		testutils.MustWriteToFile(t, recoveryConf, "primary_conninfo = 'port=6001'\n")
+
+		missingDir := filepath.Join(dir, "missing", "postgresql.conf")
+
+		deleted, err := hub.RemoveConfBackups([]string{postgresqlConf, recoveryConf, missingDir})
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		expected := []string{postgresqlConf + hub.BackupSuffix, postgresqlConf + hub.BackupSuffix + ".1"}
+		if !reflect.DeepEqual(deleted, expected) {
+			t.Errorf("got deleted %v want %v", deleted, expected)
+		}
+
+		for _, backup := range expected {
+			testutils.PathMustNotExist(t, backup)
+		}
+
+		if _, err := os.Stat(autoConf + hub.BackupSuffix); err != nil {
+			t.Errorf("expected the backup of another file to be kept: %v", err)
+		}
+
+		contents := testutils.MustReadFile(t, postgresqlConf)
+		if contents != "port=6000\n" {
+			t.Errorf("got %q want the conf file unchanged", contents)
+		}
+
+		deleted, err = hub.RemoveConfBackups([]string{postgresqlConf})
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		if len(deleted) != 0 {
+			t.Errorf("got deleted %v want none when deleting again", deleted)
+		}
+	})
+}
+
+func TestDeleteConfBackups(t *testing.T) {
+	stateDir := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, stateDir)
+
+	resetEnv := testutils.SetEnv(t, "GPUPGRADE_HOME", stateDir)
+	defer resetEnv()
+
+	coordinatorDir := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, coordinatorDir)
+
+	path := filepath.Join(coordinatorDir, "postgresql.conf")
+	testutils.MustWriteToFile(t, path, "port=15432\n")
+	testutils.MustWriteToFile(t, path+hub.BackupSuffix, "port=50432\n")
+
+	intermediate := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: coordinatorDir, Port: 50432, Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg.HqtFHX54y0o.1", Port: 50434, Role: greenplum.PrimaryRole},
+		{DbID: 3, ContentID: 1, Hostname: "sdw2", DataDir: "/data/dbfast2/seg.HqtFHX54y0o.2", Port: 50435, Role: greenplum.PrimaryRole},
+		{DbID: 4, ContentID: 0, Hostname: "sdw2", DataDir: "/data/dbfast_mirror1/seg.HqtFHX54y0o.1", Port: 50436, Role: greenplum.MirrorRole},
+	})
+	target := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: coordinatorDir, Port: 15432, Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg1", Port: 25433, Role: greenplum.PrimaryRole},
+		{DbID: 3, ContentID: 1, Hostname: "sdw2", DataDir: "/data/dbfast2/seg2", Port: 25434, Role: greenplum.PrimaryRole},
+		{DbID: 4, ContentID: 0, Hostname: "sdw2", DataDir: "/data/dbfast_mirror1/seg1", Port: 25435, Role: greenplum.MirrorRole},
+	})
+
+	t.Run("keeps the backups until finalize updates the target conf files", func(t *testing.T) {
+		err := hub.DeleteConfBackups(nil, semver.MustParse("7.0.0"), intermediate, target)
+		if !errors.Is(err, hub.ErrConfBackupsNeeded) {
+			t.Errorf("got error %#v want %#v", err, hub.ErrConfBackupsNeeded)
+		}
+
+		if _, err := os.Stat(path + hub.BackupSuffix); err != nil {
+			t.Errorf("expected the backup to be kept: %v", err)
+		}
+	})
+
+	t.Run("deletes the backups on every host and returns the hosts that failed", func(t *testing.T) {
+		store, err := step.NewSubstepFileStore()
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		err = store.Write(idl.Step_finalize, idl.Substep_update_target_conf_files, idl.Status_complete)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw1.EXPECT().DeleteConfBackups(gomock.Any(), &idl.DeleteConfBackupsRequest{
+			Paths: []string{"/data/dbfast1/seg1/postgresql.conf"},
+		}).Return(nil, errors.New("permission denied")).Times(1)
+
+		sdw2 := mock_idl.NewMockAgentClient(ctrl)
+		sdw2.EXPECT().DeleteConfBackups(gomock.Any(), &idl.DeleteConfBackupsRequest{
+			Paths: []string{
+				"/data/dbfast2/seg2/postgresql.conf",
+				"/data/dbfast_mirror1/seg1/internal.auto.conf",
+				"/data/dbfast_mirror1/seg1/postgresql.auto.conf",
+				"/data/dbfast_mirror1/seg1/postgresql.conf",
+			},
+		}).Return(&idl.DeleteConfBackupsReply{}, nil).Times(1)
+
+		agentConns := []*idl.Connection{
+			{AgentClient: sdw1, Hostname: "sdw1"},
+			{AgentClient: sdw2, Hostname: "sdw2"},
+		}
+
+		err = hub.DeleteConfBackups(agentConns, semver.MustParse("7.0.0"), intermediate, target)
+		expected := "delete conf backups on host sdw1: permission denied"
+		if err == nil || err.Error() != expected {
+			t.Errorf("got error %v want %q", err, expected)
+		}
+
+		testutils.PathMustNotExist(t, path+hub.BackupSuffix)
+
+		contents := testutils.MustReadFile(t, path)
+		if contents != "port=15432\n" {
+			t.Errorf("got %q want the coordinator conf unchanged", contents)
+		}
+	})
+}
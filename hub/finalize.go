@@ -5,8 +5,13 @@ package hub
 
 import (
 	"context"
+	"fmt"
+	"path/filepath"
 	"time"
 
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
 	"github.com/greenplum-db/gpupgrade/idl"
 	"github.com/greenplum-db/gpupgrade/step"
 	"github.com/greenplum-db/gpupgrade/upgrade"
@@ -20,7 +25,7 @@ func (s *Server) Finalize(req *idl.FinalizeRequest, stream idl.CliToHub_Finalize
 	}
 
 	st.AlwaysRun(idl.Substep_ensure_gpupgrade_agents_are_running, func(_ step.OutStreams) error {
-		_, err := RestartAgents(context.Background(), nil, AgentHosts(s.Source), s.AgentPort, utils.GetStateDir())
+		_, err := RestartAgents(context.Background(), agentContextDialer, s.JumpHost, AgentHosts(s.Source), s.AgentPort, utils.GetStateDir())
 		if err != nil {
 			return err
 		}
@@ -38,7 +43,7 @@ func (s *Server) Finalize(req *idl.FinalizeRequest, stream idl.CliToHub_Finalize
 	})
 
 	st.RunConditionally(idl.Substep_upgrade_mirrors, s.Source.HasMirrors() && s.Mode == idl.Mode_link, func(streams step.OutStreams) error {
-		return UpgradeMirrorsUsingRsync(s.agentConns, s.Source, s.Intermediate, s.UseHbaHostnames)
+		return UpgradeMirrorsUsingRsync(s.agentConns, s.Source, s.Intermediate, s.Target, s.UseHbaHostnames)
 	})
 
 	st.RunConditionally(idl.Substep_upgrade_mirrors, s.Source.HasMirrors() && s.Mode != idl.Mode_link, func(streams step.OutStreams) error {
@@ -73,12 +78,65 @@ func (s *Server) Finalize(req *idl.FinalizeRequest, stream idl.CliToHub_Finalize
 		return RenameDataDirectories(s.agentConns, s.Source, s.Intermediate)
 	})
 
+	// The data directories are only scanned for late mirrors on request, and
+	// at most once so that the backups deleted are those of the conf files
+	// that were updated.
+	var reconciledTarget *greenplum.Cluster
+	confTarget := func() (*greenplum.Cluster, error) {
+		if !req.GetReconcileLateMirrors() {
+			return s.Target, nil
+		}
+
+		if reconciledTarget == nil {
+			target, err := ReconcileLateMirrors(s.agentConns, s.Target.Version, s.Intermediate, s.Target)
+			if err != nil {
+				return nil, err
+			}
+			reconciledTarget = target
+		}
+
+		return reconciledTarget, nil
+	}
+
 	st.Run(idl.Substep_update_target_conf_files, func(streams step.OutStreams) error {
-		return UpdateConfFiles(s.agentConns, streams,
-			s.Target.Version,
+		target, err := confTarget()
+		if err != nil {
+			return err
+		}
+
+		err = UpdateConfFiles(stream.Context(), s.agentConns, st.Sender(), streams,
+			s.StateVersion,
+			ConfUpdateOptions{
+				ResumeToken:  req.GetConfResumeToken(),
+				Hosts:        req.GetConfHosts(),
+				DebugLogging: req.GetVerbose(),
+				DryRun:       req.GetConfDryRun(),
+				DumpRequests: req.GetConfDumpRequests(),
+			},
+			target.Version,
 			s.Intermediate,
-			s.Target,
+			target,
 		)
+		if err != nil {
+			return err
+		}
+
+		// the substep fails so that the next finalize updates the conf files
+		if req.GetConfDumpRequests() {
+			return utils.NewNextActionErr(xerrors.New("conf requests dumped: the target conf files were not updated"),
+				fmt.Sprintf("Review the conf update requests in %s and rerun gpupgrade finalize without --conf-dump-requests to update them.", filepath.Join(utils.GetStateDir(), ConfRequestsDir)))
+		}
+
+		if req.GetConfDryRun() {
+			return utils.NewNextActionErr(xerrors.New("conf dry run: the target conf files were not updated"),
+				"Review the conf file diffs and rerun gpupgrade finalize without --conf-dry-run to update them.")
+		}
+
+		if err := CheckPostConfInvariants(s.agentConns, target.Version, target); err != nil {
+			return err
+		}
+
+		return CheckIntermediatePathReferences(s.agentConns, streams, target.Version, s.Intermediate, target)
 	})
 
 	st.AlwaysRun(idl.Substep_start_target_cluster, func(streams step.OutStreams) error {
@@ -104,6 +162,15 @@ func (s *Server) Finalize(req *idl.FinalizeRequest, stream idl.CliToHub_Finalize
 		return DeleteBackupDirectories(streams, s.agentConns, s.BackupDirs)
 	})
 
+	st.Run(idl.Substep_delete_conf_backups, func(_ step.OutStreams) error {
+		target, err := confTarget()
+		if err != nil {
+			return err
+		}
+
+		return DeleteConfBackups(s.agentConns, target.Version, s.Intermediate, target)
+	})
+
 	st.AlwaysRun(idl.Substep_delete_segment_statedirs, func(_ step.OutStreams) error {
 		return DeleteStateDirectories(s.agentConns, s.Source.CoordinatorHostname())
 	})
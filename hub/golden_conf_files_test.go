@@ -0,0 +1,130 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"flag"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/blang/semver/v4"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/testutils"
+)
+
+// updateGolden regenerates the expected conf files of the golden tests from
+// the current behavior. Review the resulting diff before committing it:
+//
+//	go test ./hub -run TestConfFilesGolden -update
+var updateGolden = flag.Bool("update", false, "regenerate the golden conf files")
+
+// TestConfFilesGolden simulates the conf file updates against each fixture in
+// testdata/conf_golden/<case>/fixture and compares every resulting file, byte
+// for byte, against testdata/conf_golden/<case>/expected.
+func TestConfFilesGolden(t *testing.T) {
+	intermediate := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: "/data/qddir/seg.HqtFHX54y0o.-1", Port: 50432, Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: -1, Hostname: "standby", DataDir: "/data/standby.HqtFHX54y0o", Port: 50433, Role: greenplum.MirrorRole},
+		{DbID: 3, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg.HqtFHX54y0o.1", Port: 50434, Role: greenplum.PrimaryRole},
+		{DbID: 4, ContentID: 0, Hostname: "sdw2", DataDir: "/data/dbfast_mirror1/seg.HqtFHX54y0o.1", Port: 50435, Role: greenplum.MirrorRole},
+	})
+
+	target := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: "/data/qddir/seg-1", Port: 15432, Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: -1, Hostname: "standby", DataDir: "/data/standby", Port: 16432, Role: greenplum.MirrorRole},
+		{DbID: 3, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg1", Port: 25433, Role: greenplum.PrimaryRole},
+		{DbID: 4, ContentID: 0, Hostname: "sdw2", DataDir: "/data/dbfast_mirror1/seg1", Port: 25434, Role: greenplum.MirrorRole},
+	})
+
+	cases := []struct {
+		name    string
+		version string
+	}{
+		{name: "6X", version: "6.25.0"},
+		{name: "7X", version: "7.0.0"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			caseDir := filepath.Join("testdata", "conf_golden", c.name)
+
+			outputDir := testutils.GetTempDir(t, "output")
+			defer testutils.MustRemoveAll(t, outputDir)
+
+			err := hub.SimulateConfFiles(filepath.Join(caseDir, "fixture"), outputDir, semver.MustParse(c.version), intermediate, target)
+			if err != nil {
+				t.Fatalf("unexpected error %+v", err)
+			}
+
+			expectedDir := filepath.Join(caseDir, "expected")
+			if *updateGolden {
+				testutils.MustRemoveAll(t, expectedDir)
+				for path, contents := range readTree(t, outputDir) {
+					testutils.MustCreateDir(t, filepath.Dir(filepath.Join(expectedDir, path)))
+					testutils.MustWriteToFile(t, filepath.Join(expectedDir, path), contents)
+				}
+			}
+
+			actual := readTree(t, outputDir)
+			expected := readTree(t, expectedDir)
+
+			if paths, expectedPaths := sortedKeys(actual), sortedKeys(expected); !reflect.DeepEqual(paths, expectedPaths) {
+				t.Fatalf("got files %q want %q. Run with -update to regenerate the golden files.", paths, expectedPaths)
+			}
+
+			for path, contents := range expected {
+				if actual[path] != contents {
+					t.Errorf("%s: got %q want %q. Run with -update to regenerate the golden files.", path, actual[path], contents)
+				}
+			}
+		})
+	}
+}
+
+// readTree returns the contents of every file under dir keyed by its path
+// relative to dir.
+func readTree(t *testing.T, dir string) map[string]string {
+	t.Helper()
+
+	files := make(map[string]string)
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+
+		relPath, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		files[relPath] = string(contents)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("read %s: %v", dir, err)
+	}
+
+	return files
+}
+
+func sortedKeys(files map[string]string) []string {
+	var keys []string
+	for key := range files {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
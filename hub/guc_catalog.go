@@ -0,0 +1,82 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/blang/semver/v4"
+)
+
+// renamedGUCs are the GUCs renamed starting with a major version, in addition
+// to the coordinator role GUCs. A source GUC missing from the target catalog
+// that is not renamed has been removed.
+var renamedGUCs = map[uint64]map[string]string{
+	7: {
+		"min_parallel_relation_size": "min_parallel_table_scan_size",
+	},
+}
+
+// GUCChanges are the source GUCs that do not exist in the target version.
+type GUCChanges struct {
+	// Removed are the GUCs with no equivalent in the target.
+	Removed []string
+	// Renamed maps a source GUC to its name in the target.
+	Renamed map[string]string
+}
+
+// CompareGUCCatalogs reports the GUCs of the source pg_settings that are
+// removed or renamed in the target catalog, so that overrides carried forward
+// can be skipped or translated. GUC names are compared case-insensitively.
+func CompareGUCCatalogs(source []string, target []string, targetVersion semver.Version) GUCChanges {
+	catalog := make(map[string]bool)
+	for _, name := range target {
+		catalog[strings.ToLower(name)] = true
+	}
+
+	renames := gucRenames(targetVersion)
+
+	changes := GUCChanges{Renamed: make(map[string]string)}
+	for _, name := range source {
+		name = strings.ToLower(name)
+		if catalog[name] {
+			continue
+		}
+
+		if renamed, ok := renames[name]; ok && catalog[renamed] {
+			changes.Renamed[name] = renamed
+			continue
+		}
+
+		changes.Removed = append(changes.Removed, name)
+	}
+
+	sort.Strings(changes.Removed)
+	return changes
+}
+
+// gucRenames returns the renames that apply up to and including the target
+// version.
+func gucRenames(targetVersion semver.Version) map[string]string {
+	renames := make(map[string]string)
+
+	for _, guc := range coordinatorRoleGUCs {
+		if targetVersion.Major >= guc.since {
+			renames[guc.name] = guc.renamed
+		}
+	}
+
+	for major, gucs := range renamedGUCs {
+		if targetVersion.Major < major {
+			continue
+		}
+
+		for name, renamed := range gucs {
+			renames[name] = renamed
+		}
+	}
+
+	return renames
+}
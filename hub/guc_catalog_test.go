@@ -0,0 +1,61 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/blang/semver/v4"
+
+	"github.com/greenplum-db/gpupgrade/hub"
+)
+
+func TestCompareGUCCatalogs(t *testing.T) {
+	source := []string{"port", "gp_session_role", "checkpoint_segments", "min_parallel_relation_size", "gpperfmon_port", "Work_Mem"}
+
+	cases := []struct {
+		name     string
+		target   []string
+		version  string
+		expected hub.GUCChanges
+	}{
+		{
+			name:    "reports removed and renamed GUCs across major versions",
+			target:  []string{"port", "gp_role", "max_wal_size", "min_parallel_table_scan_size", "work_mem"},
+			version: "7.0.0",
+			expected: hub.GUCChanges{
+				Removed: []string{"checkpoint_segments", "gpperfmon_port"},
+				Renamed: map[string]string{
+					"gp_session_role":            "gp_role",
+					"min_parallel_relation_size": "min_parallel_table_scan_size",
+				},
+			},
+		},
+		{
+			name:    "reports a renamed GUC as removed when the new name is not in the target",
+			target:  []string{"port", "checkpoint_segments", "min_parallel_relation_size", "gpperfmon_port", "work_mem"},
+			version: "7.0.0",
+			expected: hub.GUCChanges{
+				Removed: []string{"gp_session_role"},
+				Renamed: map[string]string{},
+			},
+		},
+		{
+			name:     "reports nothing when the target has every GUC",
+			target:   source,
+			version:  "6.25.0",
+			expected: hub.GUCChanges{Renamed: map[string]string{}},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			changes := hub.CompareGUCCatalogs(source, c.target, semver.MustParse(c.version))
+			if !reflect.DeepEqual(changes, c.expected) {
+				t.Errorf("got %+v want %+v", changes, c.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/blang/semver/v4"
+)
+
+// gucDefaults are the built-in defaults of commonly overridden GUCs by major
+// version. It is not exhaustive; GUCs missing from it are never reported.
+var gucDefaults = map[uint64]map[string]string{
+	6: {
+		"checkpoint_completion_target": "0.5",
+		"cpu_index_tuple_cost":         "0.005",
+		"cpu_operator_cost":            "0.0025",
+		"cpu_tuple_cost":               "0.01",
+		"gp_vmem_protect_limit":        "8192",
+		"log_min_messages":             "warning",
+		"maintenance_work_mem":         "64MB",
+		"random_page_cost":             "100",
+		"seq_page_cost":                "1",
+		"statement_mem":                "125MB",
+		"work_mem":                     "32MB",
+	},
+	7: {
+		"checkpoint_completion_target": "0.5",
+		"cpu_index_tuple_cost":         "0.005",
+		"cpu_operator_cost":            "0.0025",
+		"cpu_tuple_cost":               "0.01",
+		"gp_vmem_protect_limit":        "8192",
+		"log_min_messages":             "warning",
+		"maintenance_work_mem":         "64MB",
+		"random_page_cost":             "4",
+		"seq_page_cost":                "1",
+		"statement_mem":                "125MB",
+		"work_mem":                     "32MB",
+	},
+}
+
+// DefaultGUCWarning returns a warning suggesting an override be dropped when
+// its value is the built-in default of the target version. It only advises;
+// the override is still written.
+func DefaultGUCWarning(version semver.Version, name string, value string) (string, bool) {
+	defaultValue, ok := gucDefaults[version.Major][strings.ToLower(name)]
+	if !ok || !sameGUCValue(value, defaultValue) {
+		return "", false
+	}
+
+	return fmt.Sprintf("%s = %s is the built-in default of Greenplum %d. Consider dropping the override.", name, value, version.Major), true
+}
+
+// defaultGUCWarnings returns a warning for each GUC the plan sets to the
+// built-in default of the target version, once per GUC and value rather than
+// once per segment. The edits are still made.
+func defaultGUCWarnings(version semver.Version, plan ConfPlan) []string {
+	var warnings []string
+	warned := make(map[string]bool)
+	for _, edit := range plan {
+		if edit.Option.GetGuc() == "" {
+			continue
+		}
+
+		warning, ok := DefaultGUCWarning(version, edit.Option.GetGuc(), edit.NewValue)
+		if !ok || warned[warning] {
+			continue
+		}
+
+		warned[warning] = true
+		warnings = append(warnings, warning)
+	}
+
+	return warnings
+}
+
+// sameGUCValue compares values ignoring quotes and case, and compares real
+// values numerically so that 1 and 1.0 are the same.
+func sameGUCValue(value string, defaultValue string) bool {
+	value = strings.TrimSpace(value)
+	if len(value) >= 2 && strings.HasPrefix(value, "'") && strings.HasSuffix(value, "'") {
+		value = value[1 : len(value)-1]
+	}
+
+	if strings.EqualFold(value, defaultValue) {
+		return true
+	}
+
+	a, aErr := strconv.ParseFloat(value, 64)
+	b, bErr := strconv.ParseFloat(defaultValue, 64)
+	return aErr == nil && bErr == nil && a == b
+}
@@ -0,0 +1,46 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"testing"
+
+	"github.com/blang/semver/v4"
+
+	"github.com/greenplum-db/gpupgrade/hub"
+)
+
+func TestDefaultGUCWarning(t *testing.T) {
+	cases := []struct {
+		name     string
+		version  string
+		guc      string
+		value    string
+		expected string
+	}{
+		{name: "warns for the default", version: "7.0.0", guc: "random_page_cost", value: "4",
+			expected: "random_page_cost = 4 is the built-in default of Greenplum 7. Consider dropping the override."},
+		{name: "compares real values numerically", version: "7.0.0", guc: "seq_page_cost", value: "1.0",
+			expected: "seq_page_cost = 1.0 is the built-in default of Greenplum 7. Consider dropping the override."},
+		{name: "ignores quotes and case", version: "6.25.0", guc: "Work_Mem", value: "'32mb'",
+			expected: "Work_Mem = '32mb' is the built-in default of Greenplum 6. Consider dropping the override."},
+		{name: "uses the defaults of the target version", version: "6.25.0", guc: "random_page_cost", value: "4"},
+		{name: "does not warn for a non-default value", version: "7.0.0", guc: "work_mem", value: "64MB"},
+		{name: "does not warn for an unknown GUC", version: "7.0.0", guc: "gp_enable_gpperfmon", value: "off"},
+		{name: "does not warn for an unknown version", version: "5.29.0", guc: "work_mem", value: "32MB"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			warning, ok := hub.DefaultGUCWarning(semver.MustParse(c.version), c.guc, c.value)
+			if ok != (c.expected != "") {
+				t.Errorf("got warned %t want %t", ok, c.expected != "")
+			}
+
+			if warning != c.expected {
+				t.Errorf("got %q want %q", warning, c.expected)
+			}
+		})
+	}
+}
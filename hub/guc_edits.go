@@ -0,0 +1,228 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils/errorlist"
+)
+
+const ReasonGUCEdit = "guc-edit"
+
+// GUCEdit sets a GUC in the postgresql.conf of every segment, such as to
+// reset shared_preload_libraries or raise max_connections for the target
+// cluster. Unlike a CustomConfEdit its pattern is built from the GUC, and the
+// setting is appended when the GUC is not set.
+type GUCEdit struct {
+	Name  string
+	Value string
+}
+
+var gucNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*$`)
+
+// Validate checks that the name is that of a GUC not rewritten by gpupgrade
+// itself, and that the value fits on a single line. The value of a real
+// valued GUC must be a decimal number so that it is not written in a locale
+// dependent form the server cannot parse.
+func (e GUCEdit) Validate() error {
+	if !gucNamePattern.MatchString(e.Name) {
+		return xerrors.Errorf("GUC edit has invalid name %q", e.Name)
+	}
+
+	for _, guc := range managedGUCs {
+		if strings.EqualFold(e.Name, guc) {
+			return xerrors.Errorf("GUC edit of %s is not allowed as it is managed by gpupgrade", e.Name)
+		}
+	}
+
+	if strings.ContainsAny(e.Value, "\r\n") {
+		return xerrors.Errorf("GUC edit of %s has value %q that is not a single line", e.Name, e.Value)
+	}
+
+	if floatGUCs[strings.ToLower(e.Name)] {
+		return validateFloatGUC(e.Name, e.Value)
+	}
+
+	return nil
+}
+
+// floatGUCs are the real valued GUCs. The server always parses them using
+// "." as the decimal separator regardless of the cluster's locale.
+var floatGUCs = map[string]bool{
+	"autovacuum_analyze_scale_factor":       true,
+	"autovacuum_vacuum_insert_scale_factor": true,
+	"autovacuum_vacuum_scale_factor":        true,
+	"bgwriter_lru_multiplier":               true,
+	"checkpoint_completion_target":          true,
+	"cpu_index_tuple_cost":                  true,
+	"cpu_operator_cost":                     true,
+	"cpu_tuple_cost":                        true,
+	"cursor_tuple_fraction":                 true,
+	"geqo_seed":                             true,
+	"geqo_selection_bias":                   true,
+	"gp_resource_group_cpu_limit":           true,
+	"gp_resource_group_memory_limit":        true,
+	"hash_mem_multiplier":                   true,
+	"jit_above_cost":                        true,
+	"jit_inline_above_cost":                 true,
+	"jit_optimize_above_cost":               true,
+	"log_statement_sample_rate":             true,
+	"log_transaction_sample_rate":           true,
+	"parallel_setup_cost":                   true,
+	"parallel_tuple_cost":                   true,
+	"random_page_cost":                      true,
+	"seq_page_cost":                         true,
+	"vacuum_cleanup_index_scale_factor":     true,
+}
+
+// floatPattern matches the decimal notation accepted for a real valued GUC.
+// Hexadecimal, infinite, and NaN values are rejected even though strtod
+// would accept them.
+var floatPattern = regexp.MustCompile(`^[+-]?([0-9]+\.?[0-9]*|\.[0-9]+)([eE][+-]?[0-9]+)?$`)
+
+func validateFloatGUC(name string, value string) error {
+	number := value
+	if len(number) >= 2 && strings.HasPrefix(number, "'") && strings.HasSuffix(number, "'") {
+		number = number[1 : len(number)-1]
+	}
+
+	if floatPattern.MatchString(number) {
+		return nil
+	}
+
+	if strings.Contains(number, ",") {
+		return xerrors.Errorf(`value %q for %s uses "," as a separator. Use "." as the decimal separator regardless of locale.`, value, name)
+	}
+
+	return xerrors.Errorf("value %q for %s is not a decimal number", value, name)
+}
+
+// validateGUCEdits returns every invalid edit along with each GUC edited more
+// than once, since only one of its values could be in effect.
+func validateGUCEdits(edits []GUCEdit) error {
+	var err error
+	seen := make(map[string]bool)
+	for _, edit := range edits {
+		err = errorlist.Append(err, edit.Validate())
+
+		name := strings.ToLower(edit.Name)
+		if seen[name] {
+			err = errorlist.Append(err, xerrors.Errorf("GUC %s is edited more than once", edit.Name))
+		}
+		seen[name] = true
+	}
+
+	return err
+}
+
+// gucEditPattern matches a setting of a GUC, capturing the name along with
+// its separator and anything following the value such as a comment. The name
+// is matched case-insensitively as postgres does.
+const gucEditPattern = `(?i)(^[ \t]*%s(?:[ \t]*=[ \t]*|[ \t]+))(?:'(?:[^']|'')*'|[^ \t#]*)(.*)$`
+
+var bareConfValuePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// quoteConfValue returns the value as written in a conf file, which is single
+// quoted unless it is a bare number or word.
+func quoteConfValue(value string) string {
+	if bareConfValuePattern.MatchString(value) {
+		return value
+	}
+
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+// gucConfValue returns the value of the GUC as written in a conf file. The
+// validated value of a real valued GUC is written as given to preserve its
+// numeric format.
+func gucConfValue(name string, value string) string {
+	if floatGUCs[strings.ToLower(name)] {
+		return value
+	}
+
+	return quoteConfValue(value)
+}
+
+// gucEdit sets the GUC in the postgresql.conf of a data directory. Only the
+// last setting is rewritten as it is the one in effect.
+func gucEdit(hostname string, dataDir string, edit GUCEdit) ConfEdit {
+	value := gucConfValue(edit.Name, edit.Value)
+	return ConfEdit{
+		Hostname: hostname,
+		NewValue: edit.Value,
+		Option: &idl.UpdateFileConfOptions{
+			Path:             filepath.Join(dataDir, "postgresql.conf"),
+			Pattern:          fmt.Sprintf(gucEditPattern, regexp.QuoteMeta(edit.Name)),
+			Replacement:      `\1` + quoteReplacement(value) + `\2`,
+			Reason:           ReasonGUCEdit,
+			Guc:              edit.Name,
+			ExpectedValue:    edit.Value,
+			AppendIfMissing:  edit.Name + " = " + value,
+			DuplicateMatches: idl.UpdateFileConfOptions_rewriteLastMatch,
+			DataDir:          dataDir,
+		},
+	}
+}
+
+// gucEditsOnHost returns the GUC edits of the selected segments of the
+// target cluster on a host.
+func gucEditsOnHost(hostname string, target *greenplum.Cluster, edits []GUCEdit, selector func(seg *greenplum.SegConfig) bool) ConfPlan {
+	var plan ConfPlan
+
+	target.ForEachSegment(func(seg *greenplum.SegConfig) bool {
+		return seg.IsOnHost(hostname) && selector(seg) && confIncluded(seg)
+	}, func(seg *greenplum.SegConfig) bool {
+		for _, edit := range edits {
+			plan = append(plan, forSegment(gucEdit(hostname, seg.DataDir, edit), seg))
+		}
+		return true
+	})
+
+	return plan
+}
+
+// ApplyGUCEdits sets the GUCs of the edits in the postgresql.conf of every
+// segment of the target cluster, locally on the coordinator and through the
+// agents on every other segment. The edits of each file are made in a single
+// pass, and a GUC already at its value is left as is so that rerunning changes
+// nothing.
+func ApplyGUCEdits(ctx context.Context, agentConns []*idl.Connection, target *greenplum.Cluster, edits []GUCEdit) error {
+	if err := validateGUCEdits(edits); err != nil {
+		return err
+	}
+
+	if len(edits) == 0 {
+		return nil
+	}
+
+	coordinator := gucEditsOnHost(target.CoordinatorHostname(), target, edits, func(seg *greenplum.SegConfig) bool {
+		return seg.IsCoordinator()
+	})
+
+	// every segment is set to the same values so those of the coordinator
+	// cover the edits
+	for _, warning := range defaultGUCWarnings(target.Version, coordinator) {
+		log.Printf("Warning: %s", warning)
+	}
+
+	if _, err := UpdateConfigurationFileChanges(ctx, expectedValueOptions(coordinator, coordinator.Options())); err != nil {
+		return err
+	}
+
+	return updateConfOnHosts(ctx, agentConns, nil, func(hostname string) (ConfPlan, error) {
+		return gucEditsOnHost(hostname, target, edits, func(seg *greenplum.SegConfig) bool {
+			return !seg.IsCoordinator()
+		}), nil
+	})
+}
@@ -0,0 +1,168 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/blang/semver/v4"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/testutils"
+	"github.com/greenplum-db/gpupgrade/testutils/testlog"
+)
+
+func TestApplyGUCEdits(t *testing.T) {
+	edits := []hub.GUCEdit{
+		{Name: "max_connections", Value: "1000"},
+		{Name: "shared_preload_libraries", Value: "metrics_collector, 'pg_stat'"},
+	}
+
+	updated := func(seeded string) string {
+		return strings.Replace(seeded, "max_connections=750\n", "max_connections=1000\n", 1) +
+			"shared_preload_libraries = 'metrics_collector, ''pg_stat'''\n"
+	}
+
+	t.Run("sets the GUCs of every segment", func(t *testing.T) {
+		c := hub.MustCreateConfTestCluster(t, semver.MustParse("7.0.0"))
+		defer testutils.MustRemoveAll(t, c.Dir)
+
+		// rerunning leaves the files as they are
+		for i := 0; i < 2; i++ {
+			err := hub.ApplyGUCEdits(context.Background(), c.AgentConns, c.Target, edits)
+			if err != nil {
+				t.Fatalf("unexpected error %+v", err)
+			}
+
+			for _, seg := range c.Target.SelectSegments(func(*greenplum.SegConfig) bool { return true }) {
+				contents := c.MustReadConfFile(t, seg.DbID, "postgresql.conf")
+				expected := updated(c.SeededConfFiles(seg.DbID)["postgresql.conf"])
+				if contents != expected {
+					t.Errorf("got postgresql.conf of dbid %d %q want %q", seg.DbID, contents, expected)
+				}
+			}
+		}
+	})
+
+	t.Run("warns for a GUC set to the default of the target version", func(t *testing.T) {
+		log := testlog.SetupTestLogger()
+
+		c := hub.MustCreateConfTestCluster(t, semver.MustParse("7.0.0"))
+		defer testutils.MustRemoveAll(t, c.Dir)
+		c.Target.Version = c.Version
+
+		err := hub.ApplyGUCEdits(context.Background(), c.AgentConns, c.Target, []hub.GUCEdit{{Name: "random_page_cost", Value: "4"}})
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		testlog.VerifyLogContains(t, log, "Warning: random_page_cost = 4 is the built-in default of Greenplum 7. Consider dropping the override.")
+
+		contents := c.MustReadConfFile(t, 1, "postgresql.conf")
+		if !strings.Contains(contents, "random_page_cost = 4\n") {
+			t.Errorf("got postgresql.conf %q want the override still written", contents)
+		}
+	})
+
+	t.Run("rewrites the last setting and preserves its comment", func(t *testing.T) {
+		c := hub.MustCreateConfTestCluster(t, semver.MustParse("7.0.0"))
+		defer testutils.MustRemoveAll(t, c.Dir)
+
+		coordinator := c.Target.Coordinator()
+		path := filepath.Join(coordinator.DataDir, "postgresql.conf")
+		testutils.MustWriteToFile(t, path, "#max_connections = 100\nMAX_CONNECTIONS 200\nmax_connections = 300 # raised\n")
+
+		err := hub.ApplyGUCEdits(context.Background(), c.AgentConns, c.Target, edits[:1])
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		expected := "#max_connections = 100\nMAX_CONNECTIONS 200\nmax_connections = 1000 # raised\n"
+		contents := testutils.MustReadFile(t, path)
+		if contents != expected {
+			t.Errorf("got %q want %q", contents, expected)
+		}
+	})
+
+	t.Run("errors on invalid edits without editing any file", func(t *testing.T) {
+		c := hub.MustCreateConfTestCluster(t, semver.MustParse("7.0.0"))
+		defer testutils.MustRemoveAll(t, c.Dir)
+
+		invalid := []hub.GUCEdit{
+			{Name: "max_connections", Value: "1000"},
+			{Name: "port", Value: "6000"},
+			{Name: "max connections", Value: "1000"},
+			{Name: "search_path", Value: "public\nport = 6000"},
+			{Name: "MAX_CONNECTIONS", Value: "2000"},
+		}
+
+		err := hub.ApplyGUCEdits(context.Background(), c.AgentConns, c.Target, invalid)
+		for _, expected := range []string{
+			"GUC edit of port is not allowed as it is managed by gpupgrade",
+			`GUC edit has invalid name "max connections"`,
+			`GUC edit of search_path has value "public\nport = 6000" that is not a single line`,
+			"GUC MAX_CONNECTIONS is edited more than once",
+		} {
+			if err == nil || !strings.Contains(err.Error(), expected) {
+				t.Errorf("got error %v want it to contain %q", err, expected)
+			}
+		}
+
+		for _, seg := range c.Target.SelectSegments(func(*greenplum.SegConfig) bool { return true }) {
+			contents := c.MustReadConfFile(t, seg.DbID, "postgresql.conf")
+			if contents != c.SeededConfFiles(seg.DbID)["postgresql.conf"] {
+				t.Errorf("got postgresql.conf of dbid %d %q want it unchanged", seg.DbID, contents)
+			}
+		}
+	})
+
+	t.Run("preserves the numeric format of real valued GUCs", func(t *testing.T) {
+		for _, value := range []string{"1.0", "1", ".5", "1.", "-0.25", "+1e3", "2.5E-1", "'1.5'"} {
+			t.Run(value, func(t *testing.T) {
+				c := hub.MustCreateConfTestCluster(t, semver.MustParse("7.0.0"))
+				defer testutils.MustRemoveAll(t, c.Dir)
+
+				path := filepath.Join(c.Target.Coordinator().DataDir, "postgresql.conf")
+				testutils.MustWriteToFile(t, path, "seq_page_cost = 4.0\n")
+
+				err := hub.ApplyGUCEdits(context.Background(), c.AgentConns, c.Target, []hub.GUCEdit{{Name: "seq_page_cost", Value: value}})
+				if err != nil {
+					t.Fatalf("unexpected error %+v", err)
+				}
+
+				expected := "seq_page_cost = " + value + "\n"
+				contents := testutils.MustReadFile(t, path)
+				if contents != expected {
+					t.Errorf("got %q want %q", contents, expected)
+				}
+			})
+		}
+	})
+
+	t.Run("rejects real valued GUCs that would be misparsed", func(t *testing.T) {
+		cases := []struct {
+			value    string
+			expected string
+		}{
+			{value: "1,5", expected: `uses "," as a separator`},
+			{value: "'1,5'", expected: `uses "," as a separator`},
+			{value: "1.5.0", expected: "is not a decimal number"},
+			{value: "nan", expected: "is not a decimal number"},
+			{value: "0x1p-2", expected: "is not a decimal number"},
+		}
+
+		for _, c := range cases {
+			t.Run(c.value, func(t *testing.T) {
+				err := hub.GUCEdit{Name: "random_page_cost", Value: c.value}.Validate()
+				if err == nil || !strings.Contains(err.Error(), c.expected) {
+					t.Errorf("expected error %v to contain %q", err, c.expected)
+				}
+			})
+		}
+	})
+}
@@ -0,0 +1,100 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/blang/semver/v4"
+	"golang.org/x/xerrors"
+)
+
+// GUCOverride is a GUC that needs to be set on the target since its source
+// value differs from the built-in default of the target version.
+type GUCOverride struct {
+	// Name is the name of the GUC in the target, which differs from the
+	// source when the GUC was renamed.
+	Name          string
+	Value         string
+	TargetDefault string
+}
+
+// GUCOverrides is the minimal set of source settings to carry forward.
+type GUCOverrides struct {
+	Overrides []GUCOverride
+	// Unknown are the non-default source GUCs with no known default in the
+	// target version, which need to be reviewed by hand.
+	Unknown []string
+}
+
+// MinimalGUCOverrides returns the source settings, such as the non-default
+// rows of the source pg_settings, whose value differs from the built-in
+// default of the target version. GUCs rewritten by gpupgrade are left out
+// since their target values are derived from the cluster, and renamed GUCs
+// are reported under their target name. It is read-only analysis; nothing is
+// written to the target.
+func MinimalGUCOverrides(source map[string]string, targetVersion semver.Version) (GUCOverrides, error) {
+	defaults, ok := gucDefaults[targetVersion.Major]
+	if !ok {
+		return GUCOverrides{}, xerrors.Errorf("the built-in GUC defaults of Greenplum %d are not known", targetVersion.Major)
+	}
+
+	managed := make(map[string]bool)
+	for _, name := range managedGUCs {
+		managed[name] = true
+	}
+
+	renames := gucRenames(targetVersion)
+
+	var overrides GUCOverrides
+	for name, value := range source {
+		name = strings.ToLower(name)
+		if renamed, ok := renames[name]; ok {
+			name = renamed
+		}
+
+		if managed[name] {
+			continue
+		}
+
+		defaultValue, ok := defaults[name]
+		if !ok {
+			overrides.Unknown = append(overrides.Unknown, name)
+			continue
+		}
+
+		if sameGUCValue(value, defaultValue) {
+			continue
+		}
+
+		overrides.Overrides = append(overrides.Overrides, GUCOverride{Name: name, Value: value, TargetDefault: defaultValue})
+	}
+
+	sort.Slice(overrides.Overrides, func(i, j int) bool { return overrides.Overrides[i].Name < overrides.Overrides[j].Name })
+	sort.Strings(overrides.Unknown)
+	return overrides, nil
+}
+
+// WriteConf writes the overrides as postgresql.conf settings for review,
+// with the target default of each and the unknown GUCs as comments.
+func (o GUCOverrides) WriteConf(w io.Writer) error {
+	for _, override := range o.Overrides {
+		_, err := fmt.Fprintf(w, "%s = '%s' # target default: %s\n", override.Name, strings.ReplaceAll(unquoteConfValue(override.Value), "'", "''"), override.TargetDefault)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, name := range o.Unknown {
+		_, err := fmt.Fprintf(w, "# %s has no known target default; review its source value\n", name)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
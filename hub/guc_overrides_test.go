@@ -0,0 +1,79 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/blang/semver/v4"
+
+	"github.com/greenplum-db/gpupgrade/hub"
+)
+
+func TestMinimalGUCOverrides(t *testing.T) {
+	source := map[string]string{
+		"port":              "5432",
+		"gp_session_role":   "dispatch",
+		"random_page_cost":  "100",
+		"Work_Mem":          "'64MB'",
+		"statement_mem":     "125MB",
+		"seq_page_cost":     "1.0",
+		"gp_resource_group": "on",
+		"log_min_messages":  "'it''s'",
+	}
+
+	t.Run("returns the settings differing from the target defaults", func(t *testing.T) {
+		overrides, err := hub.MinimalGUCOverrides(source, semver.MustParse("7.0.0"))
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		expected := hub.GUCOverrides{
+			Overrides: []hub.GUCOverride{
+				{Name: "log_min_messages", Value: "'it''s'", TargetDefault: "warning"},
+				{Name: "random_page_cost", Value: "100", TargetDefault: "4"},
+				{Name: "work_mem", Value: "'64MB'", TargetDefault: "32MB"},
+			},
+			Unknown: []string{"gp_resource_group"},
+		}
+		if !reflect.DeepEqual(overrides, expected) {
+			t.Errorf("got %+v want %+v", overrides, expected)
+		}
+
+		var buf bytes.Buffer
+		if err := overrides.WriteConf(&buf); err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		expectedConf := `log_min_messages = 'it''s' # target default: warning
+random_page_cost = '100' # target default: 4
+work_mem = '64MB' # target default: 32MB
+# gp_resource_group has no known target default; review its source value
+`
+		if buf.String() != expectedConf {
+			t.Errorf("got %q want %q", buf.String(), expectedConf)
+		}
+	})
+
+	t.Run("uses the defaults of the target version", func(t *testing.T) {
+		overrides, err := hub.MinimalGUCOverrides(map[string]string{"random_page_cost": "100"}, semver.MustParse("6.25.0"))
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		if len(overrides.Overrides) != 0 || len(overrides.Unknown) != 0 {
+			t.Errorf("expected no overrides, got %+v", overrides)
+		}
+	})
+
+	t.Run("errors when the target defaults are not known", func(t *testing.T) {
+		_, err := hub.MinimalGUCOverrides(source, semver.MustParse("5.29.0"))
+		expected := "the built-in GUC defaults of Greenplum 5 are not known"
+		if err == nil || err.Error() != expected {
+			t.Errorf("got error %v want %q", err, expected)
+		}
+	})
+}
@@ -68,7 +68,7 @@ func TestRestartAgent(t *testing.T) {
 			return listener.Dial()
 		}
 
-		restartedHosts, err := hub.RestartAgents(ctx, dialer, hostnames, port, stateDir)
+		restartedHosts, err := hub.RestartAgents(ctx, dialer, "", hostnames, port, stateDir)
 		if err != nil {
 			t.Errorf("returned %#v", err)
 		}
@@ -88,7 +88,7 @@ func TestRestartAgent(t *testing.T) {
 			return listener.Dial()
 		}
 
-		restartedHosts, err := hub.RestartAgents(ctx, dialer, hostnames, port, stateDir)
+		restartedHosts, err := hub.RestartAgents(ctx, dialer, "", hostnames, port, stateDir)
 		if err != nil {
 			t.Errorf("returned %#v", err)
 		}
@@ -111,7 +111,7 @@ func TestRestartAgent(t *testing.T) {
 			return nil, immediateFailure{}
 		}
 
-		restartedHosts, err := hub.RestartAgents(ctx, dialer, hostnames, port, stateDir)
+		restartedHosts, err := hub.RestartAgents(ctx, dialer, "", hostnames, port, stateDir)
 		if err == nil {
 			t.Errorf("expected restart agents to fail")
 		}
@@ -162,7 +162,35 @@ func TestRestartAgent(t *testing.T) {
 			return listener.Dial()
 		}
 
-		_, err := hub.RestartAgents(ctx, dialer, hostnames, port, stateDir)
+		_, err := hub.RestartAgents(ctx, dialer, "", hostnames, port, stateDir)
+		if err != nil {
+			t.Errorf("unexpected errr %#v", err)
+		}
+	})
+
+	t.Run("starts agents through the jump host", func(t *testing.T) {
+		host := "host1"
+		jumpHost := "gpadmin@bastion"
+
+		execCmd := exectest.NewCommandWithVerifier(gpupgrade_agent, func(name string, args ...string) {
+			cmd := fmt.Sprintf("bash -c \"%s/gpupgrade agent --daemonize --port %d --state-directory %s\"", testutils.MustGetExecutablePath(t), port, stateDir)
+			expected := []string{"-J", jumpHost, host, cmd}
+			if !reflect.DeepEqual(args, expected) {
+				t.Errorf("got %q want %q", args, expected)
+			}
+		})
+		hub.SetExecCommand(execCmd)
+		defer hub.ResetExecCommand()
+
+		dialer := func(ctx context.Context, address string) (net.Conn, error) {
+			if strings.HasPrefix(address, host) { // fail connection attempts to host
+				return nil, immediateFailure{}
+			}
+
+			return listener.Dial()
+		}
+
+		_, err := hub.RestartAgents(ctx, dialer, jumpHost, hostnames, port, stateDir)
 		if err != nil {
 			t.Errorf("unexpected errr %#v", err)
 		}
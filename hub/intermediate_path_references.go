@@ -0,0 +1,160 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/blang/semver/v4"
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/step"
+	"github.com/greenplum-db/gpupgrade/utils/errorlist"
+)
+
+// strictPathReferences fails CheckIntermediatePathReferences when a reference
+// is found rather than only warning.
+var strictPathReferences = false
+
+func SetStrictPathReferences(strict bool) {
+	strictPathReferences = strict
+}
+
+func ResetStrictPathReferences() {
+	strictPathReferences = false
+}
+
+// PathReference is a line of a conf file referring to an intermediate data
+// directory.
+type PathReference struct {
+	Hostname   string
+	Path       string
+	LineNumber int
+	Line       string
+	DataDir    string
+}
+
+func (r PathReference) String() string {
+	return fmt.Sprintf("%s:%d on host %s references the intermediate data directory %s: %s", r.Path, r.LineNumber, r.Hostname, r.DataDir, r.Line)
+}
+
+// CheckIntermediatePathReferences scans every conf file edited by
+// UpdateConfFiles for lingering references to the transient data directories
+// of the intermediate cluster, which indicate an incomplete rewrite. Each
+// reference is written to stdout as a warning, and under strict mode the
+// references are returned as errors.
+func CheckIntermediatePathReferences(agentConns []*idl.Connection, streams step.OutStreams, version semver.Version, intermediate *greenplum.Cluster, target *greenplum.Cluster) error {
+	references, err := FindIntermediatePathReferences(agentConns, version, intermediate, target)
+	if err != nil {
+		return err
+	}
+
+	for _, reference := range references {
+		fmt.Fprintf(streams.Stdout(), "Warning: %s\n", reference)
+
+		if strictPathReferences {
+			err = errorlist.Append(err, xerrors.New(reference.String()))
+		}
+	}
+
+	return err
+}
+
+// FindIntermediatePathReferences returns the references to the intermediate
+// data directories in the edited conf files, sorted by host, file, and line.
+func FindIntermediatePathReferences(agentConns []*idl.Connection, version semver.Version, intermediate *greenplum.Cluster, target *greenplum.Cluster) ([]PathReference, error) {
+	plan, err := PlanConfFiles(version, intermediate, target)
+	if err != nil {
+		return nil, err
+	}
+
+	var dataDirs []string
+	intermediate.ForEachSegment(func(*greenplum.SegConfig) bool { return true }, func(seg *greenplum.SegConfig) bool {
+		dataDirs = append(dataDirs, seg.DataDir)
+		return true
+	})
+
+	// the edited files of each host
+	hostFiles := make(map[string][]*idl.ReadConfigurationRequest_File)
+	seen := make(map[string]bool)
+	for _, edit := range plan {
+		key := segmentKey(edit.Hostname, edit.Option.GetPath())
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		hostFiles[edit.Hostname] = append(hostFiles[edit.Hostname], &idl.ReadConfigurationRequest_File{
+			Path:         edit.Option.GetPath(),
+			Searches:     dataDirs,
+			SkipIfAbsent: edit.Option.GetSkipIfAbsent(),
+		})
+	}
+
+	var mutex sync.Mutex
+	var references []PathReference
+	collect := func(hostname string, matches []*idl.ReadConfigurationReply_Match) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		for _, match := range matches {
+			references = append(references, PathReference{
+				Hostname:   hostname,
+				Path:       match.GetPath(),
+				LineNumber: int(match.GetLineNumber()),
+				Line:       match.GetLine(),
+				DataDir:    match.GetSearch(),
+			})
+		}
+	}
+
+	coordinator := target.CoordinatorHostname()
+	if files := hostFiles[coordinator]; len(files) > 0 {
+		matches, err := SearchConfigurationFile(files)
+		if err != nil {
+			return nil, err
+		}
+
+		collect(coordinator, matches)
+	}
+
+	request := func(ctx context.Context, conn *idl.Connection) error {
+		files := hostFiles[conn.Hostname]
+		if len(files) == 0 || conn.Hostname == coordinator {
+			return nil
+		}
+
+		reply, err := conn.AgentClient.ReadConfiguration(ctx, &idl.ReadConfigurationRequest{Files: files})
+		if err != nil {
+			return xerrors.Errorf("search configuration on host %s: %w", conn.Hostname, err)
+		}
+
+		collect(conn.Hostname, reply.GetMatches())
+		return nil
+	}
+
+	if err := ExecuteRPCContext(context.Background(), agentConns, request); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(references, func(i, j int) bool {
+		a, b := references[i], references[j]
+		if a.Hostname != b.Hostname {
+			return a.Hostname < b.Hostname
+		}
+
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+
+		return a.LineNumber < b.LineNumber
+	})
+
+	return references, nil
+}
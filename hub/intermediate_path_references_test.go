@@ -0,0 +1,170 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/blang/semver/v4"
+	"github.com/golang/mock/gomock"
+	"google.golang.org/grpc"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/idl/mock_idl"
+	"github.com/greenplum-db/gpupgrade/step"
+	"github.com/greenplum-db/gpupgrade/testutils"
+)
+
+func TestCheckIntermediatePathReferences(t *testing.T) {
+	coordinatorDir := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, coordinatorDir)
+
+	testutils.MustWriteToFile(t, filepath.Join(coordinatorDir, "postgresql.conf"), "port=15432\n")
+
+	intermediate := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: "/data/qddir/seg.HqtFHX54y0o.-1", Port: 50432, Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg.HqtFHX54y0o.1", Port: 50434, Role: greenplum.PrimaryRole},
+	})
+	target := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: coordinatorDir, Port: 15432, Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg1", Port: 25433, Role: greenplum.PrimaryRole},
+	})
+
+	version := semver.MustParse("7.0.0")
+
+	// agent searches conf files with the given contents
+	agent := func(ctrl *gomock.Controller, contents map[string]string) *mock_idl.MockAgentClient {
+		client := mock_idl.NewMockAgentClient(ctrl)
+		client.EXPECT().ReadConfiguration(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, req *idl.ReadConfigurationRequest, _ ...grpc.CallOption) (*idl.ReadConfigurationReply, error) {
+				reply := &idl.ReadConfigurationReply{}
+				for _, file := range req.GetFiles() {
+					for i, line := range strings.Split(contents[file.GetPath()], "\n") {
+						for _, search := range file.GetSearches() {
+							if strings.Contains(line, search) {
+								reply.Matches = append(reply.Matches, &idl.ReadConfigurationReply_Match{Path: file.GetPath(), LineNumber: int32(i + 1), Line: line, Search: search})
+							}
+						}
+					}
+				}
+				return reply, nil
+			})
+		return client
+	}
+
+	t.Run("finds nothing when the paths were rewritten", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		agentConns := []*idl.Connection{{Hostname: "sdw1", AgentClient: agent(ctrl, map[string]string{
+			"/data/dbfast1/seg1/postgresql.conf": "port=25433\nlog_directory = '/data/dbfast1/seg1/log'\n",
+		})}}
+
+		streams := &step.BufferedStreams{}
+		err := hub.CheckIntermediatePathReferences(agentConns, streams, version, intermediate, target)
+		if err != nil {
+			t.Errorf("unexpected error %+v", err)
+		}
+
+		if streams.StdoutBuf.Len() != 0 {
+			t.Errorf("unexpected stdout %q", streams.StdoutBuf.String())
+		}
+	})
+
+	contents := map[string]string{
+		"/data/dbfast1/seg1/postgresql.conf": "port=25433\nlog_directory = '/data/dbfast1/seg.HqtFHX54y0o.1/log'\n",
+	}
+	expected := "/data/dbfast1/seg1/postgresql.conf:2 on host sdw1 references the intermediate data directory /data/dbfast1/seg.HqtFHX54y0o.1: log_directory = '/data/dbfast1/seg.HqtFHX54y0o.1/log'"
+
+	t.Run("reports the file and the offending line", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		agentConns := []*idl.Connection{{Hostname: "sdw1", AgentClient: agent(ctrl, contents)}}
+
+		references, err := hub.FindIntermediatePathReferences(agentConns, version, intermediate, target)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		expectedReferences := []hub.PathReference{{
+			Hostname:   "sdw1",
+			Path:       "/data/dbfast1/seg1/postgresql.conf",
+			LineNumber: 2,
+			Line:       "log_directory = '/data/dbfast1/seg.HqtFHX54y0o.1/log'",
+			DataDir:    "/data/dbfast1/seg.HqtFHX54y0o.1",
+		}}
+		if !reflect.DeepEqual(references, expectedReferences) {
+			t.Errorf("got %+v want %+v", references, expectedReferences)
+		}
+	})
+
+	t.Run("warns about references", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		agentConns := []*idl.Connection{{Hostname: "sdw1", AgentClient: agent(ctrl, contents)}}
+
+		streams := &step.BufferedStreams{}
+		err := hub.CheckIntermediatePathReferences(agentConns, streams, version, intermediate, target)
+		if err != nil {
+			t.Errorf("unexpected error %+v", err)
+		}
+
+		if streams.StdoutBuf.String() != "Warning: "+expected+"\n" {
+			t.Errorf("got stdout %q want %q", streams.StdoutBuf.String(), "Warning: "+expected+"\n")
+		}
+	})
+
+	t.Run("errors on references under strict mode", func(t *testing.T) {
+		hub.SetStrictPathReferences(true)
+		defer hub.ResetStrictPathReferences()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		agentConns := []*idl.Connection{{Hostname: "sdw1", AgentClient: agent(ctrl, contents)}}
+
+		err := hub.CheckIntermediatePathReferences(agentConns, step.DevNullStream, version, intermediate, target)
+		if err == nil || err.Error() != expected {
+			t.Errorf("got error %v want %q", err, expected)
+		}
+	})
+
+	t.Run("searches the coordinator locally", func(t *testing.T) {
+		testutils.MustWriteToFile(t, filepath.Join(coordinatorDir, "postgresql.conf"), "port=15432\ngp_perfmon_dir = '/data/qddir/seg.HqtFHX54y0o.-1/perfmon'\n")
+		defer testutils.MustWriteToFile(t, filepath.Join(coordinatorDir, "postgresql.conf"), "port=15432\n")
+
+		references, err := hub.FindIntermediatePathReferences(nil, version, intermediate, target)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		if len(references) != 1 || references[0].Hostname != "coordinator" || references[0].LineNumber != 2 {
+			t.Errorf("got %+v want a reference on line 2 of the coordinator", references)
+		}
+	})
+
+	t.Run("errors when the search fails", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		expected := errors.New("permission denied")
+		client := mock_idl.NewMockAgentClient(ctrl)
+		client.EXPECT().ReadConfiguration(gomock.Any(), gomock.Any()).Return(nil, expected)
+		agentConns := []*idl.Connection{{Hostname: "sdw1", AgentClient: client}}
+
+		err := hub.CheckIntermediatePathReferences(agentConns, step.DevNullStream, version, intermediate, target)
+		if !errors.Is(err, expected) {
+			t.Errorf("got error %#v want %#v", err, expected)
+		}
+	})
+}
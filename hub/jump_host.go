@@ -0,0 +1,106 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"context"
+	"io"
+	"log"
+	"net"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+// JumpHostDialer returns a gRPC context dialer that reaches the agent address
+// by tunneling through the jump host with "ssh -W". The jump host has the
+// form [user@]host[:port]. The ssh process lives as long as the returned
+// connection; closing the connection stops it.
+func JumpHostDialer(jumpHost string) func(context.Context, string) (net.Conn, error) {
+	destination, port := jumpHostDestination(jumpHost)
+
+	return func(ctx context.Context, address string) (net.Conn, error) {
+		if err := ctx.Err(); err != nil {
+			return nil, xerrors.Errorf("dial %s through jump host %s: %w", address, jumpHost, err)
+		}
+
+		args := []string{"-W", address}
+		if port != "" {
+			args = append(args, "-p", port)
+		}
+		args = append(args, destination)
+
+		// gRPC cancels the context of the dial once the connection is made,
+		// so the ssh process is bound to a context of its own that is
+		// canceled when the connection is closed.
+		tunnelCtx, cancel := context.WithCancel(context.Background())
+		cmd := ExecCommandContext(tunnelCtx, "ssh", args...)
+
+		stdin, err := cmd.StdinPipe()
+		if err != nil {
+			cancel()
+			return nil, xerrors.Errorf("dial %s through jump host %s: %w", address, jumpHost, err)
+		}
+
+		stdout, err := cmd.StdoutPipe()
+		if err != nil {
+			cancel()
+			return nil, xerrors.Errorf("dial %s through jump host %s: %w", address, jumpHost, err)
+		}
+
+		if err := cmd.Start(); err != nil {
+			cancel()
+			return nil, xerrors.Errorf("dial %s through jump host %s: %w", address, jumpHost, err)
+		}
+
+		// Hand gRPC one end of an in-memory pipe and shuttle the other end to
+		// the ssh process, since the pipes of a process are not a net.Conn.
+		conn, tunnel := net.Pipe()
+
+		go func() {
+			_, _ = io.Copy(stdin, tunnel)
+			_ = stdin.Close()
+			cancel()
+		}()
+
+		go func() {
+			_, _ = io.Copy(tunnel, stdout)
+			_ = tunnel.Close()
+
+			if err := cmd.Wait(); err != nil && tunnelCtx.Err() == nil {
+				log.Printf("ssh tunnel to %s through jump host %s exited: %v", address, jumpHost, err)
+			}
+			cancel()
+		}()
+
+		return conn, nil
+	}
+}
+
+// jumpHostDestination splits a jump host of the form [user@]host[:port], as
+// taken by "ssh -J", into the destination and port taken by "ssh -W", whose
+// destination cannot have a port. The port is empty when none is given.
+func jumpHostDestination(jumpHost string) (string, string) {
+	user, hostPort := "", jumpHost
+	if i := strings.LastIndex(jumpHost, "@"); i >= 0 {
+		user, hostPort = jumpHost[:i+1], jumpHost[i+1:]
+	}
+
+	host, port, err := net.SplitHostPort(hostPort)
+	if err != nil {
+		return jumpHost, ""
+	}
+
+	return user + host, port
+}
+
+// sshArgs returns the arguments to run command on host with ssh, going
+// through the jump host when one is configured.
+func sshArgs(jumpHost string, host string, command string) []string {
+	if jumpHost == "" {
+		return []string{host, command}
+	}
+
+	return []string{"-J", jumpHost, host, command}
+}
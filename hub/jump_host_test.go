@@ -0,0 +1,108 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/testutils/exectest"
+)
+
+// sshTunnel stands in for "ssh -W" by echoing everything sent through the
+// tunnel back to the caller.
+func sshTunnel() {
+	_, _ = io.Copy(os.Stdout, os.Stdin)
+}
+
+func init() {
+	exectest.RegisterMains(
+		sshTunnel,
+	)
+}
+
+func TestJumpHostDialer(t *testing.T) {
+	t.Run("tunnels the connection through the jump host", func(t *testing.T) {
+		execCmd := exectest.NewCommandContextWithVerifier(sshTunnel, func(name string, args ...string) {
+			if name != "ssh" {
+				t.Errorf("got %q want ssh", name)
+			}
+
+			expected := []string{"-W", "sdw1:6416", "gpadmin@bastion"}
+			if !reflect.DeepEqual(args, expected) {
+				t.Errorf("got %q want %q", args, expected)
+			}
+		})
+		hub.SetExecCommandContext(execCmd)
+		defer hub.ResetExecCommandContext()
+
+		dial := hub.JumpHostDialer("gpadmin@bastion")
+		conn, err := dial(context.Background(), "sdw1:6416")
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+		defer conn.Close()
+
+		expected := "hello agent"
+		if _, err := conn.Write([]byte(expected)); err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		buf := make([]byte, len(expected))
+		if _, err := io.ReadFull(conn, buf); err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		if string(buf) != expected {
+			t.Errorf("got %q want %q", buf, expected)
+		}
+	})
+	t.Run("passes the port of the jump host separately", func(t *testing.T) {
+		cases := []struct {
+			jumpHost string
+			expected []string
+		}{
+			{"gpadmin@bastion:2222", []string{"-W", "sdw1:6416", "-p", "2222", "gpadmin@bastion"}},
+			{"bastion:2222", []string{"-W", "sdw1:6416", "-p", "2222", "bastion"}},
+			{"gpadmin@[fe80::1]:2222", []string{"-W", "sdw1:6416", "-p", "2222", "gpadmin@fe80::1"}},
+		}
+
+		for _, c := range cases {
+			execCmd := exectest.NewCommandContextWithVerifier(sshTunnel, func(name string, args ...string) {
+				if !reflect.DeepEqual(args, c.expected) {
+					t.Errorf("got %q want %q", args, c.expected)
+				}
+			})
+			hub.SetExecCommandContext(execCmd)
+			defer hub.ResetExecCommandContext()
+
+			conn, err := hub.JumpHostDialer(c.jumpHost)(context.Background(), "sdw1:6416")
+			if err != nil {
+				t.Fatalf("unexpected error %+v", err)
+			}
+			conn.Close()
+		}
+	})
+
+	t.Run("does not start ssh when the dial is canceled", func(t *testing.T) {
+		execCmd := exectest.NewCommandContextWithVerifier(sshTunnel, func(name string, args ...string) {
+			t.Errorf("expected ssh to not be started")
+		})
+		hub.SetExecCommandContext(execCmd)
+		defer hub.ResetExecCommandContext()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := hub.JumpHostDialer("gpadmin@bastion")(ctx, "sdw1:6416")
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("got error %#v want %#v", err, context.Canceled)
+		}
+	})
+}
@@ -0,0 +1,259 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/blang/semver/v4"
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
+	"github.com/greenplum-db/gpupgrade/utils/errorlist"
+)
+
+// InventorySegments returns the data directories found one level below each
+// parent directory, along with the port, dbid, and primary_conninfo recorded
+// in their conf files. A directory without a postgresql.conf is not a data
+// directory and is skipped, as are missing parent directories.
+func InventorySegments(parentDirs []string, recoveryConfFile string) ([]*idl.InventorySegmentsReply_DataDirectory, error) {
+	var dataDirs []*idl.InventorySegmentsReply_DataDirectory
+
+	for _, parentDir := range parentDirs {
+		entries, err := os.ReadDir(parentDir)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+
+		if err != nil {
+			return nil, xerrors.Errorf("inventory %s: %w", parentDir, err)
+		}
+
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				continue
+			}
+
+			dataDir := filepath.Join(parentDir, entry.Name())
+			postgresqlConf, err := readConfSettings(filepath.Join(dataDir, "postgresql.conf"))
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+
+			if err != nil {
+				return nil, xerrors.Errorf("inventory %s: %w", dataDir, err)
+			}
+
+			internalAutoConf, err := optionalConfSettings(filepath.Join(dataDir, "internal.auto.conf"))
+			if err != nil {
+				return nil, xerrors.Errorf("inventory %s: %w", dataDir, err)
+			}
+
+			recoveryConf, err := optionalConfSettings(filepath.Join(dataDir, recoveryConfFile))
+			if err != nil {
+				return nil, xerrors.Errorf("inventory %s: %w", dataDir, err)
+			}
+
+			port, _ := strconv.Atoi(postgresqlConf["port"])
+			dbid, _ := strconv.Atoi(internalAutoConf["gp_dbid"])
+
+			dataDirs = append(dataDirs, &idl.InventorySegmentsReply_DataDirectory{
+				DataDir:         dataDir,
+				Port:            int32(port),
+				Dbid:            int32(dbid),
+				PrimaryConninfo: recoveryConf["primary_conninfo"],
+			})
+		}
+	}
+
+	return dataDirs, nil
+}
+
+// optionalConfSettings is readConfSettings treating a missing file as empty.
+func optionalConfSettings(path string) (map[string]string, error) {
+	settings, err := readConfSettings(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]string{}, nil
+	}
+
+	return settings, err
+}
+
+var conninfoHostPattern = regexp.MustCompile(`(^|[ \t])host[ \t]*=[ \t]*([^ \t]+)`)
+
+// ReconcileLateMirrors returns the target cluster including any mirrors added
+// after initialize, which are missing from the persisted state and would
+// otherwise not have their conf files updated. Each host is asked for the data
+// directories next to its known segments, and a mirror whose primary_conninfo
+// points at an intermediate primary without a target mirror is added with the
+// port and dbid found on disk. A warning is logged for each late mirror. It
+// errors rather than guessing when the late mirrors are ambiguous.
+func ReconcileLateMirrors(agentConns []*idl.Connection, version semver.Version, intermediate *greenplum.Cluster, target *greenplum.Cluster) (*greenplum.Cluster, error) {
+	var mutex sync.Mutex
+	var lateMirrors greenplum.SegConfigs
+
+	request := func(ctx context.Context, conn *idl.Connection) error {
+		known := make(map[string]bool)
+		parents := make(map[string]bool)
+		for _, cluster := range []*greenplum.Cluster{intermediate, target} {
+			cluster.ForEachSegment(func(seg *greenplum.SegConfig) bool {
+				return seg.IsOnHost(conn.Hostname)
+			}, func(seg *greenplum.SegConfig) bool {
+				known[seg.DataDir] = true
+				parents[filepath.Dir(seg.DataDir)] = true
+				return true
+			})
+		}
+
+		if len(parents) == 0 {
+			return nil
+		}
+
+		var parentDirs []string
+		for parent := range parents {
+			parentDirs = append(parentDirs, parent)
+		}
+		sort.Strings(parentDirs)
+
+		req := &idl.InventorySegmentsRequest{ParentDirs: parentDirs, RecoveryConfFile: recoveryConfFile(version)}
+		reply, err := conn.AgentClient.InventorySegments(ctx, req)
+		if err != nil {
+			return xerrors.Errorf("inventory segments on host %s: %w", conn.Hostname, err)
+		}
+
+		for _, dataDir := range reply.GetDataDirectories() {
+			if known[dataDir.GetDataDir()] {
+				continue
+			}
+
+			primary, ok := conninfoPrimary(intermediate, dataDir.GetPrimaryConninfo())
+			if !ok {
+				continue
+			}
+
+			if _, ok := target.Mirrors[primary.ContentID]; ok {
+				continue
+			}
+
+			if _, ok := target.Primaries[primary.ContentID]; !ok {
+				continue
+			}
+
+			mutex.Lock()
+			lateMirrors = append(lateMirrors, greenplum.SegConfig{
+				DbID:      int(dataDir.GetDbid()),
+				ContentID: primary.ContentID,
+				Port:      int(dataDir.GetPort()),
+				Hostname:  conn.Hostname,
+				Address:   conn.Hostname,
+				DataDir:   dataDir.GetDataDir(),
+				Role:      greenplum.MirrorRole,
+			})
+			mutex.Unlock()
+		}
+
+		return nil
+	}
+
+	if err := ExecuteRPCContext(context.Background(), agentConns, request); err != nil {
+		return nil, err
+	}
+
+	if len(lateMirrors) == 0 {
+		return target, nil
+	}
+
+	sort.Slice(lateMirrors, func(i, j int) bool {
+		if lateMirrors[i].ContentID != lateMirrors[j].ContentID {
+			return lateMirrors[i].ContentID < lateMirrors[j].ContentID
+		}
+		return lateMirrors[i].DataDir < lateMirrors[j].DataDir
+	})
+
+	if err := checkLateMirrors(lateMirrors); err != nil {
+		return nil, utils.NewNextActionErr(err, "Remove the data directories that are not mirrors of the cluster, or rerun gpupgrade finalize without --reconcile-late-mirrors.")
+	}
+
+	reconciled := *target
+	reconciled.Mirrors = make(greenplum.ContentToSegConfig)
+	for content, mirror := range target.Mirrors {
+		reconciled.Mirrors[content] = mirror
+	}
+
+	for _, mirror := range lateMirrors {
+		log.Printf("Warning: mirror %s with content %d on host %s was not in the recorded cluster and was likely added after initialize. Including it in the conf update.", mirror.DataDir, mirror.ContentID, mirror.Hostname)
+		reconciled.Mirrors[mirror.ContentID] = mirror
+	}
+
+	return &reconciled, nil
+}
+
+// checkLateMirrors errors for each content with more than one late mirror,
+// since which of them is the mirror cannot be told from the data directories
+// alone, and for each late mirror whose port or dbid was not found on disk.
+// The late mirrors are sorted by content.
+func checkLateMirrors(lateMirrors greenplum.SegConfigs) error {
+	var err error
+
+	for i := 0; i < len(lateMirrors); {
+		j := i + 1
+		for j < len(lateMirrors) && lateMirrors[j].ContentID == lateMirrors[i].ContentID {
+			j++
+		}
+
+		if j-i > 1 {
+			var dataDirs []string
+			for _, mirror := range lateMirrors[i:j] {
+				dataDirs = append(dataDirs, fmt.Sprintf("%s:%s", mirror.Hostname, mirror.DataDir))
+			}
+
+			err = errorlist.Append(err, xerrors.Errorf("cannot tell which of the data directories %s not in the recorded cluster is the mirror of content %d", strings.Join(dataDirs, ", "), lateMirrors[i].ContentID))
+		}
+
+		i = j
+	}
+
+	for _, mirror := range lateMirrors {
+		if mirror.Port == 0 || mirror.DbID == 0 {
+			err = errorlist.Append(err, xerrors.Errorf("cannot include mirror %s:%s of content %d since its port or gp_dbid was not found", mirror.Hostname, mirror.DataDir, mirror.ContentID))
+		}
+	}
+
+	return err
+}
+
+// conninfoPrimary returns the intermediate primary a primary_conninfo
+// connects to.
+func conninfoPrimary(intermediate *greenplum.Cluster, conninfo string) (greenplum.SegConfig, bool) {
+	port, ok := conninfoPort(conninfo)
+	if !ok {
+		return greenplum.SegConfig{}, false
+	}
+
+	match := conninfoHostPattern.FindStringSubmatch(conninfo)
+	if match == nil {
+		return greenplum.SegConfig{}, false
+	}
+	host := match[2]
+
+	for _, primary := range intermediate.Primaries {
+		if strconv.Itoa(primary.Port) == port && (primary.Hostname == host || primary.Address == host) {
+			return primary, true
+		}
+	}
+
+	return greenplum.SegConfig{}, false
+}
@@ -0,0 +1,199 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/blang/semver/v4"
+	"github.com/golang/mock/gomock"
+	"google.golang.org/grpc"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/idl/mock_idl"
+	"github.com/greenplum-db/gpupgrade/testutils"
+	"github.com/greenplum-db/gpupgrade/utils"
+)
+
+func TestReconcileLateMirrors(t *testing.T) {
+	parentDir := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, parentDir)
+
+	primaryDir := filepath.Join(parentDir, "seg1")
+	lateMirrorDir := filepath.Join(parentDir, "mirror2")
+
+	testutils.MustCreateDir(t, primaryDir)
+	testutils.MustWriteToFile(t, filepath.Join(primaryDir, "postgresql.conf"), "port=50434\n")
+	testutils.MustWriteToFile(t, filepath.Join(primaryDir, "internal.auto.conf"), "gp_dbid=2\n")
+
+	// a mirror of content 1 added after initialize, so it is only on disk
+	testutils.MustCreateDir(t, lateMirrorDir)
+	testutils.MustWriteToFile(t, filepath.Join(lateMirrorDir, "postgresql.conf"), "port=50435\n")
+	testutils.MustWriteToFile(t, filepath.Join(lateMirrorDir, "internal.auto.conf"), "gp_dbid=5\n")
+	testutils.MustWriteToFile(t, filepath.Join(lateMirrorDir, "postgresql.auto.conf"), "primary_conninfo = 'user=gpadmin host=sdw2 port=50435 application_name=gp_walreceiver'\n")
+
+	// a directory that is not a data directory
+	testutils.MustCreateDir(t, filepath.Join(parentDir, "logs"))
+
+	intermediate := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: "/data/qddir/seg.HqtFHX54y0o.-1", Port: 50432, Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: filepath.Join(parentDir, "seg.HqtFHX54y0o.1"), Port: 50434, Role: greenplum.PrimaryRole},
+		{DbID: 3, ContentID: 1, Hostname: "sdw2", DataDir: "/data/dbfast2/seg.HqtFHX54y0o.2", Port: 50435, Role: greenplum.PrimaryRole},
+	})
+
+	target := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: "/data/qddir/seg-1", Port: 15432, Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: primaryDir, Port: 25433, Role: greenplum.PrimaryRole},
+		{DbID: 3, ContentID: 1, Hostname: "sdw2", DataDir: "/data/dbfast2/seg2", Port: 25434, Role: greenplum.PrimaryRole},
+	})
+
+	version := semver.MustParse("7.0.0")
+
+	inventory := func(_ context.Context, req *idl.InventorySegmentsRequest, _ ...grpc.CallOption) (*idl.InventorySegmentsReply, error) {
+		dataDirs, err := hub.InventorySegments(req.GetParentDirs(), req.GetRecoveryConfFile())
+		return &idl.InventorySegmentsReply{DataDirectories: dataDirs}, err
+	}
+
+	t.Run("includes mirrors that exist on disk but not in the recorded cluster", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw1.EXPECT().InventorySegments(gomock.Any(), &idl.InventorySegmentsRequest{
+			ParentDirs:       []string{parentDir},
+			RecoveryConfFile: "postgresql.auto.conf",
+		}).DoAndReturn(inventory)
+
+		sdw2 := mock_idl.NewMockAgentClient(ctrl)
+		sdw2.EXPECT().InventorySegments(gomock.Any(), gomock.Any()).Return(&idl.InventorySegmentsReply{}, nil)
+
+		agentConns := []*idl.Connection{
+			{AgentClient: sdw1, Hostname: "sdw1"},
+			{AgentClient: sdw2, Hostname: "sdw2"},
+		}
+
+		reconciled, err := hub.ReconcileLateMirrors(agentConns, version, intermediate, target)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		expected := greenplum.SegConfig{DbID: 5, ContentID: 1, Port: 50435, Hostname: "sdw1", Address: "sdw1", DataDir: lateMirrorDir, Role: greenplum.MirrorRole}
+		if !reflect.DeepEqual(reconciled.Mirrors[1], expected) {
+			t.Errorf("got mirror %+v want %+v", reconciled.Mirrors[1], expected)
+		}
+
+		if len(target.Mirrors) != 0 {
+			t.Errorf("expected the recorded cluster to be unchanged, got mirrors %+v", target.Mirrors)
+		}
+
+		plan, err := hub.PlanConfFiles(version, intermediate, reconciled)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		found := false
+		for _, edit := range plan {
+			if edit.Option.GetPath() == filepath.Join(lateMirrorDir, "postgresql.auto.conf") && edit.NewValue == "port=25434" {
+				found = true
+			}
+		}
+
+		if !found {
+			t.Errorf("expected the plan to rewrite the primary_conninfo of the late mirror, got %+v", plan)
+		}
+	})
+
+	t.Run("returns the recorded cluster when there are no late mirrors", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw1.EXPECT().InventorySegments(gomock.Any(), gomock.Any()).Return(&idl.InventorySegmentsReply{
+			DataDirectories: []*idl.InventorySegmentsReply_DataDirectory{
+				{DataDir: primaryDir, Port: 50434, Dbid: 2},
+			},
+		}, nil)
+
+		agentConns := []*idl.Connection{{AgentClient: sdw1, Hostname: "sdw1"}}
+
+		reconciled, err := hub.ReconcileLateMirrors(agentConns, version, intermediate, target)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		if reconciled != target {
+			t.Errorf("got %+v want the recorded cluster", reconciled)
+		}
+	})
+
+	t.Run("errors when the inventory fails", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		expected := errors.New("permission denied")
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw1.EXPECT().InventorySegments(gomock.Any(), gomock.Any()).Return(nil, expected)
+
+		agentConns := []*idl.Connection{{AgentClient: sdw1, Hostname: "sdw1"}}
+
+		_, err := hub.ReconcileLateMirrors(agentConns, version, intermediate, target)
+		if !errors.Is(err, expected) {
+			t.Errorf("got error %#v want %#v", err, expected)
+		}
+	})
+	t.Run("errors when a content has more than one late mirror", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		conninfo := "user=gpadmin host=sdw2 port=50435 application_name=gp_walreceiver"
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw1.EXPECT().InventorySegments(gomock.Any(), gomock.Any()).Return(&idl.InventorySegmentsReply{
+			DataDirectories: []*idl.InventorySegmentsReply_DataDirectory{
+				{DataDir: lateMirrorDir, Port: 50435, Dbid: 5, PrimaryConninfo: conninfo},
+				{DataDir: filepath.Join(parentDir, "mirror2.old"), Port: 50436, Dbid: 6, PrimaryConninfo: conninfo},
+			},
+		}, nil)
+
+		agentConns := []*idl.Connection{{AgentClient: sdw1, Hostname: "sdw1"}}
+
+		_, err := hub.ReconcileLateMirrors(agentConns, version, intermediate, target)
+		expected := fmt.Sprintf("cannot tell which of the data directories sdw1:%s, sdw1:%s not in the recorded cluster is the mirror of content 1", lateMirrorDir, filepath.Join(parentDir, "mirror2.old"))
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("expected error %v to contain %q", err, expected)
+		}
+
+		var nextActionErr utils.NextActionErr
+		if !errors.As(err, &nextActionErr) {
+			t.Errorf("expected a next action in error %#v", err)
+		}
+	})
+
+	t.Run("errors when the port or dbid of a late mirror is not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw1.EXPECT().InventorySegments(gomock.Any(), gomock.Any()).Return(&idl.InventorySegmentsReply{
+			DataDirectories: []*idl.InventorySegmentsReply_DataDirectory{
+				{DataDir: lateMirrorDir, Port: 50435, PrimaryConninfo: "user=gpadmin host=sdw2 port=50435"},
+			},
+		}, nil)
+
+		agentConns := []*idl.Connection{{AgentClient: sdw1, Hostname: "sdw1"}}
+
+		_, err := hub.ReconcileLateMirrors(agentConns, version, intermediate, target)
+		expected := "since its port or gp_dbid was not found"
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("expected error %v to contain %q", err, expected)
+		}
+	})
+}
@@ -0,0 +1,160 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"sync"
+
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
+)
+
+// confBackupPattern matches the name of a backup, capturing the name of the
+// conf file it backs up and the version of a backup taken after the first.
+var confBackupPattern = regexp.MustCompile(`^(.+)` + regexp.QuoteMeta(BackupSuffix) + `(?:\.([0-9]+))?$`)
+
+// ListConfBackups returns the conf backups written next to the conf files in
+// each directory. Only the entries of each directory are listed, rather than
+// walking it, and missing directories are skipped.
+func ListConfBackups(dirs []string) ([]*idl.ListConfBackupsReply_Backup, error) {
+	var backups []*idl.ListConfBackupsReply_Backup
+
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+
+		if err != nil {
+			return nil, xerrors.Errorf("list conf backups in %s: %w", dir, err)
+		}
+
+		for _, entry := range entries {
+			if !entry.Type().IsRegular() {
+				continue
+			}
+
+			path := filepath.Join(dir, entry.Name())
+			match := confBackupPattern.FindStringSubmatch(entry.Name())
+			if match == nil {
+				continue
+			}
+
+			info, err := entry.Info()
+			if err != nil {
+				return nil, xerrors.Errorf("list conf backups in %s: %w", dir, err)
+			}
+
+			contents, err := utils.System.ReadFile(path)
+			if err != nil {
+				return nil, xerrors.Errorf("list conf backups in %s: %w", dir, err)
+			}
+
+			backups = append(backups, &idl.ListConfBackupsReply_Backup{
+				Path:         path,
+				OriginalPath: filepath.Join(dir, match[1]),
+				Timestamp:    info.ModTime().Unix(),
+				Checksum:     checksum(contents),
+			})
+		}
+	}
+
+	return backups, nil
+}
+
+func checksum(contents []byte) string {
+	sum := sha256.Sum256(contents)
+	return hex.EncodeToString(sum[:])
+}
+
+// FindConfBackups lists the conf backups of each host in the data directories
+// of the clusters on the host. It does not rely on
+// the hub's own record of the backups so the conf files can be recovered
+// when that is lost.
+func FindConfBackups(agentConns []*idl.Connection, clusters ...*greenplum.Cluster) (map[string][]*idl.ListConfBackupsReply_Backup, error) {
+	var mutex sync.Mutex
+	backups := make(map[string][]*idl.ListConfBackupsReply_Backup)
+
+	request := func(ctx context.Context, conn *idl.Connection) error {
+		var dirs []string
+		for _, cluster := range clusters {
+			cluster.ForEachSegment(func(seg *greenplum.SegConfig) bool {
+				return seg.IsOnHost(conn.Hostname)
+			}, func(seg *greenplum.SegConfig) bool {
+				dirs = append(dirs, seg.DataDir)
+				return true
+			})
+		}
+
+		reply, err := conn.AgentClient.ListConfBackups(ctx, &idl.ListConfBackupsRequest{Dirs: dirs})
+		if err != nil {
+			return xerrors.Errorf("list conf backups on host %s: %w", conn.Hostname, err)
+		}
+
+		mutex.Lock()
+		backups[conn.Hostname] = reply.GetBackups()
+		mutex.Unlock()
+
+		return nil
+	}
+
+	if err := ExecuteRPCContext(context.Background(), agentConns, request); err != nil {
+		return nil, err
+	}
+
+	return backups, nil
+}
+
+// ConfRestorePlan returns the backup holding the original contents of each
+// conf file, sorted by the conf file. A file backed up more than once is
+// restored from its first backup.
+func ConfRestorePlan(backups []*idl.ListConfBackupsReply_Backup) []*idl.ListConfBackupsReply_Backup {
+	sorted := append([]*idl.ListConfBackupsReply_Backup(nil), backups...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].GetOriginalPath() != sorted[j].GetOriginalPath() {
+			return sorted[i].GetOriginalPath() < sorted[j].GetOriginalPath()
+		}
+
+		if backupVersion(sorted[i]) != backupVersion(sorted[j]) {
+			return backupVersion(sorted[i]) < backupVersion(sorted[j])
+		}
+
+		return sorted[i].GetTimestamp() < sorted[j].GetTimestamp()
+	})
+
+	var plan []*idl.ListConfBackupsReply_Backup
+	for _, backup := range sorted {
+		if len(plan) > 0 && plan[len(plan)-1].GetOriginalPath() == backup.GetOriginalPath() {
+			continue
+		}
+
+		plan = append(plan, backup)
+	}
+
+	return plan
+}
+
+// backupVersion returns the version of a backup, with the first backup of a
+// conf file being version zero.
+func backupVersion(backup *idl.ListConfBackupsReply_Backup) int {
+	match := confBackupPattern.FindStringSubmatch(filepath.Base(backup.GetPath()))
+	if match == nil || match[2] == "" {
+		return 0
+	}
+
+	version, _ := strconv.Atoi(match[2])
+	return version
+}
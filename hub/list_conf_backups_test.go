@@ -0,0 +1,144 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"google.golang.org/grpc"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/idl/mock_idl"
+	"github.com/greenplum-db/gpupgrade/testutils"
+)
+
+func TestListConfBackups(t *testing.T) {
+	dataDir := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, dataDir)
+
+	postgresqlConf := filepath.Join(dataDir, "postgresql.conf")
+	testutils.MustWriteToFile(t, postgresqlConf, "port=5000\n")
+	portEdit := func(oldPort, newPort string) []*idl.UpdateFileConfOptions {
+		return []*idl.UpdateFileConfOptions{{Path: postgresqlConf, Pattern: `(^port=)` + oldPort, Replacement: `\1` + newPort}}
+	}
+
+	// local backups, the second being versioned
+	for _, ports := range [][2]string{{"5000", "6000"}, {"6000", "7000"}} {
+		if err := hub.UpdateConfigurationFile(context.Background(), portEdit(ports[0], ports[1])); err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+	}
+
+	// not a backup
+	testutils.MustWriteToFile(t, filepath.Join(dataDir, "pg_hba.conf"), "")
+	testutils.MustCreateDir(t, filepath.Join(dataDir, "base.bak"))
+
+	t.Run("lists the backups", func(t *testing.T) {
+		backups, err := hub.ListConfBackups([]string{dataDir, filepath.Join(dataDir, "does-not-exist")})
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		// the checksums are the sha256 of port=5000 and 6000
+		expected := []*idl.ListConfBackupsReply_Backup{
+			{Path: postgresqlConf + ".bak", OriginalPath: postgresqlConf, Checksum: "401a3fab0abb88fc67ae8d34b58811bcea56ebaf003e425d5d513a364cbf813a"},
+			{Path: postgresqlConf + ".bak.1", OriginalPath: postgresqlConf, Checksum: "1ffa4cf3098607e0e229a259130426eaac238c0ec13c0efda66d208aa94d8414"},
+		}
+
+		// the modification times of the backups are not fixed
+		for _, backup := range backups {
+			if backup.GetTimestamp() == 0 {
+				t.Errorf("expected a timestamp for %s", backup.GetPath())
+			}
+			backup.Timestamp = 0
+		}
+
+		if !reflect.DeepEqual(backups, expected) {
+			t.Errorf("got %v want %v", backups, expected)
+		}
+	})
+
+	t.Run("restores each conf file from its first backup", func(t *testing.T) {
+		backups := []*idl.ListConfBackupsReply_Backup{
+			{Path: "/data/seg1/postgresql.conf.bak.1", OriginalPath: "/data/seg1/postgresql.conf", Timestamp: 1},
+			{Path: "/data/seg1/recovery.conf.bak", OriginalPath: "/data/seg1/recovery.conf", Timestamp: 3},
+			{Path: "/data/seg1/postgresql.conf.bak", OriginalPath: "/data/seg1/postgresql.conf", Timestamp: 2},
+			{Path: "/data/seg1/postgresql.conf.bak.12", OriginalPath: "/data/seg1/postgresql.conf", Timestamp: 0},
+		}
+
+		plan := hub.ConfRestorePlan(backups)
+
+		expected := []*idl.ListConfBackupsReply_Backup{backups[2], backups[1]}
+		if !reflect.DeepEqual(plan, expected) {
+			t.Errorf("got %v want %v", plan, expected)
+		}
+	})
+}
+
+func TestFindConfBackups(t *testing.T) {
+	source := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: "/data/qddir/seg-1", Port: 15432, Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg1", Port: 25433, Role: greenplum.PrimaryRole},
+		{DbID: 3, ContentID: 0, Hostname: "sdw2", DataDir: "/data/dbfast_mirror1/seg1", Port: 25434, Role: greenplum.MirrorRole},
+	})
+
+	t.Run("lists the backups of each host", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		backup := &idl.ListConfBackupsReply_Backup{Path: "/data/dbfast1/seg1/postgresql.conf.bak", OriginalPath: "/data/dbfast1/seg1/postgresql.conf"}
+
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw1.EXPECT().ListConfBackups(gomock.Any(), &idl.ListConfBackupsRequest{
+			Dirs: []string{"/data/dbfast1/seg1"},
+		}).Return(&idl.ListConfBackupsReply{Backups: []*idl.ListConfBackupsReply_Backup{backup}}, nil)
+
+		sdw2 := mock_idl.NewMockAgentClient(ctrl)
+		sdw2.EXPECT().ListConfBackups(gomock.Any(), &idl.ListConfBackupsRequest{
+			Dirs: []string{"/data/dbfast_mirror1/seg1"},
+		}).Return(&idl.ListConfBackupsReply{}, nil)
+
+		agentConns := []*idl.Connection{
+			{AgentClient: sdw1, Hostname: "sdw1"},
+			{AgentClient: sdw2, Hostname: "sdw2"},
+		}
+
+		backups, err := hub.FindConfBackups(agentConns, source)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		expected := map[string][]*idl.ListConfBackupsReply_Backup{
+			"sdw1": {backup},
+			"sdw2": nil,
+		}
+		if !reflect.DeepEqual(backups, expected) {
+			t.Errorf("got %v want %v", backups, expected)
+		}
+	})
+
+	t.Run("errors when listing fails", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		expected := errors.New("permission denied")
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw1.EXPECT().ListConfBackups(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(context.Context, *idl.ListConfBackupsRequest, ...grpc.CallOption) (*idl.ListConfBackupsReply, error) {
+				return nil, expected
+			})
+
+		_, err := hub.FindConfBackups([]*idl.Connection{{AgentClient: sdw1, Hostname: "sdw1"}}, source)
+		if !errors.Is(err, expected) {
+			t.Errorf("got error %#v want %#v", err, expected)
+		}
+	})
+}
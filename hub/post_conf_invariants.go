@@ -0,0 +1,106 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/blang/semver/v4"
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils/errorlist"
+)
+
+// CheckPostConfInvariants reads back the updated conf files and checks the
+// cluster level invariants needed to start the target cluster: segment ports
+// are unique per host, dbids are unique across the cluster, and the
+// primary_conninfo port of each mirror is the port of its primary. All
+// violations are returned.
+func CheckPostConfInvariants(agentConns []*idl.Connection, version semver.Version, target *greenplum.Cluster) error {
+	values, err := VerifyConfFiles(agentConns, version, target)
+	if err != nil {
+		return err
+	}
+
+	// actual values keyed by hostname and data directory, and then GUC name
+	actual := make(map[string]map[string]string)
+	for _, value := range values {
+		if !value.Found {
+			err = errorlist.Append(err, xerrors.Errorf("%s is not set in %s on host %s", value.Name, value.Path, value.Hostname))
+			continue
+		}
+
+		key := segmentKey(value.Hostname, filepath.Dir(value.Path))
+		if actual[key] == nil {
+			actual[key] = make(map[string]string)
+		}
+		actual[key][value.Name] = value.Actual
+	}
+
+	segments := target.SelectSegments(func(*greenplum.SegConfig) bool { return true })
+	sort.Slice(segments, func(i, j int) bool { return segments[i].DbID < segments[j].DbID })
+
+	var ports []string
+	portUsers := make(map[string][]string)
+	var dbids []string
+	dbidUsers := make(map[string][]string)
+
+	for _, seg := range segments {
+		key := segmentKey(seg.Hostname, seg.DataDir)
+
+		if port, ok := actual[key]["port"]; ok {
+			hostPort := seg.Hostname + ":" + port
+			if len(portUsers[hostPort]) == 0 {
+				ports = append(ports, hostPort)
+			}
+			portUsers[hostPort] = append(portUsers[hostPort], seg.DataDir)
+		}
+
+		if dbid, ok := actual[key]["gp_dbid"]; ok {
+			if len(dbidUsers[dbid]) == 0 {
+				dbids = append(dbids, dbid)
+			}
+			dbidUsers[dbid] = append(dbidUsers[dbid], key)
+		}
+
+		if !seg.IsMirror() && !seg.IsStandby() {
+			continue
+		}
+
+		conninfoPort, ok := actual[key]["primary_conninfo"]
+		if !ok {
+			continue
+		}
+
+		primary := target.Primaries[seg.ContentID]
+		primaryPort, ok := actual[segmentKey(primary.Hostname, primary.DataDir)]["port"]
+		if ok && conninfoPort != primaryPort {
+			err = errorlist.Append(err, xerrors.Errorf("primary_conninfo of %s on host %s has port %s but its primary %s on host %s has port %s",
+				seg.DataDir, seg.Hostname, conninfoPort, primary.DataDir, primary.Hostname, primaryPort))
+		}
+	}
+
+	for _, hostPort := range ports {
+		if dataDirs := portUsers[hostPort]; len(dataDirs) > 1 {
+			host, port, _ := strings.Cut(hostPort, ":")
+			err = errorlist.Append(err, xerrors.Errorf("port %s is used by more than one segment on host %s: %s", port, host, strings.Join(dataDirs, ", ")))
+		}
+	}
+
+	for _, dbid := range dbids {
+		if segs := dbidUsers[dbid]; len(segs) > 1 {
+			err = errorlist.Append(err, xerrors.Errorf("gp_dbid %s is used by more than one segment: %s", dbid, strings.Join(segs, ", ")))
+		}
+	}
+
+	return err
+}
+
+func segmentKey(hostname string, dataDir string) string {
+	return hostname + ":" + dataDir
+}
@@ -0,0 +1,143 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/blang/semver/v4"
+	"github.com/golang/mock/gomock"
+	"google.golang.org/grpc"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/idl/mock_idl"
+	"github.com/greenplum-db/gpupgrade/testutils"
+	"github.com/greenplum-db/gpupgrade/utils/errorlist"
+)
+
+func TestCheckPostConfInvariants(t *testing.T) {
+	coordinatorDir := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, coordinatorDir)
+
+	testutils.MustWriteToFile(t, filepath.Join(coordinatorDir, "postgresql.conf"), "port=15432\n")
+	testutils.MustWriteToFile(t, filepath.Join(coordinatorDir, "internal.auto.conf"), "gp_dbid=1\n")
+
+	target := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: coordinatorDir, Port: 15432, Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg1", Port: 25432, Role: greenplum.PrimaryRole},
+		{DbID: 3, ContentID: 1, Hostname: "sdw1", DataDir: "/data/dbfast2/seg2", Port: 25433, Role: greenplum.PrimaryRole},
+		{DbID: 4, ContentID: 0, Hostname: "sdw2", DataDir: "/data/dbfast_mirror1/seg1", Port: 25434, Role: greenplum.MirrorRole},
+	})
+
+	// agent returns the values of conf files on its host
+	agent := func(ctrl *gomock.Controller, values map[string]string) *mock_idl.MockAgentClient {
+		client := mock_idl.NewMockAgentClient(ctrl)
+		client.EXPECT().ReadConfiguration(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, req *idl.ReadConfigurationRequest, _ ...grpc.CallOption) (*idl.ReadConfigurationReply, error) {
+				reply := &idl.ReadConfigurationReply{}
+				for _, file := range req.GetFiles() {
+					for _, name := range file.GetNames() {
+						value, found := values[filepath.Join(file.GetPath(), name)]
+						reply.Values = append(reply.Values, &idl.ReadConfigurationReply_Value{Path: file.GetPath(), Name: name, Value: value, Found: found})
+					}
+				}
+				return reply, nil
+			})
+		return client
+	}
+
+	t.Run("succeeds when the invariants hold", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		agentConns := []*idl.Connection{
+			{Hostname: "sdw1", AgentClient: agent(ctrl, map[string]string{
+				"/data/dbfast1/seg1/postgresql.conf/port":       "25432",
+				"/data/dbfast1/seg1/internal.auto.conf/gp_dbid": "2",
+				"/data/dbfast2/seg2/postgresql.conf/port":       "25433",
+				"/data/dbfast2/seg2/internal.auto.conf/gp_dbid": "3",
+			})},
+			{Hostname: "sdw2", AgentClient: agent(ctrl, map[string]string{
+				"/data/dbfast_mirror1/seg1/postgresql.conf/port":                  "25434",
+				"/data/dbfast_mirror1/seg1/internal.auto.conf/gp_dbid":            "4",
+				"/data/dbfast_mirror1/seg1/postgresql.auto.conf/primary_conninfo": "host=sdw1 port=25432",
+			})},
+		}
+
+		err := hub.CheckPostConfInvariants(agentConns, semver.MustParse("7.0.0"), target)
+		if err != nil {
+			t.Errorf("unexpected error %+v", err)
+		}
+	})
+
+	t.Run("reports every violation", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		agentConns := []*idl.Connection{
+			{Hostname: "sdw1", AgentClient: agent(ctrl, map[string]string{
+				"/data/dbfast1/seg1/postgresql.conf/port":       "25432",
+				"/data/dbfast1/seg1/internal.auto.conf/gp_dbid": "2",
+				"/data/dbfast2/seg2/postgresql.conf/port":       "25432",
+				"/data/dbfast2/seg2/internal.auto.conf/gp_dbid": "3",
+			})},
+			{Hostname: "sdw2", AgentClient: agent(ctrl, map[string]string{
+				"/data/dbfast_mirror1/seg1/postgresql.conf/port":                  "25434",
+				"/data/dbfast_mirror1/seg1/internal.auto.conf/gp_dbid":            "2",
+				"/data/dbfast_mirror1/seg1/postgresql.auto.conf/primary_conninfo": "host=sdw1 port=50432",
+			})},
+		}
+
+		err := hub.CheckPostConfInvariants(agentConns, semver.MustParse("7.0.0"), target)
+		var errs errorlist.Errors
+		if !errors.As(err, &errs) {
+			t.Fatalf("got error %#v want type %T", err, errs)
+		}
+
+		expected := []string{
+			"primary_conninfo of /data/dbfast_mirror1/seg1 on host sdw2 has port 50432 but its primary /data/dbfast1/seg1 on host sdw1 has port 25432",
+			"port 25432 is used by more than one segment on host sdw1: /data/dbfast1/seg1, /data/dbfast2/seg2",
+			"gp_dbid 2 is used by more than one segment: sdw1:/data/dbfast1/seg1, sdw2:/data/dbfast_mirror1/seg1",
+		}
+
+		if len(errs) != len(expected) {
+			t.Fatalf("got %d errors want %d: %v", len(errs), len(expected), errs)
+		}
+
+		for i, e := range errs {
+			if e.Error() != expected[i] {
+				t.Errorf("got error %q want %q", e.Error(), expected[i])
+			}
+		}
+	})
+
+	t.Run("reports values that are not set", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		agentConns := []*idl.Connection{
+			{Hostname: "sdw1", AgentClient: agent(ctrl, map[string]string{
+				"/data/dbfast1/seg1/postgresql.conf/port":       "25432",
+				"/data/dbfast1/seg1/internal.auto.conf/gp_dbid": "2",
+				"/data/dbfast2/seg2/postgresql.conf/port":       "25433",
+			})},
+			{Hostname: "sdw2", AgentClient: agent(ctrl, map[string]string{
+				"/data/dbfast_mirror1/seg1/postgresql.conf/port":                  "25434",
+				"/data/dbfast_mirror1/seg1/internal.auto.conf/gp_dbid":            "4",
+				"/data/dbfast_mirror1/seg1/postgresql.auto.conf/primary_conninfo": "host=sdw1 port=25432",
+			})},
+		}
+
+		err := hub.CheckPostConfInvariants(agentConns, semver.MustParse("7.0.0"), target)
+		expected := "gp_dbid is not set in /data/dbfast2/seg2/internal.auto.conf on host sdw1"
+		if err == nil || err.Error() != expected {
+			t.Errorf("got error %v want %q", err, expected)
+		}
+	})
+}
@@ -0,0 +1,211 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils/errorlist"
+)
+
+// confLinePattern matches a GUC setting of the form "name = value" where the
+// equals sign is optional. The value is either single quoted, or runs until
+// whitespace or a comment.
+var confLinePattern = regexp.MustCompile(`^[ \t]*([A-Za-z_][A-Za-z0-9_.]*)[ \t]*=?[ \t]*('(?:[^']|'')*'|[^ \t#]*)`)
+
+// HandleReadConfiguration serves a ReadConfiguration request on the local
+// host, for both the agents and the coordinator. Errors are prefixed with the
+// hostname.
+func HandleReadConfiguration(hostname string, req *idl.ReadConfigurationRequest) (*idl.ReadConfigurationReply, error) {
+	values, err := ReadConfigurationFile(req.GetFiles())
+	if err != nil {
+		return &idl.ReadConfigurationReply{}, fmt.Errorf("on host %q: %w", hostname, err)
+	}
+
+	matches, err := SearchConfigurationFile(req.GetFiles())
+	if err != nil {
+		return &idl.ReadConfigurationReply{}, fmt.Errorf("on host %q: %w", hostname, err)
+	}
+
+	return &idl.ReadConfigurationReply{Values: values, Matches: matches}, nil
+}
+
+// ReadConfigurationFile returns the current value of each requested GUC name
+// in each file. Each file is read once regardless of the number of names
+// requested. As with postgres, the last setting of a GUC in a file wins. The
+// names of an absent file marked skipIfAbsent are not found.
+func ReadConfigurationFile(files []*idl.ReadConfigurationRequest_File) ([]*idl.ReadConfigurationReply_Value, error) {
+	var wg sync.WaitGroup
+	values := make([][]*idl.ReadConfigurationReply_Value, len(files))
+	errs := make(chan error, len(files))
+
+	for i, file := range files {
+		wg.Add(1)
+		go func(i int, file *idl.ReadConfigurationRequest_File) {
+			defer wg.Done()
+
+			settings, err := readConfSettings(file.GetPath())
+			if errors.Is(err, os.ErrNotExist) && file.GetSkipIfAbsent() {
+				settings, err = nil, nil
+			}
+
+			if err != nil {
+				errs <- xerrors.Errorf("read %s: %w", file.GetPath(), err)
+				return
+			}
+
+			for _, name := range file.GetNames() {
+				value, found := settings[strings.ToLower(name)]
+				values[i] = append(values[i], &idl.ReadConfigurationReply_Value{
+					Path:  file.GetPath(),
+					Name:  name,
+					Value: value,
+					Found: found,
+				})
+			}
+		}(i, file)
+	}
+
+	wg.Wait()
+	close(errs)
+
+	var err error
+	for e := range errs {
+		err = errorlist.Append(err, e)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*idl.ReadConfigurationReply_Value
+	for _, fileValues := range values {
+		result = append(result, fileValues...)
+	}
+
+	return result, nil
+}
+
+// SearchConfigurationFile returns the lines of each file containing any of
+// its searches. An absent file marked skipIfAbsent has no matches.
+func SearchConfigurationFile(files []*idl.ReadConfigurationRequest_File) ([]*idl.ReadConfigurationReply_Match, error) {
+	var matches []*idl.ReadConfigurationReply_Match
+	var err error
+
+	for _, file := range files {
+		if len(file.GetSearches()) == 0 {
+			continue
+		}
+
+		contents, rErr := os.ReadFile(file.GetPath())
+		if errors.Is(rErr, os.ErrNotExist) && file.GetSkipIfAbsent() {
+			continue
+		}
+
+		if rErr != nil {
+			err = errorlist.Append(err, xerrors.Errorf("search %s: %w", file.GetPath(), rErr))
+			continue
+		}
+
+		scanner := bufio.NewScanner(strings.NewReader(string(contents)))
+		for lineNumber := 1; scanner.Scan(); lineNumber++ {
+			for _, search := range file.GetSearches() {
+				if strings.Contains(scanner.Text(), search) {
+					matches = append(matches, &idl.ReadConfigurationReply_Match{
+						Path:       file.GetPath(),
+						LineNumber: int32(lineNumber),
+						Line:       scanner.Text(),
+						Search:     search,
+					})
+				}
+			}
+		}
+
+		if sErr := scanner.Err(); sErr != nil {
+			err = errorlist.Append(err, xerrors.Errorf("search %s: %w", file.GetPath(), sErr))
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return matches, nil
+}
+
+// ReadEditedGUCs returns the current value of the GUC of each option that
+// names one, reading each edited file once.
+func ReadEditedGUCs(opts []*idl.UpdateFileConfOptions) ([]*idl.ReadConfigurationReply_Value, error) {
+	var files []*idl.ReadConfigurationRequest_File
+	fileIndex := make(map[string]*idl.ReadConfigurationRequest_File)
+	seen := make(map[string]bool)
+
+	for _, opt := range opts {
+		if opt.GetGuc() == "" || seen[opt.GetPath()+"/"+opt.GetGuc()] || optionalConfFileAbsent(opt) {
+			continue
+		}
+		seen[opt.GetPath()+"/"+opt.GetGuc()] = true
+
+		file, ok := fileIndex[opt.GetPath()]
+		if !ok {
+			file = &idl.ReadConfigurationRequest_File{Path: opt.GetPath()}
+			fileIndex[opt.GetPath()] = file
+			files = append(files, file)
+		}
+
+		file.Names = append(file.Names, opt.GetGuc())
+	}
+
+	if len(files) == 0 {
+		return nil, nil
+	}
+
+	return ReadConfigurationFile(files)
+}
+
+// readConfSettings parses a conf file into a map of lowercased GUC names to
+// their unquoted values.
+func readConfSettings(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return parseConfSettings(file)
+}
+
+// parseConfSettings parses conf file contents as readConfSettings does.
+func parseConfSettings(r io.Reader) (map[string]string, error) {
+	settings := make(map[string]string)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		match := confLinePattern.FindStringSubmatch(scanner.Text())
+		if match == nil {
+			continue
+		}
+
+		settings[strings.ToLower(match[1])] = unquoteConfValue(match[2])
+	}
+
+	return settings, scanner.Err()
+}
+
+func unquoteConfValue(value string) string {
+	if len(value) >= 2 && strings.HasPrefix(value, "'") && strings.HasSuffix(value, "'") {
+		return strings.ReplaceAll(value[1:len(value)-1], "''", "'")
+	}
+
+	return value
+}
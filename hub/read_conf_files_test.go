@@ -0,0 +1,144 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/testutils"
+)
+
+func TestReadConfigurationFile(t *testing.T) {
+	t.Run("reads the last setting of each requested name", func(t *testing.T) {
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		postgresqlConf := filepath.Join(dir, "postgresql.conf")
+		testutils.MustWriteToFile(t, postgresqlConf, `
+#port = 1
+port=5000
+  PORT = 6000 # moved
+listen_addresses = '*'
+`)
+
+		autoConf := filepath.Join(dir, "postgresql.auto.conf")
+		testutils.MustWriteToFile(t, autoConf, `primary_conninfo = 'user=gpadmin password=''pass'' host=sdw1 port=7000'
+`)
+
+		values, err := hub.ReadConfigurationFile([]*idl.ReadConfigurationRequest_File{
+			{Path: postgresqlConf, Names: []string{"port", "listen_addresses", "gp_dbid"}},
+			{Path: autoConf, Names: []string{"primary_conninfo"}},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		expected := []*idl.ReadConfigurationReply_Value{
+			{Path: postgresqlConf, Name: "port", Value: "6000", Found: true},
+			{Path: postgresqlConf, Name: "listen_addresses", Value: "*", Found: true},
+			{Path: postgresqlConf, Name: "gp_dbid", Value: "", Found: false},
+			{Path: autoConf, Name: "primary_conninfo", Value: "user=gpadmin password='pass' host=sdw1 port=7000", Found: true},
+		}
+
+		if !reflect.DeepEqual(values, expected) {
+			t.Errorf("got %v want %v", values, expected)
+		}
+	})
+
+	t.Run("errors when a file cannot be read", func(t *testing.T) {
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		_, err := hub.ReadConfigurationFile([]*idl.ReadConfigurationRequest_File{
+			{Path: filepath.Join(dir, "postgresql.conf"), Names: []string{"port"}},
+		})
+		if !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("got error %#v want %#v", err, os.ErrNotExist)
+		}
+	})
+
+	t.Run("does not find the names of an absent file marked skipIfAbsent", func(t *testing.T) {
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		path := filepath.Join(dir, "gpperfmon.conf")
+		values, err := hub.ReadConfigurationFile([]*idl.ReadConfigurationRequest_File{
+			{Path: path, Names: []string{"log_location"}, SkipIfAbsent: true},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		expected := []*idl.ReadConfigurationReply_Value{{Path: path, Name: "log_location"}}
+		if !reflect.DeepEqual(values, expected) {
+			t.Errorf("got %v want %v", values, expected)
+		}
+	})
+}
+
+func TestReadEditedGUCs(t *testing.T) {
+	dir := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, dir)
+
+	postgresqlConf := filepath.Join(dir, "postgresql.conf")
+	testutils.MustWriteToFile(t, postgresqlConf, "port=25432\n")
+
+	values, err := hub.ReadEditedGUCs([]*idl.UpdateFileConfOptions{
+		{Path: postgresqlConf, Guc: "port"},
+		{Path: postgresqlConf, Guc: "port"},
+		{Path: filepath.Join(dir, "gpperfmon.conf")},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error %+v", err)
+	}
+
+	expected := []*idl.ReadConfigurationReply_Value{
+		{Path: postgresqlConf, Name: "port", Value: "25432", Found: true},
+	}
+
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("got %v want %v", values, expected)
+	}
+}
+
+func TestSearchConfigurationFile(t *testing.T) {
+	dir := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, dir)
+
+	postgresqlConf := filepath.Join(dir, "postgresql.conf")
+	testutils.MustWriteToFile(t, postgresqlConf, "port=25432\nlog_directory = '/data/seg.HqtFHX54y0o.1/log'\n")
+
+	t.Run("returns the lines containing a search", func(t *testing.T) {
+		matches, err := hub.SearchConfigurationFile([]*idl.ReadConfigurationRequest_File{
+			{Path: postgresqlConf, Searches: []string{"/data/seg.HqtFHX54y0o.1"}},
+			{Path: filepath.Join(dir, "not-searched.conf"), Names: []string{"port"}},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		expected := []*idl.ReadConfigurationReply_Match{
+			{Path: postgresqlConf, LineNumber: 2, Line: "log_directory = '/data/seg.HqtFHX54y0o.1/log'", Search: "/data/seg.HqtFHX54y0o.1"},
+		}
+
+		if !reflect.DeepEqual(matches, expected) {
+			t.Errorf("got %v want %v", matches, expected)
+		}
+	})
+
+	t.Run("errors when a file cannot be read", func(t *testing.T) {
+		_, err := hub.SearchConfigurationFile([]*idl.ReadConfigurationRequest_File{
+			{Path: filepath.Join(dir, "gpperfmon.conf"), Searches: []string{"/data"}},
+		})
+		if !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("got error %#v want %#v", err, os.ErrNotExist)
+		}
+	})
+}
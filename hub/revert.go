@@ -45,7 +45,7 @@ Cannot revert and restore the source cluster. Please contact support.`)
 	}
 
 	st.RunConditionally(idl.Substep_ensure_gpupgrade_agents_are_running, configCreated && agentsStarted, func(_ step.OutStreams) error {
-		_, err := RestartAgents(context.Background(), nil, AgentHosts(s.Source), s.AgentPort, utils.GetStateDir())
+		_, err := RestartAgents(context.Background(), agentContextDialer, s.JumpHost, AgentHosts(s.Source), s.AgentPort, utils.GetStateDir())
 		if err != nil {
 			return err
 		}
@@ -66,6 +66,12 @@ Cannot revert and restore the source cluster. Please contact support.`)
 		return s.Intermediate.Stop(streams)
 	})
 
+	// Restoring the conf files edited during the upgrade leaves the target
+	// cluster as it was initialized should deleting its data directories fail.
+	st.RunConditionally(idl.Substep_restore_conf_files, configCreated && hasExecuteStarted, func(_ step.OutStreams) error {
+		return RevertConfFiles(s.agentConns, s.Target.Version, s.Intermediate, s.Target)
+	})
+
 	st.RunConditionally(idl.Substep_delete_target_cluster_datadirs, configCreated, func(streams step.OutStreams) error {
 		return DeleteCoordinatorAndPrimaryDataDirectories(streams, s.agentConns, s.Intermediate)
 	})
@@ -0,0 +1,188 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"sort"
+
+	"github.com/blang/semver/v4"
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
+	"github.com/greenplum-db/gpupgrade/utils/errorlist"
+)
+
+// RevertConfFiles restores every conf file the conf update touches from the
+// backup written next to it, undoing UpdateConfFiles,
+// UpdateInternalAutoConfOnMirrors and UpdateInternalAutoConfOnPrimaries. The
+// conf files of the coordinator are restored locally and those of every other
+// host through its agent. A host failing to restore its files does not stop
+// the rest, and the failures of all hosts are returned together.
+func RevertConfFiles(agentConns []*idl.Connection, version semver.Version, intermediate *greenplum.Cluster, target *greenplum.Cluster) error {
+	coordinator, err := coordinatorConfPaths(version, intermediate, target)
+	if err != nil {
+		return err
+	}
+
+	restored, coordinatorErr := RestoreConfBackups(coordinator, confTempDir)
+	if coordinatorErr != nil {
+		coordinatorErr = xerrors.Errorf("restore conf backups on host %s: %w", target.CoordinatorHostname(), coordinatorErr)
+	}
+	logRestoredConfFiles(target.CoordinatorHostname(), restored)
+
+	requests, err := AgentConfRequests(version, intermediate, target)
+	if err != nil {
+		return err
+	}
+
+	request := func(ctx context.Context, conn *idl.Connection) error {
+		opts := internalAutoConfOptions(conn.Hostname, intermediate)
+		opts = append(opts, primaryInternalAutoConfOptions(conn.Hostname, intermediate, target)...)
+		for _, req := range requests[conn.Hostname] {
+			opts = append(opts, req.GetOptions()...)
+		}
+
+		paths := confPaths(opts)
+		if len(paths) == 0 {
+			return nil
+		}
+
+		reply, err := conn.AgentClient.RestoreConfBackups(ctx, &idl.RestoreConfBackupsRequest{Paths: paths, TempDir: confTempDir})
+		if err != nil {
+			return xerrors.Errorf("restore conf backups on host %s: %w", conn.Hostname, err)
+		}
+
+		logRestoredConfFiles(conn.Hostname, reply.GetRestoredPaths())
+		return nil
+	}
+
+	return errorlist.Append(coordinatorErr, ExecuteRPCContext(context.Background(), agentConns, request))
+}
+
+// coordinatorConfPaths returns the sorted paths of the conf files the conf
+// update edits on the coordinator, including those of the operator.
+func coordinatorConfPaths(version semver.Version, intermediate *greenplum.Cluster, target *greenplum.Cluster) ([]string, error) {
+	coordinator, err := coordinatorConfEdits(version, intermediate, target)
+	if err != nil {
+		return nil, err
+	}
+
+	coordinator = append(coordinator, customConfEditsOnHost(target.CoordinatorHostname(), target, func(seg *greenplum.SegConfig) bool {
+		return seg.IsCoordinator()
+	})...)
+
+	return confPaths(coordinator.Options()), nil
+}
+
+// confPaths returns the sorted paths of the options without duplicates.
+func confPaths(opts []*idl.UpdateFileConfOptions) []string {
+	seen := make(map[string]bool)
+	var paths []string
+	for _, opt := range opts {
+		if !seen[opt.GetPath()] {
+			seen[opt.GetPath()] = true
+			paths = append(paths, opt.GetPath())
+		}
+	}
+
+	sort.Strings(paths)
+	return paths
+}
+
+func logRestoredConfFiles(hostname string, paths []string) {
+	for _, path := range paths {
+		log.Printf("restored %s on host %s from its backup", path, hostname)
+	}
+}
+
+// RestoreConfBackups atomically replaces each conf file with the contents of
+// its first backup written next to it, which holds its contents before it
+// was first edited, and returns the paths restored. The temporary files of
+// the replacements are written in the ConfTempDir of tempDir. A file without
+// a backup is skipped so that restoring again is a no-op. The backups are
+// kept. Every file is attempted and the failures are returned together.
+func RestoreConfBackups(paths []string, tempDir string) ([]string, error) {
+	var restored []string
+	var err error
+
+	for _, path := range paths {
+		ok, rErr := restoreConfBackup(path, tempDir)
+		if rErr != nil {
+			err = errorlist.Append(err, xerrors.Errorf("restore %s: %w", path, rErr))
+			continue
+		}
+
+		if ok {
+			restored = append(restored, path)
+		}
+	}
+
+	return restored, err
+}
+
+// restoreConfBackup restores the file at path from its backup, or when there
+// is none from the original contents recorded in the conf journal.
+func restoreConfBackup(path string, tempDir string) (bool, error) {
+	// a symlinked file was backed up next to the file it links to
+	resolved, err := resolveConfPath(path)
+	if err != nil {
+		return false, err
+	}
+
+	backup := resolved + BackupSuffix
+	backupInfo, err := utils.System.Stat(backup)
+	if errors.Is(err, os.ErrNotExist) {
+		return restoreJournaledOriginal(path, tempDir)
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	contents, err := utils.System.ReadFile(backup)
+	if err != nil {
+		return false, err
+	}
+
+	// keep the mode and ownership of the live file when it still exists
+	info, err := utils.System.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		info, err = backupInfo, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	if err := writeConfFileAtomically(path, contents, info, tempDir); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func restoreJournaledOriginal(path string, tempDir string) (bool, error) {
+	original, ok, err := journaledOriginal(path)
+	if err != nil || !ok {
+		return false, err
+	}
+
+	info, err := utils.System.Stat(path)
+	if err != nil {
+		return false, err
+	}
+
+	if err := writeConfFileAtomically(path, original, info, tempDir); err != nil {
+		return false, err
+	}
+
+	log.Printf("restored %s from the conf journal as it has no backup", path)
+	return true, nil
+}
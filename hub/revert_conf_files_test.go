@@ -0,0 +1,143 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/blang/semver/v4"
+	"github.com/golang/mock/gomock"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/idl/mock_idl"
+	"github.com/greenplum-db/gpupgrade/testutils"
+)
+
+func TestRestoreConfBackups(t *testing.T) {
+	t.Run("restores each file from its backup and skips those without one", func(t *testing.T) {
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		postgresqlConf := filepath.Join(dir, "postgresql.conf")
+		testutils.MustWriteToFile(t, postgresqlConf, "port=6000\n")
+		testutils.MustWriteToFile(t, postgresqlConf+hub.BackupSuffix, "port=5000\n")
+
+		recoveryConf := filepath.Join(dir, "recovery.conf")
+		testutils.MustWriteToFile(t, recoveryConf, "primary_conninfo = 'port=6001'\n")
+
+		for i := 0; i < 2; i++ {
+			restored, err := hub.RestoreConfBackups([]string{postgresqlConf, recoveryConf}, "")
+			if err != nil {
+				t.Fatalf("unexpected error %+v", err)
+			}
+
+			if !reflect.DeepEqual(restored, []string{postgresqlConf}) {
+				t.Errorf("got restored %v want %v", restored, []string{postgresqlConf})
+			}
+
+			contents := testutils.MustReadFile(t, postgresqlConf)
+			if contents != "port=5000\n" {
+				t.Errorf("got %q want %q", contents, "port=5000\n")
+			}
+
+			contents = testutils.MustReadFile(t, recoveryConf)
+			if contents != "primary_conninfo = 'port=6001'\n" {
+				t.Errorf("got %q want the file without a backup unchanged", contents)
+			}
+		}
+	})
+
+	t.Run("restores the rest when a file fails", func(t *testing.T) {
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		// a directory cannot be read as a backup
+		unreadable := filepath.Join(dir, "postgresql.conf")
+		testutils.MustCreateDir(t, unreadable+hub.BackupSuffix)
+
+		path := filepath.Join(dir, "postgresql.auto.conf")
+		testutils.MustWriteToFile(t, path, "port=6000\n")
+		testutils.MustWriteToFile(t, path+hub.BackupSuffix, "port=5000\n")
+
+		restored, err := hub.RestoreConfBackups([]string{unreadable, path}, "")
+		if err == nil {
+			t.Errorf("expected an error")
+		}
+
+		if !reflect.DeepEqual(restored, []string{path}) {
+			t.Errorf("got restored %v want %v", restored, []string{path})
+		}
+
+		contents := testutils.MustReadFile(t, path)
+		if contents != "port=5000\n" {
+			t.Errorf("got %q want %q", contents, "port=5000\n")
+		}
+	})
+}
+
+func TestRevertConfFiles(t *testing.T) {
+	coordinatorDir := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, coordinatorDir)
+
+	path := filepath.Join(coordinatorDir, "postgresql.conf")
+	testutils.MustWriteToFile(t, path, "port=15432\n")
+	testutils.MustWriteToFile(t, path+hub.BackupSuffix, "port=50432\n")
+
+	intermediate := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: coordinatorDir, Port: 50432, Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg.HqtFHX54y0o.1", Port: 50434, Role: greenplum.PrimaryRole},
+		{DbID: 3, ContentID: 1, Hostname: "sdw2", DataDir: "/data/dbfast2/seg.HqtFHX54y0o.2", Port: 50435, Role: greenplum.PrimaryRole},
+		{DbID: 4, ContentID: 0, Hostname: "sdw2", DataDir: "/data/dbfast_mirror1/seg.HqtFHX54y0o.1", Port: 50436, Role: greenplum.MirrorRole},
+	})
+	target := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: coordinatorDir, Port: 15432, Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg1", Port: 25433, Role: greenplum.PrimaryRole},
+		{DbID: 3, ContentID: 1, Hostname: "sdw2", DataDir: "/data/dbfast2/seg2", Port: 25434, Role: greenplum.PrimaryRole},
+		{DbID: 4, ContentID: 0, Hostname: "sdw2", DataDir: "/data/dbfast_mirror1/seg1", Port: 25435, Role: greenplum.MirrorRole},
+	})
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	sdw1 := mock_idl.NewMockAgentClient(ctrl)
+	sdw1.EXPECT().RestoreConfBackups(gomock.Any(), &idl.RestoreConfBackupsRequest{
+		Paths: []string{"/data/dbfast1/seg1/postgresql.conf"},
+	}).Return(nil, errors.New("permission denied")).Times(1)
+
+	sdw2 := mock_idl.NewMockAgentClient(ctrl)
+	sdw2.EXPECT().RestoreConfBackups(gomock.Any(), &idl.RestoreConfBackupsRequest{
+		Paths: []string{
+			"/data/dbfast2/seg2/postgresql.conf",
+			"/data/dbfast_mirror1/seg.HqtFHX54y0o.1/internal.auto.conf",
+			"/data/dbfast_mirror1/seg1/postgresql.auto.conf",
+			"/data/dbfast_mirror1/seg1/postgresql.conf",
+		},
+	}).Return(&idl.RestoreConfBackupsReply{}, nil).Times(1)
+
+	agentConns := []*idl.Connection{
+		{AgentClient: sdw1, Hostname: "sdw1"},
+		{AgentClient: sdw2, Hostname: "sdw2"},
+	}
+
+	err := hub.RevertConfFiles(agentConns, semver.MustParse("7.0.0"), intermediate, target)
+	expected := "restore conf backups on host sdw1: permission denied"
+	if err == nil || err.Error() != expected {
+		t.Errorf("got error %v want %q", err, expected)
+	}
+
+	contents := testutils.MustReadFile(t, path)
+	if contents != "port=50432\n" {
+		t.Errorf("got %q want the coordinator conf restored", contents)
+	}
+
+	if _, err := os.Stat(path + hub.BackupSuffix); err != nil {
+		t.Errorf("expected the backup to be kept: %v", err)
+	}
+}
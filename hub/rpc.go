@@ -4,15 +4,170 @@
 package hub
 
 import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
 	"sync"
+	"time"
+
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/greenplum-db/gpupgrade/idl"
 	"github.com/greenplum-db/gpupgrade/utils/errorlist"
 )
 
 func ExecuteRPC(agentConns []*idl.Connection, executeRequest func(conn *idl.Connection) error) error {
+	return ExecuteRPCContext(context.Background(), agentConns, func(_ context.Context, conn *idl.Connection) error {
+		return executeRequest(conn)
+	})
+}
+
+// ExecuteRPCContext is ExecuteRPC for requests that honor ctx by passing it to
+// the agent. Once ctx is canceled the hosts
+// still waiting for their turn are not sent a request.
+func ExecuteRPCContext(ctx context.Context, agentConns []*idl.Connection, executeRequest func(ctx context.Context, conn *idl.Connection) error) error {
+	return ExecuteRPCResult(ctx, agentConns, executeRequest).Err()
+}
+
+// RPCResult is the outcome of a request sent to each host, for callers that
+// act on the hosts that failed such as to retry only those.
+type RPCResult struct {
+	// Succeeded are the sorted hosts whose request succeeded.
+	Succeeded []string
+	// Failed are the errors of the hosts whose request failed.
+	Failed map[string]error
+	// Skipped are the errors of the hosts that could not be reached and were
+	// skipped by the UnreachablePolicy. They are not failures.
+	Skipped map[string]error
+}
+
+// FailedHosts returns the sorted hosts whose request failed.
+func (r RPCResult) FailedHosts() []string {
+	var hosts []string
+	for host := range r.Failed {
+		hosts = append(hosts, host)
+	}
+
+	sort.Strings(hosts)
+	return hosts
+}
+
+// SkippedHosts returns the sorted hosts skipped as they could not be reached.
+func (r RPCResult) SkippedHosts() []string {
+	var hosts []string
+	for host := range r.Skipped {
+		hosts = append(hosts, host)
+	}
+
+	sort.Strings(hosts)
+	return hosts
+}
+
+// Err returns the errors of the failed hosts in the order of their hosts, or
+// nil when every request succeeded.
+func (r RPCResult) Err() error {
+	var err error
+	for _, host := range r.FailedHosts() {
+		err = errorlist.Append(err, r.Failed[host])
+	}
+
+	return err
+}
+
+// String summarizes the result, such as "succeeded: 17, failed: sdw3, sdw9".
+func (r RPCResult) String() string {
+	summary := fmt.Sprintf("succeeded: %d", len(r.Succeeded))
+	if len(r.Failed) > 0 {
+		summary += ", failed: " + strings.Join(r.FailedHosts(), ", ")
+	}
+
+	if len(r.Skipped) > 0 {
+		summary += ", skipped: " + strings.Join(r.SkippedHosts(), ", ")
+	}
+
+	return summary
+}
+
+// ExecuteRPCResult is ExecuteRPCContext returning the outcome of the request
+// of each host rather than their combined errors.
+func ExecuteRPCResult(ctx context.Context, agentConns []*idl.Connection, executeRequest func(ctx context.Context, conn *idl.Connection) error) RPCResult {
+	return ExecuteRPCProgress(ctx, agentConns, executeRequest, nil)
+}
+
+// ExecuteRPCProgress is ExecuteRPCResult also calling completed once each host
+// is done, including those not sent a request as ctx was canceled, so that
+// callers can report progress such as "updated conf on 12/40 hosts". It is
+// called concurrently from the goroutine of each host. A nil completed is not
+// called.
+func ExecuteRPCProgress(ctx context.Context, agentConns []*idl.Connection, executeRequest func(ctx context.Context, conn *idl.Connection) error, completed func(hostname string, err error)) RPCResult {
+	return ExecuteRPCPolicy(ctx, agentConns, executeRequest, completed, FailOnUnreachable)
+}
+
+// UnreachablePolicy is how the request of a host whose agent cannot be
+// reached is treated.
+type UnreachablePolicy int
+
+const (
+	// FailOnUnreachable fails the request like any other error.
+	FailOnUnreachable UnreachablePolicy = iota
+	// SkipUnreachable skips the host, such as one intentionally down, so
+	// that the other hosts can go on and the skipped ones can be finished
+	// later.
+	SkipUnreachable
+)
+
+// Skips is whether the policy skips a host whose request failed with err.
+func (p UnreachablePolicy) Skips(err error) bool {
+	return p == SkipUnreachable && unreachableRPCError(err)
+}
+
+// unreachableRPCError is whether err is a failure to connect to the agent
+// rather than one returned by it.
+func unreachableRPCError(err error) bool {
+	return status.Code(err) == codes.Unavailable
+}
+
+// ExecuteRPCPolicy is ExecuteRPCProgress reporting the hosts the policy skips
+// as they could not be reached in Skipped rather than Failed. completed is
+// still called with their error.
+func ExecuteRPCPolicy(ctx context.Context, agentConns []*idl.Connection, executeRequest func(ctx context.Context, conn *idl.Connection) error, completed func(hostname string, err error), policy UnreachablePolicy) RPCResult {
+	return ExecuteRPCBounded(ctx, agentConns, executeRequest, completed, policy, 0, 0)
+}
+
+// ExecuteRPCBounded is ExecuteRPCPolicy sending at most concurrency hosts a
+// request at once, each bounded by timeout. Zero for either does not bound the
+// requests by it.
+func ExecuteRPCBounded(ctx context.Context, agentConns []*idl.Connection, executeRequest func(ctx context.Context, conn *idl.Connection) error, completed func(hostname string, err error), policy UnreachablePolicy, concurrency int, timeout time.Duration) RPCResult {
+	type hostErr struct {
+		hostname string
+		err      error
+	}
+
 	var wg sync.WaitGroup
-	errs := make(chan error, len(agentConns))
+	errs := make(chan hostErr, len(agentConns))
+
+	workers := len(agentConns)
+	if concurrency > 0 && concurrency < workers {
+		workers = concurrency
+	}
+	sem := make(chan struct{}, workers)
+
+	execute := func(conn *idl.Connection) error {
+		if err := ctx.Err(); err != nil {
+			return xerrors.Errorf("request not sent to host %s: %w", conn.Hostname, err)
+		}
+
+		hostCtx, cancel := ctx, func() {}
+		if timeout > 0 {
+			hostCtx, cancel = context.WithTimeout(ctx, timeout)
+		}
+		defer cancel()
+
+		return executeRequest(hostCtx, conn)
+	}
 
 	for _, conn := range agentConns {
 		conn := conn
@@ -21,18 +176,35 @@ func ExecuteRPC(agentConns []*idl.Connection, executeRequest func(conn *idl.Conn
 		go func() {
 			defer wg.Done()
 
-			err := executeRequest(conn)
-			errs <- err
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			err := execute(conn)
+			if completed != nil {
+				completed(conn.Hostname, err)
+			}
+			errs <- hostErr{conn.Hostname, err}
 		}()
 	}
 
 	wg.Wait()
 	close(errs)
 
-	var err error
+	result := RPCResult{Failed: make(map[string]error), Skipped: make(map[string]error)}
 	for e := range errs {
-		err = errorlist.Append(err, e)
+		if e.err == nil {
+			result.Succeeded = append(result.Succeeded, e.hostname)
+			continue
+		}
+
+		if policy.Skips(e.err) {
+			result.Skipped[e.hostname] = e.err
+			continue
+		}
+
+		result.Failed[e.hostname] = errorlist.Append(result.Failed[e.hostname], e.err)
 	}
+	sort.Strings(result.Succeeded)
 
-	return err
+	return result
 }
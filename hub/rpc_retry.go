@@ -0,0 +1,91 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RetryPolicy is how conf requests failing as their agent is briefly
+// unavailable, such as while it restarts, are retried. The backoff doubles
+// after each attempt up to MaxBackoff.
+type RetryPolicy struct {
+	Attempts       int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+
+	// Sleep waits between attempts, returning early with the error of ctx
+	// once it is done.
+	Sleep func(ctx context.Context, d time.Duration) error
+}
+
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		Attempts:       4,
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     4 * time.Second,
+		Sleep:          sleepContext,
+	}
+}
+
+var rpcRetryPolicy = DefaultRetryPolicy()
+
+func SetRPCRetryPolicy(policy RetryPolicy) {
+	rpcRetryPolicy = policy
+}
+
+func ResetRPCRetryPolicy() {
+	rpcRetryPolicy = DefaultRetryPolicy()
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// retryableRPCError is whether err is a transient failure to reach the agent.
+func retryableRPCError(err error) bool {
+	return status.Code(err) == codes.Unavailable
+}
+
+// retryRPC calls request until it succeeds, fails with an error other than
+// the agent being unavailable, or the attempts of the policy are exhausted.
+// Other errors fail immediately. As a request may fail after the agent has
+// acted on it, request must be safe to repeat, such as a read or a conf
+// request for which repeatableConfRequest holds.
+func retryRPC(ctx context.Context, hostname string, request func() error) error {
+	policy := rpcRetryPolicy
+	backoff := policy.InitialBackoff
+
+	for attempt := 1; ; attempt++ {
+		err := request()
+		if err == nil || !retryableRPCError(err) {
+			return err
+		}
+
+		if attempt >= policy.Attempts {
+			return xerrors.Errorf("host %s still unavailable after %d attempts: %w", hostname, attempt, err)
+		}
+
+		log.Printf("retrying the request to host %s in %s as it is unavailable: %v", hostname, backoff, err)
+		if sErr := policy.Sleep(ctx, backoff); sErr != nil {
+			return xerrors.Errorf("retry the request to host %s: %w", hostname, sErr)
+		}
+
+		backoff = min(2*backoff, policy.MaxBackoff)
+	}
+}
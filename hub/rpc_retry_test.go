@@ -0,0 +1,167 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/idl/mock_idl"
+	"github.com/greenplum-db/gpupgrade/utils/errorlist"
+)
+
+func TestConfRPCRetry(t *testing.T) {
+	var sleeps []time.Duration
+	hub.SetRPCRetryPolicy(hub.RetryPolicy{
+		Attempts:       4,
+		InitialBackoff: time.Second,
+		MaxBackoff:     3 * time.Second,
+		Sleep: func(_ context.Context, d time.Duration) error {
+			sleeps = append(sleeps, d)
+			return nil
+		},
+	})
+	defer hub.ResetRPCRetryPolicy()
+
+	// each host has a mirror whose internal.auto.conf is updated
+	intermediate := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: "/data/qddir/seg-1", Port: 15432, Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg1", Port: 25433, Role: greenplum.PrimaryRole},
+		{DbID: 3, ContentID: 0, Hostname: "sdw2", DataDir: "/data/dbfast_mirror1/seg1", Port: 25434, Role: greenplum.MirrorRole},
+		{DbID: 4, ContentID: 1, Hostname: "sdw2", DataDir: "/data/dbfast2/seg2", Port: 25435, Role: greenplum.PrimaryRole},
+		{DbID: 5, ContentID: 1, Hostname: "sdw1", DataDir: "/data/dbfast_mirror2/seg2", Port: 25436, Role: greenplum.MirrorRole},
+	})
+
+	unavailable := status.Error(codes.Unavailable, "connection refused")
+
+	// agent fails each attempt it is sent with the errors in order, and then
+	// succeeds
+	agent := func(ctrl *gomock.Controller, attempts *int, errs ...error) *mock_idl.MockAgentClient {
+		client := mock_idl.NewMockAgentClient(ctrl)
+		client.EXPECT().UpdateConfiguration(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(context.Context, *idl.UpdateConfigurationRequest, ...grpc.CallOption) (*idl.UpdateConfigurationReply, error) {
+				*attempts++
+				if *attempts <= len(errs) {
+					return nil, errs[*attempts-1]
+				}
+				return &idl.UpdateConfigurationReply{}, nil
+			}).AnyTimes()
+		return client
+	}
+
+	t.Run("retries while the agent is unavailable", func(t *testing.T) {
+		sleeps = nil
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var sdw1Attempts, sdw2Attempts int
+		agentConns := []*idl.Connection{
+			{AgentClient: agent(ctrl, &sdw1Attempts, unavailable, unavailable), Hostname: "sdw1"},
+			{AgentClient: agent(ctrl, &sdw2Attempts), Hostname: "sdw2"},
+		}
+
+		err := hub.UpdateInternalAutoConfOnMirrors(agentConns, intermediate)
+		if err != nil {
+			t.Errorf("unexpected error %+v", err)
+		}
+
+		if sdw1Attempts != 3 || sdw2Attempts != 1 {
+			t.Errorf("got %d and %d attempts want 3 and 1", sdw1Attempts, sdw2Attempts)
+		}
+
+		expected := []time.Duration{time.Second, 2 * time.Second}
+		if !reflect.DeepEqual(sleeps, expected) {
+			t.Errorf("got backoffs %v want %v", sleeps, expected)
+		}
+	})
+
+	t.Run("fails immediately on other errors", func(t *testing.T) {
+		sleeps = nil
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		expected := status.Error(codes.PermissionDenied, "permission denied")
+		var sdw1Attempts, sdw2Attempts int
+		agentConns := []*idl.Connection{
+			{AgentClient: agent(ctrl, &sdw1Attempts, expected), Hostname: "sdw1"},
+			{AgentClient: agent(ctrl, &sdw2Attempts), Hostname: "sdw2"},
+		}
+
+		err := hub.UpdateInternalAutoConfOnMirrors(agentConns, intermediate)
+		if !errors.Is(err, expected) {
+			t.Errorf("got error %#v want %#v", err, expected)
+		}
+
+		if sdw1Attempts != 1 || len(sleeps) != 0 {
+			t.Errorf("got %d attempts and backoffs %v want a single attempt", sdw1Attempts, sleeps)
+		}
+	})
+
+	t.Run("returns the hosts still unavailable once the attempts are exhausted", func(t *testing.T) {
+		sleeps = nil
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var sdw1Attempts, sdw2Attempts int
+		agentConns := []*idl.Connection{
+			{AgentClient: agent(ctrl, &sdw1Attempts, unavailable, unavailable, unavailable, unavailable), Hostname: "sdw1"},
+			{AgentClient: agent(ctrl, &sdw2Attempts), Hostname: "sdw2"},
+		}
+
+		err := hub.UpdateInternalAutoConfOnMirrors(agentConns, intermediate)
+
+		var errs errorlist.Errors
+		if errors.As(err, &errs) {
+			t.Fatalf("got errors %v want only the error of sdw1", errs)
+		}
+
+		if !errors.Is(err, unavailable) || !strings.Contains(err.Error(), "host sdw1 still unavailable after 4 attempts") {
+			t.Errorf("got error %v want sdw1 still unavailable after 4 attempts", err)
+		}
+
+		expected := []time.Duration{time.Second, 2 * time.Second, 3 * time.Second}
+		if !reflect.DeepEqual(sleeps, expected) {
+			t.Errorf("got backoffs %v want %v", sleeps, expected)
+		}
+	})
+
+	t.Run("stops retrying once the wait between attempts fails", func(t *testing.T) {
+		hub.SetRPCRetryPolicy(hub.RetryPolicy{Attempts: 4, InitialBackoff: time.Hour, MaxBackoff: time.Hour, Sleep: func(context.Context, time.Duration) error {
+			return context.Canceled
+		}})
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var sdw1Attempts, sdw2Attempts int
+		agentConns := []*idl.Connection{
+			{AgentClient: agent(ctrl, &sdw1Attempts, unavailable), Hostname: "sdw1"},
+			{AgentClient: agent(ctrl, &sdw2Attempts), Hostname: "sdw2"},
+		}
+
+		err := hub.UpdateInternalAutoConfOnMirrors(agentConns, intermediate)
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("got error %#v want %#v", err, context.Canceled)
+		}
+
+		if sdw1Attempts != 1 {
+			t.Errorf("got %d attempts want 1", sdw1Attempts)
+		}
+	})
+}
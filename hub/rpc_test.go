@@ -4,13 +4,21 @@
 package hub_test
 
 import (
+	"context"
 	"errors"
 	"reflect"
 	"sort"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/greenplum-db/gpupgrade/hub"
 	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils/errorlist"
 )
 
 func TestExecuteRPC(t *testing.T) {
@@ -66,4 +74,265 @@ func TestExecuteRPC(t *testing.T) {
 			t.Errorf("got error %#v, want %#v", err, expected)
 		}
 	})
+
+	t.Run("does not send requests once the context is canceled", func(t *testing.T) {
+		agentConns := []*idl.Connection{{Hostname: "sdw1"}, {Hostname: "sdw2"}}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		var sent int32
+		request := func(ctx context.Context, conn *idl.Connection) error {
+			atomic.AddInt32(&sent, 1)
+			return nil
+		}
+
+		err := hub.ExecuteRPCContext(ctx, agentConns, request)
+		var errs errorlist.Errors
+		if !errors.As(err, &errs) {
+			t.Fatalf("error %#v does not contain type %T", err, errs)
+		}
+
+		if len(errs) != len(agentConns) {
+			t.Errorf("got error count %d, want %d", len(errs), len(agentConns))
+		}
+
+		for _, err := range errs {
+			if !errors.Is(err, context.Canceled) {
+				t.Errorf("got error %#v, want %#v", err, context.Canceled)
+			}
+		}
+
+		if sent != 0 {
+			t.Errorf("got %d requests sent want none", sent)
+		}
+	})
+}
+
+func TestExecuteRPCResult(t *testing.T) {
+	var agentConns []*idl.Connection
+	for _, host := range []string{"sdw4", "sdw1", "sdw3", "sdw2"} {
+		agentConns = append(agentConns, &idl.Connection{Hostname: host})
+	}
+
+	failures := map[string]error{
+		"sdw3": errors.New("permission denied"),
+		"sdw1": errors.New("no space left on device"),
+	}
+
+	request := func(ctx context.Context, conn *idl.Connection) error {
+		return failures[conn.Hostname]
+	}
+
+	t.Run("returns the outcome of each host", func(t *testing.T) {
+		result := hub.ExecuteRPCResult(context.Background(), agentConns, request)
+
+		expected := []string{"sdw2", "sdw4"}
+		if !reflect.DeepEqual(result.Succeeded, expected) {
+			t.Errorf("got succeeded hosts %q want %q", result.Succeeded, expected)
+		}
+
+		expected = []string{"sdw1", "sdw3"}
+		if !reflect.DeepEqual(result.FailedHosts(), expected) {
+			t.Errorf("got failed hosts %q want %q", result.FailedHosts(), expected)
+		}
+
+		for host, err := range failures {
+			if !errors.Is(result.Failed[host], err) {
+				t.Errorf("got error %#v for host %s want %#v", result.Failed[host], host, err)
+			}
+		}
+
+		summary := "succeeded: 2, failed: sdw1, sdw3"
+		if result.String() != summary {
+			t.Errorf("got summary %q want %q", result.String(), summary)
+		}
+	})
+
+	t.Run("combines the errors in the order of their hosts", func(t *testing.T) {
+		err := hub.ExecuteRPCResult(context.Background(), agentConns, request).Err()
+
+		var errs errorlist.Errors
+		if !errors.As(err, &errs) {
+			t.Fatalf("error %#v does not contain type %T", err, errs)
+		}
+
+		expected := errorlist.Errors{failures["sdw1"], failures["sdw3"]}
+		if !reflect.DeepEqual(errs, expected) {
+			t.Errorf("got errors %v want %v", errs, expected)
+		}
+	})
+
+	t.Run("has no error when every request succeeds", func(t *testing.T) {
+		result := hub.ExecuteRPCResult(context.Background(), agentConns, func(ctx context.Context, conn *idl.Connection) error {
+			return nil
+		})
+
+		if err := result.Err(); err != nil {
+			t.Errorf("unexpected error %+v", err)
+		}
+
+		if result.String() != "succeeded: 4" {
+			t.Errorf("got summary %q want %q", result.String(), "succeeded: 4")
+		}
+	})
+}
+
+func TestExecuteRPCProgress(t *testing.T) {
+	var agentConns []*idl.Connection
+	for _, host := range []string{"sdw1", "sdw2", "sdw3"} {
+		agentConns = append(agentConns, &idl.Connection{Hostname: host})
+	}
+
+	type completion struct {
+		hostname string
+		failed   bool
+	}
+
+	t.Run("reports each host once it is done", func(t *testing.T) {
+		var mutex sync.Mutex
+		var completions []completion
+		completed := func(hostname string, err error) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			completions = append(completions, completion{hostname, err != nil})
+		}
+
+		result := hub.ExecuteRPCProgress(context.Background(), agentConns, func(ctx context.Context, conn *idl.Connection) error {
+			if conn.Hostname == "sdw2" {
+				return errors.New("permission denied")
+			}
+			return nil
+		}, completed)
+
+		sort.Slice(completions, func(i, j int) bool {
+			return completions[i].hostname < completions[j].hostname
+		})
+
+		expected := []completion{{"sdw1", false}, {"sdw2", true}, {"sdw3", false}}
+		if !reflect.DeepEqual(completions, expected) {
+			t.Errorf("got completions %v want %v", completions, expected)
+		}
+
+		if result.String() != "succeeded: 2, failed: sdw2" {
+			t.Errorf("got summary %q want %q", result.String(), "succeeded: 2, failed: sdw2")
+		}
+	})
+
+	t.Run("reports the hosts not sent a request once canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		var completions int32
+		hub.ExecuteRPCProgress(ctx, agentConns, func(ctx context.Context, conn *idl.Connection) error {
+			t.Errorf("unexpected request to host %s", conn.Hostname)
+			return nil
+		}, func(hostname string, err error) {
+			if !errors.Is(err, context.Canceled) {
+				t.Errorf("got error %#v for host %s want %#v", err, hostname, context.Canceled)
+			}
+			atomic.AddInt32(&completions, 1)
+		})
+
+		if completions != int32(len(agentConns)) {
+			t.Errorf("got %d completions want %d", completions, len(agentConns))
+		}
+	})
+}
+
+func TestExecuteRPCPolicy(t *testing.T) {
+	var agentConns []*idl.Connection
+	for _, host := range []string{"sdw1", "sdw2", "sdw3"} {
+		agentConns = append(agentConns, &idl.Connection{Hostname: host})
+	}
+
+	failures := map[string]error{
+		"sdw2": status.Error(codes.Unavailable, "connection refused"),
+		"sdw3": errors.New("permission denied"),
+	}
+
+	request := func(ctx context.Context, conn *idl.Connection) error {
+		return failures[conn.Hostname]
+	}
+
+	t.Run("fails unreachable hosts by default", func(t *testing.T) {
+		result := hub.ExecuteRPCPolicy(context.Background(), agentConns, request, nil, hub.FailOnUnreachable)
+		if result.String() != "succeeded: 1, failed: sdw2, sdw3" {
+			t.Errorf("got summary %q want %q", result.String(), "succeeded: 1, failed: sdw2, sdw3")
+		}
+	})
+
+	t.Run("reports skipped unreachable hosts apart from the failed ones", func(t *testing.T) {
+		var mutex sync.Mutex
+		var completed []string
+		result := hub.ExecuteRPCPolicy(context.Background(), agentConns, request, func(hostname string, err error) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			completed = append(completed, hostname)
+		}, hub.SkipUnreachable)
+
+		if result.String() != "succeeded: 1, failed: sdw3, skipped: sdw2" {
+			t.Errorf("got summary %q want %q", result.String(), "succeeded: 1, failed: sdw3, skipped: sdw2")
+		}
+
+		if !reflect.DeepEqual(result.SkippedHosts(), []string{"sdw2"}) || !errors.Is(result.Skipped["sdw2"], failures["sdw2"]) {
+			t.Errorf("got skipped %v want sdw2", result.Skipped)
+		}
+
+		if !errors.Is(result.Err(), failures["sdw3"]) || errors.Is(result.Err(), failures["sdw2"]) {
+			t.Errorf("got error %#v want only that of sdw3", result.Err())
+		}
+
+		if len(completed) != len(agentConns) {
+			t.Errorf("got %d completions want %d", len(completed), len(agentConns))
+		}
+	})
+}
+
+func TestExecuteRPCBounded(t *testing.T) {
+	t.Run("caps the number of concurrent requests", func(t *testing.T) {
+		var agentConns []*idl.Connection
+		for _, host := range []string{"sdw1", "sdw2", "sdw3", "sdw4", "sdw5"} {
+			agentConns = append(agentConns, &idl.Connection{Hostname: host})
+		}
+
+		var inFlight, maxInFlight int32
+		request := func(ctx context.Context, conn *idl.Connection) error {
+			n := atomic.AddInt32(&inFlight, 1)
+			defer atomic.AddInt32(&inFlight, -1)
+
+			for {
+				max := atomic.LoadInt32(&maxInFlight)
+				if n <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, n) {
+					break
+				}
+			}
+
+			time.Sleep(10 * time.Millisecond)
+			return nil
+		}
+
+		err := hub.ExecuteRPCBounded(context.Background(), agentConns, request, nil, hub.FailOnUnreachable, 2, 0).Err()
+		if err != nil {
+			t.Errorf("ExecuteRPCBounded returned error %+v", err)
+		}
+
+		if maxInFlight != 2 {
+			t.Errorf("got %d concurrent requests want 2", maxInFlight)
+		}
+	})
+
+	t.Run("bounds each request by the timeout", func(t *testing.T) {
+		agentConns := []*idl.Connection{{Hostname: "sdw1"}}
+
+		request := func(ctx context.Context, conn *idl.Connection) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}
+
+		err := hub.ExecuteRPCBounded(context.Background(), agentConns, request, nil, hub.FailOnUnreachable, 0, 10*time.Millisecond).Err()
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("got error %#v, want %#v", err, context.DeadlineExceeded)
+		}
+	})
 }
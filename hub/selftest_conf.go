@@ -0,0 +1,115 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/idl"
+)
+
+type confSelfTest struct {
+	name        string
+	contents    string
+	pattern     string
+	replacement string
+	expected    string
+}
+
+var confSelfTests = []confSelfTest{
+	{
+		name: "port",
+		contents: `port=5000
+  port = 5000 # indented
+gpperfmon_port=5000
+#port=5000`,
+		pattern:     fmt.Sprintf(portPattern, 5000),
+		replacement: fmt.Sprintf(numberReplacement, 6000),
+		expected: `port=6000
+  port = 6000 # indented
+gpperfmon_port=5000
+#port=5000`,
+	},
+	{
+		name: "listen_addresses",
+		contents: `listen_addresses = 'localhost'
+#listen_addresses = 'localhost'`,
+		pattern:     `(^[ \t]*listen_addresses[ \t]*=[ \t]*)'[^']*'`,
+		replacement: `\1'*'`,
+		expected: `listen_addresses = '*'
+#listen_addresses = 'localhost'`,
+	},
+	{
+		name:        "primary_conninfo",
+		contents:    `primary_conninfo = 'user=gpadmin host=sdw1 port=5000 sslmode=disable application_name=gp_walreceiver'`,
+		pattern:     fmt.Sprintf(primaryConninfoPortPattern, 5000),
+		replacement: fmt.Sprintf(numberReplacement, 6000),
+		expected:    `primary_conninfo = 'user=gpadmin host=sdw1 port=6000 sslmode=disable application_name=gp_walreceiver'`,
+	},
+}
+
+type ConfSelfTestResult struct {
+	Name string
+	Err  error
+}
+
+func (r ConfSelfTestResult) String() string {
+	if r.Err != nil {
+		return fmt.Sprintf("FAIL %s: %v", r.Name, r.Err)
+	}
+
+	return fmt.Sprintf("PASS %s", r.Name)
+}
+
+// SelfTestConf exercises the conf file rewrite engine against generated conf
+// files in a temporary directory under dir, which is removed afterwards. It
+// allows validating an installation without a cluster.
+func SelfTestConf(dir string) ([]ConfSelfTestResult, error) {
+	tempDir, err := os.MkdirTemp(dir, "gpupgrade-selftest-conf-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tempDir)
+
+	var results []ConfSelfTestResult
+	for _, test := range confSelfTests {
+		results = append(results, ConfSelfTestResult{Name: test.name, Err: runConfSelfTest(tempDir, test)})
+	}
+
+	return results, nil
+}
+
+func runConfSelfTest(dir string, test confSelfTest) error {
+	path := filepath.Join(dir, test.name+".conf")
+	if err := os.WriteFile(path, []byte(test.contents+"\n"), 0600); err != nil {
+		return err
+	}
+
+	err := UpdateConfigurationFile(context.Background(), []*idl.UpdateFileConfOptions{{
+		Path:        path,
+		Pattern:     test.pattern,
+		Replacement: test.replacement,
+	}})
+	if err != nil {
+		return err
+	}
+
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	actual := strings.TrimSuffix(string(contents), "\n")
+	if actual != test.expected {
+		return xerrors.Errorf("got %q want %q", actual, test.expected)
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/testutils"
+)
+
+func TestSelfTestConf(t *testing.T) {
+	t.Run("passes every conf self test and cleans up", func(t *testing.T) {
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		results, err := hub.SelfTestConf(dir)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		expected := []string{"port", "listen_addresses", "primary_conninfo"}
+		if len(results) != len(expected) {
+			t.Fatalf("got %d results want %d", len(results), len(expected))
+		}
+
+		for i, result := range results {
+			if result.Name != expected[i] {
+				t.Errorf("got result %q want %q", result.Name, expected[i])
+			}
+
+			if result.Err != nil {
+				t.Errorf("%s failed: %v", result.Name, result.Err)
+			}
+		}
+
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		if len(entries) != 0 {
+			t.Errorf("expected %q to be empty, found %d entries", dir, len(entries))
+		}
+	})
+
+	t.Run("errors when the temporary directory cannot be created", func(t *testing.T) {
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		_, err := hub.SelfTestConf(dir + "/does/not/exist")
+		if !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("got error %#v want %#v", err, os.ErrNotExist)
+		}
+	})
+}
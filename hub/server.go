@@ -158,7 +158,7 @@ func (s *Server) Stop(closeAgentConns bool) {
 }
 
 func (s *Server) RestartAgents(ctx context.Context, in *idl.RestartAgentsRequest) (*idl.RestartAgentsReply, error) {
-	restartedHosts, err := RestartAgents(ctx, nil, AgentHosts(s.Source), s.AgentPort, utils.GetStateDir())
+	restartedHosts, err := RestartAgents(ctx, agentContextDialer, s.JumpHost, AgentHosts(s.Source), s.AgentPort, utils.GetStateDir())
 	if err != nil {
 		return &idl.RestartAgentsReply{}, err
 	}
@@ -173,11 +173,13 @@ func (s *Server) RestartAgents(ctx context.Context, in *idl.RestartAgentsRequest
 
 func RestartAgents(ctx context.Context,
 	dialer func(context.Context, string) (net.Conn, error),
+	jumpHost string,
 	hostnames []string,
 	port int,
 	stateDir string) ([]string, error) {
 
 	var wg sync.WaitGroup
+
 	restartedHosts := make(chan string, len(hostnames))
 	errs := make(chan error, len(hostnames))
 
@@ -214,8 +216,8 @@ func RestartAgents(ctx context.Context,
 				errs <- err
 				return
 			}
-			cmd := ExecCommand("ssh", host,
-				fmt.Sprintf("bash -c \"%s agent --daemonize --port %d --state-directory %s\"", path, port, stateDir))
+			cmd := ExecCommand("ssh", sshArgs(jumpHost, host,
+				fmt.Sprintf("bash -c \"%s agent --daemonize --port %d --state-directory %s\"", path, port, stateDir))...)
 			stdout, err := cmd.Output()
 			if err != nil {
 				errs <- err
@@ -272,12 +274,15 @@ func (s *Server) AgentConns() ([]*idl.Connection, error) {
 		return s.agentConns, nil
 	}
 
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithBlock()}
+	if agentContextDialer != nil {
+		opts = append(opts, grpc.WithContextDialer(agentContextDialer))
+	}
+
 	hostnames := AgentHosts(s.Source)
 	for _, host := range hostnames {
 		ctx, cancelFunc := context.WithTimeout(context.Background(), DialTimeout)
-		conn, err := gRPCDialer(ctx,
-			host+":"+strconv.Itoa(s.AgentPort),
-			grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithBlock())
+		conn, err := gRPCDialer(ctx, host+":"+strconv.Itoa(s.AgentPort), opts...)
 		if err != nil {
 			cancelFunc()
 			return nil, xerrors.Errorf("agent connections: %w", err)
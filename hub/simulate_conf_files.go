@@ -0,0 +1,121 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/blang/semver/v4"
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils/errorlist"
+)
+
+// SimulateConfFiles runs the conf file updates against a captured cluster
+// fixture rather than a live cluster. The fixture contains a directory per
+// hostname mirroring the absolute data directory paths on that host, such as
+// <fixtureDir>/sdw1/data/primary/gpseg0/postgresql.conf. The fixture is copied
+// to outputDir and the edits are applied to the copy so the rewritten files
+// can be inspected without modifying the fixture.
+func SimulateConfFiles(fixtureDir string, outputDir string, version semver.Version, intermediate *greenplum.Cluster, target *greenplum.Cluster) error {
+	if err := copyTree(fixtureDir, outputDir); err != nil {
+		return xerrors.Errorf("copy fixture %q to %q: %w", fixtureDir, outputDir, err)
+	}
+
+	plan, err := PlanConfFiles(version, intermediate, target)
+	if err != nil {
+		return err
+	}
+
+	return UpdateConfigurationFile(context.Background(), simulatedOptions(outputDir, plan))
+}
+
+// simulatedOptions re-roots the option paths of each host under the simulation
+// output directory.
+func simulatedOptions(outputDir string, plan ConfPlan) []*idl.UpdateFileConfOptions {
+	var simulated []*idl.UpdateFileConfOptions
+	for _, edit := range plan {
+		opt := edit.Option
+		simulated = append(simulated, &idl.UpdateFileConfOptions{
+			Path:                    filepath.Join(outputDir, edit.Hostname, opt.GetPath()),
+			Pattern:                 opt.GetPattern(),
+			Replacement:             opt.GetReplacement(),
+			Reason:                  opt.GetReason(),
+			Guc:                     opt.GetGuc(),
+			ExpectedMatches:         opt.GetExpectedMatches(),
+			MatchCurrentValue:       opt.GetMatchCurrentValue(),
+			AllowNoMatch:            opt.GetAllowNoMatch(),
+			SkipIfAbsent:            opt.GetSkipIfAbsent(),
+			DuplicateMatches:        opt.GetDuplicateMatches(),
+			DataDir:                 simulatedDataDir(outputDir, edit.Hostname, opt.GetDataDir()),
+			PreserveTrailingNewline: preserveTrailingNewline(),
+		})
+	}
+
+	return simulated
+}
+
+func simulatedDataDir(outputDir string, hostname string, dataDir string) string {
+	if dataDir == "" {
+		return ""
+	}
+
+	return filepath.Join(outputDir, hostname, dataDir)
+}
+
+func copyTree(source string, destination string) error {
+	return filepath.WalkDir(source, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relPath, err := filepath.Rel(source, path)
+		if err != nil {
+			return err
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		destPath := filepath.Join(destination, relPath)
+		if entry.IsDir() {
+			return os.MkdirAll(destPath, info.Mode().Perm())
+		}
+
+		return copyFile(path, destPath, info.Mode().Perm())
+	})
+}
+
+func copyFile(source string, destination string, perm fs.FileMode) (err error) {
+	in, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cErr := in.Close(); cErr != nil {
+			err = errorlist.Append(err, cErr)
+		}
+	}()
+
+	out, err := os.OpenFile(destination, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cErr := out.Close(); cErr != nil {
+			err = errorlist.Append(err, cErr)
+		}
+	}()
+
+	_, err = io.Copy(out, in)
+	return err
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/blang/semver/v4"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/testutils"
+)
+
+func TestSimulateConfFiles(t *testing.T) {
+	intermediate := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: "/data/qddir/seg.HqtFHX54y0o.-1", Port: 50432, Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: -1, Hostname: "standby", DataDir: "/data/standby.HqtFHX54y0o", Port: 50433, Role: greenplum.MirrorRole},
+		{DbID: 3, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg.HqtFHX54y0o.1", Port: 50434, Role: greenplum.PrimaryRole},
+		{DbID: 4, ContentID: 0, Hostname: "sdw2", DataDir: "/data/dbfast_mirror1/seg.HqtFHX54y0o.1", Port: 50435, Role: greenplum.MirrorRole},
+	})
+
+	target := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: "/data/qddir/seg-1", Port: 15432, Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: -1, Hostname: "standby", DataDir: "/data/standby", Port: 16432, Role: greenplum.MirrorRole},
+		{DbID: 3, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg1", Port: 25433, Role: greenplum.PrimaryRole},
+		{DbID: 4, ContentID: 0, Hostname: "sdw2", DataDir: "/data/dbfast_mirror1/seg1", Port: 25434, Role: greenplum.MirrorRole},
+	})
+
+	fixture := map[string]string{
+		"coordinator/data/qddir/seg-1/postgresql.conf":       "port=50432\n",
+		"standby/data/standby/postgresql.conf":               "port=50433\n",
+		"standby/data/standby/postgresql.auto.conf":          "primary_conninfo = 'user=gpadmin host=coordinator port=50432'\n",
+		"sdw1/data/dbfast1/seg1/postgresql.conf":             "port=50434\n",
+		"sdw2/data/dbfast_mirror1/seg1/postgresql.conf":      "port=50434\n",
+		"sdw2/data/dbfast_mirror1/seg1/postgresql.auto.conf": "primary_conninfo = 'user=gpadmin host=sdw1 port=50434'\n",
+	}
+
+	expected := map[string]string{
+		"coordinator/data/qddir/seg-1/postgresql.conf":       "port=15432\n",
+		"standby/data/standby/postgresql.conf":               "port=16432\n",
+		"standby/data/standby/postgresql.auto.conf":          "primary_conninfo = 'user=gpadmin host=coordinator port=15432'\n",
+		"sdw1/data/dbfast1/seg1/postgresql.conf":             "port=25433\n",
+		"sdw2/data/dbfast_mirror1/seg1/postgresql.conf":      "port=25434\n",
+		"sdw2/data/dbfast_mirror1/seg1/postgresql.auto.conf": "primary_conninfo = 'user=gpadmin host=sdw1 port=25433'\n",
+	}
+
+	t.Run("applies the conf file updates to a copy of the fixture", func(t *testing.T) {
+		fixtureDir := testutils.GetTempDir(t, "fixture")
+		defer testutils.MustRemoveAll(t, fixtureDir)
+
+		outputDir := testutils.GetTempDir(t, "output")
+		defer testutils.MustRemoveAll(t, outputDir)
+
+		for path, contents := range fixture {
+			testutils.MustCreateDir(t, filepath.Dir(filepath.Join(fixtureDir, path)))
+			testutils.MustWriteToFile(t, filepath.Join(fixtureDir, path), contents)
+		}
+
+		err := hub.SimulateConfFiles(fixtureDir, outputDir, semver.MustParse("7.0.0"), intermediate, target)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		for path, contents := range expected {
+			actual := testutils.MustReadFile(t, filepath.Join(outputDir, path))
+			if actual != contents {
+				t.Errorf("%s got %q, want %q", path, actual, contents)
+			}
+		}
+
+		for path, contents := range fixture {
+			actual := testutils.MustReadFile(t, filepath.Join(fixtureDir, path))
+			if actual != contents {
+				t.Errorf("fixture %s was modified got %q, want %q", path, actual, contents)
+			}
+		}
+	})
+
+	t.Run("errors when the fixture does not exist", func(t *testing.T) {
+		outputDir := testutils.GetTempDir(t, "output")
+		defer testutils.MustRemoveAll(t, outputDir)
+
+		err := hub.SimulateConfFiles(filepath.Join(outputDir, "does", "not", "exist"), filepath.Join(outputDir, "simulated"), semver.MustParse("7.0.0"), intermediate, target)
+		if !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("got error %#v, want %v", err, os.ErrNotExist)
+		}
+	})
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/utils"
+)
+
+// archiveEntry is a file written to a compressed tarball.
+type archiveEntry struct {
+	Name string
+	Data []byte
+	Mode os.FileMode
+}
+
+// writeTarGz atomically writes the entries in order to a compressed tarball.
+func writeTarGz(archive string, files []archiveEntry) error {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	writer := tar.NewWriter(gz)
+
+	for _, file := range files {
+		header := &tar.Header{Name: file.Name, Mode: int64(file.Mode), Size: int64(len(file.Data))}
+		if err := writer.WriteHeader(header); err != nil {
+			return xerrors.Errorf("write %s: %w", archive, err)
+		}
+
+		if _, err := writer.Write(file.Data); err != nil {
+			return xerrors.Errorf("write %s: %w", archive, err)
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return xerrors.Errorf("write %s: %w", archive, err)
+	}
+
+	if err := gz.Close(); err != nil {
+		return xerrors.Errorf("write %s: %w", archive, err)
+	}
+
+	return utils.AtomicallyWrite(archive, buf.Bytes())
+}
+
+// readTarGz returns the contents of each entry of a compressed tarball keyed
+// by name.
+func readTarGz(archive string) (map[string][]byte, error) {
+	contents, err := utils.System.ReadFile(archive)
+	if err != nil {
+		return nil, err
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(contents))
+	if err != nil {
+		return nil, xerrors.Errorf("read %s: %w", archive, err)
+	}
+
+	entries := make(map[string][]byte)
+	reader := tar.NewReader(gz)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, xerrors.Errorf("read %s: %w", archive, err)
+		}
+
+		entries[header.Name], err = io.ReadAll(reader)
+		if err != nil {
+			return nil, xerrors.Errorf("read %s: %w", archive, err)
+		}
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,57 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/utils/errorlist"
+)
+
+type hostPort struct {
+	hostname string
+	port     int
+}
+
+// CheckTargetPortCollisions errors when segments on the same host have the
+// same target port, as rewriting their conf files would leave all but one of
+// them unable to start. Unlike CheckPostConfInvariants it checks the target
+// cluster rather than the conf files, so that it can run before any edit.
+// Every duplicate port is returned naming the content IDs using it.
+func CheckTargetPortCollisions(target *greenplum.Cluster) error {
+	segments := target.SelectSegments(func(*greenplum.SegConfig) bool { return true })
+	sort.Slice(segments, func(i, j int) bool { return segments[i].DbID < segments[j].DbID })
+
+	users := make(map[hostPort][]string)
+	for _, seg := range segments {
+		key := hostPort{hostname: seg.Hostname, port: seg.Port}
+		users[key] = append(users[key], fmt.Sprintf("content %d (dbid %d)", seg.ContentID, seg.DbID))
+	}
+
+	var keys []hostPort
+	for key, segs := range users {
+		if len(segs) > 1 {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].hostname != keys[j].hostname {
+			return keys[i].hostname < keys[j].hostname
+		}
+		return keys[i].port < keys[j].port
+	})
+
+	var err error
+	for _, key := range keys {
+		err = errorlist.Append(err, xerrors.Errorf("target port %d is used by more than one segment on host %s: %s",
+			key.port, key.hostname, strings.Join(users[key], ", ")))
+	}
+
+	return err
+}
@@ -0,0 +1,62 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/utils/errorlist"
+)
+
+func TestCheckTargetPortCollisions(t *testing.T) {
+	t.Run("succeeds when the ports are unique per host", func(t *testing.T) {
+		target := hub.MustCreateCluster(t, greenplum.SegConfigs{
+			{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: "/data/qddir/seg-1", Port: 15432, Role: greenplum.PrimaryRole},
+			{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg1", Port: 6000, Role: greenplum.PrimaryRole},
+			{DbID: 3, ContentID: 1, Hostname: "sdw2", DataDir: "/data/dbfast2/seg2", Port: 6000, Role: greenplum.PrimaryRole},
+			{DbID: 4, ContentID: 0, Hostname: "sdw2", DataDir: "/data/dbfast_mirror1/seg1", Port: 6001, Role: greenplum.MirrorRole},
+			{DbID: 5, ContentID: 1, Hostname: "sdw1", DataDir: "/data/dbfast_mirror2/seg2", Port: 6001, Role: greenplum.MirrorRole},
+		})
+
+		if err := hub.CheckTargetPortCollisions(target); err != nil {
+			t.Errorf("unexpected error %+v", err)
+		}
+	})
+
+	t.Run("errors with the contents of each port used more than once on a host", func(t *testing.T) {
+		target := hub.MustCreateCluster(t, greenplum.SegConfigs{
+			{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: "/data/qddir/seg-1", Port: 15432, Role: greenplum.PrimaryRole},
+			{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg1", Port: 6000, Role: greenplum.PrimaryRole},
+			{DbID: 3, ContentID: 1, Hostname: "sdw1", DataDir: "/data/dbfast2/seg2", Port: 6000, Role: greenplum.PrimaryRole},
+			{DbID: 4, ContentID: 2, Hostname: "sdw2", DataDir: "/data/dbfast3/seg3", Port: 6000, Role: greenplum.PrimaryRole},
+			{DbID: 5, ContentID: 0, Hostname: "sdw2", DataDir: "/data/dbfast_mirror1/seg1", Port: 6001, Role: greenplum.MirrorRole},
+			{DbID: 6, ContentID: 1, Hostname: "sdw2", DataDir: "/data/dbfast_mirror2/seg2", Port: 6001, Role: greenplum.MirrorRole},
+			{DbID: 7, ContentID: 2, Hostname: "sdw1", DataDir: "/data/dbfast_mirror3/seg3", Port: 6002, Role: greenplum.MirrorRole},
+		})
+
+		err := hub.CheckTargetPortCollisions(target)
+
+		var errs errorlist.Errors
+		if !errors.As(err, &errs) {
+			t.Fatalf("got error %#v want an errorlist", err)
+		}
+
+		expected := []string{
+			"target port 6000 is used by more than one segment on host sdw1: content 0 (dbid 2), content 1 (dbid 3)",
+			"target port 6001 is used by more than one segment on host sdw2: content 0 (dbid 5), content 1 (dbid 6)",
+		}
+		if len(errs) != len(expected) {
+			t.Fatalf("got %d errors want %d: %v", len(errs), len(expected), errs)
+		}
+
+		for i, err := range errs {
+			if err.Error() != expected[i] {
+				t.Errorf("got error %q want %q", err.Error(), expected[i])
+			}
+		}
+	})
+}
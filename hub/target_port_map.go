@@ -0,0 +1,136 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/utils"
+	"github.com/greenplum-db/gpupgrade/utils/errorlist"
+)
+
+// TargetPorts are the target ports of the primary and mirror of a content.
+// For content -1 they are the ports of the coordinator and standby.
+type TargetPorts struct {
+	Primary int `json:"primary"`
+	Mirror  int `json:"mirror,omitempty"`
+}
+
+// TargetPortMap maps each content to its target ports. It is supplied by an
+// external planning system when the target ports are not those of the
+// cluster, and overrides them when rewriting the conf files.
+type TargetPortMap map[int]TargetPorts
+
+// Validate errors unless the map has the ports of exactly the segments of the
+// target cluster.
+func (m TargetPortMap) Validate(target *greenplum.Cluster) error {
+	var err error
+
+	var contents []int
+	for content := range target.Primaries {
+		contents = append(contents, content)
+	}
+	sort.Ints(contents)
+	for _, content := range contents {
+		ports, ok := m[content]
+		if !ok {
+			err = errorlist.Append(err, xerrors.Errorf("the target port map has no ports for content %d", content))
+			continue
+		}
+
+		err = errorlist.Append(err, validTargetPort(content, "primary", ports.Primary))
+
+		_, hasMirror := target.Mirrors[content]
+		switch {
+		case hasMirror:
+			err = errorlist.Append(err, validTargetPort(content, "mirror", ports.Mirror))
+		case ports.Mirror != 0:
+			err = errorlist.Append(err, xerrors.Errorf("the target port map has a mirror port for content %d which has no mirror", content))
+		}
+	}
+
+	var unknown []int
+	for content := range m {
+		if _, ok := target.Primaries[content]; !ok {
+			unknown = append(unknown, content)
+		}
+	}
+	sort.Ints(unknown)
+	for _, content := range unknown {
+		err = errorlist.Append(err, xerrors.Errorf("the target port map has ports for content %d which is not in the cluster", content))
+	}
+
+	return err
+}
+
+func validTargetPort(content int, role string, port int) error {
+	if port < 1 || port > 65535 {
+		return xerrors.Errorf("the target port map has invalid %s port %d for content %d", role, port, content)
+	}
+
+	return nil
+}
+
+// LoadTargetPortMap reads a target port map from a JSON file of the form
+// {"-1": {"primary": 15432, "mirror": 16432}, "0": {"primary": 25433}}.
+func LoadTargetPortMap(path string) (TargetPortMap, error) {
+	contents, err := utils.System.ReadFile(path)
+	if err != nil {
+		return nil, xerrors.Errorf("read target port map: %w", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(contents))
+	decoder.DisallowUnknownFields()
+
+	var ports TargetPortMap
+	if err := decoder.Decode(&ports); err != nil {
+		return nil, xerrors.Errorf("parse target port map %s: %w", path, err)
+	}
+
+	return ports, nil
+}
+
+var targetPortMap TargetPortMap
+
+// SetTargetPortMap overrides the target ports of the cluster when rewriting
+// the conf files.
+func SetTargetPortMap(ports TargetPortMap) {
+	targetPortMap = ports
+}
+
+func ResetTargetPortMap() {
+	targetPortMap = nil
+}
+
+// remapTargetPorts returns a copy of the target cluster with the ports of the
+// target port map, or the cluster itself when there is no map.
+func remapTargetPorts(target *greenplum.Cluster) (*greenplum.Cluster, error) {
+	if targetPortMap == nil || coreConfMode {
+		return target, nil
+	}
+
+	if err := targetPortMap.Validate(target); err != nil {
+		return nil, err
+	}
+
+	remapped := *target
+	remapped.Primaries = make(greenplum.ContentToSegConfig)
+	for content, primary := range target.Primaries {
+		primary.Port = targetPortMap[content].Primary
+		remapped.Primaries[content] = primary
+	}
+
+	remapped.Mirrors = make(greenplum.ContentToSegConfig)
+	for content, mirror := range target.Mirrors {
+		mirror.Port = targetPortMap[content].Mirror
+		remapped.Mirrors[content] = mirror
+	}
+
+	return &remapped, nil
+}
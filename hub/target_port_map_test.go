@@ -0,0 +1,143 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/blang/semver/v4"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/greenplum-db/gpupgrade/config"
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/step"
+	"github.com/greenplum-db/gpupgrade/testutils"
+)
+
+func TestTargetPortMap(t *testing.T) {
+	intermediate := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: "/data/qddir/seg.HqtFHX54y0o.-1", Port: 50432, Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg.HqtFHX54y0o.1", Port: 50434, Role: greenplum.PrimaryRole},
+		{DbID: 3, ContentID: 0, Hostname: "sdw2", DataDir: "/data/dbfast_mirror1/seg.HqtFHX54y0o.1", Port: 50435, Role: greenplum.MirrorRole},
+	})
+
+	target := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: "/data/qddir/seg-1", Port: 15432, Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg1", Port: 25433, Role: greenplum.PrimaryRole},
+		{DbID: 3, ContentID: 0, Hostname: "sdw2", DataDir: "/data/dbfast_mirror1/seg1", Port: 25434, Role: greenplum.MirrorRole},
+	})
+
+	version := semver.MustParse("7.0.0")
+
+	ports := hub.TargetPortMap{
+		-1: {Primary: 6000},
+		0:  {Primary: 7000, Mirror: 8000},
+	}
+
+	t.Run("the external ports win over the ports of the cluster", func(t *testing.T) {
+		hub.SetTargetPortMap(ports)
+		defer hub.ResetTargetPortMap()
+
+		plan, err := hub.PlanConfFiles(version, intermediate, target)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		newValues := make(map[string]string)
+		for _, edit := range plan {
+			newValues[edit.Option.GetPath()] = edit.NewValue
+		}
+
+		expected := map[string]string{
+			"/data/qddir/seg-1/gpperfmon/conf/gpperfmon.conf": "/data/qddir/seg-1/gpperfmon/logs",
+			"/data/qddir/seg-1/postgresql.conf":               "6000",
+			"/data/dbfast1/seg1/postgresql.conf":              "7000",
+			"/data/dbfast_mirror1/seg1/postgresql.conf":       "8000",
+			"/data/dbfast_mirror1/seg1/postgresql.auto.conf":  "port=7000",
+		}
+		if !reflect.DeepEqual(newValues, expected) {
+			t.Errorf("got new values %v want %v", newValues, expected)
+		}
+	})
+
+	t.Run("sends the external ports to the agents", func(t *testing.T) {
+		stateDir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, stateDir)
+		testutils.SetEnv(t, "GPUPGRADE_HOME", stateDir)
+
+		hub.SetTargetPortMap(ports)
+		defer hub.ResetTargetPortMap()
+
+		err := hub.UpdateConfFiles(context.Background(), nil, nil, step.DevNullStream, config.StateVersion, hub.ConfUpdateOptions{DumpRequests: true}, version, intermediate, target)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		contents := testutils.MustReadFile(t, filepath.Join(stateDir, hub.ConfRequestsDir, hub.ConfPhaseSegmentPostgresqlConf, "sdw1.json"))
+		req := &idl.UpdateConfigurationRequest{}
+		if err := protojson.Unmarshal([]byte(contents), req); err != nil {
+			t.Fatal(err)
+		}
+
+		if replacement := req.GetOptions()[0].GetReplacement(); !strings.Contains(replacement, "7000") {
+			t.Errorf("got replacement %q want the external port 7000", replacement)
+		}
+	})
+
+	t.Run("errors unless the map has the ports of exactly the segments", func(t *testing.T) {
+		hub.SetTargetPortMap(hub.TargetPortMap{
+			0: {Primary: 70000},
+			1: {Primary: 9000},
+		})
+		defer hub.ResetTargetPortMap()
+
+		_, err := hub.PlanConfFiles(version, intermediate, target)
+		for _, expected := range []string{
+			"the target port map has no ports for content -1",
+			"the target port map has invalid primary port 70000 for content 0",
+			"the target port map has invalid mirror port 0 for content 0",
+			"the target port map has ports for content 1 which is not in the cluster",
+		} {
+			if err == nil || !strings.Contains(err.Error(), expected) {
+				t.Errorf("got error %v want it to contain %q", err, expected)
+			}
+		}
+	})
+
+	t.Run("loads the map from a file", func(t *testing.T) {
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		path := filepath.Join(dir, "ports.json")
+		testutils.MustWriteToFile(t, path, `{"-1": {"primary": 6000}, "0": {"primary": 7000, "mirror": 8000}}`)
+
+		loaded, err := hub.LoadTargetPortMap(path)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		if !reflect.DeepEqual(loaded, ports) {
+			t.Errorf("got %v want %v", loaded, ports)
+		}
+	})
+
+	t.Run("errors on unknown fields", func(t *testing.T) {
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		path := filepath.Join(dir, "ports.json")
+		testutils.MustWriteToFile(t, path, `{"0": {"primary": 7000, "standby": 8000}}`)
+
+		_, err := hub.LoadTargetPortMap(path)
+		if err == nil || !strings.Contains(err.Error(), "unknown field") {
+			t.Errorf("got error %v want an unknown field error", err)
+		}
+	})
+}
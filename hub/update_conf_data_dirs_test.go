@@ -0,0 +1,94 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/blang/semver/v4"
+
+	"github.com/greenplum-db/gpupgrade/config"
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/step"
+	"github.com/greenplum-db/gpupgrade/testutils"
+)
+
+func TestUpdateConfFilesOnDataDirs(t *testing.T) {
+	stateDir := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, stateDir)
+
+	resetEnv := testutils.SetEnv(t, "GPUPGRADE_HOME", stateDir)
+	defer resetEnv()
+
+	for _, version := range []string{"6.25.0", "7.0.0"} {
+		version := semver.MustParse(version)
+
+		t.Run("rewrites the ports of every segment for "+version.String(), func(t *testing.T) {
+			c := hub.MustCreateConfTestCluster(t, version)
+			defer testutils.MustRemoveAll(t, c.Dir)
+
+			err := hub.UpdateConfFiles(context.Background(), c.AgentConns, nil, step.DevNullStream, config.StateVersion, hub.ConfUpdateOptions{}, version, c.Intermediate, c.Target)
+			if err != nil {
+				t.Fatalf("unexpected error %+v", err)
+			}
+
+			for _, seg := range c.Target.SelectSegments(func(*greenplum.SegConfig) bool { return true }) {
+				for file, expected := range c.UpdatedConfFiles(seg.DbID) {
+					contents := c.MustReadConfFile(t, seg.DbID, file)
+					if contents != expected {
+						t.Errorf("got %s of dbid %d %q want %q", file, seg.DbID, contents, expected)
+					}
+				}
+			}
+		})
+	}
+
+	t.Run("rewrites only the port in effect when it is set twice", func(t *testing.T) {
+		version := semver.MustParse("7.0.0")
+
+		c := hub.MustCreateConfTestCluster(t, version)
+		defer testutils.MustRemoveAll(t, c.Dir)
+
+		primary := c.Target.Primaries[0]
+		path := filepath.Join(primary.DataDir, "postgresql.conf")
+		testutils.MustWriteToFile(t, path, testutils.MustReadFile(t, path)+"port=50434\n")
+
+		err := hub.UpdateConfFiles(context.Background(), c.AgentConns, nil, step.DevNullStream, config.StateVersion, hub.ConfUpdateOptions{}, version, c.Intermediate, c.Target)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		contents := c.MustReadConfFile(t, primary.DbID, "postgresql.conf")
+		expected := c.SeededConfFiles(primary.DbID)["postgresql.conf"] + "port=25433\n"
+		if contents != expected {
+			t.Errorf("got %q want %q", contents, expected)
+		}
+	})
+
+	t.Run("rewrites the port of a conninfo with an IPv6 host", func(t *testing.T) {
+		version := semver.MustParse("6.25.0")
+
+		c := hub.MustCreateConfTestCluster(t, version)
+		defer testutils.MustRemoveAll(t, c.Dir)
+
+		mirror := c.Target.Mirrors[0]
+		path := filepath.Join(mirror.DataDir, "recovery.conf")
+		testutils.MustWriteToFile(t, path, strings.Replace(testutils.MustReadFile(t, path), "host=sdw1", "host=fe80::1:50434", 1))
+
+		err := hub.UpdateConfFiles(context.Background(), c.AgentConns, nil, step.DevNullStream, config.StateVersion, hub.ConfUpdateOptions{}, version, c.Intermediate, c.Target)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		contents := c.MustReadConfFile(t, mirror.DbID, "recovery.conf")
+		expected := strings.Replace(c.UpdatedConfFiles(mirror.DbID)["recovery.conf"], "host=sdw1", "host=fe80::1:50434", 1)
+		if contents != expected {
+			t.Errorf("got %q want %q", contents, expected)
+		}
+	})
+}
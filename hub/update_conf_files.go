@@ -4,219 +4,1239 @@
 package hub
 
 import (
+	"bytes"
 	"context"
 	"fmt"
-	"os/exec"
+	"log"
 	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
 
 	"github.com/blang/semver/v4"
 	"golang.org/x/xerrors"
 
+	"github.com/greenplum-db/gpupgrade/config"
 	"github.com/greenplum-db/gpupgrade/greenplum"
 	"github.com/greenplum-db/gpupgrade/idl"
 	"github.com/greenplum-db/gpupgrade/step"
+	"github.com/greenplum-db/gpupgrade/upgrade"
+	"github.com/greenplum-db/gpupgrade/utils"
 	"github.com/greenplum-db/gpupgrade/utils/errorlist"
 )
 
-func UpdateConfFiles(agentConns []*idl.Connection, _ step.OutStreams, version semver.Version, intermediate *greenplum.Cluster, target *greenplum.Cluster) error {
-	if version.Major < 7 {
-		// update gpperfmon.conf on coordinator
-		err := UpdateConfigurationFile([]*idl.UpdateFileConfOptions{{
-			Path:        filepath.Join(target.CoordinatorDataDir(), "gpperfmon", "conf", "gpperfmon.conf"),
-			Pattern:     `^log_location = .*$`,
-			Replacement: fmt.Sprintf("log_location = %s", filepath.Join(target.CoordinatorDataDir(), "gpperfmon", "logs")),
-		}})
-		if err != nil {
+// Reasons identify the feature that produced an UpdateFileConfOptions so that
+// a surprising edit can be traced back to its source.
+const (
+	ReasonPortRewrite       = "port-rewrite"
+	ReasonConninfoRewrite   = "primary-conninfo-rewrite"
+	ReasonDbidRewrite       = "dbid-rewrite"
+	ReasonGpperfmonLogPath  = "gpperfmon-log-location"
+	ReasonHbaAddressRewrite = "pg-hba-address-rewrite"
+)
+
+// AlreadyAtTargetText is written to stdout when a full conf update changed no
+// files, such as when it is re-run against an already upgraded cluster.
+const AlreadyAtTargetText = "cluster already at target configuration"
+
+// ConfUpdateOptions are the options of a single conf update, given by the
+// request that started it rather than by the configuration of the hub.
+type ConfUpdateOptions struct {
+	// ResumeToken when not empty skips the phases completed before it was
+	// written.
+	ResumeToken string
+
+	// Hosts when not empty limits the conf update to the hosts named, such as
+	// to retry only the hosts that failed so that the files of the hosts
+	// already updated are left alone. The coordinator is only updated when
+	// its host is named.
+	Hosts []string
+
+	// DebugLogging logs each conf edit with its host, segment, file, pattern
+	// and whether it changed the file, such as for a verbose finalize. When
+	// off only the summary of each host is logged.
+	DebugLogging bool
+
+	// DryRun writes a diff of each conf file the update would change instead
+	// of changing it, so that the edits can be reviewed before an upgrade.
+	// Unlike the plan the diffs are computed against the actual conf files,
+	// so an edit whose pattern matches no lines is reported as an error.
+	DryRun bool
+
+	// DumpRequests writes the requests the update would send to the agents
+	// to the state directory instead of updating the conf files. This is
+	// lower level than the plan and is for diagnosing how the options are
+	// built.
+	DumpRequests bool
+}
+
+// UpdateConfFiles refuses to rewrite anything when the persisted state was
+// written by an incompatible gpupgrade, since the edits are derived from it.
+// Only one conf operation may run at a time. After each phase a resume token
+// is written to stdout. Passing it back as the ResumeToken of opts skips the
+// phases that were already completed. Hosts that fail within the configured
+// failure threshold do not stop the update of the other hosts, but still fail
+// it with the command retrying only them. The coordinator and standby are updated before or after the segments
+// according to the configured ConfOrder. Progress is sent to sender as
+// substep events when it is not nil. When enabled, a bundle of the conf files
+// before and after the update is written to the state directory. In core mode
+// only the port rewrites are made. Canceling ctx stops the update before its
+// next phase and before any host not yet sent its edits, and is returned as
+// an error rather than tolerated as a host failure. The pattern of every edit
+// is compiled before any host is sent its edits. The resume token, hosts,
+// debug logging and dry run of the update are given by opts.
+func UpdateConfFiles(ctx context.Context, agentConns []*idl.Connection, sender idl.MessageSender, streams step.OutStreams, stateVersion int, opts ConfUpdateOptions, version semver.Version, intermediate *greenplum.Cluster, target *greenplum.Cluster) (err error) {
+	if err := config.CheckStateVersion(stateVersion); err != nil {
+		return err
+	}
+
+	if err := confOrder.Validate(); err != nil {
+		return err
+	}
+
+	target, err = remapTargetPorts(target)
+	if err != nil {
+		return err
+	}
+
+	if err := CheckTargetPortCollisions(target); err != nil {
+		return err
+	}
+
+	if err := CheckIntermediateContentIDs(intermediate, target); err != nil {
+		return err
+	}
+
+	digest, err := confResumeDigest(version, intermediate, target)
+	if err != nil {
+		return err
+	}
+
+	completed, err := parseConfResumeToken(opts.ResumeToken, digest)
+	if err != nil {
+		return err
+	}
+
+	if err := confExclusions.Validate(target); err != nil {
+		return err
+	}
+	reportConfExclusions(streams.Stdout(), target)
+
+	if err := validateConfHosts(target, opts.Hosts); err != nil {
+		return err
+	}
+	agentConns = confAgentConns(agentConns, opts.Hosts)
+
+	plan, err := PlanConfFiles(version, intermediate, target)
+	if err != nil {
+		return err
+	}
+
+	if err := ValidateConfPatterns(plan); err != nil {
+		return err
+	}
+	warnMissingGpperfmonLogLocation(streams.Stdout(), target)
+	for _, warning := range defaultGUCWarnings(version, plan) {
+		fmt.Fprintf(streams.Stdout(), "warning: %s\n", warning)
+	}
+
+	if err := writeConfPlanReport(streams.Stdout(), plan); err != nil {
+		return err
+	}
+
+	if coreConfMode {
+		fmt.Fprintln(streams.Stdout(), "core conf mode: only rewriting the ports of postgresql.conf and primary_conninfo")
+	}
+
+	if opts.DumpRequests {
+		dir := filepath.Join(utils.GetStateDir(), ConfRequestsDir)
+		if err := DumpConfRequests(dir, version, intermediate, target); err != nil {
 			return err
 		}
+
+		fmt.Fprintf(streams.Stdout(), "wrote the conf update requests to %s without sending them\n", dir)
+		return nil
 	}
 
-	// update postgresql.conf on coordinator
-	err := UpdateConfigurationFile([]*idl.UpdateFileConfOptions{{
-		Path:        filepath.Join(target.CoordinatorDataDir(), "postgresql.conf"),
-		Pattern:     fmt.Sprintf(`(^port[ \t]*=[ \t]*)%d([^0-9]|$)`, intermediate.CoordinatorPort()),
-		Replacement: fmt.Sprintf(`\1%d\2`, target.CoordinatorPort()),
-	}})
+	if opts.DryRun {
+		return previewConfFiles(ctx, agentConns, streams.Stdout(), opts.Hosts, version, intermediate, target)
+	}
+
+	release, err := AcquireConfLock()
 	if err != nil {
 		return err
 	}
+	defer func() {
+		if rErr := release(); rErr != nil {
+			err = errorlist.Append(err, rErr)
+		}
+	}()
 
-	if err := UpdatePostgresqlConfOnSegments(agentConns, intermediate, target); err != nil {
+	snapshot, err := beginConfSnapshotBundle(ctx, agentConns, version, intermediate, target)
+	if err != nil {
 		return err
 	}
 
-	if err := UpdateRecoveryConfOnSegments(agentConns, version, intermediate, target); err != nil {
+	support, err := beginConfSupportBundle(ctx, agentConns, version, intermediate, target)
+	if err != nil {
 		return err
 	}
 
-	return nil
-}
+	events := &confEvents{sender: sender}
+	events.started(len(agentConns))
+	defer func() {
+		events.finished(err != nil)
+	}()
 
-func UpdatePostgresqlConfOnSegments(agentConns []*idl.Connection, intermediate *greenplum.Cluster, target *greenplum.Cluster) error {
-	pattern := `(^port[ \t]*=[ \t]*)%d([^0-9]|$)`
-	replacement := `\1%d\2`
+	changes := &confChanges{events: events, runID: upgrade.NewID(), support: support, budget: newConfEditBudget(confEditConcurrency), debugLogging: opts.DebugLogging}
+	defer changes.writeChangedLines(streams.Stdout())
+	defer func() {
+		if sErr := support.finish(err, streams.Stdout()); sErr != nil {
+			err = errorlist.Append(err, sErr)
+		}
+	}()
 
-	request := func(conn *idl.Connection) error {
-		var opts []*idl.UpdateFileConfOptions
+	// batchedRecoveryConf is whether the primary_conninfo edits were sent with
+	// the postgresql.conf edits in this run. A run resumed after the
+	// postgresql.conf phase still sends them in their own phase.
+	batchedRecoveryConf := false
 
-		// add standby
-		if target.Standby().Hostname == conn.Hostname {
-			opt := &idl.UpdateFileConfOptions{
-				Path:        filepath.Join(target.StandbyDataDir(), "postgresql.conf"),
-				Pattern:     fmt.Sprintf(pattern, intermediate.StandbyPort()),
-				Replacement: fmt.Sprintf(replacement, target.StandbyPort()),
+	phases := map[int]struct {
+		name   string
+		update func() error
+	}{
+		confPhaseCoordinator: {ConfPhaseCoordinator, func() error {
+			return updateCoordinatorConfFiles(ctx, agentConns, changes, version, intermediate, target)
+		}},
+		confPhaseStandby: {ConfPhaseStandby, func() error {
+			return updateStandbyConfFiles(ctx, agentConns, changes, version, intermediate, target)
+		}},
+		confPhaseSegmentPostgresqlConf: {ConfPhaseSegmentPostgresqlConf, func() error {
+			if confBatchSegments {
+				batchedRecoveryConf = true
+				batch := NewConfBatch()
+				batch.AddPostgresqlConf(intermediate, target)
+				batch.AddRecoveryConf(version, intermediate, target)
+				return batch.send(ctx, agentConns, changes)
 			}
 
-			opts = append(opts, opt)
+			return updatePostgresqlConfOnSegments(ctx, agentConns, changes, intermediate, target)
+		}},
+		confPhaseSegmentRecoveryConf: {ConfPhaseSegmentRecoveryConf, func() error {
+			return updateRecoveryConfOnSegments(ctx, agentConns, changes, version, intermediate, target)
+		}},
+		confPhaseSegmentPgHbaConf: {ConfPhaseSegmentPgHbaConf, func() error {
+			return updatePgHbaConfOnSegments(ctx, agentConns, changes, intermediate, target)
+		}},
+		confPhaseCustom: {ConfPhaseCustom, func() error {
+			return updateCustomConfFiles(ctx, agentConns, changes, opts.Hosts, target)
+		}},
+	}
+
+	// Once a phase has tolerated host failures the token is no longer
+	// advanced, so that resuming from the last token written retries the
+	// failed hosts rather than skipping them.
+	var tolerated error
+	lastCompleted := completed
+	completePhase := func(phase int) {
+		if tolerated == nil {
+			lastCompleted = phase
+			writeConfResumeToken(streams.Stdout(), digest, phase)
 		}
+	}
 
-		// add mirrors
-		mirrors := target.SelectSegments(func(seg *greenplum.SegConfig) bool {
-			return seg.IsOnHost(conn.Hostname) && seg.IsMirror()
-		})
+	for _, phase := range confOrder.remaining(completed) {
+		p := phases[phase]
+		if err := ctx.Err(); err != nil {
+			return xerrors.Errorf("conf update canceled before the %s phase: %w", p.name, err)
+		}
 
-		for _, mirror := range mirrors {
-			opt := &idl.UpdateFileConfOptions{
-				Path:        filepath.Join(mirror.DataDir, "postgresql.conf"),
-				Pattern:     fmt.Sprintf(pattern, intermediate.Primaries[mirror.ContentID].Port),
-				Replacement: fmt.Sprintf(replacement, mirror.Port),
-			}
+		if phase == confPhaseCoordinator && !confHostIncluded(opts.Hosts, target.CoordinatorHostname()) {
+			log.Printf("skipping the %s phase since host %s is not updated", p.name, target.CoordinatorHostname())
+			completePhase(phase)
+			continue
+		}
 
-			opts = append(opts, opt)
+		if phase == confPhaseSegmentRecoveryConf && batchedRecoveryConf {
+			log.Printf("skipping the %s phase since its edits were sent with the %s phase", p.name, ConfPhaseSegmentPostgresqlConf)
+			completePhase(phase)
+			continue
 		}
 
-		// add primaries
-		primaries := target.SelectSegments(func(seg *greenplum.SegConfig) bool {
-			return seg.IsOnHost(conn.Hostname) && seg.IsPrimary()
-		})
+		hosts := len(agentConns)
+		if phase == confPhaseCoordinator {
+			hosts = 1
+		}
+		events.startPhase(p.name, hosts)
+		support.startPhase(p.name)
 
-		for _, primary := range primaries {
-			opt := &idl.UpdateFileConfOptions{
-				Path:        filepath.Join(primary.DataDir, "postgresql.conf"),
-				Pattern:     fmt.Sprintf(pattern, intermediate.Primaries[primary.ContentID].Port),
-				Replacement: fmt.Sprintf(replacement, primary.Port),
+		if err := p.update(); err != nil {
+			// Only failures of agent hosts count towards the threshold. The
+			// coordinator is always required.
+			failed := changes.failedHosts()
+			if phase == confPhaseCoordinator || ctx.Err() != nil || len(failed) == 0 || confFailureThreshold.Exceeded(len(failed), len(agentConns)) {
+				return err
 			}
 
-			opts = append(opts, opt)
+			tolerated = errorlist.Append(tolerated, err)
 		}
 
-		req := &idl.UpdateConfigurationRequest{Options: opts}
-		_, err := conn.AgentClient.UpdateConfiguration(context.Background(), req)
-		return err
+		completePhase(phase)
+	}
+
+	// the report is a record of the edits made, so failing to write it does
+	// not fail the update
+	if rErr := writeConfJournalReport(ctx, agentConns, target, streams.Stdout()); rErr != nil {
+		log.Printf("Warning: %v", rErr)
 	}
 
-	return ExecuteRPC(agentConns, request)
+	skipped := changes.skippedHosts()
+	if len(skipped) > 0 {
+		fmt.Fprintf(streams.Stdout(), "conf files were not updated on %d unreachable hosts: %s. Update them once they are up by retrying with only those hosts.\n", len(skipped), strings.Join(skipped, ", "))
+	}
+
+	// Hosts failed within the threshold do not fail the other hosts, but the
+	// update still fails so that it is retried on them before the cluster is
+	// started.
+	if tolerated != nil {
+		failed := changes.failedHosts()
+		log.Printf("tolerating conf update failures within the failure threshold of %s: %v", confFailureThreshold, tolerated)
+		err := snapshot.finish(ctx, agentConns, append(failed, skipped...), streams.Stdout())
+
+		token := confResumeToken{Digest: digest, Completed: lastCompleted}
+		failedErr := xerrors.Errorf("conf files were not updated on %d of %d hosts: %s: %w", len(failed), len(agentConns), strings.Join(failed, ", "), tolerated)
+		nextAction := fmt.Sprintf(`Retry the failed hosts with "gpupgrade finalize --conf-hosts %s --conf-resume-token %s".`, strings.Join(failed, ","), token)
+		return errorlist.Append(utils.NewNextActionErr(failedErr, nextAction), err)
+	}
+
+	// Only a full run can tell that the whole cluster was already updated.
+	if completed == confPhaseNone && len(opts.Hosts) == 0 && len(skipped) == 0 && changes.count() == 0 {
+		fmt.Fprintln(streams.Stdout(), AlreadyAtTargetText)
+	}
+
+	return snapshot.finish(ctx, agentConns, skipped, streams.Stdout())
 }
 
-func UpdateRecoveryConfOnSegments(agentConns []*idl.Connection, version semver.Version, intermediateCluster *greenplum.Cluster, target *greenplum.Cluster) error {
-	file := "postgresql.auto.conf"
-	if version.Major == 6 {
-		file = "recovery.conf"
+// confChanges collects the files changed and the hosts that failed across
+// the hosts of a conf update, reports each completed host to events and the
+// support bundle, and records each completed edit in the audit sink. A nil
+// confChanges discards them.
+type confChanges struct {
+	mutex  sync.Mutex
+	paths  []string
+	failed map[string]bool
+	// skipped are the hosts skipped as they could not be reached.
+	skipped map[string]bool
+	events  *confEvents
+	// runID identifies the conf update in the audit records.
+	runID   string
+	support *confSupportBundle
+	// lines are the lines changed on each host.
+	lines map[string][]*idl.ChangedLine
+	// budget bounds the files edited at once across every phase.
+	budget *confEditBudget
+	// debugLogging logs each edit and asks the agents to do the same.
+	debugLogging bool
+}
+
+// debug is whether the edits of the update are logged at debug level.
+func (c *confChanges) debug() bool {
+	return c != nil && c.debugLogging
+}
+
+func (c *confChanges) add(paths []string) {
+	if c == nil {
+		return
 	}
 
-	pattern := `(primary_conninfo .* port[ \t]*=[ \t]*)%d([^0-9]|$)`
-	replacement := `\1%d\2`
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.paths = append(c.paths, paths...)
+}
 
-	request := func(conn *idl.Connection) error {
-		var opts []*idl.UpdateFileConfOptions
+func (c *confChanges) count() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return len(c.paths)
+}
 
-		// add standby
-		if target.Standby().Hostname == conn.Hostname {
-			opt := &idl.UpdateFileConfOptions{
-				Path:        filepath.Join(target.StandbyDataDir(), file),
-				Pattern:     fmt.Sprintf(pattern, intermediateCluster.CoordinatorPort()),
-				Replacement: fmt.Sprintf(replacement, target.CoordinatorPort()),
-			}
+func (c *confChanges) fail(hostname string) {
+	if c == nil {
+		return
+	}
 
-			opts = append(opts, opt)
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.failed == nil {
+		c.failed = make(map[string]bool)
+	}
+	c.failed[hostname] = true
+}
+
+func (c *confChanges) skip(hostname string) {
+	if c == nil {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.skipped == nil {
+		c.skipped = make(map[string]bool)
+	}
+	c.skipped[hostname] = true
+}
+
+// editBudget returns the budget shared by the phases of the update, or a new
+// one for an update without confChanges.
+func (c *confChanges) editBudget() *confEditBudget {
+	if c == nil || c.budget == nil {
+		return newConfEditBudget(confEditConcurrency)
+	}
+
+	return c.budget
+}
+
+// failedHosts returns the sorted hosts that failed in any phase.
+func (c *confChanges) failedHosts() []string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return sortedHosts(c.failed)
+}
+
+// skippedHosts returns the sorted hosts skipped in any phase as they could
+// not be reached. A host skipped in one phase is skipped again in each later
+// phase it has edits in unless it comes back.
+func (c *confChanges) skippedHosts() []string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return sortedHosts(c.skipped)
+}
+
+func sortedHosts(set map[string]bool) []string {
+	var hosts []string
+	for host := range set {
+		hosts = append(hosts, host)
+	}
+
+	sort.Strings(hosts)
+	return hosts
+}
+
+// checkWrittenValues compares the values read back after writing with the
+// new value of each edit naming a GUC, returning all mismatches.
+func checkWrittenValues(hostname string, edits ConfPlan, values []*idl.ReadConfigurationReply_Value) error {
+	type key struct{ path, name string }
+	actuals := make(map[key]*idl.ReadConfigurationReply_Value)
+	for _, value := range values {
+		actuals[key{value.GetPath(), value.GetName()}] = value
+	}
+
+	var err error
+	for _, edit := range edits {
+		path, guc := edit.Option.GetPath(), edit.Option.GetGuc()
+		if guc == "" {
+			continue
 		}
 
-		// add mirrors
-		mirrors := target.SelectSegments(func(seg *greenplum.SegConfig) bool {
-			return seg.IsOnHost(conn.Hostname) && seg.IsMirror()
-		})
+		// a conditional edit is skipped when its precondition is not met
+		if edit.Option.GetMatchCurrentValue() != "" {
+			continue
+		}
+
+		// an optional file that is absent is not read back
+		if _, read := actuals[key{path, guc}]; !read && edit.Option.GetSkipIfAbsent() {
+			continue
+		}
+
+		actual, found := writtenValue(actuals[key{path, guc}])
+		if !found {
+			err = errorlist.Append(err, xerrors.Errorf("%s is not set in %s on host %s after writing", guc, path, hostname))
+			continue
+		}
+
+		if actual != edit.NewValue {
+			err = errorlist.Append(err, xerrors.Errorf("%s in %s on host %s is %s after writing but expected %s", guc, path, hostname, actual, edit.NewValue))
+		}
+	}
+
+	return err
+}
 
-		for _, mirror := range mirrors {
-			opt := &idl.UpdateFileConfOptions{
-				Path:        filepath.Join(mirror.DataDir, file),
-				Pattern:     fmt.Sprintf(pattern, intermediateCluster.Primaries[mirror.ContentID].Port),
-				Replacement: fmt.Sprintf(replacement, target.Primaries[mirror.ContentID].Port),
+// writtenValue returns the value of a GUC read back after writing in the form
+// of the new value of its edit. Only the port of primary_conninfo is edited so
+// only its port is compared.
+func writtenValue(value *idl.ReadConfigurationReply_Value) (string, bool) {
+	if !value.GetFound() {
+		return "", false
+	}
+
+	if value.GetName() == "primary_conninfo" {
+		port, found := conninfoPort(value.GetValue())
+		return "port=" + port, found
+	}
+
+	return value.GetValue(), true
+}
+
+// verifyAfterWrite has the agents read back the edited GUCs in the same
+// request that writes them, and fails the update when a value is not the
+// expected one. This avoids a separate VerifyConfFiles round-trip.
+var verifyAfterWrite = false
+
+func SetVerifyAfterWrite(verify bool) {
+	verifyAfterWrite = verify
+}
+
+func ResetVerifyAfterWrite() {
+	verifyAfterWrite = false
+}
+
+// hostCompleted reports that a host finished the current phase with the reply
+// of its update, which is nil when the update was not sent.
+func (c *confChanges) hostCompleted(hostname string, reply *idl.UpdateConfigurationReply, err error) {
+	if c == nil {
+		return
+	}
+
+	c.events.hostCompleted(hostname, err != nil)
+	c.addLines(hostname, reply.GetChangedLines())
+	c.support.hostCompleted(ConfHostResult{
+		Hostname:   hostname,
+		Changed:    reply.GetChangedPaths(),
+		Skipped:    reply.GetSkipped(),
+		RolledBack: reply.GetRolledBackPaths(),
+	}, err)
+}
+
+// updateConfOnHosts sends each host the edits of its conf files, recording
+// the changed files and failed hosts in changes. Each host waits for its
+// share of the edit budget before it is sent its edits. Unreachable hosts are
+// treated by the conf unreachable policy.
+func updateConfOnHosts(ctx context.Context, agentConns []*idl.Connection, changes *confChanges, hostEdits func(hostname string) (ConfPlan, error)) error {
+	return updateConfOnHostsPolicy(ctx, agentConns, changes, confUnreachablePolicy, hostEdits)
+}
+
+func updateConfOnHostsPolicy(ctx context.Context, agentConns []*idl.Connection, changes *confChanges, policy UnreachablePolicy, hostEdits func(hostname string) (ConfPlan, error)) error {
+	budget := changes.editBudget()
+
+	send := func(ctx context.Context, conn *idl.Connection) (*idl.UpdateConfigurationReply, error) {
+		edits, err := hostEdits(conn.Hostname)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := checkSegmentConfPaths(conn.Hostname, edits); err != nil {
+			return nil, err
+		}
+
+		req := newConfRequest(edits)
+		if req == nil {
+			return nil, nil
+		}
+
+		req.DebugLogging = changes.debug()
+		req.FileConcurrency = int32(budget.share(confRequestFiles(req), len(agentConns)))
+		release, err := budget.acquire(ctx, int(req.GetFileConcurrency()))
+		if err != nil {
+			return nil, xerrors.Errorf("update conf files on host %s: %w", conn.Hostname, err)
+		}
+		defer release()
+
+		var reply *idl.UpdateConfigurationReply
+		update := func() error {
+			return withConfRPCTimeout(ctx, conn.Hostname, "update conf files", func(ctx context.Context) error {
+				reply, err = updateConfigurationWithHeartbeat(ctx, conn, req)
+				return err
+			})
+		}
+
+		if repeatableConfRequest(req) {
+			err = retryRPC(ctx, conn.Hostname, update)
+		} else {
+			err = update()
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		changes.add(reply.GetChangedPaths())
+		reportSegmentConfResults(conn.Hostname, edits, reply.GetChangedPaths())
+		logConfEdits(conn.Hostname, edits, reply, changes.debug())
+		if err := changes.audit(conn.Hostname, edits, reply.GetChangedPaths(), reply.GetSkipped(), reply.GetRolledBackPaths()); err != nil {
+			return reply, err
+		}
+
+		for _, skip := range reply.GetSkipped() {
+			log.Printf("skipped %s on host %s", skip, conn.Hostname)
+		}
+
+		for _, satisfied := range reply.GetSatisfied() {
+			log.Printf("left %s on host %s", satisfied, conn.Hostname)
+		}
+
+		for _, warning := range reply.GetWarnings() {
+			log.Printf("Warning: %s on host %s", warning, conn.Hostname)
+		}
+
+		var links []string
+		for path := range reply.GetResolvedPaths() {
+			links = append(links, path)
+		}
+		sort.Strings(links)
+
+		for _, path := range links {
+			log.Printf("edited %s on host %s through its symlink to %s", path, conn.Hostname, reply.GetResolvedPaths()[path])
+		}
+
+		if err := checkConfChecksums(conn.Hostname, req.GetOptions(), reply.GetChecksums()); err != nil {
+			return reply, err
+		}
+
+		if verifyAfterWrite {
+			err := checkWrittenValues(conn.Hostname, edits, reply.GetValues())
+			for _, path := range reply.GetRolledBackPaths() {
+				err = errorlist.Append(err, xerrors.Errorf("rolled back %s on host %s to its contents before the update", path, conn.Hostname))
 			}
 
-			opts = append(opts, opt)
+			return reply, err
 		}
 
-		req := &idl.UpdateConfigurationRequest{Options: opts}
-		_, err := conn.AgentClient.UpdateConfiguration(context.Background(), req)
+		return reply, nil
+	}
+
+	var mutex sync.Mutex
+	replies := make(map[string]*idl.UpdateConfigurationReply)
+	request := func(ctx context.Context, conn *idl.Connection) error {
+		reply, err := send(ctx, conn)
+
+		mutex.Lock()
+		defer mutex.Unlock()
+		replies[conn.Hostname] = reply
+		return err
+	}
+
+	// Hosts not sent their edits as ctx was canceled also complete the phase
+	// so that its progress accounts for every host.
+	completed := func(hostname string, err error) {
+		if policy.Skips(err) {
+			log.Printf("Warning: skipping host %s since it could not be reached: %v", hostname, err)
+			changes.skip(hostname)
+		} else if err != nil {
+			changes.fail(hostname)
+		}
+
+		mutex.Lock()
+		reply := replies[hostname]
+		mutex.Unlock()
+
+		changes.hostCompleted(hostname, reply, err)
+	}
+
+	result := executeConfRPC(ctx, agentConns, request, completed, policy)
+	if len(result.Failed) > 0 || len(result.Skipped) > 0 {
+		log.Printf("conf update of %d hosts %s", len(agentConns), result)
+	}
+
+	return result.Err()
+}
+
+// newConfRequest returns the request sent to an agent to make the edits of
+// its host, or nil when there are none.
+func newConfRequest(edits ConfPlan) *idl.UpdateConfigurationRequest {
+	opts := confRequestOptions(edits)
+	if len(opts) == 0 {
+		return nil
+	}
+
+	return &idl.UpdateConfigurationRequest{Options: opts, VerifyAfterWrite: verifyAfterWrite}
+}
+
+// confRequestOptions returns the options of the edits as sent to an agent.
+func confRequestOptions(edits ConfPlan) []*idl.UpdateFileConfOptions {
+	return expectedValueOptions(edits, edits.Options())
+}
+
+// repeatableConfRequest is whether sending req again after the agent acted on
+// it leaves the files unchanged, which holds when every option checks the
+// value of its GUC before editing. Only such requests are retried, as the
+// agent may be unavailable only after editing some of the files.
+func repeatableConfRequest(req *idl.UpdateConfigurationRequest) bool {
+	for _, opt := range req.GetOptions() {
+		if opt.GetExpectedValue() == "" && opt.GetMatchCurrentValue() == "" {
+			return false
+		}
+	}
+
+	return true
+}
+
+// coordinatorConfEdits returns the edits of the conf files of the
+// coordinator.
+func coordinatorConfEdits(version semver.Version, intermediate *greenplum.Cluster, target *greenplum.Cluster) (ConfPlan, error) {
+	hostname := target.CoordinatorHostname()
+
+	var edits ConfPlan
+
+	// update gpperfmon.conf on coordinator
+	gpperfmon, err := gpperfmonConfEdit(hostname, target.CoordinatorDataDir())
+	if err != nil {
+		return nil, err
+	}
+	edits = append(edits, gpperfmon)
+
+	// update postgresql.conf on coordinator
+	edits = append(edits, portEdit(hostname, target.CoordinatorDataDir(), intermediate.CoordinatorPort(), target.CoordinatorPort()))
+
+	roleEdits, err := coordinatorRoleEdits(hostname, version, target.CoordinatorDataDir())
+	if err != nil {
+		return nil, err
+	}
+
+	return coreEdits(append(edits, roleEdits...)), nil
+}
+
+// gpperfmonConfEdit points the log_location of gpperfmon.conf at the logs
+// directory of the target coordinator data directory, or at the configured
+// gpperfmon log location. The conf is at the same path for every version, but
+// gpperfmon is optional, and is no longer shipped with 7, so the edit is
+// skipped when the conf is absent and tolerated when it does not set a log
+// location.
+func gpperfmonConfEdit(hostname string, dataDir string) (ConfEdit, error) {
+	logLocation, err := gpperfmonLogDir(dataDir)
+	if err != nil {
+		return ConfEdit{}, err
+	}
+
+	return ConfEdit{Hostname: hostname, NewValue: logLocation, Option: &idl.UpdateFileConfOptions{
+		Path:         filepath.Join(dataDir, "gpperfmon", "conf", "gpperfmon.conf"),
+		Pattern:      `^log_location = .*$`,
+		Replacement:  "log_location = " + quoteReplacement(logLocation),
+		Reason:       ReasonGpperfmonLogPath,
+		Guc:          "log_location",
+		AllowNoMatch: true,
+		SkipIfAbsent: true,
+		DataDir:      dataDir,
+	}}, nil
+}
+
+// updateCoordinatorConfFiles sends the coordinator the edits of its conf files
+// through the agent on its host, or a local one, as the edits of the segments
+// are sent so that the coordinator is validated and verified the same way.
+func updateCoordinatorConfFiles(ctx context.Context, agentConns []*idl.Connection, changes *confChanges, version semver.Version, intermediate *greenplum.Cluster, target *greenplum.Cluster) error {
+	edits, err := coordinatorConfEdits(version, intermediate, target)
+	if err != nil {
 		return err
 	}
 
-	return ExecuteRPC(agentConns, request)
+	// the coordinator is never skipped as the cluster cannot start without it
+	conn := coordinatorConfConn(agentConns, target.CoordinatorHostname())
+	return updateConfOnHostsPolicy(ctx, []*idl.Connection{conn}, changes, FailOnUnreachable, func(string) (ConfPlan, error) {
+		return edits, nil
+	})
+}
+
+// UpdateStandbyConfFiles updates both the postgresql.conf port and the
+// primary_conninfo port of the standby in a single request to the standby
+// host, so that the standby's files are always updated together.
+func UpdateStandbyConfFiles(ctx context.Context, agentConns []*idl.Connection, version semver.Version, intermediate *greenplum.Cluster, target *greenplum.Cluster) error {
+	return updateStandbyConfFiles(ctx, agentConns, nil, version, intermediate, target)
+}
+
+func updateStandbyConfFiles(ctx context.Context, agentConns []*idl.Connection, changes *confChanges, version semver.Version, intermediate *greenplum.Cluster, target *greenplum.Cluster) error {
+	if !target.HasStandby() {
+		return nil
+	}
+
+	return updateConfOnHosts(ctx, agentConns, changes, func(hostname string) (ConfPlan, error) {
+		return standbyConfEdits(hostname, version, intermediate, target), nil
+	})
+}
+
+func standbyConfEdits(hostname string, version semver.Version, intermediate *greenplum.Cluster, target *greenplum.Cluster) ConfPlan {
+	if !target.HasStandby() || target.StandbyHostname() != hostname {
+		return nil
+	}
+
+	return ConfPlan{
+		portEdit(hostname, target.StandbyDataDir(), intermediate.StandbyPort(), target.StandbyPort()),
+		conninfoPortEdit(hostname, filepath.Join(target.StandbyDataDir(), recoveryConfFile(version)), intermediate.CoordinatorPort(), target.CoordinatorPort()),
+	}
+}
+
+func UpdatePostgresqlConfOnSegments(ctx context.Context, agentConns []*idl.Connection, intermediate *greenplum.Cluster, target *greenplum.Cluster) error {
+	return updatePostgresqlConfOnSegments(ctx, agentConns, nil, intermediate, target)
+}
+
+func updatePostgresqlConfOnSegments(ctx context.Context, agentConns []*idl.Connection, changes *confChanges, intermediate *greenplum.Cluster, target *greenplum.Cluster) error {
+	batch := NewConfBatch()
+	batch.AddPostgresqlConf(intermediate, target)
+	return batch.send(ctx, agentConns, changes)
+}
+
+// portPattern matches the port line of a postgresql.conf. Postgres ignores
+// whitespace before a GUC name so any indentation is allowed, and is captured
+// so that it is preserved in the rewritten line.
+const portPattern = `(^[ \t]*port[ \t]*=[ \t]*)%d([^0-9]|$)`
+
+// numberReplacement replaces the number matched between the two groups of the
+// port and dbid patterns.
+const numberReplacement = `\1%d\2`
+
+// quoteReplacement escapes a literal value written by the replacement of an
+// UpdateFileConfOptions, such as a path, so that \ and & are not taken as
+// references to the match. Literal values matched by a pattern are quoted
+// with regexp.QuoteMeta.
+func quoteReplacement(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `&`, `\&`).Replace(value)
+}
+
+// portEdit rewrites the port in the postgresql.conf of a data directory.
+func portEdit(hostname string, dataDir string, oldPort int, newPort int) ConfEdit {
+	return ConfEdit{
+		Hostname: hostname,
+		OldValue: strconv.Itoa(oldPort),
+		NewValue: strconv.Itoa(newPort),
+		Option: &idl.UpdateFileConfOptions{
+			Path:        filepath.Join(dataDir, "postgresql.conf"),
+			Pattern:     fmt.Sprintf(portPattern, oldPort),
+			Replacement: fmt.Sprintf(numberReplacement, newPort),
+			Reason:      ReasonPortRewrite,
+			Guc:         "port",
+			// postgres honors the last port set, so a port set more than
+			// once only has that one rewritten
+			DuplicateMatches: idl.UpdateFileConfOptions_rewriteLastMatch,
+			DataDir:          dataDir,
+		},
+	}
+}
+
+func postgresqlConfEdits(hostname string, intermediate *greenplum.Cluster, target *greenplum.Cluster) ConfPlan {
+	var edits ConfPlan
+
+	// add mirrors
+	target.ForEachSegment(func(seg *greenplum.SegConfig) bool {
+		return seg.IsOnHost(hostname) && seg.IsMirror() && confIncluded(seg)
+	}, func(mirror *greenplum.SegConfig) bool {
+		edits = append(edits, forSegment(portEdit(hostname, mirror.DataDir, intermediate.Primaries[mirror.ContentID].Port, mirror.Port), mirror))
+		return true
+	})
+
+	// add primaries
+	target.ForEachSegment(func(seg *greenplum.SegConfig) bool {
+		return seg.IsOnHost(hostname) && seg.IsPrimary() && confIncluded(seg)
+	}, func(primary *greenplum.SegConfig) bool {
+		edits = append(edits, forSegment(portEdit(hostname, primary.DataDir, intermediate.Primaries[primary.ContentID].Port, primary.Port), primary))
+		return true
+	})
+
+	return edits
+}
+
+func UpdateRecoveryConfOnSegments(ctx context.Context, agentConns []*idl.Connection, version semver.Version, intermediateCluster *greenplum.Cluster, target *greenplum.Cluster) error {
+	return updateRecoveryConfOnSegments(ctx, agentConns, nil, version, intermediateCluster, target)
+}
+
+func updateRecoveryConfOnSegments(ctx context.Context, agentConns []*idl.Connection, changes *confChanges, version semver.Version, intermediateCluster *greenplum.Cluster, target *greenplum.Cluster) error {
+	batch := NewConfBatch()
+	batch.AddRecoveryConf(version, intermediateCluster, target)
+	return batch.send(ctx, agentConns, changes)
+}
+
+// recoveryConfFile returns the file containing the primary_conninfo of a
+// mirror or standby.
+func recoveryConfFile(version semver.Version) string {
+	if version.Major == 6 {
+		return "recovery.conf"
+	}
+
+	return "postgresql.auto.conf"
+}
+
+// primaryConninfoPortPattern matches the port within the primary_conninfo of
+// a recovery.conf or postgresql.auto.conf. It anchors on the port keyword at
+// the start of the connection string or after whitespace or a quote, rather
+// than on whatever precedes it, so that IPv6 hosts and other numeric values
+// are never mistaken for the port. A port value quoted within the string is
+// also matched.
+const primaryConninfoPortPattern = `(primary_conninfo[ \t]*=?[ \t]*'(?:.*[ \t'])?port[ \t]*=[ \t]*(?:''|\\')?)%d([^0-9]|$)`
+
+// conninfoPortEdit rewrites the port within the primary_conninfo of the
+// recovery file at path.
+func conninfoPortEdit(hostname string, path string, oldPort int, newPort int) ConfEdit {
+	return ConfEdit{
+		Hostname: hostname,
+		OldValue: "port=" + strconv.Itoa(oldPort),
+		NewValue: "port=" + strconv.Itoa(newPort),
+		Option: &idl.UpdateFileConfOptions{
+			Path:        path,
+			Pattern:     fmt.Sprintf(primaryConninfoPortPattern, oldPort),
+			Replacement: fmt.Sprintf(numberReplacement, newPort),
+			Reason:      ReasonConninfoRewrite,
+			Guc:         "primary_conninfo",
+			// the conninfo is always in a file at the top of the data
+			// directory
+			DataDir: filepath.Dir(path),
+		},
+	}
+}
+
+// recoveryConfEdits errors rather than writing port=0 into primary_conninfo
+// when the state is inconsistent and a mirror has no target primary.
+func recoveryConfEdits(hostname string, version semver.Version, intermediateCluster *greenplum.Cluster, target *greenplum.Cluster) (ConfPlan, error) {
+	file := recoveryConfFile(version)
+
+	var edits ConfPlan
+	var err error
+
+	// add mirrors
+	target.ForEachSegment(func(seg *greenplum.SegConfig) bool {
+		return seg.IsOnHost(hostname) && seg.IsMirror() && confIncluded(seg)
+	}, func(mirror *greenplum.SegConfig) bool {
+		primary, ok := target.Primaries[mirror.ContentID]
+		if !ok {
+			err = xerrors.Errorf("mirror with content %d on host %s has no target primary", mirror.ContentID, hostname)
+			return false
+		}
+
+		edits = append(edits, forSegment(conninfoPortEdit(hostname, filepath.Join(mirror.DataDir, file), intermediateCluster.Primaries[mirror.ContentID].Port, primary.Port), mirror))
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return edits, nil
 }
 
 func UpdateInternalAutoConfOnMirrors(agentConns []*idl.Connection, intermediate *greenplum.Cluster) error {
-	pattern := `(^gp_dbid=)%d([^0-9]|$)`
-	replacement := `\1%d\2`
+	return updateInternalAutoConf(agentConns, func(hostname string) []*idl.UpdateFileConfOptions {
+		return internalAutoConfOptions(hostname, intermediate)
+	})
+}
 
-	request := func(conn *idl.Connection) error {
-		intermediateMirrors := intermediate.SelectSegments(func(seg *greenplum.SegConfig) bool {
-			return seg.IsOnHost(conn.Hostname) && !seg.IsStandby() && seg.IsMirror()
-		})
+// UpdateInternalAutoConfOnPrimaries rewrites the gp_dbid of the
+// internal.auto.conf of each intermediate primary to the dbid of the target
+// primary with its content, which differ after flows such as adding mirrors
+// or rebalancing. A primary would otherwise not start since its gp_dbid does
+// not match gp_segment_configuration. Hosts without primaries whose dbid
+// changed are not sent a request.
+func UpdateInternalAutoConfOnPrimaries(agentConns []*idl.Connection, intermediate *greenplum.Cluster, target *greenplum.Cluster) error {
+	return updateInternalAutoConf(agentConns, func(hostname string) []*idl.UpdateFileConfOptions {
+		return primaryInternalAutoConfOptions(hostname, intermediate, target)
+	})
+}
 
-		if len(intermediateMirrors) == 0 {
+func updateInternalAutoConf(agentConns []*idl.Connection, hostOptions func(hostname string) []*idl.UpdateFileConfOptions) error {
+	request := func(ctx context.Context, conn *idl.Connection) error {
+		var opts []*idl.UpdateFileConfOptions
+		for _, opt := range hostOptions(conn.Hostname) {
+			opts = append(opts, agentOption(opt))
+		}
+
+		if len(opts) == 0 {
 			return nil
 		}
 
-		var opts []*idl.UpdateFileConfOptions
-		for _, intermediateMirror := range intermediateMirrors {
-			opt := &idl.UpdateFileConfOptions{
-				Path:        filepath.Join(intermediateMirror.DataDir, "internal.auto.conf"),
-				Pattern:     fmt.Sprintf(pattern, intermediate.Primaries[intermediateMirror.ContentID].DbID),
-				Replacement: fmt.Sprintf(replacement, intermediateMirror.DbID),
-			}
+		req := &idl.UpdateConfigurationRequest{Options: opts}
+		update := func() error {
+			return withConfRPCTimeout(ctx, conn.Hostname, "update internal.auto.conf", func(ctx context.Context) error {
+				_, err := conn.AgentClient.UpdateConfiguration(ctx, req)
+				return err
+			})
+		}
+
+		if repeatableConfRequest(req) {
+			return retryRPC(ctx, conn.Hostname, update)
+		}
+
+		return update()
+	}
+
+	return executeConfRPC(context.Background(), agentConns, request, nil, FailOnUnreachable).Err()
+}
 
-			opts = append(opts, opt)
+// dbidPattern matches the gp_dbid line of an internal.auto.conf.
+const dbidPattern = `(^gp_dbid=)%d([^0-9]|$)`
 
+func internalAutoConfOptions(hostname string, intermediate *greenplum.Cluster) []*idl.UpdateFileConfOptions {
+	var opts []*idl.UpdateFileConfOptions
+
+	intermediate.ForEachSegment(func(seg *greenplum.SegConfig) bool {
+		return seg.IsOnHost(hostname) && !seg.IsStandby() && seg.IsMirror()
+	}, func(intermediateMirror *greenplum.SegConfig) bool {
+		opts = append(opts, dbidOption(intermediateMirror.DataDir, intermediate.Primaries[intermediateMirror.ContentID].DbID, intermediateMirror.DbID))
+		return true
+	})
+
+	return opts
+}
+
+func primaryInternalAutoConfOptions(hostname string, intermediate *greenplum.Cluster, target *greenplum.Cluster) []*idl.UpdateFileConfOptions {
+	var opts []*idl.UpdateFileConfOptions
+
+	intermediate.ForEachSegment(func(seg *greenplum.SegConfig) bool {
+		return seg.IsOnHost(hostname) && !seg.IsCoordinator() && seg.IsPrimary()
+	}, func(intermediatePrimary *greenplum.SegConfig) bool {
+		targetPrimary, ok := target.Primaries[intermediatePrimary.ContentID]
+		if ok && targetPrimary.DbID != intermediatePrimary.DbID {
+			opts = append(opts, dbidOption(intermediatePrimary.DataDir, intermediatePrimary.DbID, targetPrimary.DbID))
 		}
+		return true
+	})
 
-		req := &idl.UpdateConfigurationRequest{Options: opts}
-		_, err := conn.AgentClient.UpdateConfiguration(context.Background(), req)
-		return err
+	return opts
+}
+
+// dbidOption rewrites the gp_dbid of the internal.auto.conf of a data
+// directory.
+func dbidOption(dataDir string, oldDbID int, newDbID int) *idl.UpdateFileConfOptions {
+	return &idl.UpdateFileConfOptions{
+		Path:          filepath.Join(dataDir, "internal.auto.conf"),
+		Pattern:       fmt.Sprintf(dbidPattern, oldDbID),
+		Replacement:   fmt.Sprintf(numberReplacement, newDbID),
+		Reason:        ReasonDbidRewrite,
+		Guc:           "gp_dbid",
+		ExpectedValue: strconv.Itoa(newDbID),
+		DataDir:       dataDir,
 	}
+}
 
-	return ExecuteRPC(agentConns, request)
+func UpdateConfigurationFile(ctx context.Context, opts []*idl.UpdateFileConfOptions) error {
+	_, err := UpdateConfigurationFileChanges(ctx, opts)
+	return err
 }
 
-func UpdateConfigurationFile(opts []*idl.UpdateFileConfOptions) error {
-	var wg sync.WaitGroup
-	errs := make(chan error, len(opts))
+// UpdateConfigurationFileChanges updates the conf files and returns the sorted
+// paths of those whose contents changed. A file already at the expected value
+// of an option whose pattern no longer matches is left unchanged. Edits
+// skipped as their precondition was not met are logged.
+func UpdateConfigurationFileChanges(ctx context.Context, opts []*idl.UpdateFileConfOptions) ([]string, error) {
+	changed, skipped, err := UpdateConfigurationFileResult(ctx, opts)
+	for _, skip := range skipped {
+		log.Printf("skipped %s", skip)
+	}
+
+	return changed, err
+}
+
+// skippedEditMessage describes an edit skipped as its precondition was not
+// met.
+func skippedEditMessage(path string, opt *idl.UpdateFileConfOptions) string {
+	return fmt.Sprintf("%s%s: %s is not %q: precondition not met", path, reasonSuffix(opt), opt.GetGuc(), opt.GetMatchCurrentValue())
+}
+
+// satisfiedEditMessage describes an edit skipped as its GUC already had its
+// expected value.
+func satisfiedEditMessage(path string, opt *idl.UpdateFileConfOptions) string {
+	return fmt.Sprintf("%s%s: %s is already %q", path, reasonSuffix(opt), opt.GetGuc(), opt.GetExpectedValue())
+}
+
+// UpdateConfigurationFileResult updates the conf files and returns both the
+// sorted paths of those whose contents changed and a description of each
+// edit skipped as the GUC did not have its MatchCurrentValue.
+//
+// Canceling ctx stops the edits of each file between options. Since the
+// edits of a file are made in memory and written at once, a file whose edits
+// were interrupted is left untouched and without a new backup.
+func UpdateConfigurationFileResult(ctx context.Context, opts []*idl.UpdateFileConfOptions) ([]string, []string, error) {
+	reply, err := UpdateConfigurationFileReply(ctx, opts)
+	return reply.GetChangedPaths(), reply.GetSkipped(), err
+}
+
+// UpdateConfigurationFileReply is UpdateConfigurationFileResult also
+// returning the lines changed by each option, which are those of the files
+// written.
+func UpdateConfigurationFileReply(ctx context.Context, opts []*idl.UpdateFileConfOptions) (*idl.UpdateConfigurationReply, error) {
+	return editConfigurationFiles(ctx, opts, false, 0)
+}
 
-	for _, opt := range opts {
+// PreviewConfigurationFile returns a unified diff of each conf file the
+// options would change, sorted by path, along with the edits that would be
+// skipped, without backing up or writing any file.
+func PreviewConfigurationFile(ctx context.Context, opts []*idl.UpdateFileConfOptions) ([]*idl.ConfFileDiff, []string, error) {
+	reply, err := editConfigurationFiles(ctx, opts, true, 0)
+	return reply.GetDiffs(), reply.GetSkipped(), err
+}
+
+// confFileConcurrency caps the number of conf files edited at once.
+var confFileConcurrency = defaultConfFileConcurrency()
 
+func defaultConfFileConcurrency() int {
+	return runtime.NumCPU() * 2
+}
+
+func SetConfFileConcurrency(concurrency int) {
+	confFileConcurrency = concurrency
+}
+
+func ResetConfFileConcurrency() {
+	confFileConcurrency = defaultConfFileConcurrency()
+}
+
+// confFileWorkers returns the number of workers editing the files, which is
+// at least one and at most one per file. A positive concurrency, such as the
+// share of the edit budget sent by the hub, takes precedence over
+// confFileConcurrency.
+func confFileWorkers(files int, concurrency int) int {
+	if concurrency <= 0 {
+		concurrency = confFileConcurrency
+	}
+
+	return min(max(concurrency, 1), files)
+}
+
+func editConfigurationFiles(ctx context.Context, opts []*idl.UpdateFileConfOptions, dryRun bool, concurrency int) (*idl.UpdateConfigurationReply, error) {
+	defer beginConfUpdate()()
+
+	// no file is edited when any path escapes its data directory
+	if err := checkConfPathsWithinDataDirs(opts); err != nil {
+		return &idl.UpdateConfigurationReply{}, err
+	}
+
+	// Files are updated concurrently, but the options of a file are applied
+	// in order to its contents in memory, which are then backed up and
+	// written once.
+	var paths []string
+	fileOpts := make(map[string][]*idl.UpdateFileConfOptions)
+	optIndex := make(map[*idl.UpdateFileConfOptions]int)
+	for i, opt := range opts {
+		optIndex[opt] = i
+		if _, ok := fileOpts[opt.GetPath()]; !ok {
+			paths = append(paths, opt.GetPath())
+		}
+		fileOpts[opt.GetPath()] = append(fileOpts[opt.GetPath()], opt)
+	}
+
+	type result struct {
+		path  string
+		reply *idl.UpdateConfigurationReply
+		err   error
+	}
+
+	var wg sync.WaitGroup
+	results := make(chan result, len(paths))
+
+	// a fixed pool of workers edits the files so that a host with many
+	// segments does not edit them all at once
+	work := make(chan string)
+	for i := 0; i < confFileWorkers(len(paths), concurrency); i++ {
 		wg.Add(1)
-		go func(opt *idl.UpdateFileConfOptions) {
+		go func() {
 			defer wg.Done()
 
-			cmd := exec.Command("sed", "-E", "-i.bak",
-				fmt.Sprintf(`s@%s@%s@`, opt.GetPattern(), opt.GetReplacement()),
-				opt.GetPath(),
-			)
-
-			output, err := cmd.CombinedOutput()
-			if err != nil {
-				errs <- xerrors.Errorf("update %s using %q failed with %q: %w", filepath.Base(opt.GetPath()), cmd.String(), string(output), err)
+			for path := range work {
+				reply, err := confEditor.Edit(ctx, path, fileOpts[path], dryRun)
+				results <- result{path, reply, err}
 			}
-		}(opt)
+		}()
 	}
 
+	for _, path := range paths {
+		work <- path
+	}
+	close(work)
+
 	wg.Wait()
-	close(errs)
+	close(results)
 
 	var err error
-	for e := range errs {
-		err = errorlist.Append(err, e)
+	reply := &idl.UpdateConfigurationReply{}
+	for r := range results {
+		err = errorlist.Append(err, r.err)
+
+		reply.ChangedPaths = append(reply.ChangedPaths, r.reply.GetChangedPaths()...)
+		reply.Skipped = append(reply.Skipped, r.reply.GetSkipped()...)
+		reply.Satisfied = append(reply.Satisfied, r.reply.GetSatisfied()...)
+		reply.Warnings = append(reply.Warnings, r.reply.GetWarnings()...)
+		reply.Diffs = append(reply.Diffs, r.reply.GetDiffs()...)
+		reply.Checksums = append(reply.Checksums, r.reply.GetChecksums()...)
+		for path, resolved := range r.reply.GetResolvedPaths() {
+			if reply.ResolvedPaths == nil {
+				reply.ResolvedPaths = make(map[string]string)
+			}
+			reply.ResolvedPaths[path] = resolved
+		}
+
+		// the editor numbers the lines by the options of their file
+		for _, line := range r.reply.GetChangedLines() {
+			if int(line.GetOption()) < len(fileOpts[r.path]) {
+				line.Option = int32(optIndex[fileOpts[r.path][line.GetOption()]])
+			}
+			reply.ChangedLines = append(reply.ChangedLines, line)
+		}
 	}
 
-	return err
+	sort.Strings(reply.ChangedPaths)
+	sort.Strings(reply.Skipped)
+	sort.Strings(reply.Satisfied)
+	sort.Strings(reply.Warnings)
+	sort.Slice(reply.Diffs, func(i, j int) bool {
+		return reply.Diffs[i].GetPath() < reply.Diffs[j].GetPath()
+	})
+	sort.SliceStable(reply.ChangedLines, func(i, j int) bool {
+		return reply.ChangedLines[i].GetOption() < reply.ChangedLines[j].GetOption()
+	})
+	sort.Slice(reply.Checksums, func(i, j int) bool {
+		return reply.Checksums[i].GetPath() < reply.Checksums[j].GetPath()
+	})
+
+	return reply, err
+}
+
+// checkCurrentValue returns whether the GUC of an option with a
+// MatchCurrentValue precondition currently has that value in contents, the
+// file as edited by the preceding options of the file.
+func checkCurrentValue(path string, contents []byte, opt *idl.UpdateFileConfOptions) (bool, error) {
+	if opt.GetMatchCurrentValue() == "" {
+		return true, nil
+	}
+
+	if opt.GetGuc() == "" {
+		return false, xerrors.Errorf("update %s%s: a current value precondition requires the GUC to check", filepath.Base(path), reasonSuffix(opt))
+	}
+
+	settings, err := parseConfSettings(bytes.NewReader(contents))
+	if err != nil {
+		return false, xerrors.Errorf("update %s%s: %w", filepath.Base(path), reasonSuffix(opt), err)
+	}
+
+	return settings[strings.ToLower(opt.GetGuc())] == opt.GetMatchCurrentValue(), nil
+}
+
+// checkExpectedMatches errors when an option expecting a number of matching
+// lines does not match exactly that many lines of contents.
+func checkExpectedMatches(path string, contents []byte, opt *idl.UpdateFileConfOptions) error {
+	if opt.GetExpectedMatches() <= 0 {
+		return nil
+	}
+
+	pattern, err := regexp.Compile(opt.GetPattern())
+	if err != nil {
+		return confCompileError(path, opt, err)
+	}
+
+	matches := 0
+	for _, line := range confLines(string(contents)) {
+		if pattern.MatchString(line) {
+			matches++
+		}
+	}
+
+	if matches != int(opt.GetExpectedMatches()) {
+		return confEditError(path, opt, fmt.Sprintf("matched %d lines but expected %d", matches, opt.GetExpectedMatches()), matchingConfSnippet(contents, pattern))
+	}
+
+	return nil
+}
+
+// reasonSuffix describes the source of an edit for error messages.
+func reasonSuffix(opt *idl.UpdateFileConfOptions) string {
+	if opt.GetReason() == "" {
+		return ""
+	}
+
+	return fmt.Sprintf(" for %s", opt.GetReason())
 }
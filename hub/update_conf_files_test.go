@@ -4,21 +4,36 @@
 package hub_test
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
-	"os/exec"
+	"os"
 	"path/filepath"
+	"reflect"
+	"sort"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/blang/semver/v4"
 	"github.com/golang/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 
+	"github.com/greenplum-db/gpupgrade/config"
 	"github.com/greenplum-db/gpupgrade/greenplum"
 	"github.com/greenplum-db/gpupgrade/hub"
 	"github.com/greenplum-db/gpupgrade/idl"
 	"github.com/greenplum-db/gpupgrade/idl/mock_idl"
+	"github.com/greenplum-db/gpupgrade/step"
 	"github.com/greenplum-db/gpupgrade/testutils"
+	"github.com/greenplum-db/gpupgrade/testutils/testlog"
+	"github.com/greenplum-db/gpupgrade/utils"
 	"github.com/greenplum-db/gpupgrade/utils/errorlist"
 )
 
@@ -41,59 +56,68 @@ func TestUpdatePostgresqlConfOnSegments(t *testing.T) {
 		{DbID: 6, ContentID: 1, Hostname: "sdw1", DataDir: "/data/dbfast_mirror2/seg2", Port: 25436, Role: greenplum.MirrorRole},
 	})
 
-	pattern := `(^port[ \t]*=[ \t]*)%d([^0-9]|$)`
+	pattern := `(^[ \t]*port[ \t]*=[ \t]*)%d([^0-9]|$)`
 	replacement := `\1%d\2`
 
 	t.Run("updates postgresql.conf on segments", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
-		standby := mock_idl.NewMockAgentClient(ctrl)
-		standby.EXPECT().UpdateConfiguration(
-			gomock.Any(),
-			&idl.UpdateConfigurationRequest{
-				Options: []*idl.UpdateFileConfOptions{{
-					Path:        "/data/standby/postgresql.conf",
-					Pattern:     fmt.Sprintf(pattern, 50433),
-					Replacement: fmt.Sprintf(replacement, 16432),
-				}},
-			},
-		).Return(&idl.UpdateConfigurationReply{}, nil)
-
+		standby := mock_idl.NewMockAgentClient(ctrl) // updated by UpdateStandbyConfFiles
 		sdw1 := mock_idl.NewMockAgentClient(ctrl)
 		sdw1.EXPECT().UpdateConfiguration(
 			gomock.Any(),
-			&idl.UpdateConfigurationRequest{
+			equivalentConfRequest(&idl.UpdateConfigurationRequest{
 				Options: []*idl.UpdateFileConfOptions{
 					{
-						Path:        "/data/dbfast_mirror2/seg2/postgresql.conf",
-						Pattern:     fmt.Sprintf(pattern, 50436),
-						Replacement: fmt.Sprintf(replacement, 25436),
+						Path:             "/data/dbfast_mirror2/seg2/postgresql.conf",
+						DataDir:          "/data/dbfast_mirror2/seg2",
+						Pattern:          fmt.Sprintf(pattern, 50436),
+						Replacement:      fmt.Sprintf(replacement, 25436),
+						Reason:           hub.ReasonPortRewrite,
+						Guc:              "port",
+						DuplicateMatches: idl.UpdateFileConfOptions_rewriteLastMatch,
+						ExpectedValue:    "25436",
 					},
 					{
-						Path:        "/data/dbfast1/seg1/postgresql.conf",
-						Pattern:     fmt.Sprintf(pattern, 50434),
-						Replacement: fmt.Sprintf(replacement, 25433),
+						Path:             "/data/dbfast1/seg1/postgresql.conf",
+						DataDir:          "/data/dbfast1/seg1",
+						Pattern:          fmt.Sprintf(pattern, 50434),
+						Replacement:      fmt.Sprintf(replacement, 25433),
+						Reason:           hub.ReasonPortRewrite,
+						Guc:              "port",
+						DuplicateMatches: idl.UpdateFileConfOptions_rewriteLastMatch,
+						ExpectedValue:    "25433",
 					}},
-			},
+			}),
 		).Return(&idl.UpdateConfigurationReply{}, nil)
 
 		sdw2 := mock_idl.NewMockAgentClient(ctrl)
 		sdw2.EXPECT().UpdateConfiguration(
 			gomock.Any(),
-			&idl.UpdateConfigurationRequest{
+			equivalentConfRequest(&idl.UpdateConfigurationRequest{
 				Options: []*idl.UpdateFileConfOptions{
 					{
-						Path:        "/data/dbfast_mirror1/seg1/postgresql.conf",
-						Pattern:     fmt.Sprintf(pattern, 50434),
-						Replacement: fmt.Sprintf(replacement, 25434),
+						Path:             "/data/dbfast_mirror1/seg1/postgresql.conf",
+						DataDir:          "/data/dbfast_mirror1/seg1",
+						Pattern:          fmt.Sprintf(pattern, 50434),
+						Replacement:      fmt.Sprintf(replacement, 25434),
+						Reason:           hub.ReasonPortRewrite,
+						Guc:              "port",
+						DuplicateMatches: idl.UpdateFileConfOptions_rewriteLastMatch,
+						ExpectedValue:    "25434",
 					},
 					{
-						Path:        "/data/dbfast2/seg2/postgresql.conf",
-						Pattern:     fmt.Sprintf(pattern, 50436),
-						Replacement: fmt.Sprintf(replacement, 25435),
+						Path:             "/data/dbfast2/seg2/postgresql.conf",
+						DataDir:          "/data/dbfast2/seg2",
+						Pattern:          fmt.Sprintf(pattern, 50436),
+						Replacement:      fmt.Sprintf(replacement, 25435),
+						Reason:           hub.ReasonPortRewrite,
+						Guc:              "port",
+						DuplicateMatches: idl.UpdateFileConfOptions_rewriteLastMatch,
+						ExpectedValue:    "25435",
 					}},
-			},
+			}),
 		).Return(&idl.UpdateConfigurationReply{}, nil)
 
 		agentConns := []*idl.Connection{
@@ -102,28 +126,221 @@ func TestUpdatePostgresqlConfOnSegments(t *testing.T) {
 			{AgentClient: sdw2, Hostname: "sdw2"},
 		}
 
-		err := hub.UpdatePostgresqlConfOnSegments(agentConns, intermediate, target)
+		err := hub.UpdatePostgresqlConfOnSegments(context.Background(), agentConns, intermediate, target)
 		if err != nil {
 			t.Errorf("unexpected err %#v", err)
 		}
 	})
 
-	t.Run("returns errors when failing to update postgresql.conf on segments", func(t *testing.T) {
+	t.Run("verifies the values read back in the same request", func(t *testing.T) {
+		hub.SetVerifyAfterWrite(true)
+		defer hub.ResetVerifyAfterWrite()
+
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
-		standby := mock_idl.NewMockAgentClient(ctrl)
-		standby.EXPECT().UpdateConfiguration(
+		reply := func(ports map[string]string) func(context.Context, *idl.UpdateConfigurationRequest, ...grpc.CallOption) (*idl.UpdateConfigurationReply, error) {
+			return func(_ context.Context, req *idl.UpdateConfigurationRequest, _ ...grpc.CallOption) (*idl.UpdateConfigurationReply, error) {
+				if !req.GetVerifyAfterWrite() {
+					t.Errorf("expected the request to verify after writing")
+				}
+
+				reply := &idl.UpdateConfigurationReply{}
+				for _, opt := range req.GetOptions() {
+					reply.Values = append(reply.Values, &idl.ReadConfigurationReply_Value{Path: opt.GetPath(), Name: opt.GetGuc(), Value: ports[opt.GetPath()], Found: true})
+				}
+				return reply, nil
+			}
+		}
+
+		standby := mock_idl.NewMockAgentClient(ctrl) // updated by UpdateStandbyConfFiles
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw1.EXPECT().UpdateConfiguration(gomock.Any(), gomock.Any()).DoAndReturn(reply(map[string]string{
+			"/data/dbfast_mirror2/seg2/postgresql.conf": "25436",
+			"/data/dbfast1/seg1/postgresql.conf":        "25433",
+		}))
+
+		sdw2 := mock_idl.NewMockAgentClient(ctrl)
+		sdw2.EXPECT().UpdateConfiguration(gomock.Any(), gomock.Any()).DoAndReturn(reply(map[string]string{
+			"/data/dbfast_mirror1/seg1/postgresql.conf": "25434",
+			"/data/dbfast2/seg2/postgresql.conf":        "50436",
+		}))
+
+		agentConns := []*idl.Connection{
+			{AgentClient: standby, Hostname: "standby"},
+			{AgentClient: sdw1, Hostname: "sdw1"},
+			{AgentClient: sdw2, Hostname: "sdw2"},
+		}
+
+		err := hub.UpdatePostgresqlConfOnSegments(context.Background(), agentConns, intermediate, target)
+		expected := "port in /data/dbfast2/seg2/postgresql.conf on host sdw2 is 50436 after writing but expected 25435"
+		if err == nil || err.Error() != expected {
+			t.Errorf("got error %v want %q", err, expected)
+		}
+	})
+
+	t.Run("updates a primary and mirror on the same host each with its own port", func(t *testing.T) {
+		intermediate := hub.MustCreateCluster(t, greenplum.SegConfigs{
+			{DbID: 1, ContentID: -1, Hostname: "sdw1", DataDir: "/data/qddir/seg.HqtFHX54y0o.-1", Port: 50432, Role: greenplum.PrimaryRole},
+			{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg.HqtFHX54y0o.1", Port: 50434, Role: greenplum.PrimaryRole},
+			{DbID: 3, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast_mirror1/seg.HqtFHX54y0o.1", Port: 50435, Role: greenplum.MirrorRole},
+		})
+
+		target := hub.MustCreateCluster(t, greenplum.SegConfigs{
+			{DbID: 1, ContentID: -1, Hostname: "sdw1", DataDir: "/data/qddir/seg-1", Port: 15432, Role: greenplum.PrimaryRole},
+			{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg1", Port: 25433, Role: greenplum.PrimaryRole},
+			{DbID: 3, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast_mirror1/seg1", Port: 25434, Role: greenplum.MirrorRole},
+		})
+
+		log := testlog.SetupTestLogger()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw1.EXPECT().UpdateConfiguration(
 			gomock.Any(),
-			&idl.UpdateConfigurationRequest{
-				Options: []*idl.UpdateFileConfOptions{{
-					Path:        "/data/standby/postgresql.conf",
-					Pattern:     fmt.Sprintf(pattern, 50433),
-					Replacement: fmt.Sprintf(replacement, 16432),
-				}},
+			equivalentConfRequest(&idl.UpdateConfigurationRequest{
+				Options: []*idl.UpdateFileConfOptions{
+					{
+						Path:             "/data/dbfast_mirror1/seg1/postgresql.conf",
+						DataDir:          "/data/dbfast_mirror1/seg1",
+						Pattern:          fmt.Sprintf(pattern, 50434),
+						Replacement:      fmt.Sprintf(replacement, 25434),
+						Reason:           hub.ReasonPortRewrite,
+						Guc:              "port",
+						DuplicateMatches: idl.UpdateFileConfOptions_rewriteLastMatch,
+						ExpectedValue:    "25434",
+					},
+					{
+						Path:             "/data/dbfast1/seg1/postgresql.conf",
+						DataDir:          "/data/dbfast1/seg1",
+						Pattern:          fmt.Sprintf(pattern, 50434),
+						Replacement:      fmt.Sprintf(replacement, 25433),
+						Reason:           hub.ReasonPortRewrite,
+						Guc:              "port",
+						DuplicateMatches: idl.UpdateFileConfOptions_rewriteLastMatch,
+						ExpectedValue:    "25433",
+					}},
+			}),
+		).Return(&idl.UpdateConfigurationReply{ChangedPaths: []string{"/data/dbfast1/seg1/postgresql.conf"}}, nil)
+
+		err := hub.UpdatePostgresqlConfOnSegments(context.Background(), []*idl.Connection{{AgentClient: sdw1, Hostname: "sdw1"}}, intermediate, target)
+		if err != nil {
+			t.Errorf("unexpected err %#v", err)
+		}
+
+		testlog.VerifyLogContains(t, log, "primary with content 0 on host sdw1: updated port in /data/dbfast1/seg1/postgresql.conf from 50434 to 25433")
+		testlog.VerifyLogContains(t, log, "mirror with content 0 on host sdw1: left port in /data/dbfast_mirror1/seg1/postgresql.conf unchanged")
+	})
+
+	t.Run("errors when a primary and mirror on the same host share a data directory", func(t *testing.T) {
+		intermediate := hub.MustCreateCluster(t, greenplum.SegConfigs{
+			{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: "/data/qddir/seg.HqtFHX54y0o.-1", Port: 50432, Role: greenplum.PrimaryRole},
+			{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg.HqtFHX54y0o.1", Port: 50434, Role: greenplum.PrimaryRole},
+			{DbID: 3, ContentID: 1, Hostname: "sdw1", DataDir: "/data/dbfast2/seg.HqtFHX54y0o.2", Port: 50435, Role: greenplum.PrimaryRole},
+		})
+
+		target := hub.MustCreateCluster(t, greenplum.SegConfigs{
+			{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: "/data/qddir/seg-1", Port: 15432, Role: greenplum.PrimaryRole},
+			{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg1", Port: 25433, Role: greenplum.PrimaryRole},
+			{DbID: 3, ContentID: 1, Hostname: "sdw1", DataDir: "/data/dbfast2/seg2", Port: 25434, Role: greenplum.PrimaryRole},
+			{DbID: 4, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast2/seg2", Port: 25435, Role: greenplum.MirrorRole},
+		})
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+
+		err := hub.UpdatePostgresqlConfOnSegments(context.Background(), []*idl.Connection{{AgentClient: sdw1, Hostname: "sdw1"}}, intermediate, target)
+		expected := "mirror with content 0 and primary with content 1 on host sdw1 both edit port in /data/dbfast2/seg2/postgresql.conf"
+		if err == nil || err.Error() != expected {
+			t.Errorf("got error %v want %q", err, expected)
+		}
+	})
+
+	t.Run("retries a host while its agent is unavailable", func(t *testing.T) {
+		hub.SetRPCRetryPolicy(hub.RetryPolicy{Attempts: 2, Sleep: func(context.Context, time.Duration) error { return nil }})
+		defer hub.ResetRPCRetryPolicy()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		standby := mock_idl.NewMockAgentClient(ctrl) // updated by UpdateStandbyConfFiles
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		gomock.InOrder(
+			sdw1.EXPECT().UpdateConfiguration(gomock.Any(), gomock.Any()).Return(nil, status.Error(codes.Unavailable, "connection refused")),
+			sdw1.EXPECT().UpdateConfiguration(gomock.Any(), gomock.Any()).Return(&idl.UpdateConfigurationReply{}, nil),
+		)
+
+		sdw2 := mock_idl.NewMockAgentClient(ctrl)
+		sdw2.EXPECT().UpdateConfiguration(gomock.Any(), gomock.Any()).Return(&idl.UpdateConfigurationReply{}, nil)
+
+		agentConns := []*idl.Connection{
+			{AgentClient: standby, Hostname: "standby"},
+			{AgentClient: sdw1, Hostname: "sdw1"},
+			{AgentClient: sdw2, Hostname: "sdw2"},
+		}
+
+		err := hub.UpdatePostgresqlConfOnSegments(context.Background(), agentConns, intermediate, target)
+		if err != nil {
+			t.Errorf("unexpected err %#v", err)
+		}
+	})
+
+	t.Run("errors when the files read back on a host miss edits", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		standby := mock_idl.NewMockAgentClient(ctrl) // updated by UpdateStandbyConfFiles
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw1.EXPECT().UpdateConfiguration(gomock.Any(), gomock.Any()).Return(&idl.UpdateConfigurationReply{
+			Checksums: []*idl.ConfFileChecksum{
+				{Path: "/data/dbfast1/seg1/postgresql.conf", Sha256: "5e8f", MatchedOptions: 0},
+				{Path: "/data/dbfast_mirror2/seg2/postgresql.conf", Sha256: "9a0c", MatchedOptions: 1},
 			},
-		).Return(&idl.UpdateConfigurationReply{}, nil)
+		}, nil)
+
+		sdw2 := mock_idl.NewMockAgentClient(ctrl)
+		sdw2.EXPECT().UpdateConfiguration(gomock.Any(), gomock.Any()).Return(&idl.UpdateConfigurationReply{
+			Checksums: []*idl.ConfFileChecksum{
+				{Path: "/data/dbfast_mirror1/seg1/postgresql.conf", Sha256: "17b2", MatchedOptions: 1},
+			},
+		}, nil)
+
+		agentConns := []*idl.Connection{
+			{AgentClient: standby, Hostname: "standby"},
+			{AgentClient: sdw1, Hostname: "sdw1"},
+			{AgentClient: sdw2, Hostname: "sdw2"},
+		}
+
+		err := hub.UpdatePostgresqlConfOnSegments(context.Background(), agentConns, intermediate, target)
+		var errs errorlist.Errors
+		if !errors.As(err, &errs) {
+			t.Fatalf("error %#v does not contain type %T", err, errs)
+		}
+
+		expected := []string{
+			"/data/dbfast1/seg1/postgresql.conf on host sdw1 has 0 of its 1 edits in effect after the update (sha256 5e8f)",
+			"/data/dbfast2/seg2/postgresql.conf on host sdw2 was not read back after the update",
+		}
+		var actual []string
+		for _, err := range errs {
+			actual = append(actual, err.Error())
+		}
+		sort.Strings(actual)
 
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("got errors %q want %q", actual, expected)
+		}
+	})
+
+	t.Run("returns errors when failing to update postgresql.conf on segments", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		standby := mock_idl.NewMockAgentClient(ctrl) // updated by UpdateStandbyConfFiles
 		expected := errors.New("permission denied")
 		sdw1 := mock_idl.NewMockAgentClient(ctrl)
 		sdw1.EXPECT().UpdateConfiguration(
@@ -143,7 +360,7 @@ func TestUpdatePostgresqlConfOnSegments(t *testing.T) {
 			{AgentClient: sdw2, Hostname: "sdw2"},
 		}
 
-		err := hub.UpdatePostgresqlConfOnSegments(agentConns, intermediate, target)
+		err := hub.UpdatePostgresqlConfOnSegments(context.Background(), agentConns, intermediate, target)
 		var errs errorlist.Errors
 		if !errors.As(err, &errs) {
 			t.Fatalf("error %#v does not contain type %T", err, errs)
@@ -180,7 +397,7 @@ func TestUpdateRecoveryConfOnSegments(t *testing.T) {
 		{DbID: 6, ContentID: 1, Hostname: "sdw1", DataDir: "/data/dbfast_mirror2/seg2", Port: 25436, Role: greenplum.MirrorRole},
 	})
 
-	pattern := `(primary_conninfo .* port[ \t]*=[ \t]*)%d([^0-9]|$)`
+	pattern := `(primary_conninfo[ \t]*=?[ \t]*'(?:.*[ \t'])?port[ \t]*=[ \t]*(?:''|\\')?)%d([^0-9]|$)`
 	replacement := `\1%d\2`
 
 	cases := []struct {
@@ -205,42 +422,39 @@ func TestUpdateRecoveryConfOnSegments(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
 
-			standby := mock_idl.NewMockAgentClient(ctrl)
-			standby.EXPECT().UpdateConfiguration(
-				gomock.Any(),
-				&idl.UpdateConfigurationRequest{
-					Options: []*idl.UpdateFileConfOptions{{
-						Path:        filepath.Join("/data/standby", c.file),
-						Pattern:     fmt.Sprintf(pattern, 50432),
-						Replacement: fmt.Sprintf(replacement, 15432),
-					}},
-				},
-			).Return(&idl.UpdateConfigurationReply{}, nil)
-
+			standby := mock_idl.NewMockAgentClient(ctrl) // updated by UpdateStandbyConfFiles
 			sdw1 := mock_idl.NewMockAgentClient(ctrl)
 			sdw1.EXPECT().UpdateConfiguration(
 				gomock.Any(),
-				&idl.UpdateConfigurationRequest{
+				equivalentConfRequest(&idl.UpdateConfigurationRequest{
 					Options: []*idl.UpdateFileConfOptions{
 						{
-							Path:        filepath.Join("/data/dbfast_mirror2/seg2", c.file),
-							Pattern:     fmt.Sprintf(pattern, 50436),
-							Replacement: fmt.Sprintf(replacement, 25435),
+							Path:          filepath.Join("/data/dbfast_mirror2/seg2", c.file),
+							DataDir:       "/data/dbfast_mirror2/seg2",
+							Pattern:       fmt.Sprintf(pattern, 50436),
+							Replacement:   fmt.Sprintf(replacement, 25435),
+							Reason:        hub.ReasonConninfoRewrite,
+							Guc:           "primary_conninfo",
+							ExpectedValue: "port=25435",
 						}},
-				},
+				}),
 			).Return(&idl.UpdateConfigurationReply{}, nil)
 
 			sdw2 := mock_idl.NewMockAgentClient(ctrl)
 			sdw2.EXPECT().UpdateConfiguration(
 				gomock.Any(),
-				&idl.UpdateConfigurationRequest{
+				equivalentConfRequest(&idl.UpdateConfigurationRequest{
 					Options: []*idl.UpdateFileConfOptions{
 						{
-							Path:        filepath.Join("/data/dbfast_mirror1/seg1", c.file),
-							Pattern:     fmt.Sprintf(pattern, 50434),
-							Replacement: fmt.Sprintf(replacement, 25433),
+							Path:          filepath.Join("/data/dbfast_mirror1/seg1", c.file),
+							DataDir:       "/data/dbfast_mirror1/seg1",
+							Pattern:       fmt.Sprintf(pattern, 50434),
+							Replacement:   fmt.Sprintf(replacement, 25433),
+							Reason:        hub.ReasonConninfoRewrite,
+							Guc:           "primary_conninfo",
+							ExpectedValue: "port=25433",
 						}},
-				},
+				}),
 			).Return(&idl.UpdateConfigurationReply{}, nil)
 
 			agentConns := []*idl.Connection{
@@ -249,7 +463,7 @@ func TestUpdateRecoveryConfOnSegments(t *testing.T) {
 				{AgentClient: sdw2, Hostname: "sdw2"},
 			}
 
-			err := hub.UpdateRecoveryConfOnSegments(agentConns, c.version, intermediate, target)
+			err := hub.UpdateRecoveryConfOnSegments(context.Background(), agentConns, c.version, intermediate, target)
 			if err != nil {
 				t.Errorf("unexpected err %#v", err)
 			}
@@ -260,18 +474,7 @@ func TestUpdateRecoveryConfOnSegments(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
-		standby := mock_idl.NewMockAgentClient(ctrl)
-		standby.EXPECT().UpdateConfiguration(
-			gomock.Any(),
-			&idl.UpdateConfigurationRequest{
-				Options: []*idl.UpdateFileConfOptions{{
-					Path:        "/data/standby/recovery.conf",
-					Pattern:     fmt.Sprintf(pattern, 50432),
-					Replacement: fmt.Sprintf(replacement, 15432),
-				}},
-			},
-		).Return(&idl.UpdateConfigurationReply{}, nil)
-
+		standby := mock_idl.NewMockAgentClient(ctrl) // updated by UpdateStandbyConfFiles
 		expected := errors.New("permission denied")
 		sdw1 := mock_idl.NewMockAgentClient(ctrl)
 		sdw1.EXPECT().UpdateConfiguration(
@@ -291,7 +494,7 @@ func TestUpdateRecoveryConfOnSegments(t *testing.T) {
 			{AgentClient: sdw2, Hostname: "sdw2"},
 		}
 
-		err := hub.UpdateRecoveryConfOnSegments(agentConns, semver.MustParse("6.0.0"), intermediate, target)
+		err := hub.UpdateRecoveryConfOnSegments(context.Background(), agentConns, semver.MustParse("6.0.0"), intermediate, target)
 		var errs errorlist.Errors
 		if !errors.As(err, &errs) {
 			t.Fatalf("error %#v does not contain type %T", err, errs)
@@ -307,6 +510,177 @@ func TestUpdateRecoveryConfOnSegments(t *testing.T) {
 			}
 		}
 	})
+
+	t.Run("errors when a mirror has no target primary", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// sdw1 has no mirrors and sdw2 must not be updated with port=0
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw2 := mock_idl.NewMockAgentClient(ctrl)
+
+		agentConns := []*idl.Connection{
+			{AgentClient: sdw1, Hostname: "sdw1"},
+			{AgentClient: sdw2, Hostname: "sdw2"},
+		}
+
+		inconsistent := hub.MustCreateCluster(t, greenplum.SegConfigs{
+			{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: "/data/qddir/seg-1", Port: 15432, Role: greenplum.PrimaryRole},
+			{DbID: 4, ContentID: 0, Hostname: "sdw2", DataDir: "/data/dbfast_mirror1/seg1", Port: 25434, Role: greenplum.MirrorRole},
+		})
+
+		err := hub.UpdateRecoveryConfOnSegments(context.Background(), agentConns, semver.MustParse("6.0.0"), intermediate, inconsistent)
+		expected := "mirror with content 0 on host sdw2 has no target primary"
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("expected error %v to contain %q", err, expected)
+		}
+	})
+}
+
+func TestUpdateStandbyConfFiles(t *testing.T) {
+	intermediate := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: "/data/qddir/seg.HqtFHX54y0o.-1", Port: 50432, Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: -1, Hostname: "standby", DataDir: "/data/standby.HqtFHX54y0o", Port: 50433, Role: greenplum.MirrorRole},
+		{DbID: 3, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg.HqtFHX54y0o.1", Port: 50434, Role: greenplum.PrimaryRole},
+		{DbID: 4, ContentID: 0, Hostname: "sdw2", DataDir: "/data/dbfast_mirror1/seg.HqtFHX54y0o.1", Port: 50435, Role: greenplum.MirrorRole},
+	})
+
+	target := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: "/data/qddir/seg-1", Port: 15432, Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: -1, Hostname: "standby", DataDir: "/data/standby", Port: 16432, Role: greenplum.MirrorRole},
+		{DbID: 3, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg1", Port: 25433, Role: greenplum.PrimaryRole},
+		{DbID: 4, ContentID: 0, Hostname: "sdw2", DataDir: "/data/dbfast_mirror1/seg1", Port: 25434, Role: greenplum.MirrorRole},
+	})
+
+	pgPattern := `(^[ \t]*port[ \t]*=[ \t]*)%d([^0-9]|$)`
+	conninfoPattern := `(primary_conninfo[ \t]*=?[ \t]*'(?:.*[ \t'])?port[ \t]*=[ \t]*(?:''|\\')?)%d([^0-9]|$)`
+	replacement := `\1%d\2`
+
+	t.Run("updates both standby conf files in a single request", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		standby := mock_idl.NewMockAgentClient(ctrl)
+		standby.EXPECT().UpdateConfiguration(
+			gomock.Any(),
+			equivalentConfRequest(&idl.UpdateConfigurationRequest{
+				Options: []*idl.UpdateFileConfOptions{
+					{
+						Path:             "/data/standby/postgresql.conf",
+						DataDir:          "/data/standby",
+						Pattern:          fmt.Sprintf(pgPattern, 50433),
+						Replacement:      fmt.Sprintf(replacement, 16432),
+						Reason:           hub.ReasonPortRewrite,
+						Guc:              "port",
+						DuplicateMatches: idl.UpdateFileConfOptions_rewriteLastMatch,
+						ExpectedValue:    "16432",
+					},
+					{
+						Path:          "/data/standby/recovery.conf",
+						DataDir:       "/data/standby",
+						Pattern:       fmt.Sprintf(conninfoPattern, 50432),
+						Replacement:   fmt.Sprintf(replacement, 15432),
+						Reason:        hub.ReasonConninfoRewrite,
+						Guc:           "primary_conninfo",
+						ExpectedValue: "port=15432",
+					}},
+			}),
+		).Return(&idl.UpdateConfigurationReply{}, nil).Times(1)
+
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw2 := mock_idl.NewMockAgentClient(ctrl)
+
+		agentConns := []*idl.Connection{
+			{AgentClient: standby, Hostname: "standby"},
+			{AgentClient: sdw1, Hostname: "sdw1"},
+			{AgentClient: sdw2, Hostname: "sdw2"},
+		}
+
+		err := hub.UpdateStandbyConfFiles(context.Background(), agentConns, semver.MustParse("6.0.0"), intermediate, target)
+		if err != nil {
+			t.Errorf("unexpected err %#v", err)
+		}
+	})
+
+	t.Run("leaves both standby conf files correct after the single operation", func(t *testing.T) {
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		standbyDir := filepath.Join(dir, "standby")
+		testutils.MustCreateDir(t, standbyDir)
+		testutils.MustWriteToFile(t, filepath.Join(standbyDir, "postgresql.conf"), "port=50433\n")
+		testutils.MustWriteToFile(t, filepath.Join(standbyDir, "postgresql.auto.conf"), "primary_conninfo = 'user=gpadmin host=coordinator port=50432'\n")
+
+		target := hub.MustCreateCluster(t, greenplum.SegConfigs{
+			{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: "/data/qddir/seg-1", Port: 15432, Role: greenplum.PrimaryRole},
+			{DbID: 2, ContentID: -1, Hostname: "standby", DataDir: standbyDir, Port: 16432, Role: greenplum.MirrorRole},
+		})
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		standby := mock_idl.NewMockAgentClient(ctrl)
+		standby.EXPECT().UpdateConfiguration(
+			gomock.Any(),
+			gomock.Any(),
+		).DoAndReturn(func(_ context.Context, req *idl.UpdateConfigurationRequest, _ ...grpc.CallOption) (*idl.UpdateConfigurationReply, error) {
+			return &idl.UpdateConfigurationReply{}, hub.UpdateConfigurationFile(context.Background(), req.GetOptions())
+		}).Times(1)
+
+		agentConns := []*idl.Connection{{AgentClient: standby, Hostname: "standby"}}
+
+		err := hub.UpdateStandbyConfFiles(context.Background(), agentConns, semver.MustParse("7.0.0"), intermediate, target)
+		if err != nil {
+			t.Fatalf("unexpected err %#v", err)
+		}
+
+		contents := testutils.MustReadFile(t, filepath.Join(standbyDir, "postgresql.conf"))
+		if contents != "port=16432\n" {
+			t.Errorf("got postgresql.conf %q", contents)
+		}
+
+		contents = testutils.MustReadFile(t, filepath.Join(standbyDir, "postgresql.auto.conf"))
+		if contents != "primary_conninfo = 'user=gpadmin host=coordinator port=15432'\n" {
+			t.Errorf("got postgresql.auto.conf %q", contents)
+		}
+	})
+
+	t.Run("does not send a request when there is no standby", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		agentConns := []*idl.Connection{{AgentClient: sdw1, Hostname: "sdw1"}}
+
+		noStandby := hub.MustCreateCluster(t, greenplum.SegConfigs{
+			{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: "/data/qddir/seg-1", Port: 15432, Role: greenplum.PrimaryRole},
+			{DbID: 3, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg1", Port: 25433, Role: greenplum.PrimaryRole},
+		})
+
+		err := hub.UpdateStandbyConfFiles(context.Background(), agentConns, semver.MustParse("7.0.0"), intermediate, noStandby)
+		if err != nil {
+			t.Errorf("unexpected err %#v", err)
+		}
+	})
+
+	t.Run("returns errors when failing to update the standby", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		expected := errors.New("permission denied")
+		standby := mock_idl.NewMockAgentClient(ctrl)
+		standby.EXPECT().UpdateConfiguration(
+			gomock.Any(),
+			gomock.Any(),
+		).Return(nil, expected)
+
+		agentConns := []*idl.Connection{{AgentClient: standby, Hostname: "standby"}}
+
+		err := hub.UpdateStandbyConfFiles(context.Background(), agentConns, semver.MustParse("7.0.0"), intermediate, target)
+		if !errors.Is(err, expected) {
+			t.Errorf("got error %#v, want %#v", err, expected)
+		}
+	})
 }
 
 func TestUpdateInternalAutoConfOnMirrors(t *testing.T) {
@@ -329,27 +703,35 @@ func TestUpdateInternalAutoConfOnMirrors(t *testing.T) {
 		sdw1 := mock_idl.NewMockAgentClient(ctrl)
 		sdw1.EXPECT().UpdateConfiguration(
 			gomock.Any(),
-			&idl.UpdateConfigurationRequest{
+			equivalentConfRequest(&idl.UpdateConfigurationRequest{
 				Options: []*idl.UpdateFileConfOptions{
 					{
-						Path:        "/data/dbfast_mirror2/seg.HqtFHX54y0o.2/internal.auto.conf",
-						Pattern:     fmt.Sprintf(pattern, 5),
-						Replacement: fmt.Sprintf(replacement, 6),
+						Path:          "/data/dbfast_mirror2/seg.HqtFHX54y0o.2/internal.auto.conf",
+						DataDir:       "/data/dbfast_mirror2/seg.HqtFHX54y0o.2",
+						Pattern:       fmt.Sprintf(pattern, 5),
+						Replacement:   fmt.Sprintf(replacement, 6),
+						Reason:        hub.ReasonDbidRewrite,
+						Guc:           "gp_dbid",
+						ExpectedValue: "6",
 					}},
-			},
+			}),
 		).Return(&idl.UpdateConfigurationReply{}, nil)
 
 		sdw2 := mock_idl.NewMockAgentClient(ctrl)
 		sdw2.EXPECT().UpdateConfiguration(
 			gomock.Any(),
-			&idl.UpdateConfigurationRequest{
+			equivalentConfRequest(&idl.UpdateConfigurationRequest{
 				Options: []*idl.UpdateFileConfOptions{
 					{
-						Path:        "/data/dbfast_mirror1/seg.HqtFHX54y0o.1/internal.auto.conf",
-						Pattern:     fmt.Sprintf(pattern, 3),
-						Replacement: fmt.Sprintf(replacement, 4),
+						Path:          "/data/dbfast_mirror1/seg.HqtFHX54y0o.1/internal.auto.conf",
+						DataDir:       "/data/dbfast_mirror1/seg.HqtFHX54y0o.1",
+						Pattern:       fmt.Sprintf(pattern, 3),
+						Replacement:   fmt.Sprintf(replacement, 4),
+						Reason:        hub.ReasonDbidRewrite,
+						Guc:           "gp_dbid",
+						ExpectedValue: "4",
 					}},
-			},
+			}),
 		).Return(&idl.UpdateConfigurationReply{}, nil)
 
 		agentConns := []*idl.Connection{
@@ -370,14 +752,18 @@ func TestUpdateInternalAutoConfOnMirrors(t *testing.T) {
 		sdw1 := mock_idl.NewMockAgentClient(ctrl)
 		sdw1.EXPECT().UpdateConfiguration(
 			gomock.Any(),
-			&idl.UpdateConfigurationRequest{
+			equivalentConfRequest(&idl.UpdateConfigurationRequest{
 				Options: []*idl.UpdateFileConfOptions{
 					{
-						Path:        "/data/dbfast_mirror2/seg.HqtFHX54y0o.2/internal.auto.conf",
-						Pattern:     fmt.Sprintf(pattern, 5),
-						Replacement: fmt.Sprintf(replacement, 6),
+						Path:          "/data/dbfast_mirror2/seg.HqtFHX54y0o.2/internal.auto.conf",
+						DataDir:       "/data/dbfast_mirror2/seg.HqtFHX54y0o.2",
+						Pattern:       fmt.Sprintf(pattern, 5),
+						Replacement:   fmt.Sprintf(replacement, 6),
+						Reason:        hub.ReasonDbidRewrite,
+						Guc:           "gp_dbid",
+						ExpectedValue: "6",
 					}},
-			},
+			}),
 		).Return(&idl.UpdateConfigurationReply{}, nil)
 
 		expected := errors.New("permission denied")
@@ -399,21 +785,606 @@ func TestUpdateInternalAutoConfOnMirrors(t *testing.T) {
 	})
 }
 
-func TestUpdateConfFiles(t *testing.T) {
-	t.Run("UpdateGpperfmonConf", func(t *testing.T) {
-		dir := testutils.GetTempDir(t, "")
-		defer testutils.MustRemoveAll(t, dir)
+func TestUpdateInternalAutoConfOnPrimaries(t *testing.T) {
+	intermediate := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: "/data/qddir/seg.HqtFHX54y0o.-1", Port: 50432, Role: greenplum.PrimaryRole},
+		{DbID: 3, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg.HqtFHX54y0o.1", Port: 50434, Role: greenplum.PrimaryRole},
+		{DbID: 4, ContentID: 0, Hostname: "sdw2", DataDir: "/data/dbfast_mirror1/seg.HqtFHX54y0o.1", Port: 50435, Role: greenplum.MirrorRole},
+		{DbID: 5, ContentID: 1, Hostname: "sdw2", DataDir: "/data/dbfast2/seg.HqtFHX54y0o.2", Port: 50436, Role: greenplum.PrimaryRole},
+		{DbID: 6, ContentID: 1, Hostname: "sdw1", DataDir: "/data/dbfast_mirror2/seg.HqtFHX54y0o.2", Port: 50437, Role: greenplum.MirrorRole},
+	})
 
-		testutils.MustCreateDir(t, filepath.Join(dir, "gpperfmon", "conf"))
-		path := filepath.Join(dir, "gpperfmon", "conf", "gpperfmon.conf")
-		testutils.MustWriteToFile(t, path, `
-log_location = /some/directory
+	// the primary of content 0 has a new dbid while that of content 1 is
+	// unchanged
+	target := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: "/data/qddir/seg-1", Port: 15432, Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg1", Port: 25433, Role: greenplum.PrimaryRole},
+		{DbID: 4, ContentID: 0, Hostname: "sdw2", DataDir: "/data/dbfast_mirror1/seg1", Port: 25434, Role: greenplum.MirrorRole},
+		{DbID: 5, ContentID: 1, Hostname: "sdw2", DataDir: "/data/dbfast2/seg2", Port: 25435, Role: greenplum.PrimaryRole},
+		{DbID: 6, ContentID: 1, Hostname: "sdw1", DataDir: "/data/dbfast_mirror2/seg2", Port: 25436, Role: greenplum.MirrorRole},
+	})
 
-# should not be replaced
+	t.Run("updates internal.auto.conf on primaries whose dbid changed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw1.EXPECT().UpdateConfiguration(
+			gomock.Any(),
+			equivalentConfRequest(&idl.UpdateConfigurationRequest{
+				Options: []*idl.UpdateFileConfOptions{
+					{
+						Path:          "/data/dbfast1/seg.HqtFHX54y0o.1/internal.auto.conf",
+						DataDir:       "/data/dbfast1/seg.HqtFHX54y0o.1",
+						Pattern:       `(^gp_dbid=)3([^0-9]|$)`,
+						Replacement:   `\12\2`,
+						Reason:        hub.ReasonDbidRewrite,
+						Guc:           "gp_dbid",
+						ExpectedValue: "2",
+					}},
+			}),
+		).Return(&idl.UpdateConfigurationReply{}, nil)
+
+		// sdw2 has no primary whose dbid changed
+		sdw2 := mock_idl.NewMockAgentClient(ctrl)
+
+		agentConns := []*idl.Connection{
+			{AgentClient: sdw1, Hostname: "sdw1"},
+			{AgentClient: sdw2, Hostname: "sdw2"},
+		}
+
+		err := hub.UpdateInternalAutoConfOnPrimaries(agentConns, intermediate, target)
+		if err != nil {
+			t.Errorf("unexpected err %#v", err)
+		}
+	})
+
+	t.Run("returns error when failing to update internal.auto.conf on primaries", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		expected := errors.New("permission denied")
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw1.EXPECT().UpdateConfiguration(gomock.Any(), gomock.Any()).Return(nil, expected)
+
+		agentConns := []*idl.Connection{{AgentClient: sdw1, Hostname: "sdw1"}}
+
+		err := hub.UpdateInternalAutoConfOnPrimaries(agentConns, intermediate, target)
+		if !errors.Is(err, expected) {
+			t.Errorf("got error %#v, want %#v", err, expected)
+		}
+	})
+}
+
+func TestUpdateGpperfmonConf(t *testing.T) {
+	stateDir := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, stateDir)
+
+	resetEnv := testutils.SetEnv(t, "GPUPGRADE_HOME", stateDir)
+	defer resetEnv()
+
+	for _, version := range []string{"6.25.0", "7.0.0"} {
+		t.Run("updates the log location for a "+version+" target", func(t *testing.T) {
+			coordinatorDir := testutils.GetTempDir(t, "")
+			defer testutils.MustRemoveAll(t, coordinatorDir)
+
+			testutils.MustWriteToFile(t, filepath.Join(coordinatorDir, "postgresql.conf"), "port=50432\n")
+
+			gpperfmonConf := filepath.Join(coordinatorDir, "gpperfmon", "conf", "gpperfmon.conf")
+			testutils.MustCreateDir(t, filepath.Dir(gpperfmonConf))
+			testutils.MustWriteToFile(t, gpperfmonConf, "log_location = /data/qddir/seg.HqtFHX54y0o.-1/gpperfmon/logs\n")
+
+			intermediate := hub.MustCreateCluster(t, greenplum.SegConfigs{
+				{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: coordinatorDir, Port: 50432, Role: greenplum.PrimaryRole},
+			})
+			target := hub.MustCreateCluster(t, greenplum.SegConfigs{
+				{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: coordinatorDir, Port: 15432, Role: greenplum.PrimaryRole},
+			})
+
+			err := hub.UpdateConfFiles(context.Background(), nil, nil, step.DevNullStream, config.StateVersion, hub.ConfUpdateOptions{}, semver.MustParse(version), intermediate, target)
+			if err != nil {
+				t.Fatalf("unexpected error %+v", err)
+			}
+
+			expected := "log_location = " + filepath.Join(coordinatorDir, "gpperfmon", "logs") + "\n"
+			contents := testutils.MustReadFile(t, gpperfmonConf)
+			if contents != expected {
+				t.Errorf("got %q want %q", contents, expected)
+			}
+		})
+
+		t.Run("skips gpperfmon when it is not installed for a "+version+" target", func(t *testing.T) {
+			coordinatorDir := testutils.GetTempDir(t, "")
+			defer testutils.MustRemoveAll(t, coordinatorDir)
+
+			testutils.MustWriteToFile(t, filepath.Join(coordinatorDir, "postgresql.conf"), "port=50432\n")
+
+			intermediate := hub.MustCreateCluster(t, greenplum.SegConfigs{
+				{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: coordinatorDir, Port: 50432, Role: greenplum.PrimaryRole},
+			})
+			target := hub.MustCreateCluster(t, greenplum.SegConfigs{
+				{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: coordinatorDir, Port: 15432, Role: greenplum.PrimaryRole},
+			})
+
+			err := hub.UpdateConfFiles(context.Background(), nil, nil, step.DevNullStream, config.StateVersion, hub.ConfUpdateOptions{}, semver.MustParse(version), intermediate, target)
+			if err != nil {
+				t.Fatalf("unexpected error %+v", err)
+			}
+
+			testutils.PathMustNotExist(t, filepath.Join(coordinatorDir, "gpperfmon"))
+
+			contents := testutils.MustReadFile(t, filepath.Join(coordinatorDir, "postgresql.conf"))
+			if contents != "port=15432\n" {
+				t.Errorf("got %q want the port updated", contents)
+			}
+		})
+	}
+
+	t.Run("writes a log location containing special characters as is", func(t *testing.T) {
+		coordinatorDir := testutils.GetTempDir(t, `qd@dir&\1\`)
+		defer testutils.MustRemoveAll(t, coordinatorDir)
+
+		testutils.MustWriteToFile(t, filepath.Join(coordinatorDir, "postgresql.conf"), "port=50432\n")
+
+		gpperfmonConf := filepath.Join(coordinatorDir, "gpperfmon", "conf", "gpperfmon.conf")
+		testutils.MustCreateDir(t, filepath.Dir(gpperfmonConf))
+		testutils.MustWriteToFile(t, gpperfmonConf, "log_location = /data/qddir/seg.HqtFHX54y0o.-1/gpperfmon/logs\n")
+
+		intermediate := hub.MustCreateCluster(t, greenplum.SegConfigs{
+			{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: coordinatorDir, Port: 50432, Role: greenplum.PrimaryRole},
+		})
+		target := hub.MustCreateCluster(t, greenplum.SegConfigs{
+			{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: coordinatorDir, Port: 15432, Role: greenplum.PrimaryRole},
+		})
+
+		err := hub.UpdateConfFiles(context.Background(), nil, nil, step.DevNullStream, config.StateVersion, hub.ConfUpdateOptions{}, semver.MustParse("6.25.0"), intermediate, target)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		expected := "log_location = " + filepath.Join(coordinatorDir, "gpperfmon", "logs") + "\n"
+		contents := testutils.MustReadFile(t, gpperfmonConf)
+		if contents != expected {
+			t.Errorf("got %q want %q", contents, expected)
+		}
+	})
+}
+
+func TestUpdateConfFiles(t *testing.T) {
+	t.Run("refuses to update conf files when the persisted state is from an incompatible gpupgrade", func(t *testing.T) {
+		stateDir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, stateDir)
+
+		resetEnv := testutils.SetEnv(t, "GPUPGRADE_HOME", stateDir)
+		defer resetEnv()
+
+		coordinatorDir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, coordinatorDir)
+
+		path := filepath.Join(coordinatorDir, "postgresql.conf")
+		testutils.MustWriteToFile(t, path, "port=50432\n")
+
+		intermediate := hub.MustCreateCluster(t, greenplum.SegConfigs{
+			{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: coordinatorDir, Port: 50432, Role: greenplum.PrimaryRole},
+		})
+		target := hub.MustCreateCluster(t, greenplum.SegConfigs{
+			{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: coordinatorDir, Port: 15432, Role: greenplum.PrimaryRole},
+		})
+
+		persisted := &config.Config{Intermediate: intermediate, Target: target, StateVersion: config.StateVersion + 1}
+		if err := persisted.Write(); err != nil {
+			t.Fatalf("write config: %+v", err)
+		}
+
+		conf, err := config.Read()
+		if err != nil {
+			t.Fatalf("read config: %+v", err)
+		}
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		agentConns := []*idl.Connection{{AgentClient: sdw1, Hostname: "sdw1"}}
+
+		err = hub.UpdateConfFiles(context.Background(), agentConns, nil, step.DevNullStream, conf.StateVersion, hub.ConfUpdateOptions{}, semver.MustParse("7.0.0"), conf.Intermediate, conf.Target)
+		var nextActionErr utils.NextActionErr
+		if !errors.As(err, &nextActionErr) {
+			t.Errorf("got error %#v want type %T", err, nextActionErr)
+		}
+
+		contents := testutils.MustReadFile(t, path)
+		if contents != "port=50432\n" {
+			t.Errorf("expected %q to be unchanged, got %q", path, contents)
+		}
+	})
+
+	t.Run("stops before the next phase once canceled", func(t *testing.T) {
+		stateDir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, stateDir)
+
+		resetEnv := testutils.SetEnv(t, "GPUPGRADE_HOME", stateDir)
+		defer resetEnv()
+
+		coordinatorDir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, coordinatorDir)
+
+		path := filepath.Join(coordinatorDir, "postgresql.conf")
+		testutils.MustWriteToFile(t, path, "port=50432\n")
+
+		intermediate := hub.MustCreateCluster(t, greenplum.SegConfigs{
+			{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: coordinatorDir, Port: 50432, Role: greenplum.PrimaryRole},
+		})
+		target := hub.MustCreateCluster(t, greenplum.SegConfigs{
+			{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: coordinatorDir, Port: 15432, Role: greenplum.PrimaryRole},
+		})
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// no request is expected once canceled
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		agentConns := []*idl.Connection{{AgentClient: sdw1, Hostname: "sdw1"}}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := hub.UpdateConfFiles(ctx, agentConns, nil, step.DevNullStream, config.StateVersion, hub.ConfUpdateOptions{}, semver.MustParse("7.0.0"), intermediate, target)
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("got error %#v, want %#v", err, context.Canceled)
+		}
+
+		expected := "conf update canceled before the " + hub.ConfPhaseCoordinator + " phase"
+		if err == nil || !strings.HasPrefix(err.Error(), expected) {
+			t.Errorf("expected error %v to start with %q", err, expected)
+		}
+
+		contents := testutils.MustReadFile(t, path)
+		if contents != "port=50432\n" {
+			t.Errorf("expected %q to be unchanged, got %q", path, contents)
+		}
+
+		testutils.PathMustNotExist(t, path+hub.BackupSuffix)
+	})
+
+	t.Run("edits nothing when target ports collide on a host", func(t *testing.T) {
+		coordinatorDir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, coordinatorDir)
+
+		path := filepath.Join(coordinatorDir, "postgresql.conf")
+		testutils.MustWriteToFile(t, path, "port=50432\n")
+
+		intermediate := hub.MustCreateCluster(t, greenplum.SegConfigs{
+			{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: coordinatorDir, Port: 50432, Role: greenplum.PrimaryRole},
+			{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg.HqtFHX54y0o.1", Port: 50434, Role: greenplum.PrimaryRole},
+			{DbID: 3, ContentID: 0, Hostname: "sdw2", DataDir: "/data/dbfast_mirror1/seg.HqtFHX54y0o.1", Port: 50436, Role: greenplum.MirrorRole},
+			{DbID: 4, ContentID: 1, Hostname: "sdw2", DataDir: "/data/dbfast_mirror2/seg.HqtFHX54y0o.2", Port: 50437, Role: greenplum.MirrorRole},
+			{DbID: 5, ContentID: 1, Hostname: "sdw1", DataDir: "/data/dbfast2/seg.HqtFHX54y0o.2", Port: 50435, Role: greenplum.PrimaryRole},
+		})
+		target := hub.MustCreateCluster(t, greenplum.SegConfigs{
+			{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: coordinatorDir, Port: 15432, Role: greenplum.PrimaryRole},
+			{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg1", Port: 6000, Role: greenplum.PrimaryRole},
+			{DbID: 3, ContentID: 0, Hostname: "sdw2", DataDir: "/data/dbfast_mirror1/seg1", Port: 6001, Role: greenplum.MirrorRole},
+			{DbID: 4, ContentID: 1, Hostname: "sdw2", DataDir: "/data/dbfast_mirror2/seg2", Port: 6001, Role: greenplum.MirrorRole},
+			{DbID: 5, ContentID: 1, Hostname: "sdw1", DataDir: "/data/dbfast2/seg2", Port: 6002, Role: greenplum.PrimaryRole},
+		})
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// no request is expected when the ports collide
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		agentConns := []*idl.Connection{{AgentClient: sdw1, Hostname: "sdw1"}}
+
+		err := hub.UpdateConfFiles(context.Background(), agentConns, nil, step.DevNullStream, config.StateVersion, hub.ConfUpdateOptions{}, semver.MustParse("7.0.0"), intermediate, target)
+		expected := "target port 6001 is used by more than one segment on host sdw2: content 0 (dbid 3), content 1 (dbid 4)"
+		if err == nil || err.Error() != expected {
+			t.Errorf("got error %v want %q", err, expected)
+		}
+
+		contents := testutils.MustReadFile(t, path)
+		if contents != "port=50432\n" {
+			t.Errorf("expected %q to be unchanged, got %q", path, contents)
+		}
+
+		testutils.PathMustNotExist(t, path+hub.BackupSuffix)
+	})
+
+	t.Run("reports when the cluster is already at the target configuration", func(t *testing.T) {
+		stateDir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, stateDir)
+
+		resetEnv := testutils.SetEnv(t, "GPUPGRADE_HOME", stateDir)
+		defer resetEnv()
+
+		coordinatorDir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, coordinatorDir)
+
+		path := filepath.Join(coordinatorDir, "postgresql.conf")
+
+		intermediate := hub.MustCreateCluster(t, greenplum.SegConfigs{
+			{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: coordinatorDir, Port: 50432, Role: greenplum.PrimaryRole},
+			{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg.HqtFHX54y0o.1", Port: 50434, Role: greenplum.PrimaryRole},
+		})
+		target := hub.MustCreateCluster(t, greenplum.SegConfigs{
+			{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: coordinatorDir, Port: 15432, Role: greenplum.PrimaryRole},
+			{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg1", Port: 25433, Role: greenplum.PrimaryRole},
+		})
+
+		cases := []struct {
+			name        string
+			coordinator string
+			changed     []string
+			expected    bool
+		}{
+			{name: "when no files changed", coordinator: "port=15432\n", expected: true},
+			{name: "not when the coordinator changed", coordinator: "port=50432\n"},
+			{name: "not when a segment changed", coordinator: "port=15432\n", changed: []string{"/data/dbfast1/seg1/postgresql.conf"}},
+		}
+
+		for _, c := range cases {
+			t.Run(c.name, func(t *testing.T) {
+				testutils.MustWriteToFile(t, path, c.coordinator)
+
+				ctrl := gomock.NewController(t)
+				defer ctrl.Finish()
+
+				sdw1 := mock_idl.NewMockAgentClient(ctrl)
+				sdw1.EXPECT().UpdateConfiguration(gomock.Any(), gomock.Any()).Return(&idl.UpdateConfigurationReply{ChangedPaths: c.changed}, nil).Times(1)
+
+				agentConns := []*idl.Connection{{AgentClient: sdw1, Hostname: "sdw1"}}
+
+				streams := &step.BufferedStreams{}
+				err := hub.UpdateConfFiles(context.Background(), agentConns, nil, streams, config.StateVersion, hub.ConfUpdateOptions{}, semver.MustParse("7.0.0"), intermediate, target)
+				if err != nil {
+					t.Fatalf("unexpected error %+v", err)
+				}
+
+				reported := strings.Contains(streams.StdoutBuf.String(), hub.AlreadyAtTargetText)
+				if reported != c.expected {
+					t.Errorf("got %t want %t reporting %q in %q", reported, c.expected, hub.AlreadyAtTargetText, streams.StdoutBuf.String())
+				}
+			})
+		}
+	})
+
+	t.Run("writes a summary of the lines changed on every host", func(t *testing.T) {
+		stateDir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, stateDir)
+
+		resetEnv := testutils.SetEnv(t, "GPUPGRADE_HOME", stateDir)
+		defer resetEnv()
+
+		coordinatorDir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, coordinatorDir)
+
+		path := filepath.Join(coordinatorDir, "postgresql.conf")
+		testutils.MustWriteToFile(t, path, "port=50432\n")
+
+		intermediate := hub.MustCreateCluster(t, greenplum.SegConfigs{
+			{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: coordinatorDir, Port: 50432, Role: greenplum.PrimaryRole},
+			{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg.HqtFHX54y0o.1", Port: 50434, Role: greenplum.PrimaryRole},
+		})
+		target := hub.MustCreateCluster(t, greenplum.SegConfigs{
+			{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: coordinatorDir, Port: 15432, Role: greenplum.PrimaryRole},
+			{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg1", Port: 25433, Role: greenplum.PrimaryRole},
+		})
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw1.EXPECT().UpdateConfiguration(gomock.Any(), gomock.Any()).Return(&idl.UpdateConfigurationReply{
+			ChangedPaths: []string{"/data/dbfast1/seg1/postgresql.conf"},
+			ChangedLines: []*idl.ChangedLine{{Path: "/data/dbfast1/seg1/postgresql.conf", OldLine: "port=50434", NewLine: "port=25433"}},
+		}, nil).Times(1)
+
+		agentConns := []*idl.Connection{{AgentClient: sdw1, Hostname: "sdw1"}}
+
+		streams := &step.BufferedStreams{}
+		err := hub.UpdateConfFiles(context.Background(), agentConns, nil, streams, config.StateVersion, hub.ConfUpdateOptions{}, semver.MustParse("7.0.0"), intermediate, target)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		expected := "changed 2 conf lines:\n" +
+			"  coordinator:" + path + ": \"port=50432\" -> \"port=15432\"\n" +
+			"  sdw1:/data/dbfast1/seg1/postgresql.conf: \"port=50434\" -> \"port=25433\"\n"
+		if !strings.Contains(streams.StdoutBuf.String(), expected) {
+			t.Errorf("got stdout %q want it to contain %q", streams.StdoutBuf.String(), expected)
+		}
+	})
+
+	t.Run("updates the other hosts only when the failed hosts are within the failure threshold", func(t *testing.T) {
+		stateDir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, stateDir)
+
+		resetEnv := testutils.SetEnv(t, "GPUPGRADE_HOME", stateDir)
+		defer resetEnv()
+
+		coordinatorDir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, coordinatorDir)
+
+		path := filepath.Join(coordinatorDir, "postgresql.conf")
+
+		intermediate := hub.MustCreateCluster(t, greenplum.SegConfigs{
+			{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: coordinatorDir, Port: 50432, Role: greenplum.PrimaryRole},
+			{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg.HqtFHX54y0o.1", Port: 50434, Role: greenplum.PrimaryRole},
+			{DbID: 3, ContentID: 1, Hostname: "sdw2", DataDir: "/data/dbfast2/seg.HqtFHX54y0o.2", Port: 50435, Role: greenplum.PrimaryRole},
+			{DbID: 4, ContentID: 2, Hostname: "sdw3", DataDir: "/data/dbfast3/seg.HqtFHX54y0o.3", Port: 50436, Role: greenplum.PrimaryRole},
+			{DbID: 5, ContentID: 3, Hostname: "sdw4", DataDir: "/data/dbfast4/seg.HqtFHX54y0o.4", Port: 50437, Role: greenplum.PrimaryRole},
+		})
+		target := hub.MustCreateCluster(t, greenplum.SegConfigs{
+			{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: coordinatorDir, Port: 15432, Role: greenplum.PrimaryRole},
+			{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg1", Port: 25433, Role: greenplum.PrimaryRole},
+			{DbID: 3, ContentID: 1, Hostname: "sdw2", DataDir: "/data/dbfast2/seg2", Port: 25434, Role: greenplum.PrimaryRole},
+			{DbID: 4, ContentID: 2, Hostname: "sdw3", DataDir: "/data/dbfast3/seg3", Port: 25435, Role: greenplum.PrimaryRole},
+			{DbID: 5, ContentID: 3, Hostname: "sdw4", DataDir: "/data/dbfast4/seg4", Port: 25436, Role: greenplum.PrimaryRole},
+		})
+
+		cases := []struct {
+			name      string
+			threshold hub.FailureThreshold
+			fails     bool
+		}{
+			{name: "stops by default", fails: true},
+			{name: "stops when exceeding a count", threshold: hub.FailureThreshold{Count: 0}, fails: true},
+			{name: "continues within a count", threshold: hub.FailureThreshold{Count: 1}},
+			{name: "stops when exceeding a percentage", threshold: hub.FailureThreshold{Percent: 20}, fails: true},
+			{name: "continues within a percentage", threshold: hub.FailureThreshold{Percent: 25}},
+		}
+
+		for _, c := range cases {
+			t.Run(c.name, func(t *testing.T) {
+				testutils.MustWriteToFile(t, path, "port=50432\n")
+
+				hub.SetConfFailureThreshold(c.threshold)
+				defer hub.ResetConfFailureThreshold()
+
+				ctrl := gomock.NewController(t)
+				defer ctrl.Finish()
+
+				expected := errors.New("permission denied")
+
+				var agentConns []*idl.Connection
+				for _, host := range []string{"sdw1", "sdw2", "sdw3", "sdw4"} {
+					client := mock_idl.NewMockAgentClient(ctrl)
+					if host == "sdw3" {
+						client.EXPECT().UpdateConfiguration(gomock.Any(), gomock.Any()).Return(nil, expected).AnyTimes()
+					} else {
+						client.EXPECT().UpdateConfiguration(gomock.Any(), gomock.Any()).Return(&idl.UpdateConfigurationReply{}, nil).AnyTimes()
+					}
+					agentConns = append(agentConns, &idl.Connection{AgentClient: client, Hostname: host})
+				}
+
+				err := hub.UpdateConfFiles(context.Background(), agentConns, nil, step.DevNullStream, config.StateVersion, hub.ConfUpdateOptions{}, semver.MustParse("7.0.0"), intermediate, target)
+				var nextActionErr utils.NextActionErr
+				retries := errors.As(err, &nextActionErr)
+				if c.fails {
+					if !errors.Is(err, expected) {
+						t.Errorf("got error %#v want %#v", err, expected)
+					}
+
+					if retries {
+						t.Errorf("expected no retry of the failed hosts, got %q", nextActionErr.NextAction)
+					}
+					return
+				}
+
+				if !retries {
+					t.Fatalf("got error %#v want a retry of the failed hosts", err)
+				}
+
+				if !errors.Is(nextActionErr.Err, expected) {
+					t.Errorf("got error %#v want %#v", nextActionErr.Err, expected)
+				}
+
+				report := "conf files were not updated on 1 of 4 hosts: sdw3"
+				if !strings.Contains(err.Error(), report) {
+					t.Errorf("expected error %q to contain %q", err, report)
+				}
+
+				if !strings.Contains(nextActionErr.NextAction, "gpupgrade finalize --conf-hosts sdw3 --conf-resume-token ") {
+					t.Errorf("expected a retry of only the failed hosts, got %#v", err)
+				}
+			})
+		}
+	})
+
+	t.Run("sends substep events for the progress of each phase", func(t *testing.T) {
+		stateDir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, stateDir)
+
+		resetEnv := testutils.SetEnv(t, "GPUPGRADE_HOME", stateDir)
+		defer resetEnv()
+
+		coordinatorDir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, coordinatorDir)
+
+		testutils.MustWriteToFile(t, filepath.Join(coordinatorDir, "postgresql.conf"), "port=50432\n")
+
+		intermediate := hub.MustCreateCluster(t, greenplum.SegConfigs{
+			{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: coordinatorDir, Port: 50432, Role: greenplum.PrimaryRole},
+			{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg.HqtFHX54y0o.1", Port: 50434, Role: greenplum.PrimaryRole},
+		})
+		target := hub.MustCreateCluster(t, greenplum.SegConfigs{
+			{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: coordinatorDir, Port: 15432, Role: greenplum.PrimaryRole},
+			{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg1", Port: 25433, Role: greenplum.PrimaryRole},
+		})
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw1.EXPECT().UpdateConfiguration(gomock.Any(), gomock.Any()).Return(&idl.UpdateConfigurationReply{}, nil)
+
+		agentConns := []*idl.Connection{{AgentClient: sdw1, Hostname: "sdw1"}}
+
+		sender := &eventSender{}
+		err := hub.UpdateConfFiles(context.Background(), agentConns, sender, step.DevNullStream, config.StateVersion, hub.ConfUpdateOptions{}, semver.MustParse("7.0.0"), intermediate, target)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		// there is no standby so its phase sends no events
+		hostCompleted := func(phase string, hostname string) *idl.SubstepEvent {
+			return &idl.SubstepEvent{Substep: idl.Substep_update_target_conf_files, Type: idl.SubstepEvent_host_completed, Phase: phase, Hostname: hostname, CompletedHosts: 1, TotalHosts: 1}
+		}
+
+		expected := []*idl.SubstepEvent{
+			{Substep: idl.Substep_update_target_conf_files, Type: idl.SubstepEvent_started, TotalHosts: 1},
+			hostCompleted(hub.ConfPhaseCoordinator, "coordinator"),
+			hostCompleted(hub.ConfPhaseSegmentPostgresqlConf, "sdw1"),
+			hostCompleted(hub.ConfPhaseSegmentRecoveryConf, "sdw1"),
+			hostCompleted(hub.ConfPhaseSegmentPgHbaConf, "sdw1"),
+			{Substep: idl.Substep_update_target_conf_files, Type: idl.SubstepEvent_finished},
+		}
+
+		if !reflect.DeepEqual(sender.events, expected) {
+			t.Errorf("got events %v want %v", sender.events, expected)
+		}
+	})
+
+	t.Run("returns the files whose contents changed", func(t *testing.T) {
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		updated := filepath.Join(dir, "postgresql.conf")
+		testutils.MustWriteToFile(t, updated, "port=5000\n")
+
+		unchanged := filepath.Join(dir, "postgresql.auto.conf")
+		testutils.MustWriteToFile(t, unchanged, "port=6000\n")
+
+		var opts []*idl.UpdateFileConfOptions
+		for _, path := range []string{updated, unchanged} {
+			opts = append(opts, &idl.UpdateFileConfOptions{Path: path, Pattern: fmt.Sprintf(`(^[ \t]*port[ \t]*=[ \t]*)%d([^0-9]|$)`, 5000), Replacement: fmt.Sprintf(`\1%d\2`, 6000), Guc: "port", ExpectedValue: "6000"})
+		}
+
+		changed, err := hub.UpdateConfigurationFileChanges(context.Background(), opts)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		expected := []string{updated}
+		if !reflect.DeepEqual(changed, expected) {
+			t.Errorf("got %q want %q", changed, expected)
+		}
+	})
+
+	t.Run("UpdateGpperfmonConf", func(t *testing.T) {
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		testutils.MustCreateDir(t, filepath.Join(dir, "gpperfmon", "conf"))
+		path := filepath.Join(dir, "gpperfmon", "conf", "gpperfmon.conf")
+		testutils.MustWriteToFile(t, path, `
+log_location = /some/directory
+
+# should not be replaced
 other_log_location = /some/directory
 `)
 
-		err := hub.UpdateConfigurationFile([]*idl.UpdateFileConfOptions{{
+		err := hub.UpdateConfigurationFile(context.Background(), []*idl.UpdateFileConfOptions{{
 			Path:        filepath.Join(dir, "gpperfmon", "conf", "gpperfmon.conf"),
 			Pattern:     `^log_location = .*$`,
 			Replacement: fmt.Sprintf("log_location = %s", filepath.Join(dir, "gpperfmon", "logs")),
@@ -443,14 +1414,17 @@ other_log_location = /some/directory
 port=5000
 port=5000 # comment
 port = 5000 # make sure we can handle spaces
+  port = 5000 # make sure we can handle space indentation
+	port = 5000 # make sure we can handle tab indentation
 
 # should not be replaced
 gpperfmon_port=5000
 port=50000
 #port=5000
+  #port=5000
 `)
 
-		err := hub.UpdateConfigurationFile([]*idl.UpdateFileConfOptions{{Path: path, Pattern: fmt.Sprintf(`(^port[ \t]*=[ \t]*)%d([^0-9]|$)`, 5000), Replacement: fmt.Sprintf(`\1%d\2`, 6000)}})
+		err := hub.UpdateConfigurationFile(context.Background(), []*idl.UpdateFileConfOptions{{Path: path, Pattern: fmt.Sprintf(`(^[ \t]*port[ \t]*=[ \t]*)%d([^0-9]|$)`, 5000), Replacement: fmt.Sprintf(`\1%d\2`, 6000)}})
 		if err != nil {
 			t.Errorf("UpdatePostgresqlConf() returned error %+v", err)
 		}
@@ -460,16 +1434,72 @@ port=50000
 port=6000
 port=6000 # comment
 port = 6000 # make sure we can handle spaces
+  port = 6000 # make sure we can handle space indentation
+	port = 6000 # make sure we can handle tab indentation
 
 # should not be replaced
 gpperfmon_port=5000
 port=50000
 #port=5000
+  #port=5000
 `
 		if contents != expected {
 			t.Errorf("replaced contents: %s\nwant: %s", contents, expected)
 		}
 	})
+	t.Run("matches a port at the end of a line without a newline", func(t *testing.T) {
+		// The pattern boundary ([^0-9]|$) must match at the end of each line
+		// rather than only at the end of the file, including the final line
+		// of a file without a trailing newline. The trailing newlines are
+		// preserved so the file is otherwise unchanged.
+		cases := []struct {
+			name     string
+			contents string
+			expected string
+		}{
+			{
+				name:     "last line without trailing newline",
+				contents: "listen_addresses='*'\nport=5000",
+				expected: "listen_addresses='*'\nport=6000",
+			},
+			{
+				name:     "only line without trailing newline",
+				contents: "port=5000",
+				expected: "port=6000",
+			},
+			{
+				name:     "line ending before other lines",
+				contents: "port=5000\nmax_connections=5000\n",
+				expected: "port=6000\nmax_connections=5000\n",
+			},
+			{
+				name:     "port prefix at end of file is not matched",
+				contents: "port=50001",
+				expected: "port=50001",
+			},
+		}
+
+		for _, c := range cases {
+			t.Run(c.name, func(t *testing.T) {
+				dir := testutils.GetTempDir(t, "")
+				defer testutils.MustRemoveAll(t, dir)
+
+				path := filepath.Join(dir, "postgresql.conf")
+				testutils.MustWriteToFile(t, path, c.contents)
+
+				err := hub.UpdateConfigurationFile(context.Background(), []*idl.UpdateFileConfOptions{{Path: path, Pattern: fmt.Sprintf(`(^[ \t]*port[ \t]*=[ \t]*)%d([^0-9]|$)`, 5000), Replacement: fmt.Sprintf(`\1%d\2`, 6000), PreserveTrailingNewline: true, AllowNoMatch: true}})
+				if err != nil {
+					t.Errorf("unexpected error %+v", err)
+				}
+
+				contents := testutils.MustReadFile(t, path)
+				if contents != c.expected {
+					t.Errorf("replaced contents: %q want: %q", contents, c.expected)
+				}
+			})
+		}
+	})
+
 	t.Run("UpdateRecoveryConf", func(t *testing.T) {
 		dir := testutils.GetTempDir(t, "")
 		defer testutils.MustRemoveAll(t, dir)
@@ -484,7 +1514,7 @@ primary_slot_name = 'internal_wal_replication_slot'
 #primary_conninfo = 'user=gpadmin host=sdw1 port=5000 sslmode=disable sslcompression=1 krbsrvname=postgres application_name=gp_walreceiver'
 `)
 
-		err := hub.UpdateConfigurationFile([]*idl.UpdateFileConfOptions{{Path: path, Pattern: fmt.Sprintf(`(primary_conninfo .* port[ \t]*=[ \t]*)%d([^0-9]|$)`, 5000), Replacement: fmt.Sprintf(`\1%d\2`, 6000)}})
+		err := hub.UpdateConfigurationFile(context.Background(), []*idl.UpdateFileConfOptions{{Path: path, Pattern: fmt.Sprintf(`(primary_conninfo[ \t]*=?[ \t]*'(?:.*[ \t'])?port[ \t]*=[ \t]*(?:''|\\')?)%d([^0-9]|$)`, 5000), Replacement: fmt.Sprintf(`\1%d\2`, 6000)}})
 		if err != nil {
 			t.Errorf("UpdateRecoveryConf() returned error %+v", err)
 		}
@@ -503,20 +1533,195 @@ primary_slot_name = 'internal_wal_replication_slot'
 		}
 	})
 
+	t.Run("UpdateRecoveryConf rewrites only the port keyword of primary_conninfo", func(t *testing.T) {
+		cases := []struct {
+			name     string
+			contents string
+			expected string
+		}{
+			{
+				name:     "IPv6 host",
+				contents: "primary_conninfo = 'user=gpadmin host=fe80::1 port=5000 sslmode=disable application_name=gp_walreceiver'\n",
+				expected: "primary_conninfo = 'user=gpadmin host=fe80::1 port=6000 sslmode=disable application_name=gp_walreceiver'\n",
+			},
+			{
+				name:     "IPv6 host ending in the port",
+				contents: "primary_conninfo = 'host=2001:db8::5000 port=5000'\n",
+				expected: "primary_conninfo = 'host=2001:db8::5000 port=6000'\n",
+			},
+			{
+				name:     "hostname and application name with digits",
+				contents: "primary_conninfo = 'host=sdw5000 port=5000 application_name=gp_5000_port=5000x'\n",
+				expected: "primary_conninfo = 'host=sdw5000 port=6000 application_name=gp_5000_port=5000x'\n",
+			},
+			{
+				name:     "port first",
+				contents: "primary_conninfo = 'port=5000 host=sdw1'\n",
+				expected: "primary_conninfo = 'port=6000 host=sdw1'\n",
+			},
+			{
+				name:     "tab separated",
+				contents: "primary_conninfo\t'host=sdw1\tport = 5000'\n",
+				expected: "primary_conninfo\t'host=sdw1\tport = 6000'\n",
+			},
+			{
+				name:     "quoted values",
+				contents: "primary_conninfo = 'host=''fe80::1'' port=''5000'' application_name=''gp 5000'''\n",
+				expected: "primary_conninfo = 'host=''fe80::1'' port=''6000'' application_name=''gp 5000'''\n",
+			},
+			{
+				name:     "backslash quoted values",
+				contents: `primary_conninfo = 'host=\'fe80::1\' port=\'5000\''` + "\n",
+				expected: `primary_conninfo = 'host=\'fe80::1\' port=\'6000\''` + "\n",
+			},
+		}
+
+		for _, c := range cases {
+			t.Run(c.name, func(t *testing.T) {
+				dir := testutils.GetTempDir(t, "")
+				defer testutils.MustRemoveAll(t, dir)
+
+				path := filepath.Join(dir, "recovery.conf")
+				testutils.MustWriteToFile(t, path, c.contents)
+
+				err := hub.UpdateConfigurationFile(context.Background(), []*idl.UpdateFileConfOptions{{Path: path, Pattern: fmt.Sprintf(`(primary_conninfo[ \t]*=?[ \t]*'(?:.*[ \t'])?port[ \t]*=[ \t]*(?:''|\\')?)%d([^0-9]|$)`, 5000), Replacement: fmt.Sprintf(`\1%d\2`, 6000)}})
+				if err != nil {
+					t.Fatalf("unexpected error %+v", err)
+				}
+
+				contents := testutils.MustReadFile(t, path)
+				if contents != c.expected {
+					t.Errorf("got %q want %q", contents, c.expected)
+				}
+			})
+		}
+	})
+
 	t.Run("returns errors", func(t *testing.T) {
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		path := filepath.Join(dir, "postgresql.conf")
+		testutils.MustWriteToFile(t, path, "port=5000\n")
+
 		opts := []*idl.UpdateFileConfOptions{
 			{
-				Path:        "",
+				Path:        path,
+				Pattern:     "(",
+				Replacement: "",
+			},
+			{
+				Path:        path,
+				Pattern:     "(",
+				Replacement: "",
+			}}
+
+		err := hub.UpdateConfigurationFile(context.Background(), opts)
+		var errs errorlist.Errors
+		if !errors.As(err, &errs) {
+			t.Fatalf("error %#v does not contain type %T", err, errs)
+		}
+
+		if len(errs) != len(opts) {
+			t.Fatalf("got error count %d, want %d", len(errs), len(opts))
+		}
+
+		for _, err := range errs {
+			expected := fmt.Sprintf(`update %s: compile pattern "(" with replacement "": error parsing regexp`, path)
+			if !strings.HasPrefix(err.Error(), expected) {
+				t.Errorf("expected error to contain %q got %q", expected, err.Error())
+			}
+		}
+	})
+
+	t.Run("includes the reason for the edit in errors", func(t *testing.T) {
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		path := filepath.Join(dir, "postgresql.conf")
+		testutils.MustWriteToFile(t, path, "port=5000\n")
+
+		err := hub.UpdateConfigurationFile(context.Background(), []*idl.UpdateFileConfOptions{{
+			Path:             path,
+			Pattern:          "(",
+			Replacement:      "",
+			Reason:           hub.ReasonPortRewrite,
+			Guc:              "port",
+			DuplicateMatches: idl.UpdateFileConfOptions_rewriteLastMatch,
+		}})
+
+		expected := fmt.Sprintf("update %s for port-rewrite: compile", path)
+		if err == nil || !strings.HasPrefix(err.Error(), expected) {
+			t.Errorf("expected error %v to start with %q", err, expected)
+		}
+	})
+
+	t.Run("names the absent file and its data directory", func(t *testing.T) {
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		path := filepath.Join(dir, "recovery.conf")
+		err := hub.UpdateConfigurationFile(context.Background(), []*idl.UpdateFileConfOptions{{
+			Path:    path,
+			Pattern: `(primary_conninfo .* port=)5000`,
+			Reason:  hub.ReasonConninfoRewrite,
+		}})
+
+		if !errors.Is(err, hub.ErrConfFileAbsent) {
+			t.Errorf("got error %#v want %#v", err, hub.ErrConfFileAbsent)
+		}
+
+		expected := fmt.Sprintf("update recovery.conf for %s: the file is absent from data directory %s", hub.ReasonConninfoRewrite, dir)
+		if err == nil || err.Error() != expected {
+			t.Errorf("got error %v want %q", err, expected)
+		}
+	})
+
+	t.Run("skips the edits of an absent file marked skip if absent", func(t *testing.T) {
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		path := filepath.Join(dir, "gpperfmon.conf")
+		changed, skipped, err := hub.UpdateConfigurationFileResult(context.Background(), []*idl.UpdateFileConfOptions{{
+			Path:         path,
+			Pattern:      `^log_location = .*$`,
+			Replacement:  "log_location = /data/gpperfmon/logs",
+			Reason:       hub.ReasonGpperfmonLogPath,
+			SkipIfAbsent: true,
+		}})
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		if len(changed) != 0 {
+			t.Errorf("got changed %v want none", changed)
+		}
+
+		expected := []string{path + " for " + hub.ReasonGpperfmonLogPath + ": the optional file is absent"}
+		if !reflect.DeepEqual(skipped, expected) {
+			t.Errorf("got skipped %q want %q", skipped, expected)
+		}
+
+		testutils.PathMustNotExist(t, path)
+	})
+
+	t.Run("returns errors when the conf file cannot be backed up", func(t *testing.T) {
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		opts := []*idl.UpdateFileConfOptions{
+			{
+				Path:        filepath.Join(dir, "postgresql.conf"),
 				Pattern:     "",
 				Replacement: "",
 			},
 			{
-				Path:        "",
+				Path:        filepath.Join(dir, "recovery.conf"),
 				Pattern:     "",
 				Replacement: "",
 			}}
 
-		err := hub.UpdateConfigurationFile(opts)
+		err := hub.UpdateConfigurationFile(context.Background(), opts)
 		var errs errorlist.Errors
 		if !errors.As(err, &errs) {
 			t.Fatalf("error %#v does not contain type %T", err, errs)
@@ -526,16 +1731,344 @@ primary_slot_name = 'internal_wal_replication_slot'
 			t.Fatalf("got error count %d, want %d", len(errs), len(opts))
 		}
 
-		sedPath, err := exec.LookPath("sed")
+		for _, err := range errs {
+			if !errors.Is(err, os.ErrNotExist) {
+				t.Errorf("got error %#v, want %#v", err, os.ErrNotExist)
+			}
+		}
+	})
+
+	t.Run("neither edits nor backs up the conf file once canceled", func(t *testing.T) {
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		path := filepath.Join(dir, "postgresql.conf")
+		testutils.MustWriteToFile(t, path, "port=5000\n")
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := hub.UpdateConfigurationFile(ctx, []*idl.UpdateFileConfOptions{{Path: path, Pattern: `^(port=)5000$`, Replacement: `\16000`}})
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("got error %#v, want %#v", err, context.Canceled)
+		}
+
+		expected := fmt.Sprintf("update %s: canceled before editing", path)
+		if err == nil || !strings.HasPrefix(err.Error(), expected) {
+			t.Errorf("expected error %v to start with %q", err, expected)
+		}
+
+		contents := testutils.MustReadFile(t, path)
+		if contents != "port=5000\n" {
+			t.Errorf("got %q want the file unchanged", contents)
+		}
+
+		testutils.PathMustNotExist(t, path+hub.BackupSuffix)
+	})
+}
+
+func TestUpdateConfigurationFileResult(t *testing.T) {
+	archiveModeEdit := func(path string) []*idl.UpdateFileConfOptions {
+		return []*idl.UpdateFileConfOptions{{
+			Path:              path,
+			Pattern:           `(^[ \t]*archive_mode[ \t]*=[ \t]*)on`,
+			Replacement:       `\1off`,
+			Reason:            "archive-mode",
+			Guc:               "archive_mode",
+			MatchCurrentValue: "on",
+		}}
+	}
+
+	t.Run("applies the options of a file in order with a single backup", func(t *testing.T) {
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		path := filepath.Join(dir, "postgresql.conf")
+		testutils.MustWriteToFile(t, path, "port=5000\n")
+
+		// the second option matches only the line written by the first
+		changed, _, err := hub.UpdateConfigurationFileResult(context.Background(), []*idl.UpdateFileConfOptions{
+			{Path: path, Pattern: `^(port=)5000$`, Replacement: `\16000`},
+			{Path: path, Pattern: `^(port=)6000$`, Replacement: `\17000`},
+		})
 		if err != nil {
-			t.Fatalf("expected sed: %v", err)
+			t.Fatalf("unexpected error %+v", err)
 		}
 
-		for _, err := range errs {
-			expected := fmt.Sprintf(`update . using "%s -E -i.bak s@@@ " failed with "sed:`, sedPath)
-			if !strings.HasPrefix(err.Error(), expected) {
-				t.Errorf("expected error to contain %q got %q", expected, err.Error())
+		if !reflect.DeepEqual(changed, []string{path}) {
+			t.Errorf("got changed %q want %q", changed, []string{path})
+		}
+
+		contents := testutils.MustReadFile(t, path)
+		if contents != "port=7000\n" {
+			t.Errorf("got %q want %q", contents, "port=7000\n")
+		}
+
+		backup := testutils.MustReadFile(t, path+hub.BackupSuffix)
+		if backup != "port=5000\n" {
+			t.Errorf("got backup %q want the original contents", backup)
+		}
+
+		testutils.PathMustNotExist(t, path+hub.BackupSuffix+".1")
+	})
+
+	t.Run("edits when the precondition is met", func(t *testing.T) {
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		path := filepath.Join(dir, "postgresql.conf")
+		testutils.MustWriteToFile(t, path, "archive_mode = on\n")
+
+		changed, skipped, err := hub.UpdateConfigurationFileResult(context.Background(), archiveModeEdit(path))
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		if !reflect.DeepEqual(changed, []string{path}) {
+			t.Errorf("got changed %q want %q", changed, []string{path})
+		}
+
+		if len(skipped) != 0 {
+			t.Errorf("unexpected skipped %q", skipped)
+		}
+
+		contents := testutils.MustReadFile(t, path)
+		if contents != "archive_mode = off\n" {
+			t.Errorf("got %q want %q", contents, "archive_mode = off\n")
+		}
+	})
+
+	t.Run("skips the edit when the precondition is not met", func(t *testing.T) {
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		path := filepath.Join(dir, "postgresql.conf")
+		testutils.MustWriteToFile(t, path, "archive_mode = always\n")
+
+		changed, skipped, err := hub.UpdateConfigurationFileResult(context.Background(), archiveModeEdit(path))
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		if len(changed) != 0 {
+			t.Errorf("unexpected changed %q", changed)
+		}
+
+		expected := []string{path + ` for archive-mode: archive_mode is not "on": precondition not met`}
+		if !reflect.DeepEqual(skipped, expected) {
+			t.Errorf("got skipped %q want %q", skipped, expected)
+		}
+
+		contents := testutils.MustReadFile(t, path)
+		if contents != "archive_mode = always\n" {
+			t.Errorf("got %q want %q", contents, "archive_mode = always\n")
+		}
+	})
+
+	t.Run("errors when the precondition does not name a GUC", func(t *testing.T) {
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		path := filepath.Join(dir, "postgresql.conf")
+		testutils.MustWriteToFile(t, path, "archive_mode = on\n")
+
+		opts := archiveModeEdit(path)
+		opts[0].Guc = ""
+
+		_, _, err := hub.UpdateConfigurationFileResult(context.Background(), opts)
+		if err == nil || !strings.Contains(err.Error(), "requires the GUC to check") {
+			t.Errorf("got error %v want a missing GUC error", err)
+		}
+	})
+
+}
+
+func TestUpdateConfigurationFileReply(t *testing.T) {
+	t.Run("returns the lines changed by each option of the files written", func(t *testing.T) {
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		postgresqlConf := filepath.Join(dir, "postgresql.conf")
+		testutils.MustWriteToFile(t, postgresqlConf, "listen_addresses='*'\nport=5000\narchive_mode=on")
+
+		unchanged := filepath.Join(dir, "recovery.conf")
+		testutils.MustWriteToFile(t, unchanged, "primary_conninfo = 'host=sdw1 port=6001'\n")
+
+		opts := []*idl.UpdateFileConfOptions{
+			{Path: unchanged, Pattern: `(primary_conninfo .* port=)6001`, Replacement: `\16001`},
+			{Path: postgresqlConf, Pattern: `^(port=)5000$`, Replacement: `\16000`},
+			{Path: postgresqlConf, Pattern: `^archive_mode=on$`, Replacement: `archive_mode=off`},
+		}
+
+		reply, err := hub.UpdateConfigurationFileReply(context.Background(), opts)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		// the final newline added to postgresql.conf is not a changed line
+		expected := []*idl.ChangedLine{
+			{Option: 1, Path: postgresqlConf, OldLine: "port=5000", NewLine: "port=6000"},
+			{Option: 2, Path: postgresqlConf, OldLine: "archive_mode=on", NewLine: "archive_mode=off"},
+		}
+		if len(reply.GetChangedLines()) != len(expected) {
+			t.Fatalf("got changed lines %v want %v", reply.GetChangedLines(), expected)
+		}
+
+		for i, line := range reply.GetChangedLines() {
+			if !proto.Equal(line, expected[i]) {
+				t.Errorf("got changed line %v want %v", line, expected[i])
 			}
 		}
+
+		if !reflect.DeepEqual(reply.GetChangedPaths(), []string{postgresqlConf}) {
+			t.Errorf("got changed paths %v want %v", reply.GetChangedPaths(), []string{postgresqlConf})
+		}
+	})
+
+	t.Run("returns the checksum and edits in effect of each file read back", func(t *testing.T) {
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		path := filepath.Join(dir, "postgresql.conf")
+		testutils.MustWriteToFile(t, path, "port=5000\narchive_mode=on\n")
+
+		opts := []*idl.UpdateFileConfOptions{
+			{Path: path, Pattern: `^(port=)5000$`, Replacement: `\16000`},
+			{Path: path, Pattern: `^archive_mode=on$`, Replacement: `archive_mode=off`},
+		}
+
+		reply, err := hub.UpdateConfigurationFileReply(context.Background(), opts)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		sum := sha256.Sum256([]byte("port=6000\narchive_mode=off\n"))
+		expected := &idl.ConfFileChecksum{Path: path, Sha256: hex.EncodeToString(sum[:]), MatchedOptions: 2}
+		if len(reply.GetChecksums()) != 1 || !proto.Equal(reply.GetChecksums()[0], expected) {
+			t.Errorf("got checksums %v want %v", reply.GetChecksums(), expected)
+		}
 	})
+
+	t.Run("counts only the edits in effect when the file read back is stale", func(t *testing.T) {
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		path := filepath.Join(dir, "postgresql.conf")
+		testutils.MustWriteToFile(t, path, "port=5000\narchive_mode=on\n")
+
+		// the read back returns the file with only the first edit
+		reads := 0
+		utils.System.ReadFile = func(filename string) ([]byte, error) {
+			reads++
+			if reads > 1 {
+				return []byte("port=6000\narchive_mode=on\n"), nil
+			}
+			return os.ReadFile(filename)
+		}
+		defer utils.ResetSystemFunctions()
+
+		opts := []*idl.UpdateFileConfOptions{
+			{Path: path, Pattern: `^(port=)5000$`, Replacement: `\16000`},
+			{Path: path, Pattern: `^archive_mode=on$`, Replacement: `archive_mode=off`},
+		}
+
+		reply, err := hub.UpdateConfigurationFileReply(context.Background(), opts)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		if len(reply.GetChecksums()) != 1 || reply.GetChecksums()[0].GetMatchedOptions() != 1 {
+			t.Errorf("got checksums %v want 1 edit in effect", reply.GetChecksums())
+		}
+	})
+}
+
+func TestUpdateConfigurationFileConcurrency(t *testing.T) {
+	hub.SetConfFileConcurrency(2)
+	defer hub.ResetConfFileConcurrency()
+
+	var mutex sync.Mutex
+	editing, maxEditing := 0, 0
+	utils.System.ReadFile = func(filename string) ([]byte, error) {
+		mutex.Lock()
+		editing++
+		maxEditing = max(maxEditing, editing)
+		mutex.Unlock()
+
+		defer func() {
+			mutex.Lock()
+			editing--
+			mutex.Unlock()
+		}()
+
+		time.Sleep(10 * time.Millisecond)
+		return os.ReadFile(filename)
+	}
+	defer utils.ResetSystemFunctions()
+
+	dir := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, dir)
+
+	var opts []*idl.UpdateFileConfOptions
+	for i := 0; i < 8; i++ {
+		path := filepath.Join(dir, fmt.Sprintf("postgresql%d.conf", i))
+		port := 5000
+		if i%2 == 1 {
+			port = 5001 // matched by no pattern
+		}
+		testutils.MustWriteToFile(t, path, fmt.Sprintf("port=%d\n", port))
+
+		opts = append(opts, &idl.UpdateFileConfOptions{Path: path, Pattern: `^(port=)5000$`, Replacement: `\16000`})
+	}
+
+	changed, err := hub.UpdateConfigurationFileChanges(context.Background(), opts)
+
+	var errs errorlist.Errors
+	if !errors.As(err, &errs) || len(errs) != 4 {
+		t.Errorf("got error %v want an error for each of the 4 files matching no lines", err)
+	}
+
+	if len(changed) != 4 {
+		t.Errorf("got changed %v want the 4 files matching the pattern", changed)
+	}
+
+	if maxEditing > 2 {
+		t.Errorf("got %d files edited at once want at most 2", maxEditing)
+	}
+}
+
+// eventSender records the substep events sent to it.
+type eventSender struct {
+	events []*idl.SubstepEvent
+}
+
+func (s *eventSender) Send(msg *idl.Message) error {
+	s.events = append(s.events, msg.GetEvent())
+	return nil
+}
+
+// equivalentConfRequest is a Matcher comparing requests with proto.Equal since
+// the options sent are clones of the planned ones. The share of the edit
+// budget sent is ignored.
+func equivalentConfRequest(req *idl.UpdateConfigurationRequest) gomock.Matcher {
+	return confRequestMatcher{req}
+}
+
+type confRequestMatcher struct {
+	expected *idl.UpdateConfigurationRequest
+}
+
+func (r confRequestMatcher) Matches(x interface{}) bool {
+	actual, ok := x.(*idl.UpdateConfigurationRequest)
+	if !ok {
+		return false
+	}
+
+	actual = proto.Clone(actual).(*idl.UpdateConfigurationRequest)
+	actual.FileConcurrency = r.expected.GetFileConcurrency()
+	return proto.Equal(r.expected, actual)
+}
+
+func (r confRequestMatcher) String() string {
+	return fmt.Sprintf("is equivalent to %v", r.expected)
 }
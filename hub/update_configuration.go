@@ -0,0 +1,200 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/greenplum-db/gpupgrade/idl"
+)
+
+// HandleUpdateConfiguration makes the edits of an UpdateConfiguration request
+// on the local host, for both the agents and the coordinator, so that every
+// host is updated by the same code. At most FileConcurrency files, when set,
+// are edited at once. Errors are prefixed with the hostname.
+func HandleUpdateConfiguration(ctx context.Context, hostname string, req *idl.UpdateConfigurationRequest) (*idl.UpdateConfigurationReply, error) {
+	if req.GetDryRun() {
+		reply, err := editConfigurationFiles(ctx, req.GetOptions(), true, int(req.GetFileConcurrency()))
+		if err != nil {
+			return &idl.UpdateConfigurationReply{}, fmt.Errorf("on host %q: %w", hostname, err)
+		}
+
+		return &idl.UpdateConfigurationReply{Diffs: reply.GetDiffs(), Skipped: reply.GetSkipped()}, nil
+	}
+
+	var originals map[string]ConfOriginal
+	var err error
+	if req.GetVerifyAfterWrite() {
+		originals, err = ReadConfOriginals(req.GetOptions())
+		if err != nil {
+			return &idl.UpdateConfigurationReply{}, fmt.Errorf("on host %q: %w", hostname, err)
+		}
+	}
+
+	reply, err := editConfigurationFiles(ctx, req.GetOptions(), false, int(req.GetFileConcurrency()))
+	if err != nil {
+		return &idl.UpdateConfigurationReply{}, fmt.Errorf("on host %q: %w", hostname, err)
+	}
+
+	if req.GetDebugLogging() {
+		LogConfOptions(req.GetOptions(), reply)
+	}
+
+	if req.GetVerifyAfterWrite() {
+		reply.Values, err = ReadEditedGUCs(req.GetOptions())
+		if err != nil {
+			return &idl.UpdateConfigurationReply{}, fmt.Errorf("on host %q: %w", hostname, err)
+		}
+
+		// restore the files that failed verification rather than leave a bad conf in place
+		reply.RolledBackPaths, err = RollBackUnverifiedFiles(req.GetOptions(), reply.Values, originals)
+		if err != nil {
+			return &idl.UpdateConfigurationReply{}, fmt.Errorf("on host %q: %w", hostname, err)
+		}
+
+		for _, path := range reply.RolledBackPaths {
+			log.Printf("rolled back %s since verifying it after writing failed", path)
+		}
+	}
+
+	return reply, nil
+}
+
+// localConfAgent is the client of an agent for the coordinator host when no
+// agent runs on it, serving its conf requests within the hub. It supports
+// only the requests of a conf update and its verification, failing the others
+// as unimplemented.
+type localConfAgent struct {
+	hostname string
+}
+
+var _ idl.AgentClient = localConfAgent{}
+
+func (a localConfAgent) unsupported(method string) error {
+	return status.Errorf(codes.Unimplemented, "%s is not supported on host %s without an agent", method, a.hostname)
+}
+
+func (a localConfAgent) UpdateConfiguration(ctx context.Context, req *idl.UpdateConfigurationRequest, opts ...grpc.CallOption) (*idl.UpdateConfigurationReply, error) {
+	return HandleUpdateConfiguration(ctx, a.hostname, req)
+}
+
+func (a localConfAgent) ReadConfiguration(ctx context.Context, req *idl.ReadConfigurationRequest, opts ...grpc.CallOption) (*idl.ReadConfigurationReply, error) {
+	return HandleReadConfiguration(a.hostname, req)
+}
+
+func (a localConfAgent) ConfUpdateHeartbeat(ctx context.Context, req *idl.ConfUpdateHeartbeatRequest, opts ...grpc.CallOption) (*idl.ConfUpdateHeartbeatReply, error) {
+	progress, active := ConfUpdateProgress()
+	return &idl.ConfUpdateHeartbeatReply{Progress: progress, ActiveUpdates: active}, nil
+}
+
+func (a localConfAgent) CreateBackupDirectory(context.Context, *idl.CreateBackupDirectoryRequest, ...grpc.CallOption) (*idl.CreateBackupDirectoryReply, error) {
+	return nil, a.unsupported("CreateBackupDirectory")
+}
+
+func (a localConfAgent) CheckDiskSpace(context.Context, *idl.CheckSegmentDiskSpaceRequest, ...grpc.CallOption) (*idl.CheckDiskSpaceReply, error) {
+	return nil, a.unsupported("CheckDiskSpace")
+}
+
+func (a localConfAgent) UpgradePrimaries(context.Context, *idl.UpgradePrimariesRequest, ...grpc.CallOption) (*idl.UpgradePrimariesReply, error) {
+	return nil, a.unsupported("UpgradePrimaries")
+}
+
+func (a localConfAgent) RenameDirectories(context.Context, *idl.RenameDirectoriesRequest, ...grpc.CallOption) (*idl.RenameDirectoriesReply, error) {
+	return nil, a.unsupported("RenameDirectories")
+}
+
+func (a localConfAgent) StopAgent(context.Context, *idl.StopAgentRequest, ...grpc.CallOption) (*idl.StopAgentReply, error) {
+	return nil, a.unsupported("StopAgent")
+}
+
+func (a localConfAgent) DeleteDataDirectories(context.Context, *idl.DeleteDataDirectoriesRequest, ...grpc.CallOption) (*idl.DeleteDataDirectoriesReply, error) {
+	return nil, a.unsupported("DeleteDataDirectories")
+}
+
+func (a localConfAgent) DeleteBackupDirectory(context.Context, *idl.DeleteBackupDirectoryRequest, ...grpc.CallOption) (*idl.DeleteBackupDirectoryReply, error) {
+	return nil, a.unsupported("DeleteBackupDirectory")
+}
+
+func (a localConfAgent) DeleteStateDirectory(context.Context, *idl.DeleteStateDirectoryRequest, ...grpc.CallOption) (*idl.DeleteStateDirectoryReply, error) {
+	return nil, a.unsupported("DeleteStateDirectory")
+}
+
+func (a localConfAgent) DeleteTablespaceDirectories(context.Context, *idl.DeleteTablespaceRequest, ...grpc.CallOption) (*idl.DeleteTablespaceReply, error) {
+	return nil, a.unsupported("DeleteTablespaceDirectories")
+}
+
+func (a localConfAgent) ArchiveLogDirectory(context.Context, *idl.ArchiveLogDirectoryRequest, ...grpc.CallOption) (*idl.ArchiveLogDirectoryReply, error) {
+	return nil, a.unsupported("ArchiveLogDirectory")
+}
+
+func (a localConfAgent) RsyncDataDirectories(context.Context, *idl.RsyncRequest, ...grpc.CallOption) (*idl.RsyncReply, error) {
+	return nil, a.unsupported("RsyncDataDirectories")
+}
+
+func (a localConfAgent) RsyncTablespaceDirectories(context.Context, *idl.RsyncRequest, ...grpc.CallOption) (*idl.RsyncReply, error) {
+	return nil, a.unsupported("RsyncTablespaceDirectories")
+}
+
+func (a localConfAgent) RestorePrimariesPgControl(context.Context, *idl.RestorePgControlRequest, ...grpc.CallOption) (*idl.RestorePgControlReply, error) {
+	return nil, a.unsupported("RestorePrimariesPgControl")
+}
+
+func (a localConfAgent) RenameTablespaces(context.Context, *idl.RenameTablespacesRequest, ...grpc.CallOption) (*idl.RenameTablespacesReply, error) {
+	return nil, a.unsupported("RenameTablespaces")
+}
+
+func (a localConfAgent) CreateRecoveryConf(context.Context, *idl.CreateRecoveryConfRequest, ...grpc.CallOption) (*idl.CreateRecoveryConfReply, error) {
+	return nil, a.unsupported("CreateRecoveryConf")
+}
+
+func (a localConfAgent) AddReplicationEntries(context.Context, *idl.AddReplicationEntriesRequest, ...grpc.CallOption) (*idl.AddReplicationEntriesReply, error) {
+	return nil, a.unsupported("AddReplicationEntries")
+}
+
+func (a localConfAgent) InventorySegments(context.Context, *idl.InventorySegmentsRequest, ...grpc.CallOption) (*idl.InventorySegmentsReply, error) {
+	return nil, a.unsupported("InventorySegments")
+}
+
+func (a localConfAgent) ListConfBackups(context.Context, *idl.ListConfBackupsRequest, ...grpc.CallOption) (*idl.ListConfBackupsReply, error) {
+	return nil, a.unsupported("ListConfBackups")
+}
+
+func (a localConfAgent) GetCapabilities(context.Context, *idl.GetCapabilitiesRequest, ...grpc.CallOption) (*idl.GetCapabilitiesReply, error) {
+	return nil, a.unsupported("GetCapabilities")
+}
+
+func (a localConfAgent) SnapshotConfFiles(context.Context, *idl.SnapshotConfFilesRequest, ...grpc.CallOption) (*idl.SnapshotConfFilesReply, error) {
+	return nil, a.unsupported("SnapshotConfFiles")
+}
+
+func (a localConfAgent) RestoreConfBackups(context.Context, *idl.RestoreConfBackupsRequest, ...grpc.CallOption) (*idl.RestoreConfBackupsReply, error) {
+	return nil, a.unsupported("RestoreConfBackups")
+}
+
+func (a localConfAgent) DeleteConfBackups(context.Context, *idl.DeleteConfBackupsRequest, ...grpc.CallOption) (*idl.DeleteConfBackupsReply, error) {
+	return nil, a.unsupported("DeleteConfBackups")
+}
+
+func (a localConfAgent) ReadConfJournal(context.Context, *idl.ReadConfJournalRequest, ...grpc.CallOption) (*idl.ReadConfJournalReply, error) {
+	return nil, a.unsupported("ReadConfJournal")
+}
+
+// coordinatorConfConn returns the connection the conf edits of the
+// coordinator are sent through, which is the agent on its host when there is
+// one and otherwise a local agent in the hub.
+func coordinatorConfConn(agentConns []*idl.Connection, hostname string) *idl.Connection {
+	for _, conn := range agentConns {
+		if conn.Hostname == hostname {
+			return conn
+		}
+	}
+
+	return &idl.Connection{AgentClient: localConfAgent{hostname: hostname}, Hostname: hostname}
+}
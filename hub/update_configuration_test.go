@@ -0,0 +1,95 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/blang/semver/v4"
+	"github.com/golang/mock/gomock"
+
+	"github.com/greenplum-db/gpupgrade/config"
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/idl/mock_idl"
+	"github.com/greenplum-db/gpupgrade/step"
+	"github.com/greenplum-db/gpupgrade/testutils"
+)
+
+func TestUpdateCoordinatorConfFiles(t *testing.T) {
+	stateDir := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, stateDir)
+
+	resetEnv := testutils.SetEnv(t, "GPUPGRADE_HOME", stateDir)
+	defer resetEnv()
+
+	coordinatorDir := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, coordinatorDir)
+
+	path := filepath.Join(coordinatorDir, "postgresql.conf")
+
+	intermediate := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "cdw", DataDir: coordinatorDir, Port: 50432, Role: greenplum.PrimaryRole},
+	})
+	target := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "cdw", DataDir: coordinatorDir, Port: 15432, Role: greenplum.PrimaryRole},
+	})
+
+	version := semver.MustParse("7.0.0")
+
+	t.Run("sends the edits through the agent on the coordinator host", func(t *testing.T) {
+		testutils.MustWriteToFile(t, path, "port=50432\n")
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		cdw := mock_idl.NewMockAgentClient(ctrl)
+		cdw.EXPECT().UpdateConfiguration(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, req *idl.UpdateConfigurationRequest, opts ...interface{}) (*idl.UpdateConfigurationReply, error) {
+				paths := make(map[string]bool)
+				for _, opt := range req.GetOptions() {
+					paths[opt.GetPath()] = true
+				}
+
+				if !paths[path] {
+					t.Errorf("got options %v want an edit of %s", req.GetOptions(), path)
+				}
+
+				return &idl.UpdateConfigurationReply{ChangedPaths: []string{path}}, nil
+			})
+
+		agentConns := []*idl.Connection{{AgentClient: cdw, Hostname: "cdw"}}
+
+		err := hub.UpdateConfFiles(context.Background(), agentConns, nil, step.DevNullStream, config.StateVersion, hub.ConfUpdateOptions{}, version, intermediate, target)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		// the mocked agent made no edits
+		contents := testutils.MustReadFile(t, path)
+		if contents != "port=50432\n" {
+			t.Errorf("got %q want it unchanged", contents)
+		}
+	})
+
+	t.Run("serves the edits within the hub when no agent runs on the coordinator host", func(t *testing.T) {
+		testutils.MustWriteToFile(t, path, "port=50432\n")
+
+		hub.SetVerifyAfterWrite(true)
+		defer hub.ResetVerifyAfterWrite()
+
+		err := hub.UpdateConfFiles(context.Background(), nil, nil, step.DevNullStream, config.StateVersion, hub.ConfUpdateOptions{}, version, intermediate, target)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		contents := testutils.MustReadFile(t, path)
+		if contents != "port=15432\n" {
+			t.Errorf("got %q want %q", contents, "port=15432\n")
+		}
+	})
+}
@@ -0,0 +1,139 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/idl"
+)
+
+// UpdatePgHbaConfOnSegments rewrites the address of the host and replication
+// entries in the pg_hba.conf of each segment whose host changed between the
+// intermediate and target clusters.
+func UpdatePgHbaConfOnSegments(ctx context.Context, agentConns []*idl.Connection, intermediate *greenplum.Cluster, target *greenplum.Cluster) error {
+	return updatePgHbaConfOnSegments(ctx, agentConns, nil, intermediate, target)
+}
+
+func updatePgHbaConfOnSegments(ctx context.Context, agentConns []*idl.Connection, changes *confChanges, intermediate *greenplum.Cluster, target *greenplum.Cluster) error {
+	moved, err := movedHosts(intermediate, target)
+	if err != nil {
+		return err
+	}
+
+	return updateConfOnHosts(ctx, agentConns, changes, func(hostname string) (ConfPlan, error) {
+		return pgHbaConfEdits(hostname, moved, target), nil
+	})
+}
+
+// hbaAddressPattern matches the address of a host entry of a pg_hba.conf,
+// such as "host replication gpadmin sdw1 trust", which follows the type,
+// database and user fields. Only an address that is the whole field is
+// matched so that a host whose name is a prefix of another is never mistaken
+// for it, and the address is compared case-insensitively as hostnames are.
+// A commented out entry is left as is.
+const hbaAddressPattern = `(^[ \t]*host(?:ssl|nossl|gssenc|nogssenc)?[ \t]+[^ \t#]+[ \t]+[^ \t#]+[ \t]+)(?i:%s)([ \t]|$)`
+
+// hbaAddressReplacement replaces the address matched between the two groups
+// of hbaAddressPattern.
+const hbaAddressReplacement = `\1%s\2`
+
+// movedHost is a host of the intermediate cluster whose segments are on
+// another host in the target cluster.
+type movedHost struct {
+	from string
+	to   string
+}
+
+// movedHosts returns the hosts of the intermediate cluster whose segments are
+// on another host in the target cluster, sorted by the intermediate host. It
+// errors when the segments of an intermediate host moved to different hosts,
+// or to a host that itself moved, as the entries of such a host cannot be
+// rewritten one address at a time.
+func movedHosts(intermediate *greenplum.Cluster, target *greenplum.Cluster) ([]movedHost, error) {
+	segments := intermediate.SelectSegments(func(*greenplum.SegConfig) bool { return true })
+	sort.Slice(segments, func(i, j int) bool { return segments[i].DbID < segments[j].DbID })
+
+	moves := make(map[string]movedHost)
+	for _, seg := range segments {
+		targets := target.Primaries
+		if seg.IsMirror() || seg.IsStandby() {
+			targets = target.Mirrors
+		}
+
+		to, ok := targets[seg.ContentID]
+		if !ok || strings.EqualFold(seg.Hostname, to.Hostname) {
+			continue
+		}
+
+		host := strings.ToLower(seg.Hostname)
+		if previous, seen := moves[host]; seen && !strings.EqualFold(previous.to, to.Hostname) {
+			return nil, xerrors.Errorf("segments of host %s in the intermediate cluster are on both host %s and host %s in the target cluster", seg.Hostname, previous.to, to.Hostname)
+		}
+
+		moves[host] = movedHost{from: seg.Hostname, to: to.Hostname}
+	}
+
+	var moved []movedHost
+	for _, move := range moves {
+		moved = append(moved, move)
+	}
+	sort.Slice(moved, func(i, j int) bool { return moved[i].from < moved[j].from })
+
+	for _, move := range moved {
+		if chained, ok := moves[strings.ToLower(move.to)]; ok {
+			return nil, xerrors.Errorf("segments of host %s moved to host %s whose segments moved to host %s", move.from, move.to, chained.to)
+		}
+	}
+
+	return moved, nil
+}
+
+// hbaAddressEdit rewrites the entries of the pg_hba.conf of a data directory
+// with the address of a moved host. The entry may not exist, such as when the
+// host was never given access, so matching no lines is not an error. Entries
+// by IP address are not rewritten, as the addresses of a host cannot be
+// paired with those of another.
+func hbaAddressEdit(hostname string, dataDir string, move movedHost) ConfEdit {
+	return ConfEdit{
+		Hostname: hostname,
+		OldValue: move.from,
+		NewValue: move.to,
+		Option: &idl.UpdateFileConfOptions{
+			Path:         filepath.Join(dataDir, "pg_hba.conf"),
+			Pattern:      fmt.Sprintf(hbaAddressPattern, regexp.QuoteMeta(move.from)),
+			Replacement:  fmt.Sprintf(hbaAddressReplacement, quoteReplacement(move.to)),
+			Reason:       ReasonHbaAddressRewrite,
+			AllowNoMatch: true,
+			DataDir:      dataDir,
+		},
+	}
+}
+
+func pgHbaConfEdits(hostname string, moved []movedHost, target *greenplum.Cluster) ConfPlan {
+	if len(moved) == 0 {
+		return nil
+	}
+
+	var edits ConfPlan
+
+	target.ForEachSegment(func(seg *greenplum.SegConfig) bool {
+		return seg.IsOnHost(hostname) && !seg.IsCoordinator() && !seg.IsStandby() && confIncluded(seg)
+	}, func(seg *greenplum.SegConfig) bool {
+		for _, move := range moved {
+			edits = append(edits, forSegment(hbaAddressEdit(hostname, seg.DataDir, move), seg))
+		}
+		return true
+	})
+
+	return coreEdits(edits)
+}
@@ -0,0 +1,195 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/blang/semver/v4"
+	"github.com/golang/mock/gomock"
+	"google.golang.org/grpc"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/idl/mock_idl"
+	"github.com/greenplum-db/gpupgrade/testutils"
+)
+
+func TestUpdatePgHbaConfOnSegments(t *testing.T) {
+	// applies the request as the agent would
+	updateConfiguration := func(ctx context.Context, req *idl.UpdateConfigurationRequest, opts ...grpc.CallOption) (*idl.UpdateConfigurationReply, error) {
+		return hub.UpdateConfigurationFileReply(ctx, req.GetOptions())
+	}
+
+	t.Run("rewrites the entries of the hosts that moved", func(t *testing.T) {
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		primaryDir := filepath.Join(dir, "seg1")
+		mirrorDir := filepath.Join(dir, "mirror1")
+		testutils.MustCreateDir(t, primaryDir)
+		testutils.MustCreateDir(t, mirrorDir)
+
+		intermediate := hub.MustCreateCluster(t, greenplum.SegConfigs{
+			{DbID: 1, ContentID: -1, Hostname: "cdw", DataDir: "/data/qddir/seg.HqtFHX54y0o.-1", Port: 50432, Role: greenplum.PrimaryRole},
+			{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg.HqtFHX54y0o.1", Port: 50434, Role: greenplum.PrimaryRole},
+			{DbID: 3, ContentID: 0, Hostname: "sdw2", DataDir: "/data/dbfast_mirror1/seg.HqtFHX54y0o.1", Port: 50435, Role: greenplum.MirrorRole},
+		})
+		target := hub.MustCreateCluster(t, greenplum.SegConfigs{
+			{DbID: 1, ContentID: -1, Hostname: "cdw", DataDir: "/data/qddir/seg-1", Port: 15432, Role: greenplum.PrimaryRole},
+			{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: primaryDir, Port: 25433, Role: greenplum.PrimaryRole},
+			{DbID: 3, ContentID: 0, Hostname: "sdw3", DataDir: mirrorDir, Port: 25434, Role: greenplum.MirrorRole},
+		})
+
+		primaryHba := filepath.Join(primaryDir, "pg_hba.conf")
+		testutils.MustWriteToFile(t, primaryHba, `# TYPE  DATABASE        USER            ADDRESS                 METHOD
+local   all             gpadmin                                 ident
+host    all             gpadmin         sdw2                    trust
+host	replication	gpadmin	SDW2	trust
+hostssl all             all             sdw2 md5
+host    all             gpadmin         sdw20                   trust
+host    all             gpadmin         sdw2.example.com        trust
+#host   all             gpadmin         sdw2                    trust
+host    replication     gpadmin         samehost                trust
+host    all             gpadmin         10.0.0.2/32             trust
+host    sdw2            gpadmin         10.0.0.3/32             trust
+`)
+
+		mirrorHba := filepath.Join(mirrorDir, "pg_hba.conf")
+		testutils.MustWriteToFile(t, mirrorHba, "host all gpadmin sdw1 trust\n")
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw1.EXPECT().UpdateConfiguration(gomock.Any(), gomock.Any()).DoAndReturn(updateConfiguration)
+
+		sdw3 := mock_idl.NewMockAgentClient(ctrl)
+		sdw3.EXPECT().UpdateConfiguration(gomock.Any(), gomock.Any()).DoAndReturn(updateConfiguration)
+
+		agentConns := []*idl.Connection{
+			{AgentClient: sdw1, Hostname: "sdw1"},
+			{AgentClient: sdw3, Hostname: "sdw3"},
+		}
+
+		err := hub.UpdatePgHbaConfOnSegments(context.Background(), agentConns, intermediate, target)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		expected := `# TYPE  DATABASE        USER            ADDRESS                 METHOD
+local   all             gpadmin                                 ident
+host    all             gpadmin         sdw3                    trust
+host	replication	gpadmin	sdw3	trust
+hostssl all             all             sdw3 md5
+host    all             gpadmin         sdw20                   trust
+host    all             gpadmin         sdw2.example.com        trust
+#host   all             gpadmin         sdw2                    trust
+host    replication     gpadmin         samehost                trust
+host    all             gpadmin         10.0.0.2/32             trust
+host    sdw2            gpadmin         10.0.0.3/32             trust
+`
+		contents := testutils.MustReadFile(t, primaryHba)
+		if contents != expected {
+			t.Errorf("got %q want %q", contents, expected)
+		}
+
+		contents = testutils.MustReadFile(t, mirrorHba)
+		if contents != "host all gpadmin sdw1 trust\n" {
+			t.Errorf("got %q want the entry of the unmoved host unchanged", contents)
+		}
+	})
+
+	t.Run("is a phase of the conf update", func(t *testing.T) {
+		intermediate := hub.MustCreateCluster(t, greenplum.SegConfigs{
+			{DbID: 1, ContentID: -1, Hostname: "cdw", DataDir: "/data/qddir/seg.HqtFHX54y0o.-1", Port: 50432, Role: greenplum.PrimaryRole},
+			{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg.HqtFHX54y0o.1", Port: 50434, Role: greenplum.PrimaryRole},
+			{DbID: 3, ContentID: 0, Hostname: "sdw2", DataDir: "/data/dbfast_mirror1/seg.HqtFHX54y0o.1", Port: 50435, Role: greenplum.MirrorRole},
+		})
+		target := hub.MustCreateCluster(t, greenplum.SegConfigs{
+			{DbID: 1, ContentID: -1, Hostname: "cdw", DataDir: "/data/qddir/seg-1", Port: 15432, Role: greenplum.PrimaryRole},
+			{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg1", Port: 25433, Role: greenplum.PrimaryRole},
+			{DbID: 3, ContentID: 0, Hostname: "sdw3", DataDir: "/data/dbfast_mirror1/seg1", Port: 25434, Role: greenplum.MirrorRole},
+		})
+
+		requests, err := hub.AgentConfRequests(semver.MustParse("7.0.0"), intermediate, target)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		opts := requests["sdw1"][hub.ConfPhaseSegmentPgHbaConf].GetOptions()
+		if len(opts) != 1 || opts[0].GetPath() != "/data/dbfast1/seg1/pg_hba.conf" || opts[0].GetReason() != hub.ReasonHbaAddressRewrite {
+			t.Errorf("got pg_hba.conf options %v want the rewrite of sdw2 in the primary", opts)
+		}
+	})
+
+	t.Run("sends nothing when no host moved", func(t *testing.T) {
+		cluster := hub.MustCreateCluster(t, greenplum.SegConfigs{
+			{DbID: 1, ContentID: -1, Hostname: "cdw", DataDir: "/data/qddir/seg-1", Port: 15432, Role: greenplum.PrimaryRole},
+			{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg1", Port: 25433, Role: greenplum.PrimaryRole},
+			{DbID: 3, ContentID: 0, Hostname: "sdw2", DataDir: "/data/dbfast_mirror1/seg1", Port: 25434, Role: greenplum.MirrorRole},
+		})
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw2 := mock_idl.NewMockAgentClient(ctrl)
+
+		agentConns := []*idl.Connection{
+			{AgentClient: sdw1, Hostname: "sdw1"},
+			{AgentClient: sdw2, Hostname: "sdw2"},
+		}
+
+		err := hub.UpdatePgHbaConfOnSegments(context.Background(), agentConns, cluster, cluster)
+		if err != nil {
+			t.Errorf("unexpected error %+v", err)
+		}
+	})
+
+	errCases := []struct {
+		name     string
+		target   greenplum.SegConfigs
+		expected string
+	}{
+		{
+			name: "errors when the segments of a host moved to different hosts",
+			target: greenplum.SegConfigs{
+				{DbID: 1, ContentID: -1, Hostname: "cdw", DataDir: "/data/qddir/seg-1", Port: 15432, Role: greenplum.PrimaryRole},
+				{DbID: 2, ContentID: 0, Hostname: "sdw3", DataDir: "/data/dbfast1/seg1", Port: 25433, Role: greenplum.PrimaryRole},
+				{DbID: 3, ContentID: 1, Hostname: "sdw4", DataDir: "/data/dbfast1/seg2", Port: 25434, Role: greenplum.PrimaryRole},
+			},
+			expected: "segments of host sdw1 in the intermediate cluster are on both host sdw3 and host sdw4 in the target cluster",
+		},
+		{
+			name: "errors when a host moved to a host that itself moved",
+			target: greenplum.SegConfigs{
+				{DbID: 1, ContentID: -1, Hostname: "cdw", DataDir: "/data/qddir/seg-1", Port: 15432, Role: greenplum.PrimaryRole},
+				{DbID: 2, ContentID: 0, Hostname: "cdw", DataDir: "/data/dbfast1/seg1", Port: 25433, Role: greenplum.PrimaryRole},
+				{DbID: 3, ContentID: 1, Hostname: "cdw", DataDir: "/data/dbfast1/seg2", Port: 25434, Role: greenplum.PrimaryRole},
+				{DbID: 4, ContentID: -1, Hostname: "sdw1", DataDir: "/data/standby", Port: 16432, Role: greenplum.MirrorRole},
+			},
+			expected: "segments of host cdw moved to host sdw1 whose segments moved to host cdw",
+		},
+	}
+
+	for _, c := range errCases {
+		t.Run(c.name, func(t *testing.T) {
+			intermediate := hub.MustCreateCluster(t, greenplum.SegConfigs{
+				{DbID: 1, ContentID: -1, Hostname: "cdw", DataDir: "/data/qddir/seg.HqtFHX54y0o.-1", Port: 50432, Role: greenplum.PrimaryRole},
+				{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg.HqtFHX54y0o.1", Port: 50434, Role: greenplum.PrimaryRole},
+				{DbID: 3, ContentID: 1, Hostname: "sdw1", DataDir: "/data/dbfast1/seg.HqtFHX54y0o.2", Port: 50435, Role: greenplum.PrimaryRole},
+				{DbID: 4, ContentID: -1, Hostname: "cdw", DataDir: "/data/standby.HqtFHX54y0o", Port: 50433, Role: greenplum.MirrorRole},
+			})
+
+			err := hub.UpdatePgHbaConfOnSegments(context.Background(), nil, intermediate, hub.MustCreateCluster(t, c.target))
+			if err == nil || err.Error() != c.expected {
+				t.Errorf("got error %v want %q", err, c.expected)
+			}
+		})
+	}
+}
@@ -16,7 +16,7 @@ import (
 	"github.com/greenplum-db/gpupgrade/utils/errorlist"
 )
 
-func UpgradeMirrorsUsingRsync(agentConns []*idl.Connection, source *greenplum.Cluster, intermediate *greenplum.Cluster, useHbaHostnames bool) error {
+func UpgradeMirrorsUsingRsync(agentConns []*idl.Connection, source *greenplum.Cluster, intermediate *greenplum.Cluster, target *greenplum.Cluster, useHbaHostnames bool) error {
 	db, err := sql.Open("pgx", intermediate.Connection())
 	if err != nil {
 		return err
@@ -59,6 +59,10 @@ func UpgradeMirrorsUsingRsync(agentConns []*idl.Connection, source *greenplum.Cl
 		return err
 	}
 
+	if err := UpdateInternalAutoConfOnPrimaries(agentConns, intermediate, target); err != nil {
+		return err
+	}
+
 	if err := intermediate.StartCoordinatorOnly(step.DevNullStream); err != nil {
 		return err
 	}
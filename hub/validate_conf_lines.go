@@ -0,0 +1,57 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"regexp"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+var confNamePattern = regexp.MustCompile(`^[ \t]*([A-Za-z_][A-Za-z0-9_.]*)`)
+
+// confAssignmentPattern matches a complete single line GUC assignment with a
+// non-empty value that is either a terminated quoted string or an unquoted
+// token, followed by an optional comment.
+var confAssignmentPattern = regexp.MustCompile(`^[ \t]*[A-Za-z_][A-Za-z0-9_.]*([ \t]*=[ \t]*|[ \t]+)('(?:[^'\\]|''|\\.)*'|[^ \t#'=][^ \t#']*)[ \t]*(#.*)?$`)
+
+// validateSingleLineGUC errors when an assignment of the GUC in the conf file
+// at path is not a complete single line. Conf files written by the server
+// always assign a GUC on one line, so a split assignment indicates a corrupt
+// file that a line based rewrite would make worse. Postgres has no dollar
+// quoting and ends a quoted value at the end of its line, so a primary_conninfo
+// that is dollar-quoted or continued onto the next line would not start the
+// segment even with its port rewritten, and is reported rather than parsed. No
+// validation is done when guc is empty.
+func validateSingleLineGUC(path string, guc string) error {
+	if guc == "" {
+		return nil
+	}
+
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return xerrors.Errorf("validate %s: %w", path, err)
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(contents))
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := scanner.Text()
+
+		match := confNamePattern.FindStringSubmatch(line)
+		if match == nil || !strings.EqualFold(match[1], guc) {
+			continue
+		}
+
+		if !confAssignmentPattern.MatchString(line) {
+			return xerrors.Errorf("%s:%d: assignment of %s %q is not a complete single line of the form %s = value. "+
+				"The file appears to be malformed. Fix it before retrying.", path, lineNumber, guc, line, guc)
+		}
+	}
+
+	return scanner.Err()
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/testutils"
+)
+
+func TestValidateSingleLineGUC(t *testing.T) {
+	portOption := func(path string) []*idl.UpdateFileConfOptions {
+		return []*idl.UpdateFileConfOptions{{
+			Path:        path,
+			Pattern:     fmt.Sprintf(`(^[ \t]*port[ \t]*=[ \t]*)%d([^0-9]|$)`, 5000),
+			Replacement: fmt.Sprintf(`\1%d\2`, 6000),
+			Guc:         "port",
+		}}
+	}
+
+	t.Run("edits complete single line assignments", func(t *testing.T) {
+		cases := []struct {
+			name     string
+			contents string
+			expected string
+		}{
+			{name: "no spaces", contents: "port=5000\n", expected: "port=6000\n"},
+			{name: "indented with a comment", contents: "  port = 5000 # moved\n", expected: "  port = 6000 # moved\n"},
+			{name: "other malformed gucs", contents: "shared_buffers =\n#port\nport=5000\n", expected: "shared_buffers =\n#port\nport=6000\n"},
+			{name: "among several lines", contents: "listen_addresses = '*'\n\n# port = 1\nport = 5000\nmax_connections = 750\n", expected: "listen_addresses = '*'\n\n# port = 1\nport = 6000\nmax_connections = 750\n"},
+			{name: "a continued line of another guc", contents: "shared_preload_libraries = 'a, \\\nb'\nport=5000\n", expected: "shared_preload_libraries = 'a, \\\nb'\nport=6000\n"},
+			{name: "a commented continued line", contents: "# port = 4000 \\\nport=5000\n", expected: "# port = 4000 \\\nport=6000\n"},
+		}
+
+		for _, c := range cases {
+			t.Run(c.name, func(t *testing.T) {
+				dir := testutils.GetTempDir(t, "")
+				defer testutils.MustRemoveAll(t, dir)
+
+				path := filepath.Join(dir, "postgresql.conf")
+				testutils.MustWriteToFile(t, path, c.contents)
+
+				err := hub.UpdateConfigurationFile(context.Background(), portOption(path))
+				if err != nil {
+					t.Fatalf("unexpected error %+v", err)
+				}
+
+				contents := testutils.MustReadFile(t, path)
+				if contents != c.expected {
+					t.Errorf("got %q want %q", contents, c.expected)
+				}
+			})
+		}
+	})
+
+	t.Run("errors on a quoted assignment the pattern does not match", func(t *testing.T) {
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		path := filepath.Join(dir, "postgresql.conf")
+		testutils.MustWriteToFile(t, path, "port = '5000'\n")
+
+		err := hub.UpdateConfigurationFile(context.Background(), portOption(path))
+		if err == nil || !strings.Contains(err.Error(), "matched no lines") {
+			t.Errorf("expected error %v to contain %q", err, "matched no lines")
+		}
+
+		contents := testutils.MustReadFile(t, path)
+		if contents != "port = '5000'\n" {
+			t.Errorf("got %q want %q", contents, "port = '5000'\n")
+		}
+	})
+
+	t.Run("rejects assignments split across lines without editing", func(t *testing.T) {
+		cases := []struct {
+			name     string
+			guc      string
+			contents string
+			expected string
+		}{
+			{name: "name without a value", guc: "port", contents: "port\n= 5000\n", expected: ":1: assignment of port"},
+			{name: "value on the next line", guc: "port", contents: "listen_addresses = '*'\nport =\n5000\n", expected: ":2: assignment of port"},
+			{name: "unterminated quote", guc: "primary_conninfo", contents: "primary_conninfo = 'user=gpadmin\nport=5000'\n", expected: ":1: assignment of primary_conninfo"},
+			{name: "dollar quoted", guc: "primary_conninfo", contents: "primary_conninfo = $$user=gpadmin port=5000$$\n", expected: ":1: assignment of primary_conninfo"},
+			{name: "split after complete lines", guc: "port", contents: "listen_addresses = '*'\nmax_connections = 750\nport\n= 5000\n", expected: ":3: assignment of port"},
+			{name: "continued with a backslash", guc: "port", contents: "port = 5000 \\\n# moved\n", expected: ":1: assignment of port"},
+			{name: "quoted value continued with a backslash", guc: "primary_conninfo", contents: "primary_conninfo = 'user=gpadmin \\\nport=5000'\n", expected: ":1: assignment of primary_conninfo"},
+		}
+
+		for _, c := range cases {
+			t.Run(c.name, func(t *testing.T) {
+				dir := testutils.GetTempDir(t, "")
+				defer testutils.MustRemoveAll(t, dir)
+
+				path := filepath.Join(dir, "postgresql.conf")
+				testutils.MustWriteToFile(t, path, c.contents)
+
+				opts := portOption(path)
+				opts[0].Guc = c.guc
+
+				err := hub.UpdateConfigurationFile(context.Background(), opts)
+				if err == nil || !strings.Contains(err.Error(), c.expected) {
+					t.Errorf("expected error %v to contain %q", err, c.expected)
+				}
+
+				contents := testutils.MustReadFile(t, path)
+				if contents != c.contents {
+					t.Errorf("got %q want %q", contents, c.contents)
+				}
+
+				testutils.PathMustNotExist(t, path+hub.BackupSuffix)
+			})
+		}
+	})
+}
@@ -0,0 +1,38 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"regexp"
+
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/utils/errorlist"
+)
+
+// ValidateConfPatterns compiles the pattern of each edit of the plan,
+// returning every one that is malformed. The patterns are built from a few
+// base patterns, so a bad one is caught here before any host is changed
+// rather than by an agent partway through the update.
+func ValidateConfPatterns(plan ConfPlan) error {
+	compiled := make(map[string]bool)
+
+	var err error
+	for _, edit := range plan {
+		pattern := edit.Option.GetPattern()
+		if compiled[pattern] {
+			continue
+		}
+
+		if _, cErr := regexp.Compile(pattern); cErr != nil {
+			err = errorlist.Append(err, xerrors.Errorf("edit of %s%s on host %s has invalid pattern %q: %w",
+				edit.Option.GetPath(), reasonSuffix(edit.Option), edit.Hostname, pattern, cErr))
+			continue
+		}
+
+		compiled[pattern] = true
+	}
+
+	return err
+}
@@ -0,0 +1,109 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/blang/semver/v4"
+	"github.com/golang/mock/gomock"
+
+	"github.com/greenplum-db/gpupgrade/config"
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/idl/mock_idl"
+	"github.com/greenplum-db/gpupgrade/step"
+	"github.com/greenplum-db/gpupgrade/testutils"
+	"github.com/greenplum-db/gpupgrade/utils/errorlist"
+)
+
+func TestValidateConfPatterns(t *testing.T) {
+	t.Run("accepts a plan whose patterns compile", func(t *testing.T) {
+		plan := hub.ConfPlan{
+			{Hostname: "sdw1", Option: &idl.UpdateFileConfOptions{Path: "/data/dbfast1/seg1/postgresql.conf", Pattern: `(^port=)50434`, Reason: hub.ReasonPortRewrite}},
+			{Hostname: "sdw2", Option: &idl.UpdateFileConfOptions{Path: "/data/dbfast2/seg2/postgresql.conf", Pattern: `(^port=)50434`, Reason: hub.ReasonPortRewrite}},
+		}
+
+		err := hub.ValidateConfPatterns(plan)
+		if err != nil {
+			t.Errorf("unexpected error %+v", err)
+		}
+	})
+
+	t.Run("returns every malformed pattern", func(t *testing.T) {
+		plan := hub.ConfPlan{
+			{Hostname: "sdw1", Option: &idl.UpdateFileConfOptions{Path: "/data/dbfast1/seg1/postgresql.conf", Pattern: `(^port=50434`, Reason: hub.ReasonPortRewrite}},
+			{Hostname: "sdw1", Option: &idl.UpdateFileConfOptions{Path: "/data/dbfast1/seg1/postgresql.auto.conf", Pattern: `(^port=)50434`}},
+			{Hostname: "sdw2", Option: &idl.UpdateFileConfOptions{Path: "/data/dbfast2/seg2/postgresql.conf", Pattern: `[a-`}},
+		}
+
+		err := hub.ValidateConfPatterns(plan)
+		var errs errorlist.Errors
+		if !errors.As(err, &errs) {
+			t.Fatalf("got error %#v want type %T", err, errs)
+		}
+
+		if len(errs) != 2 {
+			t.Fatalf("got %d errors want 2: %v", len(errs), errs)
+		}
+
+		expected := []string{
+			`edit of /data/dbfast1/seg1/postgresql.conf for port-rewrite on host sdw1 has invalid pattern "(^port=50434"`,
+			`edit of /data/dbfast2/seg2/postgresql.conf on host sdw2 has invalid pattern "[a-"`,
+		}
+		for i, e := range errs {
+			if !strings.HasPrefix(e.Error(), expected[i]) {
+				t.Errorf("got error %q want prefix %q", e.Error(), expected[i])
+			}
+		}
+	})
+
+	t.Run("the conf update sends no edits when a pattern is malformed", func(t *testing.T) {
+		stateDir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, stateDir)
+
+		resetEnv := testutils.SetEnv(t, "GPUPGRADE_HOME", stateDir)
+		defer resetEnv()
+
+		coordinatorDir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, coordinatorDir)
+
+		coordinatorConf := filepath.Join(coordinatorDir, "postgresql.conf")
+		testutils.MustWriteToFile(t, coordinatorConf, "port=50432\n")
+
+		intermediate := hub.MustCreateCluster(t, greenplum.SegConfigs{
+			{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: coordinatorDir, Port: 50432, Role: greenplum.PrimaryRole},
+			{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg.HqtFHX54y0o.1", Port: 50434, Role: greenplum.PrimaryRole},
+		})
+		target := hub.MustCreateCluster(t, greenplum.SegConfigs{
+			{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: coordinatorDir, Port: 15432, Role: greenplum.PrimaryRole},
+			{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg1", Port: 25433, Role: greenplum.PrimaryRole},
+		})
+
+		hub.SetCustomConfEdits([]hub.CustomConfEdit{{File: "postgresql.conf", Pattern: `^(shared_buffers = .*$`, Replacement: "shared_buffers = 1GB"}})
+		defer hub.ResetCustomConfEdits()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// no calls are expected
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+
+		err := hub.UpdateConfFiles(context.Background(), []*idl.Connection{{AgentClient: sdw1, Hostname: "sdw1"}}, nil, step.DevNullStream, config.StateVersion, hub.ConfUpdateOptions{}, semver.MustParse("7.0.0"), intermediate, target)
+		expected := `has invalid pattern "^(shared_buffers = .*$"`
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("got error %v want it to contain %q", err, expected)
+		}
+
+		contents := testutils.MustReadFile(t, coordinatorConf)
+		if contents != "port=50432\n" {
+			t.Errorf("got %q want the coordinator conf unchanged", contents)
+		}
+	})
+}
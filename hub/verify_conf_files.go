@@ -0,0 +1,319 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/blang/semver/v4"
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/idl"
+)
+
+// ConfValue is the expected and actual value of a GUC in a conf file of a
+// segment in the target cluster.
+type ConfValue struct {
+	Hostname string
+	Path     string
+	Name     string
+	Expected string
+	Actual   string
+	Found    bool
+	// Optional values, such as those of the optional gpperfmon.conf, are
+	// only verified when set.
+	Optional bool
+}
+
+func (v ConfValue) Matches() bool {
+	return v.Found && v.Actual == v.Expected
+}
+
+type ConfValues []ConfValue
+
+func (c ConfValues) Mismatches() ConfValues {
+	var mismatches ConfValues
+	for _, value := range c {
+		if !value.Matches() {
+			mismatches = append(mismatches, value)
+		}
+	}
+
+	return mismatches
+}
+
+// ConfFileMismatches are the mismatched values of a conf file on a host.
+type ConfFileMismatches struct {
+	Hostname string
+	Path     string
+	Values   ConfValues
+}
+
+// MismatchesByFile returns the mismatches grouped by host and file, sorted
+// by hostname and then path.
+func (c ConfValues) MismatchesByFile() []ConfFileMismatches {
+	var files []ConfFileMismatches
+	index := make(map[string]int)
+	for _, value := range c.Mismatches() {
+		key := segmentKey(value.Hostname, value.Path)
+		i, ok := index[key]
+		if !ok {
+			i = len(files)
+			index[key] = i
+			files = append(files, ConfFileMismatches{Hostname: value.Hostname, Path: value.Path})
+		}
+
+		files[i].Values = append(files[i].Values, value)
+	}
+
+	sort.SliceStable(files, func(i, j int) bool {
+		if files[i].Hostname != files[j].Hostname {
+			return files[i].Hostname < files[j].Hostname
+		}
+
+		return files[i].Path < files[j].Path
+	})
+
+	return files
+}
+
+func (c ConfValues) Table() [][]string {
+	table := [][]string{{"Hostname", "Path", "Name", "Expected", "Actual"}}
+	for _, value := range c {
+		actual := value.Actual
+		if !value.Found {
+			actual = "<not set>"
+		}
+
+		table = append(table, []string{value.Hostname, value.Path, value.Name, value.Expected, actual})
+	}
+
+	return table
+}
+
+func (c ConfValues) String() string {
+	var b bytes.Buffer
+	var t tabwriter.Writer
+	t.Init(&b, 0, 0, 2, ' ', 0)
+
+	for _, row := range c.Table() {
+		fmt.Fprintln(&t, strings.Join(row, "\t"))
+	}
+
+	t.Flush()
+	return b.String()
+}
+
+// VerifyConfFiles reads the current port, gp_dbid, and primary_conninfo port
+// of every segment in the target cluster, along with the gpperfmon log
+// location of the coordinator, and returns them along with the values
+// expected from the target cluster. These are the files edited by
+// UpdateConfFiles, whose edits it verifies without making any. Each agent is
+// sent a single request for all of the files on its host, and the
+// coordinator is read through the same connection its edits are sent
+// through.
+func VerifyConfFiles(agentConns []*idl.Connection, version semver.Version, target *greenplum.Cluster) (ConfValues, error) {
+	hostname := target.CoordinatorHostname()
+	coordinator, err := expectedConfValues(hostname, version, target, func(seg *greenplum.SegConfig) bool {
+		return seg.IsCoordinator()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logLocation, err := gpperfmonConfEdit(hostname, target.CoordinatorDataDir())
+	if err != nil {
+		return nil, err
+	}
+
+	coordinator = append(coordinator, ConfValue{
+		Hostname: hostname,
+		Path:     logLocation.Option.GetPath(),
+		Name:     logLocation.Option.GetGuc(),
+		Expected: logLocation.NewValue,
+		Optional: true,
+	})
+
+	conn := coordinatorConfConn(agentConns, hostname)
+	values, err := readConfValues(coordinator, func(files []*idl.ReadConfigurationRequest_File) ([]*idl.ReadConfigurationReply_Value, error) {
+		reply, err := conn.AgentClient.ReadConfiguration(context.Background(), &idl.ReadConfigurationRequest{Files: files})
+		if err != nil {
+			return nil, xerrors.Errorf("read configuration on host %s: %w", hostname, err)
+		}
+
+		return reply.GetValues(), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(chan ConfValues, len(agentConns))
+	request := func(ctx context.Context, conn *idl.Connection) error {
+		expected, err := expectedConfValues(conn.Hostname, version, target, func(seg *greenplum.SegConfig) bool {
+			return seg.IsOnHost(conn.Hostname) && !seg.IsCoordinator()
+		})
+		if err != nil {
+			return err
+		}
+
+		if len(expected) == 0 {
+			return nil
+		}
+
+		read := func(files []*idl.ReadConfigurationRequest_File) ([]*idl.ReadConfigurationReply_Value, error) {
+			var reply *idl.ReadConfigurationReply
+			err := retryRPC(ctx, conn.Hostname, func() error {
+				return withConfRPCTimeout(ctx, conn.Hostname, "read conf files", func(ctx context.Context) error {
+					var err error
+					reply, err = conn.AgentClient.ReadConfiguration(ctx, &idl.ReadConfigurationRequest{Files: files})
+					return err
+				})
+			})
+			if err != nil {
+				return nil, xerrors.Errorf("read configuration on host %s: %w", conn.Hostname, err)
+			}
+
+			return reply.GetValues(), nil
+		}
+
+		actual, err := readConfValues(expected, read)
+		if err != nil {
+			return err
+		}
+
+		results <- actual
+		return nil
+	}
+
+	err = executeConfRPC(context.Background(), agentConns, request, nil, FailOnUnreachable).Err()
+	close(results)
+	if err != nil {
+		return nil, err
+	}
+
+	for result := range results {
+		values = append(values, result...)
+	}
+
+	sort.SliceStable(values, func(i, j int) bool {
+		if values[i].Hostname != values[j].Hostname {
+			return values[i].Hostname < values[j].Hostname
+		}
+
+		return values[i].Path < values[j].Path
+	})
+
+	return values, nil
+}
+
+// expectedConfValues returns the expected value of each verified GUC for the
+// selected segments of the target cluster on a host.
+func expectedConfValues(hostname string, version semver.Version, target *greenplum.Cluster, selector func(seg *greenplum.SegConfig) bool) (ConfValues, error) {
+	var values ConfValues
+	var err error
+
+	target.ForEachSegment(selector, func(seg *greenplum.SegConfig) bool {
+		values = append(values,
+			ConfValue{Hostname: hostname, Path: filepath.Join(seg.DataDir, "postgresql.conf"), Name: "port", Expected: strconv.Itoa(seg.Port)},
+			ConfValue{Hostname: hostname, Path: filepath.Join(seg.DataDir, "internal.auto.conf"), Name: "gp_dbid", Expected: strconv.Itoa(seg.DbID)},
+		)
+
+		if !seg.IsMirror() && !seg.IsStandby() {
+			return true
+		}
+
+		primary, ok := target.Primaries[seg.ContentID]
+		if !ok {
+			err = xerrors.Errorf("mirror with content %d on host %s has no target primary", seg.ContentID, hostname)
+			return false
+		}
+
+		values = append(values, ConfValue{
+			Hostname: hostname,
+			Path:     filepath.Join(seg.DataDir, recoveryConfFile(version)),
+			Name:     "primary_conninfo",
+			Expected: strconv.Itoa(primary.Port),
+		})
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+// readConfValues fills in the actual values of the expected values using a
+// single read of all files. Optional values that are not set are left out,
+// and a file with only optional values may be absent.
+func readConfValues(expected ConfValues, read func([]*idl.ReadConfigurationRequest_File) ([]*idl.ReadConfigurationReply_Value, error)) (ConfValues, error) {
+	if len(expected) == 0 {
+		return nil, nil
+	}
+
+	var files []*idl.ReadConfigurationRequest_File
+	fileIndex := make(map[string]*idl.ReadConfigurationRequest_File)
+	for _, value := range expected {
+		file, ok := fileIndex[value.Path]
+		if !ok {
+			file = &idl.ReadConfigurationRequest_File{Path: value.Path, SkipIfAbsent: true}
+			fileIndex[value.Path] = file
+			files = append(files, file)
+		}
+
+		file.Names = append(file.Names, value.Name)
+		file.SkipIfAbsent = file.SkipIfAbsent && value.Optional
+	}
+
+	replies, err := read(files)
+	if err != nil {
+		return nil, err
+	}
+
+	type key struct{ path, name string }
+	actuals := make(map[key]*idl.ReadConfigurationReply_Value)
+	for _, reply := range replies {
+		actuals[key{reply.GetPath(), reply.GetName()}] = reply
+	}
+
+	var values ConfValues
+	for _, value := range expected {
+		if actual, ok := actuals[key{value.Path, value.Name}]; ok && actual.GetFound() {
+			value.Found = true
+			value.Actual = actual.GetValue()
+			if value.Name == "primary_conninfo" {
+				value.Actual, value.Found = conninfoPort(actual.GetValue())
+			}
+		}
+
+		if value.Optional && !value.Found {
+			continue
+		}
+
+		values = append(values, value)
+	}
+
+	return values, nil
+}
+
+var conninfoPortPattern = regexp.MustCompile(`(^|[ \t])port[ \t]*=[ \t]*(?:'|\\')?([0-9]+)`)
+
+// conninfoPort returns the port within a primary_conninfo connection string.
+func conninfoPort(conninfo string) (string, bool) {
+	match := conninfoPortPattern.FindStringSubmatch(conninfo)
+	if match == nil {
+		return "", false
+	}
+
+	return match[2], true
+}
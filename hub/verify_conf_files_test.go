@@ -0,0 +1,258 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/blang/semver/v4"
+	"github.com/golang/mock/gomock"
+	"google.golang.org/grpc"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/idl/mock_idl"
+	"github.com/greenplum-db/gpupgrade/testutils"
+)
+
+func TestVerifyConfFiles(t *testing.T) {
+	coordinatorDir := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, coordinatorDir)
+
+	testutils.MustWriteToFile(t, filepath.Join(coordinatorDir, "postgresql.conf"), "port=15432\n")
+	testutils.MustWriteToFile(t, filepath.Join(coordinatorDir, "internal.auto.conf"), "gp_dbid=1\n")
+
+	target := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: coordinatorDir, Port: 15432, Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: -1, Hostname: "standby", DataDir: "/data/standby", Port: 16432, Role: greenplum.MirrorRole},
+		{DbID: 3, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg1", Port: 25433, Role: greenplum.PrimaryRole},
+		{DbID: 4, ContentID: 0, Hostname: "sdw2", DataDir: "/data/dbfast_mirror1/seg1", Port: 25434, Role: greenplum.MirrorRole},
+	})
+
+	t.Run("reports actual and expected values cluster-wide with one request per host", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		standby := mock_idl.NewMockAgentClient(ctrl)
+		standby.EXPECT().ReadConfiguration(
+			gomock.Any(),
+			&idl.ReadConfigurationRequest{Files: []*idl.ReadConfigurationRequest_File{
+				{Path: "/data/standby/postgresql.conf", Names: []string{"port"}},
+				{Path: "/data/standby/internal.auto.conf", Names: []string{"gp_dbid"}},
+				{Path: "/data/standby/postgresql.auto.conf", Names: []string{"primary_conninfo"}},
+			}},
+		).Return(&idl.ReadConfigurationReply{Values: []*idl.ReadConfigurationReply_Value{
+			{Path: "/data/standby/postgresql.conf", Name: "port", Value: "16432", Found: true},
+			{Path: "/data/standby/internal.auto.conf", Name: "gp_dbid", Value: "2", Found: true},
+			{Path: "/data/standby/postgresql.auto.conf", Name: "primary_conninfo", Value: "user=gpadmin host=coordinator port=15432", Found: true},
+		}}, nil).Times(1)
+
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw1.EXPECT().ReadConfiguration(
+			gomock.Any(),
+			&idl.ReadConfigurationRequest{Files: []*idl.ReadConfigurationRequest_File{
+				{Path: "/data/dbfast1/seg1/postgresql.conf", Names: []string{"port"}},
+				{Path: "/data/dbfast1/seg1/internal.auto.conf", Names: []string{"gp_dbid"}},
+			}},
+		).Return(&idl.ReadConfigurationReply{Values: []*idl.ReadConfigurationReply_Value{
+			{Path: "/data/dbfast1/seg1/postgresql.conf", Name: "port", Value: "50434", Found: true},
+			{Path: "/data/dbfast1/seg1/internal.auto.conf", Name: "gp_dbid", Value: "3", Found: true},
+		}}, nil).Times(1)
+
+		sdw2 := mock_idl.NewMockAgentClient(ctrl)
+		sdw2.EXPECT().ReadConfiguration(
+			gomock.Any(),
+			gomock.Any(),
+		).Return(&idl.ReadConfigurationReply{Values: []*idl.ReadConfigurationReply_Value{
+			{Path: "/data/dbfast_mirror1/seg1/postgresql.conf", Name: "port", Value: "25434", Found: true},
+			{Path: "/data/dbfast_mirror1/seg1/internal.auto.conf", Name: "gp_dbid", Value: "4", Found: true},
+			{Path: "/data/dbfast_mirror1/seg1/postgresql.auto.conf", Name: "primary_conninfo", Found: false},
+		}}, nil).Times(1)
+
+		agentConns := []*idl.Connection{
+			{AgentClient: standby, Hostname: "standby"},
+			{AgentClient: sdw1, Hostname: "sdw1"},
+			{AgentClient: sdw2, Hostname: "sdw2"},
+		}
+
+		values, err := hub.VerifyConfFiles(agentConns, semver.MustParse("7.0.0"), target)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		if len(values) != 10 {
+			t.Fatalf("got %d values want 10: %v", len(values), values)
+		}
+
+		expected := hub.ConfValues{
+			{Hostname: "sdw1", Path: "/data/dbfast1/seg1/postgresql.conf", Name: "port", Expected: "25433", Actual: "50434", Found: true},
+			{Hostname: "sdw2", Path: "/data/dbfast_mirror1/seg1/postgresql.auto.conf", Name: "primary_conninfo", Expected: "25433"},
+		}
+
+		mismatches := values.Mismatches()
+		if !reflect.DeepEqual(mismatches, expected) {
+			t.Errorf("got mismatches %v want %v", mismatches, expected)
+		}
+	})
+
+	t.Run("caps the number of hosts read at once", func(t *testing.T) {
+		hub.SetConfRPCConcurrency(1)
+		defer hub.ResetConfRPCConcurrency()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var mutex sync.Mutex
+		inFlight, maxInFlight := 0, 0
+		read := func(context.Context, *idl.ReadConfigurationRequest, ...grpc.CallOption) (*idl.ReadConfigurationReply, error) {
+			mutex.Lock()
+			inFlight++
+			if inFlight > maxInFlight {
+				maxInFlight = inFlight
+			}
+			mutex.Unlock()
+
+			time.Sleep(10 * time.Millisecond)
+
+			mutex.Lock()
+			inFlight--
+			mutex.Unlock()
+
+			return &idl.ReadConfigurationReply{}, nil
+		}
+
+		var agentConns []*idl.Connection
+		for _, host := range []string{"standby", "sdw1", "sdw2"} {
+			client := mock_idl.NewMockAgentClient(ctrl)
+			client.EXPECT().ReadConfiguration(gomock.Any(), gomock.Any()).DoAndReturn(read).Times(1)
+			agentConns = append(agentConns, &idl.Connection{AgentClient: client, Hostname: host})
+		}
+
+		_, err := hub.VerifyConfFiles(agentConns, semver.MustParse("7.0.0"), target)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		if maxInFlight != 1 {
+			t.Errorf("got %d hosts read at once want 1", maxInFlight)
+		}
+	})
+
+	t.Run("times out reading a host", func(t *testing.T) {
+		hub.SetConfHostTimeout(10 * time.Millisecond)
+		defer hub.ResetConfHostTimeout()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw1.EXPECT().ReadConfiguration(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, _ *idl.ReadConfigurationRequest, _ ...grpc.CallOption) (*idl.ReadConfigurationReply, error) {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			})
+
+		agentConns := []*idl.Connection{{AgentClient: sdw1, Hostname: "sdw1"}}
+
+		_, err := hub.VerifyConfFiles(agentConns, semver.MustParse("7.0.0"), target)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("got error %#v want %#v", err, context.DeadlineExceeded)
+		}
+	})
+
+	t.Run("returns errors when failing to read configuration", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		expected := errors.New("permission denied")
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw1.EXPECT().ReadConfiguration(
+			gomock.Any(),
+			gomock.Any(),
+		).Return(nil, expected)
+
+		agentConns := []*idl.Connection{{AgentClient: sdw1, Hostname: "sdw1"}}
+
+		_, err := hub.VerifyConfFiles(agentConns, semver.MustParse("7.0.0"), target)
+		if !errors.Is(err, expected) {
+			t.Errorf("got error %#v want %#v", err, expected)
+		}
+	})
+
+	t.Run("verifies the files of each version and groups the mismatches by file", func(t *testing.T) {
+		gpperfmonConf := filepath.Join(coordinatorDir, "gpperfmon", "conf", "gpperfmon.conf")
+		testutils.MustCreateDir(t, filepath.Dir(gpperfmonConf))
+		defer testutils.MustRemoveAll(t, filepath.Join(coordinatorDir, "gpperfmon"))
+		testutils.MustWriteToFile(t, gpperfmonConf, "log_location = /data/qddir/seg.HqtFHX54y0o.-1/gpperfmon/logs\n")
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		standby := mock_idl.NewMockAgentClient(ctrl)
+		standby.EXPECT().ReadConfiguration(
+			gomock.Any(),
+			&idl.ReadConfigurationRequest{Files: []*idl.ReadConfigurationRequest_File{
+				{Path: "/data/standby/postgresql.conf", Names: []string{"port"}},
+				{Path: "/data/standby/internal.auto.conf", Names: []string{"gp_dbid"}},
+				{Path: "/data/standby/recovery.conf", Names: []string{"primary_conninfo"}},
+			}},
+		).Return(&idl.ReadConfigurationReply{Values: []*idl.ReadConfigurationReply_Value{
+			{Path: "/data/standby/postgresql.conf", Name: "port", Value: "16432", Found: true},
+			{Path: "/data/standby/internal.auto.conf", Name: "gp_dbid", Value: "2", Found: true},
+			{Path: "/data/standby/recovery.conf", Name: "primary_conninfo", Value: "user=gpadmin host=coordinator port=50432", Found: true},
+		}}, nil)
+
+		agentConns := []*idl.Connection{{AgentClient: standby, Hostname: "standby"}}
+
+		standbyTarget := hub.MustCreateCluster(t, greenplum.SegConfigs{
+			{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: coordinatorDir, Port: 15432, Role: greenplum.PrimaryRole},
+			{DbID: 2, ContentID: -1, Hostname: "standby", DataDir: "/data/standby", Port: 16432, Role: greenplum.MirrorRole},
+		})
+
+		values, err := hub.VerifyConfFiles(agentConns, semver.MustParse("6.20.0"), standbyTarget)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		if len(values) != 6 {
+			t.Fatalf("got %d values want 6: %v", len(values), values)
+		}
+
+		expected := []hub.ConfFileMismatches{
+			{Hostname: "coordinator", Path: gpperfmonConf, Values: hub.ConfValues{
+				{Hostname: "coordinator", Path: gpperfmonConf, Name: "log_location", Expected: filepath.Join(coordinatorDir, "gpperfmon", "logs"), Actual: "/data/qddir/seg.HqtFHX54y0o.-1/gpperfmon/logs", Found: true, Optional: true},
+			}},
+			{Hostname: "standby", Path: "/data/standby/recovery.conf", Values: hub.ConfValues{
+				{Hostname: "standby", Path: "/data/standby/recovery.conf", Name: "primary_conninfo", Expected: "15432", Actual: "50432", Found: true},
+			}},
+		}
+
+		mismatches := values.MismatchesByFile()
+		if !reflect.DeepEqual(mismatches, expected) {
+			t.Errorf("got mismatches %+v want %+v", mismatches, expected)
+		}
+	})
+}
+
+func TestConfValuesString(t *testing.T) {
+	values := hub.ConfValues{
+		{Hostname: "sdw1", Path: "/data/seg1/postgresql.conf", Name: "port", Expected: "25433", Actual: "50434", Found: true},
+		{Hostname: "sdw2", Path: "/data/mirror1/postgresql.auto.conf", Name: "primary_conninfo", Expected: "25433"},
+	}
+
+	expected := `Hostname  Path                                Name              Expected  Actual
+sdw1      /data/seg1/postgresql.conf          port              25433     50434
+sdw2      /data/mirror1/postgresql.auto.conf  primary_conninfo  25433     <not set>
+`
+	if values.String() != expected {
+		t.Errorf("got %q want %q", values.String(), expected)
+	}
+}
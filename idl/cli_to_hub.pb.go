@@ -134,6 +134,8 @@ const (
 	Substep_verify_gpupgrade_is_installed_across_all_hosts                Substep = 47
 	Substep_initialize_wait_for_cluster_to_be_ready                       Substep = 48
 	Substep_wait_for_cluster_to_be_ready_before_upgrade_master            Substep = 49
+	Substep_delete_conf_backups                                           Substep = 50
+	Substep_restore_conf_files                                            Substep = 51
 )
 
 // Enum value maps for Substep.
@@ -189,6 +191,8 @@ var (
 		47: "verify_gpupgrade_is_installed_across_all_hosts",
 		48: "initialize_wait_for_cluster_to_be_ready",
 		49: "wait_for_cluster_to_be_ready_before_upgrade_master",
+		50: "delete_conf_backups",
+		51: "restore_conf_files",
 	}
 	Substep_value = map[string]int32{
 		"unknown_substep":                0,
@@ -241,6 +245,8 @@ var (
 		"verify_gpupgrade_is_installed_across_all_hosts":                47,
 		"initialize_wait_for_cluster_to_be_ready":                       48,
 		"wait_for_cluster_to_be_ready_before_upgrade_master":            49,
+		"delete_conf_backups":                                           50,
+		"restore_conf_files":                                            51,
 	}
 )
 
@@ -329,6 +335,58 @@ func (Status) EnumDescriptor() ([]byte, []int) {
 	return file_cli_to_hub_proto_rawDescGZIP(), []int{2}
 }
 
+type SubstepEvent_Type int32
+
+const (
+	SubstepEvent_unknown_type   SubstepEvent_Type = 0 // http://androiddevblog.com/protocol-buffers-pitfall-adding-enum-values/
+	SubstepEvent_started        SubstepEvent_Type = 1
+	SubstepEvent_host_completed SubstepEvent_Type = 2
+	SubstepEvent_finished       SubstepEvent_Type = 3
+)
+
+// Enum value maps for SubstepEvent_Type.
+var (
+	SubstepEvent_Type_name = map[int32]string{
+		0: "unknown_type",
+		1: "started",
+		2: "host_completed",
+		3: "finished",
+	}
+	SubstepEvent_Type_value = map[string]int32{
+		"unknown_type":   0,
+		"started":        1,
+		"host_completed": 2,
+		"finished":       3,
+	}
+)
+
+func (x SubstepEvent_Type) Enum() *SubstepEvent_Type {
+	p := new(SubstepEvent_Type)
+	*p = x
+	return p
+}
+
+func (x SubstepEvent_Type) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SubstepEvent_Type) Descriptor() protoreflect.EnumDescriptor {
+	return file_cli_to_hub_proto_enumTypes[3].Descriptor()
+}
+
+func (SubstepEvent_Type) Type() protoreflect.EnumType {
+	return &file_cli_to_hub_proto_enumTypes[3]
+}
+
+func (x SubstepEvent_Type) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SubstepEvent_Type.Descriptor instead.
+func (SubstepEvent_Type) EnumDescriptor() ([]byte, []int) {
+	return file_cli_to_hub_proto_rawDescGZIP(), []int{10, 0}
+}
+
 type Chunk_Type int32
 
 const (
@@ -362,11 +420,11 @@ func (x Chunk_Type) String() string {
 }
 
 func (Chunk_Type) Descriptor() protoreflect.EnumDescriptor {
-	return file_cli_to_hub_proto_enumTypes[3].Descriptor()
+	return file_cli_to_hub_proto_enumTypes[4].Descriptor()
 }
 
 func (Chunk_Type) Type() protoreflect.EnumType {
-	return &file_cli_to_hub_proto_enumTypes[3]
+	return &file_cli_to_hub_proto_enumTypes[4]
 }
 
 func (x Chunk_Type) Number() protoreflect.EnumNumber {
@@ -375,7 +433,7 @@ func (x Chunk_Type) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use Chunk_Type.Descriptor instead.
 func (Chunk_Type) EnumDescriptor() ([]byte, []int) {
-	return file_cli_to_hub_proto_rawDescGZIP(), []int{12, 0}
+	return file_cli_to_hub_proto_rawDescGZIP(), []int{13, 0}
 }
 
 type InitializeRequest struct {
@@ -563,6 +621,22 @@ type FinalizeRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	ConfResumeToken string `protobuf:"bytes,1,opt,name=confResumeToken,proto3" json:"confResumeToken,omitempty"`
+	// verbose logs each conf edit at debug level.
+	Verbose bool `protobuf:"varint,2,opt,name=verbose,proto3" json:"verbose,omitempty"`
+	// confHosts when not empty limits the conf update to these hosts.
+	ConfHosts []string `protobuf:"bytes,3,rep,name=confHosts,proto3" json:"confHosts,omitempty"`
+	// confDryRun previews the conf update instead of making it, stopping
+	// finalize before the target cluster is started.
+	ConfDryRun bool `protobuf:"varint,4,opt,name=confDryRun,proto3" json:"confDryRun,omitempty"`
+	// reconcileLateMirrors includes the mirrors added after initialize, which
+	// are found on disk, in the conf update.
+	ReconcileLateMirrors bool `protobuf:"varint,5,opt,name=reconcileLateMirrors,proto3" json:"reconcileLateMirrors,omitempty"`
+	// confDumpRequests writes the requests the conf update would send to the
+	// agents to the state directory instead of sending them, stopping finalize
+	// before the target cluster is started.
+	ConfDumpRequests bool `protobuf:"varint,6,opt,name=confDumpRequests,proto3" json:"confDumpRequests,omitempty"`
 }
 
 func (x *FinalizeRequest) Reset() {
@@ -597,6 +671,48 @@ func (*FinalizeRequest) Descriptor() ([]byte, []int) {
 	return file_cli_to_hub_proto_rawDescGZIP(), []int{3}
 }
 
+func (x *FinalizeRequest) GetConfResumeToken() string {
+	if x != nil {
+		return x.ConfResumeToken
+	}
+	return ""
+}
+
+func (x *FinalizeRequest) GetVerbose() bool {
+	if x != nil {
+		return x.Verbose
+	}
+	return false
+}
+
+func (x *FinalizeRequest) GetConfHosts() []string {
+	if x != nil {
+		return x.ConfHosts
+	}
+	return nil
+}
+
+func (x *FinalizeRequest) GetConfDryRun() bool {
+	if x != nil {
+		return x.ConfDryRun
+	}
+	return false
+}
+
+func (x *FinalizeRequest) GetReconcileLateMirrors() bool {
+	if x != nil {
+		return x.ReconcileLateMirrors
+	}
+	return false
+}
+
+func (x *FinalizeRequest) GetConfDumpRequests() bool {
+	if x != nil {
+		return x.ConfDumpRequests
+	}
+	return false
+}
+
 type RevertRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -851,6 +967,104 @@ func (x *SubstepStatus) GetStatus() Status {
 	return Status_unknown_status
 }
 
+// SubstepEvent reports the progress of a substep that runs across hosts, such
+// as updating the target conf files. A started event is followed by a
+// host_completed event for each host of each phase, and then a finished event.
+type SubstepEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Substep        Substep           `protobuf:"varint,1,opt,name=substep,proto3,enum=idl.Substep" json:"substep,omitempty"`
+	Type           SubstepEvent_Type `protobuf:"varint,2,opt,name=type,proto3,enum=idl.SubstepEvent_Type" json:"type,omitempty"`
+	Phase          string            `protobuf:"bytes,3,opt,name=phase,proto3" json:"phase,omitempty"`                    // the phase of a host_completed event
+	Hostname       string            `protobuf:"bytes,4,opt,name=hostname,proto3" json:"hostname,omitempty"`              // the host of a host_completed event
+	Failed         bool              `protobuf:"varint,5,opt,name=failed,proto3" json:"failed,omitempty"`                 // whether the host, or for a finished event the substep, failed
+	CompletedHosts int32             `protobuf:"varint,6,opt,name=completedHosts,proto3" json:"completedHosts,omitempty"` // the hosts completed so far in the phase
+	TotalHosts     int32             `protobuf:"varint,7,opt,name=totalHosts,proto3" json:"totalHosts,omitempty"`         // the hosts in the phase, or for a started event the substep
+}
+
+func (x *SubstepEvent) Reset() {
+	*x = SubstepEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cli_to_hub_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SubstepEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubstepEvent) ProtoMessage() {}
+
+func (x *SubstepEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_cli_to_hub_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubstepEvent.ProtoReflect.Descriptor instead.
+func (*SubstepEvent) Descriptor() ([]byte, []int) {
+	return file_cli_to_hub_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *SubstepEvent) GetSubstep() Substep {
+	if x != nil {
+		return x.Substep
+	}
+	return Substep_unknown_substep
+}
+
+func (x *SubstepEvent) GetType() SubstepEvent_Type {
+	if x != nil {
+		return x.Type
+	}
+	return SubstepEvent_unknown_type
+}
+
+func (x *SubstepEvent) GetPhase() string {
+	if x != nil {
+		return x.Phase
+	}
+	return ""
+}
+
+func (x *SubstepEvent) GetHostname() string {
+	if x != nil {
+		return x.Hostname
+	}
+	return ""
+}
+
+func (x *SubstepEvent) GetFailed() bool {
+	if x != nil {
+		return x.Failed
+	}
+	return false
+}
+
+func (x *SubstepEvent) GetCompletedHosts() int32 {
+	if x != nil {
+		return x.CompletedHosts
+	}
+	return 0
+}
+
+func (x *SubstepEvent) GetTotalHosts() int32 {
+	if x != nil {
+		return x.TotalHosts
+	}
+	return 0
+}
+
 type PrepareInitClusterRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -860,7 +1074,7 @@ type PrepareInitClusterRequest struct {
 func (x *PrepareInitClusterRequest) Reset() {
 	*x = PrepareInitClusterRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_cli_to_hub_proto_msgTypes[10]
+		mi := &file_cli_to_hub_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -873,7 +1087,7 @@ func (x *PrepareInitClusterRequest) String() string {
 func (*PrepareInitClusterRequest) ProtoMessage() {}
 
 func (x *PrepareInitClusterRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_cli_to_hub_proto_msgTypes[10]
+	mi := &file_cli_to_hub_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -886,7 +1100,7 @@ func (x *PrepareInitClusterRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PrepareInitClusterRequest.ProtoReflect.Descriptor instead.
 func (*PrepareInitClusterRequest) Descriptor() ([]byte, []int) {
-	return file_cli_to_hub_proto_rawDescGZIP(), []int{10}
+	return file_cli_to_hub_proto_rawDescGZIP(), []int{11}
 }
 
 type PrepareInitClusterReply struct {
@@ -898,7 +1112,7 @@ type PrepareInitClusterReply struct {
 func (x *PrepareInitClusterReply) Reset() {
 	*x = PrepareInitClusterReply{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_cli_to_hub_proto_msgTypes[11]
+		mi := &file_cli_to_hub_proto_msgTypes[12]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -911,7 +1125,7 @@ func (x *PrepareInitClusterReply) String() string {
 func (*PrepareInitClusterReply) ProtoMessage() {}
 
 func (x *PrepareInitClusterReply) ProtoReflect() protoreflect.Message {
-	mi := &file_cli_to_hub_proto_msgTypes[11]
+	mi := &file_cli_to_hub_proto_msgTypes[12]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -924,7 +1138,7 @@ func (x *PrepareInitClusterReply) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PrepareInitClusterReply.ProtoReflect.Descriptor instead.
 func (*PrepareInitClusterReply) Descriptor() ([]byte, []int) {
-	return file_cli_to_hub_proto_rawDescGZIP(), []int{11}
+	return file_cli_to_hub_proto_rawDescGZIP(), []int{12}
 }
 
 type Chunk struct {
@@ -939,7 +1153,7 @@ type Chunk struct {
 func (x *Chunk) Reset() {
 	*x = Chunk{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_cli_to_hub_proto_msgTypes[12]
+		mi := &file_cli_to_hub_proto_msgTypes[13]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -952,7 +1166,7 @@ func (x *Chunk) String() string {
 func (*Chunk) ProtoMessage() {}
 
 func (x *Chunk) ProtoReflect() protoreflect.Message {
-	mi := &file_cli_to_hub_proto_msgTypes[12]
+	mi := &file_cli_to_hub_proto_msgTypes[13]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -965,7 +1179,7 @@ func (x *Chunk) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Chunk.ProtoReflect.Descriptor instead.
 func (*Chunk) Descriptor() ([]byte, []int) {
-	return file_cli_to_hub_proto_rawDescGZIP(), []int{12}
+	return file_cli_to_hub_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *Chunk) GetBuffer() []byte {
@@ -992,13 +1206,14 @@ type Message struct {
 	//	*Message_Chunk
 	//	*Message_Status
 	//	*Message_Response
+	//	*Message_Event
 	Contents isMessage_Contents `protobuf_oneof:"contents"`
 }
 
 func (x *Message) Reset() {
 	*x = Message{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_cli_to_hub_proto_msgTypes[13]
+		mi := &file_cli_to_hub_proto_msgTypes[14]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1011,7 +1226,7 @@ func (x *Message) String() string {
 func (*Message) ProtoMessage() {}
 
 func (x *Message) ProtoReflect() protoreflect.Message {
-	mi := &file_cli_to_hub_proto_msgTypes[13]
+	mi := &file_cli_to_hub_proto_msgTypes[14]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1024,7 +1239,7 @@ func (x *Message) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Message.ProtoReflect.Descriptor instead.
 func (*Message) Descriptor() ([]byte, []int) {
-	return file_cli_to_hub_proto_rawDescGZIP(), []int{13}
+	return file_cli_to_hub_proto_rawDescGZIP(), []int{14}
 }
 
 func (m *Message) GetContents() isMessage_Contents {
@@ -1055,6 +1270,13 @@ func (x *Message) GetResponse() *Response {
 	return nil
 }
 
+func (x *Message) GetEvent() *SubstepEvent {
+	if x, ok := x.GetContents().(*Message_Event); ok {
+		return x.Event
+	}
+	return nil
+}
+
 type isMessage_Contents interface {
 	isMessage_Contents()
 }
@@ -1071,12 +1293,18 @@ type Message_Response struct {
 	Response *Response `protobuf:"bytes,3,opt,name=response,proto3,oneof"`
 }
 
+type Message_Event struct {
+	Event *SubstepEvent `protobuf:"bytes,4,opt,name=event,proto3,oneof"`
+}
+
 func (*Message_Chunk) isMessage_Contents() {}
 
 func (*Message_Status) isMessage_Contents() {}
 
 func (*Message_Response) isMessage_Contents() {}
 
+func (*Message_Event) isMessage_Contents() {}
+
 type Response struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -1094,7 +1322,7 @@ type Response struct {
 func (x *Response) Reset() {
 	*x = Response{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_cli_to_hub_proto_msgTypes[14]
+		mi := &file_cli_to_hub_proto_msgTypes[15]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1107,7 +1335,7 @@ func (x *Response) String() string {
 func (*Response) ProtoMessage() {}
 
 func (x *Response) ProtoReflect() protoreflect.Message {
-	mi := &file_cli_to_hub_proto_msgTypes[14]
+	mi := &file_cli_to_hub_proto_msgTypes[15]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1120,7 +1348,7 @@ func (x *Response) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Response.ProtoReflect.Descriptor instead.
 func (*Response) Descriptor() ([]byte, []int) {
-	return file_cli_to_hub_proto_rawDescGZIP(), []int{14}
+	return file_cli_to_hub_proto_rawDescGZIP(), []int{15}
 }
 
 func (m *Response) GetContents() isResponse_Contents {
@@ -1197,7 +1425,7 @@ type InitializeResponse struct {
 func (x *InitializeResponse) Reset() {
 	*x = InitializeResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_cli_to_hub_proto_msgTypes[15]
+		mi := &file_cli_to_hub_proto_msgTypes[16]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1210,7 +1438,7 @@ func (x *InitializeResponse) String() string {
 func (*InitializeResponse) ProtoMessage() {}
 
 func (x *InitializeResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_cli_to_hub_proto_msgTypes[15]
+	mi := &file_cli_to_hub_proto_msgTypes[16]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1223,7 +1451,7 @@ func (x *InitializeResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use InitializeResponse.ProtoReflect.Descriptor instead.
 func (*InitializeResponse) Descriptor() ([]byte, []int) {
-	return file_cli_to_hub_proto_rawDescGZIP(), []int{15}
+	return file_cli_to_hub_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *InitializeResponse) GetHasAllMirrorsAndStandby() bool {
@@ -1244,7 +1472,7 @@ type ExecuteResponse struct {
 func (x *ExecuteResponse) Reset() {
 	*x = ExecuteResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_cli_to_hub_proto_msgTypes[16]
+		mi := &file_cli_to_hub_proto_msgTypes[17]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1257,7 +1485,7 @@ func (x *ExecuteResponse) String() string {
 func (*ExecuteResponse) ProtoMessage() {}
 
 func (x *ExecuteResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_cli_to_hub_proto_msgTypes[16]
+	mi := &file_cli_to_hub_proto_msgTypes[17]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1270,7 +1498,7 @@ func (x *ExecuteResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ExecuteResponse.ProtoReflect.Descriptor instead.
 func (*ExecuteResponse) Descriptor() ([]byte, []int) {
-	return file_cli_to_hub_proto_rawDescGZIP(), []int{16}
+	return file_cli_to_hub_proto_rawDescGZIP(), []int{17}
 }
 
 func (x *ExecuteResponse) GetIntermediate() []byte {
@@ -1294,7 +1522,7 @@ type FinalizeResponse struct {
 func (x *FinalizeResponse) Reset() {
 	*x = FinalizeResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_cli_to_hub_proto_msgTypes[17]
+		mi := &file_cli_to_hub_proto_msgTypes[18]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1307,7 +1535,7 @@ func (x *FinalizeResponse) String() string {
 func (*FinalizeResponse) ProtoMessage() {}
 
 func (x *FinalizeResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_cli_to_hub_proto_msgTypes[17]
+	mi := &file_cli_to_hub_proto_msgTypes[18]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1320,7 +1548,7 @@ func (x *FinalizeResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use FinalizeResponse.ProtoReflect.Descriptor instead.
 func (*FinalizeResponse) Descriptor() ([]byte, []int) {
-	return file_cli_to_hub_proto_rawDescGZIP(), []int{17}
+	return file_cli_to_hub_proto_rawDescGZIP(), []int{18}
 }
 
 func (x *FinalizeResponse) GetTarget() []byte {
@@ -1363,7 +1591,7 @@ type RevertResponse struct {
 func (x *RevertResponse) Reset() {
 	*x = RevertResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_cli_to_hub_proto_msgTypes[18]
+		mi := &file_cli_to_hub_proto_msgTypes[19]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1376,7 +1604,7 @@ func (x *RevertResponse) String() string {
 func (*RevertResponse) ProtoMessage() {}
 
 func (x *RevertResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_cli_to_hub_proto_msgTypes[18]
+	mi := &file_cli_to_hub_proto_msgTypes[19]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1389,7 +1617,7 @@ func (x *RevertResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RevertResponse.ProtoReflect.Descriptor instead.
 func (*RevertResponse) Descriptor() ([]byte, []int) {
-	return file_cli_to_hub_proto_rawDescGZIP(), []int{18}
+	return file_cli_to_hub_proto_rawDescGZIP(), []int{19}
 }
 
 func (x *RevertResponse) GetSource() []byte {
@@ -1417,7 +1645,7 @@ type GetConfigRequest struct {
 func (x *GetConfigRequest) Reset() {
 	*x = GetConfigRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_cli_to_hub_proto_msgTypes[19]
+		mi := &file_cli_to_hub_proto_msgTypes[20]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1430,7 +1658,7 @@ func (x *GetConfigRequest) String() string {
 func (*GetConfigRequest) ProtoMessage() {}
 
 func (x *GetConfigRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_cli_to_hub_proto_msgTypes[19]
+	mi := &file_cli_to_hub_proto_msgTypes[20]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1443,7 +1671,7 @@ func (x *GetConfigRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetConfigRequest.ProtoReflect.Descriptor instead.
 func (*GetConfigRequest) Descriptor() ([]byte, []int) {
-	return file_cli_to_hub_proto_rawDescGZIP(), []int{19}
+	return file_cli_to_hub_proto_rawDescGZIP(), []int{20}
 }
 
 func (x *GetConfigRequest) GetName() string {
@@ -1464,7 +1692,7 @@ type GetConfigReply struct {
 func (x *GetConfigReply) Reset() {
 	*x = GetConfigReply{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_cli_to_hub_proto_msgTypes[20]
+		mi := &file_cli_to_hub_proto_msgTypes[21]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1477,7 +1705,7 @@ func (x *GetConfigReply) String() string {
 func (*GetConfigReply) ProtoMessage() {}
 
 func (x *GetConfigReply) ProtoReflect() protoreflect.Message {
-	mi := &file_cli_to_hub_proto_msgTypes[20]
+	mi := &file_cli_to_hub_proto_msgTypes[21]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1490,7 +1718,7 @@ func (x *GetConfigReply) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetConfigReply.ProtoReflect.Descriptor instead.
 func (*GetConfigReply) Descriptor() ([]byte, []int) {
-	return file_cli_to_hub_proto_rawDescGZIP(), []int{20}
+	return file_cli_to_hub_proto_rawDescGZIP(), []int{21}
 }
 
 func (x *GetConfigReply) GetValue() string {
@@ -1500,6 +1728,101 @@ func (x *GetConfigReply) GetValue() string {
 	return ""
 }
 
+type CheckAgentCapabilitiesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *CheckAgentCapabilitiesRequest) Reset() {
+	*x = CheckAgentCapabilitiesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cli_to_hub_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CheckAgentCapabilitiesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckAgentCapabilitiesRequest) ProtoMessage() {}
+
+func (x *CheckAgentCapabilitiesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cli_to_hub_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckAgentCapabilitiesRequest.ProtoReflect.Descriptor instead.
+func (*CheckAgentCapabilitiesRequest) Descriptor() ([]byte, []int) {
+	return file_cli_to_hub_proto_rawDescGZIP(), []int{22}
+}
+
+type CheckAgentCapabilitiesReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// features are the conf update features the hub plans to use.
+	Features []string `protobuf:"bytes,1,rep,name=features,proto3" json:"features,omitempty"`
+	// hosts are those whose agent does not support every planned feature.
+	Hosts []*CheckAgentCapabilitiesReply_Host `protobuf:"bytes,2,rep,name=hosts,proto3" json:"hosts,omitempty"`
+}
+
+func (x *CheckAgentCapabilitiesReply) Reset() {
+	*x = CheckAgentCapabilitiesReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cli_to_hub_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CheckAgentCapabilitiesReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckAgentCapabilitiesReply) ProtoMessage() {}
+
+func (x *CheckAgentCapabilitiesReply) ProtoReflect() protoreflect.Message {
+	mi := &file_cli_to_hub_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckAgentCapabilitiesReply.ProtoReflect.Descriptor instead.
+func (*CheckAgentCapabilitiesReply) Descriptor() ([]byte, []int) {
+	return file_cli_to_hub_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *CheckAgentCapabilitiesReply) GetFeatures() []string {
+	if x != nil {
+		return x.Features
+	}
+	return nil
+}
+
+func (x *CheckAgentCapabilitiesReply) GetHosts() []*CheckAgentCapabilitiesReply_Host {
+	if x != nil {
+		return x.Hosts
+	}
+	return nil
+}
+
 // Used to set the gRPC status details that the CLI converts to a NextActions
 // error type to be displayed to the user.
 type NextActions struct {
@@ -1513,7 +1836,7 @@ type NextActions struct {
 func (x *NextActions) Reset() {
 	*x = NextActions{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_cli_to_hub_proto_msgTypes[21]
+		mi := &file_cli_to_hub_proto_msgTypes[24]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1526,7 +1849,7 @@ func (x *NextActions) String() string {
 func (*NextActions) ProtoMessage() {}
 
 func (x *NextActions) ProtoReflect() protoreflect.Message {
-	mi := &file_cli_to_hub_proto_msgTypes[21]
+	mi := &file_cli_to_hub_proto_msgTypes[24]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1539,7 +1862,7 @@ func (x *NextActions) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use NextActions.ProtoReflect.Descriptor instead.
 func (*NextActions) Descriptor() ([]byte, []int) {
-	return file_cli_to_hub_proto_rawDescGZIP(), []int{21}
+	return file_cli_to_hub_proto_rawDescGZIP(), []int{24}
 }
 
 func (x *NextActions) GetNextActions() string {
@@ -1549,6 +1872,61 @@ func (x *NextActions) GetNextActions() string {
 	return ""
 }
 
+type CheckAgentCapabilitiesReply_Host struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Hostname    string   `protobuf:"bytes,1,opt,name=hostname,proto3" json:"hostname,omitempty"`
+	Unsupported []string `protobuf:"bytes,2,rep,name=unsupported,proto3" json:"unsupported,omitempty"`
+}
+
+func (x *CheckAgentCapabilitiesReply_Host) Reset() {
+	*x = CheckAgentCapabilitiesReply_Host{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cli_to_hub_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CheckAgentCapabilitiesReply_Host) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckAgentCapabilitiesReply_Host) ProtoMessage() {}
+
+func (x *CheckAgentCapabilitiesReply_Host) ProtoReflect() protoreflect.Message {
+	mi := &file_cli_to_hub_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckAgentCapabilitiesReply_Host.ProtoReflect.Descriptor instead.
+func (*CheckAgentCapabilitiesReply_Host) Descriptor() ([]byte, []int) {
+	return file_cli_to_hub_proto_rawDescGZIP(), []int{23, 0}
+}
+
+func (x *CheckAgentCapabilitiesReply_Host) GetHostname() string {
+	if x != nil {
+		return x.Hostname
+	}
+	return ""
+}
+
+func (x *CheckAgentCapabilitiesReply_Host) GetUnsupported() []string {
+	if x != nil {
+		return x.Unsupported
+	}
+	return nil
+}
+
 var File_cli_to_hub_proto protoreflect.FileDescriptor
 
 var file_cli_to_hub_proto_rawDesc = []byte{
@@ -1580,242 +1958,302 @@ var file_cli_to_hub_proto_rawDesc = []byte{
 	0x70, 0x50, 0x67, 0x55, 0x70, 0x67, 0x72, 0x61, 0x64, 0x65, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x73,
 	0x12, 0x2a, 0x0a, 0x10, 0x70, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x42, 0x61, 0x63, 0x6b, 0x75, 0x70,
 	0x44, 0x69, 0x72, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x10, 0x70, 0x61, 0x72, 0x65,
-	0x6e, 0x74, 0x42, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x44, 0x69, 0x72, 0x73, 0x22, 0x11, 0x0a, 0x0f,
-	0x46, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22,
-	0x0f, 0x0a, 0x0d, 0x52, 0x65, 0x76, 0x65, 0x72, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x22, 0x16, 0x0a, 0x14, 0x52, 0x65, 0x73, 0x74, 0x61, 0x72, 0x74, 0x41, 0x67, 0x65, 0x6e, 0x74,
-	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x34, 0x0a, 0x12, 0x52, 0x65, 0x73, 0x74,
-	0x61, 0x72, 0x74, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x1e,
-	0x0a, 0x0a, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x48, 0x6f, 0x73, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03,
-	0x28, 0x09, 0x52, 0x0a, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x48, 0x6f, 0x73, 0x74, 0x73, 0x22, 0x15,
-	0x0a, 0x13, 0x53, 0x74, 0x6f, 0x70, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x13, 0x0a, 0x11, 0x53, 0x74, 0x6f, 0x70, 0x53, 0x65, 0x72,
-	0x76, 0x69, 0x63, 0x65, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x56, 0x0a, 0x0d, 0x53, 0x75,
-	0x62, 0x73, 0x74, 0x65, 0x70, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x20, 0x0a, 0x04, 0x73,
-	0x74, 0x65, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0c, 0x2e, 0x69, 0x64, 0x6c, 0x2e,
-	0x53, 0x75, 0x62, 0x73, 0x74, 0x65, 0x70, 0x52, 0x04, 0x73, 0x74, 0x65, 0x70, 0x12, 0x23, 0x0a,
-	0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0b, 0x2e,
-	0x69, 0x64, 0x6c, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74,
-	0x75, 0x73, 0x22, 0x1b, 0x0a, 0x19, 0x50, 0x72, 0x65, 0x70, 0x61, 0x72, 0x65, 0x49, 0x6e, 0x69,
-	0x74, 0x43, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22,
-	0x19, 0x0a, 0x17, 0x50, 0x72, 0x65, 0x70, 0x61, 0x72, 0x65, 0x49, 0x6e, 0x69, 0x74, 0x43, 0x6c,
-	0x75, 0x73, 0x74, 0x65, 0x72, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x71, 0x0a, 0x05, 0x43, 0x68,
-	0x75, 0x6e, 0x6b, 0x12, 0x16, 0x0a, 0x06, 0x62, 0x75, 0x66, 0x66, 0x65, 0x72, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x0c, 0x52, 0x06, 0x62, 0x75, 0x66, 0x66, 0x65, 0x72, 0x12, 0x23, 0x0a, 0x04, 0x74,
-	0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0f, 0x2e, 0x69, 0x64, 0x6c, 0x2e,
-	0x43, 0x68, 0x75, 0x6e, 0x6b, 0x2e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65,
-	0x22, 0x2b, 0x0a, 0x04, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0b, 0x0a, 0x07, 0x75, 0x6e, 0x6b, 0x6e,
-	0x6f, 0x77, 0x6e, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x73, 0x74, 0x64, 0x6f, 0x75, 0x74, 0x10,
-	0x01, 0x12, 0x0a, 0x0a, 0x06, 0x73, 0x74, 0x64, 0x65, 0x72, 0x72, 0x10, 0x02, 0x22, 0x94, 0x01,
-	0x0a, 0x07, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x22, 0x0a, 0x05, 0x63, 0x68, 0x75,
-	0x6e, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x43,
-	0x68, 0x75, 0x6e, 0x6b, 0x48, 0x00, 0x52, 0x05, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x12, 0x2c, 0x0a,
-	0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e,
-	0x69, 0x64, 0x6c, 0x2e, 0x53, 0x75, 0x62, 0x73, 0x74, 0x65, 0x70, 0x53, 0x74, 0x61, 0x74, 0x75,
-	0x73, 0x48, 0x00, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x2b, 0x0a, 0x08, 0x72,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e,
-	0x69, 0x64, 0x6c, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x48, 0x00, 0x52, 0x08,
-	0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x0a, 0x0a, 0x08, 0x63, 0x6f, 0x6e, 0x74,
-	0x65, 0x6e, 0x74, 0x73, 0x22, 0xa7, 0x02, 0x0a, 0x08, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x12, 0x49, 0x0a, 0x12, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e,
-	0x69, 0x64, 0x6c, 0x2e, 0x49, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x48, 0x00, 0x52, 0x12, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61,
-	0x6c, 0x69, 0x7a, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x40, 0x0a, 0x0f,
-	0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18,
-	0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x45, 0x78, 0x65, 0x63,
-	0x75, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x48, 0x00, 0x52, 0x0f, 0x65,
-	0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x43,
-	0x0a, 0x10, 0x66, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x46,
-	0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x48,
-	0x00, 0x52, 0x10, 0x66, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x12, 0x3d, 0x0a, 0x0e, 0x72, 0x65, 0x76, 0x65, 0x72, 0x74, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x69, 0x64,
-	0x6c, 0x2e, 0x52, 0x65, 0x76, 0x65, 0x72, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x48, 0x00, 0x52, 0x0e, 0x72, 0x65, 0x76, 0x65, 0x72, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x42, 0x0a, 0x0a, 0x08, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x73, 0x22, 0x4e,
-	0x0a, 0x12, 0x49, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x38, 0x0a, 0x17, 0x48, 0x61, 0x73, 0x41, 0x6c, 0x6c, 0x4d, 0x69,
-	0x72, 0x72, 0x6f, 0x72, 0x73, 0x41, 0x6e, 0x64, 0x53, 0x74, 0x61, 0x6e, 0x64, 0x62, 0x79, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x17, 0x48, 0x61, 0x73, 0x41, 0x6c, 0x6c, 0x4d, 0x69, 0x72,
-	0x72, 0x6f, 0x72, 0x73, 0x41, 0x6e, 0x64, 0x53, 0x74, 0x61, 0x6e, 0x64, 0x62, 0x79, 0x22, 0x35,
-	0x0a, 0x0f, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x12, 0x22, 0x0a, 0x0c, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x74,
-	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0c, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6d, 0x65,
-	0x64, 0x69, 0x61, 0x74, 0x65, 0x22, 0xd2, 0x01, 0x0a, 0x10, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x69,
-	0x7a, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x74, 0x61,
-	0x72, 0x67, 0x65, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x74, 0x61, 0x72, 0x67,
-	0x65, 0x74, 0x12, 0x30, 0x0a, 0x13, 0x4c, 0x6f, 0x67, 0x41, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65,
+	0x6e, 0x74, 0x42, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x44, 0x69, 0x72, 0x73, 0x22, 0xf3, 0x01, 0x0a,
+	0x0f, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x28, 0x0a, 0x0f, 0x63, 0x6f, 0x6e, 0x66, 0x52, 0x65, 0x73, 0x75, 0x6d, 0x65, 0x54, 0x6f,
+	0x6b, 0x65, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x63, 0x6f, 0x6e, 0x66, 0x52,
+	0x65, 0x73, 0x75, 0x6d, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65,
+	0x72, 0x62, 0x6f, 0x73, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x76, 0x65, 0x72,
+	0x62, 0x6f, 0x73, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x6f, 0x6e, 0x66, 0x48, 0x6f, 0x73, 0x74,
+	0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x63, 0x6f, 0x6e, 0x66, 0x48, 0x6f, 0x73,
+	0x74, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x6f, 0x6e, 0x66, 0x44, 0x72, 0x79, 0x52, 0x75, 0x6e,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x63, 0x6f, 0x6e, 0x66, 0x44, 0x72, 0x79, 0x52,
+	0x75, 0x6e, 0x12, 0x32, 0x0a, 0x14, 0x72, 0x65, 0x63, 0x6f, 0x6e, 0x63, 0x69, 0x6c, 0x65, 0x4c,
+	0x61, 0x74, 0x65, 0x4d, 0x69, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x14, 0x72, 0x65, 0x63, 0x6f, 0x6e, 0x63, 0x69, 0x6c, 0x65, 0x4c, 0x61, 0x74, 0x65, 0x4d,
+	0x69, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x12, 0x2a, 0x0a, 0x10, 0x63, 0x6f, 0x6e, 0x66, 0x44, 0x75,
+	0x6d, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x10, 0x63, 0x6f, 0x6e, 0x66, 0x44, 0x75, 0x6d, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x73, 0x22, 0x0f, 0x0a, 0x0d, 0x52, 0x65, 0x76, 0x65, 0x72, 0x74, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x22, 0x16, 0x0a, 0x14, 0x52, 0x65, 0x73, 0x74, 0x61, 0x72, 0x74, 0x41, 0x67,
+	0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x34, 0x0a, 0x12, 0x52,
+	0x65, 0x73, 0x74, 0x61, 0x72, 0x74, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x70, 0x6c,
+	0x79, 0x12, 0x1e, 0x0a, 0x0a, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x48, 0x6f, 0x73, 0x74, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x48, 0x6f, 0x73, 0x74,
+	0x73, 0x22, 0x15, 0x0a, 0x13, 0x53, 0x74, 0x6f, 0x70, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x13, 0x0a, 0x11, 0x53, 0x74, 0x6f, 0x70,
+	0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x56, 0x0a,
+	0x0d, 0x53, 0x75, 0x62, 0x73, 0x74, 0x65, 0x70, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x20,
+	0x0a, 0x04, 0x73, 0x74, 0x65, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0c, 0x2e, 0x69,
+	0x64, 0x6c, 0x2e, 0x53, 0x75, 0x62, 0x73, 0x74, 0x65, 0x70, 0x52, 0x04, 0x73, 0x74, 0x65, 0x70,
+	0x12, 0x23, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x0b, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0xbd, 0x02, 0x0a, 0x0c, 0x53, 0x75, 0x62, 0x73, 0x74, 0x65,
+	0x70, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x26, 0x0a, 0x07, 0x73, 0x75, 0x62, 0x73, 0x74, 0x65,
+	0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0c, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x53, 0x75,
+	0x62, 0x73, 0x74, 0x65, 0x70, 0x52, 0x07, 0x73, 0x75, 0x62, 0x73, 0x74, 0x65, 0x70, 0x12, 0x2a,
+	0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x16, 0x2e, 0x69,
+	0x64, 0x6c, 0x2e, 0x53, 0x75, 0x62, 0x73, 0x74, 0x65, 0x70, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x2e,
+	0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x68,
+	0x61, 0x73, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x70, 0x68, 0x61, 0x73, 0x65,
+	0x12, 0x1a, 0x0a, 0x08, 0x68, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x08, 0x68, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06,
+	0x66, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x66, 0x61,
+	0x69, 0x6c, 0x65, 0x64, 0x12, 0x26, 0x0a, 0x0e, 0x63, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65,
+	0x64, 0x48, 0x6f, 0x73, 0x74, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0e, 0x63, 0x6f,
+	0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x48, 0x6f, 0x73, 0x74, 0x73, 0x12, 0x1e, 0x0a, 0x0a,
+	0x74, 0x6f, 0x74, 0x61, 0x6c, 0x48, 0x6f, 0x73, 0x74, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x0a, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x48, 0x6f, 0x73, 0x74, 0x73, 0x22, 0x47, 0x0a, 0x04,
+	0x54, 0x79, 0x70, 0x65, 0x12, 0x10, 0x0a, 0x0c, 0x75, 0x6e, 0x6b, 0x6e, 0x6f, 0x77, 0x6e, 0x5f,
+	0x74, 0x79, 0x70, 0x65, 0x10, 0x00, 0x12, 0x0b, 0x0a, 0x07, 0x73, 0x74, 0x61, 0x72, 0x74, 0x65,
+	0x64, 0x10, 0x01, 0x12, 0x12, 0x0a, 0x0e, 0x68, 0x6f, 0x73, 0x74, 0x5f, 0x63, 0x6f, 0x6d, 0x70,
+	0x6c, 0x65, 0x74, 0x65, 0x64, 0x10, 0x02, 0x12, 0x0c, 0x0a, 0x08, 0x66, 0x69, 0x6e, 0x69, 0x73,
+	0x68, 0x65, 0x64, 0x10, 0x03, 0x22, 0x1b, 0x0a, 0x19, 0x50, 0x72, 0x65, 0x70, 0x61, 0x72, 0x65,
+	0x49, 0x6e, 0x69, 0x74, 0x43, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x22, 0x19, 0x0a, 0x17, 0x50, 0x72, 0x65, 0x70, 0x61, 0x72, 0x65, 0x49, 0x6e, 0x69,
+	0x74, 0x43, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x71, 0x0a,
+	0x05, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x12, 0x16, 0x0a, 0x06, 0x62, 0x75, 0x66, 0x66, 0x65, 0x72,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x62, 0x75, 0x66, 0x66, 0x65, 0x72, 0x12, 0x23,
+	0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0f, 0x2e, 0x69,
+	0x64, 0x6c, 0x2e, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x2e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74,
+	0x79, 0x70, 0x65, 0x22, 0x2b, 0x0a, 0x04, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0b, 0x0a, 0x07, 0x75,
+	0x6e, 0x6b, 0x6e, 0x6f, 0x77, 0x6e, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x73, 0x74, 0x64, 0x6f,
+	0x75, 0x74, 0x10, 0x01, 0x12, 0x0a, 0x0a, 0x06, 0x73, 0x74, 0x64, 0x65, 0x72, 0x72, 0x10, 0x02,
+	0x22, 0xbf, 0x01, 0x0a, 0x07, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x22, 0x0a, 0x05,
+	0x63, 0x68, 0x75, 0x6e, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x69, 0x64,
+	0x6c, 0x2e, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x48, 0x00, 0x52, 0x05, 0x63, 0x68, 0x75, 0x6e, 0x6b,
+	0x12, 0x2c, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x12, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x53, 0x75, 0x62, 0x73, 0x74, 0x65, 0x70, 0x53, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x48, 0x00, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x2b,
+	0x0a, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x0d, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x48,
+	0x00, 0x52, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x29, 0x0a, 0x05, 0x65,
+	0x76, 0x65, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x69, 0x64, 0x6c,
+	0x2e, 0x53, 0x75, 0x62, 0x73, 0x74, 0x65, 0x70, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52,
+	0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x42, 0x0a, 0x0a, 0x08, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e,
+	0x74, 0x73, 0x22, 0xa7, 0x02, 0x0a, 0x08, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x49, 0x0a, 0x12, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x69, 0x64,
+	0x6c, 0x2e, 0x49, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x48, 0x00, 0x52, 0x12, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x69,
+	0x7a, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x40, 0x0a, 0x0f, 0x65, 0x78,
+	0x65, 0x63, 0x75, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74,
+	0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x48, 0x00, 0x52, 0x0f, 0x65, 0x78, 0x65,
+	0x63, 0x75, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x43, 0x0a, 0x10,
+	0x66, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x46, 0x69, 0x6e,
+	0x61, 0x6c, 0x69, 0x7a, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x48, 0x00, 0x52,
+	0x10, 0x66, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x3d, 0x0a, 0x0e, 0x72, 0x65, 0x76, 0x65, 0x72, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x69, 0x64, 0x6c, 0x2e,
+	0x52, 0x65, 0x76, 0x65, 0x72, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x48, 0x00,
+	0x52, 0x0e, 0x72, 0x65, 0x76, 0x65, 0x72, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x42, 0x0a, 0x0a, 0x08, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x73, 0x22, 0x4e, 0x0a, 0x12,
+	0x49, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x38, 0x0a, 0x17, 0x48, 0x61, 0x73, 0x41, 0x6c, 0x6c, 0x4d, 0x69, 0x72, 0x72,
+	0x6f, 0x72, 0x73, 0x41, 0x6e, 0x64, 0x53, 0x74, 0x61, 0x6e, 0x64, 0x62, 0x79, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x17, 0x48, 0x61, 0x73, 0x41, 0x6c, 0x6c, 0x4d, 0x69, 0x72, 0x72, 0x6f,
+	0x72, 0x73, 0x41, 0x6e, 0x64, 0x53, 0x74, 0x61, 0x6e, 0x64, 0x62, 0x79, 0x22, 0x35, 0x0a, 0x0f,
+	0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x22, 0x0a, 0x0c, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x74, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0c, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6d, 0x65, 0x64, 0x69,
+	0x61, 0x74, 0x65, 0x22, 0xd2, 0x01, 0x0a, 0x10, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x74, 0x61, 0x72, 0x67,
+	0x65, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74,
+	0x12, 0x30, 0x0a, 0x13, 0x4c, 0x6f, 0x67, 0x41, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x44, 0x69,
+	0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x13, 0x4c,
+	0x6f, 0x67, 0x41, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f,
+	0x72, 0x79, 0x12, 0x56, 0x0a, 0x26, 0x41, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x64, 0x53, 0x6f,
+	0x75, 0x72, 0x63, 0x65, 0x43, 0x6f, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x44,
+	0x61, 0x74, 0x61, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x26, 0x41, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x64, 0x53, 0x6f, 0x75, 0x72,
+	0x63, 0x65, 0x43, 0x6f, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x44, 0x61, 0x74,
+	0x61, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x1c, 0x0a, 0x09, 0x55, 0x70,
+	0x67, 0x72, 0x61, 0x64, 0x65, 0x49, 0x44, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x55,
+	0x70, 0x67, 0x72, 0x61, 0x64, 0x65, 0x49, 0x44, 0x22, 0x5a, 0x0a, 0x0e, 0x52, 0x65, 0x76, 0x65,
+	0x72, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x6f,
+	0x75, 0x72, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x73, 0x6f, 0x75, 0x72,
+	0x63, 0x65, 0x12, 0x30, 0x0a, 0x13, 0x4c, 0x6f, 0x67, 0x41, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65,
 	0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
 	0x13, 0x4c, 0x6f, 0x67, 0x41, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x44, 0x69, 0x72, 0x65, 0x63,
-	0x74, 0x6f, 0x72, 0x79, 0x12, 0x56, 0x0a, 0x26, 0x41, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x64,
-	0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x43, 0x6f, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x61, 0x74, 0x6f,
-	0x72, 0x44, 0x61, 0x74, 0x61, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x18, 0x03,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x26, 0x41, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x64, 0x53, 0x6f,
-	0x75, 0x72, 0x63, 0x65, 0x43, 0x6f, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x44,
-	0x61, 0x74, 0x61, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x1c, 0x0a, 0x09,
-	0x55, 0x70, 0x67, 0x72, 0x61, 0x64, 0x65, 0x49, 0x44, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x09, 0x55, 0x70, 0x67, 0x72, 0x61, 0x64, 0x65, 0x49, 0x44, 0x22, 0x5a, 0x0a, 0x0e, 0x52, 0x65,
-	0x76, 0x65, 0x72, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x16, 0x0a, 0x06,
-	0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x73, 0x6f,
-	0x75, 0x72, 0x63, 0x65, 0x12, 0x30, 0x0a, 0x13, 0x4c, 0x6f, 0x67, 0x41, 0x72, 0x63, 0x68, 0x69,
-	0x76, 0x65, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x13, 0x4c, 0x6f, 0x67, 0x41, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x44, 0x69, 0x72,
-	0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x22, 0x26, 0x0a, 0x10, 0x47, 0x65, 0x74, 0x43, 0x6f, 0x6e,
-	0x66, 0x69, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61,
-	0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x26,
-	0x0a, 0x0e, 0x47, 0x65, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x70, 0x6c, 0x79,
-	0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0x2f, 0x0a, 0x0b, 0x4e, 0x65, 0x78, 0x74, 0x41, 0x63,
-	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x6e, 0x65, 0x78, 0x74, 0x41, 0x63, 0x74,
-	0x69, 0x6f, 0x6e, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x6e, 0x65, 0x78, 0x74,
-	0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2a, 0x5a, 0x0a, 0x04, 0x53, 0x74, 0x65, 0x70, 0x12,
-	0x10, 0x0a, 0x0c, 0x75, 0x6e, 0x6b, 0x6e, 0x6f, 0x77, 0x6e, 0x5f, 0x73, 0x74, 0x65, 0x70, 0x10,
-	0x00, 0x12, 0x0e, 0x0a, 0x0a, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x10,
-	0x01, 0x12, 0x0b, 0x0a, 0x07, 0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x10, 0x02, 0x12, 0x0c,
-	0x0a, 0x08, 0x66, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x10, 0x03, 0x12, 0x0a, 0x0a, 0x06,
-	0x72, 0x65, 0x76, 0x65, 0x72, 0x74, 0x10, 0x04, 0x12, 0x09, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74,
-	0x73, 0x10, 0x05, 0x2a, 0xb4, 0x0c, 0x0a, 0x07, 0x53, 0x75, 0x62, 0x73, 0x74, 0x65, 0x70, 0x12,
-	0x13, 0x0a, 0x0f, 0x75, 0x6e, 0x6b, 0x6e, 0x6f, 0x77, 0x6e, 0x5f, 0x73, 0x75, 0x62, 0x73, 0x74,
-	0x65, 0x70, 0x10, 0x00, 0x12, 0x20, 0x0a, 0x1c, 0x73, 0x61, 0x76, 0x69, 0x6e, 0x67, 0x5f, 0x73,
-	0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x5f, 0x63, 0x6f,
-	0x6e, 0x66, 0x69, 0x67, 0x10, 0x01, 0x12, 0x0d, 0x0a, 0x09, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f,
-	0x68, 0x75, 0x62, 0x10, 0x02, 0x12, 0x10, 0x0a, 0x0c, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x61,
-	0x67, 0x65, 0x6e, 0x74, 0x73, 0x10, 0x03, 0x12, 0x15, 0x0a, 0x11, 0x63, 0x68, 0x65, 0x63, 0x6b,
-	0x5f, 0x65, 0x6e, 0x76, 0x69, 0x72, 0x6f, 0x6e, 0x6d, 0x65, 0x6e, 0x74, 0x10, 0x04, 0x12, 0x14,
-	0x0a, 0x10, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x5f, 0x64, 0x69, 0x73, 0x6b, 0x5f, 0x73, 0x70, 0x61,
-	0x63, 0x65, 0x10, 0x05, 0x12, 0x1a, 0x0a, 0x16, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65,
-	0x5f, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x10, 0x06,
-	0x12, 0x17, 0x0a, 0x13, 0x69, 0x6e, 0x69, 0x74, 0x5f, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x5f,
-	0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x10, 0x07, 0x12, 0x1b, 0x0a, 0x17, 0x73, 0x68, 0x75,
-	0x74, 0x64, 0x6f, 0x77, 0x6e, 0x5f, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x5f, 0x63, 0x6c, 0x75,
-	0x73, 0x74, 0x65, 0x72, 0x10, 0x08, 0x12, 0x18, 0x0a, 0x14, 0x62, 0x61, 0x63, 0x6b, 0x75, 0x70,
-	0x5f, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x5f, 0x6d, 0x61, 0x73, 0x74, 0x65, 0x72, 0x10, 0x09,
-	0x12, 0x11, 0x0a, 0x0d, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x5f, 0x75, 0x70, 0x67, 0x72, 0x61, 0x64,
-	0x65, 0x10, 0x0a, 0x12, 0x1b, 0x0a, 0x17, 0x73, 0x68, 0x75, 0x74, 0x64, 0x6f, 0x77, 0x6e, 0x5f,
-	0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x10, 0x0b,
-	0x12, 0x12, 0x0a, 0x0e, 0x75, 0x70, 0x67, 0x72, 0x61, 0x64, 0x65, 0x5f, 0x6d, 0x61, 0x73, 0x74,
-	0x65, 0x72, 0x10, 0x0c, 0x12, 0x0f, 0x0a, 0x0b, 0x63, 0x6f, 0x70, 0x79, 0x5f, 0x6d, 0x61, 0x73,
-	0x74, 0x65, 0x72, 0x10, 0x0d, 0x12, 0x15, 0x0a, 0x11, 0x75, 0x70, 0x67, 0x72, 0x61, 0x64, 0x65,
-	0x5f, 0x70, 0x72, 0x69, 0x6d, 0x61, 0x72, 0x69, 0x65, 0x73, 0x10, 0x0e, 0x12, 0x18, 0x0a, 0x14,
-	0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x5f, 0x63, 0x6c, 0x75,
-	0x73, 0x74, 0x65, 0x72, 0x10, 0x0f, 0x12, 0x19, 0x0a, 0x15, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65,
-	0x5f, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x5f, 0x63, 0x61, 0x74, 0x61, 0x6c, 0x6f, 0x67, 0x10,
-	0x10, 0x12, 0x1b, 0x0a, 0x17, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x5f, 0x64, 0x61, 0x74, 0x61,
-	0x5f, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x69, 0x65, 0x73, 0x10, 0x11, 0x12, 0x1c,
-	0x0a, 0x18, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x5f, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x5f,
-	0x63, 0x6f, 0x6e, 0x66, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x10, 0x12, 0x12, 0x13, 0x0a, 0x0f,
-	0x75, 0x70, 0x67, 0x72, 0x61, 0x64, 0x65, 0x5f, 0x73, 0x74, 0x61, 0x6e, 0x64, 0x62, 0x79, 0x10,
-	0x13, 0x12, 0x13, 0x0a, 0x0f, 0x75, 0x70, 0x67, 0x72, 0x61, 0x64, 0x65, 0x5f, 0x6d, 0x69, 0x72,
-	0x72, 0x6f, 0x72, 0x73, 0x10, 0x14, 0x12, 0x16, 0x0a, 0x12, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65,
-	0x5f, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x73, 0x10, 0x15, 0x12, 0x22,
-	0x0a, 0x1e, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x5f, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x5f,
-	0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x5f, 0x64, 0x61, 0x74, 0x61, 0x64, 0x69, 0x72, 0x73,
-	0x10, 0x16, 0x12, 0x1c, 0x0a, 0x18, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x5f, 0x73, 0x65, 0x67,
-	0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x65, 0x64, 0x69, 0x72, 0x73, 0x10, 0x17,
-	0x12, 0x17, 0x0a, 0x13, 0x73, 0x74, 0x6f, 0x70, 0x5f, 0x68, 0x75, 0x62, 0x5f, 0x61, 0x6e, 0x64,
-	0x5f, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x73, 0x10, 0x18, 0x12, 0x1a, 0x0a, 0x16, 0x64, 0x65, 0x6c,
-	0x65, 0x74, 0x65, 0x5f, 0x6d, 0x61, 0x73, 0x74, 0x65, 0x72, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x65,
-	0x64, 0x69, 0x72, 0x10, 0x19, 0x12, 0x1b, 0x0a, 0x17, 0x61, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65,
-	0x5f, 0x6c, 0x6f, 0x67, 0x5f, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x69, 0x65, 0x73,
-	0x10, 0x1a, 0x12, 0x1a, 0x0a, 0x16, 0x72, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x5f, 0x73, 0x6f,
-	0x75, 0x72, 0x63, 0x65, 0x5f, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x10, 0x1b, 0x12, 0x18,
-	0x0a, 0x14, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x63,
-	0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x10, 0x1c, 0x12, 0x15, 0x0a, 0x11, 0x72, 0x65, 0x73, 0x74,
-	0x6f, 0x72, 0x65, 0x5f, 0x70, 0x67, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x10, 0x1d, 0x12,
-	0x1d, 0x0a, 0x19, 0x72, 0x65, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x73, 0x65, 0x67, 0x5f, 0x73, 0x6f,
-	0x75, 0x72, 0x63, 0x65, 0x5f, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x10, 0x1e, 0x12, 0x0f,
-	0x0a, 0x0b, 0x73, 0x74, 0x65, 0x70, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x10, 0x1f, 0x12,
-	0x41, 0x0a, 0x3d, 0x77, 0x61, 0x69, 0x74, 0x5f, 0x66, 0x6f, 0x72, 0x5f, 0x63, 0x6c, 0x75, 0x73,
-	0x74, 0x65, 0x72, 0x5f, 0x74, 0x6f, 0x5f, 0x62, 0x65, 0x5f, 0x72, 0x65, 0x61, 0x64, 0x79, 0x5f,
-	0x61, 0x66, 0x74, 0x65, 0x72, 0x5f, 0x61, 0x64, 0x64, 0x69, 0x6e, 0x67, 0x5f, 0x6d, 0x69, 0x72,
-	0x72, 0x6f, 0x72, 0x73, 0x5f, 0x61, 0x6e, 0x64, 0x5f, 0x73, 0x74, 0x61, 0x6e, 0x64, 0x62, 0x79,
-	0x10, 0x20, 0x12, 0x37, 0x0a, 0x33, 0x77, 0x61, 0x69, 0x74, 0x5f, 0x66, 0x6f, 0x72, 0x5f, 0x63,
-	0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x5f, 0x74, 0x6f, 0x5f, 0x62, 0x65, 0x5f, 0x72, 0x65, 0x61,
-	0x64, 0x79, 0x5f, 0x61, 0x66, 0x74, 0x65, 0x72, 0x5f, 0x75, 0x70, 0x64, 0x61, 0x74, 0x69, 0x6e,
-	0x67, 0x5f, 0x63, 0x61, 0x74, 0x61, 0x6c, 0x6f, 0x67, 0x10, 0x21, 0x12, 0x32, 0x0a, 0x2e, 0x73,
-	0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x5f, 0x64, 0x79, 0x6e, 0x61, 0x6d, 0x69, 0x63, 0x5f, 0x6c,
-	0x69, 0x62, 0x72, 0x61, 0x72, 0x79, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x5f, 0x6f, 0x6e, 0x5f, 0x74,
-	0x61, 0x72, 0x67, 0x65, 0x74, 0x5f, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x10, 0x22, 0x12,
-	0x2e, 0x0a, 0x2a, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x5f, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x5f,
-	0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x5f, 0x6f, 0x6e, 0x5f, 0x73,
-	0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x10, 0x23, 0x12,
-	0x2e, 0x0a, 0x2a, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x5f, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x5f,
-	0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x5f, 0x6f, 0x6e, 0x5f, 0x74,
-	0x61, 0x72, 0x67, 0x65, 0x74, 0x5f, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x10, 0x24, 0x12,
-	0x23, 0x0a, 0x1f, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x5f, 0x64, 0x61, 0x74, 0x61,
-	0x5f, 0x6d, 0x69, 0x67, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x73, 0x63, 0x72, 0x69, 0x70,
-	0x74, 0x73, 0x10, 0x25, 0x12, 0x28, 0x0a, 0x24, 0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x5f,
-	0x73, 0x74, 0x61, 0x74, 0x73, 0x5f, 0x64, 0x61, 0x74, 0x61, 0x5f, 0x6d, 0x69, 0x67, 0x72, 0x61,
-	0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x73, 0x10, 0x26, 0x12, 0x2d,
-	0x0a, 0x29, 0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x5f, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61,
-	0x6c, 0x69, 0x7a, 0x65, 0x5f, 0x64, 0x61, 0x74, 0x61, 0x5f, 0x6d, 0x69, 0x67, 0x72, 0x61, 0x74,
-	0x69, 0x6f, 0x6e, 0x5f, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x73, 0x10, 0x27, 0x12, 0x2b, 0x0a,
-	0x27, 0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x5f, 0x66, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a,
-	0x65, 0x5f, 0x64, 0x61, 0x74, 0x61, 0x5f, 0x6d, 0x69, 0x67, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
-	0x5f, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x73, 0x10, 0x28, 0x12, 0x29, 0x0a, 0x25, 0x65, 0x78,
-	0x65, 0x63, 0x75, 0x74, 0x65, 0x5f, 0x72, 0x65, 0x76, 0x65, 0x72, 0x74, 0x5f, 0x64, 0x61, 0x74,
-	0x61, 0x5f, 0x6d, 0x69, 0x67, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x73, 0x63, 0x72, 0x69,
-	0x70, 0x74, 0x73, 0x10, 0x29, 0x12, 0x15, 0x0a, 0x11, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x5f,
-	0x62, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x64, 0x69, 0x72, 0x73, 0x10, 0x2a, 0x12, 0x14, 0x0a, 0x10,
-	0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x5f, 0x62, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x64, 0x69, 0x72,
-	0x10, 0x2b, 0x12, 0x1a, 0x0a, 0x16, 0x61, 0x6e, 0x61, 0x6c, 0x79, 0x7a, 0x65, 0x5f, 0x74, 0x61,
-	0x72, 0x67, 0x65, 0x74, 0x5f, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x10, 0x2c, 0x12, 0x27,
-	0x0a, 0x23, 0x65, 0x6e, 0x73, 0x75, 0x72, 0x65, 0x5f, 0x67, 0x70, 0x75, 0x70, 0x67, 0x72, 0x61,
-	0x64, 0x65, 0x5f, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x73, 0x5f, 0x61, 0x72, 0x65, 0x5f, 0x72, 0x75,
-	0x6e, 0x6e, 0x69, 0x6e, 0x67, 0x10, 0x2d, 0x12, 0x18, 0x0a, 0x14, 0x76, 0x65, 0x72, 0x69, 0x66,
-	0x79, 0x5f, 0x67, 0x70, 0x64, 0x62, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x10,
-	0x2e, 0x12, 0x32, 0x0a, 0x2e, 0x76, 0x65, 0x72, 0x69, 0x66, 0x79, 0x5f, 0x67, 0x70, 0x75, 0x70,
-	0x67, 0x72, 0x61, 0x64, 0x65, 0x5f, 0x69, 0x73, 0x5f, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6c, 0x6c,
-	0x65, 0x64, 0x5f, 0x61, 0x63, 0x72, 0x6f, 0x73, 0x73, 0x5f, 0x61, 0x6c, 0x6c, 0x5f, 0x68, 0x6f,
-	0x73, 0x74, 0x73, 0x10, 0x2f, 0x12, 0x2b, 0x0a, 0x27, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c,
-	0x69, 0x7a, 0x65, 0x5f, 0x77, 0x61, 0x69, 0x74, 0x5f, 0x66, 0x6f, 0x72, 0x5f, 0x63, 0x6c, 0x75,
+	0x74, 0x6f, 0x72, 0x79, 0x22, 0x26, 0x0a, 0x10, 0x47, 0x65, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x69,
+	0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x26, 0x0a, 0x0e,
+	0x47, 0x65, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x14,
+	0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x22, 0x1f, 0x0a, 0x1d, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x41, 0x67, 0x65,
+	0x6e, 0x74, 0x43, 0x61, 0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0xbc, 0x01, 0x0a, 0x1b, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x41,
+	0x67, 0x65, 0x6e, 0x74, 0x43, 0x61, 0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73,
+	0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x1a, 0x0a, 0x08, 0x66, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x66, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65,
+	0x73, 0x12, 0x3b, 0x0a, 0x05, 0x68, 0x6f, 0x73, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x25, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x41, 0x67, 0x65, 0x6e,
+	0x74, 0x43, 0x61, 0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x52, 0x65, 0x70,
+	0x6c, 0x79, 0x2e, 0x48, 0x6f, 0x73, 0x74, 0x52, 0x05, 0x68, 0x6f, 0x73, 0x74, 0x73, 0x1a, 0x44,
+	0x0a, 0x04, 0x48, 0x6f, 0x73, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x68, 0x6f, 0x73, 0x74, 0x6e, 0x61,
+	0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x68, 0x6f, 0x73, 0x74, 0x6e, 0x61,
+	0x6d, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x75, 0x6e, 0x73, 0x75, 0x70, 0x70, 0x6f, 0x72, 0x74, 0x65,
+	0x64, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0b, 0x75, 0x6e, 0x73, 0x75, 0x70, 0x70, 0x6f,
+	0x72, 0x74, 0x65, 0x64, 0x22, 0x2f, 0x0a, 0x0b, 0x4e, 0x65, 0x78, 0x74, 0x41, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x6e, 0x65, 0x78, 0x74, 0x41, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x6e, 0x65, 0x78, 0x74, 0x41, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2a, 0x5a, 0x0a, 0x04, 0x53, 0x74, 0x65, 0x70, 0x12, 0x10, 0x0a,
+	0x0c, 0x75, 0x6e, 0x6b, 0x6e, 0x6f, 0x77, 0x6e, 0x5f, 0x73, 0x74, 0x65, 0x70, 0x10, 0x00, 0x12,
+	0x0e, 0x0a, 0x0a, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x10, 0x01, 0x12,
+	0x0b, 0x0a, 0x07, 0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x10, 0x02, 0x12, 0x0c, 0x0a, 0x08,
+	0x66, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x10, 0x03, 0x12, 0x0a, 0x0a, 0x06, 0x72, 0x65,
+	0x76, 0x65, 0x72, 0x74, 0x10, 0x04, 0x12, 0x09, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x73, 0x10,
+	0x05, 0x2a, 0xe5, 0x0c, 0x0a, 0x07, 0x53, 0x75, 0x62, 0x73, 0x74, 0x65, 0x70, 0x12, 0x13, 0x0a,
+	0x0f, 0x75, 0x6e, 0x6b, 0x6e, 0x6f, 0x77, 0x6e, 0x5f, 0x73, 0x75, 0x62, 0x73, 0x74, 0x65, 0x70,
+	0x10, 0x00, 0x12, 0x20, 0x0a, 0x1c, 0x73, 0x61, 0x76, 0x69, 0x6e, 0x67, 0x5f, 0x73, 0x6f, 0x75,
+	0x72, 0x63, 0x65, 0x5f, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x5f, 0x63, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x10, 0x01, 0x12, 0x0d, 0x0a, 0x09, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x68, 0x75,
+	0x62, 0x10, 0x02, 0x12, 0x10, 0x0a, 0x0c, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x61, 0x67, 0x65,
+	0x6e, 0x74, 0x73, 0x10, 0x03, 0x12, 0x15, 0x0a, 0x11, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x5f, 0x65,
+	0x6e, 0x76, 0x69, 0x72, 0x6f, 0x6e, 0x6d, 0x65, 0x6e, 0x74, 0x10, 0x04, 0x12, 0x14, 0x0a, 0x10,
+	0x63, 0x68, 0x65, 0x63, 0x6b, 0x5f, 0x64, 0x69, 0x73, 0x6b, 0x5f, 0x73, 0x70, 0x61, 0x63, 0x65,
+	0x10, 0x05, 0x12, 0x1a, 0x0a, 0x16, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x5f, 0x74,
+	0x61, 0x72, 0x67, 0x65, 0x74, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x10, 0x06, 0x12, 0x17,
+	0x0a, 0x13, 0x69, 0x6e, 0x69, 0x74, 0x5f, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x5f, 0x63, 0x6c,
+	0x75, 0x73, 0x74, 0x65, 0x72, 0x10, 0x07, 0x12, 0x1b, 0x0a, 0x17, 0x73, 0x68, 0x75, 0x74, 0x64,
+	0x6f, 0x77, 0x6e, 0x5f, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x5f, 0x63, 0x6c, 0x75, 0x73, 0x74,
+	0x65, 0x72, 0x10, 0x08, 0x12, 0x18, 0x0a, 0x14, 0x62, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x5f, 0x74,
+	0x61, 0x72, 0x67, 0x65, 0x74, 0x5f, 0x6d, 0x61, 0x73, 0x74, 0x65, 0x72, 0x10, 0x09, 0x12, 0x11,
+	0x0a, 0x0d, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x5f, 0x75, 0x70, 0x67, 0x72, 0x61, 0x64, 0x65, 0x10,
+	0x0a, 0x12, 0x1b, 0x0a, 0x17, 0x73, 0x68, 0x75, 0x74, 0x64, 0x6f, 0x77, 0x6e, 0x5f, 0x73, 0x6f,
+	0x75, 0x72, 0x63, 0x65, 0x5f, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x10, 0x0b, 0x12, 0x12,
+	0x0a, 0x0e, 0x75, 0x70, 0x67, 0x72, 0x61, 0x64, 0x65, 0x5f, 0x6d, 0x61, 0x73, 0x74, 0x65, 0x72,
+	0x10, 0x0c, 0x12, 0x0f, 0x0a, 0x0b, 0x63, 0x6f, 0x70, 0x79, 0x5f, 0x6d, 0x61, 0x73, 0x74, 0x65,
+	0x72, 0x10, 0x0d, 0x12, 0x15, 0x0a, 0x11, 0x75, 0x70, 0x67, 0x72, 0x61, 0x64, 0x65, 0x5f, 0x70,
+	0x72, 0x69, 0x6d, 0x61, 0x72, 0x69, 0x65, 0x73, 0x10, 0x0e, 0x12, 0x18, 0x0a, 0x14, 0x73, 0x74,
+	0x61, 0x72, 0x74, 0x5f, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x5f, 0x63, 0x6c, 0x75, 0x73, 0x74,
+	0x65, 0x72, 0x10, 0x0f, 0x12, 0x19, 0x0a, 0x15, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x5f, 0x74,
+	0x61, 0x72, 0x67, 0x65, 0x74, 0x5f, 0x63, 0x61, 0x74, 0x61, 0x6c, 0x6f, 0x67, 0x10, 0x10, 0x12,
+	0x1b, 0x0a, 0x17, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x5f, 0x64, 0x61, 0x74, 0x61, 0x5f, 0x64,
+	0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x69, 0x65, 0x73, 0x10, 0x11, 0x12, 0x1c, 0x0a, 0x18,
+	0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x5f, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x5f, 0x63, 0x6f,
+	0x6e, 0x66, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x10, 0x12, 0x12, 0x13, 0x0a, 0x0f, 0x75, 0x70,
+	0x67, 0x72, 0x61, 0x64, 0x65, 0x5f, 0x73, 0x74, 0x61, 0x6e, 0x64, 0x62, 0x79, 0x10, 0x13, 0x12,
+	0x13, 0x0a, 0x0f, 0x75, 0x70, 0x67, 0x72, 0x61, 0x64, 0x65, 0x5f, 0x6d, 0x69, 0x72, 0x72, 0x6f,
+	0x72, 0x73, 0x10, 0x14, 0x12, 0x16, 0x0a, 0x12, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x5f, 0x74,
+	0x61, 0x62, 0x6c, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x73, 0x10, 0x15, 0x12, 0x22, 0x0a, 0x1e,
+	0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x5f, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x5f, 0x63, 0x6c,
+	0x75, 0x73, 0x74, 0x65, 0x72, 0x5f, 0x64, 0x61, 0x74, 0x61, 0x64, 0x69, 0x72, 0x73, 0x10, 0x16,
+	0x12, 0x1c, 0x0a, 0x18, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x5f, 0x73, 0x65, 0x67, 0x6d, 0x65,
+	0x6e, 0x74, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x65, 0x64, 0x69, 0x72, 0x73, 0x10, 0x17, 0x12, 0x17,
+	0x0a, 0x13, 0x73, 0x74, 0x6f, 0x70, 0x5f, 0x68, 0x75, 0x62, 0x5f, 0x61, 0x6e, 0x64, 0x5f, 0x61,
+	0x67, 0x65, 0x6e, 0x74, 0x73, 0x10, 0x18, 0x12, 0x1a, 0x0a, 0x16, 0x64, 0x65, 0x6c, 0x65, 0x74,
+	0x65, 0x5f, 0x6d, 0x61, 0x73, 0x74, 0x65, 0x72, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x65, 0x64, 0x69,
+	0x72, 0x10, 0x19, 0x12, 0x1b, 0x0a, 0x17, 0x61, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x5f, 0x6c,
+	0x6f, 0x67, 0x5f, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x69, 0x65, 0x73, 0x10, 0x1a,
+	0x12, 0x1a, 0x0a, 0x16, 0x72, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x5f, 0x73, 0x6f, 0x75, 0x72,
+	0x63, 0x65, 0x5f, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x10, 0x1b, 0x12, 0x18, 0x0a, 0x14,
+	0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x63, 0x6c, 0x75,
+	0x73, 0x74, 0x65, 0x72, 0x10, 0x1c, 0x12, 0x15, 0x0a, 0x11, 0x72, 0x65, 0x73, 0x74, 0x6f, 0x72,
+	0x65, 0x5f, 0x70, 0x67, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x10, 0x1d, 0x12, 0x1d, 0x0a,
+	0x19, 0x72, 0x65, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x73, 0x65, 0x67, 0x5f, 0x73, 0x6f, 0x75, 0x72,
+	0x63, 0x65, 0x5f, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x10, 0x1e, 0x12, 0x0f, 0x0a, 0x0b,
+	0x73, 0x74, 0x65, 0x70, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x10, 0x1f, 0x12, 0x41, 0x0a,
+	0x3d, 0x77, 0x61, 0x69, 0x74, 0x5f, 0x66, 0x6f, 0x72, 0x5f, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65,
+	0x72, 0x5f, 0x74, 0x6f, 0x5f, 0x62, 0x65, 0x5f, 0x72, 0x65, 0x61, 0x64, 0x79, 0x5f, 0x61, 0x66,
+	0x74, 0x65, 0x72, 0x5f, 0x61, 0x64, 0x64, 0x69, 0x6e, 0x67, 0x5f, 0x6d, 0x69, 0x72, 0x72, 0x6f,
+	0x72, 0x73, 0x5f, 0x61, 0x6e, 0x64, 0x5f, 0x73, 0x74, 0x61, 0x6e, 0x64, 0x62, 0x79, 0x10, 0x20,
+	0x12, 0x37, 0x0a, 0x33, 0x77, 0x61, 0x69, 0x74, 0x5f, 0x66, 0x6f, 0x72, 0x5f, 0x63, 0x6c, 0x75,
+	0x73, 0x74, 0x65, 0x72, 0x5f, 0x74, 0x6f, 0x5f, 0x62, 0x65, 0x5f, 0x72, 0x65, 0x61, 0x64, 0x79,
+	0x5f, 0x61, 0x66, 0x74, 0x65, 0x72, 0x5f, 0x75, 0x70, 0x64, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x5f,
+	0x63, 0x61, 0x74, 0x61, 0x6c, 0x6f, 0x67, 0x10, 0x21, 0x12, 0x32, 0x0a, 0x2e, 0x73, 0x65, 0x74,
+	0x74, 0x69, 0x6e, 0x67, 0x5f, 0x64, 0x79, 0x6e, 0x61, 0x6d, 0x69, 0x63, 0x5f, 0x6c, 0x69, 0x62,
+	0x72, 0x61, 0x72, 0x79, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x5f, 0x6f, 0x6e, 0x5f, 0x74, 0x61, 0x72,
+	0x67, 0x65, 0x74, 0x5f, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x10, 0x22, 0x12, 0x2e, 0x0a,
+	0x2a, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x5f, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x5f, 0x63, 0x6f,
+	0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x5f, 0x6f, 0x6e, 0x5f, 0x73, 0x6f, 0x75,
+	0x72, 0x63, 0x65, 0x5f, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x10, 0x23, 0x12, 0x2e, 0x0a,
+	0x2a, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x5f, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x5f, 0x63, 0x6f,
+	0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x5f, 0x6f, 0x6e, 0x5f, 0x74, 0x61, 0x72,
+	0x67, 0x65, 0x74, 0x5f, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x10, 0x24, 0x12, 0x23, 0x0a,
+	0x1f, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x5f, 0x64, 0x61, 0x74, 0x61, 0x5f, 0x6d,
+	0x69, 0x67, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x73,
+	0x10, 0x25, 0x12, 0x28, 0x0a, 0x24, 0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x5f, 0x73, 0x74,
+	0x61, 0x74, 0x73, 0x5f, 0x64, 0x61, 0x74, 0x61, 0x5f, 0x6d, 0x69, 0x67, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x5f, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x73, 0x10, 0x26, 0x12, 0x2d, 0x0a, 0x29,
+	0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x5f, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x69,
+	0x7a, 0x65, 0x5f, 0x64, 0x61, 0x74, 0x61, 0x5f, 0x6d, 0x69, 0x67, 0x72, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x5f, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x73, 0x10, 0x27, 0x12, 0x2b, 0x0a, 0x27, 0x65,
+	0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x5f, 0x66, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x5f,
+	0x64, 0x61, 0x74, 0x61, 0x5f, 0x6d, 0x69, 0x67, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x73,
+	0x63, 0x72, 0x69, 0x70, 0x74, 0x73, 0x10, 0x28, 0x12, 0x29, 0x0a, 0x25, 0x65, 0x78, 0x65, 0x63,
+	0x75, 0x74, 0x65, 0x5f, 0x72, 0x65, 0x76, 0x65, 0x72, 0x74, 0x5f, 0x64, 0x61, 0x74, 0x61, 0x5f,
+	0x6d, 0x69, 0x67, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74,
+	0x73, 0x10, 0x29, 0x12, 0x15, 0x0a, 0x11, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x5f, 0x62, 0x61,
+	0x63, 0x6b, 0x75, 0x70, 0x64, 0x69, 0x72, 0x73, 0x10, 0x2a, 0x12, 0x14, 0x0a, 0x10, 0x64, 0x65,
+	0x6c, 0x65, 0x74, 0x65, 0x5f, 0x62, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x64, 0x69, 0x72, 0x10, 0x2b,
+	0x12, 0x1a, 0x0a, 0x16, 0x61, 0x6e, 0x61, 0x6c, 0x79, 0x7a, 0x65, 0x5f, 0x74, 0x61, 0x72, 0x67,
+	0x65, 0x74, 0x5f, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x10, 0x2c, 0x12, 0x27, 0x0a, 0x23,
+	0x65, 0x6e, 0x73, 0x75, 0x72, 0x65, 0x5f, 0x67, 0x70, 0x75, 0x70, 0x67, 0x72, 0x61, 0x64, 0x65,
+	0x5f, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x73, 0x5f, 0x61, 0x72, 0x65, 0x5f, 0x72, 0x75, 0x6e, 0x6e,
+	0x69, 0x6e, 0x67, 0x10, 0x2d, 0x12, 0x18, 0x0a, 0x14, 0x76, 0x65, 0x72, 0x69, 0x66, 0x79, 0x5f,
+	0x67, 0x70, 0x64, 0x62, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x10, 0x2e, 0x12,
+	0x32, 0x0a, 0x2e, 0x76, 0x65, 0x72, 0x69, 0x66, 0x79, 0x5f, 0x67, 0x70, 0x75, 0x70, 0x67, 0x72,
+	0x61, 0x64, 0x65, 0x5f, 0x69, 0x73, 0x5f, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6c, 0x6c, 0x65, 0x64,
+	0x5f, 0x61, 0x63, 0x72, 0x6f, 0x73, 0x73, 0x5f, 0x61, 0x6c, 0x6c, 0x5f, 0x68, 0x6f, 0x73, 0x74,
+	0x73, 0x10, 0x2f, 0x12, 0x2b, 0x0a, 0x27, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x69, 0x7a,
+	0x65, 0x5f, 0x77, 0x61, 0x69, 0x74, 0x5f, 0x66, 0x6f, 0x72, 0x5f, 0x63, 0x6c, 0x75, 0x73, 0x74,
+	0x65, 0x72, 0x5f, 0x74, 0x6f, 0x5f, 0x62, 0x65, 0x5f, 0x72, 0x65, 0x61, 0x64, 0x79, 0x10, 0x30,
+	0x12, 0x36, 0x0a, 0x32, 0x77, 0x61, 0x69, 0x74, 0x5f, 0x66, 0x6f, 0x72, 0x5f, 0x63, 0x6c, 0x75,
 	0x73, 0x74, 0x65, 0x72, 0x5f, 0x74, 0x6f, 0x5f, 0x62, 0x65, 0x5f, 0x72, 0x65, 0x61, 0x64, 0x79,
-	0x10, 0x30, 0x12, 0x36, 0x0a, 0x32, 0x77, 0x61, 0x69, 0x74, 0x5f, 0x66, 0x6f, 0x72, 0x5f, 0x63,
-	0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x5f, 0x74, 0x6f, 0x5f, 0x62, 0x65, 0x5f, 0x72, 0x65, 0x61,
-	0x64, 0x79, 0x5f, 0x62, 0x65, 0x66, 0x6f, 0x72, 0x65, 0x5f, 0x75, 0x70, 0x67, 0x72, 0x61, 0x64,
-	0x65, 0x5f, 0x6d, 0x61, 0x73, 0x74, 0x65, 0x72, 0x10, 0x31, 0x2a, 0x5a, 0x0a, 0x06, 0x53, 0x74,
-	0x61, 0x74, 0x75, 0x73, 0x12, 0x12, 0x0a, 0x0e, 0x75, 0x6e, 0x6b, 0x6e, 0x6f, 0x77, 0x6e, 0x5f,
-	0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x10, 0x00, 0x12, 0x0b, 0x0a, 0x07, 0x72, 0x75, 0x6e, 0x6e,
-	0x69, 0x6e, 0x67, 0x10, 0x01, 0x12, 0x0c, 0x0a, 0x08, 0x63, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74,
-	0x65, 0x10, 0x02, 0x12, 0x0a, 0x0a, 0x06, 0x66, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x10, 0x03, 0x12,
-	0x0b, 0x0a, 0x07, 0x73, 0x6b, 0x69, 0x70, 0x70, 0x65, 0x64, 0x10, 0x04, 0x12, 0x08, 0x0a, 0x04,
-	0x71, 0x75, 0x69, 0x74, 0x10, 0x05, 0x32, 0xf0, 0x03, 0x0a, 0x08, 0x43, 0x6c, 0x69, 0x54, 0x6f,
-	0x48, 0x75, 0x62, 0x12, 0x36, 0x0a, 0x0a, 0x49, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x69, 0x7a,
-	0x65, 0x12, 0x16, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x49, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x69,
-	0x7a, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0c, 0x2e, 0x69, 0x64, 0x6c, 0x2e,
-	0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x00, 0x30, 0x01, 0x12, 0x50, 0x0a, 0x17, 0x49,
-	0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x43,
-	0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x12, 0x23, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x49, 0x6e, 0x69,
-	0x74, 0x69, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x43, 0x6c, 0x75,
-	0x73, 0x74, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0c, 0x2e, 0x69, 0x64,
-	0x6c, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x00, 0x30, 0x01, 0x12, 0x30, 0x0a,
-	0x07, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x12, 0x13, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x45,
-	0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0c, 0x2e,
-	0x69, 0x64, 0x6c, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x00, 0x30, 0x01, 0x12,
-	0x32, 0x0a, 0x08, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x12, 0x14, 0x2e, 0x69, 0x64,
-	0x6c, 0x2e, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x1a, 0x0c, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22,
-	0x00, 0x30, 0x01, 0x12, 0x2e, 0x0a, 0x06, 0x52, 0x65, 0x76, 0x65, 0x72, 0x74, 0x12, 0x12, 0x2e,
-	0x69, 0x64, 0x6c, 0x2e, 0x52, 0x65, 0x76, 0x65, 0x72, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x1a, 0x0c, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22,
-	0x00, 0x30, 0x01, 0x12, 0x39, 0x0a, 0x09, 0x47, 0x65, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67,
-	0x12, 0x15, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x47, 0x65, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x47, 0x65,
-	0x74, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x45,
-	0x0a, 0x0d, 0x52, 0x65, 0x73, 0x74, 0x61, 0x72, 0x74, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x73, 0x12,
-	0x19, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x52, 0x65, 0x73, 0x74, 0x61, 0x72, 0x74, 0x41, 0x67, 0x65,
-	0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x69, 0x64, 0x6c,
-	0x2e, 0x52, 0x65, 0x73, 0x74, 0x61, 0x72, 0x74, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65,
-	0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x42, 0x0a, 0x0c, 0x53, 0x74, 0x6f, 0x70, 0x53, 0x65, 0x72,
-	0x76, 0x69, 0x63, 0x65, 0x73, 0x12, 0x18, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x53, 0x74, 0x6f, 0x70,
-	0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
-	0x16, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x53, 0x74, 0x6f, 0x70, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63,
-	0x65, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x42, 0x27, 0x5a, 0x25, 0x67, 0x69, 0x74,
-	0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x67, 0x72, 0x65, 0x65, 0x6e, 0x70, 0x6c, 0x75,
-	0x6d, 0x2d, 0x64, 0x62, 0x2f, 0x67, 0x70, 0x75, 0x70, 0x67, 0x72, 0x61, 0x64, 0x65, 0x2f, 0x69,
-	0x64, 0x6c, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x5f, 0x62, 0x65, 0x66, 0x6f, 0x72, 0x65, 0x5f, 0x75, 0x70, 0x67, 0x72, 0x61, 0x64, 0x65, 0x5f,
+	0x6d, 0x61, 0x73, 0x74, 0x65, 0x72, 0x10, 0x31, 0x12, 0x17, 0x0a, 0x13, 0x64, 0x65, 0x6c, 0x65,
+	0x74, 0x65, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x5f, 0x62, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x73, 0x10,
+	0x32, 0x12, 0x16, 0x0a, 0x12, 0x72, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x5f, 0x63, 0x6f, 0x6e,
+	0x66, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x10, 0x33, 0x2a, 0x5a, 0x0a, 0x06, 0x53, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x12, 0x12, 0x0a, 0x0e, 0x75, 0x6e, 0x6b, 0x6e, 0x6f, 0x77, 0x6e, 0x5f, 0x73,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x10, 0x00, 0x12, 0x0b, 0x0a, 0x07, 0x72, 0x75, 0x6e, 0x6e, 0x69,
+	0x6e, 0x67, 0x10, 0x01, 0x12, 0x0c, 0x0a, 0x08, 0x63, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65,
+	0x10, 0x02, 0x12, 0x0a, 0x0a, 0x06, 0x66, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x10, 0x03, 0x12, 0x0b,
+	0x0a, 0x07, 0x73, 0x6b, 0x69, 0x70, 0x70, 0x65, 0x64, 0x10, 0x04, 0x12, 0x08, 0x0a, 0x04, 0x71,
+	0x75, 0x69, 0x74, 0x10, 0x05, 0x32, 0xd2, 0x04, 0x0a, 0x08, 0x43, 0x6c, 0x69, 0x54, 0x6f, 0x48,
+	0x75, 0x62, 0x12, 0x36, 0x0a, 0x0a, 0x49, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x69, 0x7a, 0x65,
+	0x12, 0x16, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x49, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x69, 0x7a,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0c, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x4d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x00, 0x30, 0x01, 0x12, 0x50, 0x0a, 0x17, 0x49, 0x6e,
+	0x69, 0x74, 0x69, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x43, 0x6c,
+	0x75, 0x73, 0x74, 0x65, 0x72, 0x12, 0x23, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x49, 0x6e, 0x69, 0x74,
+	0x69, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x43, 0x6c, 0x75, 0x73,
+	0x74, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0c, 0x2e, 0x69, 0x64, 0x6c,
+	0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x00, 0x30, 0x01, 0x12, 0x30, 0x0a, 0x07,
+	0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x12, 0x13, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x45, 0x78,
+	0x65, 0x63, 0x75, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0c, 0x2e, 0x69,
+	0x64, 0x6c, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x00, 0x30, 0x01, 0x12, 0x32,
+	0x0a, 0x08, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x12, 0x14, 0x2e, 0x69, 0x64, 0x6c,
+	0x2e, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x0c, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x00,
+	0x30, 0x01, 0x12, 0x2e, 0x0a, 0x06, 0x52, 0x65, 0x76, 0x65, 0x72, 0x74, 0x12, 0x12, 0x2e, 0x69,
+	0x64, 0x6c, 0x2e, 0x52, 0x65, 0x76, 0x65, 0x72, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x0c, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x00,
+	0x30, 0x01, 0x12, 0x39, 0x0a, 0x09, 0x47, 0x65, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12,
+	0x15, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x47, 0x65, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x47, 0x65, 0x74,
+	0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x60, 0x0a,
+	0x16, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x43, 0x61, 0x70, 0x61, 0x62,
+	0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x12, 0x22, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x43, 0x68,
+	0x65, 0x63, 0x6b, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x43, 0x61, 0x70, 0x61, 0x62, 0x69, 0x6c, 0x69,
+	0x74, 0x69, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e, 0x69, 0x64,
+	0x6c, 0x2e, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x43, 0x61, 0x70, 0x61,
+	0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12,
+	0x45, 0x0a, 0x0d, 0x52, 0x65, 0x73, 0x74, 0x61, 0x72, 0x74, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x73,
+	0x12, 0x19, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x52, 0x65, 0x73, 0x74, 0x61, 0x72, 0x74, 0x41, 0x67,
+	0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x69, 0x64,
+	0x6c, 0x2e, 0x52, 0x65, 0x73, 0x74, 0x61, 0x72, 0x74, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x73, 0x52,
+	0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x42, 0x0a, 0x0c, 0x53, 0x74, 0x6f, 0x70, 0x53, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x12, 0x18, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x53, 0x74, 0x6f,
+	0x70, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x16, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x53, 0x74, 0x6f, 0x70, 0x53, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x42, 0x27, 0x5a, 0x25, 0x67, 0x69,
+	0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x67, 0x72, 0x65, 0x65, 0x6e, 0x70, 0x6c,
+	0x75, 0x6d, 0x2d, 0x64, 0x62, 0x2f, 0x67, 0x70, 0x75, 0x70, 0x67, 0x72, 0x61, 0x64, 0x65, 0x2f,
+	0x69, 0x64, 0x6c, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -1830,68 +2268,79 @@ func file_cli_to_hub_proto_rawDescGZIP() []byte {
 	return file_cli_to_hub_proto_rawDescData
 }
 
-var file_cli_to_hub_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
-var file_cli_to_hub_proto_msgTypes = make([]protoimpl.MessageInfo, 22)
+var file_cli_to_hub_proto_enumTypes = make([]protoimpl.EnumInfo, 5)
+var file_cli_to_hub_proto_msgTypes = make([]protoimpl.MessageInfo, 26)
 var file_cli_to_hub_proto_goTypes = []interface{}{
-	(Step)(0),                              // 0: idl.Step
-	(Substep)(0),                           // 1: idl.Substep
-	(Status)(0),                            // 2: idl.Status
-	(Chunk_Type)(0),                        // 3: idl.Chunk.Type
-	(*InitializeRequest)(nil),              // 4: idl.InitializeRequest
-	(*InitializeCreateClusterRequest)(nil), // 5: idl.InitializeCreateClusterRequest
-	(*ExecuteRequest)(nil),                 // 6: idl.ExecuteRequest
-	(*FinalizeRequest)(nil),                // 7: idl.FinalizeRequest
-	(*RevertRequest)(nil),                  // 8: idl.RevertRequest
-	(*RestartAgentsRequest)(nil),           // 9: idl.RestartAgentsRequest
-	(*RestartAgentsReply)(nil),             // 10: idl.RestartAgentsReply
-	(*StopServicesRequest)(nil),            // 11: idl.StopServicesRequest
-	(*StopServicesReply)(nil),              // 12: idl.StopServicesReply
-	(*SubstepStatus)(nil),                  // 13: idl.SubstepStatus
-	(*PrepareInitClusterRequest)(nil),      // 14: idl.PrepareInitClusterRequest
-	(*PrepareInitClusterReply)(nil),        // 15: idl.PrepareInitClusterReply
-	(*Chunk)(nil),                          // 16: idl.Chunk
-	(*Message)(nil),                        // 17: idl.Message
-	(*Response)(nil),                       // 18: idl.Response
-	(*InitializeResponse)(nil),             // 19: idl.InitializeResponse
-	(*ExecuteResponse)(nil),                // 20: idl.ExecuteResponse
-	(*FinalizeResponse)(nil),               // 21: idl.FinalizeResponse
-	(*RevertResponse)(nil),                 // 22: idl.RevertResponse
-	(*GetConfigRequest)(nil),               // 23: idl.GetConfigRequest
-	(*GetConfigReply)(nil),                 // 24: idl.GetConfigReply
-	(*NextActions)(nil),                    // 25: idl.NextActions
+	(Step)(0),                                // 0: idl.Step
+	(Substep)(0),                             // 1: idl.Substep
+	(Status)(0),                              // 2: idl.Status
+	(SubstepEvent_Type)(0),                   // 3: idl.SubstepEvent.Type
+	(Chunk_Type)(0),                          // 4: idl.Chunk.Type
+	(*InitializeRequest)(nil),                // 5: idl.InitializeRequest
+	(*InitializeCreateClusterRequest)(nil),   // 6: idl.InitializeCreateClusterRequest
+	(*ExecuteRequest)(nil),                   // 7: idl.ExecuteRequest
+	(*FinalizeRequest)(nil),                  // 8: idl.FinalizeRequest
+	(*RevertRequest)(nil),                    // 9: idl.RevertRequest
+	(*RestartAgentsRequest)(nil),             // 10: idl.RestartAgentsRequest
+	(*RestartAgentsReply)(nil),               // 11: idl.RestartAgentsReply
+	(*StopServicesRequest)(nil),              // 12: idl.StopServicesRequest
+	(*StopServicesReply)(nil),                // 13: idl.StopServicesReply
+	(*SubstepStatus)(nil),                    // 14: idl.SubstepStatus
+	(*SubstepEvent)(nil),                     // 15: idl.SubstepEvent
+	(*PrepareInitClusterRequest)(nil),        // 16: idl.PrepareInitClusterRequest
+	(*PrepareInitClusterReply)(nil),          // 17: idl.PrepareInitClusterReply
+	(*Chunk)(nil),                            // 18: idl.Chunk
+	(*Message)(nil),                          // 19: idl.Message
+	(*Response)(nil),                         // 20: idl.Response
+	(*InitializeResponse)(nil),               // 21: idl.InitializeResponse
+	(*ExecuteResponse)(nil),                  // 22: idl.ExecuteResponse
+	(*FinalizeResponse)(nil),                 // 23: idl.FinalizeResponse
+	(*RevertResponse)(nil),                   // 24: idl.RevertResponse
+	(*GetConfigRequest)(nil),                 // 25: idl.GetConfigRequest
+	(*GetConfigReply)(nil),                   // 26: idl.GetConfigReply
+	(*CheckAgentCapabilitiesRequest)(nil),    // 27: idl.CheckAgentCapabilitiesRequest
+	(*CheckAgentCapabilitiesReply)(nil),      // 28: idl.CheckAgentCapabilitiesReply
+	(*NextActions)(nil),                      // 29: idl.NextActions
+	(*CheckAgentCapabilitiesReply_Host)(nil), // 30: idl.CheckAgentCapabilitiesReply.Host
 }
 var file_cli_to_hub_proto_depIdxs = []int32{
 	1,  // 0: idl.SubstepStatus.step:type_name -> idl.Substep
 	2,  // 1: idl.SubstepStatus.status:type_name -> idl.Status
-	3,  // 2: idl.Chunk.type:type_name -> idl.Chunk.Type
-	16, // 3: idl.Message.chunk:type_name -> idl.Chunk
-	13, // 4: idl.Message.status:type_name -> idl.SubstepStatus
-	18, // 5: idl.Message.response:type_name -> idl.Response
-	19, // 6: idl.Response.initializeResponse:type_name -> idl.InitializeResponse
-	20, // 7: idl.Response.executeResponse:type_name -> idl.ExecuteResponse
-	21, // 8: idl.Response.finalizeResponse:type_name -> idl.FinalizeResponse
-	22, // 9: idl.Response.revertResponse:type_name -> idl.RevertResponse
-	4,  // 10: idl.CliToHub.Initialize:input_type -> idl.InitializeRequest
-	5,  // 11: idl.CliToHub.InitializeCreateCluster:input_type -> idl.InitializeCreateClusterRequest
-	6,  // 12: idl.CliToHub.Execute:input_type -> idl.ExecuteRequest
-	7,  // 13: idl.CliToHub.Finalize:input_type -> idl.FinalizeRequest
-	8,  // 14: idl.CliToHub.Revert:input_type -> idl.RevertRequest
-	23, // 15: idl.CliToHub.GetConfig:input_type -> idl.GetConfigRequest
-	9,  // 16: idl.CliToHub.RestartAgents:input_type -> idl.RestartAgentsRequest
-	11, // 17: idl.CliToHub.StopServices:input_type -> idl.StopServicesRequest
-	17, // 18: idl.CliToHub.Initialize:output_type -> idl.Message
-	17, // 19: idl.CliToHub.InitializeCreateCluster:output_type -> idl.Message
-	17, // 20: idl.CliToHub.Execute:output_type -> idl.Message
-	17, // 21: idl.CliToHub.Finalize:output_type -> idl.Message
-	17, // 22: idl.CliToHub.Revert:output_type -> idl.Message
-	24, // 23: idl.CliToHub.GetConfig:output_type -> idl.GetConfigReply
-	10, // 24: idl.CliToHub.RestartAgents:output_type -> idl.RestartAgentsReply
-	12, // 25: idl.CliToHub.StopServices:output_type -> idl.StopServicesReply
-	18, // [18:26] is the sub-list for method output_type
-	10, // [10:18] is the sub-list for method input_type
-	10, // [10:10] is the sub-list for extension type_name
-	10, // [10:10] is the sub-list for extension extendee
-	0,  // [0:10] is the sub-list for field type_name
+	1,  // 2: idl.SubstepEvent.substep:type_name -> idl.Substep
+	3,  // 3: idl.SubstepEvent.type:type_name -> idl.SubstepEvent.Type
+	4,  // 4: idl.Chunk.type:type_name -> idl.Chunk.Type
+	18, // 5: idl.Message.chunk:type_name -> idl.Chunk
+	14, // 6: idl.Message.status:type_name -> idl.SubstepStatus
+	20, // 7: idl.Message.response:type_name -> idl.Response
+	15, // 8: idl.Message.event:type_name -> idl.SubstepEvent
+	21, // 9: idl.Response.initializeResponse:type_name -> idl.InitializeResponse
+	22, // 10: idl.Response.executeResponse:type_name -> idl.ExecuteResponse
+	23, // 11: idl.Response.finalizeResponse:type_name -> idl.FinalizeResponse
+	24, // 12: idl.Response.revertResponse:type_name -> idl.RevertResponse
+	30, // 13: idl.CheckAgentCapabilitiesReply.hosts:type_name -> idl.CheckAgentCapabilitiesReply.Host
+	5,  // 14: idl.CliToHub.Initialize:input_type -> idl.InitializeRequest
+	6,  // 15: idl.CliToHub.InitializeCreateCluster:input_type -> idl.InitializeCreateClusterRequest
+	7,  // 16: idl.CliToHub.Execute:input_type -> idl.ExecuteRequest
+	8,  // 17: idl.CliToHub.Finalize:input_type -> idl.FinalizeRequest
+	9,  // 18: idl.CliToHub.Revert:input_type -> idl.RevertRequest
+	25, // 19: idl.CliToHub.GetConfig:input_type -> idl.GetConfigRequest
+	27, // 20: idl.CliToHub.CheckAgentCapabilities:input_type -> idl.CheckAgentCapabilitiesRequest
+	10, // 21: idl.CliToHub.RestartAgents:input_type -> idl.RestartAgentsRequest
+	12, // 22: idl.CliToHub.StopServices:input_type -> idl.StopServicesRequest
+	19, // 23: idl.CliToHub.Initialize:output_type -> idl.Message
+	19, // 24: idl.CliToHub.InitializeCreateCluster:output_type -> idl.Message
+	19, // 25: idl.CliToHub.Execute:output_type -> idl.Message
+	19, // 26: idl.CliToHub.Finalize:output_type -> idl.Message
+	19, // 27: idl.CliToHub.Revert:output_type -> idl.Message
+	26, // 28: idl.CliToHub.GetConfig:output_type -> idl.GetConfigReply
+	28, // 29: idl.CliToHub.CheckAgentCapabilities:output_type -> idl.CheckAgentCapabilitiesReply
+	11, // 30: idl.CliToHub.RestartAgents:output_type -> idl.RestartAgentsReply
+	13, // 31: idl.CliToHub.StopServices:output_type -> idl.StopServicesReply
+	23, // [23:32] is the sub-list for method output_type
+	14, // [14:23] is the sub-list for method input_type
+	14, // [14:14] is the sub-list for extension type_name
+	14, // [14:14] is the sub-list for extension extendee
+	0,  // [0:14] is the sub-list for field type_name
 }
 
 func init() { file_cli_to_hub_proto_init() }
@@ -2021,7 +2470,7 @@ func file_cli_to_hub_proto_init() {
 			}
 		}
 		file_cli_to_hub_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PrepareInitClusterRequest); i {
+			switch v := v.(*SubstepEvent); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2033,7 +2482,7 @@ func file_cli_to_hub_proto_init() {
 			}
 		}
 		file_cli_to_hub_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PrepareInitClusterReply); i {
+			switch v := v.(*PrepareInitClusterRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2045,7 +2494,7 @@ func file_cli_to_hub_proto_init() {
 			}
 		}
 		file_cli_to_hub_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Chunk); i {
+			switch v := v.(*PrepareInitClusterReply); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2057,7 +2506,7 @@ func file_cli_to_hub_proto_init() {
 			}
 		}
 		file_cli_to_hub_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Message); i {
+			switch v := v.(*Chunk); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2069,7 +2518,7 @@ func file_cli_to_hub_proto_init() {
 			}
 		}
 		file_cli_to_hub_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Response); i {
+			switch v := v.(*Message); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2081,7 +2530,7 @@ func file_cli_to_hub_proto_init() {
 			}
 		}
 		file_cli_to_hub_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*InitializeResponse); i {
+			switch v := v.(*Response); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2093,7 +2542,7 @@ func file_cli_to_hub_proto_init() {
 			}
 		}
 		file_cli_to_hub_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ExecuteResponse); i {
+			switch v := v.(*InitializeResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2105,7 +2554,7 @@ func file_cli_to_hub_proto_init() {
 			}
 		}
 		file_cli_to_hub_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*FinalizeResponse); i {
+			switch v := v.(*ExecuteResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2117,7 +2566,7 @@ func file_cli_to_hub_proto_init() {
 			}
 		}
 		file_cli_to_hub_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RevertResponse); i {
+			switch v := v.(*FinalizeResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2129,7 +2578,7 @@ func file_cli_to_hub_proto_init() {
 			}
 		}
 		file_cli_to_hub_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetConfigRequest); i {
+			switch v := v.(*RevertResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2141,7 +2590,7 @@ func file_cli_to_hub_proto_init() {
 			}
 		}
 		file_cli_to_hub_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetConfigReply); i {
+			switch v := v.(*GetConfigRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2153,6 +2602,42 @@ func file_cli_to_hub_proto_init() {
 			}
 		}
 		file_cli_to_hub_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetConfigReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cli_to_hub_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CheckAgentCapabilitiesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cli_to_hub_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CheckAgentCapabilitiesReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cli_to_hub_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*NextActions); i {
 			case 0:
 				return &v.state
@@ -2164,13 +2649,26 @@ func file_cli_to_hub_proto_init() {
 				return nil
 			}
 		}
+		file_cli_to_hub_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CheckAgentCapabilitiesReply_Host); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
-	file_cli_to_hub_proto_msgTypes[13].OneofWrappers = []interface{}{
+	file_cli_to_hub_proto_msgTypes[14].OneofWrappers = []interface{}{
 		(*Message_Chunk)(nil),
 		(*Message_Status)(nil),
 		(*Message_Response)(nil),
+		(*Message_Event)(nil),
 	}
-	file_cli_to_hub_proto_msgTypes[14].OneofWrappers = []interface{}{
+	file_cli_to_hub_proto_msgTypes[15].OneofWrappers = []interface{}{
 		(*Response_InitializeResponse)(nil),
 		(*Response_ExecuteResponse)(nil),
 		(*Response_FinalizeResponse)(nil),
@@ -2181,8 +2679,8 @@ func file_cli_to_hub_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_cli_to_hub_proto_rawDesc,
-			NumEnums:      4,
-			NumMessages:   22,
+			NumEnums:      5,
+			NumMessages:   26,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
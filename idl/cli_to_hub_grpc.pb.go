@@ -28,6 +28,7 @@ const (
 	CliToHub_Finalize_FullMethodName                = "/idl.CliToHub/Finalize"
 	CliToHub_Revert_FullMethodName                  = "/idl.CliToHub/Revert"
 	CliToHub_GetConfig_FullMethodName               = "/idl.CliToHub/GetConfig"
+	CliToHub_CheckAgentCapabilities_FullMethodName  = "/idl.CliToHub/CheckAgentCapabilities"
 	CliToHub_RestartAgents_FullMethodName           = "/idl.CliToHub/RestartAgents"
 	CliToHub_StopServices_FullMethodName            = "/idl.CliToHub/StopServices"
 )
@@ -42,6 +43,7 @@ type CliToHubClient interface {
 	Finalize(ctx context.Context, in *FinalizeRequest, opts ...grpc.CallOption) (CliToHub_FinalizeClient, error)
 	Revert(ctx context.Context, in *RevertRequest, opts ...grpc.CallOption) (CliToHub_RevertClient, error)
 	GetConfig(ctx context.Context, in *GetConfigRequest, opts ...grpc.CallOption) (*GetConfigReply, error)
+	CheckAgentCapabilities(ctx context.Context, in *CheckAgentCapabilitiesRequest, opts ...grpc.CallOption) (*CheckAgentCapabilitiesReply, error)
 	RestartAgents(ctx context.Context, in *RestartAgentsRequest, opts ...grpc.CallOption) (*RestartAgentsReply, error)
 	StopServices(ctx context.Context, in *StopServicesRequest, opts ...grpc.CallOption) (*StopServicesReply, error)
 }
@@ -223,6 +225,15 @@ func (c *cliToHubClient) GetConfig(ctx context.Context, in *GetConfigRequest, op
 	return out, nil
 }
 
+func (c *cliToHubClient) CheckAgentCapabilities(ctx context.Context, in *CheckAgentCapabilitiesRequest, opts ...grpc.CallOption) (*CheckAgentCapabilitiesReply, error) {
+	out := new(CheckAgentCapabilitiesReply)
+	err := c.cc.Invoke(ctx, CliToHub_CheckAgentCapabilities_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *cliToHubClient) RestartAgents(ctx context.Context, in *RestartAgentsRequest, opts ...grpc.CallOption) (*RestartAgentsReply, error) {
 	out := new(RestartAgentsReply)
 	err := c.cc.Invoke(ctx, CliToHub_RestartAgents_FullMethodName, in, out, opts...)
@@ -251,6 +262,7 @@ type CliToHubServer interface {
 	Finalize(*FinalizeRequest, CliToHub_FinalizeServer) error
 	Revert(*RevertRequest, CliToHub_RevertServer) error
 	GetConfig(context.Context, *GetConfigRequest) (*GetConfigReply, error)
+	CheckAgentCapabilities(context.Context, *CheckAgentCapabilitiesRequest) (*CheckAgentCapabilitiesReply, error)
 	RestartAgents(context.Context, *RestartAgentsRequest) (*RestartAgentsReply, error)
 	StopServices(context.Context, *StopServicesRequest) (*StopServicesReply, error)
 }
@@ -277,6 +289,9 @@ func (UnimplementedCliToHubServer) Revert(*RevertRequest, CliToHub_RevertServer)
 func (UnimplementedCliToHubServer) GetConfig(context.Context, *GetConfigRequest) (*GetConfigReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetConfig not implemented")
 }
+func (UnimplementedCliToHubServer) CheckAgentCapabilities(context.Context, *CheckAgentCapabilitiesRequest) (*CheckAgentCapabilitiesReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckAgentCapabilities not implemented")
+}
 func (UnimplementedCliToHubServer) RestartAgents(context.Context, *RestartAgentsRequest) (*RestartAgentsReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RestartAgents not implemented")
 }
@@ -418,6 +433,24 @@ func _CliToHub_GetConfig_Handler(srv interface{}, ctx context.Context, dec func(
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CliToHub_CheckAgentCapabilities_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckAgentCapabilitiesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CliToHubServer).CheckAgentCapabilities(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CliToHub_CheckAgentCapabilities_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CliToHubServer).CheckAgentCapabilities(ctx, req.(*CheckAgentCapabilitiesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _CliToHub_RestartAgents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(RestartAgentsRequest)
 	if err := dec(in); err != nil {
@@ -465,6 +498,10 @@ var CliToHub_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetConfig",
 			Handler:    _CliToHub_GetConfig_Handler,
 		},
+		{
+			MethodName: "CheckAgentCapabilities",
+			Handler:    _CliToHub_CheckAgentCapabilities_Handler,
+		},
 		{
 			MethodName: "RestartAgents",
 			Handler:    _CliToHub_RestartAgents_Handler,
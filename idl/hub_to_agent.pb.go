@@ -121,6 +121,59 @@ func (PgOptions_Action) EnumDescriptor() ([]byte, []int) {
 	return file_hub_to_agent_proto_rawDescGZIP(), []int{0, 1}
 }
 
+// DuplicateMatches is how a pattern matching more than one line is handled.
+type UpdateFileConfOptions_DuplicateMatches int32
+
+const (
+	UpdateFileConfOptions_rewriteAllMatches    UpdateFileConfOptions_DuplicateMatches = 0 // every matching line is rewritten, as sed does
+	UpdateFileConfOptions_warnDuplicateMatches UpdateFileConfOptions_DuplicateMatches = 1 // every matching line is rewritten with a warning
+	UpdateFileConfOptions_failDuplicateMatches UpdateFileConfOptions_DuplicateMatches = 2 // the edit fails
+	UpdateFileConfOptions_rewriteLastMatch     UpdateFileConfOptions_DuplicateMatches = 3 // only the last matching line, the one in effect, is rewritten with a warning
+)
+
+// Enum value maps for UpdateFileConfOptions_DuplicateMatches.
+var (
+	UpdateFileConfOptions_DuplicateMatches_name = map[int32]string{
+		0: "rewriteAllMatches",
+		1: "warnDuplicateMatches",
+		2: "failDuplicateMatches",
+		3: "rewriteLastMatch",
+	}
+	UpdateFileConfOptions_DuplicateMatches_value = map[string]int32{
+		"rewriteAllMatches":    0,
+		"warnDuplicateMatches": 1,
+		"failDuplicateMatches": 2,
+		"rewriteLastMatch":     3,
+	}
+)
+
+func (x UpdateFileConfOptions_DuplicateMatches) Enum() *UpdateFileConfOptions_DuplicateMatches {
+	p := new(UpdateFileConfOptions_DuplicateMatches)
+	*p = x
+	return p
+}
+
+func (x UpdateFileConfOptions_DuplicateMatches) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (UpdateFileConfOptions_DuplicateMatches) Descriptor() protoreflect.EnumDescriptor {
+	return file_hub_to_agent_proto_enumTypes[2].Descriptor()
+}
+
+func (UpdateFileConfOptions_DuplicateMatches) Type() protoreflect.EnumType {
+	return &file_hub_to_agent_proto_enumTypes[2]
+}
+
+func (x UpdateFileConfOptions_DuplicateMatches) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use UpdateFileConfOptions_DuplicateMatches.Descriptor instead.
+func (UpdateFileConfOptions_DuplicateMatches) EnumDescriptor() ([]byte, []int) {
+	return file_hub_to_agent_proto_rawDescGZIP(), []int{27, 0}
+}
+
 type PgOptions struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -1470,9 +1523,24 @@ type UpdateFileConfOptions struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Path        string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
-	Pattern     string `protobuf:"bytes,2,opt,name=pattern,proto3" json:"pattern,omitempty"`
-	Replacement string `protobuf:"bytes,3,opt,name=replacement,proto3" json:"replacement,omitempty"`
+	Path                    string                                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Pattern                 string                                 `protobuf:"bytes,2,opt,name=pattern,proto3" json:"pattern,omitempty"`
+	Replacement             string                                 `protobuf:"bytes,3,opt,name=replacement,proto3" json:"replacement,omitempty"`
+	Reason                  string                                 `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`                                    // the feature that produced the edit, such as "port-rewrite"
+	Guc                     string                                 `protobuf:"bytes,5,opt,name=guc,proto3" json:"guc,omitempty"`                                          // when set the assignment of the GUC is validated before editing
+	ExpectedMatches         int32                                  `protobuf:"varint,6,opt,name=expectedMatches,proto3" json:"expectedMatches,omitempty"`                 // when positive the pattern must match exactly this many lines before editing
+	PreserveTrailingNewline bool                                   `protobuf:"varint,7,opt,name=preserveTrailingNewline,proto3" json:"preserveTrailingNewline,omitempty"` // when set the file keeps its original trailing newlines rather than ending with exactly one
+	MatchCurrentValue       string                                 `protobuf:"bytes,8,opt,name=matchCurrentValue,proto3" json:"matchCurrentValue,omitempty"`              // when set the edit is skipped unless the guc currently has this value
+	ExpectedValue           string                                 `protobuf:"bytes,9,opt,name=expectedValue,proto3" json:"expectedValue,omitempty"`                      // the value the guc must have after the edit, with only the port compared for primary_conninfo
+	AllowNoMatch            bool                                   `protobuf:"varint,10,opt,name=allowNoMatch,proto3" json:"allowNoMatch,omitempty"`                      // when set a pattern matching no lines is not an error, such as for an optional file
+	BackupSuffix            string                                 `protobuf:"bytes,11,opt,name=backupSuffix,proto3" json:"backupSuffix,omitempty"`                       // the suffix naming the backup of the file, defaulting to .bak
+	AppendIfMissing         string                                 `protobuf:"bytes,12,opt,name=appendIfMissing,proto3" json:"appendIfMissing,omitempty"`                 // when set and the pattern matches no lines this line is appended, unless the guc is already set
+	SkipIfAbsent            bool                                   `protobuf:"varint,13,opt,name=skipIfAbsent,proto3" json:"skipIfAbsent,omitempty"`                      // when set the edit of a missing file is skipped rather than failing, such as for an optional file
+	DuplicateMatches        UpdateFileConfOptions_DuplicateMatches `protobuf:"varint,14,opt,name=duplicateMatches,proto3,enum=idl.UpdateFileConfOptions_DuplicateMatches" json:"duplicateMatches,omitempty"`
+	DataDir                 string                                 `protobuf:"bytes,15,opt,name=dataDir,proto3" json:"dataDir,omitempty"`           // when set the path must be within this data directory, which must be a clean absolute path
+	BackupDir               string                                 `protobuf:"bytes,16,opt,name=backupDir,proto3" json:"backupDir,omitempty"`       // when set the file is also backed up to this central directory, under the hostname and its absolute path
+	BackupPolicy            string                                 `protobuf:"bytes,17,opt,name=backupPolicy,proto3" json:"backupPolicy,omitempty"` // how an existing backup of the file is handled, such as "fail", defaulting to the policy of the agent
+	TempDir                 string                                 `protobuf:"bytes,18,opt,name=tempDir,proto3" json:"tempDir,omitempty"`           // when set the temporary file of the rewrite is written here if on the same filesystem as the file
 }
 
 func (x *UpdateFileConfOptions) Reset() {
@@ -1528,12 +1596,128 @@ func (x *UpdateFileConfOptions) GetReplacement() string {
 	return ""
 }
 
+func (x *UpdateFileConfOptions) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *UpdateFileConfOptions) GetGuc() string {
+	if x != nil {
+		return x.Guc
+	}
+	return ""
+}
+
+func (x *UpdateFileConfOptions) GetExpectedMatches() int32 {
+	if x != nil {
+		return x.ExpectedMatches
+	}
+	return 0
+}
+
+func (x *UpdateFileConfOptions) GetPreserveTrailingNewline() bool {
+	if x != nil {
+		return x.PreserveTrailingNewline
+	}
+	return false
+}
+
+func (x *UpdateFileConfOptions) GetMatchCurrentValue() string {
+	if x != nil {
+		return x.MatchCurrentValue
+	}
+	return ""
+}
+
+func (x *UpdateFileConfOptions) GetExpectedValue() string {
+	if x != nil {
+		return x.ExpectedValue
+	}
+	return ""
+}
+
+func (x *UpdateFileConfOptions) GetAllowNoMatch() bool {
+	if x != nil {
+		return x.AllowNoMatch
+	}
+	return false
+}
+
+func (x *UpdateFileConfOptions) GetBackupSuffix() string {
+	if x != nil {
+		return x.BackupSuffix
+	}
+	return ""
+}
+
+func (x *UpdateFileConfOptions) GetAppendIfMissing() string {
+	if x != nil {
+		return x.AppendIfMissing
+	}
+	return ""
+}
+
+func (x *UpdateFileConfOptions) GetSkipIfAbsent() bool {
+	if x != nil {
+		return x.SkipIfAbsent
+	}
+	return false
+}
+
+func (x *UpdateFileConfOptions) GetDuplicateMatches() UpdateFileConfOptions_DuplicateMatches {
+	if x != nil {
+		return x.DuplicateMatches
+	}
+	return UpdateFileConfOptions_rewriteAllMatches
+}
+
+func (x *UpdateFileConfOptions) GetDataDir() string {
+	if x != nil {
+		return x.DataDir
+	}
+	return ""
+}
+
+func (x *UpdateFileConfOptions) GetBackupDir() string {
+	if x != nil {
+		return x.BackupDir
+	}
+	return ""
+}
+
+func (x *UpdateFileConfOptions) GetBackupPolicy() string {
+	if x != nil {
+		return x.BackupPolicy
+	}
+	return ""
+}
+
+func (x *UpdateFileConfOptions) GetTempDir() string {
+	if x != nil {
+		return x.TempDir
+	}
+	return ""
+}
+
 type UpdateConfigurationRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
 	Options []*UpdateFileConfOptions `protobuf:"bytes,1,rep,name=options,proto3" json:"options,omitempty"`
+	// verifyAfterWrite reads back the GUC of each option after writing.
+	VerifyAfterWrite bool `protobuf:"varint,2,opt,name=verifyAfterWrite,proto3" json:"verifyAfterWrite,omitempty"`
+	// dryRun computes the edits and returns their diffs without backing up or
+	// writing any file.
+	DryRun bool `protobuf:"varint,3,opt,name=dryRun,proto3" json:"dryRun,omitempty"`
+	// debugLogging logs the outcome of each option on the agent.
+	DebugLogging bool `protobuf:"varint,4,opt,name=debugLogging,proto3" json:"debugLogging,omitempty"`
+	// fileConcurrency caps the files the agent edits at once, which is its
+	// share of the concurrency budget of the conf update. Zero uses the
+	// concurrency of the agent.
+	FileConcurrency int32 `protobuf:"varint,5,opt,name=fileConcurrency,proto3" json:"fileConcurrency,omitempty"`
 }
 
 func (x *UpdateConfigurationRequest) Reset() {
@@ -1575,14 +1759,46 @@ func (x *UpdateConfigurationRequest) GetOptions() []*UpdateFileConfOptions {
 	return nil
 }
 
-type UpdateConfigurationReply struct {
+func (x *UpdateConfigurationRequest) GetVerifyAfterWrite() bool {
+	if x != nil {
+		return x.VerifyAfterWrite
+	}
+	return false
+}
+
+func (x *UpdateConfigurationRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+func (x *UpdateConfigurationRequest) GetDebugLogging() bool {
+	if x != nil {
+		return x.DebugLogging
+	}
+	return false
+}
+
+func (x *UpdateConfigurationRequest) GetFileConcurrency() int32 {
+	if x != nil {
+		return x.FileConcurrency
+	}
+	return 0
+}
+
+type ConfFileDiff struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	Path string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	// diff is the unified diff hunks of the edits to the file.
+	Diff string `protobuf:"bytes,2,opt,name=diff,proto3" json:"diff,omitempty"`
 }
 
-func (x *UpdateConfigurationReply) Reset() {
-	*x = UpdateConfigurationReply{}
+func (x *ConfFileDiff) Reset() {
+	*x = ConfFileDiff{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_hub_to_agent_proto_msgTypes[29]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1590,13 +1806,13 @@ func (x *UpdateConfigurationReply) Reset() {
 	}
 }
 
-func (x *UpdateConfigurationReply) String() string {
+func (x *ConfFileDiff) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateConfigurationReply) ProtoMessage() {}
+func (*ConfFileDiff) ProtoMessage() {}
 
-func (x *UpdateConfigurationReply) ProtoReflect() protoreflect.Message {
+func (x *ConfFileDiff) ProtoReflect() protoreflect.Message {
 	mi := &file_hub_to_agent_proto_msgTypes[29]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1608,21 +1824,59 @@ func (x *UpdateConfigurationReply) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateConfigurationReply.ProtoReflect.Descriptor instead.
-func (*UpdateConfigurationReply) Descriptor() ([]byte, []int) {
+// Deprecated: Use ConfFileDiff.ProtoReflect.Descriptor instead.
+func (*ConfFileDiff) Descriptor() ([]byte, []int) {
 	return file_hub_to_agent_proto_rawDescGZIP(), []int{29}
 }
 
-type RenameTablespacesRequest struct {
+func (x *ConfFileDiff) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *ConfFileDiff) GetDiff() string {
+	if x != nil {
+		return x.Diff
+	}
+	return ""
+}
+
+type UpdateConfigurationReply struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	RenamePairs []*RenameTablespacesRequest_RenamePair `protobuf:"bytes,1,rep,name=renamePairs,proto3" json:"renamePairs,omitempty"`
+	// changedPaths are the files whose contents were changed by the update.
+	ChangedPaths []string `protobuf:"bytes,1,rep,name=changedPaths,proto3" json:"changedPaths,omitempty"`
+	// values are the GUCs read back when verifyAfterWrite is set.
+	Values []*ReadConfigurationReply_Value `protobuf:"bytes,2,rep,name=values,proto3" json:"values,omitempty"`
+	// skipped describes the edits skipped as their matchCurrentValue
+	// precondition was not met.
+	Skipped []string `protobuf:"bytes,3,rep,name=skipped,proto3" json:"skipped,omitempty"`
+	// rolledBackPaths are the files restored to their original contents since
+	// verifying them after writing failed.
+	RolledBackPaths []string `protobuf:"bytes,4,rep,name=rolledBackPaths,proto3" json:"rolledBackPaths,omitempty"`
+	// diffs are the files that would change when dryRun is set.
+	Diffs []*ConfFileDiff `protobuf:"bytes,5,rep,name=diffs,proto3" json:"diffs,omitempty"`
+	// changedLines are the lines changed by each option in the files written,
+	// in the order of the options.
+	ChangedLines []*ChangedLine `protobuf:"bytes,6,rep,name=changedLines,proto3" json:"changedLines,omitempty"`
+	// checksums are those of the files read back after the update.
+	Checksums []*ConfFileChecksum `protobuf:"bytes,7,rep,name=checksums,proto3" json:"checksums,omitempty"`
+	// warnings describe the patterns that matched more than one line.
+	Warnings []string `protobuf:"bytes,8,rep,name=warnings,proto3" json:"warnings,omitempty"`
+	// satisfied describes the edits skipped as their GUC already had its
+	// expected value, such as when rerunning an interrupted update.
+	Satisfied []string `protobuf:"bytes,9,rep,name=satisfied,proto3" json:"satisfied,omitempty"`
+	// resolvedPaths are the real files, next to which their backups are
+	// written, of the changed paths that are symlinks.
+	ResolvedPaths map[string]string `protobuf:"bytes,10,rep,name=resolvedPaths,proto3" json:"resolvedPaths,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 }
 
-func (x *RenameTablespacesRequest) Reset() {
-	*x = RenameTablespacesRequest{}
+func (x *UpdateConfigurationReply) Reset() {
+	*x = UpdateConfigurationReply{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_hub_to_agent_proto_msgTypes[30]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1630,13 +1884,13 @@ func (x *RenameTablespacesRequest) Reset() {
 	}
 }
 
-func (x *RenameTablespacesRequest) String() string {
+func (x *UpdateConfigurationReply) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RenameTablespacesRequest) ProtoMessage() {}
+func (*UpdateConfigurationReply) ProtoMessage() {}
 
-func (x *RenameTablespacesRequest) ProtoReflect() protoreflect.Message {
+func (x *UpdateConfigurationReply) ProtoReflect() protoreflect.Message {
 	mi := &file_hub_to_agent_proto_msgTypes[30]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1648,26 +1902,96 @@ func (x *RenameTablespacesRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RenameTablespacesRequest.ProtoReflect.Descriptor instead.
-func (*RenameTablespacesRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use UpdateConfigurationReply.ProtoReflect.Descriptor instead.
+func (*UpdateConfigurationReply) Descriptor() ([]byte, []int) {
 	return file_hub_to_agent_proto_rawDescGZIP(), []int{30}
 }
 
-func (x *RenameTablespacesRequest) GetRenamePairs() []*RenameTablespacesRequest_RenamePair {
+func (x *UpdateConfigurationReply) GetChangedPaths() []string {
 	if x != nil {
-		return x.RenamePairs
+		return x.ChangedPaths
 	}
 	return nil
 }
 
-type RenameTablespacesReply struct {
+func (x *UpdateConfigurationReply) GetValues() []*ReadConfigurationReply_Value {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+func (x *UpdateConfigurationReply) GetSkipped() []string {
+	if x != nil {
+		return x.Skipped
+	}
+	return nil
+}
+
+func (x *UpdateConfigurationReply) GetRolledBackPaths() []string {
+	if x != nil {
+		return x.RolledBackPaths
+	}
+	return nil
+}
+
+func (x *UpdateConfigurationReply) GetDiffs() []*ConfFileDiff {
+	if x != nil {
+		return x.Diffs
+	}
+	return nil
+}
+
+func (x *UpdateConfigurationReply) GetChangedLines() []*ChangedLine {
+	if x != nil {
+		return x.ChangedLines
+	}
+	return nil
+}
+
+func (x *UpdateConfigurationReply) GetChecksums() []*ConfFileChecksum {
+	if x != nil {
+		return x.Checksums
+	}
+	return nil
+}
+
+func (x *UpdateConfigurationReply) GetWarnings() []string {
+	if x != nil {
+		return x.Warnings
+	}
+	return nil
+}
+
+func (x *UpdateConfigurationReply) GetSatisfied() []string {
+	if x != nil {
+		return x.Satisfied
+	}
+	return nil
+}
+
+func (x *UpdateConfigurationReply) GetResolvedPaths() map[string]string {
+	if x != nil {
+		return x.ResolvedPaths
+	}
+	return nil
+}
+
+type ConfFileChecksum struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	Path string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	// sha256 is the hex digest of the file read back after the update.
+	Sha256 string `protobuf:"bytes,2,opt,name=sha256,proto3" json:"sha256,omitempty"`
+	// matchedOptions is the number of options of the file whose edit is in
+	// the file read back.
+	MatchedOptions int32 `protobuf:"varint,3,opt,name=matchedOptions,proto3" json:"matchedOptions,omitempty"`
 }
 
-func (x *RenameTablespacesReply) Reset() {
-	*x = RenameTablespacesReply{}
+func (x *ConfFileChecksum) Reset() {
+	*x = ConfFileChecksum{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_hub_to_agent_proto_msgTypes[31]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1675,13 +1999,13 @@ func (x *RenameTablespacesReply) Reset() {
 	}
 }
 
-func (x *RenameTablespacesReply) String() string {
+func (x *ConfFileChecksum) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RenameTablespacesReply) ProtoMessage() {}
+func (*ConfFileChecksum) ProtoMessage() {}
 
-func (x *RenameTablespacesReply) ProtoReflect() protoreflect.Message {
+func (x *ConfFileChecksum) ProtoReflect() protoreflect.Message {
 	mi := &file_hub_to_agent_proto_msgTypes[31]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1693,21 +2017,46 @@ func (x *RenameTablespacesReply) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RenameTablespacesReply.ProtoReflect.Descriptor instead.
-func (*RenameTablespacesReply) Descriptor() ([]byte, []int) {
+// Deprecated: Use ConfFileChecksum.ProtoReflect.Descriptor instead.
+func (*ConfFileChecksum) Descriptor() ([]byte, []int) {
 	return file_hub_to_agent_proto_rawDescGZIP(), []int{31}
 }
 
-type CreateRecoveryConfRequest struct {
+func (x *ConfFileChecksum) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *ConfFileChecksum) GetSha256() string {
+	if x != nil {
+		return x.Sha256
+	}
+	return ""
+}
+
+func (x *ConfFileChecksum) GetMatchedOptions() int32 {
+	if x != nil {
+		return x.MatchedOptions
+	}
+	return 0
+}
+
+type ChangedLine struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Connections []*CreateRecoveryConfRequest_Connection `protobuf:"bytes,1,rep,name=connections,proto3" json:"connections,omitempty"`
+	// option is the index of the option within the request.
+	Option  int32  `protobuf:"varint,1,opt,name=option,proto3" json:"option,omitempty"`
+	Path    string `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	OldLine string `protobuf:"bytes,3,opt,name=oldLine,proto3" json:"oldLine,omitempty"`
+	NewLine string `protobuf:"bytes,4,opt,name=newLine,proto3" json:"newLine,omitempty"`
 }
 
-func (x *CreateRecoveryConfRequest) Reset() {
-	*x = CreateRecoveryConfRequest{}
+func (x *ChangedLine) Reset() {
+	*x = ChangedLine{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_hub_to_agent_proto_msgTypes[32]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1715,13 +2064,13 @@ func (x *CreateRecoveryConfRequest) Reset() {
 	}
 }
 
-func (x *CreateRecoveryConfRequest) String() string {
+func (x *ChangedLine) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateRecoveryConfRequest) ProtoMessage() {}
+func (*ChangedLine) ProtoMessage() {}
 
-func (x *CreateRecoveryConfRequest) ProtoReflect() protoreflect.Message {
+func (x *ChangedLine) ProtoReflect() protoreflect.Message {
 	mi := &file_hub_to_agent_proto_msgTypes[32]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1733,40 +2082,63 @@ func (x *CreateRecoveryConfRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateRecoveryConfRequest.ProtoReflect.Descriptor instead.
-func (*CreateRecoveryConfRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use ChangedLine.ProtoReflect.Descriptor instead.
+func (*ChangedLine) Descriptor() ([]byte, []int) {
 	return file_hub_to_agent_proto_rawDescGZIP(), []int{32}
 }
 
-func (x *CreateRecoveryConfRequest) GetConnections() []*CreateRecoveryConfRequest_Connection {
+func (x *ChangedLine) GetOption() int32 {
 	if x != nil {
-		return x.Connections
+		return x.Option
 	}
-	return nil
+	return 0
 }
 
-type CreateRecoveryConfReply struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
+func (x *ChangedLine) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
 }
 
-func (x *CreateRecoveryConfReply) Reset() {
-	*x = CreateRecoveryConfReply{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_hub_to_agent_proto_msgTypes[33]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
+func (x *ChangedLine) GetOldLine() string {
+	if x != nil {
+		return x.OldLine
 	}
+	return ""
 }
 
-func (x *CreateRecoveryConfReply) String() string {
+func (x *ChangedLine) GetNewLine() string {
+	if x != nil {
+		return x.NewLine
+	}
+	return ""
+}
+
+type ReadConfigurationRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Files []*ReadConfigurationRequest_File `protobuf:"bytes,1,rep,name=files,proto3" json:"files,omitempty"`
+}
+
+func (x *ReadConfigurationRequest) Reset() {
+	*x = ReadConfigurationRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_hub_to_agent_proto_msgTypes[33]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReadConfigurationRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateRecoveryConfReply) ProtoMessage() {}
+func (*ReadConfigurationRequest) ProtoMessage() {}
 
-func (x *CreateRecoveryConfReply) ProtoReflect() protoreflect.Message {
+func (x *ReadConfigurationRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_hub_to_agent_proto_msgTypes[33]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1778,21 +2150,29 @@ func (x *CreateRecoveryConfReply) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateRecoveryConfReply.ProtoReflect.Descriptor instead.
-func (*CreateRecoveryConfReply) Descriptor() ([]byte, []int) {
+// Deprecated: Use ReadConfigurationRequest.ProtoReflect.Descriptor instead.
+func (*ReadConfigurationRequest) Descriptor() ([]byte, []int) {
 	return file_hub_to_agent_proto_rawDescGZIP(), []int{33}
 }
 
-type AddReplicationEntriesRequest struct {
+func (x *ReadConfigurationRequest) GetFiles() []*ReadConfigurationRequest_File {
+	if x != nil {
+		return x.Files
+	}
+	return nil
+}
+
+type ReadConfigurationReply struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Entries []*AddReplicationEntriesRequest_Entry `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	Values  []*ReadConfigurationReply_Value `protobuf:"bytes,1,rep,name=values,proto3" json:"values,omitempty"`
+	Matches []*ReadConfigurationReply_Match `protobuf:"bytes,2,rep,name=matches,proto3" json:"matches,omitempty"`
 }
 
-func (x *AddReplicationEntriesRequest) Reset() {
-	*x = AddReplicationEntriesRequest{}
+func (x *ReadConfigurationReply) Reset() {
+	*x = ReadConfigurationReply{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_hub_to_agent_proto_msgTypes[34]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1800,13 +2180,13 @@ func (x *AddReplicationEntriesRequest) Reset() {
 	}
 }
 
-func (x *AddReplicationEntriesRequest) String() string {
+func (x *ReadConfigurationReply) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AddReplicationEntriesRequest) ProtoMessage() {}
+func (*ReadConfigurationReply) ProtoMessage() {}
 
-func (x *AddReplicationEntriesRequest) ProtoReflect() protoreflect.Message {
+func (x *ReadConfigurationReply) ProtoReflect() protoreflect.Message {
 	mi := &file_hub_to_agent_proto_msgTypes[34]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1818,26 +2198,35 @@ func (x *AddReplicationEntriesRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AddReplicationEntriesRequest.ProtoReflect.Descriptor instead.
-func (*AddReplicationEntriesRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use ReadConfigurationReply.ProtoReflect.Descriptor instead.
+func (*ReadConfigurationReply) Descriptor() ([]byte, []int) {
 	return file_hub_to_agent_proto_rawDescGZIP(), []int{34}
 }
 
-func (x *AddReplicationEntriesRequest) GetEntries() []*AddReplicationEntriesRequest_Entry {
+func (x *ReadConfigurationReply) GetValues() []*ReadConfigurationReply_Value {
 	if x != nil {
-		return x.Entries
+		return x.Values
 	}
 	return nil
 }
 
-type AddReplicationEntriesReply struct {
+func (x *ReadConfigurationReply) GetMatches() []*ReadConfigurationReply_Match {
+	if x != nil {
+		return x.Matches
+	}
+	return nil
+}
+
+type RenameTablespacesRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	RenamePairs []*RenameTablespacesRequest_RenamePair `protobuf:"bytes,1,rep,name=renamePairs,proto3" json:"renamePairs,omitempty"`
 }
 
-func (x *AddReplicationEntriesReply) Reset() {
-	*x = AddReplicationEntriesReply{}
+func (x *RenameTablespacesRequest) Reset() {
+	*x = RenameTablespacesRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_hub_to_agent_proto_msgTypes[35]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1845,13 +2234,13 @@ func (x *AddReplicationEntriesReply) Reset() {
 	}
 }
 
-func (x *AddReplicationEntriesReply) String() string {
+func (x *RenameTablespacesRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AddReplicationEntriesReply) ProtoMessage() {}
+func (*RenameTablespacesRequest) ProtoMessage() {}
 
-func (x *AddReplicationEntriesReply) ProtoReflect() protoreflect.Message {
+func (x *RenameTablespacesRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_hub_to_agent_proto_msgTypes[35]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1863,24 +2252,66 @@ func (x *AddReplicationEntriesReply) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AddReplicationEntriesReply.ProtoReflect.Descriptor instead.
-func (*AddReplicationEntriesReply) Descriptor() ([]byte, []int) {
+// Deprecated: Use RenameTablespacesRequest.ProtoReflect.Descriptor instead.
+func (*RenameTablespacesRequest) Descriptor() ([]byte, []int) {
 	return file_hub_to_agent_proto_rawDescGZIP(), []int{35}
 }
 
-type CheckDiskSpaceReply_DiskUsage struct {
+func (x *RenameTablespacesRequest) GetRenamePairs() []*RenameTablespacesRequest_RenamePair {
+	if x != nil {
+		return x.RenamePairs
+	}
+	return nil
+}
+
+type RenameTablespacesReply struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+}
 
-	Fs        string `protobuf:"bytes,1,opt,name=fs,proto3" json:"fs,omitempty"`
-	Host      string `protobuf:"bytes,2,opt,name=host,proto3" json:"host,omitempty"`
-	Available uint64 `protobuf:"varint,3,opt,name=available,proto3" json:"available,omitempty"`
-	Required  uint64 `protobuf:"varint,4,opt,name=required,proto3" json:"required,omitempty"`
+func (x *RenameTablespacesReply) Reset() {
+	*x = RenameTablespacesReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_hub_to_agent_proto_msgTypes[36]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
 
-func (x *CheckDiskSpaceReply_DiskUsage) Reset() {
-	*x = CheckDiskSpaceReply_DiskUsage{}
+func (x *RenameTablespacesReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenameTablespacesReply) ProtoMessage() {}
+
+func (x *RenameTablespacesReply) ProtoReflect() protoreflect.Message {
+	mi := &file_hub_to_agent_proto_msgTypes[36]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenameTablespacesReply.ProtoReflect.Descriptor instead.
+func (*RenameTablespacesReply) Descriptor() ([]byte, []int) {
+	return file_hub_to_agent_proto_rawDescGZIP(), []int{36}
+}
+
+type CreateRecoveryConfRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Connections []*CreateRecoveryConfRequest_Connection `protobuf:"bytes,1,rep,name=connections,proto3" json:"connections,omitempty"`
+}
+
+func (x *CreateRecoveryConfRequest) Reset() {
+	*x = CreateRecoveryConfRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_hub_to_agent_proto_msgTypes[37]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1888,13 +2319,13 @@ func (x *CheckDiskSpaceReply_DiskUsage) Reset() {
 	}
 }
 
-func (x *CheckDiskSpaceReply_DiskUsage) String() string {
+func (x *CreateRecoveryConfRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CheckDiskSpaceReply_DiskUsage) ProtoMessage() {}
+func (*CreateRecoveryConfRequest) ProtoMessage() {}
 
-func (x *CheckDiskSpaceReply_DiskUsage) ProtoReflect() protoreflect.Message {
+func (x *CreateRecoveryConfRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_hub_to_agent_proto_msgTypes[37]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1906,68 +2337,81 @@ func (x *CheckDiskSpaceReply_DiskUsage) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CheckDiskSpaceReply_DiskUsage.ProtoReflect.Descriptor instead.
-func (*CheckDiskSpaceReply_DiskUsage) Descriptor() ([]byte, []int) {
-	return file_hub_to_agent_proto_rawDescGZIP(), []int{22, 0}
+// Deprecated: Use CreateRecoveryConfRequest.ProtoReflect.Descriptor instead.
+func (*CreateRecoveryConfRequest) Descriptor() ([]byte, []int) {
+	return file_hub_to_agent_proto_rawDescGZIP(), []int{37}
 }
 
-func (x *CheckDiskSpaceReply_DiskUsage) GetFs() string {
+func (x *CreateRecoveryConfRequest) GetConnections() []*CreateRecoveryConfRequest_Connection {
 	if x != nil {
-		return x.Fs
+		return x.Connections
 	}
-	return ""
+	return nil
 }
 
-func (x *CheckDiskSpaceReply_DiskUsage) GetHost() string {
-	if x != nil {
-		return x.Host
-	}
-	return ""
+type CreateRecoveryConfReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 }
 
-func (x *CheckDiskSpaceReply_DiskUsage) GetAvailable() uint64 {
-	if x != nil {
-		return x.Available
+func (x *CreateRecoveryConfReply) Reset() {
+	*x = CreateRecoveryConfReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_hub_to_agent_proto_msgTypes[38]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
 	}
-	return 0
 }
 
-func (x *CheckDiskSpaceReply_DiskUsage) GetRequired() uint64 {
-	if x != nil {
-		return x.Required
+func (x *CreateRecoveryConfReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateRecoveryConfReply) ProtoMessage() {}
+
+func (x *CreateRecoveryConfReply) ProtoReflect() protoreflect.Message {
+	mi := &file_hub_to_agent_proto_msgTypes[38]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return 0
+	return mi.MessageOf(x)
 }
 
-type RsyncRequest_RsyncOptions struct {
+// Deprecated: Use CreateRecoveryConfReply.ProtoReflect.Descriptor instead.
+func (*CreateRecoveryConfReply) Descriptor() ([]byte, []int) {
+	return file_hub_to_agent_proto_rawDescGZIP(), []int{38}
+}
+
+type AddReplicationEntriesRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Sources         []string `protobuf:"bytes,1,rep,name=sources,proto3" json:"sources,omitempty"`
-	DestinationHost string   `protobuf:"bytes,2,opt,name=destinationHost,proto3" json:"destinationHost,omitempty"`
-	Destination     string   `protobuf:"bytes,3,opt,name=destination,proto3" json:"destination,omitempty"`
-	Options         []string `protobuf:"bytes,4,rep,name=options,proto3" json:"options,omitempty"`
-	ExcludedFiles   []string `protobuf:"bytes,5,rep,name=excludedFiles,proto3" json:"excludedFiles,omitempty"`
+	Entries []*AddReplicationEntriesRequest_Entry `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
 }
 
-func (x *RsyncRequest_RsyncOptions) Reset() {
-	*x = RsyncRequest_RsyncOptions{}
+func (x *AddReplicationEntriesRequest) Reset() {
+	*x = AddReplicationEntriesRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_hub_to_agent_proto_msgTypes[38]
+		mi := &file_hub_to_agent_proto_msgTypes[39]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *RsyncRequest_RsyncOptions) String() string {
+func (x *AddReplicationEntriesRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RsyncRequest_RsyncOptions) ProtoMessage() {}
+func (*AddReplicationEntriesRequest) ProtoMessage() {}
 
-func (x *RsyncRequest_RsyncOptions) ProtoReflect() protoreflect.Message {
-	mi := &file_hub_to_agent_proto_msgTypes[38]
+func (x *AddReplicationEntriesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hub_to_agent_proto_msgTypes[39]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1978,72 +2422,1399 @@ func (x *RsyncRequest_RsyncOptions) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RsyncRequest_RsyncOptions.ProtoReflect.Descriptor instead.
-func (*RsyncRequest_RsyncOptions) Descriptor() ([]byte, []int) {
-	return file_hub_to_agent_proto_rawDescGZIP(), []int{23, 0}
+// Deprecated: Use AddReplicationEntriesRequest.ProtoReflect.Descriptor instead.
+func (*AddReplicationEntriesRequest) Descriptor() ([]byte, []int) {
+	return file_hub_to_agent_proto_rawDescGZIP(), []int{39}
 }
 
-func (x *RsyncRequest_RsyncOptions) GetSources() []string {
+func (x *AddReplicationEntriesRequest) GetEntries() []*AddReplicationEntriesRequest_Entry {
 	if x != nil {
-		return x.Sources
+		return x.Entries
 	}
 	return nil
 }
 
-func (x *RsyncRequest_RsyncOptions) GetDestinationHost() string {
-	if x != nil {
-		return x.DestinationHost
-	}
-	return ""
+type AddReplicationEntriesReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 }
 
-func (x *RsyncRequest_RsyncOptions) GetDestination() string {
-	if x != nil {
-		return x.Destination
+func (x *AddReplicationEntriesReply) Reset() {
+	*x = AddReplicationEntriesReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_hub_to_agent_proto_msgTypes[40]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
 	}
-	return ""
 }
 
-func (x *RsyncRequest_RsyncOptions) GetOptions() []string {
-	if x != nil {
-		return x.Options
+func (x *AddReplicationEntriesReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddReplicationEntriesReply) ProtoMessage() {}
+
+func (x *AddReplicationEntriesReply) ProtoReflect() protoreflect.Message {
+	mi := &file_hub_to_agent_proto_msgTypes[40]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
 }
 
-func (x *RsyncRequest_RsyncOptions) GetExcludedFiles() []string {
+// Deprecated: Use AddReplicationEntriesReply.ProtoReflect.Descriptor instead.
+func (*AddReplicationEntriesReply) Descriptor() ([]byte, []int) {
+	return file_hub_to_agent_proto_rawDescGZIP(), []int{40}
+}
+
+type InventorySegmentsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// parentDirs are searched for data directories one level deep.
+	ParentDirs []string `protobuf:"bytes,1,rep,name=parentDirs,proto3" json:"parentDirs,omitempty"`
+	// recoveryConfFile is the file containing the primary_conninfo of a mirror.
+	RecoveryConfFile string `protobuf:"bytes,2,opt,name=recoveryConfFile,proto3" json:"recoveryConfFile,omitempty"`
+}
+
+func (x *InventorySegmentsRequest) Reset() {
+	*x = InventorySegmentsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_hub_to_agent_proto_msgTypes[41]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *InventorySegmentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InventorySegmentsRequest) ProtoMessage() {}
+
+func (x *InventorySegmentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hub_to_agent_proto_msgTypes[41]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InventorySegmentsRequest.ProtoReflect.Descriptor instead.
+func (*InventorySegmentsRequest) Descriptor() ([]byte, []int) {
+	return file_hub_to_agent_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *InventorySegmentsRequest) GetParentDirs() []string {
+	if x != nil {
+		return x.ParentDirs
+	}
+	return nil
+}
+
+func (x *InventorySegmentsRequest) GetRecoveryConfFile() string {
+	if x != nil {
+		return x.RecoveryConfFile
+	}
+	return ""
+}
+
+type InventorySegmentsReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DataDirectories []*InventorySegmentsReply_DataDirectory `protobuf:"bytes,1,rep,name=dataDirectories,proto3" json:"dataDirectories,omitempty"`
+}
+
+func (x *InventorySegmentsReply) Reset() {
+	*x = InventorySegmentsReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_hub_to_agent_proto_msgTypes[42]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *InventorySegmentsReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InventorySegmentsReply) ProtoMessage() {}
+
+func (x *InventorySegmentsReply) ProtoReflect() protoreflect.Message {
+	mi := &file_hub_to_agent_proto_msgTypes[42]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InventorySegmentsReply.ProtoReflect.Descriptor instead.
+func (*InventorySegmentsReply) Descriptor() ([]byte, []int) {
+	return file_hub_to_agent_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *InventorySegmentsReply) GetDataDirectories() []*InventorySegmentsReply_DataDirectory {
+	if x != nil {
+		return x.DataDirectories
+	}
+	return nil
+}
+
+type ListConfBackupsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// dirs are the data directories whose entries are listed.
+	Dirs []string `protobuf:"bytes,1,rep,name=dirs,proto3" json:"dirs,omitempty"`
+}
+
+func (x *ListConfBackupsRequest) Reset() {
+	*x = ListConfBackupsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_hub_to_agent_proto_msgTypes[43]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListConfBackupsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListConfBackupsRequest) ProtoMessage() {}
+
+func (x *ListConfBackupsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hub_to_agent_proto_msgTypes[43]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListConfBackupsRequest.ProtoReflect.Descriptor instead.
+func (*ListConfBackupsRequest) Descriptor() ([]byte, []int) {
+	return file_hub_to_agent_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *ListConfBackupsRequest) GetDirs() []string {
+	if x != nil {
+		return x.Dirs
+	}
+	return nil
+}
+
+type ListConfBackupsReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Backups []*ListConfBackupsReply_Backup `protobuf:"bytes,1,rep,name=backups,proto3" json:"backups,omitempty"`
+}
+
+func (x *ListConfBackupsReply) Reset() {
+	*x = ListConfBackupsReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_hub_to_agent_proto_msgTypes[44]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListConfBackupsReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListConfBackupsReply) ProtoMessage() {}
+
+func (x *ListConfBackupsReply) ProtoReflect() protoreflect.Message {
+	mi := &file_hub_to_agent_proto_msgTypes[44]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListConfBackupsReply.ProtoReflect.Descriptor instead.
+func (*ListConfBackupsReply) Descriptor() ([]byte, []int) {
+	return file_hub_to_agent_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *ListConfBackupsReply) GetBackups() []*ListConfBackupsReply_Backup {
+	if x != nil {
+		return x.Backups
+	}
+	return nil
+}
+
+type GetCapabilitiesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *GetCapabilitiesRequest) Reset() {
+	*x = GetCapabilitiesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_hub_to_agent_proto_msgTypes[45]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetCapabilitiesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCapabilitiesRequest) ProtoMessage() {}
+
+func (x *GetCapabilitiesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hub_to_agent_proto_msgTypes[45]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCapabilitiesRequest.ProtoReflect.Descriptor instead.
+func (*GetCapabilitiesRequest) Descriptor() ([]byte, []int) {
+	return file_hub_to_agent_proto_rawDescGZIP(), []int{45}
+}
+
+type GetCapabilitiesReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// features are the conf update features the agent supports.
+	Features []string `protobuf:"bytes,1,rep,name=features,proto3" json:"features,omitempty"`
+}
+
+func (x *GetCapabilitiesReply) Reset() {
+	*x = GetCapabilitiesReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_hub_to_agent_proto_msgTypes[46]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetCapabilitiesReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCapabilitiesReply) ProtoMessage() {}
+
+func (x *GetCapabilitiesReply) ProtoReflect() protoreflect.Message {
+	mi := &file_hub_to_agent_proto_msgTypes[46]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCapabilitiesReply.ProtoReflect.Descriptor instead.
+func (*GetCapabilitiesReply) Descriptor() ([]byte, []int) {
+	return file_hub_to_agent_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *GetCapabilitiesReply) GetFeatures() []string {
+	if x != nil {
+		return x.Features
+	}
+	return nil
+}
+
+type SnapshotConfFilesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Paths []string `protobuf:"bytes,1,rep,name=paths,proto3" json:"paths,omitempty"`
+}
+
+func (x *SnapshotConfFilesRequest) Reset() {
+	*x = SnapshotConfFilesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_hub_to_agent_proto_msgTypes[47]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SnapshotConfFilesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SnapshotConfFilesRequest) ProtoMessage() {}
+
+func (x *SnapshotConfFilesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hub_to_agent_proto_msgTypes[47]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SnapshotConfFilesRequest.ProtoReflect.Descriptor instead.
+func (*SnapshotConfFilesRequest) Descriptor() ([]byte, []int) {
+	return file_hub_to_agent_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *SnapshotConfFilesRequest) GetPaths() []string {
+	if x != nil {
+		return x.Paths
+	}
+	return nil
+}
+
+type SnapshotConfFilesReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Files []*SnapshotConfFilesReply_File `protobuf:"bytes,1,rep,name=files,proto3" json:"files,omitempty"`
+}
+
+func (x *SnapshotConfFilesReply) Reset() {
+	*x = SnapshotConfFilesReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_hub_to_agent_proto_msgTypes[48]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SnapshotConfFilesReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SnapshotConfFilesReply) ProtoMessage() {}
+
+func (x *SnapshotConfFilesReply) ProtoReflect() protoreflect.Message {
+	mi := &file_hub_to_agent_proto_msgTypes[48]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SnapshotConfFilesReply.ProtoReflect.Descriptor instead.
+func (*SnapshotConfFilesReply) Descriptor() ([]byte, []int) {
+	return file_hub_to_agent_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *SnapshotConfFilesReply) GetFiles() []*SnapshotConfFilesReply_File {
+	if x != nil {
+		return x.Files
+	}
+	return nil
+}
+
+type ConfUpdateHeartbeatRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ConfUpdateHeartbeatRequest) Reset() {
+	*x = ConfUpdateHeartbeatRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_hub_to_agent_proto_msgTypes[49]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ConfUpdateHeartbeatRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfUpdateHeartbeatRequest) ProtoMessage() {}
+
+func (x *ConfUpdateHeartbeatRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hub_to_agent_proto_msgTypes[49]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfUpdateHeartbeatRequest.ProtoReflect.Descriptor instead.
+func (*ConfUpdateHeartbeatRequest) Descriptor() ([]byte, []int) {
+	return file_hub_to_agent_proto_rawDescGZIP(), []int{49}
+}
+
+type ConfUpdateHeartbeatReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// progress counts the conf edits the agent has made since it started, and
+	// advances while an UpdateConfiguration request is working.
+	Progress      int64 `protobuf:"varint,1,opt,name=progress,proto3" json:"progress,omitempty"`
+	ActiveUpdates int32 `protobuf:"varint,2,opt,name=activeUpdates,proto3" json:"activeUpdates,omitempty"`
+}
+
+func (x *ConfUpdateHeartbeatReply) Reset() {
+	*x = ConfUpdateHeartbeatReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_hub_to_agent_proto_msgTypes[50]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ConfUpdateHeartbeatReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfUpdateHeartbeatReply) ProtoMessage() {}
+
+func (x *ConfUpdateHeartbeatReply) ProtoReflect() protoreflect.Message {
+	mi := &file_hub_to_agent_proto_msgTypes[50]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfUpdateHeartbeatReply.ProtoReflect.Descriptor instead.
+func (*ConfUpdateHeartbeatReply) Descriptor() ([]byte, []int) {
+	return file_hub_to_agent_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *ConfUpdateHeartbeatReply) GetProgress() int64 {
+	if x != nil {
+		return x.Progress
+	}
+	return 0
+}
+
+func (x *ConfUpdateHeartbeatReply) GetActiveUpdates() int32 {
+	if x != nil {
+		return x.ActiveUpdates
+	}
+	return 0
+}
+
+type RestoreConfBackupsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// paths are the conf files to restore from their backups.
+	Paths []string `protobuf:"bytes,1,rep,name=paths,proto3" json:"paths,omitempty"`
+	// tempDir is where the temporary file of each restore is written when on
+	// the same filesystem as the file, empty to write it next to the file.
+	TempDir string `protobuf:"bytes,2,opt,name=tempDir,proto3" json:"tempDir,omitempty"`
+}
+
+func (x *RestoreConfBackupsRequest) Reset() {
+	*x = RestoreConfBackupsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_hub_to_agent_proto_msgTypes[51]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RestoreConfBackupsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreConfBackupsRequest) ProtoMessage() {}
+
+func (x *RestoreConfBackupsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hub_to_agent_proto_msgTypes[51]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreConfBackupsRequest.ProtoReflect.Descriptor instead.
+func (*RestoreConfBackupsRequest) Descriptor() ([]byte, []int) {
+	return file_hub_to_agent_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *RestoreConfBackupsRequest) GetPaths() []string {
+	if x != nil {
+		return x.Paths
+	}
+	return nil
+}
+
+func (x *RestoreConfBackupsRequest) GetTempDir() string {
+	if x != nil {
+		return x.TempDir
+	}
+	return ""
+}
+
+type RestoreConfBackupsReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// restoredPaths are the conf files that had a backup to restore.
+	RestoredPaths []string `protobuf:"bytes,1,rep,name=restoredPaths,proto3" json:"restoredPaths,omitempty"`
+}
+
+func (x *RestoreConfBackupsReply) Reset() {
+	*x = RestoreConfBackupsReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_hub_to_agent_proto_msgTypes[52]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RestoreConfBackupsReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreConfBackupsReply) ProtoMessage() {}
+
+func (x *RestoreConfBackupsReply) ProtoReflect() protoreflect.Message {
+	mi := &file_hub_to_agent_proto_msgTypes[52]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreConfBackupsReply.ProtoReflect.Descriptor instead.
+func (*RestoreConfBackupsReply) Descriptor() ([]byte, []int) {
+	return file_hub_to_agent_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *RestoreConfBackupsReply) GetRestoredPaths() []string {
+	if x != nil {
+		return x.RestoredPaths
+	}
+	return nil
+}
+
+type DeleteConfBackupsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// paths are the conf files whose backups are deleted.
+	Paths []string `protobuf:"bytes,1,rep,name=paths,proto3" json:"paths,omitempty"`
+}
+
+func (x *DeleteConfBackupsRequest) Reset() {
+	*x = DeleteConfBackupsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_hub_to_agent_proto_msgTypes[53]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteConfBackupsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteConfBackupsRequest) ProtoMessage() {}
+
+func (x *DeleteConfBackupsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hub_to_agent_proto_msgTypes[53]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteConfBackupsRequest.ProtoReflect.Descriptor instead.
+func (*DeleteConfBackupsRequest) Descriptor() ([]byte, []int) {
+	return file_hub_to_agent_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *DeleteConfBackupsRequest) GetPaths() []string {
+	if x != nil {
+		return x.Paths
+	}
+	return nil
+}
+
+type DeleteConfBackupsReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// deletedPaths are the backups that were deleted.
+	DeletedPaths []string `protobuf:"bytes,1,rep,name=deletedPaths,proto3" json:"deletedPaths,omitempty"`
+}
+
+func (x *DeleteConfBackupsReply) Reset() {
+	*x = DeleteConfBackupsReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_hub_to_agent_proto_msgTypes[54]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteConfBackupsReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteConfBackupsReply) ProtoMessage() {}
+
+func (x *DeleteConfBackupsReply) ProtoReflect() protoreflect.Message {
+	mi := &file_hub_to_agent_proto_msgTypes[54]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteConfBackupsReply.ProtoReflect.Descriptor instead.
+func (*DeleteConfBackupsReply) Descriptor() ([]byte, []int) {
+	return file_hub_to_agent_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *DeleteConfBackupsReply) GetDeletedPaths() []string {
+	if x != nil {
+		return x.DeletedPaths
+	}
+	return nil
+}
+
+type ReadConfJournalRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ReadConfJournalRequest) Reset() {
+	*x = ReadConfJournalRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_hub_to_agent_proto_msgTypes[55]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReadConfJournalRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadConfJournalRequest) ProtoMessage() {}
+
+func (x *ReadConfJournalRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hub_to_agent_proto_msgTypes[55]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadConfJournalRequest.ProtoReflect.Descriptor instead.
+func (*ReadConfJournalRequest) Descriptor() ([]byte, []int) {
+	return file_hub_to_agent_proto_rawDescGZIP(), []int{55}
+}
+
+type ReadConfJournalReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// records are the lines of JSON of the conf journal of the host, in the
+	// order they were appended.
+	Records []string `protobuf:"bytes,1,rep,name=records,proto3" json:"records,omitempty"`
+}
+
+func (x *ReadConfJournalReply) Reset() {
+	*x = ReadConfJournalReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_hub_to_agent_proto_msgTypes[56]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReadConfJournalReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadConfJournalReply) ProtoMessage() {}
+
+func (x *ReadConfJournalReply) ProtoReflect() protoreflect.Message {
+	mi := &file_hub_to_agent_proto_msgTypes[56]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadConfJournalReply.ProtoReflect.Descriptor instead.
+func (*ReadConfJournalReply) Descriptor() ([]byte, []int) {
+	return file_hub_to_agent_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *ReadConfJournalReply) GetRecords() []string {
+	if x != nil {
+		return x.Records
+	}
+	return nil
+}
+
+type CheckDiskSpaceReply_DiskUsage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Fs        string `protobuf:"bytes,1,opt,name=fs,proto3" json:"fs,omitempty"`
+	Host      string `protobuf:"bytes,2,opt,name=host,proto3" json:"host,omitempty"`
+	Available uint64 `protobuf:"varint,3,opt,name=available,proto3" json:"available,omitempty"`
+	Required  uint64 `protobuf:"varint,4,opt,name=required,proto3" json:"required,omitempty"`
+}
+
+func (x *CheckDiskSpaceReply_DiskUsage) Reset() {
+	*x = CheckDiskSpaceReply_DiskUsage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_hub_to_agent_proto_msgTypes[58]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CheckDiskSpaceReply_DiskUsage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckDiskSpaceReply_DiskUsage) ProtoMessage() {}
+
+func (x *CheckDiskSpaceReply_DiskUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_hub_to_agent_proto_msgTypes[58]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckDiskSpaceReply_DiskUsage.ProtoReflect.Descriptor instead.
+func (*CheckDiskSpaceReply_DiskUsage) Descriptor() ([]byte, []int) {
+	return file_hub_to_agent_proto_rawDescGZIP(), []int{22, 0}
+}
+
+func (x *CheckDiskSpaceReply_DiskUsage) GetFs() string {
+	if x != nil {
+		return x.Fs
+	}
+	return ""
+}
+
+func (x *CheckDiskSpaceReply_DiskUsage) GetHost() string {
+	if x != nil {
+		return x.Host
+	}
+	return ""
+}
+
+func (x *CheckDiskSpaceReply_DiskUsage) GetAvailable() uint64 {
+	if x != nil {
+		return x.Available
+	}
+	return 0
+}
+
+func (x *CheckDiskSpaceReply_DiskUsage) GetRequired() uint64 {
+	if x != nil {
+		return x.Required
+	}
+	return 0
+}
+
+type RsyncRequest_RsyncOptions struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Sources         []string `protobuf:"bytes,1,rep,name=sources,proto3" json:"sources,omitempty"`
+	DestinationHost string   `protobuf:"bytes,2,opt,name=destinationHost,proto3" json:"destinationHost,omitempty"`
+	Destination     string   `protobuf:"bytes,3,opt,name=destination,proto3" json:"destination,omitempty"`
+	Options         []string `protobuf:"bytes,4,rep,name=options,proto3" json:"options,omitempty"`
+	ExcludedFiles   []string `protobuf:"bytes,5,rep,name=excludedFiles,proto3" json:"excludedFiles,omitempty"`
+}
+
+func (x *RsyncRequest_RsyncOptions) Reset() {
+	*x = RsyncRequest_RsyncOptions{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_hub_to_agent_proto_msgTypes[59]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RsyncRequest_RsyncOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RsyncRequest_RsyncOptions) ProtoMessage() {}
+
+func (x *RsyncRequest_RsyncOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_hub_to_agent_proto_msgTypes[59]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RsyncRequest_RsyncOptions.ProtoReflect.Descriptor instead.
+func (*RsyncRequest_RsyncOptions) Descriptor() ([]byte, []int) {
+	return file_hub_to_agent_proto_rawDescGZIP(), []int{23, 0}
+}
+
+func (x *RsyncRequest_RsyncOptions) GetSources() []string {
+	if x != nil {
+		return x.Sources
+	}
+	return nil
+}
+
+func (x *RsyncRequest_RsyncOptions) GetDestinationHost() string {
+	if x != nil {
+		return x.DestinationHost
+	}
+	return ""
+}
+
+func (x *RsyncRequest_RsyncOptions) GetDestination() string {
+	if x != nil {
+		return x.Destination
+	}
+	return ""
+}
+
+func (x *RsyncRequest_RsyncOptions) GetOptions() []string {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+func (x *RsyncRequest_RsyncOptions) GetExcludedFiles() []string {
+	if x != nil {
+		return x.ExcludedFiles
+	}
+	return nil
+}
+
+type ReadConfigurationRequest_File struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Path         string   `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Names        []string `protobuf:"bytes,2,rep,name=names,proto3" json:"names,omitempty"`
+	Searches     []string `protobuf:"bytes,3,rep,name=searches,proto3" json:"searches,omitempty"`          // lines containing any of these are returned as matches
+	SkipIfAbsent bool     `protobuf:"varint,4,opt,name=skipIfAbsent,proto3" json:"skipIfAbsent,omitempty"` // when set a missing file is skipped rather than failing the search
+}
+
+func (x *ReadConfigurationRequest_File) Reset() {
+	*x = ReadConfigurationRequest_File{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_hub_to_agent_proto_msgTypes[61]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReadConfigurationRequest_File) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadConfigurationRequest_File) ProtoMessage() {}
+
+func (x *ReadConfigurationRequest_File) ProtoReflect() protoreflect.Message {
+	mi := &file_hub_to_agent_proto_msgTypes[61]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadConfigurationRequest_File.ProtoReflect.Descriptor instead.
+func (*ReadConfigurationRequest_File) Descriptor() ([]byte, []int) {
+	return file_hub_to_agent_proto_rawDescGZIP(), []int{33, 0}
+}
+
+func (x *ReadConfigurationRequest_File) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *ReadConfigurationRequest_File) GetNames() []string {
+	if x != nil {
+		return x.Names
+	}
+	return nil
+}
+
+func (x *ReadConfigurationRequest_File) GetSearches() []string {
+	if x != nil {
+		return x.Searches
+	}
+	return nil
+}
+
+func (x *ReadConfigurationRequest_File) GetSkipIfAbsent() bool {
+	if x != nil {
+		return x.SkipIfAbsent
+	}
+	return false
+}
+
+type ReadConfigurationReply_Value struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Path  string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Name  string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Value string `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+	Found bool   `protobuf:"varint,4,opt,name=found,proto3" json:"found,omitempty"`
+}
+
+func (x *ReadConfigurationReply_Value) Reset() {
+	*x = ReadConfigurationReply_Value{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_hub_to_agent_proto_msgTypes[62]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReadConfigurationReply_Value) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadConfigurationReply_Value) ProtoMessage() {}
+
+func (x *ReadConfigurationReply_Value) ProtoReflect() protoreflect.Message {
+	mi := &file_hub_to_agent_proto_msgTypes[62]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadConfigurationReply_Value.ProtoReflect.Descriptor instead.
+func (*ReadConfigurationReply_Value) Descriptor() ([]byte, []int) {
+	return file_hub_to_agent_proto_rawDescGZIP(), []int{34, 0}
+}
+
+func (x *ReadConfigurationReply_Value) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *ReadConfigurationReply_Value) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ReadConfigurationReply_Value) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *ReadConfigurationReply_Value) GetFound() bool {
+	if x != nil {
+		return x.Found
+	}
+	return false
+}
+
+type ReadConfigurationReply_Match struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Path       string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	LineNumber int32  `protobuf:"varint,2,opt,name=lineNumber,proto3" json:"lineNumber,omitempty"`
+	Line       string `protobuf:"bytes,3,opt,name=line,proto3" json:"line,omitempty"`
+	Search     string `protobuf:"bytes,4,opt,name=search,proto3" json:"search,omitempty"`
+}
+
+func (x *ReadConfigurationReply_Match) Reset() {
+	*x = ReadConfigurationReply_Match{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_hub_to_agent_proto_msgTypes[63]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReadConfigurationReply_Match) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadConfigurationReply_Match) ProtoMessage() {}
+
+func (x *ReadConfigurationReply_Match) ProtoReflect() protoreflect.Message {
+	mi := &file_hub_to_agent_proto_msgTypes[63]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadConfigurationReply_Match.ProtoReflect.Descriptor instead.
+func (*ReadConfigurationReply_Match) Descriptor() ([]byte, []int) {
+	return file_hub_to_agent_proto_rawDescGZIP(), []int{34, 1}
+}
+
+func (x *ReadConfigurationReply_Match) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *ReadConfigurationReply_Match) GetLineNumber() int32 {
+	if x != nil {
+		return x.LineNumber
+	}
+	return 0
+}
+
+func (x *ReadConfigurationReply_Match) GetLine() string {
+	if x != nil {
+		return x.Line
+	}
+	return ""
+}
+
+func (x *ReadConfigurationReply_Match) GetSearch() string {
+	if x != nil {
+		return x.Search
+	}
+	return ""
+}
+
+type RenameTablespacesRequest_RenamePair struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Source      string `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
+	Destination string `protobuf:"bytes,2,opt,name=destination,proto3" json:"destination,omitempty"`
+}
+
+func (x *RenameTablespacesRequest_RenamePair) Reset() {
+	*x = RenameTablespacesRequest_RenamePair{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_hub_to_agent_proto_msgTypes[64]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RenameTablespacesRequest_RenamePair) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenameTablespacesRequest_RenamePair) ProtoMessage() {}
+
+func (x *RenameTablespacesRequest_RenamePair) ProtoReflect() protoreflect.Message {
+	mi := &file_hub_to_agent_proto_msgTypes[64]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenameTablespacesRequest_RenamePair.ProtoReflect.Descriptor instead.
+func (*RenameTablespacesRequest_RenamePair) Descriptor() ([]byte, []int) {
+	return file_hub_to_agent_proto_rawDescGZIP(), []int{35, 0}
+}
+
+func (x *RenameTablespacesRequest_RenamePair) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *RenameTablespacesRequest_RenamePair) GetDestination() string {
+	if x != nil {
+		return x.Destination
+	}
+	return ""
+}
+
+type CreateRecoveryConfRequest_Connection struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	MirrorDataDir string `protobuf:"bytes,2,opt,name=mirrorDataDir,proto3" json:"mirrorDataDir,omitempty"`
+	User          string `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	PrimaryHost   string `protobuf:"bytes,3,opt,name=primaryHost,proto3" json:"primaryHost,omitempty"`
+	PrimaryPort   int32  `protobuf:"varint,4,opt,name=primaryPort,proto3" json:"primaryPort,omitempty"`
+}
+
+func (x *CreateRecoveryConfRequest_Connection) Reset() {
+	*x = CreateRecoveryConfRequest_Connection{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_hub_to_agent_proto_msgTypes[65]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateRecoveryConfRequest_Connection) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateRecoveryConfRequest_Connection) ProtoMessage() {}
+
+func (x *CreateRecoveryConfRequest_Connection) ProtoReflect() protoreflect.Message {
+	mi := &file_hub_to_agent_proto_msgTypes[65]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateRecoveryConfRequest_Connection.ProtoReflect.Descriptor instead.
+func (*CreateRecoveryConfRequest_Connection) Descriptor() ([]byte, []int) {
+	return file_hub_to_agent_proto_rawDescGZIP(), []int{37, 0}
+}
+
+func (x *CreateRecoveryConfRequest_Connection) GetMirrorDataDir() string {
+	if x != nil {
+		return x.MirrorDataDir
+	}
+	return ""
+}
+
+func (x *CreateRecoveryConfRequest_Connection) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *CreateRecoveryConfRequest_Connection) GetPrimaryHost() string {
+	if x != nil {
+		return x.PrimaryHost
+	}
+	return ""
+}
+
+func (x *CreateRecoveryConfRequest_Connection) GetPrimaryPort() int32 {
+	if x != nil {
+		return x.PrimaryPort
+	}
+	return 0
+}
+
+type AddReplicationEntriesRequest_Entry struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DataDir   string   `protobuf:"bytes,1,opt,name=DataDir,proto3" json:"DataDir,omitempty"`
+	User      string   `protobuf:"bytes,2,opt,name=User,proto3" json:"User,omitempty"`
+	HostAddrs []string `protobuf:"bytes,3,rep,name=HostAddrs,proto3" json:"HostAddrs,omitempty"`
+}
+
+func (x *AddReplicationEntriesRequest_Entry) Reset() {
+	*x = AddReplicationEntriesRequest_Entry{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_hub_to_agent_proto_msgTypes[66]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AddReplicationEntriesRequest_Entry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddReplicationEntriesRequest_Entry) ProtoMessage() {}
+
+func (x *AddReplicationEntriesRequest_Entry) ProtoReflect() protoreflect.Message {
+	mi := &file_hub_to_agent_proto_msgTypes[66]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddReplicationEntriesRequest_Entry.ProtoReflect.Descriptor instead.
+func (*AddReplicationEntriesRequest_Entry) Descriptor() ([]byte, []int) {
+	return file_hub_to_agent_proto_rawDescGZIP(), []int{39, 0}
+}
+
+func (x *AddReplicationEntriesRequest_Entry) GetDataDir() string {
+	if x != nil {
+		return x.DataDir
+	}
+	return ""
+}
+
+func (x *AddReplicationEntriesRequest_Entry) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *AddReplicationEntriesRequest_Entry) GetHostAddrs() []string {
 	if x != nil {
-		return x.ExcludedFiles
+		return x.HostAddrs
 	}
 	return nil
 }
 
-type RenameTablespacesRequest_RenamePair struct {
+type InventorySegmentsReply_DataDirectory struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Source      string `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
-	Destination string `protobuf:"bytes,2,opt,name=destination,proto3" json:"destination,omitempty"`
+	DataDir         string `protobuf:"bytes,1,opt,name=dataDir,proto3" json:"dataDir,omitempty"`
+	Port            int32  `protobuf:"varint,2,opt,name=port,proto3" json:"port,omitempty"`
+	Dbid            int32  `protobuf:"varint,3,opt,name=dbid,proto3" json:"dbid,omitempty"`
+	PrimaryConninfo string `protobuf:"bytes,4,opt,name=primaryConninfo,proto3" json:"primaryConninfo,omitempty"` // empty for a primary
 }
 
-func (x *RenameTablespacesRequest_RenamePair) Reset() {
-	*x = RenameTablespacesRequest_RenamePair{}
+func (x *InventorySegmentsReply_DataDirectory) Reset() {
+	*x = InventorySegmentsReply_DataDirectory{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_hub_to_agent_proto_msgTypes[39]
+		mi := &file_hub_to_agent_proto_msgTypes[67]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *RenameTablespacesRequest_RenamePair) String() string {
+func (x *InventorySegmentsReply_DataDirectory) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RenameTablespacesRequest_RenamePair) ProtoMessage() {}
+func (*InventorySegmentsReply_DataDirectory) ProtoMessage() {}
 
-func (x *RenameTablespacesRequest_RenamePair) ProtoReflect() protoreflect.Message {
-	mi := &file_hub_to_agent_proto_msgTypes[39]
+func (x *InventorySegmentsReply_DataDirectory) ProtoReflect() protoreflect.Message {
+	mi := &file_hub_to_agent_proto_msgTypes[67]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2054,53 +3825,67 @@ func (x *RenameTablespacesRequest_RenamePair) ProtoReflect() protoreflect.Messag
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RenameTablespacesRequest_RenamePair.ProtoReflect.Descriptor instead.
-func (*RenameTablespacesRequest_RenamePair) Descriptor() ([]byte, []int) {
-	return file_hub_to_agent_proto_rawDescGZIP(), []int{30, 0}
+// Deprecated: Use InventorySegmentsReply_DataDirectory.ProtoReflect.Descriptor instead.
+func (*InventorySegmentsReply_DataDirectory) Descriptor() ([]byte, []int) {
+	return file_hub_to_agent_proto_rawDescGZIP(), []int{42, 0}
 }
 
-func (x *RenameTablespacesRequest_RenamePair) GetSource() string {
+func (x *InventorySegmentsReply_DataDirectory) GetDataDir() string {
 	if x != nil {
-		return x.Source
+		return x.DataDir
 	}
 	return ""
 }
 
-func (x *RenameTablespacesRequest_RenamePair) GetDestination() string {
+func (x *InventorySegmentsReply_DataDirectory) GetPort() int32 {
 	if x != nil {
-		return x.Destination
+		return x.Port
+	}
+	return 0
+}
+
+func (x *InventorySegmentsReply_DataDirectory) GetDbid() int32 {
+	if x != nil {
+		return x.Dbid
+	}
+	return 0
+}
+
+func (x *InventorySegmentsReply_DataDirectory) GetPrimaryConninfo() string {
+	if x != nil {
+		return x.PrimaryConninfo
 	}
 	return ""
 }
 
-type CreateRecoveryConfRequest_Connection struct {
+type ListConfBackupsReply_Backup struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	MirrorDataDir string `protobuf:"bytes,2,opt,name=mirrorDataDir,proto3" json:"mirrorDataDir,omitempty"`
-	User          string `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
-	PrimaryHost   string `protobuf:"bytes,3,opt,name=primaryHost,proto3" json:"primaryHost,omitempty"`
-	PrimaryPort   int32  `protobuf:"varint,4,opt,name=primaryPort,proto3" json:"primaryPort,omitempty"`
+	Path         string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"` // the backup file
+	OriginalPath string `protobuf:"bytes,3,opt,name=originalPath,proto3" json:"originalPath,omitempty"`
+	Timestamp    int64  `protobuf:"varint,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"` // unix seconds
+	Checksum     string `protobuf:"bytes,6,opt,name=checksum,proto3" json:"checksum,omitempty"`    // sha256 of the backup contents
 }
 
-func (x *CreateRecoveryConfRequest_Connection) Reset() {
-	*x = CreateRecoveryConfRequest_Connection{}
+func (x *ListConfBackupsReply_Backup) Reset() {
+	*x = ListConfBackupsReply_Backup{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_hub_to_agent_proto_msgTypes[40]
+		mi := &file_hub_to_agent_proto_msgTypes[68]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *CreateRecoveryConfRequest_Connection) String() string {
+func (x *ListConfBackupsReply_Backup) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateRecoveryConfRequest_Connection) ProtoMessage() {}
+func (*ListConfBackupsReply_Backup) ProtoMessage() {}
 
-func (x *CreateRecoveryConfRequest_Connection) ProtoReflect() protoreflect.Message {
-	mi := &file_hub_to_agent_proto_msgTypes[40]
+func (x *ListConfBackupsReply_Backup) ProtoReflect() protoreflect.Message {
+	mi := &file_hub_to_agent_proto_msgTypes[68]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2111,66 +3896,67 @@ func (x *CreateRecoveryConfRequest_Connection) ProtoReflect() protoreflect.Messa
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateRecoveryConfRequest_Connection.ProtoReflect.Descriptor instead.
-func (*CreateRecoveryConfRequest_Connection) Descriptor() ([]byte, []int) {
-	return file_hub_to_agent_proto_rawDescGZIP(), []int{32, 0}
+// Deprecated: Use ListConfBackupsReply_Backup.ProtoReflect.Descriptor instead.
+func (*ListConfBackupsReply_Backup) Descriptor() ([]byte, []int) {
+	return file_hub_to_agent_proto_rawDescGZIP(), []int{44, 0}
 }
 
-func (x *CreateRecoveryConfRequest_Connection) GetMirrorDataDir() string {
+func (x *ListConfBackupsReply_Backup) GetPath() string {
 	if x != nil {
-		return x.MirrorDataDir
+		return x.Path
 	}
 	return ""
 }
 
-func (x *CreateRecoveryConfRequest_Connection) GetUser() string {
+func (x *ListConfBackupsReply_Backup) GetOriginalPath() string {
 	if x != nil {
-		return x.User
+		return x.OriginalPath
 	}
 	return ""
 }
 
-func (x *CreateRecoveryConfRequest_Connection) GetPrimaryHost() string {
+func (x *ListConfBackupsReply_Backup) GetTimestamp() int64 {
 	if x != nil {
-		return x.PrimaryHost
+		return x.Timestamp
 	}
-	return ""
+	return 0
 }
 
-func (x *CreateRecoveryConfRequest_Connection) GetPrimaryPort() int32 {
+func (x *ListConfBackupsReply_Backup) GetChecksum() string {
 	if x != nil {
-		return x.PrimaryPort
+		return x.Checksum
 	}
-	return 0
+	return ""
 }
 
-type AddReplicationEntriesRequest_Entry struct {
+type SnapshotConfFilesReply_File struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	DataDir   string   `protobuf:"bytes,1,opt,name=DataDir,proto3" json:"DataDir,omitempty"`
-	User      string   `protobuf:"bytes,2,opt,name=User,proto3" json:"User,omitempty"`
-	HostAddrs []string `protobuf:"bytes,3,rep,name=HostAddrs,proto3" json:"HostAddrs,omitempty"`
+	Path     string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Found    bool   `protobuf:"varint,2,opt,name=found,proto3" json:"found,omitempty"` // false when the file does not exist
+	Contents []byte `protobuf:"bytes,3,opt,name=contents,proto3" json:"contents,omitempty"`
+	Mode     uint32 `protobuf:"varint,4,opt,name=mode,proto3" json:"mode,omitempty"`
 }
 
-func (x *AddReplicationEntriesRequest_Entry) Reset() {
-	*x = AddReplicationEntriesRequest_Entry{}
+func (x *SnapshotConfFilesReply_File) Reset() {
+	*x = SnapshotConfFilesReply_File{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_hub_to_agent_proto_msgTypes[41]
+		mi := &file_hub_to_agent_proto_msgTypes[69]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *AddReplicationEntriesRequest_Entry) String() string {
+func (x *SnapshotConfFilesReply_File) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AddReplicationEntriesRequest_Entry) ProtoMessage() {}
+func (*SnapshotConfFilesReply_File) ProtoMessage() {}
 
-func (x *AddReplicationEntriesRequest_Entry) ProtoReflect() protoreflect.Message {
-	mi := &file_hub_to_agent_proto_msgTypes[41]
+func (x *SnapshotConfFilesReply_File) ProtoReflect() protoreflect.Message {
+	mi := &file_hub_to_agent_proto_msgTypes[69]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2181,32 +3967,39 @@ func (x *AddReplicationEntriesRequest_Entry) ProtoReflect() protoreflect.Message
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AddReplicationEntriesRequest_Entry.ProtoReflect.Descriptor instead.
-func (*AddReplicationEntriesRequest_Entry) Descriptor() ([]byte, []int) {
-	return file_hub_to_agent_proto_rawDescGZIP(), []int{34, 0}
+// Deprecated: Use SnapshotConfFilesReply_File.ProtoReflect.Descriptor instead.
+func (*SnapshotConfFilesReply_File) Descriptor() ([]byte, []int) {
+	return file_hub_to_agent_proto_rawDescGZIP(), []int{48, 0}
 }
 
-func (x *AddReplicationEntriesRequest_Entry) GetDataDir() string {
+func (x *SnapshotConfFilesReply_File) GetPath() string {
 	if x != nil {
-		return x.DataDir
+		return x.Path
 	}
 	return ""
 }
 
-func (x *AddReplicationEntriesRequest_Entry) GetUser() string {
+func (x *SnapshotConfFilesReply_File) GetFound() bool {
 	if x != nil {
-		return x.User
+		return x.Found
 	}
-	return ""
+	return false
 }
 
-func (x *AddReplicationEntriesRequest_Entry) GetHostAddrs() []string {
+func (x *SnapshotConfFilesReply_File) GetContents() []byte {
 	if x != nil {
-		return x.HostAddrs
+		return x.Contents
 	}
 	return nil
 }
 
+func (x *SnapshotConfFilesReply_File) GetMode() uint32 {
+	if x != nil {
+		return x.Mode
+	}
+	return 0
+}
+
 var File_hub_to_agent_proto protoreflect.FileDescriptor
 
 var file_hub_to_agent_proto_rawDesc = []byte{
@@ -2372,156 +4165,425 @@ var file_hub_to_agent_proto_rawDesc = []byte{
 	0x74, 0x61, 0x64, 0x69, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x64, 0x61,
 	0x74, 0x61, 0x64, 0x69, 0x72, 0x73, 0x22, 0x17, 0x0a, 0x15, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72,
 	0x65, 0x50, 0x67, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22,
-	0x67, 0x0a, 0x15, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x46, 0x69, 0x6c, 0x65, 0x43, 0x6f, 0x6e,
-	0x66, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x18, 0x0a, 0x07,
-	0x70, 0x61, 0x74, 0x74, 0x65, 0x72, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x70,
-	0x61, 0x74, 0x74, 0x65, 0x72, 0x6e, 0x12, 0x20, 0x0a, 0x0b, 0x72, 0x65, 0x70, 0x6c, 0x61, 0x63,
-	0x65, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x72, 0x65, 0x70,
-	0x6c, 0x61, 0x63, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x22, 0x52, 0x0a, 0x1a, 0x55, 0x70, 0x64, 0x61,
-	0x74, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x34, 0x0a, 0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e,
-	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x55, 0x70,
-	0x64, 0x61, 0x74, 0x65, 0x46, 0x69, 0x6c, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x4f, 0x70, 0x74, 0x69,
-	0x6f, 0x6e, 0x73, 0x52, 0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0x1a, 0x0a, 0x18,
+	0xa3, 0x06, 0x0a, 0x15, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x46, 0x69, 0x6c, 0x65, 0x43, 0x6f,
+	0x6e, 0x66, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74,
+	0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x18, 0x0a,
+	0x07, 0x70, 0x61, 0x74, 0x74, 0x65, 0x72, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
+	0x70, 0x61, 0x74, 0x74, 0x65, 0x72, 0x6e, 0x12, 0x20, 0x0a, 0x0b, 0x72, 0x65, 0x70, 0x6c, 0x61,
+	0x63, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x72, 0x65,
+	0x70, 0x6c, 0x61, 0x63, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x61,
+	0x73, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f,
+	0x6e, 0x12, 0x10, 0x0a, 0x03, 0x67, 0x75, 0x63, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03,
+	0x67, 0x75, 0x63, 0x12, 0x28, 0x0a, 0x0f, 0x65, 0x78, 0x70, 0x65, 0x63, 0x74, 0x65, 0x64, 0x4d,
+	0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0f, 0x65, 0x78,
+	0x70, 0x65, 0x63, 0x74, 0x65, 0x64, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x12, 0x38, 0x0a,
+	0x17, 0x70, 0x72, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x54, 0x72, 0x61, 0x69, 0x6c, 0x69, 0x6e,
+	0x67, 0x4e, 0x65, 0x77, 0x6c, 0x69, 0x6e, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x17,
+	0x70, 0x72, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x54, 0x72, 0x61, 0x69, 0x6c, 0x69, 0x6e, 0x67,
+	0x4e, 0x65, 0x77, 0x6c, 0x69, 0x6e, 0x65, 0x12, 0x2c, 0x0a, 0x11, 0x6d, 0x61, 0x74, 0x63, 0x68,
+	0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x08, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x11, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74,
+	0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x24, 0x0a, 0x0d, 0x65, 0x78, 0x70, 0x65, 0x63, 0x74, 0x65,
+	0x64, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x65, 0x78,
+	0x70, 0x65, 0x63, 0x74, 0x65, 0x64, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x22, 0x0a, 0x0c, 0x61,
+	0x6c, 0x6c, 0x6f, 0x77, 0x4e, 0x6f, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x18, 0x0a, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x0c, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x4e, 0x6f, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x12,
+	0x22, 0x0a, 0x0c, 0x62, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x53, 0x75, 0x66, 0x66, 0x69, 0x78, 0x18,
+	0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x62, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x53, 0x75, 0x66,
+	0x66, 0x69, 0x78, 0x12, 0x28, 0x0a, 0x0f, 0x61, 0x70, 0x70, 0x65, 0x6e, 0x64, 0x49, 0x66, 0x4d,
+	0x69, 0x73, 0x73, 0x69, 0x6e, 0x67, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x61, 0x70,
+	0x70, 0x65, 0x6e, 0x64, 0x49, 0x66, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6e, 0x67, 0x12, 0x22, 0x0a,
+	0x0c, 0x73, 0x6b, 0x69, 0x70, 0x49, 0x66, 0x41, 0x62, 0x73, 0x65, 0x6e, 0x74, 0x18, 0x0d, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x0c, 0x73, 0x6b, 0x69, 0x70, 0x49, 0x66, 0x41, 0x62, 0x73, 0x65, 0x6e,
+	0x74, 0x12, 0x57, 0x0a, 0x10, 0x64, 0x75, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x65, 0x4d, 0x61,
+	0x74, 0x63, 0x68, 0x65, 0x73, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x2b, 0x2e, 0x69, 0x64,
+	0x6c, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x46, 0x69, 0x6c, 0x65, 0x43, 0x6f, 0x6e, 0x66,
+	0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x44, 0x75, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74,
+	0x65, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x52, 0x10, 0x64, 0x75, 0x70, 0x6c, 0x69, 0x63,
+	0x61, 0x74, 0x65, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x64, 0x61,
+	0x74, 0x61, 0x44, 0x69, 0x72, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x64, 0x61, 0x74,
+	0x61, 0x44, 0x69, 0x72, 0x12, 0x1c, 0x0a, 0x09, 0x62, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x44, 0x69,
+	0x72, 0x18, 0x10, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x62, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x44,
+	0x69, 0x72, 0x12, 0x22, 0x0a, 0x0c, 0x62, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x50, 0x6f, 0x6c, 0x69,
+	0x63, 0x79, 0x18, 0x11, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x62, 0x61, 0x63, 0x6b, 0x75, 0x70,
+	0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x74, 0x65, 0x6d, 0x70, 0x44, 0x69,
+	0x72, 0x18, 0x12, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x74, 0x65, 0x6d, 0x70, 0x44, 0x69, 0x72,
+	0x22, 0x73, 0x0a, 0x10, 0x44, 0x75, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x65, 0x4d, 0x61, 0x74,
+	0x63, 0x68, 0x65, 0x73, 0x12, 0x15, 0x0a, 0x11, 0x72, 0x65, 0x77, 0x72, 0x69, 0x74, 0x65, 0x41,
+	0x6c, 0x6c, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x10, 0x00, 0x12, 0x18, 0x0a, 0x14, 0x77,
+	0x61, 0x72, 0x6e, 0x44, 0x75, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x65, 0x4d, 0x61, 0x74, 0x63,
+	0x68, 0x65, 0x73, 0x10, 0x01, 0x12, 0x18, 0x0a, 0x14, 0x66, 0x61, 0x69, 0x6c, 0x44, 0x75, 0x70,
+	0x6c, 0x69, 0x63, 0x61, 0x74, 0x65, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x10, 0x02, 0x12,
+	0x14, 0x0a, 0x10, 0x72, 0x65, 0x77, 0x72, 0x69, 0x74, 0x65, 0x4c, 0x61, 0x73, 0x74, 0x4d, 0x61,
+	0x74, 0x63, 0x68, 0x10, 0x03, 0x22, 0xe4, 0x01, 0x0a, 0x1a, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65,
+	0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x34, 0x0a, 0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x55, 0x70, 0x64, 0x61,
+	0x74, 0x65, 0x46, 0x69, 0x6c, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e,
+	0x73, 0x52, 0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x2a, 0x0a, 0x10, 0x76, 0x65,
+	0x72, 0x69, 0x66, 0x79, 0x41, 0x66, 0x74, 0x65, 0x72, 0x57, 0x72, 0x69, 0x74, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x10, 0x76, 0x65, 0x72, 0x69, 0x66, 0x79, 0x41, 0x66, 0x74, 0x65,
+	0x72, 0x57, 0x72, 0x69, 0x74, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x72, 0x79, 0x52, 0x75, 0x6e,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x64, 0x72, 0x79, 0x52, 0x75, 0x6e, 0x12, 0x22,
+	0x0a, 0x0c, 0x64, 0x65, 0x62, 0x75, 0x67, 0x4c, 0x6f, 0x67, 0x67, 0x69, 0x6e, 0x67, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x64, 0x65, 0x62, 0x75, 0x67, 0x4c, 0x6f, 0x67, 0x67, 0x69,
+	0x6e, 0x67, 0x12, 0x28, 0x0a, 0x0f, 0x66, 0x69, 0x6c, 0x65, 0x43, 0x6f, 0x6e, 0x63, 0x75, 0x72,
+	0x72, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0f, 0x66, 0x69, 0x6c,
+	0x65, 0x43, 0x6f, 0x6e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x22, 0x36, 0x0a, 0x0c,
+	0x43, 0x6f, 0x6e, 0x66, 0x46, 0x69, 0x6c, 0x65, 0x44, 0x69, 0x66, 0x66, 0x12, 0x12, 0x0a, 0x04,
+	0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68,
+	0x12, 0x12, 0x0a, 0x04, 0x64, 0x69, 0x66, 0x66, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x64, 0x69, 0x66, 0x66, 0x22, 0xa5, 0x04, 0x0a, 0x18, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x43,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x70, 0x6c,
+	0x79, 0x12, 0x22, 0x0a, 0x0c, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x50, 0x61, 0x74, 0x68,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0c, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64,
+	0x50, 0x61, 0x74, 0x68, 0x73, 0x12, 0x39, 0x0a, 0x06, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x18,
+	0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x52, 0x65, 0x61, 0x64,
+	0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x70,
+	0x6c, 0x79, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x06, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73,
+	0x12, 0x18, 0x0a, 0x07, 0x73, 0x6b, 0x69, 0x70, 0x70, 0x65, 0x64, 0x18, 0x03, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x07, 0x73, 0x6b, 0x69, 0x70, 0x70, 0x65, 0x64, 0x12, 0x28, 0x0a, 0x0f, 0x72, 0x6f,
+	0x6c, 0x6c, 0x65, 0x64, 0x42, 0x61, 0x63, 0x6b, 0x50, 0x61, 0x74, 0x68, 0x73, 0x18, 0x04, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x0f, 0x72, 0x6f, 0x6c, 0x6c, 0x65, 0x64, 0x42, 0x61, 0x63, 0x6b, 0x50,
+	0x61, 0x74, 0x68, 0x73, 0x12, 0x27, 0x0a, 0x05, 0x64, 0x69, 0x66, 0x66, 0x73, 0x18, 0x05, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x46, 0x69,
+	0x6c, 0x65, 0x44, 0x69, 0x66, 0x66, 0x52, 0x05, 0x64, 0x69, 0x66, 0x66, 0x73, 0x12, 0x34, 0x0a,
+	0x0c, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x4c, 0x69, 0x6e, 0x65, 0x73, 0x18, 0x06, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65,
+	0x64, 0x4c, 0x69, 0x6e, 0x65, 0x52, 0x0c, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x4c, 0x69,
+	0x6e, 0x65, 0x73, 0x12, 0x33, 0x0a, 0x09, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x75, 0x6d, 0x73,
+	0x18, 0x07, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x43, 0x6f, 0x6e,
+	0x66, 0x46, 0x69, 0x6c, 0x65, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x75, 0x6d, 0x52, 0x09, 0x63,
+	0x68, 0x65, 0x63, 0x6b, 0x73, 0x75, 0x6d, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x77, 0x61, 0x72, 0x6e,
+	0x69, 0x6e, 0x67, 0x73, 0x18, 0x08, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x77, 0x61, 0x72, 0x6e,
+	0x69, 0x6e, 0x67, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x61, 0x74, 0x69, 0x73, 0x66, 0x69, 0x65,
+	0x64, 0x18, 0x09, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x73, 0x61, 0x74, 0x69, 0x73, 0x66, 0x69,
+	0x65, 0x64, 0x12, 0x56, 0x0a, 0x0d, 0x72, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x64, 0x50, 0x61,
+	0x74, 0x68, 0x73, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x30, 0x2e, 0x69, 0x64, 0x6c, 0x2e,
 	0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74,
-	0x69, 0x6f, 0x6e, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0xae, 0x01, 0x0a, 0x18, 0x52, 0x65, 0x6e,
-	0x61, 0x6d, 0x65, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x73, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x4a, 0x0a, 0x0b, 0x72, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x50,
-	0x61, 0x69, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x28, 0x2e, 0x69, 0x64, 0x6c,
-	0x2e, 0x52, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x73, 0x70, 0x61, 0x63,
-	0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x52, 0x65, 0x6e, 0x61, 0x6d, 0x65,
-	0x50, 0x61, 0x69, 0x72, 0x52, 0x0b, 0x72, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x50, 0x61, 0x69, 0x72,
-	0x73, 0x1a, 0x46, 0x0a, 0x0a, 0x52, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x50, 0x61, 0x69, 0x72, 0x12,
-	0x16, 0x0a, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x74, 0x69,
-	0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65,
-	0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x18, 0x0a, 0x16, 0x52, 0x65, 0x6e,
-	0x61, 0x6d, 0x65, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x73, 0x52, 0x65,
-	0x70, 0x6c, 0x79, 0x22, 0xf5, 0x01, 0x0a, 0x19, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x52, 0x65,
-	0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x43, 0x6f, 0x6e, 0x66, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x12, 0x4b, 0x0a, 0x0b, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73,
-	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x29, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x43, 0x72, 0x65,
-	0x61, 0x74, 0x65, 0x52, 0x65, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x43, 0x6f, 0x6e, 0x66, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f,
-	0x6e, 0x52, 0x0b, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x1a, 0x8a,
-	0x01, 0x0a, 0x0a, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x24, 0x0a,
-	0x0d, 0x6d, 0x69, 0x72, 0x72, 0x6f, 0x72, 0x44, 0x61, 0x74, 0x61, 0x44, 0x69, 0x72, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x6d, 0x69, 0x72, 0x72, 0x6f, 0x72, 0x44, 0x61, 0x74, 0x61,
-	0x44, 0x69, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x12, 0x20, 0x0a, 0x0b, 0x70, 0x72, 0x69, 0x6d, 0x61,
-	0x72, 0x79, 0x48, 0x6f, 0x73, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x70, 0x72,
-	0x69, 0x6d, 0x61, 0x72, 0x79, 0x48, 0x6f, 0x73, 0x74, 0x12, 0x20, 0x0a, 0x0b, 0x70, 0x72, 0x69,
-	0x6d, 0x61, 0x72, 0x79, 0x50, 0x6f, 0x72, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0b,
-	0x70, 0x72, 0x69, 0x6d, 0x61, 0x72, 0x79, 0x50, 0x6f, 0x72, 0x74, 0x22, 0x19, 0x0a, 0x17, 0x43,
-	0x72, 0x65, 0x61, 0x74, 0x65, 0x52, 0x65, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x43, 0x6f, 0x6e,
-	0x66, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0xb6, 0x01, 0x0a, 0x1c, 0x41, 0x64, 0x64, 0x52, 0x65,
-	0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x45, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x41, 0x0a, 0x07, 0x65, 0x6e, 0x74, 0x72, 0x69,
-	0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x27, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x41,
-	0x64, 0x64, 0x52, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x45, 0x6e, 0x74,
-	0x72, 0x69, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x45, 0x6e, 0x74, 0x72,
-	0x79, 0x52, 0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x1a, 0x53, 0x0a, 0x05, 0x45, 0x6e,
-	0x74, 0x72, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x44, 0x61, 0x74, 0x61, 0x44, 0x69, 0x72, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x44, 0x61, 0x74, 0x61, 0x44, 0x69, 0x72, 0x12, 0x12, 0x0a,
-	0x04, 0x55, 0x73, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x55, 0x73, 0x65,
-	0x72, 0x12, 0x1c, 0x0a, 0x09, 0x48, 0x6f, 0x73, 0x74, 0x41, 0x64, 0x64, 0x72, 0x73, 0x18, 0x03,
-	0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x48, 0x6f, 0x73, 0x74, 0x41, 0x64, 0x64, 0x72, 0x73, 0x22,
-	0x1c, 0x0a, 0x1a, 0x41, 0x64, 0x64, 0x52, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f,
-	0x6e, 0x45, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x32, 0x9f, 0x0b,
-	0x0a, 0x05, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x12, 0x5d, 0x0a, 0x15, 0x43, 0x72, 0x65, 0x61, 0x74,
-	0x65, 0x42, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79,
-	0x12, 0x21, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x42, 0x61, 0x63,
-	0x6b, 0x75, 0x70, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65,
-	0x42, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x52,
-	0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x4f, 0x0a, 0x0e, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x44,
-	0x69, 0x73, 0x6b, 0x53, 0x70, 0x61, 0x63, 0x65, 0x12, 0x21, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x43,
-	0x68, 0x65, 0x63, 0x6b, 0x53, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x44, 0x69, 0x73, 0x6b, 0x53,
-	0x70, 0x61, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x69, 0x64,
-	0x6c, 0x2e, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x44, 0x69, 0x73, 0x6b, 0x53, 0x70, 0x61, 0x63, 0x65,
-	0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x4e, 0x0a, 0x10, 0x55, 0x70, 0x67, 0x72, 0x61,
-	0x64, 0x65, 0x50, 0x72, 0x69, 0x6d, 0x61, 0x72, 0x69, 0x65, 0x73, 0x12, 0x1c, 0x2e, 0x69, 0x64,
-	0x6c, 0x2e, 0x55, 0x70, 0x67, 0x72, 0x61, 0x64, 0x65, 0x50, 0x72, 0x69, 0x6d, 0x61, 0x72, 0x69,
-	0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x69, 0x64, 0x6c, 0x2e,
-	0x55, 0x70, 0x67, 0x72, 0x61, 0x64, 0x65, 0x50, 0x72, 0x69, 0x6d, 0x61, 0x72, 0x69, 0x65, 0x73,
-	0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x51, 0x0a, 0x11, 0x52, 0x65, 0x6e, 0x61, 0x6d,
-	0x65, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x69, 0x65, 0x73, 0x12, 0x1d, 0x2e, 0x69,
-	0x64, 0x6c, 0x2e, 0x52, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f,
-	0x72, 0x69, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x69, 0x64,
-	0x6c, 0x2e, 0x52, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72,
-	0x69, 0x65, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x39, 0x0a, 0x09, 0x53, 0x74,
-	0x6f, 0x70, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x12, 0x15, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x53, 0x74,
-	0x6f, 0x70, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13,
-	0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x53, 0x74, 0x6f, 0x70, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x52, 0x65,
-	0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x5d, 0x0a, 0x15, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x44,
-	0x61, 0x74, 0x61, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x69, 0x65, 0x73, 0x12, 0x21,
-	0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x44, 0x61, 0x74, 0x61, 0x44,
-	0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x69, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x1a, 0x1f, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x44, 0x61,
-	0x74, 0x61, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x69, 0x65, 0x73, 0x52, 0x65, 0x70,
-	0x6c, 0x79, 0x22, 0x00, 0x12, 0x5d, 0x0a, 0x15, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x42, 0x61,
-	0x63, 0x6b, 0x75, 0x70, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x21, 0x2e,
-	0x69, 0x64, 0x6c, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x42, 0x61, 0x63, 0x6b, 0x75, 0x70,
-	0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x1a, 0x1f, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x42, 0x61, 0x63,
-	0x6b, 0x75, 0x70, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x70, 0x6c,
-	0x79, 0x22, 0x00, 0x12, 0x5a, 0x0a, 0x14, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x53, 0x74, 0x61,
-	0x74, 0x65, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x20, 0x2e, 0x69, 0x64,
-	0x6c, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x44, 0x69, 0x72,
-	0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e,
-	0x69, 0x64, 0x6c, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x44,
+	0x69, 0x6f, 0x6e, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x2e, 0x52, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65,
+	0x64, 0x50, 0x61, 0x74, 0x68, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0d, 0x72, 0x65, 0x73,
+	0x6f, 0x6c, 0x76, 0x65, 0x64, 0x50, 0x61, 0x74, 0x68, 0x73, 0x1a, 0x40, 0x0a, 0x12, 0x52, 0x65,
+	0x73, 0x6f, 0x6c, 0x76, 0x65, 0x64, 0x50, 0x61, 0x74, 0x68, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b,
+	0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x66, 0x0a, 0x10,
+	0x43, 0x6f, 0x6e, 0x66, 0x46, 0x69, 0x6c, 0x65, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x75, 0x6d,
+	0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x70, 0x61, 0x74, 0x68, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x68, 0x61, 0x32, 0x35, 0x36, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x68, 0x61, 0x32, 0x35, 0x36, 0x12, 0x26, 0x0a, 0x0e,
+	0x6d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x0e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x64, 0x4f, 0x70, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x22, 0x6d, 0x0a, 0x0b, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x4c,
+	0x69, 0x6e, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x06, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x70,
+	0x61, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12,
+	0x18, 0x0a, 0x07, 0x6f, 0x6c, 0x64, 0x4c, 0x69, 0x6e, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x07, 0x6f, 0x6c, 0x64, 0x4c, 0x69, 0x6e, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x6e, 0x65, 0x77,
+	0x4c, 0x69, 0x6e, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6e, 0x65, 0x77, 0x4c,
+	0x69, 0x6e, 0x65, 0x22, 0xc6, 0x01, 0x0a, 0x18, 0x52, 0x65, 0x61, 0x64, 0x43, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x38, 0x0a, 0x05, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x22, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x52, 0x65, 0x61, 0x64, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67,
+	0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x46,
+	0x69, 0x6c, 0x65, 0x52, 0x05, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x1a, 0x70, 0x0a, 0x04, 0x46, 0x69,
+	0x6c, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x14, 0x0a, 0x05, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x18,
+	0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x12, 0x1a, 0x0a, 0x08,
+	0x73, 0x65, 0x61, 0x72, 0x63, 0x68, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08,
+	0x73, 0x65, 0x61, 0x72, 0x63, 0x68, 0x65, 0x73, 0x12, 0x22, 0x0a, 0x0c, 0x73, 0x6b, 0x69, 0x70,
+	0x49, 0x66, 0x41, 0x62, 0x73, 0x65, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c,
+	0x73, 0x6b, 0x69, 0x70, 0x49, 0x66, 0x41, 0x62, 0x73, 0x65, 0x6e, 0x74, 0x22, 0xd6, 0x02, 0x0a,
+	0x16, 0x52, 0x65, 0x61, 0x64, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x39, 0x0a, 0x06, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x52, 0x65,
+	0x61, 0x64, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52,
+	0x65, 0x70, 0x6c, 0x79, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x06, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x73, 0x12, 0x3b, 0x0a, 0x07, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x18, 0x02, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x52, 0x65, 0x61, 0x64, 0x43, 0x6f,
+	0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x70, 0x6c, 0x79,
+	0x2e, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x52, 0x07, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x1a,
+	0x5b, 0x0a, 0x05, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x12, 0x0a, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x66, 0x6f, 0x75, 0x6e, 0x64, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x66, 0x6f, 0x75, 0x6e, 0x64, 0x1a, 0x67, 0x0a, 0x05,
+	0x4d, 0x61, 0x74, 0x63, 0x68, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x1e, 0x0a, 0x0a, 0x6c, 0x69, 0x6e,
+	0x65, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x6c,
+	0x69, 0x6e, 0x65, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x6c, 0x69, 0x6e,
+	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6c, 0x69, 0x6e, 0x65, 0x12, 0x16, 0x0a,
+	0x06, 0x73, 0x65, 0x61, 0x72, 0x63, 0x68, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73,
+	0x65, 0x61, 0x72, 0x63, 0x68, 0x22, 0xae, 0x01, 0x0a, 0x18, 0x52, 0x65, 0x6e, 0x61, 0x6d, 0x65,
+	0x54, 0x61, 0x62, 0x6c, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x4a, 0x0a, 0x0b, 0x72, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x50, 0x61, 0x69, 0x72,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x28, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x52, 0x65,
+	0x6e, 0x61, 0x6d, 0x65, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x52, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x50, 0x61, 0x69,
+	0x72, 0x52, 0x0b, 0x72, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x50, 0x61, 0x69, 0x72, 0x73, 0x1a, 0x46,
+	0x0a, 0x0a, 0x52, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x50, 0x61, 0x69, 0x72, 0x12, 0x16, 0x0a, 0x06,
+	0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x6f,
+	0x75, 0x72, 0x63, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x74, 0x69,
+	0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x18, 0x0a, 0x16, 0x52, 0x65, 0x6e, 0x61, 0x6d, 0x65,
+	0x54, 0x61, 0x62, 0x6c, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79,
+	0x22, 0xf5, 0x01, 0x0a, 0x19, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x52, 0x65, 0x63, 0x6f, 0x76,
+	0x65, 0x72, 0x79, 0x43, 0x6f, 0x6e, 0x66, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x4b,
+	0x0a, 0x0b, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x29, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65,
+	0x52, 0x65, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x43, 0x6f, 0x6e, 0x66, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x2e, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0b,
+	0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x1a, 0x8a, 0x01, 0x0a, 0x0a,
+	0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x24, 0x0a, 0x0d, 0x6d, 0x69,
+	0x72, 0x72, 0x6f, 0x72, 0x44, 0x61, 0x74, 0x61, 0x44, 0x69, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0d, 0x6d, 0x69, 0x72, 0x72, 0x6f, 0x72, 0x44, 0x61, 0x74, 0x61, 0x44, 0x69, 0x72,
+	0x12, 0x12, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x75, 0x73, 0x65, 0x72, 0x12, 0x20, 0x0a, 0x0b, 0x70, 0x72, 0x69, 0x6d, 0x61, 0x72, 0x79, 0x48,
+	0x6f, 0x73, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x70, 0x72, 0x69, 0x6d, 0x61,
+	0x72, 0x79, 0x48, 0x6f, 0x73, 0x74, 0x12, 0x20, 0x0a, 0x0b, 0x70, 0x72, 0x69, 0x6d, 0x61, 0x72,
+	0x79, 0x50, 0x6f, 0x72, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0b, 0x70, 0x72, 0x69,
+	0x6d, 0x61, 0x72, 0x79, 0x50, 0x6f, 0x72, 0x74, 0x22, 0x19, 0x0a, 0x17, 0x43, 0x72, 0x65, 0x61,
+	0x74, 0x65, 0x52, 0x65, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x43, 0x6f, 0x6e, 0x66, 0x52, 0x65,
+	0x70, 0x6c, 0x79, 0x22, 0xb6, 0x01, 0x0a, 0x1c, 0x41, 0x64, 0x64, 0x52, 0x65, 0x70, 0x6c, 0x69,
+	0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x45, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x41, 0x0a, 0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x27, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x41, 0x64, 0x64, 0x52,
+	0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x45, 0x6e, 0x74, 0x72, 0x69, 0x65,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x07,
+	0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x1a, 0x53, 0x0a, 0x05, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x12, 0x18, 0x0a, 0x07, 0x44, 0x61, 0x74, 0x61, 0x44, 0x69, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x07, 0x44, 0x61, 0x74, 0x61, 0x44, 0x69, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x55, 0x73,
+	0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x55, 0x73, 0x65, 0x72, 0x12, 0x1c,
+	0x0a, 0x09, 0x48, 0x6f, 0x73, 0x74, 0x41, 0x64, 0x64, 0x72, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x09, 0x48, 0x6f, 0x73, 0x74, 0x41, 0x64, 0x64, 0x72, 0x73, 0x22, 0x1c, 0x0a, 0x1a,
+	0x41, 0x64, 0x64, 0x52, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x45, 0x6e,
+	0x74, 0x72, 0x69, 0x65, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x66, 0x0a, 0x18, 0x49, 0x6e,
+	0x76, 0x65, 0x6e, 0x74, 0x6f, 0x72, 0x79, 0x53, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x70, 0x61, 0x72, 0x65, 0x6e, 0x74,
+	0x44, 0x69, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x70, 0x61, 0x72, 0x65,
+	0x6e, 0x74, 0x44, 0x69, 0x72, 0x73, 0x12, 0x2a, 0x0a, 0x10, 0x72, 0x65, 0x63, 0x6f, 0x76, 0x65,
+	0x72, 0x79, 0x43, 0x6f, 0x6e, 0x66, 0x46, 0x69, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x10, 0x72, 0x65, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x43, 0x6f, 0x6e, 0x66, 0x46, 0x69,
+	0x6c, 0x65, 0x22, 0xea, 0x01, 0x0a, 0x16, 0x49, 0x6e, 0x76, 0x65, 0x6e, 0x74, 0x6f, 0x72, 0x79,
+	0x53, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x53, 0x0a,
+	0x0f, 0x64, 0x61, 0x74, 0x61, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x69, 0x65, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x29, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x49, 0x6e, 0x76,
+	0x65, 0x6e, 0x74, 0x6f, 0x72, 0x79, 0x53, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65,
+	0x70, 0x6c, 0x79, 0x2e, 0x44, 0x61, 0x74, 0x61, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72,
+	0x79, 0x52, 0x0f, 0x64, 0x61, 0x74, 0x61, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x69,
+	0x65, 0x73, 0x1a, 0x7b, 0x0a, 0x0d, 0x44, 0x61, 0x74, 0x61, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74,
+	0x6f, 0x72, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x64, 0x61, 0x74, 0x61, 0x44, 0x69, 0x72, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x64, 0x61, 0x74, 0x61, 0x44, 0x69, 0x72, 0x12, 0x12, 0x0a,
+	0x04, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x70, 0x6f, 0x72,
+	0x74, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x62, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x04, 0x64, 0x62, 0x69, 0x64, 0x12, 0x28, 0x0a, 0x0f, 0x70, 0x72, 0x69, 0x6d, 0x61, 0x72, 0x79,
+	0x43, 0x6f, 0x6e, 0x6e, 0x69, 0x6e, 0x66, 0x6f, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f,
+	0x70, 0x72, 0x69, 0x6d, 0x61, 0x72, 0x79, 0x43, 0x6f, 0x6e, 0x6e, 0x69, 0x6e, 0x66, 0x6f, 0x22,
+	0x2c, 0x0a, 0x16, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x42, 0x61, 0x63, 0x6b, 0x75,
+	0x70, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x69, 0x72,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x64, 0x69, 0x72, 0x73, 0x22, 0xdb, 0x01,
+	0x0a, 0x14, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x42, 0x61, 0x63, 0x6b, 0x75, 0x70,
+	0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x3a, 0x0a, 0x07, 0x62, 0x61, 0x63, 0x6b, 0x75, 0x70,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x4c, 0x69,
+	0x73, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x42, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x73, 0x52, 0x65, 0x70,
+	0x6c, 0x79, 0x2e, 0x42, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x52, 0x07, 0x62, 0x61, 0x63, 0x6b, 0x75,
+	0x70, 0x73, 0x1a, 0x86, 0x01, 0x0a, 0x06, 0x42, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x12, 0x12, 0x0a,
+	0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74,
+	0x68, 0x12, 0x22, 0x0a, 0x0c, 0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x61, 0x6c, 0x50, 0x61, 0x74,
+	0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x61,
+	0x6c, 0x50, 0x61, 0x74, 0x68, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x75, 0x6d, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x75, 0x6d, 0x4a,
+	0x04, 0x08, 0x02, 0x10, 0x03, 0x4a, 0x04, 0x08, 0x05, 0x10, 0x06, 0x22, 0x18, 0x0a, 0x16, 0x47,
+	0x65, 0x74, 0x43, 0x61, 0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x32, 0x0a, 0x14, 0x47, 0x65, 0x74, 0x43, 0x61, 0x70, 0x61,
+	0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x1a, 0x0a,
+	0x08, 0x66, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x08, 0x66, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x22, 0x30, 0x0a, 0x18, 0x53, 0x6e, 0x61,
+	0x70, 0x73, 0x68, 0x6f, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x61, 0x74, 0x68, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x70, 0x61, 0x74, 0x68, 0x73, 0x22, 0xb2, 0x01, 0x0a, 0x16,
+	0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x46, 0x69, 0x6c, 0x65,
+	0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x36, 0x0a, 0x05, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x53, 0x6e, 0x61, 0x70,
+	0x73, 0x68, 0x6f, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x70,
+	0x6c, 0x79, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x05, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x1a, 0x60,
+	0x0a, 0x04, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x14, 0x0a, 0x05, 0x66, 0x6f,
+	0x75, 0x6e, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x66, 0x6f, 0x75, 0x6e, 0x64,
+	0x12, 0x1a, 0x0a, 0x08, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x08, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x12, 0x0a, 0x04,
+	0x6d, 0x6f, 0x64, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x6d, 0x6f, 0x64, 0x65,
+	0x22, 0x1c, 0x0a, 0x1a, 0x43, 0x6f, 0x6e, 0x66, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x48, 0x65,
+	0x61, 0x72, 0x74, 0x62, 0x65, 0x61, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x5c,
+	0x0a, 0x18, 0x43, 0x6f, 0x6e, 0x66, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x48, 0x65, 0x61, 0x72,
+	0x74, 0x62, 0x65, 0x61, 0x74, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72,
+	0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x70, 0x72,
+	0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x12, 0x24, 0x0a, 0x0d, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65,
+	0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0d, 0x61,
+	0x63, 0x74, 0x69, 0x76, 0x65, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x73, 0x22, 0x4b, 0x0a, 0x19,
+	0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x42, 0x61, 0x63, 0x6b, 0x75,
+	0x70, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x61, 0x74,
+	0x68, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x70, 0x61, 0x74, 0x68, 0x73, 0x12,
+	0x18, 0x0a, 0x07, 0x74, 0x65, 0x6d, 0x70, 0x44, 0x69, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x07, 0x74, 0x65, 0x6d, 0x70, 0x44, 0x69, 0x72, 0x22, 0x3f, 0x0a, 0x17, 0x52, 0x65, 0x73,
+	0x74, 0x6f, 0x72, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x42, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x73, 0x52,
+	0x65, 0x70, 0x6c, 0x79, 0x12, 0x24, 0x0a, 0x0d, 0x72, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x64,
+	0x50, 0x61, 0x74, 0x68, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0d, 0x72, 0x65, 0x73,
+	0x74, 0x6f, 0x72, 0x65, 0x64, 0x50, 0x61, 0x74, 0x68, 0x73, 0x22, 0x30, 0x0a, 0x18, 0x44, 0x65,
+	0x6c, 0x65, 0x74, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x42, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x61, 0x74, 0x68, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x70, 0x61, 0x74, 0x68, 0x73, 0x22, 0x3c, 0x0a, 0x16,
+	0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x42, 0x61, 0x63, 0x6b, 0x75, 0x70,
+	0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x22, 0x0a, 0x0c, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65,
+	0x64, 0x50, 0x61, 0x74, 0x68, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0c, 0x64, 0x65,
+	0x6c, 0x65, 0x74, 0x65, 0x64, 0x50, 0x61, 0x74, 0x68, 0x73, 0x22, 0x18, 0x0a, 0x16, 0x52, 0x65,
+	0x61, 0x64, 0x43, 0x6f, 0x6e, 0x66, 0x4a, 0x6f, 0x75, 0x72, 0x6e, 0x61, 0x6c, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x22, 0x30, 0x0a, 0x14, 0x52, 0x65, 0x61, 0x64, 0x43, 0x6f, 0x6e, 0x66,
+	0x4a, 0x6f, 0x75, 0x72, 0x6e, 0x61, 0x6c, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x18, 0x0a, 0x07,
+	0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x72,
+	0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x32, 0x81, 0x11, 0x0a, 0x05, 0x41, 0x67, 0x65, 0x6e, 0x74,
+	0x12, 0x5d, 0x0a, 0x15, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x42, 0x61, 0x63, 0x6b, 0x75, 0x70,
+	0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x21, 0x2e, 0x69, 0x64, 0x6c, 0x2e,
+	0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x42, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x44, 0x69, 0x72, 0x65,
+	0x63, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x69,
+	0x64, 0x6c, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x42, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x44,
 	0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12,
-	0x59, 0x0a, 0x1b, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x73, 0x70,
-	0x61, 0x63, 0x65, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x69, 0x65, 0x73, 0x12, 0x1c,
-	0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x54, 0x61, 0x62, 0x6c, 0x65,
-	0x73, 0x70, 0x61, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x69,
-	0x64, 0x6c, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x73, 0x70,
-	0x61, 0x63, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x57, 0x0a, 0x13, 0x41, 0x72,
-	0x63, 0x68, 0x69, 0x76, 0x65, 0x4c, 0x6f, 0x67, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72,
-	0x79, 0x12, 0x1f, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x41, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x4c,
-	0x6f, 0x67, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x41, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65,
-	0x4c, 0x6f, 0x67, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x70, 0x6c,
-	0x79, 0x22, 0x00, 0x12, 0x3c, 0x0a, 0x14, 0x52, 0x73, 0x79, 0x6e, 0x63, 0x44, 0x61, 0x74, 0x61,
-	0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x69, 0x65, 0x73, 0x12, 0x11, 0x2e, 0x69, 0x64,
-	0x6c, 0x2e, 0x52, 0x73, 0x79, 0x6e, 0x63, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0f,
-	0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x52, 0x73, 0x79, 0x6e, 0x63, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22,
-	0x00, 0x12, 0x42, 0x0a, 0x1a, 0x52, 0x73, 0x79, 0x6e, 0x63, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x73,
-	0x70, 0x61, 0x63, 0x65, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x69, 0x65, 0x73, 0x12,
-	0x11, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x52, 0x73, 0x79, 0x6e, 0x63, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x1a, 0x0f, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x52, 0x73, 0x79, 0x6e, 0x63, 0x52, 0x65,
-	0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x57, 0x0a, 0x19, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65,
-	0x50, 0x72, 0x69, 0x6d, 0x61, 0x72, 0x69, 0x65, 0x73, 0x50, 0x67, 0x43, 0x6f, 0x6e, 0x74, 0x72,
-	0x6f, 0x6c, 0x12, 0x1c, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65,
-	0x50, 0x67, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x1a, 0x1a, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x50, 0x67,
-	0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x57,
-	0x0a, 0x13, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72,
-	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1f, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x55, 0x70, 0x64, 0x61,
-	0x74, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x55, 0x70, 0x64,
-	0x61, 0x74, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
-	0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x51, 0x0a, 0x11, 0x52, 0x65, 0x6e, 0x61, 0x6d,
-	0x65, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x73, 0x12, 0x1d, 0x2e, 0x69,
-	0x64, 0x6c, 0x2e, 0x52, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x73, 0x70,
-	0x61, 0x63, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x69, 0x64,
-	0x6c, 0x2e, 0x52, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x73, 0x70, 0x61,
-	0x63, 0x65, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x54, 0x0a, 0x12, 0x43, 0x72,
-	0x65, 0x61, 0x74, 0x65, 0x52, 0x65, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x43, 0x6f, 0x6e, 0x66,
-	0x12, 0x1e, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x52, 0x65, 0x63,
-	0x6f, 0x76, 0x65, 0x72, 0x79, 0x43, 0x6f, 0x6e, 0x66, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x1a, 0x1c, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x52, 0x65, 0x63,
-	0x6f, 0x76, 0x65, 0x72, 0x79, 0x43, 0x6f, 0x6e, 0x66, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00,
-	0x12, 0x5d, 0x0a, 0x15, 0x41, 0x64, 0x64, 0x52, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x69,
-	0x6f, 0x6e, 0x45, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x12, 0x21, 0x2e, 0x69, 0x64, 0x6c, 0x2e,
+	0x4f, 0x0a, 0x0e, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x44, 0x69, 0x73, 0x6b, 0x53, 0x70, 0x61, 0x63,
+	0x65, 0x12, 0x21, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x53, 0x65, 0x67,
+	0x6d, 0x65, 0x6e, 0x74, 0x44, 0x69, 0x73, 0x6b, 0x53, 0x70, 0x61, 0x63, 0x65, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x43, 0x68, 0x65, 0x63, 0x6b,
+	0x44, 0x69, 0x73, 0x6b, 0x53, 0x70, 0x61, 0x63, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00,
+	0x12, 0x4e, 0x0a, 0x10, 0x55, 0x70, 0x67, 0x72, 0x61, 0x64, 0x65, 0x50, 0x72, 0x69, 0x6d, 0x61,
+	0x72, 0x69, 0x65, 0x73, 0x12, 0x1c, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x55, 0x70, 0x67, 0x72, 0x61,
+	0x64, 0x65, 0x50, 0x72, 0x69, 0x6d, 0x61, 0x72, 0x69, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x55, 0x70, 0x67, 0x72, 0x61, 0x64, 0x65,
+	0x50, 0x72, 0x69, 0x6d, 0x61, 0x72, 0x69, 0x65, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00,
+	0x12, 0x51, 0x0a, 0x11, 0x52, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74,
+	0x6f, 0x72, 0x69, 0x65, 0x73, 0x12, 0x1d, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x52, 0x65, 0x6e, 0x61,
+	0x6d, 0x65, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x69, 0x65, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x52, 0x65, 0x6e, 0x61, 0x6d,
+	0x65, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x69, 0x65, 0x73, 0x52, 0x65, 0x70, 0x6c,
+	0x79, 0x22, 0x00, 0x12, 0x39, 0x0a, 0x09, 0x53, 0x74, 0x6f, 0x70, 0x41, 0x67, 0x65, 0x6e, 0x74,
+	0x12, 0x15, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x53, 0x74, 0x6f, 0x70, 0x41, 0x67, 0x65, 0x6e, 0x74,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x53, 0x74,
+	0x6f, 0x70, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x5d,
+	0x0a, 0x15, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x44, 0x61, 0x74, 0x61, 0x44, 0x69, 0x72, 0x65,
+	0x63, 0x74, 0x6f, 0x72, 0x69, 0x65, 0x73, 0x12, 0x21, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x44, 0x65,
+	0x6c, 0x65, 0x74, 0x65, 0x44, 0x61, 0x74, 0x61, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72,
+	0x69, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x69, 0x64, 0x6c,
+	0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x44, 0x61, 0x74, 0x61, 0x44, 0x69, 0x72, 0x65, 0x63,
+	0x74, 0x6f, 0x72, 0x69, 0x65, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x5d, 0x0a,
+	0x15, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x42, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x44, 0x69, 0x72,
+	0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x21, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x44, 0x65, 0x6c,
+	0x65, 0x74, 0x65, 0x42, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f,
+	0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x69, 0x64, 0x6c, 0x2e,
+	0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x42, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x44, 0x69, 0x72, 0x65,
+	0x63, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x5a, 0x0a, 0x14,
+	0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x44, 0x69, 0x72, 0x65, 0x63,
+	0x74, 0x6f, 0x72, 0x79, 0x12, 0x20, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74,
+	0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x44, 0x65, 0x6c,
+	0x65, 0x74, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72,
+	0x79, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x59, 0x0a, 0x1b, 0x44, 0x65, 0x6c, 0x65,
+	0x74, 0x65, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x44, 0x69, 0x72, 0x65,
+	0x63, 0x74, 0x6f, 0x72, 0x69, 0x65, 0x73, 0x12, 0x1c, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x44, 0x65,
+	0x6c, 0x65, 0x74, 0x65, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x44, 0x65, 0x6c, 0x65,
+	0x74, 0x65, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x52, 0x65, 0x70, 0x6c,
+	0x79, 0x22, 0x00, 0x12, 0x57, 0x0a, 0x13, 0x41, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x4c, 0x6f,
+	0x67, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x1f, 0x2e, 0x69, 0x64, 0x6c,
+	0x2e, 0x41, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x4c, 0x6f, 0x67, 0x44, 0x69, 0x72, 0x65, 0x63,
+	0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x69, 0x64,
+	0x6c, 0x2e, 0x41, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x4c, 0x6f, 0x67, 0x44, 0x69, 0x72, 0x65,
+	0x63, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x3c, 0x0a, 0x14,
+	0x52, 0x73, 0x79, 0x6e, 0x63, 0x44, 0x61, 0x74, 0x61, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f,
+	0x72, 0x69, 0x65, 0x73, 0x12, 0x11, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x52, 0x73, 0x79, 0x6e, 0x63,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0f, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x52, 0x73,
+	0x79, 0x6e, 0x63, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x42, 0x0a, 0x1a, 0x52, 0x73,
+	0x79, 0x6e, 0x63, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x44, 0x69, 0x72,
+	0x65, 0x63, 0x74, 0x6f, 0x72, 0x69, 0x65, 0x73, 0x12, 0x11, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x52,
+	0x73, 0x79, 0x6e, 0x63, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0f, 0x2e, 0x69, 0x64,
+	0x6c, 0x2e, 0x52, 0x73, 0x79, 0x6e, 0x63, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x57,
+	0x0a, 0x19, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x50, 0x72, 0x69, 0x6d, 0x61, 0x72, 0x69,
+	0x65, 0x73, 0x50, 0x67, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x12, 0x1c, 0x2e, 0x69, 0x64,
+	0x6c, 0x2e, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x50, 0x67, 0x43, 0x6f, 0x6e, 0x74, 0x72,
+	0x6f, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x69, 0x64, 0x6c, 0x2e,
+	0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x50, 0x67, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c,
+	0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x57, 0x0a, 0x13, 0x55, 0x70, 0x64, 0x61, 0x74,
+	0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1f,
+	0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69,
+	0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x1d, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x43, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00,
+	0x12, 0x51, 0x0a, 0x11, 0x52, 0x65, 0x61, 0x64, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1d, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x52, 0x65, 0x61, 0x64,
+	0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x52, 0x65, 0x61, 0x64, 0x43,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x70, 0x6c,
+	0x79, 0x22, 0x00, 0x12, 0x51, 0x0a, 0x11, 0x52, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x54, 0x61, 0x62,
+	0x6c, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x73, 0x12, 0x1d, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x52,
+	0x65, 0x6e, 0x61, 0x6d, 0x65, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x52, 0x65,
+	0x6e, 0x61, 0x6d, 0x65, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x73, 0x52,
+	0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x54, 0x0a, 0x12, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65,
+	0x52, 0x65, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x43, 0x6f, 0x6e, 0x66, 0x12, 0x1e, 0x2e, 0x69,
+	0x64, 0x6c, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x52, 0x65, 0x63, 0x6f, 0x76, 0x65, 0x72,
+	0x79, 0x43, 0x6f, 0x6e, 0x66, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x69,
+	0x64, 0x6c, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x52, 0x65, 0x63, 0x6f, 0x76, 0x65, 0x72,
+	0x79, 0x43, 0x6f, 0x6e, 0x66, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x5d, 0x0a, 0x15,
 	0x41, 0x64, 0x64, 0x52, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x45, 0x6e,
-	0x74, 0x72, 0x69, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x69,
-	0x64, 0x6c, 0x2e, 0x41, 0x64, 0x64, 0x52, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f,
-	0x6e, 0x45, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x42,
-	0x27, 0x5a, 0x25, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x67, 0x72,
-	0x65, 0x65, 0x6e, 0x70, 0x6c, 0x75, 0x6d, 0x2d, 0x64, 0x62, 0x2f, 0x67, 0x70, 0x75, 0x70, 0x67,
-	0x72, 0x61, 0x64, 0x65, 0x2f, 0x69, 0x64, 0x6c, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x74, 0x72, 0x69, 0x65, 0x73, 0x12, 0x21, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x41, 0x64, 0x64, 0x52,
+	0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x45, 0x6e, 0x74, 0x72, 0x69, 0x65,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x41,
+	0x64, 0x64, 0x52, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x45, 0x6e, 0x74,
+	0x72, 0x69, 0x65, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x51, 0x0a, 0x11, 0x49,
+	0x6e, 0x76, 0x65, 0x6e, 0x74, 0x6f, 0x72, 0x79, 0x53, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x73,
+	0x12, 0x1d, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x49, 0x6e, 0x76, 0x65, 0x6e, 0x74, 0x6f, 0x72, 0x79,
+	0x53, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x1b, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x49, 0x6e, 0x76, 0x65, 0x6e, 0x74, 0x6f, 0x72, 0x79, 0x53,
+	0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x4b,
+	0x0a, 0x0f, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x42, 0x61, 0x63, 0x6b, 0x75, 0x70,
+	0x73, 0x12, 0x1b, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x6f, 0x6e, 0x66,
+	0x42, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19,
+	0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x42, 0x61, 0x63,
+	0x6b, 0x75, 0x70, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x4b, 0x0a, 0x0f, 0x47,
+	0x65, 0x74, 0x43, 0x61, 0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x12, 0x1b,
+	0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x47, 0x65, 0x74, 0x43, 0x61, 0x70, 0x61, 0x62, 0x69, 0x6c, 0x69,
+	0x74, 0x69, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x69, 0x64,
+	0x6c, 0x2e, 0x47, 0x65, 0x74, 0x43, 0x61, 0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65,
+	0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x51, 0x0a, 0x11, 0x53, 0x6e, 0x61, 0x70,
+	0x73, 0x68, 0x6f, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x12, 0x1d, 0x2e,
+	0x69, 0x64, 0x6c, 0x2e, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x43, 0x6f, 0x6e, 0x66,
+	0x46, 0x69, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x69,
+	0x64, 0x6c, 0x2e, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x46,
+	0x69, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x57, 0x0a, 0x13, 0x43,
+	0x6f, 0x6e, 0x66, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x48, 0x65, 0x61, 0x72, 0x74, 0x62, 0x65,
+	0x61, 0x74, 0x12, 0x1f, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x55, 0x70, 0x64,
+	0x61, 0x74, 0x65, 0x48, 0x65, 0x61, 0x72, 0x74, 0x62, 0x65, 0x61, 0x74, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x55, 0x70,
+	0x64, 0x61, 0x74, 0x65, 0x48, 0x65, 0x61, 0x72, 0x74, 0x62, 0x65, 0x61, 0x74, 0x52, 0x65, 0x70,
+	0x6c, 0x79, 0x22, 0x00, 0x12, 0x54, 0x0a, 0x12, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x43,
+	0x6f, 0x6e, 0x66, 0x42, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x73, 0x12, 0x1e, 0x2e, 0x69, 0x64, 0x6c,
+	0x2e, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x42, 0x61, 0x63, 0x6b,
+	0x75, 0x70, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x69, 0x64, 0x6c,
+	0x2e, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x42, 0x61, 0x63, 0x6b,
+	0x75, 0x70, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x51, 0x0a, 0x11, 0x44, 0x65,
+	0x6c, 0x65, 0x74, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x42, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x73, 0x12,
+	0x1d, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x43, 0x6f, 0x6e, 0x66,
+	0x42, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b,
+	0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x42,
+	0x61, 0x63, 0x6b, 0x75, 0x70, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x4b, 0x0a,
+	0x0f, 0x52, 0x65, 0x61, 0x64, 0x43, 0x6f, 0x6e, 0x66, 0x4a, 0x6f, 0x75, 0x72, 0x6e, 0x61, 0x6c,
+	0x12, 0x1b, 0x2e, 0x69, 0x64, 0x6c, 0x2e, 0x52, 0x65, 0x61, 0x64, 0x43, 0x6f, 0x6e, 0x66, 0x4a,
+	0x6f, 0x75, 0x72, 0x6e, 0x61, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e,
+	0x69, 0x64, 0x6c, 0x2e, 0x52, 0x65, 0x61, 0x64, 0x43, 0x6f, 0x6e, 0x66, 0x4a, 0x6f, 0x75, 0x72,
+	0x6e, 0x61, 0x6c, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x42, 0x27, 0x5a, 0x25, 0x67, 0x69,
+	0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x67, 0x72, 0x65, 0x65, 0x6e, 0x70, 0x6c,
+	0x75, 0x6d, 0x2d, 0x64, 0x62, 0x2f, 0x67, 0x70, 0x75, 0x70, 0x67, 0x72, 0x61, 0x64, 0x65, 0x2f,
+	0x69, 0x64, 0x6c, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -2536,109 +4598,168 @@ func file_hub_to_agent_proto_rawDescGZIP() []byte {
 	return file_hub_to_agent_proto_rawDescData
 }
 
-var file_hub_to_agent_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_hub_to_agent_proto_msgTypes = make([]protoimpl.MessageInfo, 42)
+var file_hub_to_agent_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_hub_to_agent_proto_msgTypes = make([]protoimpl.MessageInfo, 70)
 var file_hub_to_agent_proto_goTypes = []interface{}{
 	(PgOptions_PgUpgradeMode)(0),                 // 0: idl.PgOptions.PgUpgradeMode
 	(PgOptions_Action)(0),                        // 1: idl.PgOptions.Action
-	(*PgOptions)(nil),                            // 2: idl.PgOptions
-	(*TablespaceInfo)(nil),                       // 3: idl.TablespaceInfo
-	(*UpgradePrimariesRequest)(nil),              // 4: idl.UpgradePrimariesRequest
-	(*UpgradePrimariesReply)(nil),                // 5: idl.UpgradePrimariesReply
-	(*CreateBackupDirectoryRequest)(nil),         // 6: idl.CreateBackupDirectoryRequest
-	(*CreateBackupDirectoryReply)(nil),           // 7: idl.CreateBackupDirectoryReply
-	(*DeleteDataDirectoriesRequest)(nil),         // 8: idl.DeleteDataDirectoriesRequest
-	(*DeleteDataDirectoriesReply)(nil),           // 9: idl.DeleteDataDirectoriesReply
-	(*DeleteStateDirectoryRequest)(nil),          // 10: idl.DeleteStateDirectoryRequest
-	(*DeleteStateDirectoryReply)(nil),            // 11: idl.DeleteStateDirectoryReply
-	(*DeleteBackupDirectoryRequest)(nil),         // 12: idl.DeleteBackupDirectoryRequest
-	(*DeleteBackupDirectoryReply)(nil),           // 13: idl.DeleteBackupDirectoryReply
-	(*DeleteTablespaceRequest)(nil),              // 14: idl.DeleteTablespaceRequest
-	(*DeleteTablespaceReply)(nil),                // 15: idl.DeleteTablespaceReply
-	(*ArchiveLogDirectoryRequest)(nil),           // 16: idl.ArchiveLogDirectoryRequest
-	(*ArchiveLogDirectoryReply)(nil),             // 17: idl.ArchiveLogDirectoryReply
-	(*RenameDirectories)(nil),                    // 18: idl.RenameDirectories
-	(*RenameDirectoriesRequest)(nil),             // 19: idl.RenameDirectoriesRequest
-	(*RenameDirectoriesReply)(nil),               // 20: idl.RenameDirectoriesReply
-	(*StopAgentRequest)(nil),                     // 21: idl.StopAgentRequest
-	(*StopAgentReply)(nil),                       // 22: idl.StopAgentReply
-	(*CheckSegmentDiskSpaceRequest)(nil),         // 23: idl.CheckSegmentDiskSpaceRequest
-	(*CheckDiskSpaceReply)(nil),                  // 24: idl.CheckDiskSpaceReply
-	(*RsyncRequest)(nil),                         // 25: idl.RsyncRequest
-	(*RsyncReply)(nil),                           // 26: idl.RsyncReply
-	(*RestorePgControlRequest)(nil),              // 27: idl.RestorePgControlRequest
-	(*RestorePgControlReply)(nil),                // 28: idl.RestorePgControlReply
-	(*UpdateFileConfOptions)(nil),                // 29: idl.UpdateFileConfOptions
-	(*UpdateConfigurationRequest)(nil),           // 30: idl.UpdateConfigurationRequest
-	(*UpdateConfigurationReply)(nil),             // 31: idl.UpdateConfigurationReply
-	(*RenameTablespacesRequest)(nil),             // 32: idl.RenameTablespacesRequest
-	(*RenameTablespacesReply)(nil),               // 33: idl.RenameTablespacesReply
-	(*CreateRecoveryConfRequest)(nil),            // 34: idl.CreateRecoveryConfRequest
-	(*CreateRecoveryConfReply)(nil),              // 35: idl.CreateRecoveryConfReply
-	(*AddReplicationEntriesRequest)(nil),         // 36: idl.AddReplicationEntriesRequest
-	(*AddReplicationEntriesReply)(nil),           // 37: idl.AddReplicationEntriesReply
-	nil,                                          // 38: idl.PgOptions.TablespacesEntry
-	(*CheckDiskSpaceReply_DiskUsage)(nil),        // 39: idl.CheckDiskSpaceReply.DiskUsage
-	(*RsyncRequest_RsyncOptions)(nil),            // 40: idl.RsyncRequest.RsyncOptions
-	(*RenameTablespacesRequest_RenamePair)(nil),  // 41: idl.RenameTablespacesRequest.RenamePair
-	(*CreateRecoveryConfRequest_Connection)(nil), // 42: idl.CreateRecoveryConfRequest.Connection
-	(*AddReplicationEntriesRequest_Entry)(nil),   // 43: idl.AddReplicationEntriesRequest.Entry
-	(Mode)(0), // 44: idl.Mode
+	(UpdateFileConfOptions_DuplicateMatches)(0),  // 2: idl.UpdateFileConfOptions.DuplicateMatches
+	(*PgOptions)(nil),                            // 3: idl.PgOptions
+	(*TablespaceInfo)(nil),                       // 4: idl.TablespaceInfo
+	(*UpgradePrimariesRequest)(nil),              // 5: idl.UpgradePrimariesRequest
+	(*UpgradePrimariesReply)(nil),                // 6: idl.UpgradePrimariesReply
+	(*CreateBackupDirectoryRequest)(nil),         // 7: idl.CreateBackupDirectoryRequest
+	(*CreateBackupDirectoryReply)(nil),           // 8: idl.CreateBackupDirectoryReply
+	(*DeleteDataDirectoriesRequest)(nil),         // 9: idl.DeleteDataDirectoriesRequest
+	(*DeleteDataDirectoriesReply)(nil),           // 10: idl.DeleteDataDirectoriesReply
+	(*DeleteStateDirectoryRequest)(nil),          // 11: idl.DeleteStateDirectoryRequest
+	(*DeleteStateDirectoryReply)(nil),            // 12: idl.DeleteStateDirectoryReply
+	(*DeleteBackupDirectoryRequest)(nil),         // 13: idl.DeleteBackupDirectoryRequest
+	(*DeleteBackupDirectoryReply)(nil),           // 14: idl.DeleteBackupDirectoryReply
+	(*DeleteTablespaceRequest)(nil),              // 15: idl.DeleteTablespaceRequest
+	(*DeleteTablespaceReply)(nil),                // 16: idl.DeleteTablespaceReply
+	(*ArchiveLogDirectoryRequest)(nil),           // 17: idl.ArchiveLogDirectoryRequest
+	(*ArchiveLogDirectoryReply)(nil),             // 18: idl.ArchiveLogDirectoryReply
+	(*RenameDirectories)(nil),                    // 19: idl.RenameDirectories
+	(*RenameDirectoriesRequest)(nil),             // 20: idl.RenameDirectoriesRequest
+	(*RenameDirectoriesReply)(nil),               // 21: idl.RenameDirectoriesReply
+	(*StopAgentRequest)(nil),                     // 22: idl.StopAgentRequest
+	(*StopAgentReply)(nil),                       // 23: idl.StopAgentReply
+	(*CheckSegmentDiskSpaceRequest)(nil),         // 24: idl.CheckSegmentDiskSpaceRequest
+	(*CheckDiskSpaceReply)(nil),                  // 25: idl.CheckDiskSpaceReply
+	(*RsyncRequest)(nil),                         // 26: idl.RsyncRequest
+	(*RsyncReply)(nil),                           // 27: idl.RsyncReply
+	(*RestorePgControlRequest)(nil),              // 28: idl.RestorePgControlRequest
+	(*RestorePgControlReply)(nil),                // 29: idl.RestorePgControlReply
+	(*UpdateFileConfOptions)(nil),                // 30: idl.UpdateFileConfOptions
+	(*UpdateConfigurationRequest)(nil),           // 31: idl.UpdateConfigurationRequest
+	(*ConfFileDiff)(nil),                         // 32: idl.ConfFileDiff
+	(*UpdateConfigurationReply)(nil),             // 33: idl.UpdateConfigurationReply
+	(*ConfFileChecksum)(nil),                     // 34: idl.ConfFileChecksum
+	(*ChangedLine)(nil),                          // 35: idl.ChangedLine
+	(*ReadConfigurationRequest)(nil),             // 36: idl.ReadConfigurationRequest
+	(*ReadConfigurationReply)(nil),               // 37: idl.ReadConfigurationReply
+	(*RenameTablespacesRequest)(nil),             // 38: idl.RenameTablespacesRequest
+	(*RenameTablespacesReply)(nil),               // 39: idl.RenameTablespacesReply
+	(*CreateRecoveryConfRequest)(nil),            // 40: idl.CreateRecoveryConfRequest
+	(*CreateRecoveryConfReply)(nil),              // 41: idl.CreateRecoveryConfReply
+	(*AddReplicationEntriesRequest)(nil),         // 42: idl.AddReplicationEntriesRequest
+	(*AddReplicationEntriesReply)(nil),           // 43: idl.AddReplicationEntriesReply
+	(*InventorySegmentsRequest)(nil),             // 44: idl.InventorySegmentsRequest
+	(*InventorySegmentsReply)(nil),               // 45: idl.InventorySegmentsReply
+	(*ListConfBackupsRequest)(nil),               // 46: idl.ListConfBackupsRequest
+	(*ListConfBackupsReply)(nil),                 // 47: idl.ListConfBackupsReply
+	(*GetCapabilitiesRequest)(nil),               // 48: idl.GetCapabilitiesRequest
+	(*GetCapabilitiesReply)(nil),                 // 49: idl.GetCapabilitiesReply
+	(*SnapshotConfFilesRequest)(nil),             // 50: idl.SnapshotConfFilesRequest
+	(*SnapshotConfFilesReply)(nil),               // 51: idl.SnapshotConfFilesReply
+	(*ConfUpdateHeartbeatRequest)(nil),           // 52: idl.ConfUpdateHeartbeatRequest
+	(*ConfUpdateHeartbeatReply)(nil),             // 53: idl.ConfUpdateHeartbeatReply
+	(*RestoreConfBackupsRequest)(nil),            // 54: idl.RestoreConfBackupsRequest
+	(*RestoreConfBackupsReply)(nil),              // 55: idl.RestoreConfBackupsReply
+	(*DeleteConfBackupsRequest)(nil),             // 56: idl.DeleteConfBackupsRequest
+	(*DeleteConfBackupsReply)(nil),               // 57: idl.DeleteConfBackupsReply
+	(*ReadConfJournalRequest)(nil),               // 58: idl.ReadConfJournalRequest
+	(*ReadConfJournalReply)(nil),                 // 59: idl.ReadConfJournalReply
+	nil,                                          // 60: idl.PgOptions.TablespacesEntry
+	(*CheckDiskSpaceReply_DiskUsage)(nil),        // 61: idl.CheckDiskSpaceReply.DiskUsage
+	(*RsyncRequest_RsyncOptions)(nil),            // 62: idl.RsyncRequest.RsyncOptions
+	nil,                                          // 63: idl.UpdateConfigurationReply.ResolvedPathsEntry
+	(*ReadConfigurationRequest_File)(nil),        // 64: idl.ReadConfigurationRequest.File
+	(*ReadConfigurationReply_Value)(nil),         // 65: idl.ReadConfigurationReply.Value
+	(*ReadConfigurationReply_Match)(nil),         // 66: idl.ReadConfigurationReply.Match
+	(*RenameTablespacesRequest_RenamePair)(nil),  // 67: idl.RenameTablespacesRequest.RenamePair
+	(*CreateRecoveryConfRequest_Connection)(nil), // 68: idl.CreateRecoveryConfRequest.Connection
+	(*AddReplicationEntriesRequest_Entry)(nil),   // 69: idl.AddReplicationEntriesRequest.Entry
+	(*InventorySegmentsReply_DataDirectory)(nil), // 70: idl.InventorySegmentsReply.DataDirectory
+	(*ListConfBackupsReply_Backup)(nil),          // 71: idl.ListConfBackupsReply.Backup
+	(*SnapshotConfFilesReply_File)(nil),          // 72: idl.SnapshotConfFilesReply.File
+	(Mode)(0),                                    // 73: idl.Mode
 }
 var file_hub_to_agent_proto_depIdxs = []int32{
 	1,  // 0: idl.PgOptions.action:type_name -> idl.PgOptions.Action
 	0,  // 1: idl.PgOptions.pgUpgradeMode:type_name -> idl.PgOptions.PgUpgradeMode
-	44, // 2: idl.PgOptions.mode:type_name -> idl.Mode
-	38, // 3: idl.PgOptions.Tablespaces:type_name -> idl.PgOptions.TablespacesEntry
+	73, // 2: idl.PgOptions.mode:type_name -> idl.Mode
+	60, // 3: idl.PgOptions.Tablespaces:type_name -> idl.PgOptions.TablespacesEntry
 	1,  // 4: idl.UpgradePrimariesRequest.action:type_name -> idl.PgOptions.Action
-	2,  // 5: idl.UpgradePrimariesRequest.opts:type_name -> idl.PgOptions
-	18, // 6: idl.RenameDirectoriesRequest.Dirs:type_name -> idl.RenameDirectories
-	39, // 7: idl.CheckDiskSpaceReply.usages:type_name -> idl.CheckDiskSpaceReply.DiskUsage
-	40, // 8: idl.RsyncRequest.options:type_name -> idl.RsyncRequest.RsyncOptions
-	29, // 9: idl.UpdateConfigurationRequest.options:type_name -> idl.UpdateFileConfOptions
-	41, // 10: idl.RenameTablespacesRequest.renamePairs:type_name -> idl.RenameTablespacesRequest.RenamePair
-	42, // 11: idl.CreateRecoveryConfRequest.connections:type_name -> idl.CreateRecoveryConfRequest.Connection
-	43, // 12: idl.AddReplicationEntriesRequest.entries:type_name -> idl.AddReplicationEntriesRequest.Entry
-	3,  // 13: idl.PgOptions.TablespacesEntry.value:type_name -> idl.TablespaceInfo
-	6,  // 14: idl.Agent.CreateBackupDirectory:input_type -> idl.CreateBackupDirectoryRequest
-	23, // 15: idl.Agent.CheckDiskSpace:input_type -> idl.CheckSegmentDiskSpaceRequest
-	4,  // 16: idl.Agent.UpgradePrimaries:input_type -> idl.UpgradePrimariesRequest
-	19, // 17: idl.Agent.RenameDirectories:input_type -> idl.RenameDirectoriesRequest
-	21, // 18: idl.Agent.StopAgent:input_type -> idl.StopAgentRequest
-	8,  // 19: idl.Agent.DeleteDataDirectories:input_type -> idl.DeleteDataDirectoriesRequest
-	12, // 20: idl.Agent.DeleteBackupDirectory:input_type -> idl.DeleteBackupDirectoryRequest
-	10, // 21: idl.Agent.DeleteStateDirectory:input_type -> idl.DeleteStateDirectoryRequest
-	14, // 22: idl.Agent.DeleteTablespaceDirectories:input_type -> idl.DeleteTablespaceRequest
-	16, // 23: idl.Agent.ArchiveLogDirectory:input_type -> idl.ArchiveLogDirectoryRequest
-	25, // 24: idl.Agent.RsyncDataDirectories:input_type -> idl.RsyncRequest
-	25, // 25: idl.Agent.RsyncTablespaceDirectories:input_type -> idl.RsyncRequest
-	27, // 26: idl.Agent.RestorePrimariesPgControl:input_type -> idl.RestorePgControlRequest
-	30, // 27: idl.Agent.UpdateConfiguration:input_type -> idl.UpdateConfigurationRequest
-	32, // 28: idl.Agent.RenameTablespaces:input_type -> idl.RenameTablespacesRequest
-	34, // 29: idl.Agent.CreateRecoveryConf:input_type -> idl.CreateRecoveryConfRequest
-	36, // 30: idl.Agent.AddReplicationEntries:input_type -> idl.AddReplicationEntriesRequest
-	7,  // 31: idl.Agent.CreateBackupDirectory:output_type -> idl.CreateBackupDirectoryReply
-	24, // 32: idl.Agent.CheckDiskSpace:output_type -> idl.CheckDiskSpaceReply
-	5,  // 33: idl.Agent.UpgradePrimaries:output_type -> idl.UpgradePrimariesReply
-	20, // 34: idl.Agent.RenameDirectories:output_type -> idl.RenameDirectoriesReply
-	22, // 35: idl.Agent.StopAgent:output_type -> idl.StopAgentReply
-	9,  // 36: idl.Agent.DeleteDataDirectories:output_type -> idl.DeleteDataDirectoriesReply
-	13, // 37: idl.Agent.DeleteBackupDirectory:output_type -> idl.DeleteBackupDirectoryReply
-	11, // 38: idl.Agent.DeleteStateDirectory:output_type -> idl.DeleteStateDirectoryReply
-	15, // 39: idl.Agent.DeleteTablespaceDirectories:output_type -> idl.DeleteTablespaceReply
-	17, // 40: idl.Agent.ArchiveLogDirectory:output_type -> idl.ArchiveLogDirectoryReply
-	26, // 41: idl.Agent.RsyncDataDirectories:output_type -> idl.RsyncReply
-	26, // 42: idl.Agent.RsyncTablespaceDirectories:output_type -> idl.RsyncReply
-	28, // 43: idl.Agent.RestorePrimariesPgControl:output_type -> idl.RestorePgControlReply
-	31, // 44: idl.Agent.UpdateConfiguration:output_type -> idl.UpdateConfigurationReply
-	33, // 45: idl.Agent.RenameTablespaces:output_type -> idl.RenameTablespacesReply
-	35, // 46: idl.Agent.CreateRecoveryConf:output_type -> idl.CreateRecoveryConfReply
-	37, // 47: idl.Agent.AddReplicationEntries:output_type -> idl.AddReplicationEntriesReply
-	31, // [31:48] is the sub-list for method output_type
-	14, // [14:31] is the sub-list for method input_type
-	14, // [14:14] is the sub-list for extension type_name
-	14, // [14:14] is the sub-list for extension extendee
-	0,  // [0:14] is the sub-list for field type_name
+	3,  // 5: idl.UpgradePrimariesRequest.opts:type_name -> idl.PgOptions
+	19, // 6: idl.RenameDirectoriesRequest.Dirs:type_name -> idl.RenameDirectories
+	61, // 7: idl.CheckDiskSpaceReply.usages:type_name -> idl.CheckDiskSpaceReply.DiskUsage
+	62, // 8: idl.RsyncRequest.options:type_name -> idl.RsyncRequest.RsyncOptions
+	2,  // 9: idl.UpdateFileConfOptions.duplicateMatches:type_name -> idl.UpdateFileConfOptions.DuplicateMatches
+	30, // 10: idl.UpdateConfigurationRequest.options:type_name -> idl.UpdateFileConfOptions
+	65, // 11: idl.UpdateConfigurationReply.values:type_name -> idl.ReadConfigurationReply.Value
+	32, // 12: idl.UpdateConfigurationReply.diffs:type_name -> idl.ConfFileDiff
+	35, // 13: idl.UpdateConfigurationReply.changedLines:type_name -> idl.ChangedLine
+	34, // 14: idl.UpdateConfigurationReply.checksums:type_name -> idl.ConfFileChecksum
+	63, // 15: idl.UpdateConfigurationReply.resolvedPaths:type_name -> idl.UpdateConfigurationReply.ResolvedPathsEntry
+	64, // 16: idl.ReadConfigurationRequest.files:type_name -> idl.ReadConfigurationRequest.File
+	65, // 17: idl.ReadConfigurationReply.values:type_name -> idl.ReadConfigurationReply.Value
+	66, // 18: idl.ReadConfigurationReply.matches:type_name -> idl.ReadConfigurationReply.Match
+	67, // 19: idl.RenameTablespacesRequest.renamePairs:type_name -> idl.RenameTablespacesRequest.RenamePair
+	68, // 20: idl.CreateRecoveryConfRequest.connections:type_name -> idl.CreateRecoveryConfRequest.Connection
+	69, // 21: idl.AddReplicationEntriesRequest.entries:type_name -> idl.AddReplicationEntriesRequest.Entry
+	70, // 22: idl.InventorySegmentsReply.dataDirectories:type_name -> idl.InventorySegmentsReply.DataDirectory
+	71, // 23: idl.ListConfBackupsReply.backups:type_name -> idl.ListConfBackupsReply.Backup
+	72, // 24: idl.SnapshotConfFilesReply.files:type_name -> idl.SnapshotConfFilesReply.File
+	4,  // 25: idl.PgOptions.TablespacesEntry.value:type_name -> idl.TablespaceInfo
+	7,  // 26: idl.Agent.CreateBackupDirectory:input_type -> idl.CreateBackupDirectoryRequest
+	24, // 27: idl.Agent.CheckDiskSpace:input_type -> idl.CheckSegmentDiskSpaceRequest
+	5,  // 28: idl.Agent.UpgradePrimaries:input_type -> idl.UpgradePrimariesRequest
+	20, // 29: idl.Agent.RenameDirectories:input_type -> idl.RenameDirectoriesRequest
+	22, // 30: idl.Agent.StopAgent:input_type -> idl.StopAgentRequest
+	9,  // 31: idl.Agent.DeleteDataDirectories:input_type -> idl.DeleteDataDirectoriesRequest
+	13, // 32: idl.Agent.DeleteBackupDirectory:input_type -> idl.DeleteBackupDirectoryRequest
+	11, // 33: idl.Agent.DeleteStateDirectory:input_type -> idl.DeleteStateDirectoryRequest
+	15, // 34: idl.Agent.DeleteTablespaceDirectories:input_type -> idl.DeleteTablespaceRequest
+	17, // 35: idl.Agent.ArchiveLogDirectory:input_type -> idl.ArchiveLogDirectoryRequest
+	26, // 36: idl.Agent.RsyncDataDirectories:input_type -> idl.RsyncRequest
+	26, // 37: idl.Agent.RsyncTablespaceDirectories:input_type -> idl.RsyncRequest
+	28, // 38: idl.Agent.RestorePrimariesPgControl:input_type -> idl.RestorePgControlRequest
+	31, // 39: idl.Agent.UpdateConfiguration:input_type -> idl.UpdateConfigurationRequest
+	36, // 40: idl.Agent.ReadConfiguration:input_type -> idl.ReadConfigurationRequest
+	38, // 41: idl.Agent.RenameTablespaces:input_type -> idl.RenameTablespacesRequest
+	40, // 42: idl.Agent.CreateRecoveryConf:input_type -> idl.CreateRecoveryConfRequest
+	42, // 43: idl.Agent.AddReplicationEntries:input_type -> idl.AddReplicationEntriesRequest
+	44, // 44: idl.Agent.InventorySegments:input_type -> idl.InventorySegmentsRequest
+	46, // 45: idl.Agent.ListConfBackups:input_type -> idl.ListConfBackupsRequest
+	48, // 46: idl.Agent.GetCapabilities:input_type -> idl.GetCapabilitiesRequest
+	50, // 47: idl.Agent.SnapshotConfFiles:input_type -> idl.SnapshotConfFilesRequest
+	52, // 48: idl.Agent.ConfUpdateHeartbeat:input_type -> idl.ConfUpdateHeartbeatRequest
+	54, // 49: idl.Agent.RestoreConfBackups:input_type -> idl.RestoreConfBackupsRequest
+	56, // 50: idl.Agent.DeleteConfBackups:input_type -> idl.DeleteConfBackupsRequest
+	58, // 51: idl.Agent.ReadConfJournal:input_type -> idl.ReadConfJournalRequest
+	8,  // 52: idl.Agent.CreateBackupDirectory:output_type -> idl.CreateBackupDirectoryReply
+	25, // 53: idl.Agent.CheckDiskSpace:output_type -> idl.CheckDiskSpaceReply
+	6,  // 54: idl.Agent.UpgradePrimaries:output_type -> idl.UpgradePrimariesReply
+	21, // 55: idl.Agent.RenameDirectories:output_type -> idl.RenameDirectoriesReply
+	23, // 56: idl.Agent.StopAgent:output_type -> idl.StopAgentReply
+	10, // 57: idl.Agent.DeleteDataDirectories:output_type -> idl.DeleteDataDirectoriesReply
+	14, // 58: idl.Agent.DeleteBackupDirectory:output_type -> idl.DeleteBackupDirectoryReply
+	12, // 59: idl.Agent.DeleteStateDirectory:output_type -> idl.DeleteStateDirectoryReply
+	16, // 60: idl.Agent.DeleteTablespaceDirectories:output_type -> idl.DeleteTablespaceReply
+	18, // 61: idl.Agent.ArchiveLogDirectory:output_type -> idl.ArchiveLogDirectoryReply
+	27, // 62: idl.Agent.RsyncDataDirectories:output_type -> idl.RsyncReply
+	27, // 63: idl.Agent.RsyncTablespaceDirectories:output_type -> idl.RsyncReply
+	29, // 64: idl.Agent.RestorePrimariesPgControl:output_type -> idl.RestorePgControlReply
+	33, // 65: idl.Agent.UpdateConfiguration:output_type -> idl.UpdateConfigurationReply
+	37, // 66: idl.Agent.ReadConfiguration:output_type -> idl.ReadConfigurationReply
+	39, // 67: idl.Agent.RenameTablespaces:output_type -> idl.RenameTablespacesReply
+	41, // 68: idl.Agent.CreateRecoveryConf:output_type -> idl.CreateRecoveryConfReply
+	43, // 69: idl.Agent.AddReplicationEntries:output_type -> idl.AddReplicationEntriesReply
+	45, // 70: idl.Agent.InventorySegments:output_type -> idl.InventorySegmentsReply
+	47, // 71: idl.Agent.ListConfBackups:output_type -> idl.ListConfBackupsReply
+	49, // 72: idl.Agent.GetCapabilities:output_type -> idl.GetCapabilitiesReply
+	51, // 73: idl.Agent.SnapshotConfFiles:output_type -> idl.SnapshotConfFilesReply
+	53, // 74: idl.Agent.ConfUpdateHeartbeat:output_type -> idl.ConfUpdateHeartbeatReply
+	55, // 75: idl.Agent.RestoreConfBackups:output_type -> idl.RestoreConfBackupsReply
+	57, // 76: idl.Agent.DeleteConfBackups:output_type -> idl.DeleteConfBackupsReply
+	59, // 77: idl.Agent.ReadConfJournal:output_type -> idl.ReadConfJournalReply
+	52, // [52:78] is the sub-list for method output_type
+	26, // [26:52] is the sub-list for method input_type
+	26, // [26:26] is the sub-list for extension type_name
+	26, // [26:26] is the sub-list for extension extendee
+	0,  // [0:26] is the sub-list for field type_name
 }
 
 func init() { file_hub_to_agent_proto_init() }
@@ -2660,8 +4781,176 @@ func file_hub_to_agent_proto_init() {
 				return nil
 			}
 		}
-		file_hub_to_agent_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*TablespaceInfo); i {
+		file_hub_to_agent_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TablespaceInfo); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_hub_to_agent_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpgradePrimariesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_hub_to_agent_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpgradePrimariesReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_hub_to_agent_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreateBackupDirectoryRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_hub_to_agent_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreateBackupDirectoryReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_hub_to_agent_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeleteDataDirectoriesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_hub_to_agent_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeleteDataDirectoriesReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_hub_to_agent_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeleteStateDirectoryRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_hub_to_agent_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeleteStateDirectoryReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_hub_to_agent_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeleteBackupDirectoryRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_hub_to_agent_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeleteBackupDirectoryReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_hub_to_agent_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeleteTablespaceRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_hub_to_agent_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeleteTablespaceReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_hub_to_agent_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ArchiveLogDirectoryRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_hub_to_agent_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ArchiveLogDirectoryReply); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2672,8 +4961,8 @@ func file_hub_to_agent_proto_init() {
 				return nil
 			}
 		}
-		file_hub_to_agent_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UpgradePrimariesRequest); i {
+		file_hub_to_agent_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RenameDirectories); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2684,8 +4973,8 @@ func file_hub_to_agent_proto_init() {
 				return nil
 			}
 		}
-		file_hub_to_agent_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UpgradePrimariesReply); i {
+		file_hub_to_agent_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RenameDirectoriesRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2696,8 +4985,8 @@ func file_hub_to_agent_proto_init() {
 				return nil
 			}
 		}
-		file_hub_to_agent_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CreateBackupDirectoryRequest); i {
+		file_hub_to_agent_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RenameDirectoriesReply); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2708,8 +4997,8 @@ func file_hub_to_agent_proto_init() {
 				return nil
 			}
 		}
-		file_hub_to_agent_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CreateBackupDirectoryReply); i {
+		file_hub_to_agent_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StopAgentRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2720,8 +5009,8 @@ func file_hub_to_agent_proto_init() {
 				return nil
 			}
 		}
-		file_hub_to_agent_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DeleteDataDirectoriesRequest); i {
+		file_hub_to_agent_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StopAgentReply); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2732,8 +5021,8 @@ func file_hub_to_agent_proto_init() {
 				return nil
 			}
 		}
-		file_hub_to_agent_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DeleteDataDirectoriesReply); i {
+		file_hub_to_agent_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CheckSegmentDiskSpaceRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2744,8 +5033,8 @@ func file_hub_to_agent_proto_init() {
 				return nil
 			}
 		}
-		file_hub_to_agent_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DeleteStateDirectoryRequest); i {
+		file_hub_to_agent_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CheckDiskSpaceReply); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2756,8 +5045,8 @@ func file_hub_to_agent_proto_init() {
 				return nil
 			}
 		}
-		file_hub_to_agent_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DeleteStateDirectoryReply); i {
+		file_hub_to_agent_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RsyncRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2768,8 +5057,8 @@ func file_hub_to_agent_proto_init() {
 				return nil
 			}
 		}
-		file_hub_to_agent_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DeleteBackupDirectoryRequest); i {
+		file_hub_to_agent_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RsyncReply); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2780,8 +5069,8 @@ func file_hub_to_agent_proto_init() {
 				return nil
 			}
 		}
-		file_hub_to_agent_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DeleteBackupDirectoryReply); i {
+		file_hub_to_agent_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RestorePgControlRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2792,8 +5081,8 @@ func file_hub_to_agent_proto_init() {
 				return nil
 			}
 		}
-		file_hub_to_agent_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DeleteTablespaceRequest); i {
+		file_hub_to_agent_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RestorePgControlReply); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2804,8 +5093,8 @@ func file_hub_to_agent_proto_init() {
 				return nil
 			}
 		}
-		file_hub_to_agent_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DeleteTablespaceReply); i {
+		file_hub_to_agent_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdateFileConfOptions); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2816,8 +5105,8 @@ func file_hub_to_agent_proto_init() {
 				return nil
 			}
 		}
-		file_hub_to_agent_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ArchiveLogDirectoryRequest); i {
+		file_hub_to_agent_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdateConfigurationRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2828,8 +5117,8 @@ func file_hub_to_agent_proto_init() {
 				return nil
 			}
 		}
-		file_hub_to_agent_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ArchiveLogDirectoryReply); i {
+		file_hub_to_agent_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ConfFileDiff); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2840,8 +5129,8 @@ func file_hub_to_agent_proto_init() {
 				return nil
 			}
 		}
-		file_hub_to_agent_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RenameDirectories); i {
+		file_hub_to_agent_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdateConfigurationReply); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2852,8 +5141,8 @@ func file_hub_to_agent_proto_init() {
 				return nil
 			}
 		}
-		file_hub_to_agent_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RenameDirectoriesRequest); i {
+		file_hub_to_agent_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ConfFileChecksum); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2864,8 +5153,8 @@ func file_hub_to_agent_proto_init() {
 				return nil
 			}
 		}
-		file_hub_to_agent_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RenameDirectoriesReply); i {
+		file_hub_to_agent_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ChangedLine); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2876,8 +5165,8 @@ func file_hub_to_agent_proto_init() {
 				return nil
 			}
 		}
-		file_hub_to_agent_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*StopAgentRequest); i {
+		file_hub_to_agent_proto_msgTypes[33].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReadConfigurationRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2888,8 +5177,8 @@ func file_hub_to_agent_proto_init() {
 				return nil
 			}
 		}
-		file_hub_to_agent_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*StopAgentReply); i {
+		file_hub_to_agent_proto_msgTypes[34].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReadConfigurationReply); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2900,8 +5189,8 @@ func file_hub_to_agent_proto_init() {
 				return nil
 			}
 		}
-		file_hub_to_agent_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CheckSegmentDiskSpaceRequest); i {
+		file_hub_to_agent_proto_msgTypes[35].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RenameTablespacesRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2912,8 +5201,8 @@ func file_hub_to_agent_proto_init() {
 				return nil
 			}
 		}
-		file_hub_to_agent_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CheckDiskSpaceReply); i {
+		file_hub_to_agent_proto_msgTypes[36].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RenameTablespacesReply); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2924,8 +5213,8 @@ func file_hub_to_agent_proto_init() {
 				return nil
 			}
 		}
-		file_hub_to_agent_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RsyncRequest); i {
+		file_hub_to_agent_proto_msgTypes[37].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreateRecoveryConfRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2936,8 +5225,8 @@ func file_hub_to_agent_proto_init() {
 				return nil
 			}
 		}
-		file_hub_to_agent_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RsyncReply); i {
+		file_hub_to_agent_proto_msgTypes[38].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreateRecoveryConfReply); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2948,8 +5237,8 @@ func file_hub_to_agent_proto_init() {
 				return nil
 			}
 		}
-		file_hub_to_agent_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RestorePgControlRequest); i {
+		file_hub_to_agent_proto_msgTypes[39].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AddReplicationEntriesRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2960,8 +5249,8 @@ func file_hub_to_agent_proto_init() {
 				return nil
 			}
 		}
-		file_hub_to_agent_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RestorePgControlReply); i {
+		file_hub_to_agent_proto_msgTypes[40].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AddReplicationEntriesReply); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2972,8 +5261,8 @@ func file_hub_to_agent_proto_init() {
 				return nil
 			}
 		}
-		file_hub_to_agent_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UpdateFileConfOptions); i {
+		file_hub_to_agent_proto_msgTypes[41].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*InventorySegmentsRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2984,8 +5273,8 @@ func file_hub_to_agent_proto_init() {
 				return nil
 			}
 		}
-		file_hub_to_agent_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UpdateConfigurationRequest); i {
+		file_hub_to_agent_proto_msgTypes[42].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*InventorySegmentsReply); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2996,8 +5285,8 @@ func file_hub_to_agent_proto_init() {
 				return nil
 			}
 		}
-		file_hub_to_agent_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UpdateConfigurationReply); i {
+		file_hub_to_agent_proto_msgTypes[43].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListConfBackupsRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3008,8 +5297,8 @@ func file_hub_to_agent_proto_init() {
 				return nil
 			}
 		}
-		file_hub_to_agent_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RenameTablespacesRequest); i {
+		file_hub_to_agent_proto_msgTypes[44].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListConfBackupsReply); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3020,8 +5309,8 @@ func file_hub_to_agent_proto_init() {
 				return nil
 			}
 		}
-		file_hub_to_agent_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RenameTablespacesReply); i {
+		file_hub_to_agent_proto_msgTypes[45].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetCapabilitiesRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3032,8 +5321,8 @@ func file_hub_to_agent_proto_init() {
 				return nil
 			}
 		}
-		file_hub_to_agent_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CreateRecoveryConfRequest); i {
+		file_hub_to_agent_proto_msgTypes[46].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetCapabilitiesReply); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3044,8 +5333,8 @@ func file_hub_to_agent_proto_init() {
 				return nil
 			}
 		}
-		file_hub_to_agent_proto_msgTypes[33].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CreateRecoveryConfReply); i {
+		file_hub_to_agent_proto_msgTypes[47].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SnapshotConfFilesRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3056,8 +5345,8 @@ func file_hub_to_agent_proto_init() {
 				return nil
 			}
 		}
-		file_hub_to_agent_proto_msgTypes[34].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AddReplicationEntriesRequest); i {
+		file_hub_to_agent_proto_msgTypes[48].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SnapshotConfFilesReply); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3068,8 +5357,8 @@ func file_hub_to_agent_proto_init() {
 				return nil
 			}
 		}
-		file_hub_to_agent_proto_msgTypes[35].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AddReplicationEntriesReply); i {
+		file_hub_to_agent_proto_msgTypes[49].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ConfUpdateHeartbeatRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3080,7 +5369,91 @@ func file_hub_to_agent_proto_init() {
 				return nil
 			}
 		}
-		file_hub_to_agent_proto_msgTypes[37].Exporter = func(v interface{}, i int) interface{} {
+		file_hub_to_agent_proto_msgTypes[50].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ConfUpdateHeartbeatReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_hub_to_agent_proto_msgTypes[51].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RestoreConfBackupsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_hub_to_agent_proto_msgTypes[52].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RestoreConfBackupsReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_hub_to_agent_proto_msgTypes[53].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeleteConfBackupsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_hub_to_agent_proto_msgTypes[54].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeleteConfBackupsReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_hub_to_agent_proto_msgTypes[55].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReadConfJournalRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_hub_to_agent_proto_msgTypes[56].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReadConfJournalReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_hub_to_agent_proto_msgTypes[58].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*CheckDiskSpaceReply_DiskUsage); i {
 			case 0:
 				return &v.state
@@ -3092,7 +5465,7 @@ func file_hub_to_agent_proto_init() {
 				return nil
 			}
 		}
-		file_hub_to_agent_proto_msgTypes[38].Exporter = func(v interface{}, i int) interface{} {
+		file_hub_to_agent_proto_msgTypes[59].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*RsyncRequest_RsyncOptions); i {
 			case 0:
 				return &v.state
@@ -3104,7 +5477,43 @@ func file_hub_to_agent_proto_init() {
 				return nil
 			}
 		}
-		file_hub_to_agent_proto_msgTypes[39].Exporter = func(v interface{}, i int) interface{} {
+		file_hub_to_agent_proto_msgTypes[61].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReadConfigurationRequest_File); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_hub_to_agent_proto_msgTypes[62].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReadConfigurationReply_Value); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_hub_to_agent_proto_msgTypes[63].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReadConfigurationReply_Match); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_hub_to_agent_proto_msgTypes[64].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*RenameTablespacesRequest_RenamePair); i {
 			case 0:
 				return &v.state
@@ -3116,7 +5525,7 @@ func file_hub_to_agent_proto_init() {
 				return nil
 			}
 		}
-		file_hub_to_agent_proto_msgTypes[40].Exporter = func(v interface{}, i int) interface{} {
+		file_hub_to_agent_proto_msgTypes[65].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*CreateRecoveryConfRequest_Connection); i {
 			case 0:
 				return &v.state
@@ -3128,7 +5537,7 @@ func file_hub_to_agent_proto_init() {
 				return nil
 			}
 		}
-		file_hub_to_agent_proto_msgTypes[41].Exporter = func(v interface{}, i int) interface{} {
+		file_hub_to_agent_proto_msgTypes[66].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*AddReplicationEntriesRequest_Entry); i {
 			case 0:
 				return &v.state
@@ -3140,14 +5549,50 @@ func file_hub_to_agent_proto_init() {
 				return nil
 			}
 		}
+		file_hub_to_agent_proto_msgTypes[67].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*InventorySegmentsReply_DataDirectory); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_hub_to_agent_proto_msgTypes[68].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListConfBackupsReply_Backup); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_hub_to_agent_proto_msgTypes[69].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SnapshotConfFilesReply_File); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_hub_to_agent_proto_rawDesc,
-			NumEnums:      2,
-			NumMessages:   42,
+			NumEnums:      3,
+			NumMessages:   70,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -36,9 +36,18 @@ const (
 	Agent_RsyncTablespaceDirectories_FullMethodName  = "/idl.Agent/RsyncTablespaceDirectories"
 	Agent_RestorePrimariesPgControl_FullMethodName   = "/idl.Agent/RestorePrimariesPgControl"
 	Agent_UpdateConfiguration_FullMethodName         = "/idl.Agent/UpdateConfiguration"
+	Agent_ReadConfiguration_FullMethodName           = "/idl.Agent/ReadConfiguration"
 	Agent_RenameTablespaces_FullMethodName           = "/idl.Agent/RenameTablespaces"
 	Agent_CreateRecoveryConf_FullMethodName          = "/idl.Agent/CreateRecoveryConf"
 	Agent_AddReplicationEntries_FullMethodName       = "/idl.Agent/AddReplicationEntries"
+	Agent_InventorySegments_FullMethodName           = "/idl.Agent/InventorySegments"
+	Agent_ListConfBackups_FullMethodName             = "/idl.Agent/ListConfBackups"
+	Agent_GetCapabilities_FullMethodName             = "/idl.Agent/GetCapabilities"
+	Agent_SnapshotConfFiles_FullMethodName           = "/idl.Agent/SnapshotConfFiles"
+	Agent_ConfUpdateHeartbeat_FullMethodName         = "/idl.Agent/ConfUpdateHeartbeat"
+	Agent_RestoreConfBackups_FullMethodName          = "/idl.Agent/RestoreConfBackups"
+	Agent_DeleteConfBackups_FullMethodName           = "/idl.Agent/DeleteConfBackups"
+	Agent_ReadConfJournal_FullMethodName             = "/idl.Agent/ReadConfJournal"
 )
 
 // AgentClient is the client API for Agent service.
@@ -59,9 +68,18 @@ type AgentClient interface {
 	RsyncTablespaceDirectories(ctx context.Context, in *RsyncRequest, opts ...grpc.CallOption) (*RsyncReply, error)
 	RestorePrimariesPgControl(ctx context.Context, in *RestorePgControlRequest, opts ...grpc.CallOption) (*RestorePgControlReply, error)
 	UpdateConfiguration(ctx context.Context, in *UpdateConfigurationRequest, opts ...grpc.CallOption) (*UpdateConfigurationReply, error)
+	ReadConfiguration(ctx context.Context, in *ReadConfigurationRequest, opts ...grpc.CallOption) (*ReadConfigurationReply, error)
 	RenameTablespaces(ctx context.Context, in *RenameTablespacesRequest, opts ...grpc.CallOption) (*RenameTablespacesReply, error)
 	CreateRecoveryConf(ctx context.Context, in *CreateRecoveryConfRequest, opts ...grpc.CallOption) (*CreateRecoveryConfReply, error)
 	AddReplicationEntries(ctx context.Context, in *AddReplicationEntriesRequest, opts ...grpc.CallOption) (*AddReplicationEntriesReply, error)
+	InventorySegments(ctx context.Context, in *InventorySegmentsRequest, opts ...grpc.CallOption) (*InventorySegmentsReply, error)
+	ListConfBackups(ctx context.Context, in *ListConfBackupsRequest, opts ...grpc.CallOption) (*ListConfBackupsReply, error)
+	GetCapabilities(ctx context.Context, in *GetCapabilitiesRequest, opts ...grpc.CallOption) (*GetCapabilitiesReply, error)
+	SnapshotConfFiles(ctx context.Context, in *SnapshotConfFilesRequest, opts ...grpc.CallOption) (*SnapshotConfFilesReply, error)
+	ConfUpdateHeartbeat(ctx context.Context, in *ConfUpdateHeartbeatRequest, opts ...grpc.CallOption) (*ConfUpdateHeartbeatReply, error)
+	RestoreConfBackups(ctx context.Context, in *RestoreConfBackupsRequest, opts ...grpc.CallOption) (*RestoreConfBackupsReply, error)
+	DeleteConfBackups(ctx context.Context, in *DeleteConfBackupsRequest, opts ...grpc.CallOption) (*DeleteConfBackupsReply, error)
+	ReadConfJournal(ctx context.Context, in *ReadConfJournalRequest, opts ...grpc.CallOption) (*ReadConfJournalReply, error)
 }
 
 type agentClient struct {
@@ -198,6 +216,15 @@ func (c *agentClient) UpdateConfiguration(ctx context.Context, in *UpdateConfigu
 	return out, nil
 }
 
+func (c *agentClient) ReadConfiguration(ctx context.Context, in *ReadConfigurationRequest, opts ...grpc.CallOption) (*ReadConfigurationReply, error) {
+	out := new(ReadConfigurationReply)
+	err := c.cc.Invoke(ctx, Agent_ReadConfiguration_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *agentClient) RenameTablespaces(ctx context.Context, in *RenameTablespacesRequest, opts ...grpc.CallOption) (*RenameTablespacesReply, error) {
 	out := new(RenameTablespacesReply)
 	err := c.cc.Invoke(ctx, Agent_RenameTablespaces_FullMethodName, in, out, opts...)
@@ -225,6 +252,78 @@ func (c *agentClient) AddReplicationEntries(ctx context.Context, in *AddReplicat
 	return out, nil
 }
 
+func (c *agentClient) InventorySegments(ctx context.Context, in *InventorySegmentsRequest, opts ...grpc.CallOption) (*InventorySegmentsReply, error) {
+	out := new(InventorySegmentsReply)
+	err := c.cc.Invoke(ctx, Agent_InventorySegments_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentClient) ListConfBackups(ctx context.Context, in *ListConfBackupsRequest, opts ...grpc.CallOption) (*ListConfBackupsReply, error) {
+	out := new(ListConfBackupsReply)
+	err := c.cc.Invoke(ctx, Agent_ListConfBackups_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentClient) GetCapabilities(ctx context.Context, in *GetCapabilitiesRequest, opts ...grpc.CallOption) (*GetCapabilitiesReply, error) {
+	out := new(GetCapabilitiesReply)
+	err := c.cc.Invoke(ctx, Agent_GetCapabilities_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentClient) SnapshotConfFiles(ctx context.Context, in *SnapshotConfFilesRequest, opts ...grpc.CallOption) (*SnapshotConfFilesReply, error) {
+	out := new(SnapshotConfFilesReply)
+	err := c.cc.Invoke(ctx, Agent_SnapshotConfFiles_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentClient) ConfUpdateHeartbeat(ctx context.Context, in *ConfUpdateHeartbeatRequest, opts ...grpc.CallOption) (*ConfUpdateHeartbeatReply, error) {
+	out := new(ConfUpdateHeartbeatReply)
+	err := c.cc.Invoke(ctx, Agent_ConfUpdateHeartbeat_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentClient) RestoreConfBackups(ctx context.Context, in *RestoreConfBackupsRequest, opts ...grpc.CallOption) (*RestoreConfBackupsReply, error) {
+	out := new(RestoreConfBackupsReply)
+	err := c.cc.Invoke(ctx, Agent_RestoreConfBackups_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentClient) DeleteConfBackups(ctx context.Context, in *DeleteConfBackupsRequest, opts ...grpc.CallOption) (*DeleteConfBackupsReply, error) {
+	out := new(DeleteConfBackupsReply)
+	err := c.cc.Invoke(ctx, Agent_DeleteConfBackups_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentClient) ReadConfJournal(ctx context.Context, in *ReadConfJournalRequest, opts ...grpc.CallOption) (*ReadConfJournalReply, error) {
+	out := new(ReadConfJournalReply)
+	err := c.cc.Invoke(ctx, Agent_ReadConfJournal_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // AgentServer is the server API for Agent service.
 // All implementations should embed UnimplementedAgentServer
 // for forward compatibility
@@ -243,9 +342,18 @@ type AgentServer interface {
 	RsyncTablespaceDirectories(context.Context, *RsyncRequest) (*RsyncReply, error)
 	RestorePrimariesPgControl(context.Context, *RestorePgControlRequest) (*RestorePgControlReply, error)
 	UpdateConfiguration(context.Context, *UpdateConfigurationRequest) (*UpdateConfigurationReply, error)
+	ReadConfiguration(context.Context, *ReadConfigurationRequest) (*ReadConfigurationReply, error)
 	RenameTablespaces(context.Context, *RenameTablespacesRequest) (*RenameTablespacesReply, error)
 	CreateRecoveryConf(context.Context, *CreateRecoveryConfRequest) (*CreateRecoveryConfReply, error)
 	AddReplicationEntries(context.Context, *AddReplicationEntriesRequest) (*AddReplicationEntriesReply, error)
+	InventorySegments(context.Context, *InventorySegmentsRequest) (*InventorySegmentsReply, error)
+	ListConfBackups(context.Context, *ListConfBackupsRequest) (*ListConfBackupsReply, error)
+	GetCapabilities(context.Context, *GetCapabilitiesRequest) (*GetCapabilitiesReply, error)
+	SnapshotConfFiles(context.Context, *SnapshotConfFilesRequest) (*SnapshotConfFilesReply, error)
+	ConfUpdateHeartbeat(context.Context, *ConfUpdateHeartbeatRequest) (*ConfUpdateHeartbeatReply, error)
+	RestoreConfBackups(context.Context, *RestoreConfBackupsRequest) (*RestoreConfBackupsReply, error)
+	DeleteConfBackups(context.Context, *DeleteConfBackupsRequest) (*DeleteConfBackupsReply, error)
+	ReadConfJournal(context.Context, *ReadConfJournalRequest) (*ReadConfJournalReply, error)
 }
 
 // UnimplementedAgentServer should be embedded to have forward compatible implementations.
@@ -294,6 +402,9 @@ func (UnimplementedAgentServer) RestorePrimariesPgControl(context.Context, *Rest
 func (UnimplementedAgentServer) UpdateConfiguration(context.Context, *UpdateConfigurationRequest) (*UpdateConfigurationReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpdateConfiguration not implemented")
 }
+func (UnimplementedAgentServer) ReadConfiguration(context.Context, *ReadConfigurationRequest) (*ReadConfigurationReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReadConfiguration not implemented")
+}
 func (UnimplementedAgentServer) RenameTablespaces(context.Context, *RenameTablespacesRequest) (*RenameTablespacesReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RenameTablespaces not implemented")
 }
@@ -303,6 +414,30 @@ func (UnimplementedAgentServer) CreateRecoveryConf(context.Context, *CreateRecov
 func (UnimplementedAgentServer) AddReplicationEntries(context.Context, *AddReplicationEntriesRequest) (*AddReplicationEntriesReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method AddReplicationEntries not implemented")
 }
+func (UnimplementedAgentServer) InventorySegments(context.Context, *InventorySegmentsRequest) (*InventorySegmentsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method InventorySegments not implemented")
+}
+func (UnimplementedAgentServer) ListConfBackups(context.Context, *ListConfBackupsRequest) (*ListConfBackupsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListConfBackups not implemented")
+}
+func (UnimplementedAgentServer) GetCapabilities(context.Context, *GetCapabilitiesRequest) (*GetCapabilitiesReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCapabilities not implemented")
+}
+func (UnimplementedAgentServer) SnapshotConfFiles(context.Context, *SnapshotConfFilesRequest) (*SnapshotConfFilesReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SnapshotConfFiles not implemented")
+}
+func (UnimplementedAgentServer) ConfUpdateHeartbeat(context.Context, *ConfUpdateHeartbeatRequest) (*ConfUpdateHeartbeatReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ConfUpdateHeartbeat not implemented")
+}
+func (UnimplementedAgentServer) RestoreConfBackups(context.Context, *RestoreConfBackupsRequest) (*RestoreConfBackupsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreConfBackups not implemented")
+}
+func (UnimplementedAgentServer) DeleteConfBackups(context.Context, *DeleteConfBackupsRequest) (*DeleteConfBackupsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteConfBackups not implemented")
+}
+func (UnimplementedAgentServer) ReadConfJournal(context.Context, *ReadConfJournalRequest) (*ReadConfJournalReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReadConfJournal not implemented")
+}
 
 // UnsafeAgentServer may be embedded to opt out of forward compatibility for this service.
 // Use of this interface is not recommended, as added methods to AgentServer will
@@ -567,6 +702,24 @@ func _Agent_UpdateConfiguration_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Agent_ReadConfiguration_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReadConfigurationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).ReadConfiguration(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Agent_ReadConfiguration_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).ReadConfiguration(ctx, req.(*ReadConfigurationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Agent_RenameTablespaces_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(RenameTablespacesRequest)
 	if err := dec(in); err != nil {
@@ -621,6 +774,150 @@ func _Agent_AddReplicationEntries_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Agent_InventorySegments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InventorySegmentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).InventorySegments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Agent_InventorySegments_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).InventorySegments(ctx, req.(*InventorySegmentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Agent_ListConfBackups_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListConfBackupsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).ListConfBackups(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Agent_ListConfBackups_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).ListConfBackups(ctx, req.(*ListConfBackupsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Agent_GetCapabilities_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCapabilitiesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).GetCapabilities(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Agent_GetCapabilities_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).GetCapabilities(ctx, req.(*GetCapabilitiesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Agent_SnapshotConfFiles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SnapshotConfFilesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).SnapshotConfFiles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Agent_SnapshotConfFiles_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).SnapshotConfFiles(ctx, req.(*SnapshotConfFilesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Agent_ConfUpdateHeartbeat_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConfUpdateHeartbeatRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).ConfUpdateHeartbeat(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Agent_ConfUpdateHeartbeat_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).ConfUpdateHeartbeat(ctx, req.(*ConfUpdateHeartbeatRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Agent_RestoreConfBackups_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreConfBackupsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).RestoreConfBackups(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Agent_RestoreConfBackups_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).RestoreConfBackups(ctx, req.(*RestoreConfBackupsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Agent_DeleteConfBackups_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteConfBackupsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).DeleteConfBackups(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Agent_DeleteConfBackups_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).DeleteConfBackups(ctx, req.(*DeleteConfBackupsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Agent_ReadConfJournal_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReadConfJournalRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).ReadConfJournal(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Agent_ReadConfJournal_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).ReadConfJournal(ctx, req.(*ReadConfJournalRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Agent_ServiceDesc is the grpc.ServiceDesc for Agent service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -684,6 +981,10 @@ var Agent_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "UpdateConfiguration",
 			Handler:    _Agent_UpdateConfiguration_Handler,
 		},
+		{
+			MethodName: "ReadConfiguration",
+			Handler:    _Agent_ReadConfiguration_Handler,
+		},
 		{
 			MethodName: "RenameTablespaces",
 			Handler:    _Agent_RenameTablespaces_Handler,
@@ -696,6 +997,38 @@ var Agent_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "AddReplicationEntries",
 			Handler:    _Agent_AddReplicationEntries_Handler,
 		},
+		{
+			MethodName: "InventorySegments",
+			Handler:    _Agent_InventorySegments_Handler,
+		},
+		{
+			MethodName: "ListConfBackups",
+			Handler:    _Agent_ListConfBackups_Handler,
+		},
+		{
+			MethodName: "GetCapabilities",
+			Handler:    _Agent_GetCapabilities_Handler,
+		},
+		{
+			MethodName: "SnapshotConfFiles",
+			Handler:    _Agent_SnapshotConfFiles_Handler,
+		},
+		{
+			MethodName: "ConfUpdateHeartbeat",
+			Handler:    _Agent_ConfUpdateHeartbeat_Handler,
+		},
+		{
+			MethodName: "RestoreConfBackups",
+			Handler:    _Agent_RestoreConfBackups_Handler,
+		},
+		{
+			MethodName: "DeleteConfBackups",
+			Handler:    _Agent_DeleteConfBackups_Handler,
+		},
+		{
+			MethodName: "ReadConfJournal",
+			Handler:    _Agent_ReadConfJournal_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "hub_to_agent.proto",
@@ -37,6 +37,26 @@ func (m *MockCliToHubClient) EXPECT() *MockCliToHubClientMockRecorder {
 	return m.recorder
 }
 
+// CheckAgentCapabilities mocks base method.
+func (m *MockCliToHubClient) CheckAgentCapabilities(ctx context.Context, in *idl.CheckAgentCapabilitiesRequest, opts ...grpc.CallOption) (*idl.CheckAgentCapabilitiesReply, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CheckAgentCapabilities", varargs...)
+	ret0, _ := ret[0].(*idl.CheckAgentCapabilitiesReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckAgentCapabilities indicates an expected call of CheckAgentCapabilities.
+func (mr *MockCliToHubClientMockRecorder) CheckAgentCapabilities(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckAgentCapabilities", reflect.TypeOf((*MockCliToHubClient)(nil).CheckAgentCapabilities), varargs...)
+}
+
 // Execute mocks base method.
 func (m *MockCliToHubClient) Execute(ctx context.Context, in *idl.ExecuteRequest, opts ...grpc.CallOption) (idl.CliToHub_ExecuteClient, error) {
 	m.ctrl.T.Helper()
@@ -835,6 +855,21 @@ func (m *MockCliToHubServer) EXPECT() *MockCliToHubServerMockRecorder {
 	return m.recorder
 }
 
+// CheckAgentCapabilities mocks base method.
+func (m *MockCliToHubServer) CheckAgentCapabilities(arg0 context.Context, arg1 *idl.CheckAgentCapabilitiesRequest) (*idl.CheckAgentCapabilitiesReply, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckAgentCapabilities", arg0, arg1)
+	ret0, _ := ret[0].(*idl.CheckAgentCapabilitiesReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckAgentCapabilities indicates an expected call of CheckAgentCapabilities.
+func (mr *MockCliToHubServerMockRecorder) CheckAgentCapabilities(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckAgentCapabilities", reflect.TypeOf((*MockCliToHubServer)(nil).CheckAgentCapabilities), arg0, arg1)
+}
+
 // Execute mocks base method.
 func (m *MockCliToHubServer) Execute(arg0 *idl.ExecuteRequest, arg1 idl.CliToHub_ExecuteServer) error {
 	m.ctrl.T.Helper()
@@ -96,6 +96,26 @@ func (mr *MockAgentClientMockRecorder) CheckDiskSpace(ctx, in interface{}, opts
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckDiskSpace", reflect.TypeOf((*MockAgentClient)(nil).CheckDiskSpace), varargs...)
 }
 
+// ConfUpdateHeartbeat mocks base method.
+func (m *MockAgentClient) ConfUpdateHeartbeat(ctx context.Context, in *idl.ConfUpdateHeartbeatRequest, opts ...grpc.CallOption) (*idl.ConfUpdateHeartbeatReply, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ConfUpdateHeartbeat", varargs...)
+	ret0, _ := ret[0].(*idl.ConfUpdateHeartbeatReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConfUpdateHeartbeat indicates an expected call of ConfUpdateHeartbeat.
+func (mr *MockAgentClientMockRecorder) ConfUpdateHeartbeat(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfUpdateHeartbeat", reflect.TypeOf((*MockAgentClient)(nil).ConfUpdateHeartbeat), varargs...)
+}
+
 // CreateBackupDirectory mocks base method.
 func (m *MockAgentClient) CreateBackupDirectory(ctx context.Context, in *idl.CreateBackupDirectoryRequest, opts ...grpc.CallOption) (*idl.CreateBackupDirectoryReply, error) {
 	m.ctrl.T.Helper()
@@ -156,6 +176,26 @@ func (mr *MockAgentClientMockRecorder) DeleteBackupDirectory(ctx, in interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBackupDirectory", reflect.TypeOf((*MockAgentClient)(nil).DeleteBackupDirectory), varargs...)
 }
 
+// DeleteConfBackups mocks base method.
+func (m *MockAgentClient) DeleteConfBackups(ctx context.Context, in *idl.DeleteConfBackupsRequest, opts ...grpc.CallOption) (*idl.DeleteConfBackupsReply, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DeleteConfBackups", varargs...)
+	ret0, _ := ret[0].(*idl.DeleteConfBackupsReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteConfBackups indicates an expected call of DeleteConfBackups.
+func (mr *MockAgentClientMockRecorder) DeleteConfBackups(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteConfBackups", reflect.TypeOf((*MockAgentClient)(nil).DeleteConfBackups), varargs...)
+}
+
 // DeleteDataDirectories mocks base method.
 func (m *MockAgentClient) DeleteDataDirectories(ctx context.Context, in *idl.DeleteDataDirectoriesRequest, opts ...grpc.CallOption) (*idl.DeleteDataDirectoriesReply, error) {
 	m.ctrl.T.Helper()
@@ -216,6 +256,106 @@ func (mr *MockAgentClientMockRecorder) DeleteTablespaceDirectories(ctx, in inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTablespaceDirectories", reflect.TypeOf((*MockAgentClient)(nil).DeleteTablespaceDirectories), varargs...)
 }
 
+// GetCapabilities mocks base method.
+func (m *MockAgentClient) GetCapabilities(ctx context.Context, in *idl.GetCapabilitiesRequest, opts ...grpc.CallOption) (*idl.GetCapabilitiesReply, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetCapabilities", varargs...)
+	ret0, _ := ret[0].(*idl.GetCapabilitiesReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCapabilities indicates an expected call of GetCapabilities.
+func (mr *MockAgentClientMockRecorder) GetCapabilities(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCapabilities", reflect.TypeOf((*MockAgentClient)(nil).GetCapabilities), varargs...)
+}
+
+// InventorySegments mocks base method.
+func (m *MockAgentClient) InventorySegments(ctx context.Context, in *idl.InventorySegmentsRequest, opts ...grpc.CallOption) (*idl.InventorySegmentsReply, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "InventorySegments", varargs...)
+	ret0, _ := ret[0].(*idl.InventorySegmentsReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InventorySegments indicates an expected call of InventorySegments.
+func (mr *MockAgentClientMockRecorder) InventorySegments(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InventorySegments", reflect.TypeOf((*MockAgentClient)(nil).InventorySegments), varargs...)
+}
+
+// ListConfBackups mocks base method.
+func (m *MockAgentClient) ListConfBackups(ctx context.Context, in *idl.ListConfBackupsRequest, opts ...grpc.CallOption) (*idl.ListConfBackupsReply, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListConfBackups", varargs...)
+	ret0, _ := ret[0].(*idl.ListConfBackupsReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListConfBackups indicates an expected call of ListConfBackups.
+func (mr *MockAgentClientMockRecorder) ListConfBackups(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListConfBackups", reflect.TypeOf((*MockAgentClient)(nil).ListConfBackups), varargs...)
+}
+
+// ReadConfJournal mocks base method.
+func (m *MockAgentClient) ReadConfJournal(ctx context.Context, in *idl.ReadConfJournalRequest, opts ...grpc.CallOption) (*idl.ReadConfJournalReply, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ReadConfJournal", varargs...)
+	ret0, _ := ret[0].(*idl.ReadConfJournalReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadConfJournal indicates an expected call of ReadConfJournal.
+func (mr *MockAgentClientMockRecorder) ReadConfJournal(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadConfJournal", reflect.TypeOf((*MockAgentClient)(nil).ReadConfJournal), varargs...)
+}
+
+// ReadConfiguration mocks base method.
+func (m *MockAgentClient) ReadConfiguration(ctx context.Context, in *idl.ReadConfigurationRequest, opts ...grpc.CallOption) (*idl.ReadConfigurationReply, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ReadConfiguration", varargs...)
+	ret0, _ := ret[0].(*idl.ReadConfigurationReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadConfiguration indicates an expected call of ReadConfiguration.
+func (mr *MockAgentClientMockRecorder) ReadConfiguration(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadConfiguration", reflect.TypeOf((*MockAgentClient)(nil).ReadConfiguration), varargs...)
+}
+
 // RenameDirectories mocks base method.
 func (m *MockAgentClient) RenameDirectories(ctx context.Context, in *idl.RenameDirectoriesRequest, opts ...grpc.CallOption) (*idl.RenameDirectoriesReply, error) {
 	m.ctrl.T.Helper()
@@ -256,6 +396,26 @@ func (mr *MockAgentClientMockRecorder) RenameTablespaces(ctx, in interface{}, op
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameTablespaces", reflect.TypeOf((*MockAgentClient)(nil).RenameTablespaces), varargs...)
 }
 
+// RestoreConfBackups mocks base method.
+func (m *MockAgentClient) RestoreConfBackups(ctx context.Context, in *idl.RestoreConfBackupsRequest, opts ...grpc.CallOption) (*idl.RestoreConfBackupsReply, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RestoreConfBackups", varargs...)
+	ret0, _ := ret[0].(*idl.RestoreConfBackupsReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreConfBackups indicates an expected call of RestoreConfBackups.
+func (mr *MockAgentClientMockRecorder) RestoreConfBackups(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreConfBackups", reflect.TypeOf((*MockAgentClient)(nil).RestoreConfBackups), varargs...)
+}
+
 // RestorePrimariesPgControl mocks base method.
 func (m *MockAgentClient) RestorePrimariesPgControl(ctx context.Context, in *idl.RestorePgControlRequest, opts ...grpc.CallOption) (*idl.RestorePgControlReply, error) {
 	m.ctrl.T.Helper()
@@ -316,6 +476,26 @@ func (mr *MockAgentClientMockRecorder) RsyncTablespaceDirectories(ctx, in interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RsyncTablespaceDirectories", reflect.TypeOf((*MockAgentClient)(nil).RsyncTablespaceDirectories), varargs...)
 }
 
+// SnapshotConfFiles mocks base method.
+func (m *MockAgentClient) SnapshotConfFiles(ctx context.Context, in *idl.SnapshotConfFilesRequest, opts ...grpc.CallOption) (*idl.SnapshotConfFilesReply, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "SnapshotConfFiles", varargs...)
+	ret0, _ := ret[0].(*idl.SnapshotConfFilesReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SnapshotConfFiles indicates an expected call of SnapshotConfFiles.
+func (mr *MockAgentClientMockRecorder) SnapshotConfFiles(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SnapshotConfFiles", reflect.TypeOf((*MockAgentClient)(nil).SnapshotConfFiles), varargs...)
+}
+
 // StopAgent mocks base method.
 func (m *MockAgentClient) StopAgent(ctx context.Context, in *idl.StopAgentRequest, opts ...grpc.CallOption) (*idl.StopAgentReply, error) {
 	m.ctrl.T.Helper()
@@ -444,6 +624,21 @@ func (mr *MockAgentServerMockRecorder) CheckDiskSpace(arg0, arg1 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckDiskSpace", reflect.TypeOf((*MockAgentServer)(nil).CheckDiskSpace), arg0, arg1)
 }
 
+// ConfUpdateHeartbeat mocks base method.
+func (m *MockAgentServer) ConfUpdateHeartbeat(arg0 context.Context, arg1 *idl.ConfUpdateHeartbeatRequest) (*idl.ConfUpdateHeartbeatReply, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfUpdateHeartbeat", arg0, arg1)
+	ret0, _ := ret[0].(*idl.ConfUpdateHeartbeatReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConfUpdateHeartbeat indicates an expected call of ConfUpdateHeartbeat.
+func (mr *MockAgentServerMockRecorder) ConfUpdateHeartbeat(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfUpdateHeartbeat", reflect.TypeOf((*MockAgentServer)(nil).ConfUpdateHeartbeat), arg0, arg1)
+}
+
 // CreateBackupDirectory mocks base method.
 func (m *MockAgentServer) CreateBackupDirectory(arg0 context.Context, arg1 *idl.CreateBackupDirectoryRequest) (*idl.CreateBackupDirectoryReply, error) {
 	m.ctrl.T.Helper()
@@ -489,6 +684,21 @@ func (mr *MockAgentServerMockRecorder) DeleteBackupDirectory(arg0, arg1 interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBackupDirectory", reflect.TypeOf((*MockAgentServer)(nil).DeleteBackupDirectory), arg0, arg1)
 }
 
+// DeleteConfBackups mocks base method.
+func (m *MockAgentServer) DeleteConfBackups(arg0 context.Context, arg1 *idl.DeleteConfBackupsRequest) (*idl.DeleteConfBackupsReply, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteConfBackups", arg0, arg1)
+	ret0, _ := ret[0].(*idl.DeleteConfBackupsReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteConfBackups indicates an expected call of DeleteConfBackups.
+func (mr *MockAgentServerMockRecorder) DeleteConfBackups(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteConfBackups", reflect.TypeOf((*MockAgentServer)(nil).DeleteConfBackups), arg0, arg1)
+}
+
 // DeleteDataDirectories mocks base method.
 func (m *MockAgentServer) DeleteDataDirectories(arg0 context.Context, arg1 *idl.DeleteDataDirectoriesRequest) (*idl.DeleteDataDirectoriesReply, error) {
 	m.ctrl.T.Helper()
@@ -534,6 +744,81 @@ func (mr *MockAgentServerMockRecorder) DeleteTablespaceDirectories(arg0, arg1 in
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTablespaceDirectories", reflect.TypeOf((*MockAgentServer)(nil).DeleteTablespaceDirectories), arg0, arg1)
 }
 
+// GetCapabilities mocks base method.
+func (m *MockAgentServer) GetCapabilities(arg0 context.Context, arg1 *idl.GetCapabilitiesRequest) (*idl.GetCapabilitiesReply, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCapabilities", arg0, arg1)
+	ret0, _ := ret[0].(*idl.GetCapabilitiesReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCapabilities indicates an expected call of GetCapabilities.
+func (mr *MockAgentServerMockRecorder) GetCapabilities(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCapabilities", reflect.TypeOf((*MockAgentServer)(nil).GetCapabilities), arg0, arg1)
+}
+
+// InventorySegments mocks base method.
+func (m *MockAgentServer) InventorySegments(arg0 context.Context, arg1 *idl.InventorySegmentsRequest) (*idl.InventorySegmentsReply, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InventorySegments", arg0, arg1)
+	ret0, _ := ret[0].(*idl.InventorySegmentsReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InventorySegments indicates an expected call of InventorySegments.
+func (mr *MockAgentServerMockRecorder) InventorySegments(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InventorySegments", reflect.TypeOf((*MockAgentServer)(nil).InventorySegments), arg0, arg1)
+}
+
+// ListConfBackups mocks base method.
+func (m *MockAgentServer) ListConfBackups(arg0 context.Context, arg1 *idl.ListConfBackupsRequest) (*idl.ListConfBackupsReply, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListConfBackups", arg0, arg1)
+	ret0, _ := ret[0].(*idl.ListConfBackupsReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListConfBackups indicates an expected call of ListConfBackups.
+func (mr *MockAgentServerMockRecorder) ListConfBackups(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListConfBackups", reflect.TypeOf((*MockAgentServer)(nil).ListConfBackups), arg0, arg1)
+}
+
+// ReadConfJournal mocks base method.
+func (m *MockAgentServer) ReadConfJournal(arg0 context.Context, arg1 *idl.ReadConfJournalRequest) (*idl.ReadConfJournalReply, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadConfJournal", arg0, arg1)
+	ret0, _ := ret[0].(*idl.ReadConfJournalReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadConfJournal indicates an expected call of ReadConfJournal.
+func (mr *MockAgentServerMockRecorder) ReadConfJournal(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadConfJournal", reflect.TypeOf((*MockAgentServer)(nil).ReadConfJournal), arg0, arg1)
+}
+
+// ReadConfiguration mocks base method.
+func (m *MockAgentServer) ReadConfiguration(arg0 context.Context, arg1 *idl.ReadConfigurationRequest) (*idl.ReadConfigurationReply, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadConfiguration", arg0, arg1)
+	ret0, _ := ret[0].(*idl.ReadConfigurationReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadConfiguration indicates an expected call of ReadConfiguration.
+func (mr *MockAgentServerMockRecorder) ReadConfiguration(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadConfiguration", reflect.TypeOf((*MockAgentServer)(nil).ReadConfiguration), arg0, arg1)
+}
+
 // RenameDirectories mocks base method.
 func (m *MockAgentServer) RenameDirectories(arg0 context.Context, arg1 *idl.RenameDirectoriesRequest) (*idl.RenameDirectoriesReply, error) {
 	m.ctrl.T.Helper()
@@ -564,6 +849,21 @@ func (mr *MockAgentServerMockRecorder) RenameTablespaces(arg0, arg1 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameTablespaces", reflect.TypeOf((*MockAgentServer)(nil).RenameTablespaces), arg0, arg1)
 }
 
+// RestoreConfBackups mocks base method.
+func (m *MockAgentServer) RestoreConfBackups(arg0 context.Context, arg1 *idl.RestoreConfBackupsRequest) (*idl.RestoreConfBackupsReply, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreConfBackups", arg0, arg1)
+	ret0, _ := ret[0].(*idl.RestoreConfBackupsReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreConfBackups indicates an expected call of RestoreConfBackups.
+func (mr *MockAgentServerMockRecorder) RestoreConfBackups(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreConfBackups", reflect.TypeOf((*MockAgentServer)(nil).RestoreConfBackups), arg0, arg1)
+}
+
 // RestorePrimariesPgControl mocks base method.
 func (m *MockAgentServer) RestorePrimariesPgControl(arg0 context.Context, arg1 *idl.RestorePgControlRequest) (*idl.RestorePgControlReply, error) {
 	m.ctrl.T.Helper()
@@ -609,6 +909,21 @@ func (mr *MockAgentServerMockRecorder) RsyncTablespaceDirectories(arg0, arg1 int
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RsyncTablespaceDirectories", reflect.TypeOf((*MockAgentServer)(nil).RsyncTablespaceDirectories), arg0, arg1)
 }
 
+// SnapshotConfFiles mocks base method.
+func (m *MockAgentServer) SnapshotConfFiles(arg0 context.Context, arg1 *idl.SnapshotConfFilesRequest) (*idl.SnapshotConfFilesReply, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SnapshotConfFiles", arg0, arg1)
+	ret0, _ := ret[0].(*idl.SnapshotConfFilesReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SnapshotConfFiles indicates an expected call of SnapshotConfFiles.
+func (mr *MockAgentServerMockRecorder) SnapshotConfFiles(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SnapshotConfFiles", reflect.TypeOf((*MockAgentServer)(nil).SnapshotConfFiles), arg0, arg1)
+}
+
 // StopAgent mocks base method.
 func (m *MockAgentServer) StopAgent(arg0 context.Context, arg1 *idl.StopAgentRequest) (*idl.StopAgentReply, error) {
 	m.ctrl.T.Helper()
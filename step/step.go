@@ -55,7 +55,16 @@ func Begin(step idl.Step, sender idl.MessageSender) (*Step, error) {
 		return nil, err
 	}
 
-	return New(step, sender, substepStore, streams), nil
+	// the substep statuses are sent through the streams so that they are
+	// serialized with the output sent to the same stream
+	return New(step, streams, substepStore, streams), nil
+}
+
+// Sender returns the MessageSender of the step, which substeps use to send
+// their own messages such as progress events. Messages are sent serialized
+// with the substep statuses and output of a step begun with Begin.
+func (s *Step) Sender() idl.MessageSender {
+	return s.sender
 }
 
 func HasStarted(step idl.Step) (bool, error) {
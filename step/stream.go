@@ -144,6 +144,20 @@ func newLogMessageSender(stream idl.MessageSender) *logMessageSender {
 	return lms
 }
 
+// Send sends msg to the MessageSender under the lock of stdout and stderr,
+// since a gRPC stream does not support concurrent sends. Once writing to
+// stdout or stderr has halted the sender, messages are dropped.
+func (l *logMessageSender) Send(msg *idl.Message) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.sender == nil {
+		return nil
+	}
+
+	return l.sender.Send(msg)
+}
+
 func (l *logMessageSender) Stdout() io.Writer {
 	return l.stdout
 }
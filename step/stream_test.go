@@ -7,7 +7,10 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 
@@ -153,4 +156,49 @@ func TestMultiplexedStream(t *testing.T) {
 			t.Errorf("log %q does not contain %q", logContents, expected)
 		}
 	})
+	t.Run("serializes messages sent with the output", func(t *testing.T) {
+		sender := &overlapSender{}
+		stream := newLogMessageSender(sender)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				_ = stream.Send(&idl.Message{})
+			}()
+			go func() {
+				defer wg.Done()
+				fmt.Fprint(stream.Stdout(), "output")
+			}()
+		}
+		wg.Wait()
+
+		if sender.overlapped {
+			t.Error("got concurrent sends want them serialized")
+		}
+
+		if sender.sent != 20 {
+			t.Errorf("got %d messages sent want 20", sender.sent)
+		}
+	})
+}
+
+// overlapSender records whether two sends overlapped.
+type overlapSender struct {
+	inSend     int32
+	overlapped bool
+	sent       int
+}
+
+func (s *overlapSender) Send(*idl.Message) error {
+	if !atomic.CompareAndSwapInt32(&s.inSend, 0, 1) {
+		s.overlapped = true
+		return nil
+	}
+	defer atomic.StoreInt32(&s.inSend, 0)
+
+	time.Sleep(time.Millisecond)
+	s.sent++
+	return nil
 }
@@ -54,6 +54,8 @@ var SubstepDescriptions = map[idl.Substep]substepText{
 	idl.Substep_delete_target_cluster_datadirs:                                substepText{"Deleting target cluster data directories...", "Delete target cluster data directories"},
 	idl.Substep_delete_segment_statedirs:                                      substepText{"Deleting state directories on the segments...", "Delete state directories on the segments"},
 	idl.Substep_delete_backupdir:                                              substepText{"Deleting internal backup directories on the segments...", "Delete internal backup directories on the segments..."},
+	idl.Substep_delete_conf_backups:                                           substepText{"Deleting configuration file backups...", "Delete configuration file backups"},
+	idl.Substep_restore_conf_files:                                            substepText{"Restoring target configuration files...", "Restore target configuration files"},
 	idl.Substep_stop_hub_and_agents:                                           substepText{"Stopping hub and agents...", "Stop hub and agents"},
 	idl.Substep_delete_master_statedir:                                        substepText{"Deleting master state directory...", "Delete master state directory"},
 	idl.Substep_archive_log_directories:                                       substepText{"Archiving log directories...", "Archive log directories"},
@@ -215,6 +215,12 @@ func testRevertAfterExecute(t *testing.T, mode idl.Mode, upgradeFailure UpgradeF
 	logArchiveDir := acceptance.MustGetLogArchiveDir(t, conf.UpgradeID)
 	verifyRevert(t, source, conf.Intermediate, revertOutput, logArchiveDir)
 
+	// verify the conf files edited during the upgrade were restored
+	restoredText := commanders.Format(substeps.SubstepDescriptions[idl.Substep_restore_conf_files].OutputText, idl.Status_complete)
+	if !strings.Contains(revertOutput, restoredText) {
+		t.Errorf("expected revert output %q to contain %q", revertOutput, restoredText)
+	}
+
 	// verify that the mirror marker files were restored to the primaries after reverting
 	acceptance.VerifyMarkerFilesOnPrimaries(t, source.Primaries, mode)
 
@@ -8,6 +8,7 @@
 package exectest
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
@@ -24,6 +25,10 @@ type Main func()
 // is created with a call to NewCommand().
 type Command func(string, ...string) *exec.Cmd
 
+// CommandContext is a function that has an identical signature to
+// exec.CommandContext(). It is created with a call to NewCommandContext().
+type CommandContext func(context.Context, string, ...string) *exec.Cmd
+
 // mains is populated by RegisterMains and used as a lookup table by Run and
 // NewCommand.
 var mains []Main
@@ -122,6 +127,32 @@ func NewCommandWithVerifier(m Main, verifier func(string, ...string)) Command {
 	}
 }
 
+// NewCommandContext works like NewCommand for exec.CommandContext(). The test
+// subprocess is killed when the context is done, as with exec.CommandContext().
+func NewCommandContext(m Main) CommandContext {
+	cmdf := NewCommand(m)
+
+	return func(ctx context.Context, executable string, args ...string) *exec.Cmd {
+		cmd := cmdf(executable, args...)
+
+		// Rebuild the command with the context, keeping the hijacked argv.
+		ctxCmd := exec.CommandContext(ctx, cmd.Path, cmd.Args[1:]...)
+		ctxCmd.Args = cmd.Args
+		return ctxCmd
+	}
+}
+
+// NewCommandContextWithVerifier works like NewCommandContext, with a verifier
+// callback as for NewCommandWithVerifier.
+func NewCommandContextWithVerifier(m Main, verifier func(string, ...string)) CommandContext {
+	cmdf := NewCommandContext(m)
+
+	return func(ctx context.Context, executable string, args ...string) *exec.Cmd {
+		verifier(executable, args...)
+		return cmdf(ctx, executable, args...)
+	}
+}
+
 // RegisterMains makes multiple Main functions available to Run in the test
 // subprocess. Call it only from your test package's init functions. Any Main
 // functions passed to NewCommand must be registered using this function.
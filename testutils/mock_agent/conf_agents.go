@@ -0,0 +1,147 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package mock_agent
+
+import (
+	"context"
+	"net"
+	"sort"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/greenplum-db/gpupgrade/idl"
+)
+
+// ConfAgents is a fake agent on each of a set of hosts that records the
+// UpdateConfiguration requests it receives and returns the reply or error set
+// for its host. The agents are served over in-process gRPC so that tests
+// dispatch to them through the same connections as real agents. Requests
+// other than UpdateConfiguration are unimplemented.
+type ConfAgents struct {
+	mu       sync.Mutex
+	servers  []*grpc.Server
+	conns    []*idl.Connection
+	requests map[string][]*idl.UpdateConfigurationRequest
+	replies  map[string]*idl.UpdateConfigurationReply
+	errs     map[string]error
+}
+
+// NewConfAgents starts a fake agent for each host. Call Stop once done.
+func NewConfAgents(hostnames ...string) *ConfAgents {
+	agents := &ConfAgents{
+		requests: make(map[string][]*idl.UpdateConfigurationRequest),
+		replies:  make(map[string]*idl.UpdateConfigurationReply),
+		errs:     make(map[string]error),
+	}
+
+	for _, hostname := range hostnames {
+		listener := bufconn.Listen(1024 * 1024)
+		server := grpc.NewServer()
+		idl.RegisterAgentServer(server, &confAgentServer{hostname: hostname, agents: agents})
+
+		go func() {
+			_ = server.Serve(listener)
+		}()
+
+		dialer := func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		conn, err := grpc.DialContext(ctx, hostname, grpc.WithContextDialer(dialer), grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			panic(err)
+		}
+
+		agents.servers = append(agents.servers, server)
+		agents.conns = append(agents.conns, &idl.Connection{
+			Conn:          conn,
+			AgentClient:   idl.NewAgentClient(conn),
+			Hostname:      hostname,
+			CancelContext: cancel,
+		})
+	}
+
+	return agents
+}
+
+// Conns returns the connections to the agents in the order of their hosts.
+func (a *ConfAgents) Conns() []*idl.Connection {
+	return a.conns
+}
+
+// SetReply has the agent on the host return reply to each request.
+func (a *ConfAgents) SetReply(hostname string, reply *idl.UpdateConfigurationReply) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.replies[hostname] = reply
+}
+
+// SetError has the agent on the host fail each request with err, which is
+// received by the hub with its gRPC status or as codes.Unknown.
+func (a *ConfAgents) SetError(hostname string, err error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.errs[hostname] = err
+}
+
+// Requests returns the requests received by the agent on the host in the
+// order they were received.
+func (a *ConfAgents) Requests(hostname string) []*idl.UpdateConfigurationRequest {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]*idl.UpdateConfigurationRequest(nil), a.requests[hostname]...)
+}
+
+// Hosts returns the sorted hosts whose agent received a request.
+func (a *ConfAgents) Hosts() []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var hosts []string
+	for host := range a.requests {
+		hosts = append(hosts, host)
+	}
+
+	sort.Strings(hosts)
+	return hosts
+}
+
+// Stop closes the connections and stops the agents.
+func (a *ConfAgents) Stop() {
+	for _, conn := range a.conns {
+		_ = conn.Conn.Close()
+		conn.CancelContext()
+	}
+
+	for _, server := range a.servers {
+		server.Stop()
+	}
+}
+
+type confAgentServer struct {
+	idl.UnimplementedAgentServer
+
+	hostname string
+	agents   *ConfAgents
+}
+
+func (s *confAgentServer) UpdateConfiguration(_ context.Context, req *idl.UpdateConfigurationRequest) (*idl.UpdateConfigurationReply, error) {
+	s.agents.mu.Lock()
+	defer s.agents.mu.Unlock()
+
+	s.agents.requests[s.hostname] = append(s.agents.requests[s.hostname], req)
+	if err := s.agents.errs[s.hostname]; err != nil {
+		return nil, err
+	}
+
+	if reply := s.agents.replies[s.hostname]; reply != nil {
+		return reply, nil
+	}
+
+	return &idl.UpdateConfigurationReply{}, nil
+}
@@ -158,6 +158,11 @@ func (m *MockAgentServer) UpdateConfiguration(context.Context, *idl.UpdateConfig
 	return &idl.UpdateConfigurationReply{}, nil
 }
 
+func (m *MockAgentServer) ReadConfiguration(context.Context, *idl.ReadConfigurationRequest) (*idl.ReadConfigurationReply, error) {
+	m.increaseCalls()
+	return &idl.ReadConfigurationReply{}, nil
+}
+
 func (m *MockAgentServer) RenameTablespaces(context context.Context, in *idl.RenameTablespacesRequest) (*idl.RenameTablespacesReply, error) {
 	return &idl.RenameTablespacesReply{}, nil
 }
@@ -169,3 +174,35 @@ func (m *MockAgentServer) CreateRecoveryConf(context context.Context, in *idl.Cr
 func (m *MockAgentServer) AddReplicationEntries(context context.Context, in *idl.AddReplicationEntriesRequest) (*idl.AddReplicationEntriesReply, error) {
 	return &idl.AddReplicationEntriesReply{}, nil
 }
+
+func (m *MockAgentServer) InventorySegments(context context.Context, in *idl.InventorySegmentsRequest) (*idl.InventorySegmentsReply, error) {
+	return &idl.InventorySegmentsReply{}, nil
+}
+
+func (m *MockAgentServer) ListConfBackups(context context.Context, in *idl.ListConfBackupsRequest) (*idl.ListConfBackupsReply, error) {
+	return &idl.ListConfBackupsReply{}, nil
+}
+
+func (m *MockAgentServer) GetCapabilities(context context.Context, in *idl.GetCapabilitiesRequest) (*idl.GetCapabilitiesReply, error) {
+	return &idl.GetCapabilitiesReply{}, nil
+}
+
+func (m *MockAgentServer) SnapshotConfFiles(context context.Context, in *idl.SnapshotConfFilesRequest) (*idl.SnapshotConfFilesReply, error) {
+	return &idl.SnapshotConfFilesReply{}, nil
+}
+
+func (m *MockAgentServer) ConfUpdateHeartbeat(context context.Context, in *idl.ConfUpdateHeartbeatRequest) (*idl.ConfUpdateHeartbeatReply, error) {
+	return &idl.ConfUpdateHeartbeatReply{}, nil
+}
+
+func (m *MockAgentServer) RestoreConfBackups(context context.Context, in *idl.RestoreConfBackupsRequest) (*idl.RestoreConfBackupsReply, error) {
+	return &idl.RestoreConfBackupsReply{}, nil
+}
+
+func (m *MockAgentServer) DeleteConfBackups(context context.Context, in *idl.DeleteConfBackupsRequest) (*idl.DeleteConfBackupsReply, error) {
+	return &idl.DeleteConfBackupsReply{}, nil
+}
+
+func (m *MockAgentServer) ReadConfJournal(context context.Context, in *idl.ReadConfJournalRequest) (*idl.ReadConfJournalReply, error) {
+	return &idl.ReadConfJournalReply{}, nil
+}